@@ -0,0 +1,124 @@
+// Package audit collects what a mutating API call touched while its handler
+// and services run, for the audit middleware to write when the call ends.
+package audit
+
+import (
+	"context"
+	"sync"
+
+	"github.com/appy29/banking-ledger-service/models"
+)
+
+// Target kinds recorded in models.AuditRecord.Targets
+const (
+	TargetTransaction     = "transaction"
+	TargetTransfer        = "transfer"
+	TargetReversal        = "reversal"
+	TargetHold            = "hold"
+	TargetSchedule        = "schedule"
+	TargetDeadLetter      = "dead_letter"
+	TargetWebhook         = "webhook"
+	TargetWebhookDelivery = "webhook_delivery"
+)
+
+// Entry is the part of an audit record filled in by handlers and services.
+// Every method is safe on a nil Entry, so code that also runs outside an
+// audited request (the queue worker, the scheduler) can call them without
+// checking.
+type Entry struct {
+	mu         sync.Mutex
+	accountIDs []string
+	targets    map[string]string
+	balances   []models.BalanceChange
+}
+
+// NewEntry returns an empty entry
+func NewEntry() *Entry {
+	return &Entry{targets: map[string]string{}}
+}
+
+// Account records customer accounts the call touched
+func (e *Entry) Account(accountIDs ...string) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, accountID := range accountIDs {
+		e.addAccount(accountID)
+	}
+}
+
+// Target records another record the call touched or created
+func (e *Entry) Target(kind, id string) {
+	if e == nil || id == "" {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.targets[kind] = id
+}
+
+// Balances records how the call moved customer balances. System ledger
+// accounts are left out.
+func (e *Entry) Balances(changes []models.BalanceChange) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, change := range changes {
+		if models.IsSystemAccount(change.AccountID) {
+			continue
+		}
+		e.addAccount(change.AccountID)
+		e.balances = append(e.balances, change)
+	}
+}
+
+// Fill copies the entry into record
+func (e *Entry) Fill(record *models.AuditRecord) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	record.AccountIDs = append([]string(nil), e.accountIDs...)
+	record.Balances = append([]models.BalanceChange(nil), e.balances...)
+	if len(e.targets) > 0 {
+		record.Targets = make(map[string]string, len(e.targets))
+		for kind, id := range e.targets {
+			record.Targets[kind] = id
+		}
+	}
+}
+
+func (e *Entry) addAccount(accountID string) {
+	if accountID == "" || models.IsSystemAccount(accountID) {
+		return
+	}
+	for _, existing := range e.accountIDs {
+		if existing == accountID {
+			return
+		}
+	}
+	e.accountIDs = append(e.accountIDs, accountID)
+}
+
+type contextKey string
+
+const entryKey contextKey = "audit_entry"
+
+// WithEntry adds an audit entry to context
+func WithEntry(ctx context.Context, entry *Entry) context.Context {
+	return context.WithValue(ctx, entryKey, entry)
+}
+
+// FromContext returns the request's audit entry, or nil if the request is
+// not audited
+func FromContext(ctx context.Context) *Entry {
+	if entry, ok := ctx.Value(entryKey).(*Entry); ok {
+		return entry
+	}
+	return nil
+}
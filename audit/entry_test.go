@@ -0,0 +1,47 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntry_Fill(t *testing.T) {
+	entry := NewEntry()
+	entry.Account("acc_from", "", "acc_to")
+	entry.Target(TargetTransfer, "trf_12345")
+	entry.Balances([]models.BalanceChange{
+		{AccountID: "acc_from", PreviousBalance: models.MustParseMoney("100.00"), NewBalance: models.MustParseMoney("75.00")},
+		{AccountID: "acc_to", PreviousBalance: models.MustParseMoney("10.00"), NewBalance: models.MustParseMoney("35.00")},
+		{AccountID: "sys_cash_usd", PreviousBalance: 0, NewBalance: models.MustParseMoney("25.00")},
+	})
+	entry.Account("acc_from")
+
+	var record models.AuditRecord
+	entry.Fill(&record)
+
+	assert.Equal(t, []string{"acc_from", "acc_to"}, record.AccountIDs)
+	assert.Equal(t, map[string]string{TargetTransfer: "trf_12345"}, record.Targets)
+	assert.Len(t, record.Balances, 2, "system accounts are left out")
+}
+
+func TestEntry_NilIsSafe(t *testing.T) {
+	entry := FromContext(context.Background())
+	assert.Nil(t, entry)
+
+	// Code outside an audited request calls these unconditionally
+	entry.Account("acc_12345")
+	entry.Target(TargetHold, "hold_12345")
+	entry.Balances([]models.BalanceChange{{AccountID: "acc_12345"}})
+
+	var record models.AuditRecord
+	entry.Fill(&record)
+	assert.Empty(t, record.AccountIDs)
+}
+
+func TestFromContext(t *testing.T) {
+	entry := NewEntry()
+	assert.Same(t, entry, FromContext(WithEntry(context.Background(), entry)))
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// APIKey is one entry of the API keys file. Only the SHA-256 of the key is
+// kept, so the file does not give the keys away.
+type APIKey struct {
+	Name      string   `json:"name"`       // Identifies the key in logs
+	KeySHA256 string   `json:"key_sha256"` // Hex SHA-256 of the key
+	Subject   string   `json:"subject"`    // Owner ID the key acts as
+	Roles     []string `json:"roles,omitempty"`
+}
+
+// APIKeys authenticates callers by the key in the X-API-Key header
+type APIKeys struct {
+	keys []APIKey
+}
+
+// LoadAPIKeys reads an API keys file: {"keys": [APIKey, ...]}
+func LoadAPIKeys(path string) (*APIKeys, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API keys file: %w", err)
+	}
+
+	var document struct {
+		Keys []APIKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid API keys file: %w", err)
+	}
+
+	return NewAPIKeys(document.Keys)
+}
+
+func NewAPIKeys(keys []APIKey) (*APIKeys, error) {
+	for i, key := range keys {
+		key.KeySHA256 = strings.ToLower(key.KeySHA256)
+		keys[i].KeySHA256 = key.KeySHA256
+		if key.Name == "" || key.Subject == "" {
+			return nil, fmt.Errorf("API key %d needs a name and a subject", i)
+		}
+		if digest, err := hex.DecodeString(key.KeySHA256); err != nil || len(digest) != sha256.Size {
+			return nil, fmt.Errorf("API key %q: key_sha256 must be a hex SHA-256", key.Name)
+		}
+	}
+	return &APIKeys{keys: keys}, nil
+}
+
+// Lookup returns the caller a key belongs to. Every entry is compared in
+// constant time, so response times do not reveal how close a guess was.
+func (k *APIKeys) Lookup(key string) (*Principal, bool) {
+	if k == nil || key == "" {
+		return nil, false
+	}
+
+	digest := HashAPIKey(key)
+	var match *APIKey
+	for i := range k.keys {
+		if subtle.ConstantTimeCompare([]byte(digest), []byte(k.keys[i].KeySHA256)) == 1 {
+			match = &k.keys[i]
+		}
+	}
+	if match == nil {
+		return nil, false
+	}
+
+	return &Principal{Subject: match.Subject, Roles: match.Roles, Method: MethodAPIKey, KeyName: match.Name}, true
+}
+
+// HashAPIKey returns the hex SHA-256 stored for key
+func HashAPIKey(key string) string {
+	digest := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(digest[:])
+}
+
+// NewAPIKey generates a random API key
+func NewAPIKey() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	return "key_" + hex.EncodeToString(secret), nil
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+)
+
+// APIKeyHeader is the request header carrying an API key
+const APIKeyHeader = "X-API-Key"
+
+// Config names the credentials the service accepts
+type Config struct {
+	JWTSecret   string        // HS256 secret
+	JWKSFile    string        // JWKS file of RS256 public keys and named HS256 keys
+	Issuer      string        // Required token "iss" when set
+	Audience    string        // Required token "aud" when set
+	ClockSkew   time.Duration // Leeway on token expiry and not-before times
+	APIKeysFile string        // API keys file, see LoadAPIKeys
+}
+
+// Authenticator identifies callers by a bearer JWT or an API key
+type Authenticator struct {
+	verifier *Verifier
+	apiKeys  *APIKeys
+}
+
+// New loads the configured keys. At least one kind of credential must be
+// configured, or no request could ever be authenticated.
+func New(config Config) (*Authenticator, error) {
+	var keys *KeySet
+	if config.JWKSFile != "" {
+		var err error
+		if keys, err = LoadKeySet(config.JWKSFile); err != nil {
+			return nil, err
+		}
+	}
+
+	var apiKeys *APIKeys
+	if config.APIKeysFile != "" {
+		var err error
+		if apiKeys, err = LoadAPIKeys(config.APIKeysFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.JWTSecret == "" && keys == nil && apiKeys == nil {
+		return nil, fmt.Errorf("no credentials configured: set a JWT secret, a JWKS file or an API keys file")
+	}
+
+	var verifier *Verifier
+	if config.JWTSecret != "" || keys != nil {
+		verifier = NewVerifier(VerifierConfig{
+			Secret:    []byte(config.JWTSecret),
+			Keys:      keys,
+			Issuer:    config.Issuer,
+			Audience:  config.Audience,
+			ClockSkew: config.ClockSkew,
+		})
+	}
+
+	return NewAuthenticator(verifier, apiKeys), nil
+}
+
+// NewAuthenticator builds an Authenticator from a verifier and API keys,
+// either of which may be nil
+func NewAuthenticator(verifier *Verifier, apiKeys *APIKeys) *Authenticator {
+	return &Authenticator{verifier: verifier, apiKeys: apiKeys}
+}
+
+// Authenticate identifies the caller from the request's Authorization and
+// X-API-Key header values. An API key is used when one is given. Failures
+// match models.ErrUnauthenticated.
+func (a *Authenticator) Authenticate(authorization, apiKey string) (*Principal, error) {
+	if apiKey != "" {
+		principal, ok := a.apiKeys.Lookup(apiKey)
+		if !ok {
+			return nil, models.Errorf(models.ErrUnauthenticated, "invalid API key")
+		}
+		return principal, nil
+	}
+
+	scheme, token, found := strings.Cut(strings.TrimSpace(authorization), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return nil, models.Errorf(models.ErrUnauthenticated, "bearer token or API key required")
+	}
+	if a.verifier == nil {
+		return nil, models.Errorf(models.ErrUnauthenticated, "bearer tokens are not accepted")
+	}
+
+	return a.verifier.Verify(strings.TrimSpace(token))
+}
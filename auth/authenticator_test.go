@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	apiKey, err := NewAPIKey()
+	require.NoError(t, err)
+	apiKeys, err := NewAPIKeys([]APIKey{{Name: "ops-dashboard", KeySHA256: HashAPIKey(apiKey), Subject: "svc_ops", Roles: []string{RoleOperator}}})
+	require.NoError(t, err)
+
+	authenticator := NewAuthenticator(NewVerifier(VerifierConfig{Secret: testSecret}), apiKeys)
+	token, err := SignHS256(validClaims(), testSecret, "")
+	require.NoError(t, err)
+
+	t.Run("bearer token", func(t *testing.T) {
+		principal, err := authenticator.Authenticate("Bearer "+token, "")
+		require.NoError(t, err)
+		assert.Equal(t, "user_1", principal.Subject)
+	})
+
+	t.Run("api key", func(t *testing.T) {
+		principal, err := authenticator.Authenticate("", apiKey)
+		require.NoError(t, err)
+		assert.Equal(t, "svc_ops", principal.Subject)
+		assert.Equal(t, MethodAPIKey, principal.Method)
+		assert.Equal(t, "ops-dashboard", principal.KeyName)
+		assert.True(t, principal.HasRole(RoleOperator))
+	})
+
+	failures := []struct {
+		name          string
+		authorization string
+		apiKey        string
+	}{
+		{"no credentials", "", ""},
+		{"basic auth", "Basic dXNlcjpwYXNz", ""},
+		{"empty bearer", "Bearer ", ""},
+		{"unknown api key", "", "key_unknown"},
+		{"bad api key wins over a good token", "Bearer " + token, "key_unknown"},
+	}
+	for _, tt := range failures {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := authenticator.Authenticate(tt.authorization, tt.apiKey)
+			assert.ErrorIs(t, err, models.ErrUnauthenticated)
+		})
+	}
+
+	t.Run("bearer without a verifier", func(t *testing.T) {
+		_, err := NewAuthenticator(nil, apiKeys).Authenticate("Bearer "+token, "")
+		assert.ErrorIs(t, err, models.ErrUnauthenticated)
+	})
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Config{})
+	assert.Error(t, err, "nothing configured")
+
+	dir := t.TempDir()
+	keysFile := filepath.Join(dir, "api-keys.json")
+	require.NoError(t, os.WriteFile(keysFile, []byte(`{"keys":[{"name":"ci","key_sha256":"`+HashAPIKey("key_ci")+`","subject":"svc_ci"}]}`), 0o600))
+
+	authenticator, err := New(Config{APIKeysFile: keysFile})
+	require.NoError(t, err)
+	principal, err := authenticator.Authenticate("", "key_ci")
+	require.NoError(t, err)
+	assert.Equal(t, "svc_ci", principal.Subject)
+
+	_, err = New(Config{APIKeysFile: filepath.Join(dir, "missing.json")})
+	assert.Error(t, err)
+}
+
+func TestNewAPIKeys_Invalid(t *testing.T) {
+	_, err := NewAPIKeys([]APIKey{{Name: "ci", KeySHA256: "abc", Subject: "svc_ci"}})
+	assert.Error(t, err)
+
+	_, err = NewAPIKeys([]APIKey{{KeySHA256: HashAPIKey("key"), Subject: "svc_ci"}})
+	assert.Error(t, err)
+}
+
+func TestPrincipal_CanAccess(t *testing.T) {
+	admin := &Principal{Subject: "root", Roles: []string{RoleAdmin}}
+	operator := &Principal{Subject: "ops", Roles: []string{RoleOperator}}
+	customer := &Principal{Subject: "user_1"}
+	noSubject := &Principal{}
+
+	tests := []struct {
+		name      string
+		principal *Principal
+		owner     string
+		access    Access
+		allowed   bool
+	}{
+		{"admin writes any account", admin, "user_2", AccessWrite, true},
+		{"operator reads any account", operator, "user_2", AccessRead, true},
+		{"operator cannot move money on others' accounts", operator, "user_2", AccessWrite, false},
+		{"operator writes own account", operator, "ops", AccessWrite, true},
+		{"owner writes", customer, "user_1", AccessWrite, true},
+		{"other customer cannot read", customer, "user_2", AccessRead, false},
+		{"unowned account", customer, "", AccessRead, false},
+		{"no subject never owns", noSubject, "", AccessRead, false},
+		{"anonymous is an admin", Anonymous(), "user_2", AccessWrite, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.allowed, tt.principal.CanAccess(tt.owner, tt.access))
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// minRSAKeyBits is the smallest RSA modulus accepted for verifying tokens
+const minRSAKeyBits = 2048
+
+// jsonWebKey is one entry of a JWKS file (RFC 7517). RSA keys verify RS256
+// tokens and symmetric ("oct") keys verify HS256 tokens.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	N   string `json:"n,omitempty"` // RSA modulus
+	E   string `json:"e,omitempty"` // RSA exponent
+	K   string `json:"k,omitempty"` // Symmetric key
+}
+
+// KeySet holds the token verification keys loaded from a JWKS file, by kid
+type KeySet struct {
+	publicKeys map[string]*rsa.PublicKey
+	secrets    map[string][]byte
+}
+
+// LoadKeySet reads a JWKS file. Keys marked for a use other than "sig" are
+// left out; an unsupported key type is an error so a typo does not silently
+// disable a key.
+func LoadKeySet(path string) (*KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+	return ParseKeySet(data)
+}
+
+// ParseKeySet parses the contents of a JWKS file
+func ParseKeySet(data []byte) (*KeySet, error) {
+	var document struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %w", err)
+	}
+
+	keys := &KeySet{publicKeys: map[string]*rsa.PublicKey{}, secrets: map[string][]byte{}}
+	for i, jwk := range document.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		switch jwk.Kty {
+		case "RSA":
+			if jwk.Alg != "" && jwk.Alg != AlgRS256 {
+				return nil, fmt.Errorf("JWKS key %d (%q): unsupported algorithm %q", i, jwk.Kid, jwk.Alg)
+			}
+			key, err := jwk.rsaPublicKey()
+			if err != nil {
+				return nil, fmt.Errorf("JWKS key %d (%q): %w", i, jwk.Kid, err)
+			}
+			keys.publicKeys[jwk.Kid] = key
+		case "oct":
+			if jwk.Kid == "" {
+				return nil, fmt.Errorf("JWKS key %d: symmetric keys need a kid", i)
+			}
+			if jwk.Alg != "" && jwk.Alg != AlgHS256 {
+				return nil, fmt.Errorf("JWKS key %d (%q): unsupported algorithm %q", i, jwk.Kid, jwk.Alg)
+			}
+			secret, err := base64.RawURLEncoding.DecodeString(jwk.K)
+			if err != nil || len(secret) == 0 {
+				return nil, fmt.Errorf("JWKS key %d (%q): invalid k", i, jwk.Kid)
+			}
+			keys.secrets[jwk.Kid] = secret
+		default:
+			return nil, fmt.Errorf("JWKS key %d (%q): unsupported key type %q", i, jwk.Kid, jwk.Kty)
+		}
+	}
+
+	return keys, nil
+}
+
+func (jwk *jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil || len(n) == 0 {
+		return nil, fmt.Errorf("invalid modulus")
+	}
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil || len(e) == 0 || len(e) > 4 {
+		return nil, fmt.Errorf("invalid exponent")
+	}
+
+	exponent := 0
+	for _, b := range e {
+		exponent = exponent<<8 | int(b)
+	}
+
+	key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent}
+	if bits := key.N.BitLen(); bits < minRSAKeyBits {
+		return nil, fmt.Errorf("RSA key is %d bits, at least %d are required", bits, minRSAKeyBits)
+	}
+	return key, nil
+}
+
+// PublicJWK returns the JWKS entry for an RSA public key, for publishing the
+// key a locally minted token is signed with
+func PublicJWK(key *rsa.PublicKey, kid string) map[string]string {
+	return map[string]string{
+		"kty": "RSA",
+		"kid": kid,
+		"alg": AlgRS256,
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+// publicKey returns the RSA key named kid. A token without a kid may use the
+// only RSA key in the set.
+func (k *KeySet) publicKey(kid string) *rsa.PublicKey {
+	if k == nil {
+		return nil
+	}
+	if kid == "" && len(k.publicKeys) == 1 {
+		for _, key := range k.publicKeys {
+			return key
+		}
+	}
+	return k.publicKeys[kid]
+}
+
+// secret returns the symmetric key named kid
+func (k *KeySet) secret(kid string) []byte {
+	if k == nil {
+		return nil
+	}
+	return k.secrets[kid]
+}
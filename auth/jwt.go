@@ -0,0 +1,181 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Signing algorithms accepted in a token's "alg" header
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+)
+
+// Claims are the JWT claims the service reads. Roles is a private claim
+// holding any of RoleAdmin and RoleOperator.
+type Claims struct {
+	Subject   string   `json:"sub"`
+	Roles     []string `json:"roles,omitempty"`
+	Issuer    string   `json:"iss,omitempty"`
+	Audience  Audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+}
+
+// Audience is the "aud" claim, which may be a single string or a list
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("aud must be a string or a list of strings")
+	}
+	*a = list
+	return nil
+}
+
+// The jwt.Claims methods let the parser check the registered claims
+
+func (c Claims) GetExpirationTime() (*jwt.NumericDate, error) { return numericDate(c.ExpiresAt), nil }
+func (c Claims) GetNotBefore() (*jwt.NumericDate, error)      { return numericDate(c.NotBefore), nil }
+func (c Claims) GetIssuedAt() (*jwt.NumericDate, error)       { return numericDate(c.IssuedAt), nil }
+func (c Claims) GetIssuer() (string, error)                   { return c.Issuer, nil }
+func (c Claims) GetSubject() (string, error)                  { return c.Subject, nil }
+func (c Claims) GetAudience() (jwt.ClaimStrings, error)       { return jwt.ClaimStrings(c.Audience), nil }
+
+func numericDate(seconds int64) *jwt.NumericDate {
+	if seconds == 0 {
+		return nil
+	}
+	return jwt.NewNumericDate(time.Unix(seconds, 0))
+}
+
+// VerifierConfig configures which tokens a Verifier accepts
+type VerifierConfig struct {
+	Secret    []byte        // HS256 secret; tokens without a "kid" are checked against it
+	Keys      *KeySet       // Keys loaded from a JWKS file, chosen by the token's "kid"
+	Issuer    string        // Required "iss" when set
+	Audience  string        // Required in "aud" when set
+	ClockSkew time.Duration // Leeway allowed on "exp" and "nbf"
+}
+
+// Verifier checks bearer JWTs signed with HS256 or RS256
+type Verifier struct {
+	config VerifierConfig
+	now    func() time.Time
+}
+
+func NewVerifier(config VerifierConfig) *Verifier {
+	return &Verifier{config: config, now: time.Now}
+}
+
+// Verify checks a token's signature and claims and returns the caller it
+// identifies. Failures match models.ErrUnauthenticated.
+func (v *Verifier) Verify(token string) (*Principal, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{AlgHS256, AlgRS256}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(v.config.ClockSkew),
+		jwt.WithTimeFunc(v.now),
+	}
+	if v.config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(v.config.Issuer))
+	}
+	if v.config.Audience != "" {
+		options = append(options, jwt.WithAudience(v.config.Audience))
+	}
+
+	var claims Claims
+	if _, err := jwt.ParseWithClaims(token, &claims, v.key, options...); err != nil {
+		return nil, models.Errorf(models.ErrUnauthenticated, "%s", rejection(err))
+	}
+	if claims.Subject == "" {
+		return nil, models.Errorf(models.ErrUnauthenticated, "token has no subject")
+	}
+
+	return &Principal{Subject: claims.Subject, Roles: claims.Roles, Method: MethodJWT}, nil
+}
+
+// key picks the key the token header names. The key's type must match the
+// algorithm, so an RSA public key can never be used as an HMAC secret.
+func (v *Verifier) key(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	switch token.Method.Alg() {
+	case AlgHS256:
+		secret := v.config.Secret
+		if kid != "" {
+			secret = v.config.Keys.secret(kid)
+		}
+		if len(secret) == 0 {
+			return nil, fmt.Errorf("no HS256 key for token")
+		}
+		return secret, nil
+
+	case AlgRS256:
+		key := v.config.Keys.publicKey(kid)
+		if key == nil {
+			return nil, fmt.Errorf("no RS256 key for token")
+		}
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unsupported token algorithm %q", token.Method.Alg())
+}
+
+// rejection says why a token failed to parse, without echoing its contents
+func rejection(err error) string {
+	switch {
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return "malformed token"
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid):
+		return "invalid token signature"
+	case errors.Is(err, jwt.ErrTokenUnverifiable):
+		return "token cannot be verified"
+	case errors.Is(err, jwt.ErrTokenRequiredClaimMissing):
+		return "token has no expiry"
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return "token expired"
+	case errors.Is(err, jwt.ErrTokenNotValidYet):
+		return "token not valid yet"
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return "token issuer not accepted"
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return "token not issued for this service"
+	}
+	return "invalid token"
+}
+
+// SignHS256 mints a token signed with secret. kid may be empty.
+func SignHS256(claims Claims, secret []byte, kid string) (string, error) {
+	return sign(jwt.SigningMethodHS256, claims, secret, kid)
+}
+
+// SignRS256 mints a token signed with key, naming the public key kid
+func SignRS256(claims Claims, key *rsa.PrivateKey, kid string) (string, error) {
+	return sign(jwt.SigningMethodRS256, claims, key, kid)
+}
+
+func sign(method jwt.SigningMethod, claims Claims, key interface{}, kid string) (string, error) {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return signed, nil
+}
@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSecret = []byte("test-secret")
+
+func validClaims() Claims {
+	return Claims{
+		Subject:   "user_1",
+		Roles:     []string{RoleOperator},
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func testKeySet(t *testing.T, key *rsa.PrivateKey) *KeySet {
+	document, err := json.Marshal(map[string]interface{}{
+		"keys": []interface{}{
+			PublicJWK(&key.PublicKey, "rsa-1"),
+			map[string]string{"kty": "oct", "kid": "hmac-1", "k": base64.RawURLEncoding.EncodeToString([]byte("named-secret"))},
+		},
+	})
+	require.NoError(t, err)
+	keys, err := ParseKeySet(document)
+	require.NoError(t, err)
+	return keys
+}
+
+func TestVerifier_HS256(t *testing.T) {
+	verifier := NewVerifier(VerifierConfig{Secret: testSecret})
+
+	token, err := SignHS256(validClaims(), testSecret, "")
+	require.NoError(t, err)
+
+	principal, err := verifier.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, "user_1", principal.Subject)
+	assert.Equal(t, []string{RoleOperator}, principal.Roles)
+	assert.Equal(t, MethodJWT, principal.Method)
+}
+
+func TestVerifier_RS256FromJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	verifier := NewVerifier(VerifierConfig{Keys: testKeySet(t, key)})
+
+	token, err := SignRS256(validClaims(), key, "rsa-1")
+	require.NoError(t, err)
+	principal, err := verifier.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, "user_1", principal.Subject)
+
+	// A token signed by another key is rejected
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	forged, err := SignRS256(validClaims(), other, "rsa-1")
+	require.NoError(t, err)
+	_, err = verifier.Verify(forged)
+	assert.ErrorIs(t, err, models.ErrUnauthenticated)
+
+	// Named HS256 keys come from the same set
+	named, err := SignHS256(validClaims(), []byte("named-secret"), "hmac-1")
+	require.NoError(t, err)
+	_, err = verifier.Verify(named)
+	assert.NoError(t, err)
+
+	// The RSA public key cannot be used as an HMAC secret
+	confused, err := SignHS256(validClaims(), key.PublicKey.N.Bytes(), "rsa-1")
+	require.NoError(t, err)
+	_, err = verifier.Verify(confused)
+	assert.ErrorIs(t, err, models.ErrUnauthenticated)
+}
+
+func TestVerifier_Rejects(t *testing.T) {
+	verifier := NewVerifier(VerifierConfig{
+		Secret:    testSecret,
+		Issuer:    "https://issuer.example",
+		Audience:  "banking-ledger",
+		ClockSkew: time.Minute,
+	})
+
+	valid := validClaims()
+	valid.Issuer = "https://issuer.example"
+	valid.Audience = Audience{"other", "banking-ledger"}
+
+	sign := func(change func(*Claims)) string {
+		claims := valid
+		change(&claims)
+		token, err := SignHS256(claims, testSecret, "")
+		require.NoError(t, err)
+		return token
+	}
+
+	token := sign(func(*Claims) {})
+	_, err := verifier.Verify(token)
+	require.NoError(t, err)
+
+	unsigned := func() string {
+		header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+		return header + "." + strings.Split(token, ".")[1] + "."
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"malformed", "not-a-token"},
+		{"alg none", unsigned()},
+		{"wrong secret", func() string {
+			token, _ := SignHS256(valid, []byte("other-secret"), "")
+			return token
+		}()},
+		{"tampered claims", func() string {
+			parts := strings.Split(token, ".")
+			parts[1] = base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"admin","roles":["admin"],"exp":9999999999}`))
+			return strings.Join(parts, ".")
+		}()},
+		{"expired beyond skew", sign(func(c *Claims) { c.ExpiresAt = time.Now().Add(-2 * time.Minute).Unix() })},
+		{"not valid yet", sign(func(c *Claims) { c.NotBefore = time.Now().Add(5 * time.Minute).Unix() })},
+		{"no expiry", sign(func(c *Claims) { c.ExpiresAt = 0 })},
+		{"no subject", sign(func(c *Claims) { c.Subject = "" })},
+		{"wrong issuer", sign(func(c *Claims) { c.Issuer = "https://evil.example" })},
+		{"wrong audience", sign(func(c *Claims) { c.Audience = Audience{"other"} })},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier.Verify(tt.token)
+			assert.ErrorIs(t, err, models.ErrUnauthenticated)
+		})
+	}
+
+	// Expiry within the allowed skew is still accepted
+	_, err = verifier.Verify(sign(func(c *Claims) { c.ExpiresAt = time.Now().Add(-30 * time.Second).Unix() }))
+	assert.NoError(t, err)
+}
+
+func TestAudience_UnmarshalJSON(t *testing.T) {
+	var claims Claims
+	require.NoError(t, json.Unmarshal([]byte(`{"aud":"banking-ledger"}`), &claims))
+	assert.Equal(t, Audience{"banking-ledger"}, claims.Audience)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"aud":["a","b"]}`), &claims))
+	assert.Equal(t, Audience{"a", "b"}, claims.Audience)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"aud":42}`), &claims))
+}
+
+func TestParseKeySet_Invalid(t *testing.T) {
+	tests := []string{
+		`not json`,
+		`{"keys":[{"kty":"EC","kid":"ec-1"}]}`,
+		`{"keys":[{"kty":"RSA","kid":"rsa-1","n":"","e":"AQAB"}]}`,
+		`{"keys":[{"kty":"RSA","kid":"rsa-1","alg":"RS512","n":"AQAB","e":"AQAB"}]}`,
+		`{"keys":[{"kty":"oct","k":"c2VjcmV0"}]}`,
+	}
+
+	for _, document := range tests {
+		_, err := ParseKeySet([]byte(document))
+		assert.Error(t, err, document)
+	}
+
+	// RSA keys under 2048 bits are too weak to trust
+	weak, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	document, err := json.Marshal(map[string]interface{}{"keys": []interface{}{PublicJWK(&weak.PublicKey, "rsa-weak")}})
+	require.NoError(t, err)
+	_, err = ParseKeySet(document)
+	assert.ErrorContains(t, err, "1024 bits")
+
+	// Encryption keys are skipped rather than rejected
+	keys, err := ParseKeySet([]byte(`{"keys":[{"kty":"EC","use":"enc"}]}`))
+	require.NoError(t, err)
+	assert.Nil(t, keys.publicKey(""))
+}
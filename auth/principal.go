@@ -0,0 +1,84 @@
+// Package auth identifies API callers from bearer JWTs or API keys and
+// decides what they may do with an account.
+package auth
+
+import "context"
+
+// Roles a caller can hold. A caller without one is a customer, limited to the
+// accounts it owns.
+const (
+	RoleAdmin    = "admin"    // Every endpoint, every account
+	RoleOperator = "operator" // Reads every account and runs the operational endpoints
+)
+
+// How authenticated callers proved who they are
+const (
+	MethodJWT       = "jwt"
+	MethodAPIKey    = "api_key"
+	MethodAnonymous = "anonymous" // Authentication is disabled
+)
+
+// Access is what a caller wants to do with an account
+type Access int
+
+const (
+	AccessRead  Access = iota // View the account and its records
+	AccessWrite               // Move money on it or change its standing instructions
+)
+
+// Principal is an authenticated caller
+type Principal struct {
+	Subject string   // Owner ID the caller acts as; accounts it creates belong to it
+	Roles   []string // Any of RoleAdmin and RoleOperator
+	Method  string
+	KeyName string // Name of the API key used, for API key callers
+}
+
+// Anonymous returns the principal requests run as when authentication is
+// disabled. It is an admin so every route stays reachable.
+func Anonymous() *Principal {
+	return &Principal{Roles: []string{RoleAdmin}, Method: MethodAnonymous}
+}
+
+// HasRole reports whether the caller holds any of roles
+func (p *Principal) HasRole(roles ...string) bool {
+	for _, held := range p.Roles {
+		for _, role := range roles {
+			if held == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CanAccess reports whether the caller may use an account owned by ownerID.
+// Admins may do anything, operators may read every account, and everyone
+// else only the accounts they own.
+func (p *Principal) CanAccess(ownerID string, access Access) bool {
+	if p.HasRole(RoleAdmin) {
+		return true
+	}
+	if access == AccessRead && p.HasRole(RoleOperator) {
+		return true
+	}
+	return p.Subject != "" && p.Subject == ownerID
+}
+
+type contextKey string
+
+const principalKey contextKey = "principal"
+
+// WithPrincipal adds the authenticated caller to context
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+// PrincipalFromContext returns the authenticated caller, or nil if the
+// request was not authenticated
+func PrincipalFromContext(ctx context.Context) *Principal {
+	if principal, ok := ctx.Value(principalKey).(*Principal); ok {
+		return principal
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/appy29/banking-ledger-service/config"
+	"github.com/appy29/banking-ledger-service/storage"
+	"github.com/appy29/banking-ledger-service/utils"
+)
+
+// exitError is the exit code when a subcommand could not run. A finished
+// check exits 0 when everything agreed and 1 when it found a problem.
+const exitError = 2
+
+// commands are the subcommands that run once and exit instead of starting
+// the server
+var commands = map[string]func(args []string) int{
+	"reconcile":    runReconcile,
+	"verify-chain": runVerifyChain,
+	"mint-token":   runMintToken,
+	"api-key":      runAPIKey,
+}
+
+// commandEnv is what a subcommand works with: the configuration, a logger
+// and both stores
+type commandEnv struct {
+	cfg                *config.Config
+	logger             *slog.Logger
+	accountStorage     *storage.PostgresAccountStorage
+	transactionStorage *storage.MongoTransactionStorage
+}
+
+// openCommandEnv connects to PostgreSQL and MongoDB. Logs go to stderr so the
+// report on stdout stays parseable.
+func openCommandEnv() (*commandEnv, bool) {
+	cfg := config.Load()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	accountStorage, err := storage.NewPostgresAccountStorage(cfg.GetPostgreSQLDSN())
+	if err != nil {
+		logger.Error("Failed to initialize PostgreSQL storage", slog.String("error", err.Error()))
+		return nil, false
+	}
+
+	transactionStorage, err := storage.NewMongoTransactionStorage(cfg.MongoURI, cfg.MongoDB, "transaction_logs")
+	if err != nil {
+		logger.Error("Failed to initialize MongoDB storage", slog.String("error", err.Error()))
+		accountStorage.Close()
+		return nil, false
+	}
+
+	return &commandEnv{
+		cfg:                cfg,
+		logger:             logger,
+		accountStorage:     accountStorage,
+		transactionStorage: transactionStorage,
+	}, true
+}
+
+// context returns a context carrying the logger that is cancelled on SIGINT
+// or SIGTERM
+func (e *commandEnv) context() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	return utils.WithLogger(ctx, e.logger), stop
+}
+
+func (e *commandEnv) Close() {
+	e.transactionStorage.Close()
+	e.accountStorage.Close()
+}
+
+// writeJSON writes v to stdout as indented JSON
+func writeJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/auth"
+	"github.com/appy29/banking-ledger-service/config"
+)
+
+// runMintToken implements `banking-ledger-service mint-token`: it writes a
+// JWT for -sub to stdout, signed with AUTH_JWT_SECRET (HS256) or with the RSA
+// private key in -key (RS256). With -jwks it writes the JWKS file for -key
+// instead, so the server can verify the tokens minted with it.
+func runMintToken(args []string) int {
+	flags := flag.NewFlagSet("mint-token", flag.ContinueOnError)
+	subject := flags.String("sub", "", "subject (owner ID) the token acts as")
+	roles := flags.String("roles", "", "comma-separated roles: admin, operator")
+	ttl := flags.Duration("ttl", time.Hour, "how long the token is valid")
+	keyFile := flags.String("key", "", "PEM RSA private key; signs RS256 instead of HS256")
+	kid := flags.String("kid", "local", "key ID for RS256 tokens")
+	printJWKS := flags.Bool("jwks", false, "write the JWKS file for -key instead of a token")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	var key *rsa.PrivateKey
+	if *keyFile != "" {
+		var err error
+		if key, err = loadRSAPrivateKey(*keyFile); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+	}
+
+	if *printJWKS {
+		if key == nil {
+			fmt.Fprintln(os.Stderr, "-jwks needs -key")
+			return exitError
+		}
+		if err := writeJSON(map[string]interface{}{"keys": []map[string]string{auth.PublicJWK(&key.PublicKey, *kid)}}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+		return 0
+	}
+
+	if *subject == "" {
+		fmt.Fprintln(os.Stderr, "-sub is required")
+		return exitError
+	}
+
+	cfg := config.Load()
+	now := time.Now()
+	claims := auth.Claims{
+		Subject:   *subject,
+		Roles:     splitList(*roles),
+		Issuer:    cfg.Auth.Issuer,
+		ExpiresAt: now.Add(*ttl).Unix(),
+		IssuedAt:  now.Unix(),
+	}
+	if cfg.Auth.Audience != "" {
+		claims.Audience = auth.Audience{cfg.Auth.Audience}
+	}
+
+	var token string
+	var err error
+	if key != nil {
+		token, err = auth.SignRS256(claims, key, *kid)
+	} else if cfg.Auth.JWTSecret != "" {
+		token, err = auth.SignHS256(claims, []byte(cfg.Auth.JWTSecret), "")
+	} else {
+		err = fmt.Errorf("set AUTH_JWT_SECRET or pass -key")
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+
+	fmt.Println(token)
+	return 0
+}
+
+// runAPIKey implements `banking-ledger-service api-key`: it generates an API
+// key and writes it to stdout with the entry to add to AUTH_API_KEYS_FILE.
+// The key itself is not stored anywhere, so it is only shown this once.
+func runAPIKey(args []string) int {
+	flags := flag.NewFlagSet("api-key", flag.ContinueOnError)
+	name := flags.String("name", "", "name identifying the key in logs")
+	subject := flags.String("sub", "", "subject (owner ID) the key acts as")
+	roles := flags.String("roles", "", "comma-separated roles: admin, operator")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	if *name == "" || *subject == "" {
+		fmt.Fprintln(os.Stderr, "-name and -sub are required")
+		return exitError
+	}
+
+	key, err := auth.NewAPIKey()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+
+	if err := writeJSON(map[string]interface{}{
+		"api_key": key,
+		"entry": auth.APIKey{
+			Name:      *name,
+			KeySHA256: auth.HashAPIKey(key),
+			Subject:   *subject,
+			Roles:     splitList(*roles),
+		},
+	}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+	return 0
+}
+
+// loadRSAPrivateKey reads a PKCS #1 or PKCS #8 PEM RSA private key
+func loadRSAPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM file", path)
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an RSA key", path)
+	}
+	return key, nil
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -2,11 +2,13 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"regexp"
 	"strings"
 
+	"github.com/appy29/banking-ledger-service/auth"
 	"github.com/appy29/banking-ledger-service/models"
 	"github.com/appy29/banking-ledger-service/services"
 	"github.com/appy29/banking-ledger-service/utils"
@@ -55,33 +57,47 @@ func validateOwnerName(name string) error {
 	return nil
 }
 
-// validateInitialBalance validates the initial balance amount
-func validateInitialBalance(amount float64) error {
-	if amount < 0 {
+// maxAmount is the largest balance or transaction amount the API accepts
+var maxAmount = models.MustParseMoney("999999999.99")
+
+// validateCurrency checks an optional ISO 4217 currency code
+func validateCurrency(currency string) error {
+	if currency == "" {
+		return nil
+	}
+
+	if !models.IsSupportedCurrency(currency) {
+		return fmt.Errorf("unsupported currency: %s", currency)
+	}
+
+	return nil
+}
+
+// validateInitialBalance validates the initial balance amount for the account's currency
+func validateInitialBalance(amount models.Money, currency string) error {
+	if amount.IsNegative() {
 		return errors.New("initial balance cannot be negative")
 	}
 
 	// Check for reasonable maximum
-	if amount > 999999999.99 {
+	if amount > maxAmount {
 		return errors.New("initial balance exceeds maximum allowed amount")
 	}
 
-	// Check for precision
-	// Use a small epsilon to handle floating point precision issues
-	rounded := float64(int64(amount*100+0.5)) / 100
-	if abs(amount-rounded) > 0.001 {
-		return errors.New("initial balance cannot have more than 2 decimal places")
+	if currency == "" {
+		currency = models.DefaultCurrency
 	}
 
-	return nil
-}
-
-// Helper function for absolute value
-func abs(x float64) float64 {
-	if x < 0 {
-		return -x
+	// Check for precision against the currency's minor unit
+	precision, ok := models.CurrencyPrecision(currency)
+	if !ok {
+		return fmt.Errorf("unsupported currency: %s", currency)
+	}
+	if amount.DecimalPlaces() > precision {
+		return fmt.Errorf("initial balance cannot have more than %d decimal places", precision)
 	}
-	return x
+
+	return nil
 }
 
 // CreateAccount handles POST /accounts
@@ -113,10 +129,23 @@ func (h *AccountHandler) CreateAccount(c *gin.Context) {
 		return
 	}
 
+	// Validate currency
+	req.Currency = models.NormalizeCurrency(req.Currency)
+	if err := validateCurrency(req.Currency); err != nil {
+		logger.Error("Currency validation failed",
+			slog.String("currency", req.Currency),
+			slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid currency",
+			"details": err.Error(),
+		})
+		return
+	}
+
 	// Validate initial balance
-	if err := validateInitialBalance(req.InitialBalance); err != nil {
+	if err := validateInitialBalance(req.InitialBalance, req.Currency); err != nil {
 		logger.Error("Initial balance validation failed",
-			slog.Float64("initial_balance", req.InitialBalance),
+			slog.String("initial_balance", req.InitialBalance.String()),
 			slog.String("error", err.Error()))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Invalid initial balance",
@@ -128,14 +157,29 @@ func (h *AccountHandler) CreateAccount(c *gin.Context) {
 	// Clean up the owner name
 	req.OwnerName = strings.TrimSpace(req.OwnerName)
 
+	// Admins may open an account for anyone; everyone else owns the accounts
+	// they open
+	req.OwnerID = strings.TrimSpace(req.OwnerID)
+	if principal := auth.PrincipalFromContext(ctx); principal != nil {
+		if req.OwnerID != "" && req.OwnerID != principal.Subject && !principal.HasRole(auth.RoleAdmin) {
+			logger.Warn("Account owner rejected", slog.String("owner_id", req.OwnerID))
+			respondError(c, models.Errorf(models.ErrForbidden, "only admins may open accounts for another owner"), "Invalid request", "Failed to create account")
+			return
+		}
+		if req.OwnerID == "" {
+			req.OwnerID = principal.Subject
+		}
+	}
+
 	logger.Info("Account creation request validated",
 		slog.String("owner_name", req.OwnerName),
-		slog.Float64("initial_balance", req.InitialBalance))
+		slog.String("currency", req.Currency),
+		slog.String("initial_balance", req.InitialBalance.String()))
 
 	account, err := h.accountService.CreateAccount(ctx, &req)
 	if err != nil {
 		logger.Error("Failed to create account", slog.String("error", err.Error()))
-		c.JSON(http.StatusBadRequest, gin.H{
+		c.JSON(errorStatus(err), gin.H{
 			"error": err.Error(),
 		})
 		return
@@ -144,7 +188,7 @@ func (h *AccountHandler) CreateAccount(c *gin.Context) {
 	logger.Info("Account created successfully",
 		slog.String("account_id", account.ID),
 		slog.String("owner_name", account.OwnerName),
-		slog.Float64("balance", account.Balance))
+		slog.String("balance", account.Balance.String()))
 
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Account created successfully",
@@ -164,7 +208,7 @@ func (h *AccountHandler) GetAccount(c *gin.Context) {
 	account, err := h.accountService.GetAccountByID(ctx, accountID)
 	if err != nil {
 		logger.Error("Failed to get account", slog.String("error", err.Error()))
-		c.JSON(http.StatusNotFound, gin.H{
+		c.JSON(errorStatus(err), gin.H{
 			"error": err.Error(),
 		})
 		return
@@ -172,9 +216,136 @@ func (h *AccountHandler) GetAccount(c *gin.Context) {
 
 	logger.Info("Account retrieved successfully",
 		slog.String("owner_name", account.OwnerName),
-		slog.Float64("balance", account.Balance))
+		slog.String("balance", account.Balance.String()))
+
+	c.JSON(http.StatusOK, gin.H{
+		"account": account,
+	})
+}
+
+// GetLedgerBalance handles GET /accounts/:id/ledger
+func (h *AccountHandler) GetLedgerBalance(c *gin.Context) {
+	ctx := c.Request.Context()
+	logger := utils.LoggerFromContext(ctx)
+	accountID := c.Param("id")
+
+	logger = logger.With(slog.String("account_id", accountID))
+	logger.Info("Get ledger balance request received")
+
+	balance, err := h.accountService.GetLedgerBalance(ctx, accountID)
+	if err != nil {
+		logger.Error("Failed to get ledger balance", slog.String("error", err.Error()))
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"ledger": balance,
+	})
+}
+
+// FreezeAccount handles POST /admin/accounts/:id/freeze
+func (h *AccountHandler) FreezeAccount(c *gin.Context) {
+	h.changeAccountStatus(c, models.AccountFrozen)
+}
+
+// UnfreezeAccount handles POST /admin/accounts/:id/unfreeze
+func (h *AccountHandler) UnfreezeAccount(c *gin.Context) {
+	h.changeAccountStatus(c, models.AccountActive)
+}
+
+// CloseAccount handles POST /admin/accounts/:id/close
+func (h *AccountHandler) CloseAccount(c *gin.Context) {
+	h.changeAccountStatus(c, models.AccountClosed)
+}
+
+// changeAccountStatus moves the account to status with the reason given in
+// the request body
+func (h *AccountHandler) changeAccountStatus(c *gin.Context, status string) {
+	ctx := c.Request.Context()
+	accountID := c.Param("id")
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("operation", "change_account_status"),
+		slog.String("account_id", accountID),
+		slog.String("status", status))
+
+	var req models.AccountStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	change, err := h.accountService.ChangeAccountStatus(ctx, accountID, status, &req)
+	if err != nil {
+		logger.Error("Failed to change account status", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid status change request", "Failed to change account status")
+		return
+	}
+
+	logger.Info("Account status changed", slog.String("from_status", change.FromStatus))
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Account status changed",
+		"status_change": change,
+	})
+}
+
+// GetAccountStatusHistory handles GET /admin/accounts/:id/status-history
+func (h *AccountHandler) GetAccountStatusHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID := c.Param("id")
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("operation", "get_account_status_history"),
+		slog.String("account_id", accountID))
+
+	changes, err := h.accountService.GetAccountStatusHistory(ctx, accountID)
+	if err != nil {
+		logger.Error("Failed to get account status history", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid request", "Failed to get account status history")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"account_id":     accountID,
+		"status_changes": changes,
+	})
+}
+
+// SetOverdraftLimit handles PUT /admin/accounts/:id/overdraft-limit
+func (h *AccountHandler) SetOverdraftLimit(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID := c.Param("id")
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("operation", "set_overdraft_limit"),
+		slog.String("account_id", accountID))
+
+	var req models.OverdraftLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	account, err := h.accountService.SetOverdraftLimit(ctx, accountID, &req)
+	if err != nil {
+		logger.Error("Failed to set overdraft limit", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid overdraft limit", "Failed to set overdraft limit")
+		return
+	}
+
+	logger.Info("Overdraft limit changed", slog.String("overdraft_limit", account.OverdraftLimit.String()))
 
 	c.JSON(http.StatusOK, gin.H{
+		"message": "Overdraft limit updated",
 		"account": account,
 	})
 }
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
@@ -40,9 +41,41 @@ func (m *MockAccountService) GetAccountByID(ctx context.Context, accountID strin
 	return args.Get(0).(*models.Account), args.Error(1)
 }
 
-func (m *MockAccountService) GetAccountBalance(ctx context.Context, accountID string) (float64, error) {
+func (m *MockAccountService) GetAccountBalance(ctx context.Context, accountID string) (models.Money, error) {
 	args := m.Called(ctx, accountID)
-	return args.Get(0).(float64), args.Error(1)
+	return args.Get(0).(models.Money), args.Error(1)
+}
+
+func (m *MockAccountService) GetLedgerBalance(ctx context.Context, accountID string) (*models.LedgerBalance, error) {
+	args := m.Called(ctx, accountID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LedgerBalance), args.Error(1)
+}
+
+func (m *MockAccountService) ChangeAccountStatus(ctx context.Context, accountID, status string, req *models.AccountStatusRequest) (*models.AccountStatusChange, error) {
+	args := m.Called(ctx, accountID, status, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AccountStatusChange), args.Error(1)
+}
+
+func (m *MockAccountService) GetAccountStatusHistory(ctx context.Context, accountID string) ([]models.AccountStatusChange, error) {
+	args := m.Called(ctx, accountID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AccountStatusChange), args.Error(1)
+}
+
+func (m *MockAccountService) SetOverdraftLimit(ctx context.Context, accountID string, req *models.OverdraftLimitRequest) (*models.Account, error) {
+	args := m.Called(ctx, accountID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Account), args.Error(1)
 }
 
 func setupAccountTestRouter() (*gin.Engine, *MockAccountService) {
@@ -62,6 +95,12 @@ func setupAccountTestRouter() (*gin.Engine, *MockAccountService) {
 
 	router.POST("/accounts", handler.CreateAccount)
 	router.GET("/accounts/:id", handler.GetAccount)
+	router.GET("/accounts/:id/ledger", handler.GetLedgerBalance)
+	router.POST("/admin/accounts/:id/freeze", handler.FreezeAccount)
+	router.POST("/admin/accounts/:id/unfreeze", handler.UnfreezeAccount)
+	router.POST("/admin/accounts/:id/close", handler.CloseAccount)
+	router.GET("/admin/accounts/:id/status-history", handler.GetAccountStatusHistory)
+	router.PUT("/admin/accounts/:id/overdraft-limit", handler.SetOverdraftLimit)
 
 	return router, mockService
 }
@@ -73,19 +112,19 @@ func TestCreateAccount_Success(t *testing.T) {
 	expectedAccount := &models.Account{
 		ID:        "acc_12345",
 		OwnerName: "John Doe",
-		Balance:   1000.00,
+		Balance:   models.MustParseMoney("1000.00"),
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
 	mockService.On("CreateAccount", mock.Anything, mock.MatchedBy(func(req *models.CreateAccountRequest) bool {
-		return req.OwnerName == "John Doe" && req.InitialBalance == 1000.00
+		return req.OwnerName == "John Doe" && req.InitialBalance == models.MustParseMoney("1000.00")
 	})).Return(expectedAccount, nil)
 
 	// Create request
 	requestBody := models.CreateAccountRequest{
 		OwnerName:      "John Doe",
-		InitialBalance: 1000.00,
+		InitialBalance: models.MustParseMoney("1000.00"),
 	}
 	jsonBody, _ := json.Marshal(requestBody)
 
@@ -129,22 +168,22 @@ func TestCreateAccount_InvalidJSON(t *testing.T) {
 func TestCreateAccount_InvalidBalance(t *testing.T) {
 	testCases := []struct {
 		name        string
-		balance     float64
+		balance     models.Money
 		expectedErr string
 	}{
 		{
 			name:        "Negative balance",
-			balance:     -100.00,
+			balance:     models.MustParseMoney("-100.00"),
 			expectedErr: "initial balance cannot be negative",
 		},
 		{
 			name:        "Excessive amount",
-			balance:     1000000000.00,
+			balance:     models.MustParseMoney("1000000000.00"),
 			expectedErr: "initial balance exceeds maximum allowed amount",
 		},
 		{
 			name:        "Too many decimal places",
-			balance:     100.123,
+			balance:     models.MustParseMoney("100.123"),
 			expectedErr: "initial balance cannot have more than 2 decimal places",
 		},
 	}
@@ -184,7 +223,7 @@ func TestCreateAccount_ServiceError(t *testing.T) {
 
 	requestBody := models.CreateAccountRequest{
 		OwnerName:      "John Doe",
-		InitialBalance: 1000.00,
+		InitialBalance: models.MustParseMoney("1000.00"),
 	}
 	jsonBody, _ := json.Marshal(requestBody)
 
@@ -194,7 +233,7 @@ func TestCreateAccount_ServiceError(t *testing.T) {
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
 
 	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
@@ -210,7 +249,7 @@ func TestGetAccount_Success(t *testing.T) {
 	expectedAccount := &models.Account{
 		ID:        "acc_12345",
 		OwnerName: "John Doe",
-		Balance:   1500.75,
+		Balance:   models.MustParseMoney("1500.75"),
 		CreatedAt: time.Now().Add(-24 * time.Hour),
 		UpdatedAt: time.Now(),
 	}
@@ -239,7 +278,7 @@ func TestGetAccount_Success(t *testing.T) {
 func TestGetAccount_NotFound(t *testing.T) {
 	router, mockService := setupAccountTestRouter()
 
-	mockService.On("GetAccountByID", mock.Anything, "acc_nonexistent").Return(nil, errors.New("account not found"))
+	mockService.On("GetAccountByID", mock.Anything, "acc_nonexistent").Return(nil, fmt.Errorf("failed to get account: %w", models.ErrAccountNotFound))
 
 	req, _ := http.NewRequest("GET", "/accounts/acc_nonexistent", nil)
 	w := httptest.NewRecorder()
@@ -255,6 +294,188 @@ func TestGetAccount_NotFound(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestGetLedgerBalance_Success(t *testing.T) {
+	router, mockService := setupAccountTestRouter()
+
+	mockService.On("GetLedgerBalance", mock.Anything, "acc_12345").Return(&models.LedgerBalance{
+		AccountID:     "acc_12345",
+		Currency:      "USD",
+		Balance:       models.MustParseMoney("250.00"),
+		PostedBalance: models.MustParseMoney("250.00"),
+		InBalance:     true,
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/accounts/acc_12345/ledger", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	ledger := response["ledger"].(map[string]interface{})
+	assert.Equal(t, 250.0, ledger["posted_balance"])
+	assert.Equal(t, true, ledger["in_balance"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetLedgerBalance_NotFound(t *testing.T) {
+	router, mockService := setupAccountTestRouter()
+
+	mockService.On("GetLedgerBalance", mock.Anything, "acc_nonexistent").Return(nil, fmt.Errorf("failed to get ledger balance: %w", models.ErrAccountNotFound))
+
+	req, _ := http.NewRequest("GET", "/accounts/acc_nonexistent/ledger", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestFreezeAccount_Success(t *testing.T) {
+	router, mockService := setupAccountTestRouter()
+
+	mockService.On("ChangeAccountStatus", mock.Anything, "acc_12345", models.AccountFrozen, &models.AccountStatusRequest{Reason: "Card reported stolen"}).
+		Return(&models.AccountStatusChange{
+			ID:         1,
+			AccountID:  "acc_12345",
+			FromStatus: models.AccountActive,
+			ToStatus:   models.AccountFrozen,
+			Reason:     "Card reported stolen",
+			ChangedAt:  time.Now(),
+		}, nil)
+
+	req, _ := http.NewRequest("POST", "/admin/accounts/acc_12345/freeze", bytes.NewBufferString(`{"reason":"Card reported stolen"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	change := response["status_change"].(map[string]interface{})
+	assert.Equal(t, "active", change["from_status"])
+	assert.Equal(t, "frozen", change["to_status"])
+	assert.Equal(t, "Card reported stolen", change["reason"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCloseAccount_NonZeroBalance(t *testing.T) {
+	router, mockService := setupAccountTestRouter()
+
+	mockService.On("ChangeAccountStatus", mock.Anything, "acc_12345", models.AccountClosed, mock.Anything).
+		Return(nil, fmt.Errorf("failed to change account status: %w",
+			models.Errorf(models.ErrInvalidStatusChange, "account acc_12345 has a balance of 10.00; it must be zero to close")))
+
+	req, _ := http.NewRequest("POST", "/admin/accounts/acc_12345/close", bytes.NewBufferString(`{"reason":"Customer request"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "Invalid account status change", response["error"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUnfreezeAccount_MissingReason(t *testing.T) {
+	router, mockService := setupAccountTestRouter()
+
+	mockService.On("ChangeAccountStatus", mock.Anything, "acc_12345", models.AccountActive, &models.AccountStatusRequest{}).
+		Return(nil, models.Errorf(models.ErrInvalidRequest, "a reason is required to change an account's status"))
+
+	req, _ := http.NewRequest("POST", "/admin/accounts/acc_12345/unfreeze", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetAccountStatusHistory_Success(t *testing.T) {
+	router, mockService := setupAccountTestRouter()
+
+	mockService.On("GetAccountStatusHistory", mock.Anything, "acc_12345").Return([]models.AccountStatusChange{
+		{ID: 1, AccountID: "acc_12345", FromStatus: "active", ToStatus: "frozen", Reason: "Suspicious activity"},
+		{ID: 2, AccountID: "acc_12345", FromStatus: "frozen", ToStatus: "active", Reason: "Cleared by fraud team"},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/admin/accounts/acc_12345/status-history", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response["status_changes"], 2)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSetOverdraftLimit_Success(t *testing.T) {
+	router, mockService := setupAccountTestRouter()
+
+	mockService.On("SetOverdraftLimit", mock.Anything, "acc_12345", &models.OverdraftLimitRequest{
+		OverdraftLimit: models.MustParseMoney("500.00"),
+	}).Return(&models.Account{
+		ID:               "acc_12345",
+		OwnerName:        "Acme Ltd",
+		Balance:          models.MustParseMoney("-120.00"),
+		Currency:         "USD",
+		Status:           models.AccountActive,
+		OverdraftLimit:   models.MustParseMoney("500.00"),
+		Overdrawn:        true,
+		AvailableBalance: models.MustParseMoney("380.00"),
+	}, nil)
+
+	req, _ := http.NewRequest("PUT", "/admin/accounts/acc_12345/overdraft-limit", bytes.NewBufferString(`{"overdraft_limit": 500.00}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	account := response["account"].(map[string]interface{})
+	assert.Equal(t, 500.0, account["overdraft_limit"])
+	assert.Equal(t, -120.0, account["balance"])
+	assert.Equal(t, true, account["overdrawn"])
+	assert.Equal(t, 380.0, account["available_balance"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSetOverdraftLimit_Negative(t *testing.T) {
+	router, mockService := setupAccountTestRouter()
+
+	mockService.On("SetOverdraftLimit", mock.Anything, "acc_12345", mock.Anything).
+		Return(nil, models.Errorf(models.ErrInvalidAmount, "overdraft limit cannot be negative"))
+
+	req, _ := http.NewRequest("PUT", "/admin/accounts/acc_12345/overdraft-limit", bytes.NewBufferString(`{"overdraft_limit": -10}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
 // Test validation helper functions
 func TestValidateOwnerName(t *testing.T) {
 	testCases := []struct {
@@ -289,21 +510,26 @@ func TestValidateOwnerName(t *testing.T) {
 func TestValidateInitialBalance(t *testing.T) {
 	testCases := []struct {
 		name        string
-		input       float64
+		input       models.Money
+		currency    string
 		expectError bool
 		errorMsg    string
 	}{
-		{"Valid balance", 1000.50, false, ""},
-		{"Zero balance", 0.00, false, ""},
-		{"Negative balance", -100.00, true, "cannot be negative"},
-		{"Excessive amount", 1000000000.00, true, "exceeds maximum"},
-		{"Too many decimals", 100.123, true, "more than 2 decimal places"},
-		{"Valid two decimals", 99.99, false, ""},
+		{"Valid balance", models.MustParseMoney("1000.50"), "", false, ""},
+		{"Zero balance", models.MustParseMoney("0.00"), "", false, ""},
+		{"Negative balance", models.MustParseMoney("-100.00"), "", true, "cannot be negative"},
+		{"Excessive amount", models.MustParseMoney("1000000000.00"), "", true, "exceeds maximum"},
+		{"Too many decimals", models.MustParseMoney("100.123"), "", true, "more than 2 decimal places"},
+		{"Valid two decimals", models.MustParseMoney("99.99"), "USD", false, ""},
+		{"JPY whole units", models.MustParseMoney("5000"), "JPY", false, ""},
+		{"JPY with decimals", models.MustParseMoney("5000.50"), "JPY", true, "more than 0 decimal places"},
+		{"BHD three decimals", models.MustParseMoney("12.345"), "BHD", false, ""},
+		{"Unsupported currency", models.MustParseMoney("10.00"), "XYZ", true, "unsupported currency"},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := validateInitialBalance(tc.input)
+			err := validateInitialBalance(tc.input, tc.currency)
 			if tc.expectError {
 				assert.Error(t, err)
 				assert.Contains(t, err.Error(), tc.errorMsg)
@@ -313,9 +539,3 @@ func TestValidateInitialBalance(t *testing.T) {
 		})
 	}
 }
-
-func TestAbs(t *testing.T) {
-	assert.Equal(t, 5.0, abs(-5.0))
-	assert.Equal(t, 5.0, abs(5.0))
-	assert.Equal(t, 0.0, abs(0.0))
-}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/services"
+	"github.com/appy29/banking-ledger-service/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	reaperService         services.ReaperServiceInterface
+	deadLetterService     services.DeadLetterServiceInterface
+	reconciliationService services.ReconciliationServiceInterface
+}
+
+func NewAdminHandler(reaperService services.ReaperServiceInterface, deadLetterService services.DeadLetterServiceInterface, reconciliationService services.ReconciliationServiceInterface) *AdminHandler {
+	return &AdminHandler{
+		reaperService:         reaperService,
+		deadLetterService:     deadLetterService,
+		reconciliationService: reconciliationService,
+	}
+}
+
+// GetReaperStats handles GET /api/v1/admin/reaper
+func (h *AdminHandler) GetReaperStats(c *gin.Context) {
+	ctx := c.Request.Context()
+	logger := utils.LoggerFromContext(ctx)
+
+	logger = logger.With(slog.String("operation", "get_reaper_stats"))
+
+	stats, err := h.reaperService.Stats(ctx)
+	if err != nil {
+		logger.Error("Failed to get reaper stats", slog.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get reaper stats",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reaper": stats,
+	})
+}
+
+// GetReconciliationReport handles GET /api/v1/admin/reconciliation, returning
+// the report of the scheduled reconciler's last run
+func (h *AdminHandler) GetReconciliationReport(c *gin.Context) {
+	ctx := c.Request.Context()
+	logger := utils.LoggerFromContext(ctx)
+
+	logger = logger.With(slog.String("operation", "get_reconciliation_report"))
+
+	report, err := h.reconciliationService.LastReport(ctx)
+	if err != nil {
+		logger.Error("Failed to get reconciliation report", slog.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get reconciliation report",
+		})
+		return
+	}
+	if report == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "No reconciliation has run yet",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reconciliation": report,
+		"unresolved":     report.Unresolved(),
+	})
+}
+
+// ListDeadLetters handles GET /api/v1/admin/dlq
+func (h *AdminHandler) ListDeadLetters(c *gin.Context) {
+	ctx := c.Request.Context()
+	logger := utils.LoggerFromContext(ctx)
+
+	// Get pagination parameters from middleware
+	page := c.GetInt("page")
+	limit := c.GetInt("limit")
+	if page == 0 {
+		page = 1
+	}
+	if limit == 0 {
+		limit = 10
+	}
+	status := c.Query("status")
+
+	logger = logger.With(
+		slog.String("operation", "list_dead_letters"),
+		slog.String("status", status),
+		slog.Int("page", page),
+		slog.Int("limit", limit),
+	)
+
+	deadLetters, total, err := h.deadLetterService.ListDeadLetters(ctx, status, page, limit)
+	if err != nil {
+		logger.Error("Failed to list dead letters", slog.String("error", err.Error()))
+
+		if errors.Is(err, models.ErrInvalidDeadLetterStatus) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+				"field": "status",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to retrieve dead letters",
+				"details": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"dead_letters": deadLetters,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	})
+}
+
+// GetDeadLetter handles GET /api/v1/admin/dlq/:id
+func (h *AdminHandler) GetDeadLetter(c *gin.Context) {
+	ctx := c.Request.Context()
+	logger := utils.LoggerFromContext(ctx)
+	id := c.Param("id")
+
+	logger = logger.With(
+		slog.String("operation", "get_dead_letter"),
+		slog.String("dead_letter_id", id))
+
+	deadLetter, err := h.deadLetterService.GetDeadLetter(ctx, id)
+	if err != nil {
+		logger.Error("Failed to get dead letter", slog.String("error", err.Error()))
+		h.respondDeadLetterError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deadLetter)
+}
+
+// ReplayDeadLetter handles POST /api/v1/admin/dlq/:id/replay
+func (h *AdminHandler) ReplayDeadLetter(c *gin.Context) {
+	ctx := c.Request.Context()
+	logger := utils.LoggerFromContext(ctx)
+	id := c.Param("id")
+
+	logger = logger.With(
+		slog.String("operation", "replay_dead_letter"),
+		slog.String("dead_letter_id", id))
+
+	deadLetter, err := h.deadLetterService.ReplayDeadLetter(ctx, id)
+	if err != nil {
+		logger.Error("Failed to replay dead letter", slog.String("error", err.Error()))
+		h.respondDeadLetterError(c, err)
+		return
+	}
+
+	logger.Info("Dead letter replayed", slog.String("message_id", deadLetter.MessageID))
+	c.JSON(http.StatusOK, deadLetter)
+}
+
+// DiscardDeadLetter handles POST /api/v1/admin/dlq/:id/discard
+func (h *AdminHandler) DiscardDeadLetter(c *gin.Context) {
+	ctx := c.Request.Context()
+	logger := utils.LoggerFromContext(ctx)
+	id := c.Param("id")
+
+	logger = logger.With(
+		slog.String("operation", "discard_dead_letter"),
+		slog.String("dead_letter_id", id))
+
+	deadLetter, err := h.deadLetterService.DiscardDeadLetter(ctx, id)
+	if err != nil {
+		logger.Error("Failed to discard dead letter", slog.String("error", err.Error()))
+		h.respondDeadLetterError(c, err)
+		return
+	}
+
+	logger.Info("Dead letter discarded", slog.String("message_id", deadLetter.MessageID))
+	c.JSON(http.StatusOK, deadLetter)
+}
+
+func (h *AdminHandler) respondDeadLetterError(c *gin.Context, err error) {
+	respondError(c, err, "Invalid dead letter request", "Failed to process dead letter")
+}
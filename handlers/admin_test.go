@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/queue"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockReaperService for testing
+type MockReaperService struct {
+	mock.Mock
+}
+
+func (m *MockReaperService) Stats(ctx context.Context) (*models.ReaperStats, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReaperStats), args.Error(1)
+}
+
+// MockDeadLetterService for testing
+type MockDeadLetterService struct {
+	mock.Mock
+}
+
+func (m *MockDeadLetterService) ListDeadLetters(ctx context.Context, status string, page, limit int) ([]models.DeadLetter, int64, error) {
+	args := m.Called(ctx, status, page, limit)
+	return args.Get(0).([]models.DeadLetter), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockDeadLetterService) GetDeadLetter(ctx context.Context, id string) (*models.DeadLetter, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DeadLetter), args.Error(1)
+}
+
+func (m *MockDeadLetterService) ReplayDeadLetter(ctx context.Context, id string) (*models.DeadLetter, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DeadLetter), args.Error(1)
+}
+
+func (m *MockDeadLetterService) DiscardDeadLetter(ctx context.Context, id string) (*models.DeadLetter, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DeadLetter), args.Error(1)
+}
+
+// MockReconciliationService for testing
+type MockReconciliationService struct {
+	mock.Mock
+}
+
+func (m *MockReconciliationService) Reconcile(ctx context.Context, opts models.ReconcileOptions) (*models.ReconciliationReport, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReconciliationReport), args.Error(1)
+}
+
+func (m *MockReconciliationService) LastReport(ctx context.Context) (*models.ReconciliationReport, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReconciliationReport), args.Error(1)
+}
+
+func setupAdminTestRouter() (*gin.Engine, *MockReaperService, *MockDeadLetterService) {
+	router, mockReaper, mockDeadLetters, _ := setupAdminTestRouterWithReconciliation()
+	return router, mockReaper, mockDeadLetters
+}
+
+func setupAdminTestRouterWithReconciliation() (*gin.Engine, *MockReaperService, *MockDeadLetterService, *MockReconciliationService) {
+	gin.SetMode(gin.TestMode)
+
+	mockReaper := &MockReaperService{}
+	mockDeadLetters := &MockDeadLetterService{}
+	mockReconciliation := &MockReconciliationService{}
+	handler := NewAdminHandler(mockReaper, mockDeadLetters, mockReconciliation)
+
+	router := gin.New()
+	router.GET("/admin/reaper", handler.GetReaperStats)
+	router.GET("/admin/reconciliation", handler.GetReconciliationReport)
+	router.GET("/admin/dlq", handler.ListDeadLetters)
+	router.GET("/admin/dlq/:id", handler.GetDeadLetter)
+	router.POST("/admin/dlq/:id/replay", handler.ReplayDeadLetter)
+	router.POST("/admin/dlq/:id/discard", handler.DiscardDeadLetter)
+
+	return router, mockReaper, mockDeadLetters, mockReconciliation
+}
+
+func TestGetReaperStats_Success(t *testing.T) {
+	router, mockReaper, _ := setupAdminTestRouter()
+
+	mockReaper.On("Stats", mock.Anything).Return(&models.ReaperStats{
+		Runs:          3,
+		Completed:     1,
+		Requeued:      2,
+		StalePending:  5,
+		MaxAgeSeconds: 600,
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/admin/reaper", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Reaper models.ReaperStats `json:"reaper"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, int64(2), response.Reaper.Requeued)
+	assert.Equal(t, int64(5), response.Reaper.StalePending)
+
+	mockReaper.AssertExpectations(t)
+}
+
+func TestGetReconciliationReport_Success(t *testing.T) {
+	router, _, _, mockReconciliation := setupAdminTestRouterWithReconciliation()
+
+	mockReconciliation.On("LastReport", mock.Anything).Return(&models.ReconciliationReport{
+		AccountsChecked: 12,
+		Issues: []models.ReconciliationIssue{
+			{AccountID: "acc_12345", Kind: models.ReconcileBalanceMismatch, Expected: models.MustParseMoney("100.00"), Actual: models.MustParseMoney("90.00"), Repaired: true},
+			{AccountID: "acc_67890", Kind: models.ReconcileOrphanedPending, TransactionID: "txn_1"},
+		},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/admin/reconciliation", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Reconciliation models.ReconciliationReport `json:"reconciliation"`
+		Unresolved     int                         `json:"unresolved"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 12, response.Reconciliation.AccountsChecked)
+	assert.Len(t, response.Reconciliation.Issues, 2)
+	assert.Equal(t, 1, response.Unresolved)
+
+	mockReconciliation.AssertExpectations(t)
+}
+
+func TestGetReconciliationReport_NotRunYet(t *testing.T) {
+	router, _, _, mockReconciliation := setupAdminTestRouterWithReconciliation()
+
+	mockReconciliation.On("LastReport", mock.Anything).Return(nil, nil)
+
+	req, _ := http.NewRequest("GET", "/admin/reconciliation", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockReconciliation.AssertExpectations(t)
+}
+
+func TestGetReaperStats_StorageError(t *testing.T) {
+	router, mockReaper, _ := setupAdminTestRouter()
+
+	mockReaper.On("Stats", mock.Anything).Return(nil, errors.New("failed to count stale pending transactions: connection refused"))
+
+	req, _ := http.NewRequest("GET", "/admin/reaper", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	mockReaper.AssertExpectations(t)
+}
+
+func TestListDeadLetters_FiltersByStatus(t *testing.T) {
+	router, _, mockDeadLetters := setupAdminTestRouter()
+
+	mockDeadLetters.On("ListDeadLetters", mock.Anything, "dead", 1, 10).
+		Return([]models.DeadLetter{{ID: "dlq_1", MessageID: "txn_1", Reason: "rejected", Status: "dead"}}, int64(1), nil)
+
+	req, _ := http.NewRequest("GET", "/admin/dlq?status=dead", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		DeadLetters []models.DeadLetter `json:"dead_letters"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.DeadLetters, 1)
+	assert.Equal(t, "rejected", response.DeadLetters[0].Reason)
+
+	mockDeadLetters.AssertExpectations(t)
+}
+
+func TestReplayDeadLetter_Success(t *testing.T) {
+	router, _, mockDeadLetters := setupAdminTestRouter()
+
+	mockDeadLetters.On("ReplayDeadLetter", mock.Anything, "dlq_1").
+		Return(&models.DeadLetter{ID: "dlq_1", MessageID: "txn_1", Status: "replayed"}, nil)
+
+	req, _ := http.NewRequest("POST", "/admin/dlq/dlq_1/replay", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"replayed"`)
+	mockDeadLetters.AssertExpectations(t)
+}
+
+func TestReplayDeadLetter_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{"not found", models.ErrDeadLetterNotFound, http.StatusNotFound},
+		{"already resolved", models.Errorf(models.ErrDeadLetterResolved, "dead letter is already replayed"), http.StatusConflict},
+		{"broker down", fmt.Errorf("failed to replay dead letter: %w", queue.ErrNotConnected), http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, _, mockDeadLetters := setupAdminTestRouter()
+			mockDeadLetters.On("ReplayDeadLetter", mock.Anything, "dlq_1").Return(nil, tt.err)
+
+			req, _ := http.NewRequest("POST", "/admin/dlq/dlq_1/replay", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
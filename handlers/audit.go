@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/services"
+	"github.com/appy29/banking-ledger-service/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService services.AuditServiceInterface
+}
+
+func NewAuditHandler(auditService services.AuditServiceInterface) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// parseAuditFilter reads the audit trail filters from the query string
+func parseAuditFilter(c *gin.Context) (*models.AuditFilter, error) {
+	filter := &models.AuditFilter{
+		Actor:     strings.TrimSpace(c.Query("actor")),
+		AccountID: strings.TrimSpace(c.Query("account_id")),
+	}
+
+	var err error
+	if value := c.Query("from"); value != "" {
+		if filter.From, err = parseQueryTime(value, false); err != nil {
+			return nil, err
+		}
+	}
+	if value := c.Query("to"); value != "" {
+		if filter.To, err = parseQueryTime(value, true); err != nil {
+			return nil, err
+		}
+	}
+
+	return filter, nil
+}
+
+// QueryAuditTrail handles GET /api/v1/admin/audit, returning a cursor-paginated
+// page of the audit trail filtered by actor, account and time range
+func (h *AuditHandler) QueryAuditTrail(c *gin.Context) {
+	ctx := c.Request.Context()
+	logger := utils.LoggerFromContext(ctx)
+
+	limit := c.GetInt("limit")
+	if limit == 0 {
+		limit = 10
+	}
+
+	logger = logger.With(
+		slog.String("operation", "query_audit_trail"),
+		slog.Int("limit", limit),
+	)
+
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		logger.Error("Invalid audit filter", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid audit trail query",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	page, err := h.auditService.Query(ctx, filter, c.Query("cursor"), limit)
+	if err != nil {
+		logger.Error("Failed to query audit trail", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid audit trail query", "Failed to query audit trail")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"records": page.Records,
+		"pagination": gin.H{
+			"limit":       limit,
+			"next_cursor": page.NextCursor,
+			"has_more":    page.HasMore,
+		},
+	})
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAuditService for testing
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) Record(ctx context.Context, record *models.AuditRecord) error {
+	return m.Called(ctx, record).Error(0)
+}
+
+func (m *MockAuditService) Query(ctx context.Context, filter *models.AuditFilter, cursor string, limit int) (*models.AuditPage, error) {
+	args := m.Called(ctx, filter, cursor, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AuditPage), args.Error(1)
+}
+
+func setupAuditTestRouter() (*gin.Engine, *MockAuditService) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := &MockAuditService{}
+	router := gin.New()
+	router.GET("/admin/audit", NewAuditHandler(mockService).QueryAuditTrail)
+	return router, mockService
+}
+
+func TestQueryAuditTrail_Success(t *testing.T) {
+	router, mockService := setupAuditTestRouter()
+
+	expectedFilter := &models.AuditFilter{
+		Actor:     "user_1",
+		AccountID: "acc_12345",
+		From:      time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:        time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+	mockService.On("Query", mock.Anything, expectedFilter, "", 10).Return(&models.AuditPage{
+		Records: []models.AuditRecord{{
+			ID:         "aud_12345",
+			Actor:      "user_1",
+			Action:     "POST /api/v1/accounts/:id/transactions",
+			AccountIDs: []string{"acc_12345"},
+			Outcome:    models.AuditRejected,
+			StatusCode: http.StatusUnprocessableEntity,
+		}},
+		NextCursor: "next",
+		HasMore:    true,
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/admin/audit?actor=user_1&account_id=acc_12345&from=2025-01-01&to=2025-01-31", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	records := response["records"].([]interface{})
+	assert.Len(t, records, 1)
+	assert.Equal(t, "rejected", records[0].(map[string]interface{})["outcome"])
+	pagination := response["pagination"].(map[string]interface{})
+	assert.Equal(t, "next", pagination["next_cursor"])
+	assert.Equal(t, true, pagination["has_more"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestQueryAuditTrail_Invalid(t *testing.T) {
+	router, mockService := setupAuditTestRouter()
+	mockService.On("Query", mock.Anything, mock.Anything, "bad", 10).Return(nil, models.Errorf(models.ErrInvalidRequest, "invalid cursor"))
+
+	for _, query := range []string{"from=yesterday", "cursor=bad"} {
+		t.Run(query, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/admin/audit?"+query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response map[string]interface{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "Invalid audit trail query", response["error"])
+		})
+	}
+}
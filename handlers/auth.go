@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/appy29/banking-ledger-service/auth"
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// AccessCheck is one of the AccessService checks, applied to the record the
+// :id path parameter names
+type AccessCheck func(ctx context.Context, id string, access auth.Access) error
+
+// Authorize lets a request through only if the caller may use the record in
+// its path, e.g. Authorize(accessService.CheckAccount, auth.AccessWrite)
+func Authorize(check AccessCheck, access auth.Access) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		if err := check(ctx, c.Param("id"), access); err != nil {
+			utils.LoggerFromContext(ctx).Warn("Request not authorized", slog.String("error", err.Error()))
+			respondError(c, err, "Invalid request", "Failed to authorize request")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireRole lets a request through only if the caller holds one of roles
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		principal := auth.PrincipalFromContext(ctx)
+
+		var err error
+		switch {
+		case principal == nil:
+			err = models.ErrUnauthenticated
+		case !principal.HasRole(roles...):
+			err = models.Errorf(models.ErrForbidden, "requires one of the roles %v", roles)
+		}
+		if err != nil {
+			utils.LoggerFromContext(ctx).Warn("Request not authorized", slog.String("error", err.Error()))
+			respondError(c, err, "Invalid request", "Failed to authorize request")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/appy29/banking-ledger-service/auth"
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAccessService for testing
+type MockAccessService struct {
+	mock.Mock
+}
+
+func (m *MockAccessService) CheckAccount(ctx context.Context, accountID string, access auth.Access) error {
+	return m.Called(ctx, accountID, access).Error(0)
+}
+
+func (m *MockAccessService) CheckTransaction(ctx context.Context, transactionID string, access auth.Access) error {
+	return m.Called(ctx, transactionID, access).Error(0)
+}
+
+func (m *MockAccessService) CheckTransfer(ctx context.Context, transferID string, access auth.Access) error {
+	return m.Called(ctx, transferID, access).Error(0)
+}
+
+func (m *MockAccessService) CheckHold(ctx context.Context, holdID string, access auth.Access) error {
+	return m.Called(ctx, holdID, access).Error(0)
+}
+
+func (m *MockAccessService) CheckSchedule(ctx context.Context, scheduleID string, access auth.Access) error {
+	return m.Called(ctx, scheduleID, access).Error(0)
+}
+
+func (m *MockAccessService) CheckWebhook(ctx context.Context, webhookID string, access auth.Access) error {
+	return m.Called(ctx, webhookID, access).Error(0)
+}
+
+// setupAuthTestRouter returns a router whose requests run as principal; a
+// nil principal leaves them unauthenticated
+func setupAuthTestRouter(principal *auth.Principal) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+		ctx := utils.WithLogger(c.Request.Context(), logger)
+		if principal != nil {
+			ctx = auth.WithPrincipal(ctx, principal)
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	})
+	return router
+}
+
+func okHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+func TestAuthorize(t *testing.T) {
+	mockAccess := &MockAccessService{}
+	mockAccess.On("CheckAccount", mock.Anything, "acc_mine", auth.AccessWrite).Return(nil)
+	mockAccess.On("CheckAccount", mock.Anything, "acc_theirs", auth.AccessWrite).Return(models.Errorf(models.ErrForbidden, "subject \"user_1\" does not own the account"))
+	mockAccess.On("CheckAccount", mock.Anything, "acc_missing", auth.AccessWrite).Return(models.ErrAccountNotFound)
+
+	router := setupAuthTestRouter(&auth.Principal{Subject: "user_1"})
+	router.POST("/accounts/:id/transactions", Authorize(mockAccess.CheckAccount, auth.AccessWrite), okHandler)
+
+	tests := []struct {
+		accountID      string
+		expectedStatus int
+		expectedError  string
+	}{
+		{"acc_mine", http.StatusOK, ""},
+		{"acc_theirs", http.StatusForbidden, "Access denied"},
+		{"acc_missing", http.StatusNotFound, "Account not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accountID, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "/accounts/"+tt.accountID+"/transactions", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				var response map[string]interface{}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedError, response["error"])
+			}
+		})
+	}
+
+	mockAccess.AssertExpectations(t)
+}
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name           string
+		principal      *auth.Principal
+		expectedStatus int
+	}{
+		{"operator", &auth.Principal{Subject: "ops", Roles: []string{auth.RoleOperator}}, http.StatusOK},
+		{"admin", &auth.Principal{Subject: "root", Roles: []string{auth.RoleAdmin}}, http.StatusOK},
+		{"customer", &auth.Principal{Subject: "user_1"}, http.StatusForbidden},
+		{"unauthenticated", nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupAuthTestRouter(tt.principal)
+			router.GET("/admin/reaper", RequireRole(auth.RoleOperator, auth.RoleAdmin), okHandler)
+
+			req, _ := http.NewRequest("GET", "/admin/reaper", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestProcessTransfer_SourceAccountNotOwned(t *testing.T) {
+	mockService := &MockTransactionService{}
+	mockAccess := &MockAccessService{}
+	mockAccess.On("CheckAccount", mock.Anything, "acc_theirs", auth.AccessWrite).Return(models.Errorf(models.ErrForbidden, "subject \"user_1\" does not own the account"))
+
+	router := setupAuthTestRouter(&auth.Principal{Subject: "user_1"})
+	router.POST("/transfers", NewTransferHandler(mockService, mockAccess, nil, false).ProcessTransfer)
+
+	jsonBody, _ := json.Marshal(models.TransferRequest{
+		FromAccountID: "acc_theirs",
+		ToAccountID:   "acc_mine",
+		Amount:        models.MustParseMoney("150.00"),
+	})
+	req, _ := http.NewRequest("POST", "/transfers", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockService.AssertNotCalled(t, "ProcessTransfer", mock.Anything, mock.Anything)
+	mockAccess.AssertExpectations(t)
+}
+
+func TestCreateAccount_Owner(t *testing.T) {
+	tests := []struct {
+		name           string
+		principal      *auth.Principal
+		ownerID        string
+		expectedStatus int
+		expectedOwner  string
+	}{
+		{"customer owns new account", &auth.Principal{Subject: "user_1"}, "", http.StatusCreated, "user_1"},
+		{"customer names self", &auth.Principal{Subject: "user_1"}, "user_1", http.StatusCreated, "user_1"},
+		{"customer names someone else", &auth.Principal{Subject: "user_1"}, "user_2", http.StatusForbidden, ""},
+		{"admin opens for a customer", &auth.Principal{Subject: "root", Roles: []string{auth.RoleAdmin}}, "user_2", http.StatusCreated, "user_2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAccountService{}
+			router := setupAuthTestRouter(tt.principal)
+			router.POST("/accounts", NewAccountHandler(mockService).CreateAccount)
+
+			if tt.expectedStatus == http.StatusCreated {
+				mockService.On("CreateAccount", mock.Anything, mock.MatchedBy(func(req *models.CreateAccountRequest) bool {
+					return req.OwnerID == tt.expectedOwner
+				})).Return(&models.Account{ID: "acc_12345", OwnerName: "John Doe", OwnerID: tt.expectedOwner}, nil)
+			}
+
+			jsonBody, _ := json.Marshal(models.CreateAccountRequest{OwnerName: "John Doe", OwnerID: tt.ownerID})
+			req, _ := http.NewRequest("POST", "/accounts", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
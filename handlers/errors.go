@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/queue"
+	"github.com/gin-gonic/gin"
+)
+
+// errorStatus maps a service error to the HTTP status it is reported with.
+// Errors the services did not classify are treated as server failures.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrUnauthenticated):
+		return http.StatusUnauthorized
+
+	case errors.Is(err, models.ErrForbidden):
+		return http.StatusForbidden
+
+	case errors.Is(err, models.ErrAccountNotFound),
+		errors.Is(err, models.ErrTransactionNotFound),
+		errors.Is(err, models.ErrTransferNotFound),
+		errors.Is(err, models.ErrHoldNotFound),
+		errors.Is(err, models.ErrScheduleNotFound),
+		errors.Is(err, models.ErrDeadLetterNotFound),
+		errors.Is(err, models.ErrWebhookNotFound),
+		errors.Is(err, models.ErrWebhookDeliveryNotFound):
+		return http.StatusNotFound
+
+	case errors.Is(err, models.ErrInvalidRequest),
+		errors.Is(err, models.ErrInsufficientFunds),
+		errors.Is(err, models.ErrInvalidTransactionType),
+		errors.Is(err, models.ErrInvalidAmount),
+		errors.Is(err, models.ErrSameAccount),
+		errors.Is(err, models.ErrCurrencyMismatch),
+		errors.Is(err, models.ErrUnsupportedCurrency),
+		errors.Is(err, models.ErrInvalidPrecision),
+		errors.Is(err, models.ErrInvalidSchedule),
+		errors.Is(err, models.ErrInvalidDeadLetterStatus),
+		errors.Is(err, models.ErrInvalidWebhook):
+		return http.StatusBadRequest
+
+	case errors.Is(err, models.ErrIdempotencyKeyReused),
+		errors.Is(err, models.ErrLimitExceeded):
+		return http.StatusUnprocessableEntity
+
+	case errors.Is(err, models.ErrIdempotencyInProgress),
+		errors.Is(err, models.ErrIdempotencyIncomplete),
+		errors.Is(err, models.ErrNotPending),
+		errors.Is(err, models.ErrAlreadyApplied),
+		errors.Is(err, models.ErrAlreadyReversed),
+		errors.Is(err, models.ErrNotReversible),
+		errors.Is(err, models.ErrTransactionSealed),
+		errors.Is(err, models.ErrHoldNotActive),
+		errors.Is(err, models.ErrAccountFrozen),
+		errors.Is(err, models.ErrAccountClosed),
+		errors.Is(err, models.ErrInvalidStatusChange),
+		errors.Is(err, models.ErrInvalidScheduleChange),
+		errors.Is(err, models.ErrDeadLetterResolved),
+		errors.Is(err, models.ErrWebhookDeliveryPending):
+		return http.StatusConflict
+
+	case errors.Is(err, queue.ErrNotConnected):
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusInternalServerError
+}
+
+// errorSummaries name the failures that get their own "error" text
+var errorSummaries = []struct {
+	err     error
+	summary string
+}{
+	{models.ErrUnauthenticated, "Authentication required"},
+	{models.ErrForbidden, "Access denied"},
+	{models.ErrInsufficientFunds, "Insufficient funds"},
+	{models.ErrLimitExceeded, "Transaction limit exceeded"},
+	{models.ErrAccountNotFound, "Account not found"},
+	{models.ErrAccountFrozen, "Account is frozen"},
+	{models.ErrAccountClosed, "Account is closed"},
+	{models.ErrInvalidStatusChange, "Invalid account status change"},
+	{models.ErrTransactionNotFound, "Transaction not found"},
+	{models.ErrTransferNotFound, "Transfer not found"},
+	{models.ErrHoldNotFound, "Hold not found"},
+	{models.ErrHoldNotActive, "Hold is not active"},
+	{models.ErrScheduleNotFound, "Schedule not found"},
+	{models.ErrInvalidScheduleChange, "Invalid schedule change"},
+	{models.ErrAlreadyReversed, "Transaction already reversed"},
+	{models.ErrIdempotencyKeyReused, "Idempotency key already used"},
+	{models.ErrIdempotencyInProgress, "Request already in progress"},
+	{models.ErrIdempotencyIncomplete, "Request already applied"},
+	{models.ErrDeadLetterNotFound, "Dead letter not found"},
+	{models.ErrDeadLetterResolved, "Dead letter already resolved"},
+	{models.ErrWebhookNotFound, "Webhook not found"},
+	{models.ErrWebhookDeliveryNotFound, "Webhook delivery not found"},
+	{models.ErrWebhookDeliveryPending, "Webhook delivery still pending"},
+	{queue.ErrNotConnected, "Message queue unavailable"},
+}
+
+// respondError writes err with the status errorStatus picks. Well-known
+// failures get their own summary; anything else is reported as invalid for
+// client errors and as failed for server errors.
+func respondError(c *gin.Context, err error, invalid, failed string) {
+	status := errorStatus(err)
+
+	summary := failed
+	if status < http.StatusInternalServerError {
+		summary = invalid
+	}
+	for _, known := range errorSummaries {
+		if errors.Is(err, known.err) {
+			summary = known.summary
+			break
+		}
+	}
+
+	c.JSON(status, gin.H{
+		"error":   summary,
+		"details": err.Error(),
+	})
+}
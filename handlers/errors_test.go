@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/queue"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{"account not found", fmt.Errorf("failed to get account: %w", models.ErrAccountNotFound), http.StatusNotFound},
+		{"transfer not found", fmt.Errorf("failed to get transfer: %w", models.ErrTransferNotFound), http.StatusNotFound},
+		{"insufficient funds", fmt.Errorf("failed to process transfer: %w", &models.InsufficientFundsError{}), http.StatusBadRequest},
+		{"invalid request", models.Errorf(models.ErrInvalidRequest, "account ID is required"), http.StatusBadRequest},
+		{"precision", models.Errorf(models.ErrInvalidPrecision, "too many decimal places"), http.StatusBadRequest},
+		{"idempotency key reused", models.ErrIdempotencyKeyReused, http.StatusUnprocessableEntity},
+		{"not pending", models.Errorf(models.ErrNotPending, "transaction is not in pending state: completed"), http.StatusConflict},
+		{"unauthenticated", models.Errorf(models.ErrUnauthenticated, "token expired"), http.StatusUnauthorized},
+		{"forbidden", models.Errorf(models.ErrForbidden, "account belongs to another owner"), http.StatusForbidden},
+		{"webhook delivery pending", models.Errorf(models.ErrWebhookDeliveryPending, "webhook delivery whd_1 is still pending"), http.StatusConflict},
+		{"queue down", fmt.Errorf("failed to replay dead letter: %w", queue.ErrNotConnected), http.StatusServiceUnavailable},
+		{"unclassified", errors.New("failed to save transaction: database error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedStatus, errorStatus(tt.err))
+		})
+	}
+}
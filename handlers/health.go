@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"time"
 
+	"github.com/appy29/banking-ledger-service/models"
 	"github.com/appy29/banking-ledger-service/queue"
 	"github.com/appy29/banking-ledger-service/services"
 	"github.com/gin-gonic/gin"
@@ -95,7 +97,7 @@ func (h *HealthHandler) checkPostgresConnection(ctx context.Context) HealthStatu
 
 	if err != nil {
 		// We expect "account not found" error - this means DB is working
-		if err.Error() == "failed to get account: account not found" {
+		if errors.Is(err, models.ErrAccountNotFound) {
 			return HealthStatus{
 				Healthy: true,
 				Status:  "connected",
@@ -124,7 +126,7 @@ func (h *HealthHandler) checkMongoConnection(ctx context.Context) HealthStatus {
 
 	if err != nil {
 		// We expect "transaction not found" error - this means MongoDB is working
-		if err.Error() == "failed to get transaction: transaction not found" {
+		if errors.Is(err, models.ErrTransactionNotFound) {
 			return HealthStatus{
 				Healthy: true,
 				Status:  "connected",
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// PlaceHold handles POST /accounts/:id/holds
+func (h *TransactionHandler) PlaceHold(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID := c.Param("id")
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("operation", "place_hold"),
+		slog.String("account_id", accountID))
+
+	var req models.CreateHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	req.Currency = models.NormalizeCurrency(req.Currency)
+	err := validateCurrency(req.Currency)
+	if err == nil {
+		err = validateTransactionAmount(req.Amount, req.Currency)
+	}
+	if err != nil {
+		logger.Error("Hold validation failed", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid hold request",
+			"details": err.Error(),
+		})
+		return
+	}
+	req.Description = strings.TrimSpace(req.Description)
+
+	hold, err := h.transactionService.PlaceHold(ctx, accountID, &req)
+	if err != nil {
+		logger.Error("Failed to place hold", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid hold request", "Failed to place hold")
+		return
+	}
+
+	logger.Info("Hold placed", slog.String("hold_id", hold.ID))
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Hold placed successfully",
+		"hold":    hold,
+	})
+}
+
+// GetAccountHolds handles GET /accounts/:id/holds
+func (h *TransactionHandler) GetAccountHolds(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID := c.Param("id")
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("operation", "get_account_holds"),
+		slog.String("account_id", accountID))
+
+	holds, err := h.transactionService.GetAccountHolds(ctx, accountID)
+	if err != nil {
+		logger.Error("Failed to get holds", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid request", "Failed to get holds")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"account_id": accountID,
+		"holds":      holds,
+	})
+}
+
+// GetHold handles GET /holds/:id
+func (h *TransactionHandler) GetHold(c *gin.Context) {
+	ctx := c.Request.Context()
+	holdID := c.Param("id")
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("operation", "get_hold"),
+		slog.String("hold_id", holdID))
+
+	hold, err := h.transactionService.GetHold(ctx, holdID)
+	if err != nil {
+		logger.Error("Failed to get hold", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid request", "Failed to get hold")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"hold": hold,
+	})
+}
+
+// CaptureHold handles POST /holds/:id/capture. An empty body captures the
+// full hold.
+func (h *TransactionHandler) CaptureHold(c *gin.Context) {
+	ctx := c.Request.Context()
+	holdID := c.Param("id")
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("operation", "capture_hold"),
+		slog.String("hold_id", holdID))
+
+	var req models.CaptureHoldRequest
+	if err := bindOptionalJSON(c, &req); err != nil {
+		logger.Error("Invalid request body", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if req.Amount.IsNegative() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid capture request",
+			"details": "amount must be greater than 0",
+		})
+		return
+	}
+	req.Description = strings.TrimSpace(req.Description)
+
+	capture, err := h.transactionService.CaptureHold(ctx, holdID, &req)
+	if err != nil {
+		logger.Error("Hold capture failed", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid capture request", "Hold capture failed")
+		return
+	}
+
+	c.Set(transactionIDContextKey, capture.Transaction.TransactionID)
+	logger.Info("Hold captured",
+		slog.String("transaction_id", capture.Transaction.TransactionID),
+		slog.String("captured_amount", capture.Hold.CapturedAmount.String()))
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Hold captured successfully",
+		"hold":        capture.Hold,
+		"transaction": capture.Transaction,
+	})
+}
+
+// ReleaseHold handles POST /holds/:id/release
+func (h *TransactionHandler) ReleaseHold(c *gin.Context) {
+	ctx := c.Request.Context()
+	holdID := c.Param("id")
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("operation", "release_hold"),
+		slog.String("hold_id", holdID))
+
+	hold, err := h.transactionService.ReleaseHold(ctx, holdID)
+	if err != nil {
+		logger.Error("Hold release failed", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid release request", "Hold release failed")
+		return
+	}
+
+	logger.Info("Hold released")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Hold released successfully",
+		"hold":    hold,
+	})
+}
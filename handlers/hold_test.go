@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPlaceHold_Success(t *testing.T) {
+	router, mockService := setupTransactionTestRouter(false)
+
+	mockService.On("PlaceHold", mock.Anything, "acc_12345", &models.CreateHoldRequest{
+		Amount:      models.MustParseMoney("80.00"),
+		Currency:    "USD",
+		Description: "Card authorization",
+	}).Return(&models.Hold{
+		ID:        "hold_12345",
+		AccountID: "acc_12345",
+		Amount:    models.MustParseMoney("80.00"),
+		Currency:  "USD",
+		Status:    models.HoldActive,
+		ExpiresAt: time.Now().Add(models.DefaultHoldTTL),
+	}, nil)
+
+	body := `{"amount": 80.00, "currency": "usd", "description": " Card authorization "}`
+	req, _ := http.NewRequest("POST", "/accounts/acc_12345/holds", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	hold := response["hold"].(map[string]interface{})
+	assert.Equal(t, "hold_12345", hold["id"])
+	assert.Equal(t, "active", hold["status"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestPlaceHold_InvalidAmount(t *testing.T) {
+	router, mockService := setupTransactionTestRouter(false)
+
+	req, _ := http.NewRequest("POST", "/accounts/acc_12345/holds", bytes.NewBufferString(`{"amount": 0}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "PlaceHold")
+}
+
+func TestPlaceHold_InsufficientFunds(t *testing.T) {
+	router, mockService := setupTransactionTestRouter(false)
+
+	mockService.On("PlaceHold", mock.Anything, "acc_12345", mock.Anything).
+		Return(nil, fmt.Errorf("failed to place hold: %w", &models.InsufficientFundsError{AccountID: "acc_12345", Balance: models.MustParseMoney("20.00"), Requested: models.MustParseMoney("80.00")}))
+
+	req, _ := http.NewRequest("POST", "/accounts/acc_12345/holds", bytes.NewBufferString(`{"amount": 80.00}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "Insufficient funds", response["error"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCaptureHold_EmptyBodyCapturesInFull(t *testing.T) {
+	router, mockService := setupTransactionTestRouter(false)
+
+	mockService.On("CaptureHold", mock.Anything, "hold_12345", &models.CaptureHoldRequest{}).Return(&models.HoldCapture{
+		Hold: &models.Hold{
+			ID:             "hold_12345",
+			AccountID:      "acc_12345",
+			Amount:         models.MustParseMoney("80.00"),
+			Status:         models.HoldCaptured,
+			CapturedAmount: models.MustParseMoney("80.00"),
+			TransactionID:  "txn_capture",
+		},
+		Transaction: &models.Transaction{
+			ID:            "txn_capture",
+			TransactionID: "txn_capture",
+			AccountID:     "acc_12345",
+			Type:          "withdraw",
+			Amount:        models.MustParseMoney("80.00"),
+			Status:        "completed",
+		},
+	}, nil)
+
+	req, _ := http.NewRequest("POST", "/holds/hold_12345/capture", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "captured", response["hold"].(map[string]interface{})["status"])
+	assert.Equal(t, "txn_capture", response["transaction"].(map[string]interface{})["transaction_id"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestReleaseHold_NotActive(t *testing.T) {
+	router, mockService := setupTransactionTestRouter(false)
+
+	mockService.On("ReleaseHold", mock.Anything, "hold_12345").
+		Return(nil, fmt.Errorf("failed to release hold: %w", models.Errorf(models.ErrHoldNotActive, "hold hold_12345 is expired")))
+
+	req, _ := http.NewRequest("POST", "/holds/hold_12345/release", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "Hold is not active", response["error"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetHold_NotFound(t *testing.T) {
+	router, mockService := setupTransactionTestRouter(false)
+
+	mockService.On("GetHold", mock.Anything, "hold_missing").
+		Return(nil, fmt.Errorf("failed to get hold: %w", models.ErrHoldNotFound))
+
+	req, _ := http.NewRequest("GET", "/holds/hold_missing", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
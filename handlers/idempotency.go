@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/services"
+	"github.com/appy29/banking-ledger-service/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header clients use to make retries safe
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotentReplayHeader marks a response that was replayed from storage
+	IdempotentReplayHeader = "Idempotent-Replayed"
+
+	// maxIdempotencyKeyLength bounds the header so it can be indexed cheaply
+	maxIdempotencyKeyLength = 255
+
+	// transactionIDContextKey lets the processing paths report which transaction
+	// the stored response belongs to
+	transactionIDContextKey = "idempotency_transaction_id"
+)
+
+// bodyRecorder tees everything written to the client into a buffer so the
+// response can be stored against the idempotency key
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *bodyRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *bodyRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// beginIdempotentRequest handles the Idempotency-Key header. It returns false
+// when the response has already been written (a replay or an error), and
+// otherwise a finish function that must run once the request was processed.
+func beginIdempotentRequest(c *gin.Context, idempotencyService services.IdempotencyServiceInterface, accountID string, req *models.TransactionRequest) (func(), bool) {
+	key := strings.TrimSpace(c.GetHeader(IdempotencyKeyHeader))
+	if key == "" || idempotencyService == nil {
+		return func() {}, true
+	}
+
+	ctx := c.Request.Context()
+	logger := utils.LoggerFromContext(ctx).With(slog.String("idempotency_key", key))
+
+	if len(key) > maxIdempotencyKeyLength {
+		logger.Error("Idempotency key too long", slog.Int("length", len(key)))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid idempotency key",
+			"details": "Idempotency-Key cannot exceed 255 characters",
+		})
+		return nil, false
+	}
+
+	req.IdempotencyKey = key
+
+	record, err := idempotencyService.BeginRequest(ctx, accountID, key, req)
+	if err != nil {
+		logger.Error("Idempotency check failed", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid idempotency key", "Failed to check idempotency key")
+		return nil, false
+	}
+
+	if record != nil {
+		logger.Info("Replaying stored response",
+			slog.Int("status_code", record.StatusCode),
+			slog.String("transaction_id", record.TransactionID))
+		c.Header(IdempotentReplayHeader, "true")
+		c.Data(record.StatusCode, "application/json; charset=utf-8", record.ResponseBody)
+		return nil, false
+	}
+
+	recorder := &bodyRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+
+	finish := func() {
+		status := recorder.Status()
+		body := recorder.body.Bytes()
+		transactionID := c.GetString(transactionIDContextKey)
+
+		// Server-side failures are not final; let the client retry with the
+		// same key, unless the reserved transaction reached the ledger anyway
+		if status >= http.StatusInternalServerError {
+			transaction, applied, err := idempotencyService.AppliedTransaction(ctx, req.TransactionID)
+			switch {
+			case err != nil:
+				// Keep the claim; a retry checks again once it goes stale
+				logger.Error("Failed to check ledger after server error", slog.String("error", err.Error()))
+				return
+			case !applied:
+				logger.Warn("Releasing idempotency key after server error", slog.Int("status_code", status))
+				idempotencyService.ReleaseRequest(ctx, accountID, key)
+				return
+			case transaction == nil:
+				// The balance changed but no record says how; keep the claim
+				// so retries are told so instead of applying it again
+				logger.Warn("Keeping idempotency key: transaction was applied without a record",
+					slog.String("transaction_id", req.TransactionID))
+				return
+			}
+
+			logger.Warn("Transaction was applied despite server error, storing its outcome",
+				slog.Int("status_code", status),
+				slog.String("transaction_id", transaction.TransactionID))
+			status, body, err = appliedResponse(transaction)
+			if err != nil {
+				logger.Error("Failed to build idempotent response", slog.String("error", err.Error()))
+				return
+			}
+			transactionID = transaction.TransactionID
+		}
+
+		if err := idempotencyService.CompleteRequest(ctx, accountID, key, status, body, transactionID); err != nil {
+			logger.Error("Failed to store idempotent response", slog.String("error", err.Error()))
+		}
+	}
+
+	return finish, true
+}
+
+// appliedResponse is the response a request should have had when its
+// transaction reached the log although the request failed: the sync response
+// for a settled transaction, and the queued response otherwise
+func appliedResponse(transaction *models.Transaction) (int, []byte, error) {
+	status := http.StatusAccepted
+	response := gin.H{
+		"message":         "Transaction queued for processing",
+		"transaction_id":  transaction.TransactionID,
+		"status":          transaction.Status,
+		"account_id":      transaction.AccountID,
+		"processing_mode": "async",
+	}
+
+	switch transaction.Status {
+	case "completed", "partially_refunded", "reversed":
+		status = http.StatusOK
+		response = gin.H{
+			"message":         "Transaction processed successfully",
+			"transaction":     transaction,
+			"processing_mode": "sync",
+		}
+	}
+
+	body, err := json.Marshal(response)
+	return status, body, err
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockIdempotencyService for testing
+type MockIdempotencyService struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyService) BeginRequest(ctx context.Context, accountID, key string, req *models.TransactionRequest) (*models.IdempotencyRecord, error) {
+	args := m.Called(ctx, accountID, key, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IdempotencyRecord), args.Error(1)
+}
+
+func (m *MockIdempotencyService) CompleteRequest(ctx context.Context, accountID, key string, statusCode int, responseBody []byte, transactionID string) error {
+	args := m.Called(ctx, accountID, key, statusCode, responseBody, transactionID)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyService) ReleaseRequest(ctx context.Context, accountID, key string) error {
+	args := m.Called(ctx, accountID, key)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyService) AppliedTransaction(ctx context.Context, transactionID string) (*models.Transaction, bool, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).(*models.Transaction), args.Bool(1), args.Error(2)
+}
+
+func setupIdempotencyTestRouter() (*gin.Engine, *MockTransactionService, *MockIdempotencyService) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := &MockTransactionService{}
+	mockIdempotency := &MockIdempotencyService{}
+	handler := NewTransactionHandler(mockService, mockIdempotency, nil, false)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+		ctx := utils.WithLogger(c.Request.Context(), logger)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	})
+
+	router.POST("/accounts/:id/transactions", handler.ProcessTransaction)
+
+	return router, mockService, mockIdempotency
+}
+
+func newIdempotentRequest(key string, body models.TransactionRequest) *http.Request {
+	jsonBody, _ := json.Marshal(body)
+	req, _ := http.NewRequest("POST", "/accounts/acc_12345/transactions", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IdempotencyKeyHeader, key)
+	return req
+}
+
+func TestProcessTransaction_IdempotencyKey_StoresResponse(t *testing.T) {
+	router, mockService, mockIdempotency := setupIdempotencyTestRouter()
+
+	mockIdempotency.On("BeginRequest", mock.Anything, "acc_12345", "key-1", mock.Anything).Return(nil, nil)
+	mockService.On("ProcessTransaction", mock.Anything, "acc_12345", mock.MatchedBy(func(req *models.TransactionRequest) bool {
+		return req.IdempotencyKey == "key-1"
+	})).Return(&models.Transaction{ID: "txn_12345", TransactionID: "txn_12345", Status: "completed"}, nil)
+	mockIdempotency.On("CompleteRequest", mock.Anything, "acc_12345", "key-1", http.StatusOK, mock.MatchedBy(func(body []byte) bool {
+		return bytes.Contains(body, []byte(`"txn_12345"`))
+	}), "txn_12345").Return(nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newIdempotentRequest("key-1", models.TransactionRequest{Type: "deposit", Amount: models.MustParseMoney("50.00")}))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get(IdempotentReplayHeader))
+
+	mockService.AssertExpectations(t)
+	mockIdempotency.AssertExpectations(t)
+}
+
+func TestProcessTransaction_IdempotencyKey_ReplaysOriginalResponse(t *testing.T) {
+	router, mockService, mockIdempotency := setupIdempotencyTestRouter()
+
+	original := []byte(`{"message":"Transaction queued for processing","status":"pending","transaction_id":"txn_original"}`)
+	mockIdempotency.On("BeginRequest", mock.Anything, "acc_12345", "key-1", mock.Anything).
+		Return(&models.IdempotencyRecord{StatusCode: http.StatusAccepted, ResponseBody: original, TransactionID: "txn_original"}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newIdempotentRequest("key-1", models.TransactionRequest{Type: "deposit", Amount: models.MustParseMoney("50.00")}))
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, "true", w.Header().Get(IdempotentReplayHeader))
+	assert.JSONEq(t, string(original), w.Body.String())
+
+	// The transaction must not be processed a second time
+	mockService.AssertNotCalled(t, "ProcessTransaction", mock.Anything, mock.Anything, mock.Anything)
+	mockIdempotency.AssertExpectations(t)
+}
+
+func TestProcessTransaction_IdempotencyKey_DifferentBody(t *testing.T) {
+	router, _, mockIdempotency := setupIdempotencyTestRouter()
+
+	mockIdempotency.On("BeginRequest", mock.Anything, "acc_12345", "key-1", mock.Anything).
+		Return(nil, models.ErrIdempotencyKeyReused)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newIdempotentRequest("key-1", models.TransactionRequest{Type: "withdraw", Amount: models.MustParseMoney("75.00")}))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockIdempotency.AssertExpectations(t)
+}
+
+func TestProcessTransaction_IdempotencyKey_ReleasedOnServerError(t *testing.T) {
+	router, mockService, mockIdempotency := setupIdempotencyTestRouter()
+
+	mockIdempotency.On("BeginRequest", mock.Anything, "acc_12345", "key-1", mock.Anything).Return(nil, nil)
+	mockService.On("ProcessTransaction", mock.Anything, "acc_12345", mock.Anything).
+		Return(nil, errors.New("failed to save transaction: database error"))
+	mockIdempotency.On("AppliedTransaction", mock.Anything, "").Return(nil, false, nil)
+	mockIdempotency.On("ReleaseRequest", mock.Anything, "acc_12345", "key-1").Return(nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newIdempotentRequest("key-1", models.TransactionRequest{Type: "deposit", Amount: models.MustParseMoney("50.00")}))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	mockIdempotency.AssertNotCalled(t, "CompleteRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockIdempotency.AssertExpectations(t)
+}
+
+// claimKey makes BeginRequest claim the key and reserve transactionID, as the
+// real service does
+func claimKey(mockIdempotency *MockIdempotencyService, transactionID string) {
+	mockIdempotency.On("BeginRequest", mock.Anything, "acc_12345", "key-1", mock.Anything).
+		Run(func(args mock.Arguments) {
+			args.Get(3).(*models.TransactionRequest).TransactionID = transactionID
+		}).
+		Return(nil, nil)
+}
+
+func TestProcessTransaction_IdempotencyKey_ServerErrorAfterApply(t *testing.T) {
+	router, mockService, mockIdempotency := setupIdempotencyTestRouter()
+
+	claimKey(mockIdempotency, "txn_reserved")
+	mockService.On("ProcessTransaction", mock.Anything, "acc_12345", mock.Anything).
+		Return(nil, errors.New("failed to update transaction: timeout"))
+	mockIdempotency.On("AppliedTransaction", mock.Anything, "txn_reserved").
+		Return(&models.Transaction{ID: "txn_reserved", TransactionID: "txn_reserved", AccountID: "acc_12345", Status: "completed"}, true, nil)
+
+	// The key is completed with the applied transaction, never released
+	mockIdempotency.On("CompleteRequest", mock.Anything, "acc_12345", "key-1", http.StatusOK, mock.MatchedBy(func(body []byte) bool {
+		return bytes.Contains(body, []byte(`"txn_reserved"`)) && bytes.Contains(body, []byte(`"processing_mode":"sync"`))
+	}), "txn_reserved").Return(nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newIdempotentRequest("key-1", models.TransactionRequest{Type: "deposit", Amount: models.MustParseMoney("50.00")}))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	mockIdempotency.AssertNotCalled(t, "ReleaseRequest", mock.Anything, mock.Anything, mock.Anything)
+	mockIdempotency.AssertExpectations(t)
+}
+
+func TestProcessTransaction_IdempotencyKey_ServerErrorAfterBalanceChange(t *testing.T) {
+	router, mockService, mockIdempotency := setupIdempotencyTestRouter()
+
+	claimKey(mockIdempotency, "txn_reserved")
+	mockService.On("ProcessTransaction", mock.Anything, "acc_12345", mock.Anything).
+		Return(nil, errors.New("failed to save transaction: database error"))
+	mockIdempotency.On("AppliedTransaction", mock.Anything, "txn_reserved").Return(nil, true, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newIdempotentRequest("key-1", models.TransactionRequest{Type: "deposit", Amount: models.MustParseMoney("50.00")}))
+
+	// Without a record there is no outcome to store; the claim stays so a
+	// retry cannot apply the transaction a second time
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	mockIdempotency.AssertNotCalled(t, "ReleaseRequest", mock.Anything, mock.Anything, mock.Anything)
+	mockIdempotency.AssertNotCalled(t, "CompleteRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockIdempotency.AssertExpectations(t)
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// GetAccountLimits handles GET /admin/accounts/:id/limits
+func (h *TransactionHandler) GetAccountLimits(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID := c.Param("id")
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("operation", "get_account_limits"),
+		slog.String("account_id", accountID))
+
+	limits, err := h.transactionService.GetAccountLimits(ctx, accountID)
+	if err != nil {
+		logger.Error("Failed to get account limits", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid request", "Failed to get account limits")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"limits": limits,
+	})
+}
+
+// SetAccountLimits handles PUT /admin/accounts/:id/limits. The body replaces
+// the account's overrides; limits left out or null fall back to the defaults.
+func (h *TransactionHandler) SetAccountLimits(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID := c.Param("id")
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("operation", "set_account_limits"),
+		slog.String("account_id", accountID))
+
+	var req models.LimitOverrides
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	limits, err := h.transactionService.SetAccountLimits(ctx, accountID, &req)
+	if err != nil {
+		logger.Error("Failed to set account limits", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid limits", "Failed to set account limits")
+		return
+	}
+
+	logger.Info("Account limits updated")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Account limits updated",
+		"limits":  limits,
+	})
+}
+
+// GetLimitBreaches handles GET /admin/accounts/:id/limit-breaches
+func (h *TransactionHandler) GetLimitBreaches(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID := c.Param("id")
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("operation", "get_limit_breaches"),
+		slog.String("account_id", accountID))
+
+	breaches, err := h.transactionService.GetLimitBreaches(ctx, accountID)
+	if err != nil {
+		logger.Error("Failed to get limit breaches", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid request", "Failed to get limit breaches")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"account_id": accountID,
+		"breaches":   breaches,
+	})
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProcessTransaction_LimitExceeded(t *testing.T) {
+	router, mockService := setupTransactionTestRouter(false)
+
+	mockService.On("ProcessTransaction", mock.Anything, "acc_12345", mock.Anything).
+		Return(nil, fmt.Errorf("failed to process transaction: %w", &models.LimitExceededError{
+			AccountID: "acc_12345",
+			Limit:     models.LimitMaxWithdrawal,
+			Max:       "500.00",
+			Attempted: "800.00",
+		}))
+
+	req, _ := http.NewRequest("POST", "/accounts/acc_12345/transactions", bytes.NewBufferString(`{"type": "withdraw", "amount": 800.00}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "Transaction limit exceeded", response["error"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSetAccountLimits_Success(t *testing.T) {
+	router, mockService := setupTransactionTestRouter(false)
+
+	raised := models.MustParseMoney("5000.00")
+	mockService.On("SetAccountLimits", mock.Anything, "acc_12345", &models.LimitOverrides{MaxWithdrawal: &raised}).
+		Return(&models.AccountLimits{
+			AccountID: "acc_12345",
+			Defaults:  models.TransactionLimits{MaxWithdrawal: models.MustParseMoney("500.00")},
+			Overrides: models.LimitOverrides{MaxWithdrawal: &raised},
+			Effective: models.TransactionLimits{MaxWithdrawal: raised},
+		}, nil)
+
+	req, _ := http.NewRequest("PUT", "/admin/accounts/acc_12345/limits", bytes.NewBufferString(`{"max_withdrawal": 5000.00, "max_deposit": null}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	limits := response["limits"].(map[string]interface{})
+	assert.Equal(t, 5000.0, limits["effective"].(map[string]interface{})["max_withdrawal"])
+	assert.Nil(t, limits["overrides"].(map[string]interface{})["max_deposit"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSetAccountLimits_Negative(t *testing.T) {
+	router, mockService := setupTransactionTestRouter(false)
+
+	mockService.On("SetAccountLimits", mock.Anything, "acc_12345", mock.Anything).
+		Return(nil, models.Errorf(models.ErrInvalidAmount, "max_deposit cannot be negative"))
+
+	req, _ := http.NewRequest("PUT", "/admin/accounts/acc_12345/limits", bytes.NewBufferString(`{"max_deposit": -1}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
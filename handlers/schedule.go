@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/services"
+	"github.com/appy29/banking-ledger-service/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type ScheduleHandler struct {
+	scheduleService services.ScheduleServiceInterface
+}
+
+func NewScheduleHandler(scheduleService services.ScheduleServiceInterface) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduleService: scheduleService,
+	}
+}
+
+// CreateSchedule handles POST /accounts/:id/schedules
+func (h *ScheduleHandler) CreateSchedule(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID := c.Param("id")
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("operation", "create_schedule"),
+		slog.String("account_id", accountID))
+
+	var req models.CreateScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// The amount, type and currency are checked like an immediate transaction's
+	txReq := models.TransactionRequest{Type: req.Type, Amount: req.Amount, Currency: req.Currency, Description: req.Description}
+	if err := validateTransactionRequest(&txReq); err != nil {
+		logger.Error("Schedule validation failed", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid schedule request",
+			"details": err.Error(),
+		})
+		return
+	}
+	req.Type, req.Currency, req.Description = txReq.Type, txReq.Currency, txReq.Description
+
+	schedule, err := h.scheduleService.CreateSchedule(ctx, accountID, &req)
+	if err != nil {
+		logger.Error("Failed to create schedule", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid schedule request", "Failed to create schedule")
+		return
+	}
+
+	logger.Info("Schedule created", slog.String("schedule_id", schedule.ID))
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Schedule created successfully",
+		"schedule": schedule,
+	})
+}
+
+// GetAccountSchedules handles GET /accounts/:id/schedules
+func (h *ScheduleHandler) GetAccountSchedules(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID := c.Param("id")
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("operation", "get_account_schedules"),
+		slog.String("account_id", accountID))
+
+	schedules, err := h.scheduleService.GetAccountSchedules(ctx, accountID)
+	if err != nil {
+		logger.Error("Failed to get schedules", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid request", "Failed to get schedules")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"account_id": accountID,
+		"schedules":  schedules,
+	})
+}
+
+// GetSchedule handles GET /schedules/:id
+func (h *ScheduleHandler) GetSchedule(c *gin.Context) {
+	ctx := c.Request.Context()
+	scheduleID := c.Param("id")
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("operation", "get_schedule"),
+		slog.String("schedule_id", scheduleID))
+
+	schedule, err := h.scheduleService.GetSchedule(ctx, scheduleID)
+	if err != nil {
+		logger.Error("Failed to get schedule", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid request", "Failed to get schedule")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"schedule": schedule,
+	})
+}
+
+// UpdateSchedule handles PATCH /schedules/:id
+func (h *ScheduleHandler) UpdateSchedule(c *gin.Context) {
+	ctx := c.Request.Context()
+	scheduleID := c.Param("id")
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("operation", "update_schedule"),
+		slog.String("schedule_id", scheduleID))
+
+	var req models.UpdateScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	schedule, err := h.scheduleService.UpdateSchedule(ctx, scheduleID, &req)
+	if err != nil {
+		logger.Error("Failed to update schedule", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid schedule request", "Failed to update schedule")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Schedule updated",
+		"schedule": schedule,
+	})
+}
+
+// CancelSchedule handles DELETE /schedules/:id. The schedule is kept, marked
+// cancelled, so its history stays visible.
+func (h *ScheduleHandler) CancelSchedule(c *gin.Context) {
+	h.changeSchedule(c, "cancel_schedule", "Schedule cancelled", h.scheduleService.CancelSchedule)
+}
+
+// PauseSchedule handles POST /schedules/:id/pause
+func (h *ScheduleHandler) PauseSchedule(c *gin.Context) {
+	h.changeSchedule(c, "pause_schedule", "Schedule paused", h.scheduleService.PauseSchedule)
+}
+
+// ResumeSchedule handles POST /schedules/:id/resume
+func (h *ScheduleHandler) ResumeSchedule(c *gin.Context) {
+	h.changeSchedule(c, "resume_schedule", "Schedule resumed", h.scheduleService.ResumeSchedule)
+}
+
+// SkipSchedule handles POST /schedules/:id/skip, which passes over the next
+// occurrence
+func (h *ScheduleHandler) SkipSchedule(c *gin.Context) {
+	h.changeSchedule(c, "skip_schedule", "Next occurrence skipped", h.scheduleService.SkipSchedule)
+}
+
+// changeSchedule runs a body-less schedule control and reports the result
+func (h *ScheduleHandler) changeSchedule(c *gin.Context, operation, message string, change func(ctx context.Context, scheduleID string) (*models.Schedule, error)) {
+	ctx := c.Request.Context()
+	scheduleID := c.Param("id")
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("operation", operation),
+		slog.String("schedule_id", scheduleID))
+
+	schedule, err := change(ctx, scheduleID)
+	if err != nil {
+		logger.Error("Failed to change schedule", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid request", "Failed to change schedule")
+		return
+	}
+
+	logger.Info(message, slog.String("status", schedule.Status))
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  message,
+		"schedule": schedule,
+	})
+}
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockScheduleService for testing
+type MockScheduleService struct {
+	mock.Mock
+}
+
+func (m *MockScheduleService) CreateSchedule(ctx context.Context, accountID string, req *models.CreateScheduleRequest) (*models.Schedule, error) {
+	args := m.Called(ctx, accountID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleService) GetSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error) {
+	args := m.Called(ctx, scheduleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleService) GetAccountSchedules(ctx context.Context, accountID string) ([]models.Schedule, error) {
+	args := m.Called(ctx, accountID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleService) UpdateSchedule(ctx context.Context, scheduleID string, req *models.UpdateScheduleRequest) (*models.Schedule, error) {
+	args := m.Called(ctx, scheduleID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleService) CancelSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error) {
+	args := m.Called(ctx, scheduleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleService) PauseSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error) {
+	args := m.Called(ctx, scheduleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleService) ResumeSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error) {
+	args := m.Called(ctx, scheduleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleService) SkipSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error) {
+	args := m.Called(ctx, scheduleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Schedule), args.Error(1)
+}
+
+func setupScheduleTestRouter() (*gin.Engine, *MockScheduleService) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := &MockScheduleService{}
+	handler := NewScheduleHandler(mockService)
+
+	router := gin.New()
+	router.POST("/accounts/:id/schedules", handler.CreateSchedule)
+	router.GET("/accounts/:id/schedules", handler.GetAccountSchedules)
+	router.GET("/schedules/:id", handler.GetSchedule)
+	router.PATCH("/schedules/:id", handler.UpdateSchedule)
+	router.DELETE("/schedules/:id", handler.CancelSchedule)
+	router.POST("/schedules/:id/pause", handler.PauseSchedule)
+	router.POST("/schedules/:id/resume", handler.ResumeSchedule)
+	router.POST("/schedules/:id/skip", handler.SkipSchedule)
+
+	return router, mockService
+}
+
+func TestCreateSchedule_Success(t *testing.T) {
+	router, mockService := setupScheduleTestRouter()
+
+	next := time.Date(2030, 4, 1, 0, 0, 0, 0, time.UTC)
+	mockService.On("CreateSchedule", mock.Anything, "acc_12345", &models.CreateScheduleRequest{
+		Type:        "deposit",
+		Amount:      models.MustParseMoney("500.00"),
+		Currency:    "USD",
+		Description: "Rent",
+		Cron:        "@monthly",
+	}).Return(&models.Schedule{
+		ID:         "sch_12345",
+		AccountID:  "acc_12345",
+		Type:       "deposit",
+		Amount:     models.MustParseMoney("500.00"),
+		Currency:   "USD",
+		Cron:       "@monthly",
+		MissedRuns: models.MissedRunsLatest,
+		Status:     models.ScheduleActive,
+		NextRunAt:  &next,
+	}, nil)
+
+	body := `{"type": " DEPOSIT ", "amount": 500.00, "currency": "usd", "description": " Rent ", "cron": "@monthly"}`
+	req, _ := http.NewRequest("POST", "/accounts/acc_12345/schedules", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	schedule := response["schedule"].(map[string]interface{})
+	assert.Equal(t, "sch_12345", schedule["id"])
+	assert.Equal(t, "2030-04-01T00:00:00Z", schedule["next_run_at"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateSchedule_InvalidType(t *testing.T) {
+	router, mockService := setupScheduleTestRouter()
+
+	req, _ := http.NewRequest("POST", "/accounts/acc_12345/schedules", bytes.NewBufferString(`{"type": "transfer", "amount": 10, "cron": "@daily"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "CreateSchedule")
+}
+
+func TestCreateSchedule_InvalidCron(t *testing.T) {
+	router, mockService := setupScheduleTestRouter()
+
+	mockService.On("CreateSchedule", mock.Anything, "acc_12345", mock.Anything).
+		Return(nil, models.Errorf(models.ErrInvalidSchedule, "cron expression \"every day\" must have 5 fields"))
+
+	req, _ := http.NewRequest("POST", "/accounts/acc_12345/schedules", bytes.NewBufferString(`{"type": "deposit", "amount": 10, "cron": "every day"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateSchedule_Success(t *testing.T) {
+	router, mockService := setupScheduleTestRouter()
+
+	amount := models.MustParseMoney("750.00")
+	mockService.On("UpdateSchedule", mock.Anything, "sch_12345", &models.UpdateScheduleRequest{Amount: &amount}).
+		Return(&models.Schedule{ID: "sch_12345", Amount: amount, Status: models.ScheduleActive}, nil)
+
+	req, _ := http.NewRequest("PATCH", "/schedules/sch_12345", bytes.NewBufferString(`{"amount": 750.00}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPauseSchedule_NotActive(t *testing.T) {
+	router, mockService := setupScheduleTestRouter()
+
+	mockService.On("PauseSchedule", mock.Anything, "sch_12345").
+		Return(nil, models.Errorf(models.ErrInvalidScheduleChange, "schedule sch_12345 is paused, not active"))
+
+	req, _ := http.NewRequest("POST", "/schedules/sch_12345/pause", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "Invalid schedule change", response["error"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCancelSchedule_NotFound(t *testing.T) {
+	router, mockService := setupScheduleTestRouter()
+
+	mockService.On("CancelSchedule", mock.Anything, "sch_missing").Return(nil, models.ErrScheduleNotFound)
+
+	req, _ := http.NewRequest("DELETE", "/schedules/sch_missing", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
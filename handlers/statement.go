@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// dateLayout is the date-only form accepted wherever an RFC 3339 time is
+const dateLayout = "2006-01-02"
+
+// parseQueryTime parses an RFC 3339 time or a date. A date stands for the
+// start of that day in UTC, or with endOfDay for the start of the next day,
+// so that a date used as an exclusive bound includes the whole day.
+func parseQueryTime(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: use RFC 3339 or YYYY-MM-DD", value)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// statementPeriod reads a statement's [from, to) range from either month
+// (YYYY-MM) or from and to
+func statementPeriod(c *gin.Context) (time.Time, time.Time, error) {
+	if month := c.Query("month"); month != "" {
+		from, err := time.Parse("2006-01", month)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid month %q: use YYYY-MM", month)
+		}
+		return from, from.AddDate(0, 1, 0), nil
+	}
+
+	if c.Query("from") == "" || c.Query("to") == "" {
+		return time.Time{}, time.Time{}, errors.New("either month or both from and to are required")
+	}
+	from, err := parseQueryTime(c.Query("from"), false)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	to, err := parseQueryTime(c.Query("to"), true)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return from, to, nil
+}
+
+// GetBalanceAsOf handles GET /accounts/:id/balance?as_of=
+func (h *TransactionHandler) GetBalanceAsOf(c *gin.Context) {
+	ctx := c.Request.Context()
+	logger := utils.LoggerFromContext(ctx)
+	accountID := c.Param("id")
+
+	logger = logger.With(
+		slog.String("operation", "get_balance_as_of"),
+		slog.String("account_id", accountID),
+	)
+
+	asOf := time.Now()
+	if value := c.Query("as_of"); value != "" {
+		var err error
+		if asOf, err = parseQueryTime(value, true); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid as_of",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	balance, err := h.transactionService.GetBalanceAsOf(ctx, accountID, asOf)
+	if err != nil {
+		logger.Error("Failed to get balance as of", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid balance request", "Failed to get balance")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"balance": balance,
+	})
+}
+
+// GetStatement handles GET /accounts/:id/statement. The period is given by
+// month or by from and to; format picks json (default), csv or pdf.
+func (h *TransactionHandler) GetStatement(c *gin.Context) {
+	ctx := c.Request.Context()
+	logger := utils.LoggerFromContext(ctx)
+	accountID := c.Param("id")
+
+	logger = logger.With(
+		slog.String("operation", "get_statement"),
+		slog.String("account_id", accountID),
+	)
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" && format != "pdf" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid format",
+			"details": "format must be one of json, csv or pdf",
+		})
+		return
+	}
+
+	from, to, err := statementPeriod(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid statement period",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	statement, err := h.transactionService.GetStatement(ctx, accountID, from, to)
+	if err != nil {
+		logger.Error("Failed to generate statement", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid statement request", "Failed to generate statement")
+		return
+	}
+
+	logger.Info("Statement generated",
+		slog.String("format", format),
+		slog.Int("line_count", len(statement.Lines)))
+
+	filename := fmt.Sprintf("statement_%s_%s_%s.%s", accountID,
+		statement.From.Format(dateLayout), statement.To.AddDate(0, 0, -1).Format(dateLayout), format)
+
+	switch format {
+	case "csv":
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", statementCSV(statement))
+	case "pdf":
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+		c.Data(http.StatusOK, "application/pdf", statementPDF(statement))
+	default:
+		c.JSON(http.StatusOK, gin.H{
+			"statement": statement,
+		})
+	}
+}
+
+// statementCSV writes a summary block, the totals by type and then one row
+// per line item, separated by blank rows
+func statementCSV(statement *models.Statement) []byte {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	w.WriteAll([][]string{
+		{"account_id", statement.AccountID},
+		{"owner_name", statement.OwnerName},
+		{"currency", statement.Currency},
+		{"from", statement.From.Format(time.RFC3339)},
+		{"to", statement.To.Format(time.RFC3339)},
+		{"opening_balance", statement.OpeningBalance.String()},
+		{"closing_balance", statement.ClosingBalance.String()},
+		{},
+		{"type", "count", "total"},
+	})
+	for _, total := range statement.Totals {
+		w.Write([]string{total.Type, strconv.Itoa(total.Count), total.Amount.String()})
+	}
+
+	w.Write(nil)
+	w.Write([]string{"timestamp", "transaction_id", "type", "description", "amount", "balance", "status"})
+	for _, line := range statement.Lines {
+		w.Write([]string{
+			line.Timestamp.Format(time.RFC3339),
+			line.TransactionID,
+			line.Type,
+			line.Description,
+			line.Amount.String(),
+			line.Balance.String(),
+			line.Status,
+		})
+	}
+
+	w.Flush()
+	return buf.Bytes()
+}
+
+// statementPDF lays the statement out as a plain text report
+func statementPDF(statement *models.Statement) []byte {
+	pdf := utils.NewTextPDF()
+
+	pdf.Line("ACCOUNT STATEMENT")
+	pdf.Line("")
+	pdf.Linef("Account:  %s", statement.AccountID)
+	pdf.Linef("Owner:    %s", statement.OwnerName)
+	pdf.Linef("Currency: %s", statement.Currency)
+	pdf.Linef("Period:   %s to %s", statement.From.Format(time.RFC3339), statement.To.Format(time.RFC3339))
+	pdf.Linef("Opening balance: %s", statement.OpeningBalance)
+	pdf.Linef("Closing balance: %s", statement.ClosingBalance)
+	pdf.Line("")
+
+	pdf.Linef("%-14s %6s %18s", "Type", "Count", "Total")
+	for _, total := range statement.Totals {
+		pdf.Linef("%-14s %6d %18s", total.Type, total.Count, total.Amount)
+	}
+	pdf.Line("")
+
+	pdf.Linef("%-16s %-12s %-24s %17s %17s", "Date", "Type", "Description", "Amount", "Balance")
+	for _, line := range statement.Lines {
+		description := line.Description
+		switch line.Status {
+		case "reversed":
+			description = "[reversed] " + description
+		case "partially_refunded":
+			description = "[refunded] " + description
+		}
+		if len(description) > 24 {
+			description = description[:23] + "~"
+		}
+		pdf.Linef("%-16s %-12s %-24s %17s %17s",
+			line.Timestamp.UTC().Format("2006-01-02 15:04"), line.Type, description, line.Amount, line.Balance)
+	}
+	if len(statement.Lines) == 0 {
+		pdf.Line("No transactions in this period.")
+	}
+
+	pdf.Line("")
+	pdf.Linef("Generated %s", statement.GeneratedAt.UTC().Format(time.RFC3339))
+
+	return pdf.Bytes()
+}
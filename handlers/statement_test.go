@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleStatement() *models.Statement {
+	return &models.Statement{
+		AccountID:      "acc_12345",
+		OwnerName:      "John Doe",
+		Currency:       "USD",
+		From:           time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC),
+		To:             time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC),
+		OpeningBalance: models.MustParseMoney("100.00"),
+		ClosingBalance: models.MustParseMoney("130.00"),
+		Totals: []models.StatementTotal{
+			{Type: "deposit", Count: 1, Amount: models.MustParseMoney("50.00")},
+			{Type: "withdraw", Count: 1, Amount: models.MustParseMoney("20.00")},
+		},
+		Lines: []models.StatementLine{
+			{Timestamp: time.Date(2024, 8, 3, 9, 0, 0, 0, time.UTC), TransactionID: "txn_1", Type: "deposit", Description: "Salary (August)", Amount: models.MustParseMoney("50.00"), Balance: models.MustParseMoney("150.00"), Status: "completed"},
+			{Timestamp: time.Date(2024, 8, 9, 9, 0, 0, 0, time.UTC), TransactionID: "txn_2", Type: "withdraw", Description: "ATM", Amount: models.MustParseMoney("-20.00"), Balance: models.MustParseMoney("130.00"), Status: "completed"},
+		},
+		GeneratedAt: time.Now(),
+	}
+}
+
+func TestParseQueryTime(t *testing.T) {
+	start, err := parseQueryTime("2024-08-31", false)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 8, 31, 0, 0, 0, 0, time.UTC), start)
+
+	end, err := parseQueryTime("2024-08-31", true)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC), end)
+
+	exact, err := parseQueryTime("2024-08-31T12:30:00Z", true)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 8, 31, 12, 30, 0, 0, time.UTC), exact)
+
+	_, err = parseQueryTime("31/08/2024", false)
+	assert.Error(t, err)
+}
+
+func TestGetBalanceAsOf_Success(t *testing.T) {
+	router, mockService := setupTransactionTestRouter(false)
+
+	asOf := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetBalanceAsOf", mock.Anything, "acc_12345", asOf).Return(&models.HistoricalBalance{
+		AccountID: "acc_12345",
+		Currency:  "USD",
+		AsOf:      asOf,
+		Balance:   models.MustParseMoney("130.00"),
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/accounts/acc_12345/balance?as_of=2024-08-31", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 130.0, response["balance"].(map[string]interface{})["balance"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetBalanceAsOf_InvalidTime(t *testing.T) {
+	router, mockService := setupTransactionTestRouter(false)
+
+	req, _ := http.NewRequest("GET", "/accounts/acc_12345/balance?as_of=yesterday", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "GetBalanceAsOf")
+}
+
+func TestGetStatement_JSONForMonth(t *testing.T) {
+	router, mockService := setupTransactionTestRouter(false)
+
+	statement := sampleStatement()
+	mockService.On("GetStatement", mock.Anything, "acc_12345", statement.From, statement.To).Return(statement, nil)
+
+	req, _ := http.NewRequest("GET", "/accounts/acc_12345/statement?month=2024-08", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	body := response["statement"].(map[string]interface{})
+	assert.Equal(t, 100.0, body["opening_balance"])
+	assert.Equal(t, 130.0, body["closing_balance"])
+	assert.Len(t, body["lines"], 2)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetStatement_CSV(t *testing.T) {
+	router, mockService := setupTransactionTestRouter(false)
+
+	statement := sampleStatement()
+	mockService.On("GetStatement", mock.Anything, "acc_12345", statement.From, statement.To).Return(statement, nil)
+
+	req, _ := http.NewRequest("GET", "/accounts/acc_12345/statement?from=2024-08-01&to=2024-08-31&format=csv", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="statement_acc_12345_2024-08-01_2024-08-31.csv"`, w.Header().Get("Content-Disposition"))
+
+	reader := csv.NewReader(bytes.NewReader(w.Body.Bytes()))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	require.NoError(t, err)
+	assert.Contains(t, records, []string{"opening_balance", "100.00"})
+	assert.Contains(t, records, []string{"deposit", "1", "50.00"})
+	assert.Contains(t, records, []string{"2024-08-09T09:00:00Z", "txn_2", "withdraw", "ATM", "-20.00", "130.00", "completed"})
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetStatement_PDF(t *testing.T) {
+	router, mockService := setupTransactionTestRouter(false)
+
+	statement := sampleStatement()
+	mockService.On("GetStatement", mock.Anything, "acc_12345", statement.From, statement.To).Return(statement, nil)
+
+	req, _ := http.NewRequest("GET", "/accounts/acc_12345/statement?month=2024-08&format=pdf", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.True(t, bytes.HasPrefix(w.Body.Bytes(), []byte("%PDF-1.4")))
+	assert.Contains(t, w.Body.String(), `Salary \(August\)`)
+	assert.True(t, bytes.HasSuffix(w.Body.Bytes(), []byte("%%EOF\n")))
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetStatement_InvalidRequest(t *testing.T) {
+	router, mockService := setupTransactionTestRouter(false)
+
+	for _, query := range []string{
+		"",
+		"?from=2024-08-01",
+		"?month=August",
+		"?month=2024-08&format=xml",
+	} {
+		req, _ := http.NewRequest("GET", "/accounts/acc_12345/statement"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+
+	mockService.AssertNotCalled(t, "GetStatement")
+}
@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"strings"
@@ -16,13 +18,17 @@ import (
 
 type TransactionHandler struct {
 	transactionService services.TransactionServiceInterface
+	idempotencyService services.IdempotencyServiceInterface
 	rabbitMQ           *queue.RabbitMQ
 	asyncMode          bool
 }
 
-func NewTransactionHandler(transactionService services.TransactionServiceInterface, rabbitMQ *queue.RabbitMQ, asyncMode bool) *TransactionHandler {
+// NewTransactionHandler creates the handler; idempotencyService may be nil, in
+// which case the Idempotency-Key header is ignored
+func NewTransactionHandler(transactionService services.TransactionServiceInterface, idempotencyService services.IdempotencyServiceInterface, rabbitMQ *queue.RabbitMQ, asyncMode bool) *TransactionHandler {
 	return &TransactionHandler{
 		transactionService: transactionService,
+		idempotencyService: idempotencyService,
 		rabbitMQ:           rabbitMQ,
 		asyncMode:          asyncMode,
 	}
@@ -44,27 +50,33 @@ func validateTransactionType(transactionType string) error {
 	return nil
 }
 
-// validateTransactionAmount validates the transaction amount
-func validateTransactionAmount(amount float64) error {
-	if amount <= 0 {
+// validateTransactionAmount validates the transaction amount. When the client
+// names no currency the account's precision is enforced later by the service.
+func validateTransactionAmount(amount models.Money, currency string) error {
+	if !amount.IsPositive() {
 		return errors.New("transaction amount must be greater than 0")
 	}
 
 	// Check for reasonable maximum transaction limit
-	if amount > 999999999.99 {
+	if amount > maxAmount {
 		return errors.New("transaction amount exceeds maximum allowed limit")
 	}
 
-	// Check for precision
-	// Use a small epsilon to handle floating point precision issues
-	rounded := float64(int64(amount*100+0.5)) / 100
-	if abs(amount-rounded) > 0.001 {
-		return errors.New("transaction amount cannot have more than 2 decimal places")
+	// Check for precision; a positive amount within the currency's minor
+	// unit is automatically at least one minor unit (e.g. $0.01)
+	if currency == "" {
+		if amount.DecimalPlaces() > models.MaxCurrencyPrecision {
+			return fmt.Errorf("transaction amount cannot have more than %d decimal places", models.MaxCurrencyPrecision)
+		}
+		return nil
 	}
 
-	// Check for minimum transaction amount (e.g., $0.01)
-	if amount < 0.01 {
-		return errors.New("transaction amount must be at least $0.01")
+	precision, ok := models.CurrencyPrecision(currency)
+	if !ok {
+		return fmt.Errorf("unsupported currency: %s", currency)
+	}
+	if amount.DecimalPlaces() > precision {
+		return fmt.Errorf("transaction amount cannot have more than %d decimal places for %s", precision, currency)
 	}
 
 	return nil
@@ -77,8 +89,13 @@ func validateTransactionRequest(req *models.TransactionRequest) error {
 		return err
 	}
 
-	// Validate amount
-	if err := validateTransactionAmount(req.Amount); err != nil {
+	// Validate currency and amount
+	req.Currency = models.NormalizeCurrency(req.Currency)
+	if err := validateCurrency(req.Currency); err != nil {
+		return err
+	}
+
+	if err := validateTransactionAmount(req.Amount, req.Currency); err != nil {
 		return err
 	}
 
@@ -90,6 +107,25 @@ func validateTransactionRequest(req *models.TransactionRequest) error {
 	return nil
 }
 
+// checkAccountCurrency returns the account's currency after checking that the
+// requested currency (if any) matches it and the amount fits its precision
+func checkAccountCurrency(account *models.Account, requested string, amount models.Money) (string, error) {
+	currency := account.Currency
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+
+	if requested != "" && requested != currency {
+		return "", models.Errorf(models.ErrCurrencyMismatch, "currency mismatch: account %s is denominated in %s, not %s", account.ID, currency, requested)
+	}
+
+	if err := models.ValidateAmountPrecision(amount, currency); err != nil {
+		return "", err
+	}
+
+	return currency, nil
+}
+
 // ProcessTransaction handles POST /accounts/:id/transactions
 func (h *TransactionHandler) ProcessTransaction(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -115,7 +151,7 @@ func (h *TransactionHandler) ProcessTransaction(c *gin.Context) {
 	if err := validateTransactionRequest(&req); err != nil {
 		logger.Error("Transaction validation failed",
 			slog.String("type", req.Type),
-			slog.Float64("amount", req.Amount),
+			slog.String("amount", req.Amount.String()),
 			slog.String("error", err.Error()))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Invalid transaction request",
@@ -126,11 +162,18 @@ func (h *TransactionHandler) ProcessTransaction(c *gin.Context) {
 
 	logger = logger.With(
 		slog.String("transaction_type", req.Type),
-		slog.Float64("amount", req.Amount),
+		slog.String("amount", req.Amount.String()),
 	)
 
 	logger.Info("Transaction request received and validated")
 
+	// Replay or reject retries that carry an Idempotency-Key
+	finish, proceed := beginIdempotentRequest(c, h.idempotencyService, accountID, &req)
+	if !proceed {
+		return
+	}
+	defer finish()
+
 	// If async mode is enabled and RabbitMQ is available, use queue
 	if h.asyncMode && h.rabbitMQ != nil && h.rabbitMQ.IsConnected() {
 		logger.Info("Processing transaction asynchronously")
@@ -150,30 +193,71 @@ func (h *TransactionHandler) processTransactionAsync(c *gin.Context, accountID s
 	account, err := h.transactionService.GetAccountByID(ctx, accountID)
 	if err != nil {
 		logger.Error("Account validation failed", slog.String("error", err.Error()))
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Account not found",
-		})
+		respondError(c, err, "Invalid transaction request", "Failed to validate account")
 		return
 	}
 
 	logger.Info("Account validated for async transaction",
-		slog.Float64("current_balance", account.Balance))
+		slog.String("current_balance", account.Balance.String()))
 
-	// Pre-validate withdrawal amount against current balance
-	if req.Type == "withdraw" && account.Balance < req.Amount {
+	// Check the amount against the account's currency before queueing
+	currency, err := checkAccountCurrency(account, req.Currency, req.Amount)
+	if err != nil {
+		logger.Error("Currency check failed", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid transaction request", "Failed to validate account")
+		return
+	}
+
+	// Frozen and closed accounts are turned away before queueing; the worker
+	// checks again under the row lock in case the state changes meanwhile
+	checkStatus := account.CheckCredit
+	if req.Type == "withdraw" {
+		checkStatus = account.CheckDebit
+	}
+	if err := checkStatus(); err != nil {
+		logger.Error("Account status check failed", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid transaction request", "Failed to validate account")
+		return
+	}
+
+	// Pre-validate withdrawal amount against the balance not reserved by holds
+	if req.Type == "withdraw" && account.Available() < req.Amount {
 		logger.Error("Insufficient funds detected before queueing",
-			slog.Float64("current_balance", account.Balance),
-			slog.Float64("requested_amount", req.Amount))
+			slog.String("available_balance", account.Available().String()),
+			slog.String("requested_amount", req.Amount.String()))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Insufficient funds",
-			"details": "Current balance is insufficient for this withdrawal",
+			"details": "Available balance is insufficient for this withdrawal",
 		})
 		return
 	}
 
-	// Create transaction ID
-	transactionID := models.NewTransactionID()
+	// Create transaction ID, unless a claimed Idempotency-Key reserved one
+	transactionID := req.TransactionID
+	if transactionID == "" {
+		transactionID = models.NewTransactionID()
+	}
 	logger = logger.With(slog.String("transaction_id", transactionID))
+	c.Set(transactionIDContextKey, transactionID)
+
+	// The queue message is stored in the pending record itself (transactional
+	// outbox); the outbox relay publishes it, retrying until the broker accepts it
+	outbox, err := queue.NewOutboxEntry(ctx, queue.TransactionMessage{
+		ID:        transactionID,
+		AccountID: accountID,
+		Type:      req.Type,
+		Amount:    req.Amount,
+		Currency:  currency,
+		Reference: req.Description,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		logger.Error("Failed to build outbox message", slog.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create transaction record",
+		})
+		return
+	}
 
 	// Save pending transaction to MongoDB immediately
 	pendingTransaction := &models.Transaction{
@@ -184,9 +268,12 @@ func (h *TransactionHandler) processTransactionAsync(c *gin.Context, accountID s
 		Amount:          req.Amount,
 		PreviousBalance: account.Balance,
 		NewBalance:      0, // Will be updated by worker
+		Currency:        currency,
 		Description:     req.Description,
 		Timestamp:       time.Now(),
 		Status:          "pending",
+		IdempotencyKey:  req.IdempotencyKey,
+		Outbox:          outbox,
 	}
 
 	logger.Info("Creating pending transaction record")
@@ -198,24 +285,6 @@ func (h *TransactionHandler) processTransactionAsync(c *gin.Context, accountID s
 		return
 	}
 
-	// Create queue message
-	message := queue.TransactionMessage{
-		ID:        transactionID,
-		AccountID: accountID,
-		Type:      req.Type,
-		Amount:    req.Amount,
-		Reference: req.Description,
-		CreatedAt: time.Now(),
-	}
-
-	logger.Info("Publishing transaction to queue")
-	if err := h.rabbitMQ.PublishTransaction(ctx, message); err != nil {
-		logger.Error("Failed to publish to queue, updating to failed status", slog.String("error", err.Error()))
-		h.transactionService.UpdateTransactionStatusWithError(ctx, transactionID, "failed", "Queue system unavailable")
-		h.processTransactionSync(c, accountID, req)
-		return
-	}
-
 	logger.Info("Transaction queued successfully")
 	c.JSON(http.StatusAccepted, gin.H{
 		"message":         "Transaction queued for processing",
@@ -236,30 +305,15 @@ func (h *TransactionHandler) processTransactionSync(c *gin.Context, accountID st
 	transaction, err := h.transactionService.ProcessTransaction(ctx, accountID, req)
 	if err != nil {
 		logger.Error("Synchronous transaction processing failed", slog.String("error", err.Error()))
-
-		// Provide more specific error messages for common issues
-		if strings.Contains(err.Error(), "insufficient funds") {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   "Insufficient funds",
-				"details": err.Error(),
-			})
-		} else if strings.Contains(err.Error(), "account not found") {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error":   "Account not found",
-				"details": err.Error(),
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Transaction processing failed",
-				"details": err.Error(),
-			})
-		}
+		respondError(c, err, "Invalid transaction request", "Transaction processing failed")
 		return
 	}
 
+	c.Set(transactionIDContextKey, transaction.TransactionID)
+
 	logger.Info("Transaction processed successfully",
 		slog.String("transaction_id", transaction.ID),
-		slog.Float64("new_balance", transaction.NewBalance))
+		slog.String("new_balance", transaction.NewBalance.String()))
 
 	c.JSON(http.StatusOK, gin.H{
 		"message":         "Transaction processed successfully",
@@ -268,12 +322,111 @@ func (h *TransactionHandler) processTransactionSync(c *gin.Context, accountID st
 	})
 }
 
-// GetTransactions handles GET /accounts/:id/transactions
+// transactionFilterParams are the query parameters that filter transaction
+// history; any of them, or a cursor, selects cursor pagination
+var transactionFilterParams = []string{"type", "status", "min_amount", "max_amount", "from", "to", "q"}
+
+// Values accepted by the type and status filters
+var (
+	filterTypes    = []string{"deposit", "withdraw", "transfer_in", "transfer_out"}
+	filterStatuses = []string{"pending", "completed", "failed", "partially_refunded", "reversed"}
+)
+
+// queryList collects a parameter given repeatedly and/or comma separated
+func queryList(c *gin.Context, key string) []string {
+	var values []string
+	for _, value := range c.QueryArray(key) {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+	return values
+}
+
+func checkAllowed(field string, values, allowed []string) error {
+	for _, value := range values {
+		found := false
+		for _, a := range allowed {
+			if value == a {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("invalid %s %q: must be one of %s", field, value, strings.Join(allowed, ", "))
+		}
+	}
+	return nil
+}
+
+// parseTransactionFilter reads the history filters from the query string
+func parseTransactionFilter(c *gin.Context) (*models.TransactionFilter, error) {
+	filter := &models.TransactionFilter{
+		Types:       queryList(c, "type"),
+		Statuses:    queryList(c, "status"),
+		Description: strings.TrimSpace(c.Query("q")),
+	}
+	if err := checkAllowed("type", filter.Types, filterTypes); err != nil {
+		return nil, err
+	}
+	if err := checkAllowed("status", filter.Statuses, filterStatuses); err != nil {
+		return nil, err
+	}
+
+	for key, target := range map[string]**models.Money{"min_amount": &filter.MinAmount, "max_amount": &filter.MaxAmount} {
+		if value := c.Query(key); value != "" {
+			amount, err := models.ParseMoney(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", key, err)
+			}
+			*target = &amount
+		}
+	}
+
+	var err error
+	if value := c.Query("from"); value != "" {
+		if filter.From, err = parseQueryTime(value, false); err != nil {
+			return nil, err
+		}
+	}
+	if value := c.Query("to"); value != "" {
+		if filter.To, err = parseQueryTime(value, true); err != nil {
+			return nil, err
+		}
+	}
+
+	return filter, nil
+}
+
+// usesCursorPagination reports whether the request asks for cursor
+// pagination, by sending a cursor (even an empty one) or any filter
+func usesCursorPagination(c *gin.Context) bool {
+	if _, ok := c.GetQuery("cursor"); ok {
+		return true
+	}
+	for _, key := range transactionFilterParams {
+		if _, ok := c.GetQuery(key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTransactions handles GET /accounts/:id/transactions. Plain page/limit
+// requests keep offset pagination; a cursor or any filter switches to cursor
+// pagination.
 func (h *TransactionHandler) GetTransactions(c *gin.Context) {
 	ctx := c.Request.Context()
 	logger := utils.LoggerFromContext(ctx)
 	accountID := c.Param("id")
 
+	if usesCursorPagination(c) {
+		h.listTransactions(c, accountID)
+		return
+	}
+
 	// Get pagination parameters from middleware
 	page := c.GetInt("page")
 	limit := c.GetInt("limit")
@@ -296,18 +449,7 @@ func (h *TransactionHandler) GetTransactions(c *gin.Context) {
 	transactions, total, err := h.transactionService.GetTransactionHistory(ctx, accountID, page, limit)
 	if err != nil {
 		logger.Error("Failed to get transaction history", slog.String("error", err.Error()))
-
-		if strings.Contains(err.Error(), "account not found") {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error":   "Account not found",
-				"details": err.Error(),
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to retrieve transaction history",
-				"details": err.Error(),
-			})
-		}
+		respondError(c, err, "Invalid transaction history request", "Failed to retrieve transaction history")
 		return
 	}
 
@@ -325,6 +467,62 @@ func (h *TransactionHandler) GetTransactions(c *gin.Context) {
 	})
 }
 
+// listTransactions serves a cursor-paginated, filtered page of history
+func (h *TransactionHandler) listTransactions(c *gin.Context, accountID string) {
+	ctx := c.Request.Context()
+	logger := utils.LoggerFromContext(ctx)
+
+	limit := c.GetInt("limit")
+	if limit == 0 {
+		limit = 10
+	}
+	cursor := c.Query("cursor")
+
+	logger = logger.With(
+		slog.String("operation", "list_transactions"),
+		slog.String("account_id", accountID),
+		slog.Int("limit", limit),
+	)
+
+	if _, ok := c.GetQuery("page"); ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid transaction history request",
+			"details": "page cannot be combined with cursor pagination or filters",
+		})
+		return
+	}
+
+	filter, err := parseTransactionFilter(c)
+	if err != nil {
+		logger.Error("Invalid transaction filter", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid transaction history request",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	page, err := h.transactionService.ListTransactions(ctx, accountID, filter, cursor, limit)
+	if err != nil {
+		logger.Error("Failed to list transactions", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid transaction history request", "Failed to retrieve transaction history")
+		return
+	}
+
+	logger.Info("Transaction page retrieved successfully",
+		slog.Int("returned_count", len(page.Transactions)),
+		slog.Bool("has_more", page.HasMore))
+
+	c.JSON(http.StatusOK, gin.H{
+		"transactions": page.Transactions,
+		"pagination": gin.H{
+			"limit":       limit,
+			"next_cursor": page.NextCursor,
+			"has_more":    page.HasMore,
+		},
+	})
+}
+
 // GetTransaction handles GET /transactions/:id
 func (h *TransactionHandler) GetTransaction(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -341,7 +539,7 @@ func (h *TransactionHandler) GetTransaction(c *gin.Context) {
 	transaction, err := h.transactionService.GetTransactionByID(ctx, transactionID)
 	if err != nil {
 		logger.Error("Failed to get transaction", slog.String("error", err.Error()))
-		c.JSON(http.StatusNotFound, gin.H{
+		c.JSON(errorStatus(err), gin.H{
 			"error": err.Error(),
 		})
 		return
@@ -356,6 +554,92 @@ func (h *TransactionHandler) GetTransaction(c *gin.Context) {
 	})
 }
 
+// ReverseTransaction handles POST /transactions/:id/reverse. The body is
+// optional; without an amount the whole transaction is reversed.
+func (h *TransactionHandler) ReverseTransaction(c *gin.Context) {
+	ctx := c.Request.Context()
+	logger := utils.LoggerFromContext(ctx)
+	transactionID := c.Param("id")
+
+	logger = logger.With(
+		slog.String("operation", "reverse_transaction"),
+		slog.String("transaction_id", transactionID),
+	)
+
+	var req models.ReverseTransactionRequest
+	if err := bindOptionalJSON(c, &req); err != nil {
+		logger.Error("Invalid request body", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if req.Amount.IsNegative() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid reversal request",
+			"details": "amount must be greater than 0",
+		})
+		return
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+
+	logger.Info("Reversal request received", slog.String("amount", req.Amount.String()))
+
+	reversal, err := h.transactionService.ReverseTransaction(ctx, transactionID, &req)
+	if err != nil {
+		logger.Error("Transaction reversal failed", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid reversal request", "Transaction reversal failed")
+		return
+	}
+
+	logger.Info("Transaction reversed successfully",
+		slog.String("reversal_id", reversal.TransactionID),
+		slog.String("new_balance", reversal.NewBalance.String()))
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Transaction reversed successfully",
+		"reversal": reversal,
+	})
+}
+
+// VerifyTransactionChain handles GET /accounts/:id/transactions/verify. A
+// broken chain is still a successful check: the response says where it broke.
+func (h *TransactionHandler) VerifyTransactionChain(c *gin.Context) {
+	ctx := c.Request.Context()
+	logger := utils.LoggerFromContext(ctx)
+	accountID := c.Param("id")
+
+	logger = logger.With(
+		slog.String("operation", "verify_transaction_chain"),
+		slog.String("account_id", accountID),
+	)
+
+	verification, err := h.transactionService.VerifyTransactionChain(ctx, accountID)
+	if err != nil {
+		logger.Error("Failed to verify transaction chain", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid verification request", "Failed to verify transaction chain")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"verification": verification,
+	})
+}
+
+// bindOptionalJSON binds the request body into obj, leaving it zero when the
+// body is empty
+func bindOptionalJSON(c *gin.Context, obj interface{}) error {
+	if c.Request.Body == nil || c.Request.ContentLength == 0 {
+		return nil
+	}
+	if err := c.ShouldBindJSON(obj); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
 // GetProcessingMode handles GET /processing-mode
 func (h *TransactionHandler) GetProcessingMode(c *gin.Context) {
 	ctx := c.Request.Context()
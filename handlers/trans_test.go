@@ -4,7 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
@@ -39,6 +39,14 @@ func (m *MockTransactionService) GetTransactionHistory(ctx context.Context, acco
 	return args.Get(0).([]models.Transaction), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockTransactionService) ListTransactions(ctx context.Context, accountID string, filter *models.TransactionFilter, cursor string, limit int) (*models.TransactionPage, error) {
+	args := m.Called(ctx, accountID, filter, cursor, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TransactionPage), args.Error(1)
+}
+
 func (m *MockTransactionService) GetTransactionByID(ctx context.Context, transactionID string) (*models.Transaction, error) {
 	args := m.Called(ctx, transactionID)
 	if args.Get(0) == nil {
@@ -47,6 +55,102 @@ func (m *MockTransactionService) GetTransactionByID(ctx context.Context, transac
 	return args.Get(0).(*models.Transaction), args.Error(1)
 }
 
+func (m *MockTransactionService) ReverseTransaction(ctx context.Context, transactionID string, req *models.ReverseTransactionRequest) (*models.Transaction, error) {
+	args := m.Called(ctx, transactionID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionService) GetBalanceAsOf(ctx context.Context, accountID string, asOf time.Time) (*models.HistoricalBalance, error) {
+	args := m.Called(ctx, accountID, asOf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HistoricalBalance), args.Error(1)
+}
+
+func (m *MockTransactionService) GetStatement(ctx context.Context, accountID string, from, to time.Time) (*models.Statement, error) {
+	args := m.Called(ctx, accountID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Statement), args.Error(1)
+}
+
+func (m *MockTransactionService) VerifyTransactionChain(ctx context.Context, accountID string) (*models.ChainVerification, error) {
+	args := m.Called(ctx, accountID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ChainVerification), args.Error(1)
+}
+
+func (m *MockTransactionService) PlaceHold(ctx context.Context, accountID string, req *models.CreateHoldRequest) (*models.Hold, error) {
+	args := m.Called(ctx, accountID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Hold), args.Error(1)
+}
+
+func (m *MockTransactionService) GetHold(ctx context.Context, holdID string) (*models.Hold, error) {
+	args := m.Called(ctx, holdID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Hold), args.Error(1)
+}
+
+func (m *MockTransactionService) GetAccountHolds(ctx context.Context, accountID string) ([]models.Hold, error) {
+	args := m.Called(ctx, accountID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Hold), args.Error(1)
+}
+
+func (m *MockTransactionService) CaptureHold(ctx context.Context, holdID string, req *models.CaptureHoldRequest) (*models.HoldCapture, error) {
+	args := m.Called(ctx, holdID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HoldCapture), args.Error(1)
+}
+
+func (m *MockTransactionService) ReleaseHold(ctx context.Context, holdID string) (*models.Hold, error) {
+	args := m.Called(ctx, holdID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Hold), args.Error(1)
+}
+
+func (m *MockTransactionService) GetAccountLimits(ctx context.Context, accountID string) (*models.AccountLimits, error) {
+	args := m.Called(ctx, accountID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AccountLimits), args.Error(1)
+}
+
+func (m *MockTransactionService) SetAccountLimits(ctx context.Context, accountID string, overrides *models.LimitOverrides) (*models.AccountLimits, error) {
+	args := m.Called(ctx, accountID, overrides)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AccountLimits), args.Error(1)
+}
+
+func (m *MockTransactionService) GetLimitBreaches(ctx context.Context, accountID string) ([]models.LimitBreach, error) {
+	args := m.Called(ctx, accountID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.LimitBreach), args.Error(1)
+}
+
 func (m *MockTransactionService) GetAccountByID(ctx context.Context, accountID string) (*models.Account, error) {
 	args := m.Called(ctx, accountID)
 	if args.Get(0) == nil {
@@ -78,11 +182,35 @@ func (m *MockTransactionService) UpdateTransactionStatusWithError(ctx context.Co
 	return args.Error(0)
 }
 
-func (m *MockTransactionService) CreateInitialTransaction(ctx context.Context, accountID string, initialBalance float64) error {
-	args := m.Called(ctx, accountID, initialBalance)
+func (m *MockTransactionService) ProcessTransfer(ctx context.Context, req *models.TransferRequest) (*models.Transfer, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transfer), args.Error(1)
+}
+
+func (m *MockTransactionService) GetTransfer(ctx context.Context, transferID string) (*models.Transfer, error) {
+	args := m.Called(ctx, transferID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transfer), args.Error(1)
+}
+
+func (m *MockTransactionService) CreatePendingTransfer(ctx context.Context, transfer *models.Transfer) error {
+	args := m.Called(ctx, transfer)
 	return args.Error(0)
 }
 
+func (m *MockTransactionService) ProcessTransferAsync(ctx context.Context, transferID string, req *models.TransferRequest) (*models.Transfer, error) {
+	args := m.Called(ctx, transferID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transfer), args.Error(1)
+}
+
 func setupTransactionTestRouter(asyncMode bool) (*gin.Engine, *MockTransactionService) {
 	gin.SetMode(gin.TestMode)
 
@@ -90,7 +218,7 @@ func setupTransactionTestRouter(asyncMode bool) (*gin.Engine, *MockTransactionSe
 	// Use nil for RabbitMQ in sync mode tests, create a simple mock for async tests
 	var rabbitMQ *queue.RabbitMQ = nil
 
-	handler := NewTransactionHandler(mockService, rabbitMQ, asyncMode)
+	handler := NewTransactionHandler(mockService, nil, rabbitMQ, asyncMode)
 
 	router := gin.New()
 	router.Use(func(c *gin.Context) {
@@ -119,6 +247,18 @@ func setupTransactionTestRouter(asyncMode bool) (*gin.Engine, *MockTransactionSe
 	router.POST("/accounts/:id/transactions", handler.ProcessTransaction)
 	router.GET("/accounts/:id/transactions", handler.GetTransactions)
 	router.GET("/transactions/:id", handler.GetTransaction)
+	router.POST("/transactions/:id/reverse", handler.ReverseTransaction)
+	router.GET("/accounts/:id/balance", handler.GetBalanceAsOf)
+	router.GET("/accounts/:id/statement", handler.GetStatement)
+	router.GET("/accounts/:id/transactions/verify", handler.VerifyTransactionChain)
+	router.POST("/accounts/:id/holds", handler.PlaceHold)
+	router.GET("/accounts/:id/holds", handler.GetAccountHolds)
+	router.GET("/holds/:id", handler.GetHold)
+	router.POST("/holds/:id/capture", handler.CaptureHold)
+	router.POST("/holds/:id/release", handler.ReleaseHold)
+	router.GET("/admin/accounts/:id/limits", handler.GetAccountLimits)
+	router.PUT("/admin/accounts/:id/limits", handler.SetAccountLimits)
+	router.GET("/admin/accounts/:id/limit-breaches", handler.GetLimitBreaches)
 	router.GET("/processing-mode", handler.GetProcessingMode)
 
 	return router, mockService
@@ -132,20 +272,20 @@ func TestProcessTransaction_SyncMode_Success(t *testing.T) {
 		TransactionID:   "txn_12345",
 		AccountID:       "acc_12345",
 		Type:            "deposit",
-		Amount:          500.00,
-		PreviousBalance: 1000.00,
-		NewBalance:      1500.00,
+		Amount:          models.MustParseMoney("500.00"),
+		PreviousBalance: models.MustParseMoney("1000.00"),
+		NewBalance:      models.MustParseMoney("1500.00"),
 		Status:          "completed",
 		Timestamp:       time.Now(),
 	}
 
 	mockService.On("ProcessTransaction", mock.Anything, "acc_12345", mock.MatchedBy(func(req *models.TransactionRequest) bool {
-		return req.Type == "deposit" && req.Amount == 500.00
+		return req.Type == "deposit" && req.Amount == models.MustParseMoney("500.00")
 	})).Return(expectedTransaction, nil)
 
 	requestBody := models.TransactionRequest{
 		Type:        "deposit",
-		Amount:      500.00,
+		Amount:      models.MustParseMoney("500.00"),
 		Description: "Test deposit",
 	}
 	jsonBody, _ := json.Marshal(requestBody)
@@ -192,7 +332,7 @@ func TestGetTransactions_Success(t *testing.T) {
 			ID:        "txn_1",
 			AccountID: "acc_12345",
 			Type:      "deposit",
-			Amount:    500.00,
+			Amount:    models.MustParseMoney("500.00"),
 			Status:    "completed",
 		},
 	}
@@ -215,6 +355,91 @@ func TestGetTransactions_Success(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestGetTransactions_CursorWithFilters(t *testing.T) {
+	router, mockService := setupTransactionTestRouter(false)
+
+	minAmount := models.MustParseMoney("10")
+	expectedFilter := &models.TransactionFilter{
+		Types:       []string{"deposit", "withdraw"},
+		Statuses:    []string{"completed"},
+		MinAmount:   &minAmount,
+		From:        time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC),
+		Description: "rent",
+	}
+
+	mockService.On("ListTransactions", mock.Anything, "acc_12345", expectedFilter, "abc", 5).Return(&models.TransactionPage{
+		Transactions: []models.Transaction{{ID: "txn_1", AccountID: "acc_12345"}},
+		NextCursor:   "def",
+		HasMore:      true,
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/accounts/acc_12345/transactions?cursor=abc&limit=5&type=deposit,withdraw&status=completed&min_amount=10&from=2024-08-01&to=2024-08-31&q=rent", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	pagination := response["pagination"].(map[string]interface{})
+	assert.Equal(t, "def", pagination["next_cursor"])
+	assert.Equal(t, true, pagination["has_more"])
+	assert.NotContains(t, pagination, "total")
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetTransactions_EmptyCursorStartsCursorMode(t *testing.T) {
+	router, mockService := setupTransactionTestRouter(false)
+
+	mockService.On("ListTransactions", mock.Anything, "acc_12345", &models.TransactionFilter{}, "", 10).
+		Return(&models.TransactionPage{Transactions: []models.Transaction{}}, nil)
+
+	req, _ := http.NewRequest("GET", "/accounts/acc_12345/transactions?cursor=", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetTransactions_InvalidFilters(t *testing.T) {
+	router, mockService := setupTransactionTestRouter(false)
+
+	for _, query := range []string{
+		"?type=refund",
+		"?status=settled",
+		"?min_amount=ten",
+		"?from=last-week",
+		"?cursor=abc&page=2",
+	} {
+		req, _ := http.NewRequest("GET", "/accounts/acc_12345/transactions"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+
+	mockService.AssertNotCalled(t, "ListTransactions")
+}
+
+func TestGetTransactions_InvalidCursor(t *testing.T) {
+	router, mockService := setupTransactionTestRouter(false)
+
+	mockService.On("ListTransactions", mock.Anything, "acc_12345", mock.Anything, "garbage", 10).
+		Return(nil, models.Errorf(models.ErrInvalidRequest, "invalid cursor"))
+
+	req, _ := http.NewRequest("GET", "/accounts/acc_12345/transactions?cursor=garbage", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestGetTransaction_Success(t *testing.T) {
 	router, mockService := setupTransactionTestRouter(false)
 
@@ -222,7 +447,7 @@ func TestGetTransaction_Success(t *testing.T) {
 		ID:        "txn_12345",
 		AccountID: "acc_12345",
 		Type:      "deposit",
-		Amount:    500.00,
+		Amount:    models.MustParseMoney("500.00"),
 		Status:    "completed",
 	}
 
@@ -245,7 +470,7 @@ func TestGetTransaction_Success(t *testing.T) {
 func TestGetTransaction_NotFound(t *testing.T) {
 	router, mockService := setupTransactionTestRouter(false)
 
-	mockService.On("GetTransactionByID", mock.Anything, "txn_nonexistent").Return(nil, errors.New("transaction not found"))
+	mockService.On("GetTransactionByID", mock.Anything, "txn_nonexistent").Return(nil, fmt.Errorf("failed to get transaction: %w", models.ErrTransactionNotFound))
 
 	req, _ := http.NewRequest("GET", "/transactions/txn_nonexistent", nil)
 	w := httptest.NewRecorder()
@@ -255,6 +480,73 @@ func TestGetTransaction_NotFound(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestReverseTransaction_Success(t *testing.T) {
+	router, mockService := setupTransactionTestRouter(false)
+
+	reversal := &models.Transaction{
+		ID:            "txn_reversal",
+		TransactionID: "txn_reversal",
+		AccountID:     "acc_12345",
+		Type:          "withdraw",
+		Amount:        models.MustParseMoney("25.00"),
+		Status:        "completed",
+		ReversalOf:    "txn_12345",
+	}
+
+	mockService.On("ReverseTransaction", mock.Anything, "txn_12345", &models.ReverseTransactionRequest{
+		Amount: models.MustParseMoney("25.00"),
+		Reason: "duplicate",
+	}).Return(reversal, nil)
+
+	req, _ := http.NewRequest("POST", "/transactions/txn_12345/reverse", bytes.NewBufferString(`{"amount": 25.00, "reason": " duplicate "}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "txn_12345", response["reversal"].(map[string]interface{})["reversal_of"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestReverseTransaction_EmptyBodyReversesInFull(t *testing.T) {
+	router, mockService := setupTransactionTestRouter(false)
+
+	mockService.On("ReverseTransaction", mock.Anything, "txn_12345", &models.ReverseTransactionRequest{}).
+		Return(&models.Transaction{TransactionID: "txn_reversal", ReversalOf: "txn_12345"}, nil)
+
+	req, _ := http.NewRequest("POST", "/transactions/txn_12345/reverse", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestReverseTransaction_AlreadyReversed(t *testing.T) {
+	router, mockService := setupTransactionTestRouter(false)
+
+	mockService.On("ReverseTransaction", mock.Anything, "txn_12345", mock.Anything).
+		Return(nil, models.Errorf(models.ErrAlreadyReversed, "transaction already reversed by txn_other"))
+
+	req, _ := http.NewRequest("POST", "/transactions/txn_12345/reverse", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "Transaction already reversed", response["error"])
+
+	mockService.AssertExpectations(t)
+}
+
 func TestGetProcessingMode_SyncMode(t *testing.T) {
 	router, _ := setupTransactionTestRouter(false) // Sync mode
 
@@ -272,3 +564,61 @@ func TestGetProcessingMode_SyncMode(t *testing.T) {
 	assert.Equal(t, "disconnected", response["queue_status"])
 	assert.Equal(t, false, response["async_enabled"])
 }
+
+func TestVerifyTransactionChain_Broken(t *testing.T) {
+	router, mockService := setupTransactionTestRouter(false)
+
+	mockService.On("VerifyTransactionChain", mock.Anything, "acc_12345").Return(&models.ChainVerification{
+		AccountID: "acc_12345",
+		Valid:     false,
+		Verified:  4,
+		HeadHash:  "ab12",
+		BrokenLink: &models.ChainBreak{
+			ChainSeq:      5,
+			TransactionID: "txn_5",
+			Reason:        "contents do not match the record's hash",
+		},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/accounts/acc_12345/transactions/verify", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	verification := response["verification"].(map[string]interface{})
+	assert.Equal(t, false, verification["valid"])
+	assert.Equal(t, "txn_5", verification["broken_link"].(map[string]interface{})["transaction_id"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestVerifyTransactionChain_AccountNotFound(t *testing.T) {
+	router, mockService := setupTransactionTestRouter(false)
+
+	mockService.On("VerifyTransactionChain", mock.Anything, "acc_missing").
+		Return(nil, fmt.Errorf("failed to get account: %w", models.ErrAccountNotFound))
+
+	req, _ := http.NewRequest("GET", "/accounts/acc_missing/transactions/verify", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCheckAccountCurrency(t *testing.T) {
+	account := &models.Account{ID: "acc_12345", Currency: "EUR"}
+
+	currency, err := checkAccountCurrency(account, "", models.MustParseMoney("10.00"))
+	assert.NoError(t, err)
+	assert.Equal(t, "EUR", currency)
+
+	// Reported like the service's own check rather than as a server error
+	_, err = checkAccountCurrency(account, "USD", models.MustParseMoney("10.00"))
+	assert.ErrorIs(t, err, models.ErrCurrencyMismatch)
+	assert.Equal(t, http.StatusBadRequest, errorStatus(err))
+}
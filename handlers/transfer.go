@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/audit"
+	"github.com/appy29/banking-ledger-service/auth"
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/queue"
+	"github.com/appy29/banking-ledger-service/services"
+	"github.com/appy29/banking-ledger-service/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type TransferHandler struct {
+	transactionService services.TransactionServiceInterface
+	accessService      services.AccessServiceInterface // Nil skips the check on the source account
+	rabbitMQ           *queue.RabbitMQ
+	asyncMode          bool
+}
+
+func NewTransferHandler(transactionService services.TransactionServiceInterface, accessService services.AccessServiceInterface, rabbitMQ *queue.RabbitMQ, asyncMode bool) *TransferHandler {
+	return &TransferHandler{
+		transactionService: transactionService,
+		accessService:      accessService,
+		rabbitMQ:           rabbitMQ,
+		asyncMode:          asyncMode,
+	}
+}
+
+// validateTransferRequest validates the complete transfer request
+func validateTransferRequest(req *models.TransferRequest) error {
+	req.FromAccountID = strings.TrimSpace(req.FromAccountID)
+	req.ToAccountID = strings.TrimSpace(req.ToAccountID)
+
+	if req.FromAccountID == "" || req.ToAccountID == "" {
+		return errors.New("from_account_id and to_account_id are required")
+	}
+
+	if !strings.HasPrefix(req.FromAccountID, "acc_") || !strings.HasPrefix(req.ToAccountID, "acc_") {
+		return errors.New("invalid account ID format")
+	}
+
+	if req.FromAccountID == req.ToAccountID {
+		return errors.New("cannot transfer to the same account")
+	}
+
+	req.Currency = models.NormalizeCurrency(req.Currency)
+	if err := validateCurrency(req.Currency); err != nil {
+		return err
+	}
+
+	if err := validateTransactionAmount(req.Amount, req.Currency); err != nil {
+		return err
+	}
+
+	req.Description = strings.TrimSpace(req.Description)
+
+	return nil
+}
+
+// ProcessTransfer handles POST /transfers
+func (h *TransferHandler) ProcessTransfer(c *gin.Context) {
+	ctx := c.Request.Context()
+	logger := utils.LoggerFromContext(ctx)
+
+	logger = logger.With(slog.String("operation", "process_transfer"))
+
+	var req models.TransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := validateTransferRequest(&req); err != nil {
+		logger.Error("Transfer validation failed",
+			slog.String("from_account_id", req.FromAccountID),
+			slog.String("to_account_id", req.ToAccountID),
+			slog.String("amount", req.Amount.String()),
+			slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid transfer request",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	logger = logger.With(
+		slog.String("from_account_id", req.FromAccountID),
+		slog.String("to_account_id", req.ToAccountID),
+		slog.String("amount", req.Amount.String()),
+	)
+
+	logger.Info("Transfer request received and validated")
+	audit.FromContext(ctx).Account(req.FromAccountID, req.ToAccountID)
+
+	// Moving money out of an account takes write access to it; the
+	// destination only has to exist
+	if h.accessService != nil {
+		if err := h.accessService.CheckAccount(ctx, req.FromAccountID, auth.AccessWrite); err != nil {
+			logger.Warn("Transfer not authorized", slog.String("error", err.Error()))
+			respondError(c, err, "Invalid transfer request", "Failed to authorize transfer")
+			return
+		}
+	}
+
+	if h.asyncMode && h.rabbitMQ != nil && h.rabbitMQ.IsConnected() {
+		logger.Info("Processing transfer asynchronously")
+		h.processTransferAsync(c, &req)
+	} else {
+		logger.Info("Processing transfer synchronously")
+		h.processTransferSync(c, &req)
+	}
+}
+
+// processTransferAsync creates both pending legs and queues the transfer
+func (h *TransferHandler) processTransferAsync(c *gin.Context, req *models.TransferRequest) {
+	ctx := c.Request.Context()
+	logger := utils.LoggerFromContext(ctx)
+
+	fromAccount, err := h.transactionService.GetAccountByID(ctx, req.FromAccountID)
+	if err != nil {
+		logger.Error("Source account validation failed", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid transfer request", "Failed to validate source account")
+		return
+	}
+
+	toAccount, err := h.transactionService.GetAccountByID(ctx, req.ToAccountID)
+	if err != nil {
+		logger.Error("Destination account validation failed", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid transfer request", "Failed to validate destination account")
+		return
+	}
+
+	// Both accounts must share the transfer's currency
+	currency, err := checkAccountCurrency(fromAccount, req.Currency, req.Amount)
+	if err == nil {
+		currency, err = checkAccountCurrency(toAccount, currency, req.Amount)
+	}
+	if err != nil {
+		logger.Error("Currency check failed", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid transfer request", "Failed to validate source account")
+		return
+	}
+	req.Currency = currency
+
+	// As for single transactions, the worker repeats this under the row lock
+	err = fromAccount.CheckDebit()
+	if err == nil {
+		err = toAccount.CheckCredit()
+	}
+	if err != nil {
+		logger.Error("Account status check failed", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid transfer request", "Failed to validate accounts")
+		return
+	}
+
+	if fromAccount.Available() < req.Amount {
+		logger.Error("Insufficient funds detected before queueing",
+			slog.String("available_balance", fromAccount.Available().String()),
+			slog.String("requested_amount", req.Amount.String()))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Insufficient funds",
+			"details": "Source account's available balance is insufficient for this transfer",
+		})
+		return
+	}
+
+	transfer := services.NewPendingTransfer(req)
+	logger = logger.With(slog.String("transfer_id", transfer.TransferID))
+
+	// The queue message rides on the credit leg, which is inserted last, so it
+	// only exists once both pending legs do; the outbox relay publishes it
+	outbox, err := queue.NewOutboxEntry(ctx, queue.TransactionMessage{
+		ID:          transfer.TransferID,
+		AccountID:   req.FromAccountID,
+		ToAccountID: req.ToAccountID,
+		Type:        "transfer",
+		Amount:      req.Amount,
+		Currency:    currency,
+		Reference:   req.Description,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		logger.Error("Failed to build outbox message", slog.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create transfer record",
+		})
+		return
+	}
+	transfer.Credit.Outbox = outbox
+
+	logger.Info("Creating pending transfer records")
+	if err := h.transactionService.CreatePendingTransfer(ctx, transfer); err != nil {
+		logger.Error("Failed to create pending transfer", slog.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create transfer record",
+		})
+		return
+	}
+
+	logger.Info("Transfer queued successfully")
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":         "Transfer queued for processing",
+		"transfer_id":     transfer.TransferID,
+		"status":          "pending",
+		"from_account_id": req.FromAccountID,
+		"to_account_id":   req.ToAccountID,
+		"processing_mode": "async",
+	})
+}
+
+// processTransferSync processes the transfer immediately
+func (h *TransferHandler) processTransferSync(c *gin.Context, req *models.TransferRequest) {
+	ctx := c.Request.Context()
+	logger := utils.LoggerFromContext(ctx)
+
+	transfer, err := h.transactionService.ProcessTransfer(ctx, req)
+	if err != nil {
+		logger.Error("Synchronous transfer processing failed", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid transfer request", "Transfer processing failed")
+		return
+	}
+
+	logger.Info("Transfer processed successfully", slog.String("transfer_id", transfer.TransferID))
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":         "Transfer processed successfully",
+		"transfer":        transfer,
+		"processing_mode": "sync",
+	})
+}
+
+// GetTransfer handles GET /transfers/:id
+func (h *TransferHandler) GetTransfer(c *gin.Context) {
+	ctx := c.Request.Context()
+	logger := utils.LoggerFromContext(ctx)
+	transferID := c.Param("id")
+
+	logger = logger.With(
+		slog.String("operation", "get_transfer"),
+		slog.String("transfer_id", transferID),
+	)
+
+	logger.Info("Getting transfer")
+
+	transfer, err := h.transactionService.GetTransfer(ctx, transferID)
+	if err != nil {
+		logger.Error("Failed to get transfer", slog.String("error", err.Error()))
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Transfer retrieved successfully", slog.String("status", transfer.Status))
+
+	c.JSON(http.StatusOK, gin.H{
+		"transfer": transfer,
+	})
+}
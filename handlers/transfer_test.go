@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupTransferTestRouter() (*gin.Engine, *MockTransactionService) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := &MockTransactionService{}
+	handler := NewTransferHandler(mockService, nil, nil, false)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+		ctx := utils.WithLogger(c.Request.Context(), logger)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	})
+
+	router.POST("/transfers", handler.ProcessTransfer)
+	router.GET("/transfers/:id", handler.GetTransfer)
+
+	return router, mockService
+}
+
+func TestProcessTransfer_SyncMode_Success(t *testing.T) {
+	router, mockService := setupTransferTestRouter()
+
+	expectedTransfer := &models.Transfer{
+		TransferID:    "trf_12345",
+		FromAccountID: "acc_from",
+		ToAccountID:   "acc_to",
+		Amount:        models.MustParseMoney("150.00"),
+		Status:        "completed",
+		Debit:         &models.Transaction{TransactionID: "txn_debit", Type: "transfer_out", TransferID: "trf_12345"},
+		Credit:        &models.Transaction{TransactionID: "txn_credit", Type: "transfer_in", TransferID: "trf_12345"},
+	}
+
+	mockService.On("ProcessTransfer", mock.Anything, mock.MatchedBy(func(req *models.TransferRequest) bool {
+		return req.FromAccountID == "acc_from" && req.ToAccountID == "acc_to" && req.Amount == models.MustParseMoney("150.00")
+	})).Return(expectedTransfer, nil)
+
+	jsonBody, _ := json.Marshal(models.TransferRequest{
+		FromAccountID: "acc_from",
+		ToAccountID:   "acc_to",
+		Amount:        models.MustParseMoney("150.00"),
+		Description:   "Rent share",
+	})
+
+	req, _ := http.NewRequest("POST", "/transfers", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "Transfer processed successfully", response["message"])
+	assert.Equal(t, "sync", response["processing_mode"])
+	assert.Contains(t, response, "transfer")
+
+	mockService.AssertExpectations(t)
+}
+
+func TestProcessTransfer_ValidationErrors(t *testing.T) {
+	router, _ := setupTransferTestRouter()
+
+	testCases := []struct {
+		name string
+		body models.TransferRequest
+	}{
+		{"missing source", models.TransferRequest{ToAccountID: "acc_to", Amount: models.MustParseMoney("10.00")}},
+		{"invalid account format", models.TransferRequest{FromAccountID: "bad", ToAccountID: "acc_to", Amount: models.MustParseMoney("10.00")}},
+		{"same account", models.TransferRequest{FromAccountID: "acc_same", ToAccountID: "acc_same", Amount: models.MustParseMoney("10.00")}},
+		{"zero amount", models.TransferRequest{FromAccountID: "acc_from", ToAccountID: "acc_to", Amount: 0}},
+		{"too many decimals", models.TransferRequest{FromAccountID: "acc_from", ToAccountID: "acc_to", Amount: models.MustParseMoney("10.123"), Currency: "USD"}},
+		{"unsupported currency", models.TransferRequest{FromAccountID: "acc_from", ToAccountID: "acc_to", Amount: models.MustParseMoney("10.00"), Currency: "XYZ"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			jsonBody, _ := json.Marshal(tc.body)
+			req, _ := http.NewRequest("POST", "/transfers", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestProcessTransfer_InsufficientFunds(t *testing.T) {
+	router, mockService := setupTransferTestRouter()
+
+	mockService.On("ProcessTransfer", mock.Anything, mock.Anything).
+		Return(nil, fmt.Errorf("failed to process transfer: %w", &models.InsufficientFundsError{AccountID: "acc_from", Balance: models.MustParseMoney("10.00"), Requested: models.MustParseMoney("150.00")}))
+
+	jsonBody, _ := json.Marshal(models.TransferRequest{
+		FromAccountID: "acc_from",
+		ToAccountID:   "acc_to",
+		Amount:        models.MustParseMoney("150.00"),
+	})
+
+	req, _ := http.NewRequest("POST", "/transfers", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "Insufficient funds", response["error"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestProcessTransfer_FrozenSourceAccount(t *testing.T) {
+	router, mockService := setupTransferTestRouter()
+
+	mockService.On("ProcessTransfer", mock.Anything, mock.Anything).
+		Return(nil, fmt.Errorf("failed to process transfer: %w", models.Errorf(models.ErrAccountFrozen, "account acc_from is frozen")))
+
+	jsonBody, _ := json.Marshal(models.TransferRequest{
+		FromAccountID: "acc_from",
+		ToAccountID:   "acc_to",
+		Amount:        models.MustParseMoney("25.00"),
+	})
+
+	req, _ := http.NewRequest("POST", "/transfers", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "Account is frozen", response["error"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetTransfer_NotFound(t *testing.T) {
+	router, mockService := setupTransferTestRouter()
+
+	mockService.On("GetTransfer", mock.Anything, "trf_missing").Return(nil, fmt.Errorf("failed to get transfer: %w", models.ErrTransferNotFound))
+
+	req, _ := http.NewRequest("GET", "/transfers/trf_missing", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestProcessTransfer_CurrencyMismatch(t *testing.T) {
+	router, mockService := setupTransferTestRouter()
+
+	mockService.On("ProcessTransfer", mock.Anything, mock.Anything).
+		Return(nil, fmt.Errorf("failed to process transfer: %w", models.Errorf(models.ErrCurrencyMismatch, "currency mismatch: account acc_to is denominated in EUR, not USD")))
+
+	jsonBody, _ := json.Marshal(models.TransferRequest{
+		FromAccountID: "acc_from",
+		ToAccountID:   "acc_to",
+		Amount:        models.MustParseMoney("25.00"),
+	})
+
+	req, _ := http.NewRequest("POST", "/transfers", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "Invalid transfer request", response["error"])
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/services"
+	"github.com/appy29/banking-ledger-service/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookService services.WebhookServiceInterface
+}
+
+func NewWebhookHandler(webhookService services.WebhookServiceInterface) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// CreateAccountWebhook handles POST /accounts/:id/webhooks
+func (h *WebhookHandler) CreateAccountWebhook(c *gin.Context) {
+	h.createWebhook(c, c.Param("id"))
+}
+
+// CreateGlobalWebhook handles POST /admin/webhooks. A global subscription
+// hears about every account.
+func (h *WebhookHandler) CreateGlobalWebhook(c *gin.Context) {
+	h.createWebhook(c, "")
+}
+
+func (h *WebhookHandler) createWebhook(c *gin.Context, accountID string) {
+	ctx := c.Request.Context()
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("operation", "create_webhook"),
+		slog.String("account_id", accountID))
+
+	var req models.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(ctx, accountID, &req)
+	if err != nil {
+		logger.Error("Failed to create webhook", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid webhook request", "Failed to create webhook")
+		return
+	}
+
+	logger.Info("Webhook created", slog.String("webhook_id", webhook.ID))
+
+	// The secret is only ever shown here
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Webhook created successfully",
+		"webhook": webhook,
+		"secret":  webhook.Secret,
+	})
+}
+
+// GetAccountWebhooks handles GET /accounts/:id/webhooks
+func (h *WebhookHandler) GetAccountWebhooks(c *gin.Context) {
+	accountID := c.Param("id")
+	h.listWebhooks(c, accountID, gin.H{"account_id": accountID})
+}
+
+// GetGlobalWebhooks handles GET /admin/webhooks
+func (h *WebhookHandler) GetGlobalWebhooks(c *gin.Context) {
+	h.listWebhooks(c, "", gin.H{})
+}
+
+func (h *WebhookHandler) listWebhooks(c *gin.Context, accountID string, response gin.H) {
+	ctx := c.Request.Context()
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("operation", "get_webhooks"),
+		slog.String("account_id", accountID))
+
+	webhooks, err := h.webhookService.GetWebhooks(ctx, accountID)
+	if err != nil {
+		logger.Error("Failed to get webhooks", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid request", "Failed to get webhooks")
+		return
+	}
+
+	response["webhooks"] = webhooks
+	c.JSON(http.StatusOK, response)
+}
+
+// GetWebhook handles GET /webhooks/:id
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	ctx := c.Request.Context()
+	webhookID := c.Param("id")
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("operation", "get_webhook"),
+		slog.String("webhook_id", webhookID))
+
+	webhook, err := h.webhookService.GetWebhook(ctx, webhookID)
+	if err != nil {
+		logger.Error("Failed to get webhook", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid request", "Failed to get webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhook": webhook,
+	})
+}
+
+// DeleteWebhook handles DELETE /webhooks/:id
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	ctx := c.Request.Context()
+	webhookID := c.Param("id")
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("operation", "delete_webhook"),
+		slog.String("webhook_id", webhookID))
+
+	if err := h.webhookService.DeleteWebhook(ctx, webhookID); err != nil {
+		logger.Error("Failed to delete webhook", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid request", "Failed to delete webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Webhook deleted",
+		"webhook_id": webhookID,
+	})
+}
+
+// ListDeliveries handles GET /webhooks/:id/deliveries
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	ctx := c.Request.Context()
+	webhookID := c.Param("id")
+
+	// Get pagination parameters from middleware
+	page := c.GetInt("page")
+	limit := c.GetInt("limit")
+	if page == 0 {
+		page = 1
+	}
+	if limit == 0 {
+		limit = 10
+	}
+	status := c.Query("status")
+
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("operation", "list_webhook_deliveries"),
+		slog.String("webhook_id", webhookID),
+		slog.String("status", status),
+		slog.Int("page", page),
+		slog.Int("limit", limit))
+
+	deliveries, total, err := h.webhookService.ListDeliveries(ctx, webhookID, status, page, limit)
+	if err != nil {
+		logger.Error("Failed to list webhook deliveries", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid request", "Failed to retrieve webhook deliveries")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhook_id": webhookID,
+		"deliveries": deliveries,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	})
+}
+
+// GetDelivery handles GET /webhooks/:id/deliveries/:delivery_id. The delivery
+// comes with the log of its attempts.
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	ctx := c.Request.Context()
+	webhookID := c.Param("id")
+	deliveryID := c.Param("delivery_id")
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("operation", "get_webhook_delivery"),
+		slog.String("webhook_id", webhookID),
+		slog.String("delivery_id", deliveryID))
+
+	delivery, err := h.webhookService.GetDelivery(ctx, webhookID, deliveryID)
+	if err != nil {
+		logger.Error("Failed to get webhook delivery", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid request", "Failed to get webhook delivery")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"delivery": delivery,
+	})
+}
+
+// RedeliverDelivery handles POST /webhooks/:id/deliveries/:delivery_id/redeliver
+func (h *WebhookHandler) RedeliverDelivery(c *gin.Context) {
+	ctx := c.Request.Context()
+	webhookID := c.Param("id")
+	deliveryID := c.Param("delivery_id")
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("operation", "redeliver_webhook"),
+		slog.String("webhook_id", webhookID),
+		slog.String("delivery_id", deliveryID))
+
+	delivery, err := h.webhookService.Redeliver(ctx, webhookID, deliveryID)
+	if err != nil {
+		logger.Error("Failed to redeliver webhook", slog.String("error", err.Error()))
+		respondError(c, err, "Invalid request", "Failed to redeliver webhook")
+		return
+	}
+
+	logger.Info("Webhook redelivery queued", slog.String("redelivery_id", delivery.ID))
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":  "Webhook redelivery queued",
+		"delivery": delivery,
+	})
+}
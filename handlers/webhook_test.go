@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWebhookService for testing
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) CreateWebhook(ctx context.Context, accountID string, req *models.CreateWebhookRequest) (*models.WebhookSubscription, error) {
+	args := m.Called(ctx, accountID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookService) GetWebhook(ctx context.Context, webhookID string) (*models.WebhookSubscription, error) {
+	args := m.Called(ctx, webhookID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookService) GetWebhooks(ctx context.Context, accountID string) ([]models.WebhookSubscription, error) {
+	args := m.Called(ctx, accountID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookService) DeleteWebhook(ctx context.Context, webhookID string) error {
+	return m.Called(ctx, webhookID).Error(0)
+}
+
+func (m *MockWebhookService) ListDeliveries(ctx context.Context, webhookID, status string, page, limit int) ([]models.WebhookDelivery, int64, error) {
+	args := m.Called(ctx, webhookID, status, page, limit)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]models.WebhookDelivery), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockWebhookService) GetDelivery(ctx context.Context, webhookID, deliveryID string) (*models.WebhookDelivery, error) {
+	args := m.Called(ctx, webhookID, deliveryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookService) Redeliver(ctx context.Context, webhookID, deliveryID string) (*models.WebhookDelivery, error) {
+	args := m.Called(ctx, webhookID, deliveryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookDelivery), args.Error(1)
+}
+
+func setupWebhookTestRouter() (*gin.Engine, *MockWebhookService) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := &MockWebhookService{}
+	handler := NewWebhookHandler(mockService)
+
+	router := gin.New()
+	router.POST("/accounts/:id/webhooks", handler.CreateAccountWebhook)
+	router.GET("/accounts/:id/webhooks", handler.GetAccountWebhooks)
+	router.POST("/admin/webhooks", handler.CreateGlobalWebhook)
+	router.GET("/admin/webhooks", handler.GetGlobalWebhooks)
+	router.GET("/webhooks/:id", handler.GetWebhook)
+	router.DELETE("/webhooks/:id", handler.DeleteWebhook)
+	router.GET("/webhooks/:id/deliveries", handler.ListDeliveries)
+	router.GET("/webhooks/:id/deliveries/:delivery_id", handler.GetDelivery)
+	router.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", handler.RedeliverDelivery)
+
+	return router, mockService
+}
+
+func TestCreateAccountWebhook_Success(t *testing.T) {
+	router, mockService := setupWebhookTestRouter()
+
+	threshold := models.MustParseMoney("100.00")
+	mockService.On("CreateWebhook", mock.Anything, "acc_12345", &models.CreateWebhookRequest{
+		URL:                 "https://example.com/hooks",
+		Events:              []string{models.EventTransactionCompleted, models.EventBalanceLow},
+		LowBalanceThreshold: &threshold,
+	}).Return(&models.WebhookSubscription{
+		ID:                  "whk_12345",
+		AccountID:           "acc_12345",
+		URL:                 "https://example.com/hooks",
+		Events:              []string{models.EventTransactionCompleted, models.EventBalanceLow},
+		Secret:              "whsec_abc",
+		LowBalanceThreshold: &threshold,
+	}, nil)
+
+	body := `{"url": "https://example.com/hooks", "events": ["transaction.completed", "balance.low"], "low_balance_threshold": 100.00}`
+	req, _ := http.NewRequest("POST", "/accounts/acc_12345/webhooks", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "whsec_abc", response["secret"])
+	webhook := response["webhook"].(map[string]interface{})
+	assert.Equal(t, "whk_12345", webhook["id"])
+	assert.NotContains(t, webhook, "secret")
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateGlobalWebhook_Invalid(t *testing.T) {
+	router, mockService := setupWebhookTestRouter()
+
+	mockService.On("CreateWebhook", mock.Anything, "", mock.Anything).
+		Return(nil, models.Errorf(models.ErrInvalidWebhook, "unknown event %q", "account.deleted"))
+
+	req, _ := http.NewRequest("POST", "/admin/webhooks", bytes.NewBufferString(`{"url": "https://example.com", "events": ["account.deleted"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetWebhook_NotFound(t *testing.T) {
+	router, mockService := setupWebhookTestRouter()
+
+	mockService.On("GetWebhook", mock.Anything, "whk_missing").Return(nil, models.ErrWebhookNotFound)
+
+	req, _ := http.NewRequest("GET", "/webhooks/whk_missing", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "Webhook not found", response["error"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestListDeliveries_Success(t *testing.T) {
+	router, mockService := setupWebhookTestRouter()
+
+	mockService.On("ListDeliveries", mock.Anything, "whk_12345", models.WebhookDeliveryFailed, 1, 10).
+		Return([]models.WebhookDelivery{{ID: "whd_1", SubscriptionID: "whk_12345", Status: models.WebhookDeliveryFailed, Attempts: 9}}, int64(1), nil)
+
+	req, _ := http.NewRequest("GET", "/webhooks/whk_12345/deliveries?status=failed", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response["deliveries"], 1)
+	assert.Equal(t, float64(1), response["pagination"].(map[string]interface{})["total"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestRedeliverDelivery_Success(t *testing.T) {
+	router, mockService := setupWebhookTestRouter()
+
+	mockService.On("Redeliver", mock.Anything, "whk_12345", "whd_1").
+		Return(&models.WebhookDelivery{ID: "whd_2", SubscriptionID: "whk_12345", Status: models.WebhookDeliveryPending, RedeliveryOf: "whd_1"}, nil)
+
+	req, _ := http.NewRequest("POST", "/webhooks/whk_12345/deliveries/whd_1/redeliver", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	delivery := response["delivery"].(map[string]interface{})
+	assert.Equal(t, "whd_1", delivery["redelivery_of"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestRedeliverDelivery_StillPending(t *testing.T) {
+	router, mockService := setupWebhookTestRouter()
+
+	mockService.On("Redeliver", mock.Anything, "whk_12345", "whd_1").
+		Return(nil, models.Errorf(models.ErrWebhookDeliveryPending, "webhook delivery whd_1 is still pending"))
+
+	req, _ := http.NewRequest("POST", "/webhooks/whk_12345/deliveries/whd_1/redeliver", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
@@ -52,14 +52,14 @@ func TestDatabaseIntegration(t *testing.T) {
 		// Create account
 		req := &models.CreateAccountRequest{
 			OwnerName:      "Integration Test User",
-			InitialBalance: 1000.00,
+			InitialBalance: models.MustParseMoney("1000.00"),
 		}
 
 		account, err := accountService.CreateAccount(ctx, req)
 		require.NoError(t, err)
 		require.NotNil(t, account)
 		assert.Equal(t, "Integration Test User", account.OwnerName)
-		assert.Equal(t, 1000.00, account.Balance)
+		assert.Equal(t, models.MustParseMoney("1000.00"), account.Balance)
 
 		// Retrieve account
 		retrievedAccount, err := accountService.GetAccountByID(ctx, account.ID)
@@ -71,14 +71,14 @@ func TestDatabaseIntegration(t *testing.T) {
 		// Get balance
 		balance, err := accountService.GetAccountBalance(ctx, account.ID)
 		require.NoError(t, err)
-		assert.Equal(t, 1000.00, balance)
+		assert.Equal(t, models.MustParseMoney("1000.00"), balance)
 	})
 
 	t.Run("Transaction Processing with Database Persistence", func(t *testing.T) {
 		// Create test account
 		accountReq := &models.CreateAccountRequest{
 			OwnerName:      "Transaction Test User",
-			InitialBalance: 500.00,
+			InitialBalance: models.MustParseMoney("500.00"),
 		}
 		account, err := accountService.CreateAccount(ctx, accountReq)
 		require.NoError(t, err)
@@ -86,22 +86,22 @@ func TestDatabaseIntegration(t *testing.T) {
 		// Test deposit
 		depositReq := &models.TransactionRequest{
 			Type:        "deposit",
-			Amount:      250.00,
+			Amount:      models.MustParseMoney("250.00"),
 			Description: "Integration test deposit",
 		}
 
 		transaction, err := transactionService.ProcessTransaction(ctx, account.ID, depositReq)
 		require.NoError(t, err)
 		assert.Equal(t, "deposit", transaction.Type)
-		assert.Equal(t, 250.00, transaction.Amount)
-		assert.Equal(t, 500.00, transaction.PreviousBalance)
-		assert.Equal(t, 750.00, transaction.NewBalance)
+		assert.Equal(t, models.MustParseMoney("250.00"), transaction.Amount)
+		assert.Equal(t, models.MustParseMoney("500.00"), transaction.PreviousBalance)
+		assert.Equal(t, models.MustParseMoney("750.00"), transaction.NewBalance)
 		assert.Equal(t, "completed", transaction.Status)
 
 		// Verify account balance was updated in PostgreSQL
 		updatedBalance, err := accountService.GetAccountBalance(ctx, account.ID)
 		require.NoError(t, err)
-		assert.Equal(t, 750.00, updatedBalance)
+		assert.Equal(t, models.MustParseMoney("750.00"), updatedBalance)
 
 		// Verify transaction was saved in MongoDB
 		savedTransaction, err := transactionService.GetTransactionByID(ctx, transaction.TransactionID)
@@ -112,26 +112,26 @@ func TestDatabaseIntegration(t *testing.T) {
 		// Test withdrawal
 		withdrawReq := &models.TransactionRequest{
 			Type:        "withdraw",
-			Amount:      200.00,
+			Amount:      models.MustParseMoney("200.00"),
 			Description: "Integration test withdrawal",
 		}
 
 		withdrawTransaction, err := transactionService.ProcessTransaction(ctx, account.ID, withdrawReq)
 		require.NoError(t, err)
-		assert.Equal(t, 750.00, withdrawTransaction.PreviousBalance)
-		assert.Equal(t, 550.00, withdrawTransaction.NewBalance)
+		assert.Equal(t, models.MustParseMoney("750.00"), withdrawTransaction.PreviousBalance)
+		assert.Equal(t, models.MustParseMoney("550.00"), withdrawTransaction.NewBalance)
 
 		// Verify final balance
 		finalBalance, err := accountService.GetAccountBalance(ctx, account.ID)
 		require.NoError(t, err)
-		assert.Equal(t, 550.00, finalBalance)
+		assert.Equal(t, models.MustParseMoney("550.00"), finalBalance)
 	})
 
 	t.Run("Transaction History with Pagination", func(t *testing.T) {
 		// Create account for history test
 		accountReq := &models.CreateAccountRequest{
 			OwnerName:      "History Test User",
-			InitialBalance: 300.00,
+			InitialBalance: models.MustParseMoney("300.00"),
 		}
 		account, err := accountService.CreateAccount(ctx, accountReq)
 		require.NoError(t, err)
@@ -140,7 +140,7 @@ func TestDatabaseIntegration(t *testing.T) {
 		for i := 0; i < 5; i++ {
 			req := &models.TransactionRequest{
 				Type:        "deposit",
-				Amount:      float64(10 * (i + 1)),
+				Amount:      models.NewMoneyFromMinorUnits(int64(10*(i+1)), 0),
 				Description: "Test transaction",
 			}
 			_, err := transactionService.ProcessTransaction(ctx, account.ID, req)
@@ -162,7 +162,7 @@ func TestDatabaseIntegration(t *testing.T) {
 		// Create account with limited funds
 		accountReq := &models.CreateAccountRequest{
 			OwnerName:      "Insufficient Funds Test",
-			InitialBalance: 100.00,
+			InitialBalance: models.MustParseMoney("100.00"),
 		}
 		account, err := accountService.CreateAccount(ctx, accountReq)
 		require.NoError(t, err)
@@ -170,7 +170,7 @@ func TestDatabaseIntegration(t *testing.T) {
 		// Try to withdraw more than available
 		withdrawReq := &models.TransactionRequest{
 			Type:        "withdraw",
-			Amount:      150.00,
+			Amount:      models.MustParseMoney("150.00"),
 			Description: "Insufficient funds test",
 		}
 
@@ -182,21 +182,21 @@ func TestDatabaseIntegration(t *testing.T) {
 		// Verify balance unchanged
 		balance, err := accountService.GetAccountBalance(ctx, account.ID)
 		require.NoError(t, err)
-		assert.Equal(t, 100.00, balance)
+		assert.Equal(t, models.MustParseMoney("100.00"), balance)
 	})
 
 	t.Run("Concurrent Transaction Processing", func(t *testing.T) {
 		// Create account for concurrency test
 		accountReq := &models.CreateAccountRequest{
 			OwnerName:      "Concurrency Test User",
-			InitialBalance: 1000.00,
+			InitialBalance: models.MustParseMoney("1000.00"),
 		}
 		account, err := accountService.CreateAccount(ctx, accountReq)
 		require.NoError(t, err)
 
 		// Run concurrent deposits
 		numGoroutines := 10
-		depositAmount := 50.00
+		depositAmount := models.MustParseMoney("50.00")
 		done := make(chan bool, numGoroutines)
 		errors := make(chan error, numGoroutines)
 
@@ -230,7 +230,7 @@ func TestDatabaseIntegration(t *testing.T) {
 		// Verify final balance
 		finalBalance, err := accountService.GetAccountBalance(ctx, account.ID)
 		require.NoError(t, err)
-		expectedBalance := 1000.00 + (float64(numGoroutines) * depositAmount)
+		expectedBalance := models.MustParseMoney("1000.00") + models.Money(numGoroutines)*depositAmount
 		assert.Equal(t, expectedBalance, finalBalance)
 
 		// Verify all transactions were recorded
@@ -262,7 +262,7 @@ func TestAsyncTransactionProcessing(t *testing.T) {
 		// Create test account
 		accountReq := &models.CreateAccountRequest{
 			OwnerName:      "Async Test User",
-			InitialBalance: 600.00,
+			InitialBalance: models.MustParseMoney("600.00"),
 		}
 		account, err := accountService.CreateAccount(ctx, accountReq)
 		require.NoError(t, err)
@@ -274,7 +274,7 @@ func TestAsyncTransactionProcessing(t *testing.T) {
 			TransactionID:   transactionID,
 			AccountID:       account.ID,
 			Type:            "deposit",
-			Amount:          150.00,
+			Amount:          models.MustParseMoney("150.00"),
 			PreviousBalance: account.Balance,
 			NewBalance:      0, // Will be updated during processing
 			Description:     "Async deposit test",
@@ -290,38 +290,38 @@ func TestAsyncTransactionProcessing(t *testing.T) {
 		savedTransaction, err := transactionService.GetTransactionByID(ctx, transactionID)
 		require.NoError(t, err)
 		assert.Equal(t, "pending", savedTransaction.Status)
-		assert.Equal(t, 0.0, savedTransaction.NewBalance) // Not yet processed
+		assert.Equal(t, models.Money(0), savedTransaction.NewBalance) // Not yet processed
 
 		// Process the pending transaction (simulating worker behavior)
 		req := &models.TransactionRequest{
 			Type:        "deposit",
-			Amount:      150.00,
+			Amount:      models.MustParseMoney("150.00"),
 			Description: "Async deposit test",
 		}
 
 		completedTransaction, err := transactionService.ProcessTransactionAsync(ctx, transactionID, req)
 		require.NoError(t, err)
 		assert.Equal(t, "completed", completedTransaction.Status)
-		assert.Equal(t, 600.00, completedTransaction.PreviousBalance)
-		assert.Equal(t, 750.00, completedTransaction.NewBalance)
+		assert.Equal(t, models.MustParseMoney("600.00"), completedTransaction.PreviousBalance)
+		assert.Equal(t, models.MustParseMoney("750.00"), completedTransaction.NewBalance)
 
 		// Verify account balance was updated
 		finalBalance, err := accountService.GetAccountBalance(ctx, account.ID)
 		require.NoError(t, err)
-		assert.Equal(t, 750.00, finalBalance)
+		assert.Equal(t, models.MustParseMoney("750.00"), finalBalance)
 
 		// Verify transaction record was updated
 		finalTransaction, err := transactionService.GetTransactionByID(ctx, transactionID)
 		require.NoError(t, err)
 		assert.Equal(t, "completed", finalTransaction.Status)
-		assert.Equal(t, 750.00, finalTransaction.NewBalance)
+		assert.Equal(t, models.MustParseMoney("750.00"), finalTransaction.NewBalance)
 	})
 
 	t.Run("Async Insufficient Funds Handling", func(t *testing.T) {
 		// Create account with limited funds
 		accountReq := &models.CreateAccountRequest{
 			OwnerName:      "Async Insufficient Test",
-			InitialBalance: 100.00,
+			InitialBalance: models.MustParseMoney("100.00"),
 		}
 		account, err := accountService.CreateAccount(ctx, accountReq)
 		require.NoError(t, err)
@@ -333,7 +333,7 @@ func TestAsyncTransactionProcessing(t *testing.T) {
 			TransactionID: transactionID,
 			AccountID:     account.ID,
 			Type:          "withdraw",
-			Amount:        200.00, // More than available
+			Amount:        models.MustParseMoney("200.00"), // More than available
 			Status:        "pending",
 			Timestamp:     time.Now(),
 		}
@@ -344,7 +344,7 @@ func TestAsyncTransactionProcessing(t *testing.T) {
 		// Try to process (should fail)
 		req := &models.TransactionRequest{
 			Type:   "withdraw",
-			Amount: 200.00,
+			Amount: models.MustParseMoney("200.00"),
 		}
 
 		processedTransaction, err := transactionService.ProcessTransactionAsync(ctx, transactionID, req)
@@ -360,6 +360,6 @@ func TestAsyncTransactionProcessing(t *testing.T) {
 		// Verify account balance unchanged
 		balance, err := accountService.GetAccountBalance(ctx, account.ID)
 		require.NoError(t, err)
-		assert.Equal(t, 100.00, balance)
+		assert.Equal(t, models.MustParseMoney("100.00"), balance)
 	})
 }
@@ -4,24 +4,39 @@ import (
 	"context"
 	"log"
 	"log/slog"
+	"math"
+	"net/http"
 	"os"
 	"os/signal"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/appy29/banking-ledger-service/auth"
 	"github.com/appy29/banking-ledger-service/config"
 	"github.com/appy29/banking-ledger-service/handlers"
 	"github.com/appy29/banking-ledger-service/middleware"
 	"github.com/appy29/banking-ledger-service/queue"
 	"github.com/appy29/banking-ledger-service/services"
 	"github.com/appy29/banking-ledger-service/storage"
+	"github.com/appy29/banking-ledger-service/tracing"
 	"github.com/appy29/banking-ledger-service/worker"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 func main() {
+	// Subcommands run once and exit instead of starting the server
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			os.Exit(command(os.Args[2:]))
+		}
+	}
+
 	// Load configuration
 	cfg := config.Load()
 
@@ -38,6 +53,32 @@ func main() {
 		slog.String("server_addr", cfg.GetServerAddr()),
 		slog.Int("worker_count", cfg.WorkerCount))
 
+	// Export trace spans; a nil provider leaves tracing off
+	tracerProvider, err := tracing.New(context.Background(), cfg.Tracing)
+	if err != nil {
+		logger.Error("Failed to initialize tracing", slog.String("error", err.Error()))
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	tracing.SetDefault(tracerProvider)
+	if tracerProvider != nil {
+		logger.Info("Tracing enabled",
+			slog.String("exporter", cfg.Tracing.Exporter),
+			slog.Float64("sample_ratio", cfg.Tracing.SampleRatio))
+	}
+
+	// Load the credentials API callers authenticate with
+	authenticate := middleware.AllowAnonymous()
+	if cfg.AuthEnabled {
+		authenticator, err := auth.New(cfg.Auth)
+		if err != nil {
+			logger.Error("Failed to initialize authentication", slog.String("error", err.Error()))
+			log.Fatalf("Failed to initialize authentication: %v", err)
+		}
+		authenticate = middleware.Authenticate(authenticator)
+	} else {
+		logger.Warn("Authentication is disabled - every API request runs as an admin")
+	}
+
 	// Initialize PostgreSQL storage
 	logger.Info("Connecting to PostgreSQL")
 	accountStorage, err := storage.NewPostgresAccountStorage(cfg.GetPostgreSQLDSN())
@@ -56,11 +97,30 @@ func main() {
 		log.Fatalf("Failed to initialize MongoDB storage: %v", err)
 	}
 	defer transactionStorage.Close()
+
+	idempotencyStorage, err := storage.NewMongoIdempotencyStorage(cfg.MongoURI, cfg.MongoDB, "idempotency_keys")
+	if err != nil {
+		logger.Error("Failed to initialize idempotency storage", slog.String("error", err.Error()))
+		log.Fatalf("Failed to initialize idempotency storage: %v", err)
+	}
+	defer idempotencyStorage.Close()
+
+	deadLetterStorage, err := storage.NewMongoDeadLetterStorage(cfg.MongoURI, cfg.MongoDB, "dead_letters")
+	if err != nil {
+		logger.Error("Failed to initialize dead letter storage", slog.String("error", err.Error()))
+		log.Fatalf("Failed to initialize dead letter storage: %v", err)
+	}
+	defer deadLetterStorage.Close()
 	logger.Info("MongoDB connected successfully")
 
 	// Initialize RabbitMQ
 	logger.Info("Connecting to RabbitMQ")
 	rabbitmq := queue.NewRabbitMQ(cfg.RabbitMQURL)
+	rabbitmq.SetRetryPolicy(queue.RetryPolicy{
+		MaxRetries: cfg.WorkerMaxRetries,
+		BaseDelay:  cfg.RetryBaseDelay,
+		MaxDelay:   cfg.RetryMaxDelay,
+	})
 
 	rabbitConnected := false
 	for i := 0; i < 10; i++ {
@@ -84,6 +144,38 @@ func main() {
 	// Initialize services
 	accountService := services.NewAccountService(accountStorage)
 	transactionService := services.NewTransactionService(accountStorage, transactionStorage)
+	transactionService.SetDefaultLimits(cfg.DefaultLimits)
+	idempotencyService := services.NewIdempotencyService(idempotencyStorage, accountStorage, transactionStorage)
+	outboxService := services.NewOutboxService(transactionStorage)
+	reaperService := services.NewReaperService(accountStorage, transactionStorage, cfg.PendingMaxAge, cfg.ReaperMaxRequeues)
+	deadLetterService := services.NewDeadLetterService(deadLetterStorage, rabbitmq)
+	reconciliationService := services.NewReconciliationService(accountStorage, transactionStorage, cfg.PendingMaxAge)
+	scheduleService := services.NewScheduleService(accountStorage, accountStorage, transactionStorage, cfg.ScheduleMissedGrace)
+	accessService := services.NewAccessService(accountStorage, transactionStorage, accountStorage, accountStorage)
+	auditService := services.NewAuditService(accountStorage)
+	webhookService := services.NewWebhookService(accountStorage, accountStorage, queue.RetryPolicy{
+		MaxRetries: cfg.WebhookMaxRetries,
+		BaseDelay:  cfg.WebhookRetryBaseDelay,
+		MaxDelay:   cfg.WebhookRetryMaxDelay,
+	}, cfg.WebhookTimeout)
+	accountService.SetNotifier(webhookService)
+	transactionService.SetNotifier(webhookService)
+	reaperService.SetNotifier(webhookService)
+
+	// Read at scrape time, so it keeps rising while the workers are stuck
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "ledger_pending_transaction_oldest_age_seconds",
+		Help: "Age of the oldest transaction still pending.",
+	}, func() float64 {
+		scrapeCtx, scrapeCancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer scrapeCancel()
+		age, err := reaperService.OldestPendingAge(scrapeCtx)
+		if err != nil {
+			logger.Warn("Failed to read oldest pending transaction age", slog.String("error", err.Error()))
+			return math.NaN()
+		}
+		return age.Seconds()
+	})
 
 	// Start background workers if RabbitMQ is connected
 	ctx, cancel := context.WithCancel(context.Background())
@@ -114,35 +206,124 @@ func main() {
 		}
 
 		logger.Info("All workers started successfully")
+
+		// The outbox relay publishes queue messages stored with pending records
+		outboxRelay := worker.NewOutboxRelay(rabbitmq, outboxService, cfg.OutboxPollInterval)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := outboxRelay.Start(ctx); err != nil && err != context.Canceled {
+				logger.Error("Outbox relay stopped", slog.String("error", err.Error()))
+			}
+		}()
+
+		// The pending sweeper settles records that no worker finished
+		pendingSweeper := worker.NewPendingSweeper(reaperService, cfg.ReaperInterval)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := pendingSweeper.Start(ctx); err != nil && err != context.Canceled {
+				logger.Error("Pending sweeper stopped", slog.String("error", err.Error()))
+			}
+		}()
+
+		// The dead letter consumer keeps dead-lettered messages for inspection and replay
+		deadLetterConsumer := worker.NewDeadLetterConsumer(rabbitmq, deadLetterService)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := deadLetterConsumer.Start(ctx); err != nil && err != context.Canceled {
+				logger.Error("Dead letter consumer stopped", slog.String("error", err.Error()))
+			}
+		}()
 	} else {
 		logger.Info("RabbitMQ not available - running in sync mode only")
+
+		// Without the outbox relay and workers nothing would pick up a queued
+		// occurrence, so the scheduler applies each one itself
+		scheduleService.SetProcessor(transactionService)
 	}
 
+	// The scheduler runs in both modes: it queues due standing orders through
+	// the outbox in async mode and applies them directly in sync mode
+	scheduler := worker.NewScheduler(scheduleService, cfg.SchedulerInterval)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := scheduler.Start(ctx); err != nil && err != context.Canceled {
+			logger.Error("Scheduler stopped", slog.String("error", err.Error()))
+		}
+	}()
+
+	// The reconciler checks balances against the transaction log in both modes
+	reconciler := worker.NewReconciler(reconciliationService, cfg.ReconcileInterval, cfg.ReconcileAutoRepair)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := reconciler.Start(ctx); err != nil && err != context.Canceled {
+			logger.Error("Reconciler stopped", slog.String("error", err.Error()))
+		}
+	}()
+
+	// Expired holds stop reserving funds on their own; the expirer updates their status
+	holdExpirer := worker.NewHoldExpirer(transactionService, cfg.HoldExpiryInterval)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := holdExpirer.Start(ctx); err != nil && err != context.Canceled {
+			logger.Error("Hold expirer stopped", slog.String("error", err.Error()))
+		}
+	}()
+
+	// Webhook deliveries are queued by the services in both modes
+	webhookDispatcher := worker.NewWebhookDispatcher(webhookService, cfg.WebhookDispatchInterval)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := webhookDispatcher.Start(ctx); err != nil && err != context.Canceled {
+			logger.Error("Webhook dispatcher stopped", slog.String("error", err.Error()))
+		}
+	}()
+
 	// Initialize Gin router
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	router := gin.New()
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
+	// Count and time every request, including CORS preflights
+	router.Use(middleware.Metrics())
 
 	// Add CORS middleware
 	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:8081", "http://localhost", "*"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
+		AllowOrigins:     cfg.CORSAllowedOrigins,
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", auth.APIKeyHeader, handlers.IdempotencyKeyHeader, tracing.TraceParentHeader},
 		AllowCredentials: false,
 	}))
 
 	// Add middleware
 	router.Use(middleware.AddRequestID())
+	router.Use(otelgin.Middleware(cfg.Tracing.ServiceName))
+	router.Use(middleware.Trace())
 	router.Use(middleware.InjectLogger(logger))
+	router.Use(middleware.Audit(auditService))
 	router.Use(middleware.ValidateJSON())
 	router.Use(gin.Recovery())
 
 	// Initialize handlers
 	healthHandler := handlers.NewHealthHandler(accountService, transactionService, rabbitmq)
 	accountHandler := handlers.NewAccountHandler(accountService)
-	transactionHandler := handlers.NewTransactionHandler(transactionService, rabbitmq, asyncMode)
+	transactionHandler := handlers.NewTransactionHandler(transactionService, idempotencyService, rabbitmq, asyncMode)
+	transferHandler := handlers.NewTransferHandler(transactionService, accessService, rabbitmq, asyncMode)
+	adminHandler := handlers.NewAdminHandler(reaperService, deadLetterService, reconciliationService)
+	scheduleHandler := handlers.NewScheduleHandler(scheduleService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
 
 	// Health check routes
 	router.GET("/health", healthHandler.HealthCheck)
@@ -153,20 +334,94 @@ func main() {
 		c.File("./docs/swagger.yml")
 	})
 
-	// API v1 routes with validation middleware
-	v1 := router.Group("/api/v1")
+	// Callers may only read and move money on the accounts they own, and the
+	// records that belong to them; operators read every account and admins do
+	// anything
+	readAccount := handlers.Authorize(accessService.CheckAccount, auth.AccessRead)
+	writeAccount := handlers.Authorize(accessService.CheckAccount, auth.AccessWrite)
+	readTransaction := handlers.Authorize(accessService.CheckTransaction, auth.AccessRead)
+	readTransfer := handlers.Authorize(accessService.CheckTransfer, auth.AccessRead)
+	readHold := handlers.Authorize(accessService.CheckHold, auth.AccessRead)
+	writeHold := handlers.Authorize(accessService.CheckHold, auth.AccessWrite)
+	readSchedule := handlers.Authorize(accessService.CheckSchedule, auth.AccessRead)
+	writeSchedule := handlers.Authorize(accessService.CheckSchedule, auth.AccessWrite)
+	readWebhook := handlers.Authorize(accessService.CheckWebhook, auth.AccessRead)
+	writeWebhook := handlers.Authorize(accessService.CheckWebhook, auth.AccessWrite)
+
+	// Operational endpoints are for operators and admins; changing an
+	// account's standing is for admins only
+	operators := handlers.RequireRole(auth.RoleOperator, auth.RoleAdmin)
+	admins := handlers.RequireRole(auth.RoleAdmin)
+
+	// API v1 routes with authentication and validation middleware
+	v1 := router.Group("/api/v1", authenticate)
 	{
 		// Account routes
 		v1.POST("/accounts", accountHandler.CreateAccount)
-		v1.GET("/accounts/:id", middleware.ValidateAccountID(), accountHandler.GetAccount)
+		v1.GET("/accounts/:id", middleware.ValidateAccountID(), readAccount, accountHandler.GetAccount)
+		v1.GET("/accounts/:id/ledger", middleware.ValidateAccountID(), readAccount, accountHandler.GetLedgerBalance)
+		v1.GET("/accounts/:id/balance", middleware.ValidateAccountID(), readAccount, transactionHandler.GetBalanceAsOf)
+		v1.GET("/accounts/:id/statement", middleware.ValidateAccountID(), readAccount, transactionHandler.GetStatement)
 
 		// Transaction routes
-		v1.POST("/accounts/:id/transactions", middleware.ValidateAccountID(), transactionHandler.ProcessTransaction)
-		v1.GET("/accounts/:id/transactions", middleware.ValidateAccountID(), middleware.ValidatePagination(), transactionHandler.GetTransactions)
-		v1.GET("/transactions/:id", middleware.ValidateTransactionID(), transactionHandler.GetTransaction)
+		v1.POST("/accounts/:id/transactions", middleware.ValidateAccountID(), writeAccount, transactionHandler.ProcessTransaction)
+		v1.GET("/accounts/:id/transactions", middleware.ValidateAccountID(), middleware.ValidatePagination(), readAccount, transactionHandler.GetTransactions)
+		v1.GET("/accounts/:id/transactions/verify", middleware.ValidateAccountID(), readAccount, transactionHandler.VerifyTransactionChain)
+		v1.GET("/transactions/:id", middleware.ValidateTransactionID(), readTransaction, transactionHandler.GetTransaction)
+		v1.POST("/transactions/:id/reverse", middleware.ValidateTransactionID(), operators, transactionHandler.ReverseTransaction)
+
+		// Hold routes
+		v1.POST("/accounts/:id/holds", middleware.ValidateAccountID(), writeAccount, transactionHandler.PlaceHold)
+		v1.GET("/accounts/:id/holds", middleware.ValidateAccountID(), readAccount, transactionHandler.GetAccountHolds)
+		v1.GET("/holds/:id", middleware.ValidateHoldID(), readHold, transactionHandler.GetHold)
+		v1.POST("/holds/:id/capture", middleware.ValidateHoldID(), writeHold, transactionHandler.CaptureHold)
+		v1.POST("/holds/:id/release", middleware.ValidateHoldID(), writeHold, transactionHandler.ReleaseHold)
+
+		// Scheduled transactions
+		v1.POST("/accounts/:id/schedules", middleware.ValidateAccountID(), writeAccount, scheduleHandler.CreateSchedule)
+		v1.GET("/accounts/:id/schedules", middleware.ValidateAccountID(), readAccount, scheduleHandler.GetAccountSchedules)
+		v1.GET("/schedules/:id", middleware.ValidateScheduleID(), readSchedule, scheduleHandler.GetSchedule)
+		v1.PATCH("/schedules/:id", middleware.ValidateScheduleID(), writeSchedule, scheduleHandler.UpdateSchedule)
+		v1.DELETE("/schedules/:id", middleware.ValidateScheduleID(), writeSchedule, scheduleHandler.CancelSchedule)
+		v1.POST("/schedules/:id/pause", middleware.ValidateScheduleID(), writeSchedule, scheduleHandler.PauseSchedule)
+		v1.POST("/schedules/:id/resume", middleware.ValidateScheduleID(), writeSchedule, scheduleHandler.ResumeSchedule)
+		v1.POST("/schedules/:id/skip", middleware.ValidateScheduleID(), writeSchedule, scheduleHandler.SkipSchedule)
+
+		// Webhook routes; deliveries are reached through their subscription
+		v1.POST("/accounts/:id/webhooks", middleware.ValidateAccountID(), writeAccount, webhookHandler.CreateAccountWebhook)
+		v1.GET("/accounts/:id/webhooks", middleware.ValidateAccountID(), readAccount, webhookHandler.GetAccountWebhooks)
+		v1.GET("/webhooks/:id", middleware.ValidateWebhookID(), readWebhook, webhookHandler.GetWebhook)
+		v1.DELETE("/webhooks/:id", middleware.ValidateWebhookID(), writeWebhook, webhookHandler.DeleteWebhook)
+		v1.GET("/webhooks/:id/deliveries", middleware.ValidateWebhookID(), readWebhook, middleware.ValidatePagination(), webhookHandler.ListDeliveries)
+		v1.GET("/webhooks/:id/deliveries/:delivery_id", middleware.ValidateWebhookID(), middleware.ValidateWebhookDeliveryID(), readWebhook, webhookHandler.GetDelivery)
+		v1.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", middleware.ValidateWebhookID(), middleware.ValidateWebhookDeliveryID(), writeWebhook, webhookHandler.RedeliverDelivery)
+
+		// Transfer routes; the handler checks the caller may debit the source account
+		v1.POST("/transfers", transferHandler.ProcessTransfer)
+		v1.GET("/transfers/:id", middleware.ValidateTransferID(), readTransfer, transferHandler.GetTransfer)
 
 		// Debug/monitoring routes
 		v1.GET("/processing-mode", transactionHandler.GetProcessingMode)
+
+		// Admin routes
+		admin := v1.Group("/admin", operators)
+		admin.GET("/reaper", adminHandler.GetReaperStats)
+		admin.GET("/reconciliation", adminHandler.GetReconciliationReport)
+		admin.POST("/accounts/:id/freeze", middleware.ValidateAccountID(), admins, accountHandler.FreezeAccount)
+		admin.POST("/accounts/:id/unfreeze", middleware.ValidateAccountID(), admins, accountHandler.UnfreezeAccount)
+		admin.POST("/accounts/:id/close", middleware.ValidateAccountID(), admins, accountHandler.CloseAccount)
+		admin.GET("/accounts/:id/status-history", middleware.ValidateAccountID(), accountHandler.GetAccountStatusHistory)
+		admin.PUT("/accounts/:id/overdraft-limit", middleware.ValidateAccountID(), admins, accountHandler.SetOverdraftLimit)
+		admin.GET("/accounts/:id/limits", middleware.ValidateAccountID(), transactionHandler.GetAccountLimits)
+		admin.PUT("/accounts/:id/limits", middleware.ValidateAccountID(), admins, transactionHandler.SetAccountLimits)
+		admin.GET("/accounts/:id/limit-breaches", middleware.ValidateAccountID(), transactionHandler.GetLimitBreaches)
+		admin.GET("/dlq", middleware.ValidatePagination(), adminHandler.ListDeadLetters)
+		admin.GET("/dlq/:id", middleware.ValidateDeadLetterID(), adminHandler.GetDeadLetter)
+		admin.POST("/dlq/:id/replay", middleware.ValidateDeadLetterID(), adminHandler.ReplayDeadLetter)
+		admin.POST("/dlq/:id/discard", middleware.ValidateDeadLetterID(), adminHandler.DiscardDeadLetter)
+		admin.GET("/audit", middleware.ValidatePagination(), auditHandler.QueryAuditTrail)
+		admin.GET("/webhooks", webhookHandler.GetGlobalWebhooks)
+		admin.POST("/webhooks", admins, webhookHandler.CreateGlobalWebhook)
 	}
 
 	// Handle graceful shutdown
@@ -191,10 +446,24 @@ func main() {
 			logger.Warn("Timeout waiting for workers, forcing shutdown")
 		}
 
+		// Export the spans of the last requests and messages
+		if tracerProvider != nil {
+			flushCtx, flushCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := tracerProvider.Shutdown(flushCtx); err != nil {
+				logger.Warn("Failed to flush traces", slog.String("error", err.Error()))
+			}
+			flushCancel()
+		}
+
 		logger.Info("Service shutting down")
 		os.Exit(0)
 	}()
 
+	// Metrics get a listener of their own, so they stay off the public API
+	if cfg.MetricsAddr != "" {
+		go serveMetrics(cfg.MetricsAddr, logger)
+	}
+
 	// Start the server
 	logger.Info("Banking Ledger Service ready",
 		slog.String("address", cfg.GetServerAddr()),
@@ -205,3 +474,16 @@ func main() {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
+
+// serveMetrics serves Prometheus metrics, including the Go runtime and
+// process collectors, on addr
+func serveMetrics(addr string, logger *slog.Logger) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	logger.Info("Serving metrics", slog.String("address", addr))
+	if err := server.ListenAndServe(); err != nil {
+		logger.Error("Metrics listener stopped", slog.String("error", err.Error()))
+	}
+}
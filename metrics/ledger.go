@@ -0,0 +1,114 @@
+// Package metrics holds the service's Prometheus metrics. They are
+// registered with the default registry, which also carries the Go runtime
+// and process collectors, so promhttp.Handler serves them all.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Transaction modes: sync requests post before responding, async ones are
+// applied by a worker from the queue
+const (
+	ModeSync  = "sync"
+	ModeAsync = "async"
+)
+
+// Transaction outcomes. Rejected transactions broke a business rule and will
+// not succeed on retry; failed ones hit a system error.
+const (
+	OutcomeCompleted = "completed"
+	OutcomeRejected  = "rejected"
+	OutcomeFailed    = "failed"
+)
+
+// What the transaction worker finally did with a delivery
+const (
+	DeliveryAck        = "ack"
+	DeliveryNack       = "nack"
+	DeliveryRequeue    = "requeue"
+	DeliveryRetry      = "retry"
+	DeliveryDeadLetter = "dead_letter"
+)
+
+// How a webhook delivery attempt ended
+const (
+	WebhookDelivered = "delivered"
+	WebhookRetry     = "retry"  // Failed, and another attempt is scheduled
+	WebhookGaveUp    = "failed" // Failed, and the retry policy allows no more attempts
+)
+
+// latencyBuckets cover the slower end of request and worker latency
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// pendingAgeBuckets cover a pending record waiting from a second to an hour
+var pendingAgeBuckets = []float64{0.1, 0.5, 1, 5, 15, 30, 60, 120, 300, 600, 1800, 3600}
+
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ledger_http_requests_total",
+		Help: "HTTP requests handled, by method, route and status code.",
+	}, []string{"method", "route", "status"})
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ledger_http_request_duration_seconds",
+		Help:    "Time taken to handle HTTP requests, by method, route and status code.",
+		Buckets: latencyBuckets,
+	}, []string{"method", "route", "status"})
+
+	Transactions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ledger_transactions_total",
+		Help: "Deposits, withdrawals and transfers processed, by type, mode and outcome.",
+	}, []string{"type", "mode", "outcome"})
+	PendingTransactionAge = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ledger_pending_transaction_age_seconds",
+		Help:    "How long async transactions were pending before a worker completed them, by type.",
+		Buckets: pendingAgeBuckets,
+	}, []string{"type"})
+
+	WorkerProcessingDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ledger_worker_processing_duration_seconds",
+		Help:    "Time the transaction worker took to handle a delivery, by message type.",
+		Buckets: latencyBuckets,
+	}, []string{"type"})
+	WorkerInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ledger_worker_in_flight_messages",
+		Help: "Deliveries the transaction workers are handling right now.",
+	})
+	WorkerDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ledger_worker_deliveries_total",
+		Help: "Deliveries settled by the transaction worker, by what was done with them: ack, nack, requeue, retry or dead_letter.",
+	}, []string{"outcome"})
+
+	QueuePublishFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ledger_queue_publish_failures_total",
+		Help: "Messages that could not be published to RabbitMQ, by kind: transaction, retry, dead_letter or replay.",
+	}, []string{"kind"})
+
+	WebhookAttempts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ledger_webhook_delivery_attempts_total",
+		Help: "Webhook delivery attempts, by event type and outcome: delivered, retry or failed.",
+	}, []string{"event", "outcome"})
+	WebhookAttemptDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ledger_webhook_delivery_duration_seconds",
+		Help:    "Time subscribers took to answer webhook deliveries, by event type.",
+		Buckets: latencyBuckets,
+	}, []string{"event"})
+
+	PostgresLockWait = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name: "ledger_postgres_row_lock_wait_seconds",
+		Help: "Time spent waiting for account row locks before a balance change.",
+	})
+
+	MongoOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "ledger_mongo_operation_duration_seconds",
+		Help: "MongoDB command latency, by collection, command and outcome.",
+	}, []string{"collection", "command", "outcome"})
+)
+
+// ObserveSince records the seconds elapsed since start
+func ObserveSince(observer prometheus.Observer, start time.Time) {
+	observer.Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserveSince(t *testing.T) {
+	var observed float64
+	ObserveSince(prometheus.ObserverFunc(func(v float64) { observed = v }), time.Now().Add(-2*time.Second))
+
+	assert.InDelta(t, 2, observed, 0.5)
+}
+
+func TestDefaultGatherer(t *testing.T) {
+	Transactions.WithLabelValues("deposit", ModeSync, OutcomeCompleted).Inc()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	names := make(map[string]bool)
+	for _, family := range families {
+		names[family.GetName()] = true
+	}
+	assert.True(t, names["ledger_transactions_total"])
+	assert.True(t, names["go_goroutines"], "the Go runtime collector is registered")
+	assert.True(t, names["process_cpu_seconds_total"], "the process collector is registered")
+}
@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/audit"
+	"github.com/appy29/banking-ledger-service/auth"
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditRecorder writes records to the audit trail
+type AuditRecorder interface {
+	Record(ctx context.Context, record *models.AuditRecord) error
+}
+
+// maxAuditErrorLength caps the error text kept from a rejected call's response
+const maxAuditErrorLength = 1024
+
+// auditPathTargets names what a route's :id identifies, by the path segment
+// before it
+var auditPathTargets = map[string]string{
+	"transactions": audit.TargetTransaction,
+	"transfers":    audit.TargetTransfer,
+	"holds":        audit.TargetHold,
+	"schedules":    audit.TargetSchedule,
+	"dlq":          audit.TargetDeadLetter,
+}
+
+// Audit writes every mutating call to a known route to the audit trail once
+// it has been handled, whatever the outcome. It must run after AddRequestID
+// and InjectLogger and before authentication, so calls rejected for bad
+// credentials are recorded too. Handlers and services add what the call
+// touched through audit.FromContext.
+func Audit(recorder AuditRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isMutating(c.Request.Method) || c.FullPath() == "" {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		entry := audit.NewEntry()
+		c.Request = c.Request.WithContext(audit.WithEntry(c.Request.Context(), entry))
+		writer := &auditWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		record := &models.AuditRecord{
+			RequestID:  c.GetString("request_id"),
+			Action:     c.Request.Method + " " + c.FullPath(),
+			StatusCode: c.Writer.Status(),
+			ClientIP:   c.ClientIP(),
+			Timestamp:  start,
+		}
+		record.Outcome = models.AuditOutcome(record.StatusCode)
+		if record.Outcome != models.AuditSucceeded {
+			record.Error = responseError(writer.body.Bytes())
+		}
+
+		// Authentication runs inside this middleware, so the caller is only
+		// known now
+		ctx := c.Request.Context()
+		if principal := auth.PrincipalFromContext(ctx); principal != nil {
+			record.Actor = principal.Subject
+			record.AuthMethod = principal.Method
+			record.APIKey = principal.KeyName
+			record.Roles = principal.Roles
+		}
+
+		addPathTarget(c, entry)
+		entry.Fill(record)
+
+		// The client may already have gone, but the record must still be written
+		if err := recorder.Record(context.WithoutCancel(ctx), record); err != nil {
+			utils.LoggerFromContext(ctx).Error("Failed to write audit record",
+				slog.String("error", err.Error()),
+				slog.String("action", record.Action),
+				slog.String("actor", record.Actor),
+				slog.Any("account_ids", record.AccountIDs),
+				slog.Any("targets", record.Targets),
+				slog.Int("status_code", record.StatusCode))
+		}
+	}
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// addPathTarget records the account or other record named by the route's :id
+func addPathTarget(c *gin.Context, entry *audit.Entry) {
+	id := c.Param("id")
+	if id == "" {
+		return
+	}
+
+	segments := strings.Split(c.FullPath(), "/")
+	for i := 1; i < len(segments); i++ {
+		if segments[i] != ":id" {
+			continue
+		}
+		if segments[i-1] == "accounts" {
+			entry.Account(id)
+		} else if kind, ok := auditPathTargets[segments[i-1]]; ok {
+			entry.Target(kind, id)
+		}
+		return
+	}
+}
+
+// responseError extracts the error from a JSON error response body
+func responseError(body []byte) string {
+	var response struct {
+		Error   string `json:"error"`
+		Details string `json:"details"`
+	}
+	message := strings.TrimSpace(string(body))
+	if err := json.Unmarshal(body, &response); err == nil && response.Error != "" {
+		message = response.Error
+		if response.Details != "" {
+			message += ": " + response.Details
+		}
+	}
+	if len(message) > maxAuditErrorLength {
+		message = message[:maxAuditErrorLength]
+	}
+	return message
+}
+
+// auditWriter keeps the start of error responses for the audit record
+type auditWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *auditWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *auditWriter) capture(data []byte) {
+	if w.Status() < http.StatusBadRequest {
+		return
+	}
+	// Keep enough of a truncated body to fill the record's error
+	if room := 4*maxAuditErrorLength - w.body.Len(); room > 0 {
+		if len(data) > room {
+			data = data[:room]
+		}
+		w.body.Write(data)
+	}
+}
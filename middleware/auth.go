@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/appy29/banking-ledger-service/auth"
+	"github.com/appy29/banking-ledger-service/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Authenticate rejects requests without a valid bearer token or API key, and
+// adds the caller to the request context and its logger
+func Authenticate(authenticator *auth.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, err := authenticator.Authenticate(c.GetHeader("Authorization"), c.GetHeader(auth.APIKeyHeader))
+		if err != nil {
+			utils.LoggerFromContext(c.Request.Context()).Warn("Authentication failed", slog.String("error", err.Error()))
+			c.Header("WWW-Authenticate", `Bearer realm="banking-ledger"`)
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   "Authentication required",
+				"details": err.Error(),
+			})
+			c.Abort()
+			return
+		}
+
+		setPrincipal(c, principal)
+		c.Next()
+	}
+}
+
+// AllowAnonymous runs every request as auth.Anonymous, for deployments with
+// authentication disabled
+func AllowAnonymous() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		setPrincipal(c, auth.Anonymous())
+		c.Next()
+	}
+}
+
+func setPrincipal(c *gin.Context, principal *auth.Principal) {
+	ctx := c.Request.Context()
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("subject", principal.Subject),
+		slog.String("auth_method", principal.Method))
+
+	ctx = auth.WithPrincipal(utils.WithLogger(ctx, logger), principal)
+	c.Request = c.Request.WithContext(ctx)
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"log/slog"
 
+	"github.com/appy29/banking-ledger-service/tracing"
 	"github.com/appy29/banking-ledger-service/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -37,6 +38,9 @@ func InjectLogger(baseLogger *slog.Logger) gin.HandlerFunc {
 			slog.String("method", c.Request.Method),
 			slog.String("path", c.Request.URL.Path),
 		)
+		if traceID := tracing.TraceIDFromContext(c.Request.Context()); traceID != "" {
+			contextLogger = contextLogger.With(slog.String("trace_id", traceID))
+		}
 
 		// Add logger to request context
 		ctx := utils.WithLogger(c.Request.Context(), contextLogger)
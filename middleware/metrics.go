@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute labels requests that matched no route, so unknown paths
+// cannot each create a series
+const unmatchedRoute = "unmatched"
+
+// Metrics counts and times every request by method, route template and status.
+// It should run first so the time spent in the other middleware is included.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		metrics.HTTPRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		metrics.ObserveSince(metrics.HTTPRequestDuration.WithLabelValues(c.Request.Method, route, status), start)
+	}
+}
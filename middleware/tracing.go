@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/appy29/banking-ledger-service/tracing"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceIDHeader returns the request's trace ID so callers can quote it
+const TraceIDHeader = "X-Trace-ID"
+
+// Trace tags the server span otelgin.Middleware started with the request ID
+// and returns its trace ID to the caller. It must run after AddRequestID and
+// otelgin.Middleware, and before InjectLogger, which adds the trace ID to the
+// request's logger.
+func Trace() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("request.id", c.GetString("request_id")))
+
+		if traceID := tracing.TraceIDFromContext(ctx); traceID != "" {
+			c.Header(TraceIDHeader, traceID)
+		}
+
+		c.Next()
+	}
+}
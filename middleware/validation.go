@@ -60,6 +60,162 @@ func ValidateTransactionID() gin.HandlerFunc {
 	}
 }
 
+// ValidateTransferID validates transfer ID parameter
+func ValidateTransferID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		transferID := c.Param("id")
+		if transferID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "transfer ID is required",
+				"field": "id",
+			})
+			c.Abort()
+			return
+		}
+
+		if !strings.HasPrefix(transferID, "trf_") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "invalid transfer ID format",
+				"field": "id",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ValidateDeadLetterID validates dead letter ID parameter
+func ValidateDeadLetterID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		deadLetterID := c.Param("id")
+		if deadLetterID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "dead letter ID is required",
+				"field": "id",
+			})
+			c.Abort()
+			return
+		}
+
+		if !strings.HasPrefix(deadLetterID, "dlq_") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "invalid dead letter ID format",
+				"field": "id",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ValidateHoldID validates hold ID parameter
+func ValidateHoldID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		holdID := c.Param("id")
+		if holdID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "hold ID is required",
+				"field": "id",
+			})
+			c.Abort()
+			return
+		}
+
+		if !strings.HasPrefix(holdID, "hold_") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "invalid hold ID format",
+				"field": "id",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ValidateScheduleID validates schedule ID parameter
+func ValidateScheduleID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scheduleID := c.Param("id")
+		if scheduleID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "schedule ID is required",
+				"field": "id",
+			})
+			c.Abort()
+			return
+		}
+
+		if !strings.HasPrefix(scheduleID, "sch_") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "invalid schedule ID format",
+				"field": "id",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ValidateWebhookID validates webhook ID parameter
+func ValidateWebhookID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		webhookID := c.Param("id")
+		if webhookID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "webhook ID is required",
+				"field": "id",
+			})
+			c.Abort()
+			return
+		}
+
+		if !strings.HasPrefix(webhookID, "whk_") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "invalid webhook ID format",
+				"field": "id",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ValidateWebhookDeliveryID validates webhook delivery ID parameter
+func ValidateWebhookDeliveryID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		deliveryID := c.Param("delivery_id")
+		if deliveryID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "delivery ID is required",
+				"field": "delivery_id",
+			})
+			c.Abort()
+			return
+		}
+
+		if !strings.HasPrefix(deliveryID, "whd_") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "invalid delivery ID format",
+				"field": "delivery_id",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // ValidatePagination validates pagination query parameters
 func ValidatePagination() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// Account states. A frozen account can still receive money but nothing can
+// leave it; a closed account takes no postings at all and cannot be reopened.
+const (
+	AccountActive = "active"
+	AccountFrozen = "frozen"
+	AccountClosed = "closed"
+)
+
+// AccountStatusChange records one change of an account's state and why it
+// was made
+type AccountStatusChange struct {
+	ID         int64     `json:"id"`
+	AccountID  string    `json:"account_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Reason     string    `json:"reason"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+// accountStatus treats accounts stored before states existed as active
+func (a *Account) accountStatus() string {
+	if a.Status == "" {
+		return AccountActive
+	}
+	return a.Status
+}
+
+// CheckDebit reports whether money may be taken out of the account
+func (a *Account) CheckDebit() error {
+	switch a.accountStatus() {
+	case AccountFrozen:
+		return Errorf(ErrAccountFrozen, "account %s is frozen", a.ID)
+	case AccountClosed:
+		return Errorf(ErrAccountClosed, "account %s is closed", a.ID)
+	}
+	return nil
+}
+
+// CheckCredit reports whether money may be paid into the account
+func (a *Account) CheckCredit() error {
+	if a.accountStatus() == AccountClosed {
+		return Errorf(ErrAccountClosed, "account %s is closed", a.ID)
+	}
+	return nil
+}
+
+// ValidateStatusChange checks that an account may move from one state to
+// another: active and frozen accounts may be frozen, unfrozen or closed,
+// and a closed account stays closed
+func ValidateStatusChange(from, to string) error {
+	if from == "" {
+		from = AccountActive
+	}
+
+	allowed := false
+	switch to {
+	case AccountFrozen:
+		allowed = from == AccountActive
+	case AccountActive:
+		allowed = from == AccountFrozen
+	case AccountClosed:
+		allowed = from == AccountActive || from == AccountFrozen
+	default:
+		return Errorf(ErrInvalidStatusChange, "unknown account status %q", to)
+	}
+
+	if !allowed {
+		return Errorf(ErrInvalidStatusChange, "account cannot change from %s to %s", from, to)
+	}
+	return nil
+}
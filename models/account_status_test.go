@@ -0,0 +1,71 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccount_CheckDebitAndCredit(t *testing.T) {
+	tests := []struct {
+		status    string
+		debitErr  error
+		creditErr error
+	}{
+		{status: "", debitErr: nil, creditErr: nil},
+		{status: AccountActive, debitErr: nil, creditErr: nil},
+		{status: AccountFrozen, debitErr: ErrAccountFrozen, creditErr: nil},
+		{status: AccountClosed, debitErr: ErrAccountClosed, creditErr: ErrAccountClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			account := &Account{ID: "acc_1", Status: tt.status}
+
+			err := account.CheckDebit()
+			if tt.debitErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, tt.debitErr))
+			}
+
+			err = account.CheckCredit()
+			if tt.creditErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, tt.creditErr))
+			}
+		})
+	}
+}
+
+func TestValidateStatusChange(t *testing.T) {
+	tests := []struct {
+		from, to string
+		valid    bool
+	}{
+		{AccountActive, AccountFrozen, true},
+		{"", AccountFrozen, true},
+		{AccountFrozen, AccountActive, true},
+		{AccountActive, AccountClosed, true},
+		{AccountFrozen, AccountClosed, true},
+		{AccountActive, AccountActive, false},
+		{AccountFrozen, AccountFrozen, false},
+		{AccountClosed, AccountActive, false},
+		{AccountClosed, AccountFrozen, false},
+		{AccountClosed, AccountClosed, false},
+		{AccountActive, "suspended", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			err := ValidateStatusChange(tt.from, tt.to)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, ErrInvalidStatusChange))
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit outcomes, derived from the response status
+const (
+	AuditSucceeded = "succeeded" // 2xx, including requests accepted for asynchronous processing
+	AuditRejected  = "rejected"  // 4xx: invalid, unauthorized or refused by a business rule
+	AuditFailed    = "failed"    // 5xx
+)
+
+// AuditRecord is one mutating API call in the audit trail. Records are only
+// ever inserted; storage refuses to change or delete them.
+type AuditRecord struct {
+	ID         string            `json:"id"`
+	RequestID  string            `json:"request_id"` // X-Request-ID of the call
+	Actor      string            `json:"actor"`      // Subject of the caller; empty when authentication failed
+	AuthMethod string            `json:"auth_method,omitempty"`
+	APIKey     string            `json:"api_key,omitempty"` // Name of the API key used, if any
+	Roles      []string          `json:"roles,omitempty"`
+	Action     string            `json:"action"`                // Route called, e.g. "POST /api/v1/transfers"
+	AccountIDs []string          `json:"account_ids,omitempty"` // Customer accounts the call touched
+	Targets    map[string]string `json:"targets,omitempty"`     // Other records it touched or created, by kind
+	Balances   []BalanceChange   `json:"balances,omitempty"`    // Balances before and after, for calls that moved money
+	Outcome    string            `json:"outcome"`               // "succeeded", "rejected" or "failed"
+	StatusCode int               `json:"status_code"`
+	Error      string            `json:"error,omitempty"`
+	ClientIP   string            `json:"client_ip"`
+	Timestamp  time.Time         `json:"timestamp"`
+}
+
+// AuditOutcome classifies a response status
+func AuditOutcome(status int) string {
+	switch {
+	case status >= 500:
+		return AuditFailed
+	case status >= 400:
+		return AuditRejected
+	default:
+		return AuditSucceeded
+	}
+}
+
+// AuditFilter narrows an audit trail query. Zero fields match every record.
+type AuditFilter struct {
+	Actor     string
+	AccountID string
+	From      time.Time // Inclusive
+	To        time.Time // Exclusive
+}
+
+// Validate rejects ranges that can never match
+func (f *AuditFilter) Validate() error {
+	if !f.From.IsZero() && !f.To.IsZero() && !f.From.Before(f.To) {
+		return Errorf(ErrInvalidRequest, "from must be before to")
+	}
+	return nil
+}
+
+// AuditCursor is a position in the audit trail, which is ordered newest first
+// by timestamp and then by record ID, like transaction history
+type AuditCursor = TransactionCursor
+
+// AuditPage is one page of a cursor-paginated audit trail query
+type AuditPage struct {
+	Records    []AuditRecord `json:"records"`
+	NextCursor string        `json:"next_cursor,omitempty"` // Empty on the last page
+	HasMore    bool          `json:"has_more"`
+}
+
+func NewAuditID() string {
+	return "aud_" + uuid.New().String()
+}
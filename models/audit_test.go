@@ -0,0 +1,26 @@
+package models
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditOutcome(t *testing.T) {
+	assert.Equal(t, AuditSucceeded, AuditOutcome(http.StatusCreated))
+	assert.Equal(t, AuditSucceeded, AuditOutcome(http.StatusAccepted))
+	assert.Equal(t, AuditRejected, AuditOutcome(http.StatusForbidden))
+	assert.Equal(t, AuditRejected, AuditOutcome(http.StatusUnprocessableEntity))
+	assert.Equal(t, AuditFailed, AuditOutcome(http.StatusInternalServerError))
+}
+
+func TestAuditFilter_Validate(t *testing.T) {
+	day := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, (&AuditFilter{}).Validate())
+	assert.NoError(t, (&AuditFilter{Actor: "user_1", From: day, To: day.AddDate(0, 0, 1)}).Validate())
+	assert.True(t, errors.Is((&AuditFilter{From: day, To: day}).Validate(), ErrInvalidRequest))
+}
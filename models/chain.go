@@ -0,0 +1,217 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// chainContents is the canonical form of a sealed record: the fields that
+// must never change once the balance change is final, in a fixed order.
+// Status is the one the record was sealed with; later refunds change it and
+// the refund links, so VerifyChain checks those against the chained refund
+// records instead. Timestamps are cut to the millisecond that MongoDB stores.
+type chainContents struct {
+	ChainSeq              int64  `json:"seq"`
+	PrevHash              string `json:"prev"`
+	ID                    string `json:"id"`
+	TransactionID         string `json:"transaction_id"`
+	AccountID             string `json:"account_id"`
+	Type                  string `json:"type"`
+	Amount                string `json:"amount"`
+	Currency              string `json:"currency"`
+	PreviousBalance       string `json:"previous_balance"`
+	NewBalance            string `json:"new_balance"`
+	Description           string `json:"description"`
+	Timestamp             int64  `json:"timestamp"`
+	TransferID            string `json:"transfer_id"`
+	CounterpartyAccountID string `json:"counterparty_account_id"`
+	ReversalOf            string `json:"reversal_of"`
+	Status                string `json:"status"`
+}
+
+// ComputeHash returns the hex SHA-256 of the record's canonical contents,
+// including its chain position and the previous record's hash
+func (t *Transaction) ComputeHash() string {
+	data, _ := json.Marshal(chainContents{
+		ChainSeq:              t.ChainSeq,
+		PrevHash:              t.PrevHash,
+		ID:                    t.ID,
+		TransactionID:         t.TransactionID,
+		AccountID:             t.AccountID,
+		Type:                  t.Type,
+		Amount:                t.Amount.String(),
+		Currency:              t.Currency,
+		PreviousBalance:       t.PreviousBalance.String(),
+		NewBalance:            t.NewBalance.String(),
+		Description:           t.Description,
+		Timestamp:             t.Timestamp.UnixMilli(),
+		TransferID:            t.TransferID,
+		CounterpartyAccountID: t.CounterpartyAccountID,
+		ReversalOf:            t.ReversalOf,
+		Status:                t.settledStatus(),
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// settledStatus is the status the record was sealed with: refunds only ever
+// move a completed record on to partially_refunded or reversed
+func (t *Transaction) settledStatus() string {
+	if t.Status == "partially_refunded" || t.Status == "reversed" {
+		return "completed"
+	}
+	return t.Status
+}
+
+// IsSealed reports whether the record has been added to its account's chain
+func (t *Transaction) IsSealed() bool {
+	return t.Hash != ""
+}
+
+// Seal links the record after previous, the head of its account's chain, or
+// starts the chain when previous is nil
+func (t *Transaction) Seal(previous *Transaction) {
+	t.ChainSeq = 1
+	t.PrevHash = ""
+	if previous != nil {
+		t.ChainSeq = previous.ChainSeq + 1
+		t.PrevHash = previous.Hash
+	}
+	t.Hash = t.ComputeHash()
+}
+
+// ChainBreak is the first link in an account's chain that does not verify
+type ChainBreak struct {
+	ChainSeq      int64  `json:"chain_seq"`
+	TransactionID string `json:"transaction_id,omitempty"`
+	Reason        string `json:"reason"`
+}
+
+// ChainVerification is the result of walking one account's hash chain
+type ChainVerification struct {
+	AccountID  string      `json:"account_id"`
+	Valid      bool        `json:"valid"`
+	Verified   int         `json:"verified"`            // Links checked before the first break, or all of them
+	HeadHash   string      `json:"head_hash,omitempty"` // Hash of the last valid link; anchor it elsewhere to catch truncation
+	BrokenLink *ChainBreak `json:"broken_link,omitempty"`
+	VerifiedAt time.Time   `json:"verified_at"`
+}
+
+// VerifyChain walks an account's sealed records in chain order and stops at
+// the first one that was edited, removed or inserted out of turn, or whose
+// refund state does not match the refund records chained after it. A refund
+// still being posted shows up as a break until its record is sealed.
+func VerifyChain(accountID string, records []Transaction) *ChainVerification {
+	result := &ChainVerification{AccountID: accountID, Valid: true, VerifiedAt: time.Now()}
+
+	linked, broken := verifyLinks(accountID, records)
+
+	// Only refunds whose own links verify count towards the originals
+	refunds := make(map[string][]*Transaction)
+	for i := range records[:linked] {
+		if refund := &records[i]; refund.ReversalOf != "" {
+			refunds[refund.ReversalOf] = append(refunds[refund.ReversalOf], refund)
+		}
+	}
+
+	for i := range records[:linked] {
+		record := &records[i]
+		if reason := checkRefunds(record, refunds[record.TransactionID]); reason != "" {
+			linked = i
+			broken = &ChainBreak{ChainSeq: record.ChainSeq, TransactionID: record.TransactionID, Reason: reason}
+			break
+		}
+	}
+
+	result.Verified = linked
+	if linked > 0 {
+		result.HeadHash = records[linked-1].Hash
+	}
+	if broken != nil {
+		result.Valid = false
+		result.BrokenLink = broken
+	}
+	return result
+}
+
+// verifyLinks checks each record's position, previous hash and own hash. It
+// returns how many records verify before the first break, and the break.
+func verifyLinks(accountID string, records []Transaction) (int, *ChainBreak) {
+	var previous *Transaction
+	for i := range records {
+		record := &records[i]
+
+		expectedSeq := int64(1)
+		expectedPrev := ""
+		if previous != nil {
+			expectedSeq = previous.ChainSeq + 1
+			expectedPrev = previous.Hash
+		}
+
+		var reason string
+		switch {
+		case record.ChainSeq != expectedSeq:
+			reason = fmt.Sprintf("expected link %d, found %d: a record is missing", expectedSeq, record.ChainSeq)
+		case record.AccountID != accountID:
+			reason = fmt.Sprintf("record belongs to account %s", record.AccountID)
+		case record.PrevHash != expectedPrev:
+			reason = "previous hash does not match the link before it"
+		case record.Hash != record.ComputeHash():
+			reason = "contents do not match the record's hash"
+		case record.settledStatus() != "completed":
+			reason = fmt.Sprintf("sealed record has status %q", record.Status)
+		}
+
+		if reason != "" {
+			return i, &ChainBreak{ChainSeq: expectedSeq, TransactionID: record.TransactionID, Reason: reason}
+		}
+		previous = record
+	}
+
+	return len(records), nil
+}
+
+// checkRefunds compares a record's status and refund links with the refund
+// records that reverse it, and explains the first difference
+func checkRefunds(record *Transaction, refunds []*Transaction) string {
+	var refunded Money
+	ids := make(map[string]bool, len(refunds))
+	for _, refund := range refunds {
+		refunded = refunded.Add(refund.Amount)
+		ids[refund.TransactionID] = true
+	}
+
+	status := "completed"
+	switch {
+	case refunded > record.Amount:
+		return fmt.Sprintf("refunds of %s exceed the amount %s", refunded, record.Amount)
+	case refunded == 0:
+	case refunded == record.Amount:
+		status = "reversed"
+	default:
+		status = "partially_refunded"
+	}
+
+	if record.Status != status {
+		return fmt.Sprintf("status %q does not match its refunds: expected %q", record.Status, status)
+	}
+	if record.RefundedAmount != refunded {
+		return fmt.Sprintf("refunded amount %s does not match its refunds of %s", record.RefundedAmount, refunded)
+	}
+	if len(record.Refunds) != len(ids) {
+		return fmt.Sprintf("lists %d refunds, found %d", len(record.Refunds), len(ids))
+	}
+	for _, id := range record.Refunds {
+		if !ids[id] {
+			return fmt.Sprintf("lists refund %s, which is not in the chain", id)
+		}
+	}
+	reversed := status == "reversed"
+	if reversed && !ids[record.ReversedBy] || !reversed && record.ReversedBy != "" {
+		return fmt.Sprintf("reversed by %q, which does not match its refunds", record.ReversedBy)
+	}
+	return ""
+}
@@ -0,0 +1,257 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sealedChain returns n records for acc_12345, each sealed after the last
+func sealedChain(n int) []Transaction {
+	records := make([]Transaction, n)
+	balance := MustParseMoney("0")
+	start := time.Date(2024, 8, 1, 9, 0, 0, 123456789, time.UTC)
+
+	for i := range records {
+		amount := MustParseMoney("10.00")
+		records[i] = Transaction{
+			ID:              NewTransactionID(),
+			AccountID:       "acc_12345",
+			Type:            "deposit",
+			Amount:          amount,
+			Currency:        "USD",
+			PreviousBalance: balance,
+			NewBalance:      balance.Add(amount),
+			Description:     "Salary",
+			Timestamp:       start.Add(time.Duration(i) * time.Hour),
+			Status:          "completed",
+		}
+		records[i].TransactionID = records[i].ID
+		balance = records[i].NewBalance
+
+		var previous *Transaction
+		if i > 0 {
+			previous = &records[i-1]
+		}
+		records[i].Seal(previous)
+	}
+	return records
+}
+
+// refund seals a refund of amount against records[i] after the last record,
+// and records it on the original as RecordRefund does
+func refund(records []Transaction, i int, amount Money) []Transaction {
+	last := records[len(records)-1]
+	original := &records[i]
+
+	record := Transaction{
+		ID:              NewTransactionID(),
+		AccountID:       original.AccountID,
+		Type:            "withdraw",
+		Amount:          amount,
+		Currency:        original.Currency,
+		PreviousBalance: last.NewBalance,
+		NewBalance:      last.NewBalance.Sub(amount),
+		Description:     "Reversal of " + original.TransactionID,
+		Timestamp:       last.Timestamp.Add(time.Hour),
+		Status:          "completed",
+		ReversalOf:      original.TransactionID,
+	}
+	record.TransactionID = record.ID
+	record.Seal(&last)
+
+	original.RefundedAmount = original.RefundedAmount.Add(amount)
+	original.Refunds = append(original.Refunds, record.TransactionID)
+	original.Status = "partially_refunded"
+	if original.RefundedAmount == original.Amount {
+		original.Status = "reversed"
+		original.ReversedBy = record.TransactionID
+	}
+	return append(records, record)
+}
+
+func TestTransaction_Seal(t *testing.T) {
+	records := sealedChain(2)
+
+	assert.Equal(t, int64(1), records[0].ChainSeq)
+	assert.Empty(t, records[0].PrevHash)
+	assert.Len(t, records[0].Hash, 64)
+	assert.Equal(t, int64(2), records[1].ChainSeq)
+	assert.Equal(t, records[0].Hash, records[1].PrevHash)
+	assert.True(t, records[1].IsSealed())
+}
+
+func TestTransaction_ComputeHash_IgnoresMutableFields(t *testing.T) {
+	record := sealedChain(1)[0]
+	hash := record.Hash
+
+	// A reversal and MongoDB's millisecond timestamps leave the hash alone
+	reversedAt := time.Now()
+	record.Status = "reversed"
+	record.ReversedBy = "txn_2"
+	record.ReversedAt = &reversedAt
+	record.ErrorMessage = "reaper: balance change was applied but the record was left pending"
+	record.Timestamp = record.Timestamp.Truncate(time.Millisecond)
+	assert.Equal(t, hash, record.ComputeHash())
+
+	record.Amount = MustParseMoney("1000.00")
+	assert.NotEqual(t, hash, record.ComputeHash())
+}
+
+func TestVerifyChain(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(records []Transaction) []Transaction
+		seq    int64
+		reason string
+	}{
+		{
+			name:   "intact",
+			tamper: func(records []Transaction) []Transaction { return records },
+		},
+		{
+			name: "edited amount",
+			tamper: func(records []Transaction) []Transaction {
+				records[2].Amount = MustParseMoney("99.00")
+				return records
+			},
+			seq:    3,
+			reason: "contents do not match",
+		},
+		{
+			name: "edited and rehashed",
+			tamper: func(records []Transaction) []Transaction {
+				records[1].NewBalance = MustParseMoney("500.00")
+				records[1].Hash = records[1].ComputeHash()
+				return records
+			},
+			seq:    3,
+			reason: "previous hash",
+		},
+		{
+			name: "deleted record",
+			tamper: func(records []Transaction) []Transaction {
+				return append(records[:1], records[2:]...)
+			},
+			seq:    2,
+			reason: "missing",
+		},
+		{
+			name: "status rewritten",
+			tamper: func(records []Transaction) []Transaction {
+				records[3].Status = "failed"
+				return records
+			},
+			seq:    4,
+			reason: "contents do not match",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records := tt.tamper(sealedChain(4))
+			result := VerifyChain("acc_12345", records)
+
+			if tt.reason == "" {
+				assert.True(t, result.Valid)
+				assert.Equal(t, 4, result.Verified)
+				assert.Equal(t, records[3].Hash, result.HeadHash)
+				assert.Nil(t, result.BrokenLink)
+				return
+			}
+
+			assert.False(t, result.Valid)
+			require.NotNil(t, result.BrokenLink)
+			assert.Equal(t, tt.seq, result.BrokenLink.ChainSeq)
+			assert.Contains(t, result.BrokenLink.Reason, tt.reason)
+			assert.Equal(t, int(tt.seq-1), result.Verified)
+		})
+	}
+}
+
+func TestVerifyChain_Refunds(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(records []Transaction) []Transaction
+		seq    int64
+		reason string
+	}{
+		{
+			name:   "intact",
+			tamper: func(records []Transaction) []Transaction { return records },
+		},
+		{
+			name: "reversed without refunds",
+			tamper: func(records []Transaction) []Transaction {
+				records[0].Status = "reversed"
+				return records
+			},
+			seq:    1,
+			reason: "status",
+		},
+		{
+			name: "refunded amount edited",
+			tamper: func(records []Transaction) []Transaction {
+				records[1].RefundedAmount = MustParseMoney("1.00")
+				return records
+			},
+			seq:    2,
+			reason: "refunded amount",
+		},
+		{
+			name: "refund link removed",
+			tamper: func(records []Transaction) []Transaction {
+				records[2].Refunds = records[2].Refunds[:1]
+				return records
+			},
+			seq:    3,
+			reason: "lists 1 refunds",
+		},
+		{
+			name: "reversed by someone else",
+			tamper: func(records []Transaction) []Transaction {
+				records[2].ReversedBy = "txn_other"
+				return records
+			},
+			seq:    3,
+			reason: "reversed by",
+		},
+		{
+			name: "refund record cut off",
+			tamper: func(records []Transaction) []Transaction {
+				return records[:len(records)-1]
+			},
+			seq:    3,
+			reason: "status",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records := sealedChain(3)
+			records = refund(records, 1, MustParseMoney("4.00"))
+			records = refund(records, 2, MustParseMoney("6.00"))
+			records = refund(records, 2, MustParseMoney("4.00"))
+			require.Equal(t, "partially_refunded", records[1].Status)
+			require.Equal(t, "reversed", records[2].Status)
+
+			records = tt.tamper(records)
+			result := VerifyChain("acc_12345", records)
+
+			if tt.reason == "" {
+				assert.True(t, result.Valid)
+				assert.Equal(t, len(records), result.Verified)
+				assert.Nil(t, result.BrokenLink)
+				return
+			}
+
+			assert.False(t, result.Valid)
+			require.NotNil(t, result.BrokenLink)
+			assert.Equal(t, tt.seq, result.BrokenLink.ChainSeq)
+			assert.Contains(t, result.BrokenLink.Reason, tt.reason)
+			assert.Equal(t, int(tt.seq-1), result.Verified)
+		})
+	}
+}
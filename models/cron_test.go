@@ -0,0 +1,56 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@every"} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := ParseCron(expr)
+			assert.True(t, errors.Is(err, ErrInvalidSchedule))
+		})
+	}
+}
+
+func TestCronExpr_Next(t *testing.T) {
+	from := time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC) // A Thursday
+
+	tests := []struct {
+		name string
+		expr string
+		want time.Time
+	}{
+		{name: "first of every month", expr: "0 9 1 * *", want: time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)},
+		{name: "monthly descriptor", expr: "@monthly", want: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "every friday", expr: "0 12 * * 5", want: time.Date(2026, 1, 16, 12, 0, 0, 0, time.UTC)},
+		{name: "sunday as 7", expr: "0 0 * * 7", want: time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC)},
+		{name: "every quarter hour", expr: "*/15 * * * *", want: time.Date(2026, 1, 15, 10, 45, 0, 0, time.UTC)},
+		{name: "list and range", expr: "0 8-9,17 * * 1-5", want: time.Date(2026, 1, 15, 17, 0, 0, 0, time.UTC)},
+		{name: "day of month or day of week", expr: "0 0 20 * 5", want: time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{name: "leap day", expr: "0 0 29 2 *", want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "never", expr: "0 0 30 2 *", want: time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cron, err := ParseCron(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cron.Next(from))
+		})
+	}
+}
+
+func TestCronExpr_NextIsStrictlyAfter(t *testing.T) {
+	cron, err := ParseCron("@daily")
+	require.NoError(t, err)
+
+	midnight := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, midnight.AddDate(0, 0, 1), cron.Next(midnight))
+	assert.Equal(t, midnight, cron.Next(midnight.Add(-time.Second)))
+}
@@ -0,0 +1,68 @@
+package models
+
+import "strings"
+
+// DefaultCurrency is used for accounts created without an explicit currency
+// and for accounts that existed before currencies were introduced
+const DefaultCurrency = "USD"
+
+// MaxCurrencyPrecision is the largest number of minor-unit digits among the
+// supported currencies; amounts with more decimals are never valid
+const MaxCurrencyPrecision = 3
+
+// currencyPrecision maps supported ISO 4217 codes to their number of minor-unit digits
+var currencyPrecision = map[string]int{
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"CHF": 2,
+	"CAD": 2,
+	"AUD": 2,
+	"NZD": 2,
+	"SEK": 2,
+	"NOK": 2,
+	"DKK": 2,
+	"INR": 2,
+	"CNY": 2,
+	"SGD": 2,
+	"HKD": 2,
+	"JPY": 0,
+	"KRW": 0,
+	"BHD": 3,
+	"KWD": 3,
+	"OMR": 3,
+	"JOD": 3,
+	"TND": 3,
+}
+
+// NormalizeCurrency upper-cases and trims a currency code
+func NormalizeCurrency(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// CurrencyPrecision returns the number of decimal places allowed for a currency
+func CurrencyPrecision(code string) (int, bool) {
+	precision, ok := currencyPrecision[NormalizeCurrency(code)]
+	return precision, ok
+}
+
+// IsSupportedCurrency reports whether the ledger accepts the given currency code
+func IsSupportedCurrency(code string) bool {
+	_, ok := CurrencyPrecision(code)
+	return ok
+}
+
+// ValidateAmountPrecision checks that amount has no more decimal places than
+// the currency's minor unit allows (e.g. none for JPY, three for BHD)
+func ValidateAmountPrecision(amount Money, currency string) error {
+	precision, ok := CurrencyPrecision(currency)
+	if !ok {
+		return Errorf(ErrUnsupportedCurrency, "unsupported currency: %s", currency)
+	}
+
+	if amount.DecimalPlaces() > precision {
+		return Errorf(ErrInvalidPrecision, "amount cannot have more than %d decimal places for %s", precision, NormalizeCurrency(currency))
+	}
+
+	return nil
+}
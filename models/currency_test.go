@@ -0,0 +1,35 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrencyPrecision(t *testing.T) {
+	precision, ok := CurrencyPrecision(" usd ")
+	assert.True(t, ok)
+	assert.Equal(t, 2, precision)
+
+	precision, ok = CurrencyPrecision("JPY")
+	assert.True(t, ok)
+	assert.Equal(t, 0, precision)
+
+	_, ok = CurrencyPrecision("XYZ")
+	assert.False(t, ok)
+}
+
+func TestValidateAmountPrecision(t *testing.T) {
+	assert.NoError(t, ValidateAmountPrecision(MustParseMoney("10.25"), "EUR"))
+	assert.NoError(t, ValidateAmountPrecision(MustParseMoney("500"), "JPY"))
+	assert.NoError(t, ValidateAmountPrecision(MustParseMoney("1.125"), "KWD"))
+
+	err := ValidateAmountPrecision(MustParseMoney("500.5"), "JPY")
+	assert.EqualError(t, err, "amount cannot have more than 0 decimal places for JPY")
+
+	err = ValidateAmountPrecision(MustParseMoney("10.125"), "usd")
+	assert.EqualError(t, err, "amount cannot have more than 2 decimal places for USD")
+
+	err = ValidateAmountPrecision(MustParseMoney("10"), "XYZ")
+	assert.EqualError(t, err, "unsupported currency: XYZ")
+}
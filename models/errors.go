@@ -0,0 +1,101 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Domain errors shared by the storage and service layers. Callers decide what
+// to do with a failure by testing for these with errors.Is; the message text
+// is only meant for logs and API error details.
+var (
+	ErrInvalidRequest          = errors.New("invalid request")
+	ErrAccountNotFound         = errors.New("account not found")
+	ErrAccountFrozen           = errors.New("account is frozen")
+	ErrAccountClosed           = errors.New("account is closed")
+	ErrInvalidStatusChange     = errors.New("invalid account status change")
+	ErrTransactionNotFound     = errors.New("transaction not found")
+	ErrTransferNotFound        = errors.New("transfer not found")
+	ErrHoldNotFound            = errors.New("hold not found")
+	ErrHoldNotActive           = errors.New("hold is not active")
+	ErrInsufficientFunds       = errors.New("insufficient funds")
+	ErrLimitExceeded           = errors.New("transaction limit exceeded")
+	ErrInvalidTransactionType  = errors.New("invalid transaction type")
+	ErrInvalidAmount           = errors.New("invalid amount")
+	ErrSameAccount             = errors.New("cannot transfer to the same account")
+	ErrCurrencyMismatch        = errors.New("currency mismatch")
+	ErrUnsupportedCurrency     = errors.New("unsupported currency")
+	ErrInvalidPrecision        = errors.New("too many decimal places")
+	ErrNotPending              = errors.New("not in pending state")
+	ErrAlreadyApplied          = errors.New("transaction already applied")
+	ErrAlreadyReversed         = errors.New("transaction already reversed")
+	ErrNotReversible           = errors.New("transaction cannot be reversed")
+	ErrTransactionSealed       = errors.New("transaction is sealed")
+	ErrUnbalancedEntry         = errors.New("unbalanced journal entry")
+	ErrIdempotencyKeyReused    = errors.New("idempotency key reused with a different request body")
+	ErrIdempotencyInProgress   = errors.New("request with this idempotency key is still in progress")
+	ErrIdempotencyIncomplete   = errors.New("request with this idempotency key was applied but its response was not stored")
+	ErrDeadLetterNotFound      = errors.New("dead letter not found")
+	ErrDeadLetterResolved      = errors.New("dead letter is already resolved")
+	ErrInvalidDeadLetterStatus = errors.New("invalid dead letter status")
+	ErrTransactionExists       = errors.New("transaction already exists")
+	ErrScheduleNotFound        = errors.New("schedule not found")
+	ErrInvalidSchedule         = errors.New("invalid schedule")
+	ErrInvalidScheduleChange   = errors.New("invalid schedule change")
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrInvalidWebhook          = errors.New("invalid webhook")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrWebhookDeliveryPending  = errors.New("webhook delivery is still pending")
+	ErrUnauthenticated         = errors.New("authentication required")
+	ErrForbidden               = errors.New("access denied")
+)
+
+// domainError gives one of the sentinel errors a more specific message while
+// still matching it under errors.Is
+type domainError struct {
+	kind error
+	msg  string
+}
+
+func (e *domainError) Error() string { return e.msg }
+
+func (e *domainError) Unwrap() error { return e.kind }
+
+// Errorf formats an error message that matches kind under errors.Is, e.g.
+// Errorf(ErrInvalidAmount, "amount must be greater than 0")
+func Errorf(kind error, format string, args ...interface{}) error {
+	return &domainError{kind: kind, msg: fmt.Sprintf(format, args...)}
+}
+
+// InsufficientFundsError is returned when a debit is larger than the funds
+// available on an account. It matches ErrInsufficientFunds under errors.Is.
+type InsufficientFundsError struct {
+	AccountID string
+	Balance   Money // Available balance: the ledger balance less active holds, plus any overdraft limit
+	Requested Money
+}
+
+func (e *InsufficientFundsError) Error() string {
+	return fmt.Sprintf("insufficient funds: available balance %s, requested %s", e.Balance, e.Requested)
+}
+
+func (e *InsufficientFundsError) Is(target error) bool {
+	return target == ErrInsufficientFunds
+}
+
+// LimitExceededError is returned when a transaction would break one of the
+// account's transaction limits. It matches ErrLimitExceeded under errors.Is.
+type LimitExceededError struct {
+	AccountID string
+	Limit     string // Name of the limit, e.g. LimitMaxDailyWithdrawn
+	Max       string // The limit
+	Attempted string // What the transaction would have brought the total to
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("transaction limit exceeded: %s is %s, attempted %s", e.Limit, e.Max, e.Attempted)
+}
+
+func (e *LimitExceededError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
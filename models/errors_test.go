@@ -0,0 +1,38 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorf_MatchesKind(t *testing.T) {
+	err := fmt.Errorf("failed to process transaction: %w", Errorf(ErrNotPending, "transaction is not in pending state: %s", "completed"))
+
+	assert.True(t, errors.Is(err, ErrNotPending))
+	assert.False(t, errors.Is(err, ErrInvalidAmount))
+	assert.EqualError(t, err, "failed to process transaction: transaction is not in pending state: completed")
+}
+
+func TestInsufficientFundsError(t *testing.T) {
+	err := fmt.Errorf("failed to update balance: %w", &InsufficientFundsError{
+		AccountID: "acc_1",
+		Balance:   MustParseMoney("10.00"),
+		Requested: MustParseMoney("25.50"),
+	})
+
+	assert.True(t, errors.Is(err, ErrInsufficientFunds))
+	assert.Contains(t, err.Error(), "insufficient funds: available balance 10.00, requested 25.50")
+
+	var insufficient *InsufficientFundsError
+	assert.True(t, errors.As(err, &insufficient))
+	assert.Equal(t, "acc_1", insufficient.AccountID)
+	assert.Equal(t, MustParseMoney("25.50"), insufficient.Requested)
+}
+
+func TestValidateAmountPrecision_ErrorKinds(t *testing.T) {
+	assert.ErrorIs(t, ValidateAmountPrecision(MustParseMoney("500.5"), "JPY"), ErrInvalidPrecision)
+	assert.ErrorIs(t, ValidateAmountPrecision(MustParseMoney("5"), "XYZ"), ErrUnsupportedCurrency)
+}
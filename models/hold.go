@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Hold states. Only an active hold that has not reached its expiry reserves
+// funds; the other states are final.
+const (
+	HoldActive   = "active"
+	HoldCaptured = "captured"
+	HoldReleased = "released"
+	HoldExpired  = "expired"
+)
+
+// Hold reserves part of an account's balance, e.g. for a card authorization,
+// until it is captured, released or expires. A hold lowers the available
+// balance but leaves the ledger balance alone; only a capture moves money.
+type Hold struct {
+	ID             string    `json:"id"`
+	AccountID      string    `json:"account_id"`
+	Amount         Money     `json:"amount"`
+	Currency       string    `json:"currency"`
+	Description    string    `json:"description"`
+	Status         string    `json:"status"`
+	CapturedAmount Money     `json:"captured_amount"`
+	TransactionID  string    `json:"transaction_id,omitempty"` // Withdrawal posted by the capture
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// IsExpired reports whether an active hold has reached its expiry and no
+// longer reserves funds, whether or not the expiry sweep has marked it yet
+func (h *Hold) IsExpired(now time.Time) bool {
+	return h.Status == HoldActive && !now.Before(h.ExpiresAt)
+}
+
+// CreateHoldRequest represents the request body for placing a hold
+type CreateHoldRequest struct {
+	Amount           Money  `json:"amount"`
+	Currency         string `json:"currency"` // Optional, must match the account currency when set
+	Description      string `json:"description"`
+	ExpiresInSeconds int64  `json:"expires_in_seconds"` // Optional, defaults to DefaultHoldTTL
+}
+
+// CaptureHoldRequest represents the request body for capturing a hold. A zero
+// amount captures the full hold; anything less is a partial capture, and the
+// rest of the hold is released.
+type CaptureHoldRequest struct {
+	Amount      Money  `json:"amount"`
+	Description string `json:"description"`
+}
+
+// HoldCapture is the result of capturing a hold: the settled hold and the
+// withdrawal that moved the money
+type HoldCapture struct {
+	Hold        *Hold        `json:"hold"`
+	Transaction *Transaction `json:"transaction"`
+}
+
+// Limits on how long a hold can reserve funds
+const (
+	DefaultHoldTTL = 7 * 24 * time.Hour
+	MaxHoldTTL     = 30 * 24 * time.Hour
+)
+
+func NewHoldID() string {
+	return "hold_" + uuid.New().String()
+}
@@ -0,0 +1,207 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Posting directions
+const (
+	Debit  = "debit"
+	Credit = "credit"
+)
+
+// Kinds of system ledger account. Each kind exists once per currency.
+const (
+	SystemCash     = "cash"     // Money held by the bank; the other side of deposits and withdrawals
+	SystemSuspense = "suspense" // Amounts that cannot yet be attributed to a customer account
+	SystemFees     = "fees"     // Fee income
+)
+
+// systemAccountPrefix marks ledger accounts that do not belong to a customer
+const systemAccountPrefix = "sys_"
+
+// SystemAccountID returns the ID of the system account of kind for currency,
+// e.g. "sys_cash_usd"
+func SystemAccountID(kind, currency string) string {
+	return systemAccountPrefix + kind + "_" + strings.ToLower(NormalizeCurrency(currency))
+}
+
+// IsSystemAccount reports whether a ledger account ID names a system account
+func IsSystemAccount(accountID string) bool {
+	return strings.HasPrefix(accountID, systemAccountPrefix)
+}
+
+// ParseSystemAccountID splits a system account ID into its kind and currency
+func ParseSystemAccountID(accountID string) (kind, currency string, ok bool) {
+	if !IsSystemAccount(accountID) {
+		return "", "", false
+	}
+
+	rest := strings.TrimPrefix(accountID, systemAccountPrefix)
+	i := strings.LastIndex(rest, "_")
+	if i <= 0 || i == len(rest)-1 {
+		return "", "", false
+	}
+	return rest[:i], NormalizeCurrency(rest[i+1:]), true
+}
+
+// SystemAccount is a ledger account owned by the bank rather than a customer.
+// Its balance is always derived from its postings.
+type SystemAccount struct {
+	ID            string `json:"id"`
+	Kind          string `json:"kind"` // "cash", "suspense" or "fees"
+	Currency      string `json:"currency"`
+	NormalBalance string `json:"normal_balance"` // Direction that increases the balance
+	Balance       Money  `json:"balance"`
+}
+
+// SystemAccountNormalBalance returns the direction that increases a system
+// account of kind. Cash and suspense are assets; fees are income.
+func SystemAccountNormalBalance(kind string) string {
+	if kind == SystemFees {
+		return Credit
+	}
+	return Debit
+}
+
+// Posting is one side of a journal entry against a single ledger account.
+// Customer accounts are liabilities of the bank, so a credit increases their
+// balance and a debit decreases it.
+type Posting struct {
+	AccountID     string    `json:"account_id"`
+	TransactionID string    `json:"transaction_id,omitempty"` // Transaction log record the posting belongs to
+	Direction     string    `json:"direction"`                // "debit" or "credit"
+	Amount        Money     `json:"amount"`
+	Currency      string    `json:"currency"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// JournalEntry is a set of postings whose debits and credits balance in every
+// currency. Balances only ever change by posting an entry.
+type JournalEntry struct {
+	ID          string    `json:"id"`
+	Description string    `json:"description"`
+	Postings    []Posting `json:"postings"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// Limits are the velocity limits of the customer account the entry
+	// debits. The journal checks them under the account's row lock, so
+	// concurrent withdrawals cannot together exceed them.
+	Limits *TransactionLimits `json:"-"`
+}
+
+// NewJournalEntry creates an entry from postings, stamping them with its time
+func NewJournalEntry(description string, postings ...Posting) *JournalEntry {
+	now := time.Now()
+	for i := range postings {
+		postings[i].CreatedAt = now
+	}
+
+	return &JournalEntry{
+		ID:          NewJournalEntryID(),
+		Description: description,
+		Postings:    postings,
+		CreatedAt:   now,
+	}
+}
+
+// Validate rejects entries that are not balanced double-entry: every entry
+// needs a debit and a credit, positive amounts, and equal debits and credits
+// in each currency
+func (e *JournalEntry) Validate() error {
+	if len(e.Postings) < 2 {
+		return Errorf(ErrUnbalancedEntry, "journal entry needs at least two postings")
+	}
+
+	totals := make(map[string]Money)
+	for _, posting := range e.Postings {
+		if posting.AccountID == "" {
+			return Errorf(ErrUnbalancedEntry, "posting has no account")
+		}
+		if posting.Amount <= 0 {
+			return Errorf(ErrUnbalancedEntry, "posting amount must be greater than 0")
+		}
+
+		currency := NormalizeCurrency(posting.Currency)
+		switch posting.Direction {
+		case Debit:
+			totals[currency] = totals[currency].Add(posting.Amount)
+		case Credit:
+			totals[currency] = totals[currency].Sub(posting.Amount)
+		default:
+			return Errorf(ErrUnbalancedEntry, "invalid posting direction: %s", posting.Direction)
+		}
+	}
+
+	for currency, total := range totals {
+		if total != 0 {
+			return Errorf(ErrUnbalancedEntry, "journal entry is unbalanced by %s %s", total, currency)
+		}
+	}
+
+	return nil
+}
+
+// Reversal returns a new entry that undoes e by posting every amount to the
+// opposite side
+func (e *JournalEntry) Reversal(description string) *JournalEntry {
+	postings := make([]Posting, len(e.Postings))
+	for i, posting := range e.Postings {
+		posting.Direction = oppositeDirection(posting.Direction)
+		postings[i] = posting
+	}
+	return NewJournalEntry(description, postings...)
+}
+
+func oppositeDirection(direction string) string {
+	if direction == Debit {
+		return Credit
+	}
+	return Debit
+}
+
+// NewTransactionEntry builds the two-leg entry for a deposit or withdrawal:
+// a deposit debits the cash account and credits the customer, a withdrawal
+// does the opposite
+func NewTransactionEntry(transactionID, accountID, transactionType string, amount Money, currency, description string) (*JournalEntry, error) {
+	customer := Posting{AccountID: accountID, TransactionID: transactionID, Amount: amount, Currency: currency}
+	cash := Posting{AccountID: SystemAccountID(SystemCash, currency), TransactionID: transactionID, Amount: amount, Currency: currency}
+
+	switch transactionType {
+	case "deposit":
+		customer.Direction, cash.Direction = Credit, Debit
+	case "withdraw":
+		customer.Direction, cash.Direction = Debit, Credit
+	default:
+		return nil, Errorf(ErrInvalidTransactionType, "invalid transaction type: %s", transactionType)
+	}
+
+	return NewJournalEntry(description, cash, customer), nil
+}
+
+// NewTransferEntry builds the entry for a transfer between two customer
+// accounts; each posting carries the ID of its transfer leg
+func NewTransferEntry(debitTransactionID, creditTransactionID, fromAccountID, toAccountID string, amount Money, currency, description string) *JournalEntry {
+	return NewJournalEntry(description,
+		Posting{AccountID: fromAccountID, TransactionID: debitTransactionID, Direction: Debit, Amount: amount, Currency: currency},
+		Posting{AccountID: toAccountID, TransactionID: creditTransactionID, Direction: Credit, Amount: amount, Currency: currency},
+	)
+}
+
+// BalanceChange is how a journal entry moved one customer account's balance
+type BalanceChange struct {
+	AccountID       string `json:"account_id"`
+	PreviousBalance Money  `json:"previous_balance"`
+	NewBalance      Money  `json:"new_balance"`
+}
+
+// LedgerBalance compares an account's stored balance with the balance derived
+// from its postings
+type LedgerBalance struct {
+	AccountID     string `json:"account_id"`
+	Currency      string `json:"currency"`
+	Balance       Money  `json:"balance"`        // Stored on the account row
+	PostedBalance Money  `json:"posted_balance"` // Credits minus debits over all postings
+	InBalance     bool   `json:"in_balance"`
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemAccountID(t *testing.T) {
+	id := SystemAccountID(SystemCash, "usd")
+	assert.Equal(t, "sys_cash_usd", id)
+	assert.True(t, IsSystemAccount(id))
+	assert.False(t, IsSystemAccount("acc_12345"))
+
+	kind, currency, ok := ParseSystemAccountID(id)
+	assert.True(t, ok)
+	assert.Equal(t, SystemCash, kind)
+	assert.Equal(t, "USD", currency)
+
+	_, _, ok = ParseSystemAccountID("acc_12345")
+	assert.False(t, ok)
+	_, _, ok = ParseSystemAccountID("sys_cash")
+	assert.False(t, ok)
+}
+
+func TestJournalEntry_Validate(t *testing.T) {
+	amount := MustParseMoney("100.00")
+
+	tests := []struct {
+		name     string
+		postings []Posting
+		wantErr  bool
+	}{
+		{
+			name: "balanced",
+			postings: []Posting{
+				{AccountID: "sys_cash_usd", Direction: Debit, Amount: amount, Currency: "USD"},
+				{AccountID: "acc_1", Direction: Credit, Amount: amount, Currency: "USD"},
+			},
+		},
+		{
+			name: "single posting",
+			postings: []Posting{
+				{AccountID: "acc_1", Direction: Credit, Amount: amount, Currency: "USD"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unbalanced amounts",
+			postings: []Posting{
+				{AccountID: "sys_cash_usd", Direction: Debit, Amount: amount, Currency: "USD"},
+				{AccountID: "acc_1", Direction: Credit, Amount: MustParseMoney("99.99"), Currency: "USD"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "balanced total across currencies",
+			postings: []Posting{
+				{AccountID: "sys_cash_usd", Direction: Debit, Amount: amount, Currency: "USD"},
+				{AccountID: "acc_1", Direction: Credit, Amount: amount, Currency: "EUR"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "zero amount",
+			postings: []Posting{
+				{AccountID: "sys_cash_usd", Direction: Debit, Currency: "USD"},
+				{AccountID: "acc_1", Direction: Credit, Currency: "USD"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid direction",
+			postings: []Posting{
+				{AccountID: "sys_cash_usd", Direction: "sideways", Amount: amount, Currency: "USD"},
+				{AccountID: "acc_1", Direction: Credit, Amount: amount, Currency: "USD"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewJournalEntry("test", tt.postings...).Validate()
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrUnbalancedEntry))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewTransactionEntry(t *testing.T) {
+	amount := MustParseMoney("50.00")
+
+	deposit, err := NewTransactionEntry("txn_1", "acc_1", "deposit", amount, "USD", "Salary")
+	require.NoError(t, err)
+	require.NoError(t, deposit.Validate())
+	assert.Equal(t, "Salary", deposit.Description)
+	assert.Equal(t, "sys_cash_usd", deposit.Postings[0].AccountID)
+	assert.Equal(t, Debit, deposit.Postings[0].Direction)
+	assert.Equal(t, "acc_1", deposit.Postings[1].AccountID)
+	assert.Equal(t, Credit, deposit.Postings[1].Direction)
+	assert.Equal(t, "txn_1", deposit.Postings[1].TransactionID)
+
+	withdrawal, err := NewTransactionEntry("txn_2", "acc_1", "withdraw", amount, "USD", "")
+	require.NoError(t, err)
+	assert.Equal(t, Credit, withdrawal.Postings[0].Direction)
+	assert.Equal(t, Debit, withdrawal.Postings[1].Direction)
+
+	_, err = NewTransactionEntry("txn_3", "acc_1", "transfer", amount, "USD", "")
+	assert.True(t, errors.Is(err, ErrInvalidTransactionType))
+}
+
+func TestJournalEntry_Reversal(t *testing.T) {
+	entry := NewTransferEntry("txn_1", "txn_2", "acc_1", "acc_2", MustParseMoney("10.00"), "EUR", "Rent")
+	reversal := entry.Reversal("rollback of " + entry.ID)
+
+	require.NoError(t, reversal.Validate())
+	assert.NotEqual(t, entry.ID, reversal.ID)
+	assert.Equal(t, "rollback of "+entry.ID, reversal.Description)
+	assert.Equal(t, Credit, reversal.Postings[0].Direction)
+	assert.Equal(t, Debit, reversal.Postings[1].Direction)
+	assert.Equal(t, "txn_1", reversal.Postings[0].TransactionID)
+
+	// The original entry is left untouched
+	assert.Equal(t, Debit, entry.Postings[0].Direction)
+}
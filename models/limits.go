@@ -0,0 +1,157 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Names of the transaction limits, as used in API bodies and limit errors
+const (
+	LimitMaxDeposit          = "max_deposit"
+	LimitMaxWithdrawal       = "max_withdrawal"
+	LimitMaxDailyWithdrawals = "max_daily_withdrawals"
+	LimitMaxDailyWithdrawn   = "max_daily_withdrawn"
+	LimitMaxMonthlyWithdrawn = "max_monthly_withdrawn"
+)
+
+// Windows the velocity limits are measured over. Both are rolling, ending at
+// the moment the transaction is checked.
+const (
+	LimitDailyWindow   = 24 * time.Hour
+	LimitMonthlyWindow = 30 * 24 * time.Hour
+)
+
+// TransactionLimits caps what can be paid into or taken out of an account.
+// Withdrawals and outgoing transfers both count as withdrawals. A zero value
+// means the limit is not enforced.
+type TransactionLimits struct {
+	MaxDeposit          Money `json:"max_deposit"`           // Largest single deposit
+	MaxWithdrawal       Money `json:"max_withdrawal"`        // Largest single withdrawal
+	MaxDailyWithdrawals int64 `json:"max_daily_withdrawals"` // Withdrawals per rolling 24 hours
+	MaxDailyWithdrawn   Money `json:"max_daily_withdrawn"`   // Total withdrawn per rolling 24 hours
+	MaxMonthlyWithdrawn Money `json:"max_monthly_withdrawn"` // Total withdrawn per rolling 30 days
+}
+
+// HasVelocityLimits reports whether checking a withdrawal needs the account's
+// recent withdrawals
+func (l TransactionLimits) HasVelocityLimits() bool {
+	return l.MaxDailyWithdrawals > 0 || l.MaxDailyWithdrawn > 0 || l.MaxMonthlyWithdrawn > 0
+}
+
+// CheckAmount checks a single deposit or withdrawal of amount against the
+// per-transaction limits
+func (l TransactionLimits) CheckAmount(accountID string, withdrawal bool, amount Money) error {
+	if withdrawal {
+		if l.MaxWithdrawal > 0 && amount > l.MaxWithdrawal {
+			return &LimitExceededError{AccountID: accountID, Limit: LimitMaxWithdrawal, Max: l.MaxWithdrawal.String(), Attempted: amount.String()}
+		}
+		return nil
+	}
+
+	if l.MaxDeposit > 0 && amount > l.MaxDeposit {
+		return &LimitExceededError{AccountID: accountID, Limit: LimitMaxDeposit, Max: l.MaxDeposit.String(), Attempted: amount.String()}
+	}
+	return nil
+}
+
+// CheckUsage checks a withdrawal of amount on top of the account's recent
+// withdrawals against the velocity limits
+func (l TransactionLimits) CheckUsage(accountID string, amount Money, usage *LimitUsage) error {
+	if count := usage.DailyWithdrawals + 1; l.MaxDailyWithdrawals > 0 && count > l.MaxDailyWithdrawals {
+		return &LimitExceededError{AccountID: accountID, Limit: LimitMaxDailyWithdrawals,
+			Max: fmt.Sprint(l.MaxDailyWithdrawals), Attempted: fmt.Sprint(count)}
+	}
+	if total := usage.DailyWithdrawn.Add(amount); l.MaxDailyWithdrawn > 0 && total > l.MaxDailyWithdrawn {
+		return &LimitExceededError{AccountID: accountID, Limit: LimitMaxDailyWithdrawn, Max: l.MaxDailyWithdrawn.String(), Attempted: total.String()}
+	}
+	if total := usage.MonthlyWithdrawn.Add(amount); l.MaxMonthlyWithdrawn > 0 && total > l.MaxMonthlyWithdrawn {
+		return &LimitExceededError{AccountID: accountID, Limit: LimitMaxMonthlyWithdrawn, Max: l.MaxMonthlyWithdrawn.String(), Attempted: total.String()}
+	}
+	return nil
+}
+
+// LimitOverrides replaces some of the default limits for one account. A nil
+// field keeps the default; zero removes the limit for the account.
+type LimitOverrides struct {
+	MaxDeposit          *Money `json:"max_deposit"`
+	MaxWithdrawal       *Money `json:"max_withdrawal"`
+	MaxDailyWithdrawals *int64 `json:"max_daily_withdrawals"`
+	MaxDailyWithdrawn   *Money `json:"max_daily_withdrawn"`
+	MaxMonthlyWithdrawn *Money `json:"max_monthly_withdrawn"`
+}
+
+// IsEmpty reports whether every limit is left at its default
+func (o *LimitOverrides) IsEmpty() bool {
+	return o == nil || (o.MaxDeposit == nil && o.MaxWithdrawal == nil && o.MaxDailyWithdrawals == nil &&
+		o.MaxDailyWithdrawn == nil && o.MaxMonthlyWithdrawn == nil)
+}
+
+// Validate rejects negative limits
+func (o *LimitOverrides) Validate() error {
+	for name, value := range map[string]*Money{
+		LimitMaxDeposit:          o.MaxDeposit,
+		LimitMaxWithdrawal:       o.MaxWithdrawal,
+		LimitMaxDailyWithdrawn:   o.MaxDailyWithdrawn,
+		LimitMaxMonthlyWithdrawn: o.MaxMonthlyWithdrawn,
+	} {
+		if value != nil && value.IsNegative() {
+			return Errorf(ErrInvalidAmount, "%s cannot be negative", name)
+		}
+	}
+	if o.MaxDailyWithdrawals != nil && *o.MaxDailyWithdrawals < 0 {
+		return Errorf(ErrInvalidRequest, "%s cannot be negative", LimitMaxDailyWithdrawals)
+	}
+	return nil
+}
+
+// Apply returns defaults with the overrides applied. A nil o applies none.
+func (o *LimitOverrides) Apply(defaults TransactionLimits) TransactionLimits {
+	limits := defaults
+	if o == nil {
+		return limits
+	}
+	if o.MaxDeposit != nil {
+		limits.MaxDeposit = *o.MaxDeposit
+	}
+	if o.MaxWithdrawal != nil {
+		limits.MaxWithdrawal = *o.MaxWithdrawal
+	}
+	if o.MaxDailyWithdrawals != nil {
+		limits.MaxDailyWithdrawals = *o.MaxDailyWithdrawals
+	}
+	if o.MaxDailyWithdrawn != nil {
+		limits.MaxDailyWithdrawn = *o.MaxDailyWithdrawn
+	}
+	if o.MaxMonthlyWithdrawn != nil {
+		limits.MaxMonthlyWithdrawn = *o.MaxMonthlyWithdrawn
+	}
+	return limits
+}
+
+// AccountLimits shows an account's overrides and the limits in force for it
+type AccountLimits struct {
+	AccountID string            `json:"account_id"`
+	Defaults  TransactionLimits `json:"defaults"`
+	Overrides LimitOverrides    `json:"overrides"`
+	Effective TransactionLimits `json:"effective"`
+}
+
+// LimitUsage is what an account has withdrawn within the limit windows
+type LimitUsage struct {
+	DailyWithdrawals int64 `json:"daily_withdrawals"`
+	DailyWithdrawn   Money `json:"daily_withdrawn"`
+	MonthlyWithdrawn Money `json:"monthly_withdrawn"`
+}
+
+// LimitBreach records a transaction that was rejected for exceeding a limit
+type LimitBreach struct {
+	ID            int64     `json:"id"`
+	AccountID     string    `json:"account_id"`
+	TransactionID string    `json:"transaction_id,omitempty"`
+	Type          string    `json:"type"` // "deposit", "withdraw" or "transfer_out"
+	Amount        Money     `json:"amount"`
+	Limit         string    `json:"limit"`
+	Max           string    `json:"max"`
+	Attempted     string    `json:"attempted"`
+	RecordedAt    time.Time `json:"recorded_at"`
+}
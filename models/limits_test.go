@@ -0,0 +1,76 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionLimits_CheckAmount(t *testing.T) {
+	limits := TransactionLimits{MaxDeposit: MustParseMoney("1000.00"), MaxWithdrawal: MustParseMoney("200.00")}
+
+	assert.NoError(t, limits.CheckAmount("acc_1", false, MustParseMoney("1000.00")))
+	assert.NoError(t, limits.CheckAmount("acc_1", true, MustParseMoney("200.00")))
+
+	err := limits.CheckAmount("acc_1", true, MustParseMoney("200.01"))
+	var exceeded *LimitExceededError
+	require.True(t, errors.As(err, &exceeded))
+	assert.Equal(t, LimitMaxWithdrawal, exceeded.Limit)
+	assert.True(t, errors.Is(err, ErrLimitExceeded))
+
+	// Zero limits are not enforced
+	assert.NoError(t, TransactionLimits{}.CheckAmount("acc_1", false, MustParseMoney("999999999.99")))
+}
+
+func TestTransactionLimits_CheckUsage(t *testing.T) {
+	limits := TransactionLimits{
+		MaxDailyWithdrawals: 3,
+		MaxDailyWithdrawn:   MustParseMoney("500.00"),
+		MaxMonthlyWithdrawn: MustParseMoney("2000.00"),
+	}
+	assert.True(t, limits.HasVelocityLimits())
+	assert.False(t, TransactionLimits{MaxDeposit: MustParseMoney("1.00")}.HasVelocityLimits())
+
+	tests := []struct {
+		name  string
+		usage LimitUsage
+		limit string
+	}{
+		{name: "within limits", usage: LimitUsage{DailyWithdrawals: 2, DailyWithdrawn: MustParseMoney("400.00"), MonthlyWithdrawn: MustParseMoney("1900.00")}},
+		{name: "too many withdrawals", usage: LimitUsage{DailyWithdrawals: 3}, limit: LimitMaxDailyWithdrawals},
+		{name: "daily total", usage: LimitUsage{DailyWithdrawn: MustParseMoney("400.01")}, limit: LimitMaxDailyWithdrawn},
+		{name: "monthly total", usage: LimitUsage{MonthlyWithdrawn: MustParseMoney("1900.01")}, limit: LimitMaxMonthlyWithdrawn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := limits.CheckUsage("acc_1", MustParseMoney("100.00"), &tt.usage)
+			if tt.limit == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var exceeded *LimitExceededError
+			require.True(t, errors.As(err, &exceeded))
+			assert.Equal(t, tt.limit, exceeded.Limit)
+		})
+	}
+}
+
+func TestLimitOverrides_Apply(t *testing.T) {
+	defaults := TransactionLimits{MaxDeposit: MustParseMoney("1000.00"), MaxDailyWithdrawals: 5}
+
+	var none *LimitOverrides
+	assert.Equal(t, defaults, none.Apply(defaults))
+	assert.True(t, none.IsEmpty())
+
+	unlimited := Money(0)
+	count := int64(20)
+	overrides := &LimitOverrides{MaxDeposit: &unlimited, MaxDailyWithdrawals: &count}
+	assert.False(t, overrides.IsEmpty())
+	assert.Equal(t, TransactionLimits{MaxDailyWithdrawals: 20}, overrides.Apply(defaults))
+
+	negative := int64(-1)
+	assert.True(t, errors.Is((&LimitOverrides{MaxDailyWithdrawals: &negative}).Validate(), ErrInvalidRequest))
+}
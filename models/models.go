@@ -10,9 +10,33 @@ import (
 type Account struct {
 	ID        string    `json:"id" bson:"id"`
 	OwnerName string    `json:"owner_name" bson:"ownername"`
-	Balance   float64   `json:"balance" bson:"balance"`
+	Balance   Money     `json:"balance" bson:"balance"`   // Ledger balance
+	Currency  string    `json:"currency" bson:"currency"` // ISO 4217 code, e.g. "USD"
+	Status    string    `json:"status" bson:"status"`     // "active", "frozen" or "closed"
 	CreatedAt time.Time `json:"created_at" bson:"createdat"`
 	UpdatedAt time.Time `json:"updated_at" bson:"updatedat"`
+
+	// Subject of the caller the account belongs to; empty for accounts
+	// created before authentication, which only admins and operators reach
+	OwnerID string `json:"owner_id,omitempty" bson:"ownerid"`
+
+	// How far debits may take the balance below zero; zero means no overdraft
+	OverdraftLimit Money `json:"overdraft_limit" bson:"overdraftlimit"`
+	Overdrawn      bool  `json:"overdrawn" bson:"-"` // Balance is below zero
+
+	// Funds reserved by active holds, and what can still be debited: the
+	// ledger balance less those holds plus the overdraft limit
+	HeldBalance      Money `json:"held_balance" bson:"-"`
+	AvailableBalance Money `json:"available_balance" bson:"-"`
+
+	// Limits set for this account in place of the defaults; nil when it has none
+	LimitOverrides *LimitOverrides `json:"-" bson:"-"`
+}
+
+// Available returns what can still be debited from the account: the ledger
+// balance less the funds reserved by holds, plus the overdraft limit
+func (a *Account) Available() Money {
+	return a.Balance.Sub(a.HeldBalance).Add(a.OverdraftLimit)
 }
 
 // Transaction represents a transaction log
@@ -21,26 +45,202 @@ type Transaction struct {
 	TransactionID   string    `json:"transaction_id" bson:"transactionid"`
 	AccountID       string    `json:"account_id" bson:"accountid"`
 	Type            string    `json:"type" bson:"type"` // "deposit" or "withdraw"
-	Amount          float64   `json:"amount" bson:"amount"`
-	PreviousBalance float64   `json:"previous_balance" bson:"previousbalance"`
-	NewBalance      float64   `json:"new_balance" bson:"newbalance"`
+	Amount          Money     `json:"amount" bson:"amount"`
+	PreviousBalance Money     `json:"previous_balance" bson:"previousbalance"`
+	NewBalance      Money     `json:"new_balance" bson:"newbalance"`
+	Currency        string    `json:"currency,omitempty" bson:"currency,omitempty"`
 	Description     string    `json:"description" bson:"description"`
 	Timestamp       time.Time `json:"timestamp" bson:"timestamp"`
-	Status          string    `json:"status" bson:"status"`                                  // "pending", "completed", "failed"
+	Status          string    `json:"status" bson:"status"`                                  // "pending", "completed", "failed", "partially_refunded", "reversed"
 	ErrorMessage    string    `json:"error_message,omitempty" bson:"errormessage,omitempty"` // Added for failed transactions
+	IdempotencyKey  string    `json:"idempotency_key,omitempty" bson:"idempotencykey,omitempty"`
+	Overdrawn       bool      `json:"overdrawn,omitempty" bson:"overdrawn,omitempty"` // A debit that left the balance below zero
+
+	// Outbox holds the queue message for a pending record; it is written in the
+	// same document so the intent to publish can never be lost
+	Outbox *OutboxEntry `json:"-" bson:"outbox,omitempty"`
+
+	// Transfer legs only: both legs share the transfer ID
+	TransferID            string `json:"transfer_id,omitempty" bson:"transferid,omitempty"`
+	CounterpartyAccountID string `json:"counterparty_account_id,omitempty" bson:"counterpartyaccountid,omitempty"`
+
+	// Scheduled transactions name the schedule that created them
+	ScheduleID string `json:"schedule_id,omitempty" bson:"scheduleid,omitempty"`
+
+	// Reversals: the compensating transaction names the original it reverses,
+	// and the original keeps the running total of its refunds. Once they add
+	// up to its amount it is reversed and names the refund that completed it.
+	ReversalOf     string     `json:"reversal_of,omitempty" bson:"reversalof,omitempty"`
+	RefundedAmount Money      `json:"refunded_amount,omitempty" bson:"refundedamount,omitempty"`
+	Refunds        []string   `json:"refunds,omitempty" bson:"refunds,omitempty"`
+	ReversedBy     string     `json:"reversed_by,omitempty" bson:"reversedby,omitempty"`
+	ReversedAt     *time.Time `json:"reversed_at,omitempty" bson:"reversedat,omitempty"`
+
+	// Tamper evidence: a completed record is sealed with its position in the
+	// account's hash chain, the previous record's hash and its own
+	ChainSeq int64  `json:"chain_seq,omitempty" bson:"chainseq,omitempty"`
+	PrevHash string `json:"prev_hash,omitempty" bson:"prevhash,omitempty"`
+	Hash     string `json:"hash,omitempty" bson:"hash,omitempty"`
+}
+
+// MarkOverdrawn flags a completed debit that left the account balance below
+// zero, i.e. one that drew on the overdraft
+func (t *Transaction) MarkOverdrawn() {
+	t.Overdrawn = t.Status == "completed" && t.SignedAmount().IsNegative() && t.NewBalance.IsNegative()
+}
+
+// OutboxEntry is a queue message waiting to be published by the outbox relay
+type OutboxEntry struct {
+	Payload       []byte     `bson:"payload"` // JSON-encoded queue message
+	Dispatched    bool       `bson:"dispatched"`
+	Attempts      int        `bson:"attempts"`
+	NextAttemptAt time.Time  `bson:"nextattemptat"`
+	DispatchedAt  *time.Time `bson:"dispatchedat,omitempty"`
+	LastError     string     `bson:"lasterror,omitempty"`
+	Requeues      int        `bson:"requeues,omitempty"`    // Times the pending reaper re-enqueued the message
+	TraceParent   string     `bson:"traceparent,omitempty"` // Trace context of the request that queued the message
+}
+
+// IsInFlight reports whether the message is still waiting to be published, or
+// was published after cutoff and may still be picked up by a worker
+func (e *OutboxEntry) IsInFlight(cutoff time.Time) bool {
+	if !e.Dispatched {
+		return true
+	}
+	return e.DispatchedAt != nil && e.DispatchedAt.After(cutoff)
+}
+
+// NewOutboxEntry creates an entry that is due for publishing immediately
+func NewOutboxEntry(payload []byte) *OutboxEntry {
+	return &OutboxEntry{
+		Payload:       payload,
+		NextAttemptAt: time.Now(),
+	}
+}
+
+// AppliedTransaction records, next to the balances, that a queued transaction
+// has changed an account balance
+type AppliedTransaction struct {
+	TransactionID   string    `json:"transaction_id"`
+	AccountID       string    `json:"account_id"`
+	PreviousBalance Money     `json:"previous_balance"`
+	NewBalance      Money     `json:"new_balance"`
+	AppliedAt       time.Time `json:"applied_at"`
+}
+
+// ReaperStats summarizes what the stuck-pending reaper has done since startup
+type ReaperStats struct {
+	Runs             int64      `json:"runs"`
+	LastRunAt        *time.Time `json:"last_run_at,omitempty"`
+	Scanned          int64      `json:"scanned"`
+	Completed        int64      `json:"completed"`         // Balance change had been applied; record marked completed
+	Requeued         int64      `json:"requeued"`          // Message handed back to the outbox relay
+	Failed           int64      `json:"failed"`            // Gave up; balance was never changed
+	AwaitingDispatch int64      `json:"awaiting_dispatch"` // Message still queued or recently published
+	Errors           int64      `json:"errors"`
+	StalePending     int64      `json:"stale_pending"` // Pending records older than MaxAge right now
+	MaxAgeSeconds    int64      `json:"max_age_seconds"`
+}
+
+// Transfer groups the linked debit ("transfer_out") and credit ("transfer_in")
+// legs of an account-to-account transfer
+type Transfer struct {
+	TransferID    string       `json:"transfer_id"`
+	FromAccountID string       `json:"from_account_id"`
+	ToAccountID   string       `json:"to_account_id"`
+	Amount        Money        `json:"amount"`
+	Currency      string       `json:"currency"`
+	Description   string       `json:"description"`
+	Status        string       `json:"status"` // "pending", "completed", "failed"
+	Debit         *Transaction `json:"debit"`
+	Credit        *Transaction `json:"credit"`
 }
 
 // CreateAccountRequest represents the request body for creating an account
 type CreateAccountRequest struct {
-	OwnerName      string  `json:"owner_name"`
-	InitialBalance float64 `json:"initial_balance"`
+	OwnerName      string `json:"owner_name"`
+	InitialBalance Money  `json:"initial_balance"`
+	Currency       string `json:"currency"` // Optional, defaults to DefaultCurrency
+	OwnerID        string `json:"owner_id"` // Admins only; other callers own the accounts they create
 }
 
 // TransactionRequest represents the request body for transactions
 type TransactionRequest struct {
-	Type        string  `json:"type"` // "deposit" or "withdraw"
-	Amount      float64 `json:"amount"`
-	Description string  `json:"description"`
+	Type        string `json:"type"` // "deposit" or "withdraw"
+	Amount      Money  `json:"amount"`
+	Currency    string `json:"currency"` // Optional, must match the account currency when set
+	Description string `json:"description"`
+
+	// IdempotencyKey is taken from the Idempotency-Key header, never the body
+	IdempotencyKey string `json:"-"`
+	// TransactionID is reserved when the idempotency key is claimed, so a
+	// stale claim can be checked against the ledger before it is reclaimed
+	TransactionID string `json:"-"`
+}
+
+// ReverseTransactionRequest represents the request body for reversing a
+// transaction. A zero amount reverses the full original amount.
+type ReverseTransactionRequest struct {
+	Amount Money  `json:"amount"`
+	Reason string `json:"reason"`
+}
+
+// OverdraftLimitRequest represents the request body for setting an account's
+// overdraft limit
+type OverdraftLimitRequest struct {
+	OverdraftLimit Money `json:"overdraft_limit"`
+}
+
+// AccountStatusRequest represents the request body for freezing, unfreezing
+// or closing an account
+type AccountStatusRequest struct {
+	Reason string `json:"reason"`
+}
+
+// TransferRequest represents the request body for account-to-account transfers
+type TransferRequest struct {
+	FromAccountID string `json:"from_account_id"`
+	ToAccountID   string `json:"to_account_id"`
+	Amount        Money  `json:"amount"`
+	Currency      string `json:"currency"` // Optional, must match both account currencies when set
+	Description   string `json:"description"`
+}
+
+// IdempotencyRecord remembers the outcome of a request sent with an
+// Idempotency-Key header so that retries get the original response back
+type IdempotencyRecord struct {
+	Key           string    `json:"key" bson:"key"`
+	AccountID     string    `json:"account_id" bson:"accountid"`
+	Fingerprint   string    `json:"fingerprint" bson:"fingerprint"`  // SHA-256 of the normalized request
+	StatusCode    int       `json:"status_code" bson:"statuscode"`   // 0 while the original request is in flight
+	ResponseBody  []byte    `json:"-" bson:"responsebody,omitempty"` // Raw JSON returned to the client
+	TransactionID string    `json:"transaction_id,omitempty" bson:"transactionid,omitempty"`
+	CreatedAt     time.Time `json:"created_at" bson:"createdat"`
+	UpdatedAt     time.Time `json:"updated_at" bson:"updatedat"`
+}
+
+// IsCompleted reports whether the original request has finished and its response was stored
+func (r *IdempotencyRecord) IsCompleted() bool {
+	return r.StatusCode != 0
+}
+
+// DeadLetter is a message RabbitMQ dead-lettered from the transaction queue,
+// kept until an operator replays or discards it
+type DeadLetter struct {
+	ID          string     `json:"id" bson:"_id"`
+	MessageID   string     `json:"message_id" bson:"messageid"` // Transaction ID, or transfer ID for transfers
+	Reason      string     `json:"reason" bson:"reason"`        // x-death reason ("rejected", "expired", "maxlen") or "retries_exhausted"
+	Queue       string     `json:"queue" bson:"queue"`          // Queue the message died in
+	RoutingKeys []string   `json:"routing_keys,omitempty" bson:"routingkeys,omitempty"`
+	DeathCount  int64      `json:"death_count" bson:"deathcount"`
+	DiedAt      time.Time  `json:"died_at" bson:"diedat"`
+	Attempts    int64      `json:"attempts,omitempty" bson:"attempts,omitempty"`    // Processing attempts before the worker gave up
+	LastError   string     `json:"last_error,omitempty" bson:"lasterror,omitempty"` // Error from the last attempt
+	Body        string     `json:"body" bson:"body"`                                // Original message payload
+	Status      string     `json:"status" bson:"status"`                            // "dead", "replayed" or "discarded"
+	ReceivedAt  time.Time  `json:"received_at" bson:"receivedat"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty" bson:"resolvedat,omitempty"`
+	DedupKey    string     `json:"-" bson:"dedupkey"` // Identifies one death, so a redelivered dead letter is stored once
 }
 
 // Helper functions to generate IDs
@@ -51,3 +251,15 @@ func NewAccountID() string {
 func NewTransactionID() string {
 	return "txn_" + uuid.New().String()
 }
+
+func NewTransferID() string {
+	return "trf_" + uuid.New().String()
+}
+
+func NewJournalEntryID() string {
+	return "jrn_" + uuid.New().String()
+}
+
+func NewDeadLetterID() string {
+	return "dlq_" + uuid.New().String()
+}
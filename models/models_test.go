@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccount_Available(t *testing.T) {
+	account := &Account{
+		Balance:        MustParseMoney("100.00"),
+		HeldBalance:    MustParseMoney("30.00"),
+		OverdraftLimit: MustParseMoney("250.00"),
+	}
+	assert.Equal(t, MustParseMoney("320.00"), account.Available())
+
+	// Overdrawn past a lowered limit: nothing can be debited
+	account = &Account{Balance: MustParseMoney("-80.00"), OverdraftLimit: MustParseMoney("50.00")}
+	assert.Equal(t, MustParseMoney("-30.00"), account.Available())
+}
+
+func TestTransaction_MarkOverdrawn(t *testing.T) {
+	tests := []struct {
+		name       string
+		txType     string
+		status     string
+		newBalance string
+		overdrawn  bool
+	}{
+		{name: "withdrawal into overdraft", txType: "withdraw", status: "completed", newBalance: "-20.00", overdrawn: true},
+		{name: "transfer out into overdraft", txType: "transfer_out", status: "completed", newBalance: "-0.01", overdrawn: true},
+		{name: "withdrawal to zero", txType: "withdraw", status: "completed", newBalance: "0", overdrawn: false},
+		{name: "deposit while overdrawn", txType: "deposit", status: "completed", newBalance: "-10.00", overdrawn: false},
+		{name: "pending withdrawal", txType: "withdraw", status: "pending", newBalance: "-20.00", overdrawn: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transaction := &Transaction{Type: tt.txType, Status: tt.status, Amount: MustParseMoney("25.00"), NewBalance: MustParseMoney(tt.newBalance)}
+			transaction.MarkOverdrawn()
+			assert.Equal(t, tt.overdrawn, transaction.Overdrawn)
+		})
+	}
+}
@@ -0,0 +1,294 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MoneyScale is the number of decimal places Money keeps. Four places covers
+// every ISO 4217 minor unit (0, 2 or 3 digits) without any rounding.
+const MoneyScale = 4
+
+const moneyFactor = 10000 // 10^MoneyScale
+
+// Money is an exact monetary amount stored as an integer number of
+// ten-thousandths. It replaces float64 everywhere a balance or amount is held,
+// so values round-trip unchanged through JSON, Postgres NUMERIC and Mongo.
+type Money int64
+
+// ParseMoney parses a plain decimal string such as "1500", "-12.5" or "0.0125"
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("invalid amount: empty value")
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	whole, fraction, hasPoint := strings.Cut(s, ".")
+	if whole == "" && fraction == "" || hasPoint && fraction == "" {
+		return 0, fmt.Errorf("invalid amount: %q", s)
+	}
+	if len(fraction) > MoneyScale {
+		// Allow trailing zeros beyond the scale, e.g. "1.500000"
+		if strings.Trim(fraction[MoneyScale:], "0") != "" {
+			return 0, fmt.Errorf("invalid amount: more than %d decimal places", MoneyScale)
+		}
+		fraction = fraction[:MoneyScale]
+	}
+
+	for _, part := range []string{whole, fraction} {
+		for _, char := range part {
+			if char < '0' || char > '9' {
+				return 0, fmt.Errorf("invalid amount: %q", s)
+			}
+		}
+	}
+
+	var units int64
+	if whole != "" {
+		parsed, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil || parsed > math.MaxInt64/moneyFactor {
+			return 0, fmt.Errorf("invalid amount: %q is out of range", s)
+		}
+		units = parsed * moneyFactor
+	}
+
+	if fraction != "" {
+		fraction += strings.Repeat("0", MoneyScale-len(fraction))
+		parsed, _ := strconv.ParseInt(fraction, 10, 64)
+		if units > math.MaxInt64-parsed {
+			return 0, fmt.Errorf("invalid amount: %q is out of range", s)
+		}
+		units += parsed
+	}
+
+	if negative {
+		units = -units
+	}
+	return Money(units), nil
+}
+
+// MustParseMoney is like ParseMoney but panics on invalid input. Intended for
+// constants and tests.
+func MustParseMoney(s string) Money {
+	m, err := ParseMoney(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// MoneyFromFloat converts a float64 to Money, rounding to MoneyScale places.
+// Only used for legacy data that was stored as a float.
+func MoneyFromFloat(f float64) Money {
+	return Money(math.Round(f * moneyFactor))
+}
+
+// NewMoneyFromMinorUnits builds Money from an integer count of minor units with
+// the given number of decimals, e.g. (1050, 2) is 10.50.
+func NewMoneyFromMinorUnits(units int64, decimals int) Money {
+	for i := decimals; i < MoneyScale; i++ {
+		units *= 10
+	}
+	return Money(units)
+}
+
+func (m Money) Add(other Money) Money { return m + other }
+func (m Money) Sub(other Money) Money { return m - other }
+func (m Money) Neg() Money            { return -m }
+
+func (m Money) IsZero() bool     { return m == 0 }
+func (m Money) IsPositive() bool { return m > 0 }
+func (m Money) IsNegative() bool { return m < 0 }
+
+// DecimalPlaces returns how many decimal places are needed to represent m exactly
+func (m Money) DecimalPlaces() int {
+	fraction := int64(m) % moneyFactor
+	if fraction < 0 {
+		fraction = -fraction
+	}
+
+	places := MoneyScale
+	for places > 0 && fraction%10 == 0 {
+		fraction /= 10
+		places--
+	}
+	return places
+}
+
+// String formats m with at least two decimal places, e.g. "1500.00" or "0.125"
+func (m Money) String() string {
+	return m.StringFixed(max(2, m.DecimalPlaces()))
+}
+
+// StringFixed formats m with exactly the given number of decimal places
+// (truncating any extra precision).
+func (m Money) StringFixed(places int) string {
+	units := int64(m)
+	sign := ""
+	if units < 0 {
+		sign = "-"
+		units = -units
+	}
+
+	whole := units / moneyFactor
+	fraction := fmt.Sprintf("%0*d", MoneyScale, units%moneyFactor)
+
+	if places <= 0 {
+		return fmt.Sprintf("%s%d", sign, whole)
+	}
+	if places > MoneyScale {
+		fraction += strings.Repeat("0", places-MoneyScale)
+	}
+	return fmt.Sprintf("%s%d.%s", sign, whole, fraction[:places])
+}
+
+// MarshalJSON encodes m as a JSON number with no float conversion
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a numeric string
+func (m *Money) UnmarshalJSON(data []byte) error {
+	text := strings.TrimSpace(string(data))
+	if text == "null" {
+		return nil
+	}
+	text = strings.Trim(text, `"`)
+
+	// JSON allows exponents, which ParseMoney does not; fall back to an exact
+	// big.Rat conversion for those. Like a plain decimal, the value may not
+	// have more than MoneyScale places.
+	if strings.ContainsAny(text, "eE") {
+		rat, ok := new(big.Rat).SetString(text)
+		if !ok {
+			return fmt.Errorf("invalid amount: %s", text)
+		}
+		if !new(big.Rat).Mul(rat, big.NewRat(moneyFactor, 1)).IsInt() {
+			return fmt.Errorf("invalid amount: more than %d decimal places", MoneyScale)
+		}
+		text = rat.FloatString(MoneyScale)
+	}
+
+	parsed, err := ParseMoney(text)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value implements driver.Valuer so Postgres receives an exact NUMERIC literal
+func (m Money) Value() (driver.Value, error) {
+	return m.StringFixed(MoneyScale), nil
+}
+
+// Scan implements sql.Scanner for NUMERIC columns
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		parsed, err := ParseMoney(string(v))
+		if err != nil {
+			return err
+		}
+		*m = parsed
+	case string:
+		parsed, err := ParseMoney(v)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+	case int64:
+		*m = Money(v * moneyFactor)
+	case float64:
+		*m = MoneyFromFloat(v)
+	case nil:
+		*m = 0
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+	return nil
+}
+
+// MarshalBSONValue stores m as a Decimal128 so Mongo keeps the exact value
+func (m Money) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	d, err := primitive.ParseDecimal128(m.StringFixed(MoneyScale))
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to encode amount: %w", err)
+	}
+	return bson.MarshalValue(d)
+}
+
+// UnmarshalBSONValue decodes Decimal128 values as well as the doubles and
+// integers written before Money was introduced
+func (m *Money) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	raw := bson.RawValue{Type: t, Value: data}
+
+	switch t {
+	case bsontype.Decimal128:
+		parsed, err := moneyFromDecimal128(raw.Decimal128())
+		if err != nil {
+			return err
+		}
+		*m = parsed
+	case bsontype.Double:
+		*m = MoneyFromFloat(raw.Double())
+	case bsontype.Int32:
+		*m = Money(int64(raw.Int32()) * moneyFactor)
+	case bsontype.Int64:
+		*m = Money(raw.Int64() * moneyFactor)
+	case bsontype.String:
+		parsed, err := ParseMoney(raw.StringValue())
+		if err != nil {
+			return err
+		}
+		*m = parsed
+	case bsontype.Null, bsontype.Undefined:
+		*m = 0
+	default:
+		return fmt.Errorf("cannot decode BSON %s into Money", t)
+	}
+	return nil
+}
+
+func moneyFromDecimal128(d primitive.Decimal128) (Money, error) {
+	coefficient, exponent, err := d.BigInt()
+	if err != nil {
+		return 0, fmt.Errorf("invalid decimal amount: %w", err)
+	}
+
+	// value = coefficient * 10^exponent; rescale to MoneyScale places
+	shift := exponent + MoneyScale
+	ten := big.NewInt(10)
+	if shift >= 0 {
+		coefficient.Mul(coefficient, new(big.Int).Exp(ten, big.NewInt(int64(shift)), nil))
+	} else {
+		divisor := new(big.Int).Exp(ten, big.NewInt(int64(-shift)), nil)
+		remainder := new(big.Int)
+		coefficient.QuoRem(coefficient, divisor, remainder)
+		if remainder.Sign() != 0 {
+			return 0, fmt.Errorf("invalid decimal amount: more than %d decimal places", MoneyScale)
+		}
+	}
+
+	if !coefficient.IsInt64() {
+		return 0, fmt.Errorf("invalid decimal amount: out of range")
+	}
+	return Money(coefficient.Int64()), nil
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestParseMoney(t *testing.T) {
+	testCases := []struct {
+		input       string
+		expected    Money
+		expectError bool
+	}{
+		{"0", 0, false},
+		{"1500", 15000000, false},
+		{"1500.75", 15007500, false},
+		{"-12.5", -125000, false},
+		{".5", 5000, false},
+		{"0.125", 1250, false},
+		{"1.500000", 15000, false},
+		{"0.00001", 0, true},
+		{"", 0, true},
+		{"abc", 0, true},
+		{"1.2.3", 0, true},
+		{"5.", 0, true},
+		{"922337203685477.5807", Money(math.MaxInt64), false},
+		{"922337203685477.9999", 0, true},
+		{"922337203685478", 0, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			m, err := ParseMoney(tc.input)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, m)
+		})
+	}
+}
+
+func TestMoney_StringAndDecimalPlaces(t *testing.T) {
+	assert.Equal(t, "1500.00", MustParseMoney("1500").String())
+	assert.Equal(t, "0.10", MustParseMoney("0.1").String())
+	assert.Equal(t, "0.125", MustParseMoney("0.125").String())
+	assert.Equal(t, "-3.50", MustParseMoney("-3.5").String())
+	assert.Equal(t, "12", MustParseMoney("12.99").StringFixed(0))
+
+	assert.Equal(t, 0, MustParseMoney("10").DecimalPlaces())
+	assert.Equal(t, 2, MustParseMoney("10.01").DecimalPlaces())
+	assert.Equal(t, 3, MustParseMoney("-10.001").DecimalPlaces())
+}
+
+func TestMoney_ExactArithmetic(t *testing.T) {
+	// 0.1 + 0.2 is the classic float64 drift case
+	sum := MustParseMoney("0.1").Add(MustParseMoney("0.2"))
+	assert.Equal(t, MustParseMoney("0.3"), sum)
+	assert.Equal(t, MustParseMoney("-0.3"), sum.Neg())
+}
+
+func TestMoney_JSON(t *testing.T) {
+	var req TransactionRequest
+	err := json.Unmarshal([]byte(`{"type":"deposit","amount":100.10}`), &req)
+	assert.NoError(t, err)
+	assert.Equal(t, MustParseMoney("100.10"), req.Amount)
+
+	err = json.Unmarshal([]byte(`{"amount":"42.5"}`), &req)
+	assert.NoError(t, err)
+	assert.Equal(t, MustParseMoney("42.5"), req.Amount)
+
+	err = json.Unmarshal([]byte(`{"amount":1.5e2}`), &req)
+	assert.NoError(t, err)
+	assert.Equal(t, MustParseMoney("150"), req.Amount)
+
+	err = json.Unmarshal([]byte(`{"amount":1.123456}`), &req)
+	assert.Error(t, err)
+
+	err = json.Unmarshal([]byte(`{"amount":1.23456e0}`), &req)
+	assert.Error(t, err)
+
+	err = json.Unmarshal([]byte(`{"amount":12345e-4}`), &req)
+	assert.NoError(t, err)
+	assert.Equal(t, MustParseMoney("1.2345"), req.Amount)
+
+	encoded, err := json.Marshal(Account{Balance: MustParseMoney("1500.75")})
+	assert.NoError(t, err)
+	assert.Contains(t, string(encoded), `"balance":1500.75`)
+}
+
+func TestMoney_BSON(t *testing.T) {
+	original := Transaction{Amount: MustParseMoney("19.99"), NewBalance: MustParseMoney("1000000.01")}
+
+	data, err := bson.Marshal(original)
+	assert.NoError(t, err)
+
+	var decoded Transaction
+	assert.NoError(t, bson.Unmarshal(data, &decoded))
+	assert.Equal(t, original.Amount, decoded.Amount)
+	assert.Equal(t, original.NewBalance, decoded.NewBalance)
+}
+
+func TestMoney_BSONLegacyFloats(t *testing.T) {
+	// Documents written before Money was introduced hold doubles and ints
+	legacy, err := bson.Marshal(bson.M{
+		"amount":          250.1,
+		"previousbalance": int32(500),
+		"newbalance":      int64(750),
+	})
+	assert.NoError(t, err)
+
+	var decoded Transaction
+	assert.NoError(t, bson.Unmarshal(legacy, &decoded))
+	assert.Equal(t, MustParseMoney("250.10"), decoded.Amount)
+	assert.Equal(t, MustParseMoney("500"), decoded.PreviousBalance)
+	assert.Equal(t, MustParseMoney("750"), decoded.NewBalance)
+}
+
+func TestMoney_SQL(t *testing.T) {
+	var m Money
+	assert.NoError(t, m.Scan([]byte("1234.5600")))
+	assert.Equal(t, MustParseMoney("1234.56"), m)
+
+	value, err := m.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "1234.5600", value)
+}
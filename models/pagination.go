@@ -0,0 +1,70 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+// TransactionFilter narrows a transaction history query. Zero fields match
+// every transaction.
+type TransactionFilter struct {
+	Types       []string  // Any of these types
+	Statuses    []string  // Any of these statuses
+	MinAmount   *Money    // Inclusive
+	MaxAmount   *Money    // Inclusive
+	From        time.Time // Inclusive
+	To          time.Time // Exclusive
+	Description string    // Case-insensitive substring of the description
+}
+
+// Validate rejects ranges that can never match
+func (f *TransactionFilter) Validate() error {
+	if f.MinAmount != nil && f.MaxAmount != nil && *f.MinAmount > *f.MaxAmount {
+		return Errorf(ErrInvalidRequest, "min_amount cannot be greater than max_amount")
+	}
+	if !f.From.IsZero() && !f.To.IsZero() && !f.From.Before(f.To) {
+		return Errorf(ErrInvalidRequest, "from must be before to")
+	}
+	return nil
+}
+
+// TransactionCursor is a position in an account's history, which is ordered
+// newest first by timestamp and then by record ID. A page starts right after
+// the transaction the cursor names, so new arrivals never shift it.
+type TransactionCursor struct {
+	Timestamp time.Time `json:"ts"`
+	ID        string    `json:"id"`
+}
+
+// CursorAfter returns the cursor that continues after transaction
+func CursorAfter(transaction *Transaction) *TransactionCursor {
+	return &TransactionCursor{Timestamp: transaction.Timestamp, ID: transaction.ID}
+}
+
+// Encode returns the cursor as an opaque URL-safe string
+func (c *TransactionCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeTransactionCursor parses a cursor made by Encode
+func DecodeTransactionCursor(value string) (*TransactionCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, Errorf(ErrInvalidRequest, "invalid cursor")
+	}
+
+	var cursor TransactionCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" || cursor.Timestamp.IsZero() {
+		return nil, Errorf(ErrInvalidRequest, "invalid cursor")
+	}
+	return &cursor, nil
+}
+
+// TransactionPage is one page of a cursor-paginated transaction history
+type TransactionPage struct {
+	Transactions []Transaction `json:"transactions"`
+	NextCursor   string        `json:"next_cursor,omitempty"` // Empty on the last page
+	HasMore      bool          `json:"has_more"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionCursor_RoundTrip(t *testing.T) {
+	transaction := &Transaction{ID: "txn_1", Timestamp: time.Date(2024, 8, 30, 20, 55, 11, 123000000, time.UTC)}
+
+	encoded := CursorAfter(transaction).Encode()
+	assert.NotContains(t, encoded, "txn_1", "cursor should be opaque")
+
+	cursor, err := DecodeTransactionCursor(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, "txn_1", cursor.ID)
+	assert.True(t, transaction.Timestamp.Equal(cursor.Timestamp))
+}
+
+func TestDecodeTransactionCursor_Invalid(t *testing.T) {
+	for _, value := range []string{"not base64!", "bm90IGpzb24", "e30"} {
+		_, err := DecodeTransactionCursor(value)
+		assert.True(t, errors.Is(err, ErrInvalidRequest), value)
+	}
+}
+
+func TestTransactionFilter_Validate(t *testing.T) {
+	low, high := MustParseMoney("10.00"), MustParseMoney("20.00")
+	day := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, (&TransactionFilter{}).Validate())
+	assert.NoError(t, (&TransactionFilter{MinAmount: &low, MaxAmount: &high, From: day, To: day.AddDate(0, 0, 1)}).Validate())
+	assert.True(t, errors.Is((&TransactionFilter{MinAmount: &high, MaxAmount: &low}).Validate(), ErrInvalidRequest))
+	assert.True(t, errors.Is((&TransactionFilter{From: day, To: day}).Validate(), ErrInvalidRequest))
+}
@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+// Kinds of discrepancy found by reconciliation
+const (
+	// The balance replayed from the transaction log differs from accounts.balance
+	ReconcileBalanceMismatch = "balance_mismatch"
+	// accounts.balance differs from the sum of the account's postings
+	ReconcileLedgerMismatch = "ledger_mismatch"
+	// A record's previous balance is not the new balance of the record before it
+	ReconcileBalanceGap = "balance_gap"
+	// A record's new balance is not its previous balance plus its amount
+	ReconcileRecordMismatch = "record_mismatch"
+	// A record has been pending for longer than any worker or reaper takes
+	ReconcileOrphanedPending = "orphaned_pending"
+)
+
+// ReconcileOptions selects what a reconciliation run checks and whether it
+// repairs what it finds
+type ReconcileOptions struct {
+	AccountID string // Only this account; empty checks every account
+	Repair    bool   // Post adjustment entries for balance mismatches
+}
+
+// ReconciliationIssue is one discrepancy between Postgres and the transaction log
+type ReconciliationIssue struct {
+	AccountID     string `json:"account_id"`
+	Kind          string `json:"kind"`
+	TransactionID string `json:"transaction_id,omitempty"` // Record the issue was found at, if any
+	Expected      Money  `json:"expected"`
+	Actual        Money  `json:"actual"`
+	Detail        string `json:"detail"`
+	Repaired      bool   `json:"repaired"`
+}
+
+// ReconciliationRepair is an adjustment entry posted, or attempted, to bring
+// an account's balance in line with its transaction log
+type ReconciliationRepair struct {
+	AccountID      string `json:"account_id"`
+	JournalEntryID string `json:"journal_entry_id,omitempty"`
+	Amount         Money  `json:"amount"` // Signed change to the account balance
+	Error          string `json:"error,omitempty"`
+}
+
+// ReconciliationReport is the outcome of one reconciliation run
+type ReconciliationReport struct {
+	StartedAt       time.Time              `json:"started_at"`
+	FinishedAt      time.Time              `json:"finished_at"`
+	AccountID       string                 `json:"account_id,omitempty"` // Set when a single account was checked
+	Repair          bool                   `json:"repair"`
+	AccountsChecked int                    `json:"accounts_checked"`
+	AccountsSkipped int                    `json:"accounts_skipped"` // Balance moved during the check; retried next run
+	Issues          []ReconciliationIssue  `json:"issues"`
+	Repairs         []ReconciliationRepair `json:"repairs"`
+}
+
+// Unresolved counts the issues that were not repaired
+func (r *ReconciliationReport) Unresolved() int {
+	count := 0
+	for _, issue := range r.Issues {
+		if !issue.Repaired {
+			count++
+		}
+	}
+	return count
+}
+
+// ExitCode is the process exit code for the report: 0 when Postgres and the
+// log agree (or every discrepancy was repaired), 1 otherwise
+func (r *ReconciliationReport) ExitCode() int {
+	if r.Unresolved() > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Schedule states. Completed and cancelled schedules are final.
+const (
+	ScheduleActive    = "active"
+	SchedulePaused    = "paused"
+	ScheduleCompleted = "completed"
+	ScheduleCancelled = "cancelled"
+)
+
+// What the scheduler does with occurrences that fell due while it was not
+// running, e.g. during downtime
+const (
+	MissedRunsLatest = "latest" // Run the most recent missed occurrence and skip the older ones
+	MissedRunsAll    = "all"    // Run every missed occurrence, oldest first
+	MissedRunsSkip   = "skip"   // Skip occurrences that are more than the grace period late
+)
+
+// Schedule is a standing order: a deposit or withdrawal made once at a set
+// time, or again at every occurrence of a cron expression. Each occurrence
+// that runs is logged as a pending transaction and processed through the
+// queue like any other.
+type Schedule struct {
+	ID                string     `json:"id"`
+	AccountID         string     `json:"account_id"`
+	Type              string     `json:"type"` // "deposit" or "withdraw"
+	Amount            Money      `json:"amount"`
+	Currency          string     `json:"currency"`
+	Description       string     `json:"description"`
+	Cron              string     `json:"cron,omitempty"` // Empty for a one-off schedule
+	MissedRuns        string     `json:"missed_runs"`
+	Status            string     `json:"status"`
+	NextRunAt         *time.Time `json:"next_run_at"` // Nil once the schedule has finished
+	EndAt             *time.Time `json:"end_at,omitempty"`
+	LastRunAt         *time.Time `json:"last_run_at,omitempty"` // Occurrence most recently run
+	LastTransactionID string     `json:"last_transaction_id,omitempty"`
+	RunCount          int64      `json:"run_count"`
+	SkippedCount      int64      `json:"skipped_count"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// IsRecurring reports whether the schedule runs at every occurrence of a
+// cron expression rather than once
+func (s *Schedule) IsRecurring() bool {
+	return s.Cron != ""
+}
+
+// IsFinished reports whether the schedule will never run again
+func (s *Schedule) IsFinished() bool {
+	return s.Status == ScheduleCompleted || s.Status == ScheduleCancelled
+}
+
+// NextAfter returns the schedule's first occurrence after t, or nil if it has
+// none: a one-off schedule never recurs, and a recurring one stops at EndAt
+func (s *Schedule) NextAfter(t time.Time) (*time.Time, error) {
+	if !s.IsRecurring() {
+		return nil, nil
+	}
+
+	cron, err := ParseCron(s.Cron)
+	if err != nil {
+		return nil, err
+	}
+
+	next := cron.Next(t)
+	if next.IsZero() || (s.EndAt != nil && next.After(*s.EndAt)) {
+		return nil, nil
+	}
+	return &next, nil
+}
+
+// ScheduleStep is what the scheduler does with a schedule that has fallen due
+type ScheduleStep struct {
+	Run     *time.Time // Occurrence to run, if any
+	Skipped int64      // Missed occurrences passed over without running
+	Next    *time.Time // Next occurrence afterwards; nil completes the schedule
+}
+
+// Due works out the next step for a schedule whose next run is at or before
+// now. An occurrence counts as missed when a later one is also due, or, for
+// MissedRunsSkip, when it is more than grace late.
+func (s *Schedule) Due(now time.Time, grace time.Duration) (*ScheduleStep, error) {
+	occurrence := *s.NextRunAt
+	step := &ScheduleStep{}
+
+	for {
+		next, err := s.NextAfter(occurrence)
+		if err != nil {
+			return nil, err
+		}
+
+		var missed bool
+		switch s.MissedRuns {
+		case MissedRunsAll:
+		case MissedRunsSkip:
+			missed = now.Sub(occurrence) > grace
+		default:
+			missed = next != nil && !next.After(now)
+		}
+
+		if !missed {
+			step.Run, step.Next = &occurrence, next
+			return step, nil
+		}
+
+		step.Skipped++
+		if next == nil || next.After(now) {
+			step.Next = next
+			return step, nil
+		}
+		occurrence = *next
+	}
+}
+
+// CreateScheduleRequest represents the request body for creating a schedule
+type CreateScheduleRequest struct {
+	Type        string     `json:"type"` // "deposit" or "withdraw"
+	Amount      Money      `json:"amount"`
+	Currency    string     `json:"currency"` // Optional, must match the account currency when set
+	Description string     `json:"description"`
+	Cron        string     `json:"cron"`        // Recurring schedules only
+	RunAt       *time.Time `json:"run_at"`      // When a one-off schedule runs; a recurring one starts after it. Defaults to now
+	EndAt       *time.Time `json:"end_at"`      // Optional, recurring schedules only
+	MissedRuns  string     `json:"missed_runs"` // Defaults to MissedRunsLatest
+}
+
+// UpdateScheduleRequest changes an active or paused schedule. Fields left out
+// are unchanged; changing when the schedule runs recomputes its next run.
+type UpdateScheduleRequest struct {
+	Amount      *Money     `json:"amount"`
+	Description *string    `json:"description"`
+	Cron        *string    `json:"cron"`   // Recurring schedules only
+	RunAt       *time.Time `json:"run_at"` // Moves a one-off schedule; restarts a recurring one after it
+	EndAt       *time.Time `json:"end_at"`
+	MissedRuns  *string    `json:"missed_runs"`
+}
+
+func NewScheduleID() string {
+	return "sch_" + uuid.New().String()
+}
+
+// scheduleNamespace seeds the IDs of the transactions schedules create
+var scheduleNamespace = uuid.MustParse("6f1c2b9e-3d4a-4f7e-9a51-0c8e2d7b4a13")
+
+// ScheduledTransactionID returns the ID of the transaction a schedule creates
+// for one occurrence. It is the same every time it is computed, so however
+// often an occurrence is run only one transaction can be logged for it.
+func ScheduledTransactionID(scheduleID string, occurrence time.Time) string {
+	name := scheduleID + "@" + occurrence.UTC().Format(time.RFC3339)
+	return "txn_" + uuid.NewSHA1(scheduleNamespace, []byte(name)).String()
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func hourly(next time.Time, missedRuns string) *Schedule {
+	return &Schedule{ID: "sch_1", Cron: "@hourly", MissedRuns: missedRuns, NextRunAt: &next}
+}
+
+func TestSchedule_Due(t *testing.T) {
+	base := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) *time.Time {
+		t := base.Add(time.Duration(hours) * time.Hour)
+		return &t
+	}
+
+	tests := []struct {
+		name     string
+		schedule *Schedule
+		now      time.Time
+		want     ScheduleStep
+	}{
+		{name: "on time", schedule: hourly(base, MissedRunsLatest), now: base.Add(time.Minute),
+			want: ScheduleStep{Run: at(0), Next: at(1)}},
+		{name: "latest runs the last missed occurrence", schedule: hourly(base, MissedRunsLatest), now: base.Add(3*time.Hour + 30*time.Minute),
+			want: ScheduleStep{Run: at(3), Skipped: 3, Next: at(4)}},
+		{name: "all runs the oldest missed occurrence", schedule: hourly(base, MissedRunsAll), now: base.Add(3*time.Hour + 30*time.Minute),
+			want: ScheduleStep{Run: at(0), Next: at(1)}},
+		{name: "skip runs an occurrence within the grace period", schedule: hourly(base, MissedRunsSkip), now: base.Add(10 * time.Minute),
+			want: ScheduleStep{Run: at(0), Next: at(1)}},
+		{name: "skip passes over late occurrences", schedule: hourly(base, MissedRunsSkip), now: base.Add(3*time.Hour + 30*time.Minute),
+			want: ScheduleStep{Skipped: 4, Next: at(4)}},
+		{name: "skip runs the latest if it is within grace", schedule: hourly(base, MissedRunsSkip), now: base.Add(3*time.Hour + 10*time.Minute),
+			want: ScheduleStep{Run: at(3), Skipped: 3, Next: at(4)}},
+		{name: "one-off runs late", schedule: &Schedule{NextRunAt: at(0), MissedRuns: MissedRunsLatest}, now: base.Add(48 * time.Hour),
+			want: ScheduleStep{Run: at(0)}},
+		{name: "late one-off is skipped", schedule: &Schedule{NextRunAt: at(0), MissedRuns: MissedRunsSkip}, now: base.Add(48 * time.Hour),
+			want: ScheduleStep{Skipped: 1}},
+		{name: "end reached", schedule: &Schedule{Cron: "@hourly", NextRunAt: at(0), EndAt: at(0), MissedRuns: MissedRunsLatest}, now: base.Add(time.Minute),
+			want: ScheduleStep{Run: at(0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, err := tt.schedule.Due(tt.now, 15*time.Minute)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *step)
+		})
+	}
+}
+
+func TestScheduledTransactionID(t *testing.T) {
+	occurrence := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	id := ScheduledTransactionID("sch_1", occurrence)
+	assert.Regexp(t, `^txn_[0-9a-f-]{36}$`, id)
+	assert.Equal(t, id, ScheduledTransactionID("sch_1", occurrence.In(time.FixedZone("CEST", 2*60*60))))
+	assert.NotEqual(t, id, ScheduledTransactionID("sch_1", occurrence.Add(time.Hour)))
+	assert.NotEqual(t, id, ScheduledTransactionID("sch_2", occurrence))
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// HistoricalBalance is an account's balance at a point in time, read from the
+// balance snapshots in the transaction log
+type HistoricalBalance struct {
+	AccountID string    `json:"account_id"`
+	Currency  string    `json:"currency"`
+	AsOf      time.Time `json:"as_of"`
+	Balance   Money     `json:"balance"`
+}
+
+// Statement summarizes an account's settled transactions over [From, To)
+type Statement struct {
+	AccountID      string           `json:"account_id"`
+	OwnerName      string           `json:"owner_name"`
+	Currency       string           `json:"currency"`
+	From           time.Time        `json:"from"`
+	To             time.Time        `json:"to"` // Exclusive
+	OpeningBalance Money            `json:"opening_balance"`
+	ClosingBalance Money            `json:"closing_balance"`
+	Totals         []StatementTotal `json:"totals"` // One per transaction type, sorted by type
+	Lines          []StatementLine  `json:"lines"`  // Oldest first
+	GeneratedAt    time.Time        `json:"generated_at"`
+}
+
+// StatementTotal adds up one type of transaction on a statement
+type StatementTotal struct {
+	Type   string `json:"type"`
+	Count  int    `json:"count"`
+	Amount Money  `json:"amount"`
+}
+
+// StatementLine is one transaction on a statement
+type StatementLine struct {
+	Timestamp     time.Time `json:"timestamp"`
+	TransactionID string    `json:"transaction_id"`
+	Type          string    `json:"type"`
+	Description   string    `json:"description"`
+	Amount        Money     `json:"amount"`  // Positive for credits, negative for debits
+	Balance       Money     `json:"balance"` // Balance after the transaction
+	Status        string    `json:"status"`
+}
+
+// SignedAmount returns the transaction amount as it moved the account
+// balance: positive for deposits and incoming transfers, negative otherwise
+func (t *Transaction) SignedAmount() Money {
+	switch t.Type {
+	case "deposit", "transfer_in":
+		return t.Amount
+	}
+	return t.Amount.Neg()
+}
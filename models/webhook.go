@@ -0,0 +1,253 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook event types
+const (
+	EventTransactionCompleted = "transaction.completed" // A deposit or withdrawal was applied
+	EventTransactionFailed    = "transaction.failed"    // A queued deposit or withdrawal was marked failed
+	EventAccountCreated       = "account.created"
+	EventBalanceLow           = "balance.low" // A balance fell below a subscription's low balance threshold
+)
+
+// WebhookEventTypes lists the events a subscription can ask for
+var WebhookEventTypes = []string{
+	EventTransactionCompleted,
+	EventTransactionFailed,
+	EventAccountCreated,
+	EventBalanceLow,
+}
+
+// Webhook delivery states. Delivered and failed deliveries are final; either
+// can be sent again as a new delivery.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed" // Every attempt allowed by the retry policy failed
+)
+
+// Headers sent with each webhook delivery
+const (
+	WebhookIDHeader        = "X-Webhook-ID"        // Delivery ID; a redelivery has its own
+	WebhookEventHeader     = "X-Webhook-Event"     // Event type
+	WebhookTimestampHeader = "X-Webhook-Timestamp" // Unix seconds when the attempt was signed
+	WebhookSignatureHeader = "X-Webhook-Signature" // "v1=" and the hex HMAC-SHA256 of "<timestamp>.<body>"
+)
+
+// WebhookSubscription asks for events to be posted to a URL. An account
+// subscription hears about one account; a global one, with no account ID,
+// hears about every account.
+type WebhookSubscription struct {
+	ID                  string    `json:"id"`
+	AccountID           string    `json:"account_id,omitempty"`
+	URL                 string    `json:"url"`
+	Events              []string  `json:"events"`
+	Secret              string    `json:"-"`                               // Signs deliveries; only returned when the subscription is created
+	LowBalanceThreshold *Money    `json:"low_balance_threshold,omitempty"` // Required for balance.low
+	Description         string    `json:"description"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// IsGlobal reports whether the subscription hears about every account
+func (s *WebhookSubscription) IsGlobal() bool {
+	return s.AccountID == ""
+}
+
+// Wants reports whether the subscription asked for the event type
+func (s *WebhookSubscription) Wants(eventType string) bool {
+	return slices.Contains(s.Events, eventType)
+}
+
+// BalanceFellBelow reports whether a balance change took the balance from at
+// or above the subscription's low balance threshold to below it. Balances
+// already below the threshold do not count again until they recover.
+func (s *WebhookSubscription) BalanceFellBelow(previous, current Money) bool {
+	if s.LowBalanceThreshold == nil {
+		return false
+	}
+	return previous >= *s.LowBalanceThreshold && current < *s.LowBalanceThreshold
+}
+
+// Validate checks a new subscription's URL, events and threshold
+func (s *WebhookSubscription) Validate() error {
+	target, err := url.Parse(s.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return Errorf(ErrInvalidWebhook, "url must be an absolute http or https URL")
+	}
+
+	if len(s.Events) == 0 {
+		return Errorf(ErrInvalidWebhook, "at least one event is required")
+	}
+	for _, event := range s.Events {
+		if !slices.Contains(WebhookEventTypes, event) {
+			return Errorf(ErrInvalidWebhook, "unknown event %q", event)
+		}
+	}
+
+	if s.Wants(EventBalanceLow) && s.LowBalanceThreshold == nil {
+		return Errorf(ErrInvalidWebhook, "low_balance_threshold is required for %s", EventBalanceLow)
+	}
+	if !s.Wants(EventBalanceLow) && s.LowBalanceThreshold != nil {
+		return Errorf(ErrInvalidWebhook, "low_balance_threshold only applies to %s", EventBalanceLow)
+	}
+	return nil
+}
+
+// WebhookEvent is the body posted to subscribers
+type WebhookEvent struct {
+	ID        string          `json:"id"` // Shared by every delivery of the event, so receivers can drop duplicates
+	Type      string          `json:"type"`
+	AccountID string          `json:"account_id"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"` // The transaction, the account, or a BalanceLowData
+}
+
+// NewWebhookEvent creates an event carrying data
+func NewWebhookEvent(eventType, accountID string, data interface{}) (*WebhookEvent, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	return &WebhookEvent{
+		ID:        "evt_" + uuid.New().String(),
+		Type:      eventType,
+		AccountID: accountID,
+		CreatedAt: time.Now().UTC(),
+		Data:      encoded,
+	}, nil
+}
+
+// BalanceLowData is the data of a balance.low event. Each subscription is told
+// about crossing its own threshold.
+type BalanceLowData struct {
+	AccountID       string `json:"account_id"`
+	TransactionID   string `json:"transaction_id"`
+	PreviousBalance Money  `json:"previous_balance"`
+	Balance         Money  `json:"balance"`
+	Threshold       Money  `json:"threshold"`
+	Currency        string `json:"currency"`
+}
+
+// WebhookDelivery sends one event to one subscription
+type WebhookDelivery struct {
+	ID             string           `json:"id"`
+	SubscriptionID string           `json:"subscription_id"`
+	EventID        string           `json:"event_id"`
+	EventType      string           `json:"event_type"`
+	AccountID      string           `json:"account_id"`
+	Payload        json.RawMessage  `json:"payload"` // The WebhookEvent as posted
+	Status         string           `json:"status"`
+	Attempts       int              `json:"attempts"`
+	NextAttemptAt  *time.Time       `json:"next_attempt_at,omitempty"` // Nil once the delivery is final
+	LastStatusCode int              `json:"last_status_code,omitempty"`
+	LastError      string           `json:"last_error,omitempty"`
+	RedeliveryOf   string           `json:"redelivery_of,omitempty"` // The delivery this one sends again
+	DeliveredAt    *time.Time       `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+	AttemptLog     []WebhookAttempt `json:"attempt_log,omitempty"` // Only filled in when one delivery is fetched
+}
+
+// NewWebhookDelivery creates a pending delivery of event to subscription,
+// due straight away
+func NewWebhookDelivery(subscriptionID string, event *WebhookEvent) (*WebhookDelivery, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s event: %w", event.Type, err)
+	}
+
+	now := time.Now().UTC()
+	return &WebhookDelivery{
+		ID:             NewWebhookDeliveryID(),
+		SubscriptionID: subscriptionID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		AccountID:      event.AccountID,
+		Payload:        payload,
+		Status:         WebhookDeliveryPending,
+		NextAttemptAt:  &now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// IsFinished reports whether the delivery will not be attempted again
+func (d *WebhookDelivery) IsFinished() bool {
+	return d.Status == WebhookDeliveryDelivered || d.Status == WebhookDeliveryFailed
+}
+
+// WebhookAttempt records one POST of a delivery and how the subscriber answered
+type WebhookAttempt struct {
+	DeliveryID   string    `json:"delivery_id"`
+	Attempt      int       `json:"attempt"` // 1 for the first attempt
+	StatusCode   int       `json:"status_code,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	ResponseBody string    `json:"response_body,omitempty"` // The start of the subscriber's answer
+	DurationMs   int64     `json:"duration_ms"`
+	AttemptedAt  time.Time `json:"attempted_at"`
+}
+
+// Succeeded reports whether the subscriber accepted the delivery with a 2xx
+// status
+func (a *WebhookAttempt) Succeeded() bool {
+	return a.Error == "" && a.StatusCode >= 200 && a.StatusCode <= 299
+}
+
+// CreateWebhookRequest represents the request body for creating a webhook
+// subscription
+type CreateWebhookRequest struct {
+	URL                 string   `json:"url"`
+	Events              []string `json:"events"`
+	LowBalanceThreshold *Money   `json:"low_balance_threshold"` // Required for balance.low
+	Description         string   `json:"description"`
+}
+
+func NewWebhookID() string {
+	return "whk_" + uuid.New().String()
+}
+
+func NewWebhookDeliveryID() string {
+	return "whd_" + uuid.New().String()
+}
+
+// NewWebhookSecret returns a random secret for signing a subscription's
+// deliveries
+func NewWebhookSecret() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(key), nil
+}
+
+// SignWebhook returns the X-Webhook-Signature value for a body sent at
+// timestamp. Signing the timestamp with the body lets receivers reject
+// replayed deliveries.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature reports whether signature is SignWebhook's value for
+// the body and timestamp, comparing in constant time
+func VerifyWebhookSignature(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(SignWebhook(secret, timestamp, body)), []byte(signature))
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSubscription_Validate(t *testing.T) {
+	threshold := MustParseMoney("100.00")
+
+	tests := []struct {
+		name         string
+		subscription WebhookSubscription
+		wantErr      string
+	}{
+		{name: "valid", subscription: WebhookSubscription{URL: "https://example.com/hooks", Events: []string{EventTransactionCompleted}}},
+		{name: "balance.low with threshold", subscription: WebhookSubscription{URL: "http://hooks.internal:9000/ledger", Events: []string{EventBalanceLow}, LowBalanceThreshold: &threshold}},
+		{name: "relative url", subscription: WebhookSubscription{URL: "/hooks", Events: []string{EventAccountCreated}},
+			wantErr: "url must be an absolute http or https URL"},
+		{name: "unsupported scheme", subscription: WebhookSubscription{URL: "ftp://example.com", Events: []string{EventAccountCreated}},
+			wantErr: "url must be an absolute http or https URL"},
+		{name: "no events", subscription: WebhookSubscription{URL: "https://example.com"},
+			wantErr: "at least one event is required"},
+		{name: "unknown event", subscription: WebhookSubscription{URL: "https://example.com", Events: []string{"account.deleted"}},
+			wantErr: `unknown event "account.deleted"`},
+		{name: "balance.low without threshold", subscription: WebhookSubscription{URL: "https://example.com", Events: []string{EventBalanceLow}},
+			wantErr: "low_balance_threshold is required for balance.low"},
+		{name: "threshold without balance.low", subscription: WebhookSubscription{URL: "https://example.com", Events: []string{EventAccountCreated}, LowBalanceThreshold: &threshold},
+			wantErr: "low_balance_threshold only applies to balance.low"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.subscription.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+			assert.True(t, errors.Is(err, ErrInvalidWebhook))
+		})
+	}
+}
+
+func TestWebhookSubscription_BalanceFellBelow(t *testing.T) {
+	threshold := MustParseMoney("100.00")
+	subscription := &WebhookSubscription{Events: []string{EventBalanceLow}, LowBalanceThreshold: &threshold}
+
+	assert.True(t, subscription.BalanceFellBelow(MustParseMoney("150.00"), MustParseMoney("99.99")))
+	assert.True(t, subscription.BalanceFellBelow(MustParseMoney("100.00"), MustParseMoney("-20.00")))
+	assert.False(t, subscription.BalanceFellBelow(MustParseMoney("150.00"), MustParseMoney("100.00")), "at the threshold is not below it")
+	assert.False(t, subscription.BalanceFellBelow(MustParseMoney("80.00"), MustParseMoney("50.00")), "already below")
+	assert.False(t, (&WebhookSubscription{}).BalanceFellBelow(MustParseMoney("150.00"), MustParseMoney("0")))
+}
+
+func TestSignWebhook(t *testing.T) {
+	body := []byte(`{"id":"evt_1"}`)
+
+	// Matches Python's hmac.new(b"whsec_test", b'1700000000.{"id":"evt_1"}', hashlib.sha256)
+	signature := SignWebhook("whsec_test", 1700000000, body)
+	assert.Equal(t, "v1=c89214b5b5da833daed6f0b8c5bb6bd58cea9022bd80ccc78230f3942d632925", signature)
+
+	assert.True(t, VerifyWebhookSignature("whsec_test", 1700000000, body, signature))
+	assert.False(t, VerifyWebhookSignature("whsec_other", 1700000000, body, signature))
+	assert.False(t, VerifyWebhookSignature("whsec_test", 1700000001, body, signature))
+	assert.False(t, VerifyWebhookSignature("whsec_test", 1700000000, []byte(`{"id":"evt_2"}`), signature))
+}
+
+func TestNewWebhookDelivery(t *testing.T) {
+	event, err := NewWebhookEvent(EventAccountCreated, "acc_1", map[string]string{"id": "acc_1"})
+	require.NoError(t, err)
+
+	delivery, err := NewWebhookDelivery("whk_1", event)
+	require.NoError(t, err)
+
+	assert.Equal(t, WebhookDeliveryPending, delivery.Status)
+	assert.Equal(t, event.ID, delivery.EventID)
+	assert.Equal(t, "acc_1", delivery.AccountID)
+	assert.NotNil(t, delivery.NextAttemptAt)
+
+	var posted WebhookEvent
+	require.NoError(t, json.Unmarshal(delivery.Payload, &posted))
+	assert.Equal(t, event.ID, posted.ID)
+	assert.Equal(t, EventAccountCreated, posted.Type)
+	assert.JSONEq(t, `{"id":"acc_1"}`, string(posted.Data))
+}
+
+func TestNewWebhookSecret(t *testing.T) {
+	first, err := NewWebhookSecret()
+	require.NoError(t, err)
+	second, err := NewWebhookSecret()
+	require.NoError(t, err)
+
+	assert.Len(t, first, len("whsec_")+64)
+	assert.NotEqual(t, first, second)
+}
@@ -0,0 +1,193 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/metrics"
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/tracing"
+	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	DeadLetterExchange = ExchangeName + "_dlx"
+	DeadLetterQueue    = "transaction_dlq"
+)
+
+// setupDeadLetterQueue declares the exchange the transaction queue dead-letters
+// into, and the queue that collects those messages
+func (r *RabbitMQ) setupDeadLetterQueue() error {
+	// Fanout, so messages arrive whatever routing key they were published with
+	err := r.channel.ExchangeDeclare(
+		DeadLetterExchange, // name
+		"fanout",           // type
+		true,               // durable
+		false,              // auto-deleted
+		false,              // internal
+		false,              // no-wait
+		nil,                // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare dead letter exchange: %v", err)
+	}
+
+	_, err = r.channel.QueueDeclare(
+		DeadLetterQueue, // name
+		true,            // durable
+		false,           // delete when unused
+		false,           // exclusive
+		false,           // no-wait
+		nil,             // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare dead letter queue: %v", err)
+	}
+
+	err = r.channel.QueueBind(
+		DeadLetterQueue,    // queue name
+		"",                 // routing key
+		DeadLetterExchange, // exchange
+		false,
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to bind dead letter queue: %v", err)
+	}
+
+	return nil
+}
+
+// ConsumeDeadLetters returns a channel to consume dead-lettered messages
+func (r *RabbitMQ) ConsumeDeadLetters() (<-chan amqp.Delivery, error) {
+	msgs, err := r.channel.Consume(
+		DeadLetterQueue, // queue
+		"",              // consumer
+		false,           // auto-ack (we'll ack once the dead letter is stored)
+		false,           // exclusive
+		false,           // no-local
+		false,           // no-wait
+		nil,             // args
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register dead letter consumer: %v", err)
+	}
+
+	return msgs, nil
+}
+
+// RepublishMessage publishes a stored message body back to the transaction
+// queue, as part of the trace of the request that replays it
+func (r *RabbitMQ) RepublishMessage(ctx context.Context, messageID string, body []byte) error {
+	if !r.IsConnected() {
+		metrics.QueuePublishFailures.WithLabelValues("replay").Inc()
+		return ErrNotConnected
+	}
+
+	ctx, span := tracing.Start(ctx, TransactionQueue+" publish", trace.SpanKindProducer,
+		MessagingAttributes("publish", messageID)...)
+	defer span.End()
+
+	headers := amqp.Table{}
+	tracing.Inject(ctx, HeaderCarrier(headers))
+
+	err := r.channel.Publish(
+		ExchangeName, // exchange
+		RoutingKey,   // routing key
+		false,        // mandatory
+		false,        // immediate
+		amqp.Publishing{
+			ContentType:  "application/json",
+			Body:         body,
+			Headers:      headers,
+			DeliveryMode: amqp.Persistent,
+			Timestamp:    time.Now(),
+			MessageId:    messageID,
+		})
+	if err != nil {
+		tracing.RecordError(span, err)
+		metrics.QueuePublishFailures.WithLabelValues("replay").Inc()
+		return fmt.Errorf("failed to publish message: %v", err)
+	}
+
+	log.Printf("Republished message: %s", messageID)
+	return nil
+}
+
+// NewDeadLetter builds a dead letter record from a delivery, reading why and
+// where the message died from its most recent x-death entry, or from the retry
+// headers when a worker gave up on it
+func NewDeadLetter(delivery amqp.Delivery) *models.DeadLetter {
+	now := time.Now()
+	deadLetter := &models.DeadLetter{
+		ID:         models.NewDeadLetterID(),
+		MessageID:  delivery.MessageId,
+		Reason:     "unknown",
+		Body:       string(delivery.Body),
+		Status:     "dead",
+		DiedAt:     now,
+		ReceivedAt: now,
+	}
+
+	if deadLetter.MessageID == "" {
+		var msg TransactionMessage
+		if err := json.Unmarshal(delivery.Body, &msg); err == nil {
+			deadLetter.MessageID = msg.ID
+		}
+	}
+
+	if deaths, ok := delivery.Headers["x-death"].([]interface{}); ok && len(deaths) > 0 {
+		// RabbitMQ keeps the most recent death first
+		if death, ok := deaths[0].(amqp.Table); ok {
+			if reason, ok := death["reason"].(string); ok {
+				deadLetter.Reason = reason
+			}
+			if queue, ok := death["queue"].(string); ok {
+				deadLetter.Queue = queue
+			}
+			if count, ok := death["count"].(int64); ok {
+				deadLetter.DeathCount = count
+			}
+			if diedAt, ok := death["time"].(time.Time); ok {
+				deadLetter.DiedAt = diedAt
+			}
+			if keys, ok := death["routing-keys"].([]interface{}); ok {
+				for _, key := range keys {
+					if key, ok := key.(string); ok {
+						deadLetter.RoutingKeys = append(deadLetter.RoutingKeys, key)
+					}
+				}
+			}
+
+			deadLetter.DedupKey = fmt.Sprintf("%s|%s|%s|%d|%d",
+				deadLetter.MessageID, deadLetter.Queue, deadLetter.Reason, deadLetter.DeathCount, deadLetter.DiedAt.Unix())
+		}
+	}
+
+	if lastError, ok := delivery.Headers[LastErrorHeader].(string); ok {
+		deadLetter.LastError = lastError
+	}
+	deadLetter.Attempts = int64(RetryCount(delivery))
+
+	// Sent here by a worker that ran out of retries, not by RabbitMQ
+	if deadLetter.Reason == "unknown" && deadLetter.LastError != "" {
+		deadLetter.Reason = "retries_exhausted"
+		deadLetter.Queue = TransactionQueue
+		if !delivery.Timestamp.IsZero() {
+			deadLetter.DiedAt = delivery.Timestamp
+		}
+		deadLetter.DedupKey = fmt.Sprintf("%s|%s|%d|%d",
+			deadLetter.MessageID, deadLetter.Reason, deadLetter.Attempts, deadLetter.DiedAt.Unix())
+	}
+
+	// Without x-death there is nothing to recognise a redelivery by
+	if deadLetter.DedupKey == "" {
+		deadLetter.DedupKey = deadLetter.ID
+	}
+
+	return deadLetter
+}
@@ -0,0 +1,34 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/tracing"
+)
+
+// NewOutboxEntry encodes a message so it can be stored with its pending
+// transaction and published later by the outbox relay. The context's trace is
+// kept with it so the publish and the worker join the request's trace.
+func NewOutboxEntry(ctx context.Context, msg TransactionMessage) (*models.OutboxEntry, error) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal outbox message: %v", err)
+	}
+
+	entry := models.NewOutboxEntry(payload)
+	entry.TraceParent = tracing.TraceParent(ctx)
+	return entry, nil
+}
+
+// DecodeOutboxEntry restores the message stored in an outbox entry
+func DecodeOutboxEntry(entry *models.OutboxEntry) (TransactionMessage, error) {
+	var msg TransactionMessage
+	if err := json.Unmarshal(entry.Payload, &msg); err != nil {
+		return msg, fmt.Errorf("failed to unmarshal outbox message: %v", err)
+	}
+
+	return msg, nil
+}
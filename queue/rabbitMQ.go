@@ -4,11 +4,16 @@ package queue
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
+	"github.com/appy29/banking-ledger-service/metrics"
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/tracing"
 	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -17,27 +22,35 @@ const (
 	RoutingKey       = "transaction.process"
 )
 
+// ErrNotConnected is returned when a message cannot be published because the
+// broker connection is down
+var ErrNotConnected = errors.New("RabbitMQ is not connected")
+
 // TransactionMessage represents a transaction to be processed
 type TransactionMessage struct {
-	ID        string    `json:"id"`
-	AccountID string    `json:"account_id"`
-	Type      string    `json:"type"` // "deposit" or "withdraw"
-	Amount    float64   `json:"amount"`
-	Reference string    `json:"reference"`
-	CreatedAt time.Time `json:"created_at"`
+	ID          string       `json:"id"` // Transaction ID, or transfer ID when Type is "transfer"
+	AccountID   string       `json:"account_id"`
+	ToAccountID string       `json:"to_account_id,omitempty"` // Destination account for transfers
+	Type        string       `json:"type"`                    // "deposit", "withdraw" or "transfer"
+	Amount      models.Money `json:"amount"`
+	Currency    string       `json:"currency,omitempty"`
+	Reference   string       `json:"reference"`
+	CreatedAt   time.Time    `json:"created_at"`
 }
 
 // RabbitMQ represents RabbitMQ connection and channel
 type RabbitMQ struct {
-	conn    *amqp.Connection
-	channel *amqp.Channel
-	url     string
+	conn        *amqp.Connection
+	channel     *amqp.Channel
+	url         string
+	retryPolicy RetryPolicy
 }
 
 // NewRabbitMQ creates a new RabbitMQ instance
 func NewRabbitMQ(url string) *RabbitMQ {
 	return &RabbitMQ{
-		url: url,
+		url:         url,
+		retryPolicy: DefaultRetryPolicy,
 	}
 }
 
@@ -84,9 +97,13 @@ func (r *RabbitMQ) setupExchangeAndQueue() error {
 		return fmt.Errorf("failed to declare exchange: %v", err)
 	}
 
+	if err := r.setupDeadLetterQueue(); err != nil {
+		return err
+	}
+
 	// Declare queue with dead letter exchange
 	args := amqp.Table{
-		"x-dead-letter-exchange": ExchangeName + "_dlx",
+		"x-dead-letter-exchange": DeadLetterExchange,
 		"x-message-ttl":          300000, // 5 minutes
 	}
 
@@ -114,6 +131,10 @@ func (r *RabbitMQ) setupExchangeAndQueue() error {
 		return fmt.Errorf("failed to bind queue: %v", err)
 	}
 
+	if err := r.setupRetryQueues(); err != nil {
+		return err
+	}
+
 	// Set QoS - process one message at a time per worker
 	err = r.channel.Qos(
 		1,     // prefetch count
@@ -128,13 +149,22 @@ func (r *RabbitMQ) setupExchangeAndQueue() error {
 	return nil
 }
 
-// PublishTransaction publishes a transaction message to the queue
+// PublishTransaction publishes a transaction message to the queue. The trace
+// context of ctx goes in the message headers for the worker to continue.
 func (r *RabbitMQ) PublishTransaction(ctx context.Context, msg TransactionMessage) error {
+	ctx, span := tracing.Start(ctx, TransactionQueue+" publish", trace.SpanKindProducer,
+		MessagingAttributes("publish", msg.ID)...)
+	defer span.End()
+
 	body, err := json.Marshal(msg)
 	if err != nil {
+		tracing.RecordError(span, err)
 		return fmt.Errorf("failed to marshal message: %v", err)
 	}
 
+	headers := amqp.Table{}
+	tracing.Inject(ctx, HeaderCarrier(headers))
+
 	err = r.channel.Publish(
 		ExchangeName, // exchange
 		RoutingKey,   // routing key
@@ -143,12 +173,15 @@ func (r *RabbitMQ) PublishTransaction(ctx context.Context, msg TransactionMessag
 		amqp.Publishing{
 			ContentType:  "application/json",
 			Body:         body,
+			Headers:      headers,
 			DeliveryMode: amqp.Persistent, // make message persistent
 			Timestamp:    time.Now(),
 			MessageId:    msg.ID,
 		})
 
 	if err != nil {
+		tracing.RecordError(span, err)
+		metrics.QueuePublishFailures.WithLabelValues("transaction").Inc()
 		return fmt.Errorf("failed to publish message: %v", err)
 	}
 
@@ -0,0 +1,176 @@
+package queue
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/metrics"
+	"github.com/streadway/amqp"
+)
+
+const (
+	// RetryCountHeader counts the retries already scheduled for a message
+	RetryCountHeader = "x-retry-count"
+	// LastErrorHeader carries the error from the most recent attempt
+	LastErrorHeader = "x-last-error"
+)
+
+// RetryPolicy bounds how often a message that hit a system error is retried,
+// and how long to wait between attempts
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// DefaultRetryPolicy retries five times, waiting 1s, 2s, 4s, 8s and 16s
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 5,
+	BaseDelay:  time.Second,
+	MaxDelay:   5 * time.Minute,
+}
+
+// Delay returns the wait before the given retry (1-based): BaseDelay doubled
+// per retry, capped at MaxDelay
+func (p RetryPolicy) Delay(retry int) time.Duration {
+	if retry < 1 {
+		retry = 1
+	}
+
+	delay := p.BaseDelay
+	for i := 1; i < retry; i++ {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+
+	return delay
+}
+
+// retryQueueName names the delay queue for one backoff step. The TTL is part
+// of the name because a queue's arguments cannot change once declared.
+func retryQueueName(delay time.Duration) string {
+	return fmt.Sprintf("%s_retry_%dms", TransactionQueue, delay.Milliseconds())
+}
+
+// SetRetryPolicy replaces the retry policy; call it before Connect so the
+// matching delay queues are declared
+func (r *RabbitMQ) SetRetryPolicy(policy RetryPolicy) {
+	r.retryPolicy = policy
+}
+
+// RetryPolicy returns the policy workers retry messages under
+func (r *RabbitMQ) RetryPolicy() RetryPolicy {
+	return r.retryPolicy
+}
+
+// setupRetryQueues declares one delay queue per backoff step. Messages wait in
+// it for the step's TTL and are then dead-lettered back to the transaction queue.
+func (r *RabbitMQ) setupRetryQueues() error {
+	declared := make(map[string]bool)
+	for retry := 1; retry <= r.retryPolicy.MaxRetries; retry++ {
+		delay := r.retryPolicy.Delay(retry)
+		name := retryQueueName(delay)
+		if declared[name] {
+			continue
+		}
+
+		args := amqp.Table{
+			"x-message-ttl":             delay.Milliseconds(),
+			"x-dead-letter-exchange":    ExchangeName,
+			"x-dead-letter-routing-key": RoutingKey,
+		}
+
+		_, err := r.channel.QueueDeclare(
+			name,  // name
+			true,  // durable
+			false, // delete when unused
+			false, // exclusive
+			false, // no-wait
+			args,  // arguments
+		)
+		if err != nil {
+			return fmt.Errorf("failed to declare retry queue %s: %v", name, err)
+		}
+		declared[name] = true
+	}
+
+	return nil
+}
+
+// RetryCount returns how many retries were already scheduled for a delivery
+func RetryCount(delivery amqp.Delivery) int {
+	switch count := delivery.Headers[RetryCountHeader].(type) {
+	case int64:
+		return int(count)
+	case int32:
+		return int(count)
+	case int:
+		return count
+	}
+	return 0
+}
+
+// PublishRetry schedules another attempt of a delivery after the backoff delay
+// for the given retry
+func (r *RabbitMQ) PublishRetry(delivery amqp.Delivery, retry int, cause error) error {
+	delay := r.retryPolicy.Delay(retry)
+
+	headers := amqp.Table{}
+	for key, value := range delivery.Headers {
+		headers[key] = value
+	}
+	headers[RetryCountHeader] = int64(retry)
+	headers[LastErrorHeader] = cause.Error()
+
+	// The default exchange routes straight to the named queue
+	err := r.channel.Publish(
+		"",                    // exchange
+		retryQueueName(delay), // routing key
+		false,                 // mandatory
+		false,                 // immediate
+		amqp.Publishing{
+			ContentType:  "application/json",
+			Body:         delivery.Body,
+			Headers:      headers,
+			DeliveryMode: amqp.Persistent,
+			Timestamp:    time.Now(),
+			MessageId:    delivery.MessageId,
+		})
+	if err != nil {
+		metrics.QueuePublishFailures.WithLabelValues("retry").Inc()
+		return fmt.Errorf("failed to publish retry: %v", err)
+	}
+
+	log.Printf("Scheduled retry %d/%d of message %s in %v", retry, r.retryPolicy.MaxRetries, delivery.MessageId, delay)
+	return nil
+}
+
+// PublishDeadLetter sends a delivery that ran out of retries to the dead letter
+// queue, recording how many attempts were made and the last error
+func (r *RabbitMQ) PublishDeadLetter(delivery amqp.Delivery, attempts int, cause error) error {
+	err := r.channel.Publish(
+		DeadLetterExchange, // exchange
+		"",                 // routing key
+		false,              // mandatory
+		false,              // immediate
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        delivery.Body,
+			Headers: amqp.Table{
+				RetryCountHeader: int64(attempts),
+				LastErrorHeader:  cause.Error(),
+			},
+			DeliveryMode: amqp.Persistent,
+			Timestamp:    time.Now(),
+			MessageId:    delivery.MessageId,
+		})
+	if err != nil {
+		metrics.QueuePublishFailures.WithLabelValues("dead_letter").Inc()
+		return fmt.Errorf("failed to publish dead letter: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package queue
+
+import (
+	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// HeaderCarrier adapts AMQP message headers to a propagation.TextMapCarrier,
+// so trace context travels with a message from the publisher to the worker
+type HeaderCarrier amqp.Table
+
+func (h HeaderCarrier) Get(key string) string {
+	value, _ := h[key].(string)
+	return value
+}
+
+func (h HeaderCarrier) Set(key, value string) {
+	h[key] = value
+}
+
+func (h HeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for key := range h {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// MessagingAttributes describe a message for publish and process spans
+func MessagingAttributes(operation, messageID string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("messaging.system", "rabbitmq"),
+		attribute.String("messaging.operation", operation),
+		attribute.String("messaging.destination.name", TransactionQueue),
+		attribute.String("messaging.message.id", messageID),
+	}
+}
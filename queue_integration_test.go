@@ -42,7 +42,7 @@ func TestQueueIntegration(t *testing.T) {
 			ID:        "txn_test_12345",
 			AccountID: "acc_test_verification",
 			Type:      "deposit",
-			Amount:    250.00,
+			Amount:    models.MustParseMoney("250.00"),
 			Reference: "Queue integration test",
 			CreatedAt: time.Now(),
 		}
@@ -64,7 +64,7 @@ func TestQueueIntegration(t *testing.T) {
 			// Only proceed with other assertions if we got the right message
 			if receivedMessage.ID == "txn_test_12345" {
 				assert.Equal(t, "deposit", receivedMessage.Type)
-				assert.Equal(t, 250.00, receivedMessage.Amount)
+				assert.Equal(t, models.MustParseMoney("250.00"), receivedMessage.Amount)
 				assert.Equal(t, "acc_test_verification", receivedMessage.AccountID)
 				assert.Equal(t, "Queue integration test", receivedMessage.Reference)
 			}
@@ -94,7 +94,7 @@ func TestQueueIntegration(t *testing.T) {
 				ID:        "txn_multi_001",
 				AccountID: "acc_multi_test",
 				Type:      "deposit",
-				Amount:    100.00,
+				Amount:    models.MustParseMoney("100.00"),
 				Reference: "Multi test 1",
 				CreatedAt: time.Now(),
 			},
@@ -102,7 +102,7 @@ func TestQueueIntegration(t *testing.T) {
 				ID:        "txn_multi_002",
 				AccountID: "acc_multi_test",
 				Type:      "withdraw",
-				Amount:    50.00,
+				Amount:    models.MustParseMoney("50.00"),
 				Reference: "Multi test 2",
 				CreatedAt: time.Now(),
 			},
@@ -183,7 +183,7 @@ func TestEndToEndAsyncFlow(t *testing.T) {
 		// Create test account
 		accountReq := &models.CreateAccountRequest{
 			OwnerName:      "Async Flow Test User",
-			InitialBalance: 1000.00,
+			InitialBalance: models.MustParseMoney("1000.00"),
 		}
 		account, err := accountService.CreateAccount(ctx, accountReq)
 		require.NoError(t, err)
@@ -195,7 +195,7 @@ func TestEndToEndAsyncFlow(t *testing.T) {
 			TransactionID:   transactionID,
 			AccountID:       account.ID,
 			Type:            "deposit",
-			Amount:          300.00,
+			Amount:          models.MustParseMoney("300.00"),
 			PreviousBalance: account.Balance,
 			NewBalance:      0,
 			Description:     "End-to-end async test",
@@ -211,7 +211,7 @@ func TestEndToEndAsyncFlow(t *testing.T) {
 			ID:        transactionID, // Use same ID as transaction
 			AccountID: account.ID,
 			Type:      "deposit",
-			Amount:    300.00,
+			Amount:    models.MustParseMoney("300.00"),
 			Reference: "End-to-end async test",
 			CreatedAt: time.Now(),
 		}
@@ -258,13 +258,13 @@ func TestEndToEndAsyncFlow(t *testing.T) {
 			finalTransaction, err := transactionService.GetTransactionByID(ctx, transactionID)
 			require.NoError(t, err)
 			assert.Equal(t, "completed", finalTransaction.Status)
-			assert.Equal(t, 1000.00, finalTransaction.PreviousBalance)
-			assert.Equal(t, 1300.00, finalTransaction.NewBalance)
+			assert.Equal(t, models.MustParseMoney("1000.00"), finalTransaction.PreviousBalance)
+			assert.Equal(t, models.MustParseMoney("1300.00"), finalTransaction.NewBalance)
 
 			// Verify account balance
 			finalBalance, err := accountService.GetAccountBalance(ctx, account.ID)
 			require.NoError(t, err)
-			assert.Equal(t, 1300.00, finalBalance)
+			assert.Equal(t, models.MustParseMoney("1300.00"), finalBalance)
 
 		case <-time.After(15 * time.Second):
 			// Check transaction status for debugging
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/services"
+)
+
+// runReconcile implements `banking-ledger-service reconcile`: it checks every
+// account (or one, with -account) against the transaction log, writes the
+// report to stdout and returns the process exit code
+func runReconcile(args []string) int {
+	flags := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	accountID := flags.String("account", "", "check only this account")
+	repair := flags.Bool("repair", false, "post adjustment entries for balances that disagree with the log")
+	format := flags.String("format", "json", "report format: json or text")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	if *format != "json" && *format != "text" {
+		fmt.Fprintf(os.Stderr, "invalid format %q: use json or text\n", *format)
+		return exitError
+	}
+
+	env, ok := openCommandEnv()
+	if !ok {
+		return exitError
+	}
+	defer env.Close()
+
+	ctx, stop := env.context()
+	defer stop()
+
+	reconciliationService := services.NewReconciliationService(env.accountStorage, env.transactionStorage, env.cfg.PendingMaxAge)
+	report, err := reconciliationService.Reconcile(ctx, models.ReconcileOptions{AccountID: *accountID, Repair: *repair})
+	if err != nil {
+		env.logger.Error("Reconciliation failed", slog.String("error", err.Error()))
+		return exitError
+	}
+
+	if *format == "text" {
+		printReconciliationReport(report)
+	} else {
+		if err := writeJSON(report); err != nil {
+			env.logger.Error("Failed to write report", slog.String("error", err.Error()))
+			return exitError
+		}
+	}
+
+	return report.ExitCode()
+}
+
+// printReconciliationReport writes a human-readable summary of report
+func printReconciliationReport(report *models.ReconciliationReport) {
+	fmt.Printf("Reconciliation %s (%s)\n", report.StartedAt.Format("2006-01-02 15:04:05"), report.FinishedAt.Sub(report.StartedAt).Round(time.Millisecond))
+	fmt.Printf("Accounts checked: %d, skipped: %d\n", report.AccountsChecked, report.AccountsSkipped)
+	fmt.Printf("Issues: %d, unresolved: %d\n", len(report.Issues), report.Unresolved())
+
+	for _, issue := range report.Issues {
+		status := ""
+		if issue.Repaired {
+			status = " [repaired]"
+		}
+		fmt.Printf("  %-16s %-20s %-40s expected %s, actual %s: %s%s\n",
+			issue.Kind, issue.AccountID, issue.TransactionID, issue.Expected, issue.Actual, issue.Detail, status)
+	}
+	for _, repair := range report.Repairs {
+		if repair.Error != "" {
+			fmt.Printf("  repair of %s by %s failed: %s\n", repair.AccountID, repair.Amount, repair.Error)
+		} else {
+			fmt.Printf("  repaired %s by %s in %s\n", repair.AccountID, repair.Amount, repair.JournalEntryID)
+		}
+	}
+}
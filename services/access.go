@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/appy29/banking-ledger-service/auth"
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/utils"
+)
+
+// AccessService decides whether the caller in the request context may use an
+// account, or a record that belongs to one. Records are judged by the account
+// they belong to; a transfer by either of its accounts.
+type AccessService struct {
+	accountStorage     AccountStorage
+	transactionStorage TransactionStorage
+	scheduleStorage    ScheduleStorage
+	webhookStorage     WebhookStorage
+}
+
+func NewAccessService(accountStorage AccountStorage, transactionStorage TransactionStorage, scheduleStorage ScheduleStorage, webhookStorage WebhookStorage) *AccessService {
+	return &AccessService{
+		accountStorage:     accountStorage,
+		transactionStorage: transactionStorage,
+		scheduleStorage:    scheduleStorage,
+		webhookStorage:     webhookStorage,
+	}
+}
+
+// CheckAccount returns nil if the caller may use the account
+func (s *AccessService) CheckAccount(ctx context.Context, accountID string, access auth.Access) error {
+	return s.check(ctx, access, func() ([]string, error) {
+		return []string{accountID}, nil
+	})
+}
+
+// CheckTransaction returns nil if the caller may use the account the
+// transaction was made on
+func (s *AccessService) CheckTransaction(ctx context.Context, transactionID string, access auth.Access) error {
+	return s.check(ctx, access, func() ([]string, error) {
+		transaction, err := s.transactionStorage.GetTransactionByID(ctx, transactionID)
+		if err != nil {
+			return nil, err
+		}
+		return []string{transaction.AccountID}, nil
+	})
+}
+
+// CheckTransfer returns nil if the caller may use either account of the
+// transfer
+func (s *AccessService) CheckTransfer(ctx context.Context, transferID string, access auth.Access) error {
+	return s.check(ctx, access, func() ([]string, error) {
+		legs, err := s.transactionStorage.GetTransactionsByTransferID(ctx, transferID)
+		if err != nil {
+			return nil, err
+		}
+		if len(legs) == 0 {
+			return nil, models.Errorf(models.ErrTransferNotFound, "transfer not found: %s", transferID)
+		}
+		accountIDs := make([]string, 0, len(legs))
+		for _, leg := range legs {
+			accountIDs = append(accountIDs, leg.AccountID)
+		}
+		return accountIDs, nil
+	})
+}
+
+// CheckHold returns nil if the caller may use the account the hold is on
+func (s *AccessService) CheckHold(ctx context.Context, holdID string, access auth.Access) error {
+	return s.check(ctx, access, func() ([]string, error) {
+		hold, err := s.accountStorage.GetHold(ctx, holdID)
+		if err != nil {
+			return nil, err
+		}
+		return []string{hold.AccountID}, nil
+	})
+}
+
+// CheckSchedule returns nil if the caller may use the account the schedule
+// is set up on
+func (s *AccessService) CheckSchedule(ctx context.Context, scheduleID string, access auth.Access) error {
+	return s.check(ctx, access, func() ([]string, error) {
+		schedule, err := s.scheduleStorage.GetSchedule(ctx, scheduleID)
+		if err != nil {
+			return nil, err
+		}
+		return []string{schedule.AccountID}, nil
+	})
+}
+
+// CheckWebhook returns nil if the caller may use the account the webhook
+// subscription is for. Global subscriptions belong to no account, so only
+// roles that cover every account may use them.
+func (s *AccessService) CheckWebhook(ctx context.Context, webhookID string, access auth.Access) error {
+	return s.check(ctx, access, func() ([]string, error) {
+		subscription, err := s.webhookStorage.GetWebhookSubscription(ctx, webhookID)
+		if err != nil {
+			return nil, err
+		}
+		if subscription.IsGlobal() {
+			return nil, nil
+		}
+		return []string{subscription.AccountID}, nil
+	})
+}
+
+// check passes callers whose roles allow the access on any account without
+// looking anything up; anyone else must own one of the accounts accountIDs
+// returns
+func (s *AccessService) check(ctx context.Context, access auth.Access, accountIDs func() ([]string, error)) error {
+	principal := auth.PrincipalFromContext(ctx)
+	if principal == nil {
+		return models.ErrUnauthenticated
+	}
+	if principal.CanAccess("", access) {
+		return nil
+	}
+
+	ids, err := accountIDs()
+	if err != nil {
+		return err
+	}
+	for _, accountID := range ids {
+		account, err := s.accountStorage.GetAccountByID(ctx, accountID)
+		if err != nil {
+			return err
+		}
+		if principal.CanAccess(account.OwnerID, access) {
+			return nil
+		}
+	}
+
+	utils.LoggerFromContext(ctx).Warn("Access denied",
+		slog.String("service", "access"),
+		slog.Any("account_ids", ids))
+	return models.Errorf(models.ErrForbidden, "%s does not own the account", describe(principal))
+}
+
+// describe names the caller in error messages
+func describe(principal *auth.Principal) string {
+	if principal.KeyName != "" {
+		return fmt.Sprintf("API key %q", principal.KeyName)
+	}
+	return fmt.Sprintf("subject %q", principal.Subject)
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/appy29/banking-ledger-service/auth"
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func newAccessTestService(ctrl *gomock.Controller) (*AccessService, *MockAccountStorage, *MockTransactionStorage, *MockScheduleStorage) {
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	mockScheduleStorage := NewMockScheduleStorage(ctrl)
+	mockWebhookStorage := NewMockWebhookStorage(ctrl)
+	return NewAccessService(mockAccountStorage, mockTransactionStorage, mockScheduleStorage, mockWebhookStorage), mockAccountStorage, mockTransactionStorage, mockScheduleStorage
+}
+
+func asPrincipal(principal *auth.Principal) context.Context {
+	return auth.WithPrincipal(context.Background(), principal)
+}
+
+func TestAccessService_CheckAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockAccountStorage, _, _ := newAccessTestService(ctrl)
+	mockAccountStorage.EXPECT().
+		GetAccountByID(gomock.Any(), "acc_12345").
+		Return(&models.Account{ID: "acc_12345", OwnerID: "user_1"}, nil).
+		AnyTimes()
+
+	owner := asPrincipal(&auth.Principal{Subject: "user_1"})
+	assert.NoError(t, service.CheckAccount(owner, "acc_12345", auth.AccessWrite))
+
+	stranger := asPrincipal(&auth.Principal{Subject: "user_2"})
+	assert.ErrorIs(t, service.CheckAccount(stranger, "acc_12345", auth.AccessRead), models.ErrForbidden)
+
+	operator := asPrincipal(&auth.Principal{Subject: "ops", Roles: []string{auth.RoleOperator}})
+	assert.NoError(t, service.CheckAccount(operator, "acc_12345", auth.AccessRead))
+	assert.ErrorIs(t, service.CheckAccount(operator, "acc_12345", auth.AccessWrite), models.ErrForbidden)
+
+	assert.ErrorIs(t, service.CheckAccount(context.Background(), "acc_12345", auth.AccessRead), models.ErrUnauthenticated)
+}
+
+func TestAccessService_AdminSkipsLookup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// No storage calls are expected
+	service, _, _, _ := newAccessTestService(ctrl)
+	admin := asPrincipal(&auth.Principal{Subject: "root", Roles: []string{auth.RoleAdmin}})
+
+	assert.NoError(t, service.CheckAccount(admin, "acc_12345", auth.AccessWrite))
+	assert.NoError(t, service.CheckHold(admin, "hold_12345", auth.AccessWrite))
+	assert.NoError(t, service.CheckSchedule(admin, "sch_12345", auth.AccessWrite))
+}
+
+func TestAccessService_CheckRecords(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockAccountStorage, mockTransactionStorage, mockScheduleStorage := newAccessTestService(ctrl)
+	mockAccountStorage.EXPECT().
+		GetAccountByID(gomock.Any(), "acc_mine").
+		Return(&models.Account{ID: "acc_mine", OwnerID: "user_1"}, nil).
+		AnyTimes()
+	mockAccountStorage.EXPECT().
+		GetAccountByID(gomock.Any(), "acc_theirs").
+		Return(&models.Account{ID: "acc_theirs", OwnerID: "user_2"}, nil).
+		AnyTimes()
+
+	ctx := asPrincipal(&auth.Principal{Subject: "user_1"})
+
+	t.Run("transaction", func(t *testing.T) {
+		mockTransactionStorage.EXPECT().
+			GetTransactionByID(gomock.Any(), "txn_mine").
+			Return(&models.Transaction{ID: "txn_mine", AccountID: "acc_mine"}, nil)
+		mockTransactionStorage.EXPECT().
+			GetTransactionByID(gomock.Any(), "txn_theirs").
+			Return(&models.Transaction{ID: "txn_theirs", AccountID: "acc_theirs"}, nil)
+
+		assert.NoError(t, service.CheckTransaction(ctx, "txn_mine", auth.AccessRead))
+		assert.ErrorIs(t, service.CheckTransaction(ctx, "txn_theirs", auth.AccessRead), models.ErrForbidden)
+	})
+
+	t.Run("transfer into the caller's account", func(t *testing.T) {
+		mockTransactionStorage.EXPECT().
+			GetTransactionsByTransferID(gomock.Any(), "tfr_12345").
+			Return([]models.Transaction{{AccountID: "acc_theirs"}, {AccountID: "acc_mine"}}, nil)
+
+		assert.NoError(t, service.CheckTransfer(ctx, "tfr_12345", auth.AccessRead))
+	})
+
+	t.Run("unknown transfer", func(t *testing.T) {
+		mockTransactionStorage.EXPECT().
+			GetTransactionsByTransferID(gomock.Any(), "tfr_missing").
+			Return(nil, nil)
+
+		assert.ErrorIs(t, service.CheckTransfer(ctx, "tfr_missing", auth.AccessRead), models.ErrTransferNotFound)
+	})
+
+	t.Run("hold", func(t *testing.T) {
+		mockAccountStorage.EXPECT().
+			GetHold(gomock.Any(), "hold_12345").
+			Return(&models.Hold{ID: "hold_12345", AccountID: "acc_theirs"}, nil)
+
+		assert.ErrorIs(t, service.CheckHold(ctx, "hold_12345", auth.AccessWrite), models.ErrForbidden)
+	})
+
+	t.Run("schedule", func(t *testing.T) {
+		mockScheduleStorage.EXPECT().
+			GetSchedule(gomock.Any(), "sch_12345").
+			Return(&models.Schedule{ID: "sch_12345", AccountID: "acc_mine"}, nil)
+
+		assert.NoError(t, service.CheckSchedule(ctx, "sch_12345", auth.AccessWrite))
+	})
+
+	t.Run("missing schedule", func(t *testing.T) {
+		mockScheduleStorage.EXPECT().
+			GetSchedule(gomock.Any(), "sch_missing").
+			Return(nil, models.ErrScheduleNotFound)
+
+		assert.ErrorIs(t, service.CheckSchedule(ctx, "sch_missing", auth.AccessRead), models.ErrScheduleNotFound)
+	})
+}
+
+func TestAccessService_CheckWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockWebhookStorage := NewMockWebhookStorage(ctrl)
+	service := NewAccessService(mockAccountStorage, NewMockTransactionStorage(ctrl), NewMockScheduleStorage(ctrl), mockWebhookStorage)
+
+	mockAccountStorage.EXPECT().
+		GetAccountByID(gomock.Any(), "acc_mine").
+		Return(&models.Account{ID: "acc_mine", OwnerID: "user_1"}, nil).
+		AnyTimes()
+	mockWebhookStorage.EXPECT().
+		GetWebhookSubscription(gomock.Any(), "whk_account").
+		Return(&models.WebhookSubscription{ID: "whk_account", AccountID: "acc_mine"}, nil).
+		AnyTimes()
+	mockWebhookStorage.EXPECT().
+		GetWebhookSubscription(gomock.Any(), "whk_global").
+		Return(&models.WebhookSubscription{ID: "whk_global"}, nil).
+		AnyTimes()
+
+	owner := asPrincipal(&auth.Principal{Subject: "user_1"})
+	assert.NoError(t, service.CheckWebhook(owner, "whk_account", auth.AccessWrite))
+	assert.ErrorIs(t, service.CheckWebhook(owner, "whk_global", auth.AccessRead), models.ErrForbidden)
+
+	operator := asPrincipal(&auth.Principal{Subject: "ops", Roles: []string{auth.RoleOperator}})
+	assert.NoError(t, service.CheckWebhook(operator, "whk_global", auth.AccessRead))
+	assert.ErrorIs(t, service.CheckWebhook(operator, "whk_global", auth.AccessWrite), models.ErrForbidden)
+}
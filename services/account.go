@@ -4,14 +4,17 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
+	"github.com/appy29/banking-ledger-service/audit"
 	"github.com/appy29/banking-ledger-service/models"
 	"github.com/appy29/banking-ledger-service/utils"
 )
 
 type AccountService struct {
-	storage AccountStorage
+	storage  AccountStorage
+	notifier EventNotifier // Told about new accounts, if set
 }
 
 func NewAccountService(storage AccountStorage) *AccountService {
@@ -20,33 +23,54 @@ func NewAccountService(storage AccountStorage) *AccountService {
 	}
 }
 
+// SetNotifier sets where webhook events for new accounts are sent
+func (s *AccountService) SetNotifier(notifier EventNotifier) {
+	s.notifier = notifier
+}
+
 func (s *AccountService) CreateAccount(ctx context.Context, req *models.CreateAccountRequest) (*models.Account, error) {
 	logger := utils.LoggerFromContext(ctx).With(slog.String("service", "account"))
 
 	logger.Info("Starting account creation",
 		slog.String("owner_name", req.OwnerName),
-		slog.Float64("initial_balance", req.InitialBalance))
+		slog.String("initial_balance", req.InitialBalance.String()))
 
 	// Validate request
 	if req.OwnerName == "" {
 		logger.Error("Validation failed: owner name is required")
-		return nil, fmt.Errorf("owner name is required")
+		return nil, models.Errorf(models.ErrInvalidRequest, "owner name is required")
 	}
 
 	if req.InitialBalance < 0 {
 		logger.Error("Validation failed: initial balance cannot be negative",
-			slog.Float64("initial_balance", req.InitialBalance))
-		return nil, fmt.Errorf("initial balance cannot be negative")
+			slog.String("initial_balance", req.InitialBalance.String()))
+		return nil, models.Errorf(models.ErrInvalidAmount, "initial balance cannot be negative")
+	}
+
+	currency := models.NormalizeCurrency(req.Currency)
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+
+	if err := models.ValidateAmountPrecision(req.InitialBalance, currency); err != nil {
+		logger.Error("Validation failed: invalid initial balance for currency",
+			slog.String("currency", currency),
+			slog.String("error", err.Error()))
+		return nil, err
 	}
 
 	// Create account model
 	account := &models.Account{
 		ID:        models.NewAccountID(),
 		OwnerName: req.OwnerName,
+		OwnerID:   strings.TrimSpace(req.OwnerID),
 		Balance:   req.InitialBalance,
+		Currency:  currency,
+		Status:    models.AccountActive,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
+	account.AvailableBalance = account.Balance
 
 	logger = logger.With(slog.String("account_id", account.ID))
 	logger.Info("Account model created, saving to storage")
@@ -57,6 +81,12 @@ func (s *AccountService) CreateAccount(ctx context.Context, req *models.CreateAc
 		return nil, fmt.Errorf("failed to create account: %w", err)
 	}
 
+	audit.FromContext(ctx).Balances([]models.BalanceChange{{AccountID: account.ID, NewBalance: account.Balance}})
+
+	if s.notifier != nil {
+		s.notifier.AccountCreated(ctx, account)
+	}
+
 	logger.Info("Account created successfully in storage")
 	return account, nil
 }
@@ -70,7 +100,7 @@ func (s *AccountService) GetAccountByID(ctx context.Context, accountID string) (
 
 	if accountID == "" {
 		logger.Error("Validation failed: account ID is required")
-		return nil, fmt.Errorf("account ID is required")
+		return nil, models.Errorf(models.ErrInvalidRequest, "account ID is required")
 	}
 
 	account, err := s.storage.GetAccountByID(ctx, accountID)
@@ -81,12 +111,12 @@ func (s *AccountService) GetAccountByID(ctx context.Context, accountID string) (
 
 	logger.Info("Account retrieved successfully",
 		slog.String("owner_name", account.OwnerName),
-		slog.Float64("balance", account.Balance))
+		slog.String("balance", account.Balance.String()))
 
 	return account, nil
 }
 
-func (s *AccountService) GetAccountBalance(ctx context.Context, accountID string) (float64, error) {
+func (s *AccountService) GetAccountBalance(ctx context.Context, accountID string) (models.Money, error) {
 	logger := utils.LoggerFromContext(ctx).With(
 		slog.String("service", "account"),
 		slog.String("account_id", accountID))
@@ -99,6 +129,135 @@ func (s *AccountService) GetAccountBalance(ctx context.Context, accountID string
 		return 0, err
 	}
 
-	logger.Info("Account balance retrieved successfully", slog.Float64("balance", account.Balance))
+	logger.Info("Account balance retrieved successfully", slog.String("balance", account.Balance.String()))
 	return account.Balance, nil
 }
+
+// GetLedgerBalance checks an account's stored balance against the balance
+// derived from its journal postings
+func (s *AccountService) GetLedgerBalance(ctx context.Context, accountID string) (*models.LedgerBalance, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "account"),
+		slog.String("account_id", accountID))
+
+	logger.Info("Starting ledger balance check")
+
+	if accountID == "" {
+		logger.Error("Validation failed: account ID is required")
+		return nil, models.Errorf(models.ErrInvalidRequest, "account ID is required")
+	}
+
+	balance, err := s.storage.GetLedgerBalance(ctx, accountID)
+	if err != nil {
+		logger.Error("Failed to get ledger balance from storage", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to get ledger balance: %w", err)
+	}
+
+	if !balance.InBalance {
+		logger.Warn("Account balance disagrees with its postings",
+			slog.String("balance", balance.Balance.String()),
+			slog.String("posted_balance", balance.PostedBalance.String()))
+	} else {
+		logger.Info("Ledger balance checked", slog.String("balance", balance.Balance.String()))
+	}
+
+	return balance, nil
+}
+
+// maxStatusReasonLength caps the reason recorded with a state change
+const maxStatusReasonLength = 500
+
+// ChangeAccountStatus freezes, unfreezes or closes an account. A reason is
+// required and is recorded with the change.
+func (s *AccountService) ChangeAccountStatus(ctx context.Context, accountID, status string, req *models.AccountStatusRequest) (*models.AccountStatusChange, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "account"),
+		slog.String("operation", "change_account_status"),
+		slog.String("account_id", accountID),
+		slog.String("status", status))
+
+	logger.Info("Starting account status change")
+
+	if accountID == "" {
+		logger.Error("Validation failed: account ID is required")
+		return nil, models.Errorf(models.ErrInvalidRequest, "account ID is required")
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		logger.Error("Validation failed: reason is required")
+		return nil, models.Errorf(models.ErrInvalidRequest, "a reason is required to change an account's status")
+	}
+	if len(reason) > maxStatusReasonLength {
+		logger.Error("Validation failed: reason too long")
+		return nil, models.Errorf(models.ErrInvalidRequest, "reason cannot exceed %d characters", maxStatusReasonLength)
+	}
+
+	change, err := s.storage.UpdateAccountStatus(ctx, accountID, status, reason)
+	if err != nil {
+		logger.Error("Failed to change account status", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to change account status: %w", err)
+	}
+
+	logger.Info("Account status changed",
+		slog.String("from_status", change.FromStatus),
+		slog.String("reason", change.Reason))
+
+	return change, nil
+}
+
+// GetAccountStatusHistory returns every state change of an account, oldest first
+func (s *AccountService) GetAccountStatusHistory(ctx context.Context, accountID string) ([]models.AccountStatusChange, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "account"),
+		slog.String("operation", "get_account_status_history"),
+		slog.String("account_id", accountID))
+
+	// Fails with ErrAccountNotFound for an unknown account rather than
+	// returning an empty history
+	if _, err := s.GetAccountByID(ctx, accountID); err != nil {
+		return nil, err
+	}
+
+	changes, err := s.storage.GetAccountStatusHistory(ctx, accountID)
+	if err != nil {
+		logger.Error("Failed to get account status history", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to get account status history: %w", err)
+	}
+
+	return changes, nil
+}
+
+// SetOverdraftLimit changes how far debits may take an account below zero. A
+// zero limit removes the overdraft.
+func (s *AccountService) SetOverdraftLimit(ctx context.Context, accountID string, req *models.OverdraftLimitRequest) (*models.Account, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "account"),
+		slog.String("operation", "set_overdraft_limit"),
+		slog.String("account_id", accountID))
+
+	logger.Info("Starting overdraft limit change", slog.String("overdraft_limit", req.OverdraftLimit.String()))
+
+	if req.OverdraftLimit.IsNegative() {
+		logger.Error("Validation failed: overdraft limit cannot be negative")
+		return nil, models.Errorf(models.ErrInvalidAmount, "overdraft limit cannot be negative")
+	}
+
+	account, err := s.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := models.ValidateAmountPrecision(req.OverdraftLimit, account.Currency); err != nil {
+		logger.Error("Validation failed: invalid overdraft limit for currency", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	account, err = s.storage.SetOverdraftLimit(ctx, accountID, req.OverdraftLimit)
+	if err != nil {
+		logger.Error("Failed to set overdraft limit", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to set overdraft limit: %w", err)
+	}
+
+	logger.Info("Overdraft limit changed", slog.String("available_balance", account.AvailableBalance.String()))
+	return account, nil
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -24,7 +25,7 @@ func TestAccountService_CreateAccount_Success(t *testing.T) {
 
 	req := &models.CreateAccountRequest{
 		OwnerName:      "John Doe",
-		InitialBalance: 500.00,
+		InitialBalance: models.MustParseMoney("500.00"),
 	}
 
 	// Setup context with logger for testing
@@ -37,9 +38,10 @@ func TestAccountService_CreateAccount_Success(t *testing.T) {
 		DoAndReturn(func(ctx context.Context, account *models.Account) error {
 			// Verify the account being created has correct values
 			assert.Equal(t, "John Doe", account.OwnerName)
-			assert.Equal(t, 500.00, account.Balance)
+			assert.Equal(t, models.MustParseMoney("500.00"), account.Balance)
 			assert.True(t, len(account.ID) > 0)
 			assert.True(t, account.ID[:4] == "acc_")
+			assert.Equal(t, models.AccountActive, account.Status)
 			return nil
 		}).
 		Times(1)
@@ -51,12 +53,35 @@ func TestAccountService_CreateAccount_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, account)
 	assert.Equal(t, "John Doe", account.OwnerName)
-	assert.Equal(t, 500.00, account.Balance)
+	assert.Equal(t, models.MustParseMoney("500.00"), account.Balance)
 	assert.True(t, len(account.ID) > 0)
 	assert.WithinDuration(t, time.Now(), account.CreatedAt, time.Second)
 	assert.WithinDuration(t, time.Now(), account.UpdatedAt, time.Second)
 }
 
+func TestAccountService_CreateAccount_NotifiesCreated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockAccountStorage(ctrl)
+	mockNotifier := NewMockEventNotifier(ctrl)
+	service := NewAccountService(mockStorage)
+	service.SetNotifier(mockNotifier)
+	ctx := context.Background()
+
+	mockStorage.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Return(nil)
+
+	var notified *models.Account
+	mockNotifier.EXPECT().
+		AccountCreated(gomock.Any(), gomock.Any()).
+		Do(func(ctx context.Context, account *models.Account) { notified = account })
+
+	account, err := service.CreateAccount(ctx, &models.CreateAccountRequest{OwnerName: "John Doe"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, account, notified)
+}
+
 func TestAccountService_CreateAccount_EmptyOwnerName(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -66,7 +91,7 @@ func TestAccountService_CreateAccount_EmptyOwnerName(t *testing.T) {
 
 	req := &models.CreateAccountRequest{
 		OwnerName:      "", // Empty name
-		InitialBalance: 500.00,
+		InitialBalance: models.MustParseMoney("500.00"),
 	}
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
@@ -93,7 +118,7 @@ func TestAccountService_CreateAccount_NegativeBalance(t *testing.T) {
 
 	req := &models.CreateAccountRequest{
 		OwnerName:      "John Doe",
-		InitialBalance: -100.00, // Negative balance
+		InitialBalance: models.MustParseMoney("-100.00"), // Negative balance
 	}
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
@@ -117,7 +142,7 @@ func TestAccountService_CreateAccount_StorageError(t *testing.T) {
 
 	req := &models.CreateAccountRequest{
 		OwnerName:      "John Doe",
-		InitialBalance: 500.00,
+		InitialBalance: models.MustParseMoney("500.00"),
 	}
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
@@ -148,7 +173,7 @@ func TestAccountService_CreateAccount_ZeroBalance(t *testing.T) {
 
 	req := &models.CreateAccountRequest{
 		OwnerName:      "John Doe",
-		InitialBalance: 0.00, // Zero balance should be allowed
+		InitialBalance: models.MustParseMoney("0.00"), // Zero balance should be allowed
 	}
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
@@ -165,7 +190,7 @@ func TestAccountService_CreateAccount_ZeroBalance(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	assert.NotNil(t, account)
-	assert.Equal(t, 0.00, account.Balance)
+	assert.Equal(t, models.MustParseMoney("0.00"), account.Balance)
 }
 
 func TestAccountService_GetAccountByID_Success(t *testing.T) {
@@ -179,7 +204,7 @@ func TestAccountService_GetAccountByID_Success(t *testing.T) {
 	expectedAccount := &models.Account{
 		ID:        accountID,
 		OwnerName: "John Doe",
-		Balance:   750.50,
+		Balance:   models.MustParseMoney("750.50"),
 		CreatedAt: time.Now().Add(-24 * time.Hour),
 		UpdatedAt: time.Now(),
 	}
@@ -200,7 +225,7 @@ func TestAccountService_GetAccountByID_Success(t *testing.T) {
 	assert.NotNil(t, account)
 	assert.Equal(t, accountID, account.ID)
 	assert.Equal(t, "John Doe", account.OwnerName)
-	assert.Equal(t, 750.50, account.Balance)
+	assert.Equal(t, models.MustParseMoney("750.50"), account.Balance)
 }
 
 func TestAccountService_GetAccountByID_EmptyID(t *testing.T) {
@@ -259,7 +284,7 @@ func TestAccountService_GetAccountBalance_Success(t *testing.T) {
 	expectedAccount := &models.Account{
 		ID:        accountID,
 		OwnerName: "John Doe",
-		Balance:   1250.75,
+		Balance:   models.MustParseMoney("1250.75"),
 		CreatedAt: time.Now().Add(-24 * time.Hour),
 		UpdatedAt: time.Now(),
 	}
@@ -277,7 +302,7 @@ func TestAccountService_GetAccountBalance_Success(t *testing.T) {
 
 	// Assert
 	assert.NoError(t, err)
-	assert.Equal(t, 1250.75, balance)
+	assert.Equal(t, models.MustParseMoney("1250.75"), balance)
 }
 
 func TestAccountService_GetAccountBalance_AccountNotFound(t *testing.T) {
@@ -302,6 +327,154 @@ func TestAccountService_GetAccountBalance_AccountNotFound(t *testing.T) {
 
 	// Assert
 	assert.Error(t, err)
-	assert.Equal(t, 0.0, balance)
+	assert.Equal(t, models.Money(0), balance)
 	assert.Contains(t, err.Error(), "failed to get account")
 }
+
+func TestAccountService_GetLedgerBalance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockAccountStorage(ctrl)
+	service := NewAccountService(mockStorage)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	ctx := utils.WithLogger(context.Background(), logger)
+
+	mockStorage.EXPECT().
+		GetLedgerBalance(ctx, "acc_12345").
+		Return(&models.LedgerBalance{
+			AccountID:     "acc_12345",
+			Currency:      "USD",
+			Balance:       models.MustParseMoney("100.00"),
+			PostedBalance: models.MustParseMoney("90.00"),
+		}, nil).
+		Times(1)
+
+	balance, err := service.GetLedgerBalance(ctx, "acc_12345")
+
+	assert.NoError(t, err)
+	assert.False(t, balance.InBalance)
+	assert.Equal(t, models.MustParseMoney("90.00"), balance.PostedBalance)
+
+	_, err = service.GetLedgerBalance(ctx, "")
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+}
+
+func TestAccountService_ChangeAccountStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockAccountStorage(ctrl)
+	service := NewAccountService(mockStorage)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	ctx := utils.WithLogger(context.Background(), logger)
+
+	mockStorage.EXPECT().
+		UpdateAccountStatus(ctx, "acc_12345", models.AccountFrozen, "Card reported stolen").
+		Return(&models.AccountStatusChange{
+			ID:         1,
+			AccountID:  "acc_12345",
+			FromStatus: models.AccountActive,
+			ToStatus:   models.AccountFrozen,
+			Reason:     "Card reported stolen",
+		}, nil).
+		Times(1)
+
+	// The reason is trimmed before it is recorded
+	change, err := service.ChangeAccountStatus(ctx, "acc_12345", models.AccountFrozen, &models.AccountStatusRequest{Reason: "  Card reported stolen "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.AccountActive, change.FromStatus)
+	assert.Equal(t, models.AccountFrozen, change.ToStatus)
+}
+
+func TestAccountService_ChangeAccountStatus_RequiresReason(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockAccountStorage(ctrl)
+	service := NewAccountService(mockStorage)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	ctx := utils.WithLogger(context.Background(), logger)
+
+	// No storage calls expected
+	_, err := service.ChangeAccountStatus(ctx, "acc_12345", models.AccountClosed, &models.AccountStatusRequest{Reason: "   "})
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+
+	_, err = service.ChangeAccountStatus(ctx, "acc_12345", models.AccountClosed, &models.AccountStatusRequest{Reason: strings.Repeat("x", maxStatusReasonLength+1)})
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+}
+
+func TestAccountService_ChangeAccountStatus_InvalidChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockAccountStorage(ctrl)
+	service := NewAccountService(mockStorage)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	ctx := utils.WithLogger(context.Background(), logger)
+
+	mockStorage.EXPECT().
+		UpdateAccountStatus(ctx, "acc_12345", models.AccountActive, "Reopen").
+		Return(nil, models.Errorf(models.ErrInvalidStatusChange, "account cannot change from closed to active")).
+		Times(1)
+
+	_, err := service.ChangeAccountStatus(ctx, "acc_12345", models.AccountActive, &models.AccountStatusRequest{Reason: "Reopen"})
+	assert.True(t, errors.Is(err, models.ErrInvalidStatusChange))
+}
+
+func TestAccountService_SetOverdraftLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockAccountStorage(ctrl)
+	service := NewAccountService(mockStorage)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	ctx := utils.WithLogger(context.Background(), logger)
+
+	mockStorage.EXPECT().
+		GetAccountByID(ctx, "acc_12345").
+		Return(&models.Account{ID: "acc_12345", Currency: "USD", Balance: models.MustParseMoney("50.00")}, nil).
+		Times(1)
+	mockStorage.EXPECT().
+		SetOverdraftLimit(ctx, "acc_12345", models.MustParseMoney("250.00")).
+		Return(&models.Account{
+			ID:               "acc_12345",
+			Currency:         "USD",
+			Balance:          models.MustParseMoney("50.00"),
+			OverdraftLimit:   models.MustParseMoney("250.00"),
+			AvailableBalance: models.MustParseMoney("300.00"),
+		}, nil).
+		Times(1)
+
+	account, err := service.SetOverdraftLimit(ctx, "acc_12345", &models.OverdraftLimitRequest{OverdraftLimit: models.MustParseMoney("250.00")})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.MustParseMoney("250.00"), account.OverdraftLimit)
+	assert.Equal(t, models.MustParseMoney("300.00"), account.AvailableBalance)
+}
+
+func TestAccountService_SetOverdraftLimit_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockAccountStorage(ctrl)
+	service := NewAccountService(mockStorage)
+
+	_, err := service.SetOverdraftLimit(context.Background(), "acc_12345", &models.OverdraftLimitRequest{OverdraftLimit: models.MustParseMoney("-1.00")})
+	assert.True(t, errors.Is(err, models.ErrInvalidAmount))
+
+	// JPY has no minor units
+	mockStorage.EXPECT().
+		GetAccountByID(gomock.Any(), "acc_12345").
+		Return(&models.Account{ID: "acc_12345", Currency: "JPY"}, nil).
+		Times(1)
+
+	_, err = service.SetOverdraftLimit(context.Background(), "acc_12345", &models.OverdraftLimitRequest{OverdraftLimit: models.MustParseMoney("100.50")})
+	assert.True(t, errors.Is(err, models.ErrInvalidPrecision))
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/utils"
+)
+
+// AuditService writes mutating API calls to the audit trail and serves
+// queries over it
+type AuditService struct {
+	storage AuditStorage
+}
+
+func NewAuditService(storage AuditStorage) *AuditService {
+	return &AuditService{storage: storage}
+}
+
+// Record appends a record to the audit trail, assigning its ID and timestamp
+// if they are not set
+func (s *AuditService) Record(ctx context.Context, record *models.AuditRecord) error {
+	if record.ID == "" {
+		record.ID = models.NewAuditID()
+	}
+	if record.Timestamp.IsZero() {
+		record.Timestamp = time.Now()
+	}
+	// Postgres keeps microseconds; truncating here keeps the record equal to
+	// what a query returns
+	record.Timestamp = record.Timestamp.UTC().Truncate(time.Microsecond)
+
+	if err := s.storage.InsertAuditRecord(ctx, record); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+// Query returns a page of the audit trail matching filter, newest first
+func (s *AuditService) Query(ctx context.Context, filter *models.AuditFilter, cursor string, limit int) (*models.AuditPage, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "audit"),
+		slog.String("operation", "query"))
+
+	if filter == nil {
+		filter = &models.AuditFilter{}
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	var after *models.AuditCursor
+	if cursor != "" {
+		var err error
+		if after, err = models.DecodeTransactionCursor(cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	// Fetch one extra record to learn whether another page follows
+	records, err := s.storage.GetAuditRecordsPage(ctx, filter, after, limit+1)
+	if err != nil {
+		logger.Error("Failed to query audit trail", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to query audit trail: %w", err)
+	}
+
+	page := &models.AuditPage{Records: records}
+	if len(records) > limit {
+		page.Records = records[:limit]
+		page.HasMore = true
+		last := page.Records[limit-1]
+		page.NextCursor = (&models.AuditCursor{Timestamp: last.Timestamp, ID: last.ID}).Encode()
+	}
+	if page.Records == nil {
+		page.Records = []models.AuditRecord{}
+	}
+
+	return page, nil
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/audit"
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuditService_Record(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuditStorage := NewMockAuditStorage(ctrl)
+	service := NewAuditService(mockAuditStorage)
+
+	mockAuditStorage.EXPECT().
+		InsertAuditRecord(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, record *models.AuditRecord) error {
+			assert.Contains(t, record.ID, "aud_")
+			assert.False(t, record.Timestamp.IsZero())
+			assert.Equal(t, time.UTC, record.Timestamp.Location())
+			return nil
+		})
+
+	err := service.Record(context.Background(), &models.AuditRecord{Action: "POST /api/v1/accounts", Outcome: models.AuditSucceeded})
+	assert.NoError(t, err)
+
+	mockAuditStorage.EXPECT().
+		InsertAuditRecord(gomock.Any(), gomock.Any()).
+		Return(errors.New("database error"))
+
+	err = service.Record(context.Background(), &models.AuditRecord{Action: "POST /api/v1/accounts"})
+	assert.Error(t, err)
+}
+
+func TestAuditService_Query(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuditStorage := NewMockAuditStorage(ctrl)
+	service := NewAuditService(mockAuditStorage)
+
+	now := time.Now().UTC()
+	records := make([]models.AuditRecord, 3)
+	for i := range records {
+		records[i] = models.AuditRecord{ID: fmt.Sprintf("aud_%d", 3-i), Timestamp: now.Add(-time.Duration(i) * time.Minute)}
+	}
+	filter := &models.AuditFilter{Actor: "user_1", AccountID: "acc_12345"}
+
+	mockAuditStorage.EXPECT().
+		GetAuditRecordsPage(gomock.Any(), filter, nil, 3).
+		Return(records, nil)
+
+	page, err := service.Query(context.Background(), filter, "", 2)
+	require.NoError(t, err)
+	assert.Len(t, page.Records, 2)
+	assert.True(t, page.HasMore)
+
+	// The cursor continues after the last record returned
+	mockAuditStorage.EXPECT().
+		GetAuditRecordsPage(gomock.Any(), filter, &models.AuditCursor{Timestamp: records[1].Timestamp, ID: records[1].ID}, 3).
+		Return(records[2:], nil)
+
+	page, err = service.Query(context.Background(), filter, page.NextCursor, 2)
+	require.NoError(t, err)
+	assert.Len(t, page.Records, 1)
+	assert.False(t, page.HasMore)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestAuditService_QueryInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Storage is never reached
+	service := NewAuditService(NewMockAuditStorage(ctrl))
+	now := time.Now()
+
+	_, err := service.Query(context.Background(), &models.AuditFilter{From: now, To: now.Add(-time.Hour)}, "", 10)
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+
+	_, err = service.Query(context.Background(), nil, "not-a-cursor", 10)
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+}
+
+func TestTransactionService_ProcessTransaction_FillsAuditEntry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
+
+	expectPostedEntry(t, mockAccountStorage, "acc_12345", "deposit", models.MustParseMoney("250.00"), models.MustParseMoney("500.00"), models.MustParseMoney("750.00"))
+	mockTransactionStorage.EXPECT().CreateTransaction(gomock.Any(), gomock.Any()).Return(nil)
+
+	entry := audit.NewEntry()
+	ctx := audit.WithEntry(context.Background(), entry)
+	transaction, err := service.ProcessTransaction(ctx, "acc_12345", &models.TransactionRequest{Type: "deposit", Amount: models.MustParseMoney("250.00")})
+	require.NoError(t, err)
+
+	var record models.AuditRecord
+	entry.Fill(&record)
+	assert.Equal(t, []string{"acc_12345"}, record.AccountIDs)
+	assert.Equal(t, transaction.TransactionID, record.Targets[audit.TargetTransaction])
+	assert.Equal(t, []models.BalanceChange{{
+		AccountID:       "acc_12345",
+		PreviousBalance: models.MustParseMoney("500.00"),
+		NewBalance:      models.MustParseMoney("750.00"),
+	}}, record.Balances)
+}
+
+func TestTransactionService_ProcessTransaction_RolledBackLeavesNoAuditBalances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
+
+	expectPostedEntry(t, mockAccountStorage, "acc_12345", "deposit", models.MustParseMoney("100.00"), models.MustParseMoney("500.00"), models.MustParseMoney("600.00"))
+	mockTransactionStorage.EXPECT().CreateTransaction(gomock.Any(), gomock.Any()).Return(errors.New("database error"))
+	expectPostedEntry(t, mockAccountStorage, "acc_12345", "withdraw", models.MustParseMoney("100.00"), models.MustParseMoney("600.00"), models.MustParseMoney("500.00"))
+
+	entry := audit.NewEntry()
+	ctx := audit.WithEntry(context.Background(), entry)
+	_, err := service.ProcessTransaction(ctx, "acc_12345", &models.TransactionRequest{Type: "deposit", Amount: models.MustParseMoney("100.00")})
+	require.Error(t, err)
+
+	// The posting was reversed, so the record must not claim the balance moved
+	var record models.AuditRecord
+	entry.Fill(&record)
+	assert.Empty(t, record.Balances)
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/utils"
+)
+
+// VerifyTransactionChain walks an account's hash chain of sealed records and
+// reports the first link that does not verify
+func (s *TransactionService) VerifyTransactionChain(ctx context.Context, accountID string) (*models.ChainVerification, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "transaction"),
+		slog.String("operation", "verify_transaction_chain"),
+		slog.String("account_id", accountID))
+
+	logger.Info("Verifying transaction chain")
+
+	if _, err := s.accountStorage.GetAccountByID(ctx, accountID); err != nil {
+		logger.Error("Account not found for chain verification", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	records, err := s.transactionStorage.GetSealedTransactions(ctx, accountID)
+	if err != nil {
+		logger.Error("Failed to get sealed transactions", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to verify transaction chain: %w", err)
+	}
+
+	result := models.VerifyChain(accountID, records)
+	if !result.Valid {
+		logger.Warn("Transaction chain is broken",
+			slog.Int64("chain_seq", result.BrokenLink.ChainSeq),
+			slog.String("transaction_id", result.BrokenLink.TransactionID),
+			slog.String("reason", result.BrokenLink.Reason))
+	} else {
+		logger.Info("Transaction chain verified", slog.Int("links", result.Verified))
+	}
+
+	return result, nil
+}
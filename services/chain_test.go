@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTransactionService_VerifyTransactionChain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	ctx := context.Background()
+
+	first := loggedTransaction("txn_1", "deposit", "100.00", "0", "100.00")
+	first.Seal(nil)
+	second := loggedTransaction("txn_2", "withdraw", "30.00", "100.00", "70.00")
+	second.Seal(&first)
+	second.Amount = models.MustParseMoney("3.00")
+
+	mockAccountStorage.EXPECT().GetAccountByID(ctx, "acc_12345").Return(reconcileAccount("70.00"), nil)
+	mockTransactionStorage.EXPECT().
+		GetSealedTransactions(ctx, "acc_12345").
+		Return([]models.Transaction{first, second}, nil)
+
+	verification, err := service.VerifyTransactionChain(ctx, "acc_12345")
+
+	require.NoError(t, err)
+	assert.False(t, verification.Valid)
+	assert.Equal(t, 1, verification.Verified)
+	assert.Equal(t, first.Hash, verification.HeadHash)
+	assert.Equal(t, "txn_2", verification.BrokenLink.TransactionID)
+}
+
+func TestTransactionService_VerifyTransactionChain_AccountNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, NewMockTransactionStorage(ctrl))
+	ctx := context.Background()
+
+	mockAccountStorage.EXPECT().GetAccountByID(ctx, "acc_missing").Return(nil, models.ErrAccountNotFound)
+
+	_, err := service.VerifyTransactionChain(ctx, "acc_missing")
+	assert.True(t, errors.Is(err, models.ErrAccountNotFound))
+}
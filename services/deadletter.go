@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/utils"
+)
+
+// DeadLetterService records dead-lettered queue messages and lets operators
+// replay or discard them
+type DeadLetterService struct {
+	storage   DeadLetterStorage
+	publisher MessagePublisher
+}
+
+func NewDeadLetterService(storage DeadLetterStorage, publisher MessagePublisher) *DeadLetterService {
+	return &DeadLetterService{
+		storage:   storage,
+		publisher: publisher,
+	}
+}
+
+// RecordDeadLetter stores a message taken off the dead letter queue
+func (s *DeadLetterService) RecordDeadLetter(ctx context.Context, deadLetter *models.DeadLetter) error {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "dead_letter"),
+		slog.String("operation", "record"),
+		slog.String("message_id", deadLetter.MessageID))
+
+	if err := s.storage.SaveDeadLetter(ctx, deadLetter); err != nil {
+		logger.Error("Failed to save dead letter", slog.String("error", err.Error()))
+		return err
+	}
+
+	logger.Warn("Dead letter recorded",
+		slog.String("dead_letter_id", deadLetter.ID),
+		slog.String("reason", deadLetter.Reason),
+		slog.String("queue", deadLetter.Queue))
+	return nil
+}
+
+// ListDeadLetters returns a page of dead letters, optionally filtered by status
+func (s *DeadLetterService) ListDeadLetters(ctx context.Context, status string, page, limit int) ([]models.DeadLetter, int64, error) {
+	switch status {
+	case "", "dead", "replayed", "discarded":
+	default:
+		return nil, 0, models.Errorf(models.ErrInvalidDeadLetterStatus, "invalid dead letter status: %s", status)
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	deadLetters, total, err := s.storage.GetDeadLetters(ctx, status, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get dead letters: %w", err)
+	}
+
+	return deadLetters, total, nil
+}
+
+func (s *DeadLetterService) GetDeadLetter(ctx context.Context, id string) (*models.DeadLetter, error) {
+	if id == "" {
+		return nil, models.Errorf(models.ErrInvalidRequest, "dead letter ID is required")
+	}
+
+	return s.storage.GetDeadLetterByID(ctx, id)
+}
+
+// ReplayDeadLetter publishes the message back to the transaction queue. A
+// replayed message is safe to process twice: the worker ignores records that
+// are no longer pending, and a balance change is only ever applied once.
+func (s *DeadLetterService) ReplayDeadLetter(ctx context.Context, id string) (*models.DeadLetter, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "dead_letter"),
+		slog.String("operation", "replay"),
+		slog.String("dead_letter_id", id))
+
+	deadLetter, err := s.claim(ctx, id, "replayed")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.publisher.RepublishMessage(ctx, deadLetter.MessageID, []byte(deadLetter.Body)); err != nil {
+		logger.Error("Failed to republish dead letter", slog.String("error", err.Error()))
+
+		// Put it back so it can be replayed later
+		if _, revertErr := s.storage.TransitionDeadLetter(ctx, id, "replayed", "dead", nil); revertErr != nil {
+			logger.Error("Failed to reset dead letter status", slog.String("error", revertErr.Error()))
+		}
+		return nil, fmt.Errorf("failed to replay dead letter: %w", err)
+	}
+
+	logger.Info("Dead letter replayed", slog.String("message_id", deadLetter.MessageID))
+	return deadLetter, nil
+}
+
+// DiscardDeadLetter marks a dead letter as handled without replaying it
+func (s *DeadLetterService) DiscardDeadLetter(ctx context.Context, id string) (*models.DeadLetter, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "dead_letter"),
+		slog.String("operation", "discard"),
+		slog.String("dead_letter_id", id))
+
+	deadLetter, err := s.claim(ctx, id, "discarded")
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Dead letter discarded", slog.String("message_id", deadLetter.MessageID))
+	return deadLetter, nil
+}
+
+// claim moves an unresolved dead letter to status, so two operators cannot
+// both act on it
+func (s *DeadLetterService) claim(ctx context.Context, id, status string) (*models.DeadLetter, error) {
+	deadLetter, err := s.GetDeadLetter(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadLetter.Status != "dead" {
+		return nil, models.Errorf(models.ErrDeadLetterResolved, "dead letter is already %s", deadLetter.Status)
+	}
+
+	now := time.Now()
+	claimed, err := s.storage.TransitionDeadLetter(ctx, id, "dead", status, &now)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, models.ErrDeadLetterResolved
+	}
+
+	deadLetter.Status = status
+	deadLetter.ResolvedAt = &now
+	return deadLetter, nil
+}
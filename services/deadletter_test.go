@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/queue"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func newTestDeadLetter(status string) *models.DeadLetter {
+	return &models.DeadLetter{
+		ID:         "dlq_1",
+		MessageID:  "txn_1",
+		Reason:     "rejected",
+		Queue:      "transaction_queue",
+		Body:       `{"id":"txn_1","type":"deposit"}`,
+		Status:     status,
+		ReceivedAt: time.Now(),
+	}
+}
+
+func TestDeadLetterService_ReplayDeadLetter_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockDeadLetterStorage(ctrl)
+	mockPublisher := NewMockMessagePublisher(ctrl)
+	service := NewDeadLetterService(mockStorage, mockPublisher)
+
+	gomock.InOrder(
+		mockStorage.EXPECT().GetDeadLetterByID(gomock.Any(), "dlq_1").Return(newTestDeadLetter("dead"), nil),
+		mockStorage.EXPECT().TransitionDeadLetter(gomock.Any(), "dlq_1", "dead", "replayed", gomock.Not(gomock.Nil())).Return(true, nil),
+		mockPublisher.EXPECT().RepublishMessage(gomock.Any(), "txn_1", []byte(`{"id":"txn_1","type":"deposit"}`)).Return(nil),
+	)
+
+	deadLetter, err := service.ReplayDeadLetter(context.Background(), "dlq_1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "replayed", deadLetter.Status)
+	assert.NotNil(t, deadLetter.ResolvedAt)
+}
+
+func TestDeadLetterService_ReplayDeadLetter_PublishFailureResetsStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockDeadLetterStorage(ctrl)
+	mockPublisher := NewMockMessagePublisher(ctrl)
+	service := NewDeadLetterService(mockStorage, mockPublisher)
+
+	gomock.InOrder(
+		mockStorage.EXPECT().GetDeadLetterByID(gomock.Any(), "dlq_1").Return(newTestDeadLetter("dead"), nil),
+		mockStorage.EXPECT().TransitionDeadLetter(gomock.Any(), "dlq_1", "dead", "replayed", gomock.Any()).Return(true, nil),
+		mockPublisher.EXPECT().RepublishMessage(gomock.Any(), "txn_1", gomock.Any()).Return(queue.ErrNotConnected),
+		mockStorage.EXPECT().TransitionDeadLetter(gomock.Any(), "dlq_1", "replayed", "dead", nil).Return(true, nil),
+	)
+
+	deadLetter, err := service.ReplayDeadLetter(context.Background(), "dlq_1")
+
+	assert.Error(t, err)
+	assert.Nil(t, deadLetter)
+	assert.Contains(t, err.Error(), "not connected")
+}
+
+func TestDeadLetterService_ReplayDeadLetter_AlreadyResolved(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockDeadLetterStorage(ctrl)
+	mockPublisher := NewMockMessagePublisher(ctrl)
+	service := NewDeadLetterService(mockStorage, mockPublisher)
+
+	mockStorage.EXPECT().GetDeadLetterByID(gomock.Any(), "dlq_1").Return(newTestDeadLetter("discarded"), nil)
+
+	_, err := service.ReplayDeadLetter(context.Background(), "dlq_1")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "dead letter is already discarded")
+}
+
+func TestDeadLetterService_DiscardDeadLetter_LostRace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockDeadLetterStorage(ctrl)
+	service := NewDeadLetterService(mockStorage, NewMockMessagePublisher(ctrl))
+
+	mockStorage.EXPECT().GetDeadLetterByID(gomock.Any(), "dlq_1").Return(newTestDeadLetter("dead"), nil)
+	mockStorage.EXPECT().TransitionDeadLetter(gomock.Any(), "dlq_1", "dead", "discarded", gomock.Any()).Return(false, nil)
+
+	_, err := service.DiscardDeadLetter(context.Background(), "dlq_1")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "dead letter is already resolved")
+}
+
+func TestDeadLetterService_ListDeadLetters_InvalidStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewDeadLetterService(NewMockDeadLetterStorage(ctrl), NewMockMessagePublisher(ctrl))
+
+	_, _, err := service.ListDeadLetters(context.Background(), "archived", 1, 10)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid dead letter status")
+}
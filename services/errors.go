@@ -0,0 +1,42 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/appy29/banking-ledger-service/models"
+)
+
+// businessErrors are failures that retrying cannot fix
+var businessErrors = []error{
+	models.ErrInvalidRequest,
+	models.ErrInsufficientFunds,
+	models.ErrLimitExceeded,
+	models.ErrInvalidTransactionType,
+	models.ErrAccountNotFound,
+	models.ErrAccountFrozen,
+	models.ErrAccountClosed,
+	models.ErrInvalidAmount,
+	models.ErrNotPending,
+	models.ErrHoldNotFound,
+	models.ErrHoldNotActive,
+	models.ErrSameAccount,
+	models.ErrCurrencyMismatch,
+	models.ErrUnsupportedCurrency,
+	models.ErrInvalidPrecision,
+	models.ErrAlreadyApplied,
+	models.ErrAlreadyReversed,
+	models.ErrNotReversible,
+	models.ErrTransactionSealed,
+	models.ErrUnbalancedEntry,
+}
+
+// IsBusinessError checks if error is due to business logic. Anything else is
+// treated as a system error (database or network) and may succeed on retry.
+func IsBusinessError(err error) bool {
+	for _, businessErr := range businessErrors {
+		if errors.Is(err, businessErr) {
+			return true
+		}
+	}
+	return false
+}
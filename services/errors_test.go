@@ -0,0 +1,32 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsBusinessError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		business bool
+	}{
+		{"insufficient funds", fmt.Errorf("failed to update balance: %w", &models.InsufficientFundsError{}), true},
+		{"account not found", fmt.Errorf("failed to process transaction: %w", models.ErrAccountNotFound), true},
+		{"already applied", models.Errorf(models.ErrAlreadyApplied, "transaction already applied: txn_1"), true},
+		{"currency mismatch", models.Errorf(models.ErrCurrencyMismatch, "currency mismatch"), true},
+		{"database error", errors.New("failed to begin transaction: connection refused"), false},
+		// Matching is by identity, not by message text
+		{"lookalike message", errors.New("insufficient funds"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.business, IsBusinessError(tt.err))
+		})
+	}
+}
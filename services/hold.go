@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/audit"
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/utils"
+)
+
+// PlaceHold reserves funds on an account without moving them. The account's
+// available balance must cover the hold.
+func (s *TransactionService) PlaceHold(ctx context.Context, accountID string, req *models.CreateHoldRequest) (*models.Hold, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "transaction"),
+		slog.String("operation", "place_hold"),
+		slog.String("account_id", accountID))
+
+	logger.Info("Starting hold placement", slog.String("amount", req.Amount.String()))
+
+	if req.Amount <= 0 {
+		return nil, models.Errorf(models.ErrInvalidAmount, "amount must be greater than 0")
+	}
+
+	ttl := models.DefaultHoldTTL
+	if req.ExpiresInSeconds != 0 {
+		ttl = time.Duration(req.ExpiresInSeconds) * time.Second
+	}
+	if ttl <= 0 || ttl > models.MaxHoldTTL {
+		return nil, models.Errorf(models.ErrInvalidRequest, "expires_in_seconds must be between 1 and %d", int64(models.MaxHoldTTL/time.Second))
+	}
+
+	currency, err := s.resolveCurrency(ctx, accountID, req.Currency, req.Amount)
+	if err != nil {
+		logger.Error("Currency check failed", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to place hold: %w", err)
+	}
+
+	now := time.Now()
+	hold := &models.Hold{
+		ID:          models.NewHoldID(),
+		AccountID:   accountID,
+		Amount:      req.Amount,
+		Currency:    currency,
+		Description: req.Description,
+		Status:      models.HoldActive,
+		ExpiresAt:   now.Add(ttl),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.accountStorage.CreateHold(ctx, hold); err != nil {
+		logger.Error("Failed to place hold", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to place hold: %w", err)
+	}
+
+	audit.FromContext(ctx).Target(audit.TargetHold, hold.ID)
+
+	logger.Info("Hold placed",
+		slog.String("hold_id", hold.ID),
+		slog.Time("expires_at", hold.ExpiresAt))
+	return hold, nil
+}
+
+// GetHold returns a hold by ID
+func (s *TransactionService) GetHold(ctx context.Context, holdID string) (*models.Hold, error) {
+	if holdID == "" {
+		return nil, models.Errorf(models.ErrInvalidRequest, "hold ID is required")
+	}
+
+	hold, err := s.accountStorage.GetHold(ctx, holdID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hold: %w", err)
+	}
+	return hold, nil
+}
+
+// GetAccountHolds returns every hold placed on an account, newest first
+func (s *TransactionService) GetAccountHolds(ctx context.Context, accountID string) ([]models.Hold, error) {
+	if _, err := s.GetAccountByID(ctx, accountID); err != nil {
+		return nil, err
+	}
+
+	holds, err := s.accountStorage.GetAccountHolds(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holds: %w", err)
+	}
+	return holds, nil
+}
+
+// CaptureHold settles a hold by withdrawing the captured amount, the whole
+// hold unless a smaller amount is given. The rest of the hold is released.
+func (s *TransactionService) CaptureHold(ctx context.Context, holdID string, req *models.CaptureHoldRequest) (*models.HoldCapture, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "transaction"),
+		slog.String("operation", "capture_hold"),
+		slog.String("hold_id", holdID))
+
+	logger.Info("Starting hold capture", slog.String("amount", req.Amount.String()))
+
+	hold, err := s.GetHold(ctx, holdID)
+	if err != nil {
+		return nil, err
+	}
+	auditEntry := audit.FromContext(ctx)
+	auditEntry.Account(hold.AccountID)
+	if hold.Status != models.HoldActive {
+		return nil, models.Errorf(models.ErrHoldNotActive, "hold %s is %s", holdID, hold.Status)
+	}
+
+	amount := req.Amount
+	if amount == 0 {
+		amount = hold.Amount
+	}
+	if amount.IsNegative() {
+		return nil, models.Errorf(models.ErrInvalidAmount, "capture amount must be greater than 0")
+	}
+	if amount > hold.Amount {
+		return nil, models.Errorf(models.ErrInvalidAmount, "capture amount %s exceeds the hold amount %s", amount, hold.Amount)
+	}
+	if err := models.ValidateAmountPrecision(amount, hold.Currency); err != nil {
+		return nil, err
+	}
+
+	description := req.Description
+	if description == "" {
+		description = "Capture of hold " + holdID
+	}
+
+	transactionID := models.NewTransactionID()
+	logger = logger.With(slog.String("transaction_id", transactionID))
+
+	entry, err := models.NewTransactionEntry(transactionID, hold.AccountID, "withdraw", amount, hold.Currency, description)
+	if err != nil {
+		return nil, err
+	}
+
+	captured, changes, err := s.accountStorage.CaptureHold(ctx, holdID, entry)
+	if err != nil {
+		logger.Error("Failed to capture hold", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to capture hold: %w", err)
+	}
+	change := balanceChange(changes, hold.AccountID)
+
+	transaction := &models.Transaction{
+		ID:              transactionID,
+		TransactionID:   transactionID,
+		AccountID:       hold.AccountID,
+		Type:            "withdraw",
+		Amount:          amount,
+		PreviousBalance: change.PreviousBalance,
+		NewBalance:      change.NewBalance,
+		Currency:        hold.Currency,
+		Description:     description,
+		Timestamp:       time.Now(),
+		Status:          "completed",
+	}
+
+	if err := s.transactionStorage.CreateTransaction(ctx, transaction); err != nil {
+		logger.Error("Failed to save capture, reversing journal entry", slog.String("error", err.Error()))
+		s.reverseEntry(ctx, entry)
+		if err := s.accountStorage.ReopenHold(ctx, holdID, transactionID); err != nil {
+			logger.Error("Failed to reopen hold", slog.String("error", err.Error()))
+		}
+		return nil, fmt.Errorf("failed to save transaction: %w", err)
+	}
+
+	auditEntry.Target(audit.TargetTransaction, transactionID)
+	auditEntry.Balances(changes)
+
+	logger.Info("Hold captured",
+		slog.String("captured_amount", amount.String()),
+		slog.String("new_balance", change.NewBalance.String()))
+
+	return &models.HoldCapture{Hold: captured, Transaction: transaction}, nil
+}
+
+// ReleaseHold cancels an active hold, making its funds available again
+func (s *TransactionService) ReleaseHold(ctx context.Context, holdID string) (*models.Hold, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "transaction"),
+		slog.String("operation", "release_hold"),
+		slog.String("hold_id", holdID))
+
+	if holdID == "" {
+		return nil, models.Errorf(models.ErrInvalidRequest, "hold ID is required")
+	}
+
+	hold, err := s.accountStorage.ReleaseHold(ctx, holdID)
+	if err != nil {
+		logger.Error("Failed to release hold", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to release hold: %w", err)
+	}
+
+	audit.FromContext(ctx).Account(hold.AccountID)
+
+	logger.Info("Hold released", slog.String("amount", hold.Amount.String()))
+	return hold, nil
+}
+
+// ExpireHolds marks holds past their expiry as expired
+func (s *TransactionService) ExpireHolds(ctx context.Context) (int64, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "transaction"),
+		slog.String("operation", "expire_holds"))
+
+	expired, err := s.accountStorage.ExpireHolds(ctx, time.Now())
+	if err != nil {
+		logger.Error("Failed to expire holds", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	if expired > 0 {
+		logger.Info("Holds expired", slog.Int64("expired", expired))
+	}
+	return expired, nil
+}
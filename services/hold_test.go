@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func activeHold() *models.Hold {
+	return &models.Hold{
+		ID:        "hold_12345",
+		AccountID: "acc_12345",
+		Amount:    models.MustParseMoney("80.00"),
+		Currency:  "USD",
+		Status:    models.HoldActive,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+func TestTransactionService_PlaceHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	ctx := utils.WithLogger(context.Background(), logger)
+
+	mockAccountStorage.EXPECT().
+		CreateHold(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, hold *models.Hold) error {
+			assert.Equal(t, "acc_12345", hold.AccountID)
+			assert.Equal(t, models.MustParseMoney("80.00"), hold.Amount)
+			assert.Equal(t, "USD", hold.Currency)
+			assert.Equal(t, models.HoldActive, hold.Status)
+			assert.WithinDuration(t, time.Now().Add(15*time.Minute), hold.ExpiresAt, time.Second)
+			return nil
+		}).
+		Times(1)
+
+	hold, err := service.PlaceHold(ctx, "acc_12345", &models.CreateHoldRequest{
+		Amount:           models.MustParseMoney("80.00"),
+		Description:      "Card authorization",
+		ExpiresInSeconds: 900,
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, hold.ID, "hold_")
+}
+
+func TestTransactionService_PlaceHold_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
+
+	_, err := service.PlaceHold(context.Background(), "acc_12345", &models.CreateHoldRequest{})
+	assert.True(t, errors.Is(err, models.ErrInvalidAmount))
+
+	_, err = service.PlaceHold(context.Background(), "acc_12345", &models.CreateHoldRequest{
+		Amount:           models.MustParseMoney("10.00"),
+		ExpiresInSeconds: int64(models.MaxHoldTTL/time.Second) + 1,
+	})
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+
+	_, err = service.PlaceHold(context.Background(), "acc_12345", &models.CreateHoldRequest{
+		Amount:   models.MustParseMoney("10.00"),
+		Currency: "EUR",
+	})
+	assert.True(t, errors.Is(err, models.ErrCurrencyMismatch))
+
+	// The storage layer checks the available balance under the row lock
+	mockAccountStorage.EXPECT().
+		CreateHold(gomock.Any(), gomock.Any()).
+		Return(&models.InsufficientFundsError{AccountID: "acc_12345", Balance: models.MustParseMoney("5.00"), Requested: models.MustParseMoney("10.00")})
+
+	_, err = service.PlaceHold(context.Background(), "acc_12345", &models.CreateHoldRequest{Amount: models.MustParseMoney("10.00")})
+	assert.True(t, errors.Is(err, models.ErrInsufficientFunds))
+}
+
+func TestTransactionService_CaptureHold_Partial(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	ctx := utils.WithLogger(context.Background(), logger)
+
+	mockAccountStorage.EXPECT().GetHold(ctx, "hold_12345").Return(activeHold(), nil)
+	mockAccountStorage.EXPECT().
+		CaptureHold(ctx, "hold_12345", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, holdID string, entry *models.JournalEntry) (*models.Hold, []models.BalanceChange, error) {
+			assert.NoError(t, entry.Validate())
+			assert.Equal(t, "Capture of hold hold_12345", entry.Description)
+			for _, posting := range entry.Postings {
+				assert.Equal(t, models.MustParseMoney("60.00"), posting.Amount)
+				if posting.AccountID == "acc_12345" {
+					assert.Equal(t, models.Debit, posting.Direction)
+				}
+			}
+
+			hold := activeHold()
+			hold.Status = models.HoldCaptured
+			hold.CapturedAmount = models.MustParseMoney("60.00")
+			return hold, []models.BalanceChange{{
+				AccountID:       "acc_12345",
+				PreviousBalance: models.MustParseMoney("100.00"),
+				NewBalance:      models.MustParseMoney("40.00"),
+			}}, nil
+		})
+	mockTransactionStorage.EXPECT().
+		CreateTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, transaction *models.Transaction) error {
+			assert.Equal(t, "withdraw", transaction.Type)
+			assert.Equal(t, "completed", transaction.Status)
+			assert.Equal(t, models.MustParseMoney("60.00"), transaction.Amount)
+			assert.Equal(t, models.MustParseMoney("40.00"), transaction.NewBalance)
+			return nil
+		})
+
+	capture, err := service.CaptureHold(ctx, "hold_12345", &models.CaptureHoldRequest{Amount: models.MustParseMoney("60.00")})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.HoldCaptured, capture.Hold.Status)
+	assert.Equal(t, models.MustParseMoney("60.00"), capture.Hold.CapturedAmount)
+	assert.Equal(t, models.MustParseMoney("100.00"), capture.Transaction.PreviousBalance)
+}
+
+func TestTransactionService_CaptureHold_Rejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+
+	mockAccountStorage.EXPECT().GetHold(gomock.Any(), "hold_12345").Return(activeHold(), nil)
+	_, err := service.CaptureHold(context.Background(), "hold_12345", &models.CaptureHoldRequest{Amount: models.MustParseMoney("80.01")})
+	assert.True(t, errors.Is(err, models.ErrInvalidAmount))
+
+	released := activeHold()
+	released.Status = models.HoldReleased
+	mockAccountStorage.EXPECT().GetHold(gomock.Any(), "hold_12345").Return(released, nil)
+	_, err = service.CaptureHold(context.Background(), "hold_12345", &models.CaptureHoldRequest{})
+	assert.True(t, errors.Is(err, models.ErrHoldNotActive))
+}
+
+func TestTransactionService_CaptureHold_LogFailureReopensHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+
+	var captureID string
+	mockAccountStorage.EXPECT().GetHold(gomock.Any(), "hold_12345").Return(activeHold(), nil)
+	mockAccountStorage.EXPECT().
+		CaptureHold(gomock.Any(), "hold_12345", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, holdID string, entry *models.JournalEntry) (*models.Hold, []models.BalanceChange, error) {
+			captureID = entry.Postings[0].TransactionID
+			return activeHold(), []models.BalanceChange{{AccountID: "acc_12345"}}, nil
+		})
+	mockTransactionStorage.EXPECT().CreateTransaction(gomock.Any(), gomock.Any()).Return(errors.New("mongo unavailable"))
+
+	// The withdrawal is reversed and the hold reserves the funds again
+	mockAccountStorage.EXPECT().
+		PostJournalEntry(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, entry *models.JournalEntry) ([]models.BalanceChange, error) {
+			assert.Contains(t, entry.Description, "rollback of")
+			return nil, nil
+		})
+	mockAccountStorage.EXPECT().
+		ReopenHold(gomock.Any(), "hold_12345", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, holdID, transactionID string) error {
+			assert.Equal(t, captureID, transactionID)
+			return nil
+		})
+
+	_, err := service.CaptureHold(context.Background(), "hold_12345", &models.CaptureHoldRequest{})
+	assert.Error(t, err)
+}
+
+func TestTransactionService_ReleaseHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+
+	released := activeHold()
+	released.Status = models.HoldReleased
+	mockAccountStorage.EXPECT().ReleaseHold(gomock.Any(), "hold_12345").Return(released, nil)
+
+	hold, err := service.ReleaseHold(context.Background(), "hold_12345")
+	require.NoError(t, err)
+	assert.Equal(t, models.HoldReleased, hold.Status)
+
+	mockAccountStorage.EXPECT().
+		ReleaseHold(gomock.Any(), "hold_67890").
+		Return(nil, models.Errorf(models.ErrHoldNotActive, "hold hold_67890 is captured"))
+
+	_, err = service.ReleaseHold(context.Background(), "hold_67890")
+	assert.True(t, errors.Is(err, models.ErrHoldNotActive))
+}
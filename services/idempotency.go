@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/utils"
+)
+
+// staleIdempotencyClaim is how long an unfinished claim blocks retries before
+// it is treated as abandoned (e.g. the API instance died mid-request)
+const staleIdempotencyClaim = 2 * time.Minute
+
+type IdempotencyService struct {
+	storage            IdempotencyStorage
+	accountStorage     AccountStorage
+	transactionStorage TransactionStorage
+}
+
+func NewIdempotencyService(storage IdempotencyStorage, accountStorage AccountStorage, transactionStorage TransactionStorage) *IdempotencyService {
+	return &IdempotencyService{
+		storage:            storage,
+		accountStorage:     accountStorage,
+		transactionStorage: transactionStorage,
+	}
+}
+
+// BeginRequest claims an idempotency key for a transaction request. It returns
+// nil when the caller owns the key and should process the request, or the
+// completed record whose response must be replayed. A successful claim
+// reserves the transaction ID the request must use (req.TransactionID).
+func (s *IdempotencyService) BeginRequest(ctx context.Context, accountID, key string, req *models.TransactionRequest) (*models.IdempotencyRecord, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "idempotency"),
+		slog.String("operation", "begin_request"),
+		slog.String("account_id", accountID),
+		slog.String("idempotency_key", key))
+
+	if key == "" {
+		logger.Error("Idempotency key is required")
+		return nil, models.Errorf(models.ErrInvalidRequest, "idempotency key is required")
+	}
+
+	fingerprint := transactionFingerprint(accountID, req)
+	now := time.Now()
+
+	record := &models.IdempotencyRecord{
+		Key:           key,
+		AccountID:     accountID,
+		Fingerprint:   fingerprint,
+		TransactionID: models.NewTransactionID(),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	existing, err := s.storage.ClaimIdempotencyKey(ctx, record)
+	if err != nil {
+		logger.Error("Failed to claim idempotency key", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if existing == nil {
+		logger.Info("Idempotency key claimed", slog.String("transaction_id", record.TransactionID))
+		req.TransactionID = record.TransactionID
+		return nil, nil
+	}
+
+	if existing.Fingerprint != fingerprint {
+		logger.Warn("Idempotency key reused with a different request")
+		return nil, models.ErrIdempotencyKeyReused
+	}
+
+	if !existing.IsCompleted() {
+		if time.Since(existing.CreatedAt) < staleIdempotencyClaim {
+			logger.Warn("Idempotency key is still being processed")
+			return nil, models.ErrIdempotencyInProgress
+		}
+
+		// The original request may have been applied before its response was
+		// stored; reclaiming the key then would apply it a second time
+		applied, err := s.claimApplied(ctx, existing)
+		if err != nil {
+			logger.Error("Failed to check stale idempotency key", slog.String("error", err.Error()))
+			return nil, err
+		}
+		if applied {
+			logger.Warn("Stale idempotency key already reached the ledger",
+				slog.String("transaction_id", existing.TransactionID))
+			return nil, models.Errorf(models.ErrIdempotencyIncomplete, "transaction %s was already applied", existing.TransactionID)
+		}
+
+		// The original request never finished; release the claim and try once more
+		logger.Warn("Reclaiming stale idempotency key", slog.Time("claimed_at", existing.CreatedAt))
+		released, err := s.storage.DeleteStaleIdempotencyRecord(ctx, accountID, key, existing.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if !released {
+			// Another retry reclaimed or the original completed the key first
+			logger.Warn("Stale idempotency key changed before it was released")
+			return nil, models.ErrIdempotencyInProgress
+		}
+		return s.BeginRequest(ctx, accountID, key, req)
+	}
+
+	logger.Info("Replaying stored response", slog.Int("status_code", existing.StatusCode))
+	return existing, nil
+}
+
+// claimApplied reports whether the transaction reserved by an unfinished claim
+// exists in the transaction log or has changed a balance
+func (s *IdempotencyService) claimApplied(ctx context.Context, record *models.IdempotencyRecord) (bool, error) {
+	_, applied, err := s.AppliedTransaction(ctx, record.TransactionID)
+	return applied, err
+}
+
+// AppliedTransaction reports whether a reserved transaction ID reached the
+// ledger, either in the transaction log or as a balance change. The record is
+// returned when it is in the log, and nil when only the balance change is.
+func (s *IdempotencyService) AppliedTransaction(ctx context.Context, transactionID string) (*models.Transaction, bool, error) {
+	if transactionID == "" {
+		return nil, false, nil
+	}
+
+	transaction, err := s.transactionStorage.GetTransactionByID(ctx, transactionID)
+	if err == nil {
+		return transaction, true, nil
+	}
+	if !errors.Is(err, models.ErrTransactionNotFound) {
+		return nil, false, err
+	}
+
+	applied, err := s.accountStorage.GetAppliedTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, false, err
+	}
+	return nil, applied != nil, nil
+}
+
+// CompleteRequest stores the final response for a claimed key
+func (s *IdempotencyService) CompleteRequest(ctx context.Context, accountID, key string, statusCode int, responseBody []byte, transactionID string) error {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "idempotency"),
+		slog.String("operation", "complete_request"),
+		slog.String("account_id", accountID),
+		slog.String("idempotency_key", key))
+
+	if err := s.storage.CompleteIdempotencyRecord(ctx, accountID, key, statusCode, responseBody, transactionID); err != nil {
+		logger.Error("Failed to store idempotent response", slog.String("error", err.Error()))
+		return err
+	}
+
+	logger.Info("Idempotent response stored", slog.Int("status_code", statusCode))
+	return nil
+}
+
+// ReleaseRequest forgets a claimed key so that a retry is processed afresh.
+// Used when the original request failed for reasons the client can retry.
+func (s *IdempotencyService) ReleaseRequest(ctx context.Context, accountID, key string) error {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "idempotency"),
+		slog.String("operation", "release_request"),
+		slog.String("account_id", accountID),
+		slog.String("idempotency_key", key))
+
+	if err := s.storage.DeleteIdempotencyRecord(ctx, accountID, key); err != nil {
+		logger.Error("Failed to release idempotency key", slog.String("error", err.Error()))
+		return err
+	}
+
+	logger.Info("Idempotency key released")
+	return nil
+}
+
+// transactionFingerprint hashes the normalized request so that a retry with the
+// same key can be told apart from a different request reusing it
+func transactionFingerprint(accountID string, req *models.TransactionRequest) string {
+	canonical := strings.Join([]string{
+		accountID,
+		strings.ToLower(strings.TrimSpace(req.Type)),
+		req.Amount.String(),
+		models.NormalizeCurrency(req.Currency),
+		strings.TrimSpace(req.Description),
+	}, "\x1f")
+
+	sum := sha256.Sum256([]byte(canonical))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestIdempotencyService_BeginRequest_ClaimsNewKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockIdempotencyStorage(ctrl)
+	service := NewIdempotencyService(mockStorage, nil, nil)
+
+	req := &models.TransactionRequest{Type: "deposit", Amount: models.MustParseMoney("25.00")}
+	ctx := context.Background()
+	var claimedID string
+
+	mockStorage.EXPECT().
+		ClaimIdempotencyKey(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, record *models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+			assert.Equal(t, "key-1", record.Key)
+			assert.Equal(t, "acc_12345", record.AccountID)
+			assert.Equal(t, transactionFingerprint("acc_12345", req), record.Fingerprint)
+			assert.False(t, record.IsCompleted())
+			assert.NotEmpty(t, record.TransactionID)
+			claimedID = record.TransactionID
+			return nil, nil
+		}).
+		Times(1)
+
+	record, err := service.BeginRequest(ctx, "acc_12345", "key-1", req)
+
+	assert.NoError(t, err)
+	assert.Nil(t, record)
+	assert.Equal(t, claimedID, req.TransactionID)
+}
+
+func TestIdempotencyService_BeginRequest_ReplaysCompletedRecord(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockIdempotencyStorage(ctrl)
+	service := NewIdempotencyService(mockStorage, nil, nil)
+
+	req := &models.TransactionRequest{Type: "deposit", Amount: models.MustParseMoney("25.00")}
+	stored := &models.IdempotencyRecord{
+		Key:          "key-1",
+		AccountID:    "acc_12345",
+		Fingerprint:  transactionFingerprint("acc_12345", req),
+		StatusCode:   202,
+		ResponseBody: []byte(`{"transaction_id":"txn_1"}`),
+		CreatedAt:    time.Now(),
+	}
+
+	mockStorage.EXPECT().ClaimIdempotencyKey(gomock.Any(), gomock.Any()).Return(stored, nil).Times(1)
+
+	record, err := service.BeginRequest(context.Background(), "acc_12345", "key-1", req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, stored, record)
+}
+
+func TestIdempotencyService_BeginRequest_DifferentBody(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockIdempotencyStorage(ctrl)
+	service := NewIdempotencyService(mockStorage, nil, nil)
+
+	original := &models.TransactionRequest{Type: "deposit", Amount: models.MustParseMoney("25.00")}
+	retry := &models.TransactionRequest{Type: "deposit", Amount: models.MustParseMoney("250.00")}
+
+	mockStorage.EXPECT().
+		ClaimIdempotencyKey(gomock.Any(), gomock.Any()).
+		Return(&models.IdempotencyRecord{Fingerprint: transactionFingerprint("acc_12345", original), StatusCode: 200}, nil).
+		Times(1)
+
+	record, err := service.BeginRequest(context.Background(), "acc_12345", "key-1", retry)
+
+	assert.Error(t, err)
+	assert.Nil(t, record)
+	assert.Contains(t, err.Error(), "different request body")
+}
+
+func TestIdempotencyService_BeginRequest_InProgressAndStale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockIdempotencyStorage(ctrl)
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewIdempotencyService(mockStorage, mockAccountStorage, mockTransactionStorage)
+
+	req := &models.TransactionRequest{Type: "withdraw", Amount: models.MustParseMoney("10.00")}
+	fingerprint := transactionFingerprint("acc_12345", req)
+
+	// A fresh claim that has not finished blocks the retry
+	mockStorage.EXPECT().
+		ClaimIdempotencyKey(gomock.Any(), gomock.Any()).
+		Return(&models.IdempotencyRecord{Fingerprint: fingerprint, TransactionID: "txn_1", CreatedAt: time.Now()}, nil).
+		Times(1)
+
+	_, err := service.BeginRequest(context.Background(), "acc_12345", "key-1", req)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "still in progress")
+
+	// An abandoned claim that never reached the ledger is released and claimed again
+	claimedAt := time.Now().Add(-time.Hour)
+	gomock.InOrder(
+		mockStorage.EXPECT().
+			ClaimIdempotencyKey(gomock.Any(), gomock.Any()).
+			Return(&models.IdempotencyRecord{Fingerprint: fingerprint, TransactionID: "txn_1", CreatedAt: claimedAt}, nil),
+		mockTransactionStorage.EXPECT().GetTransactionByID(gomock.Any(), "txn_1").Return(nil, models.ErrTransactionNotFound),
+		mockAccountStorage.EXPECT().GetAppliedTransaction(gomock.Any(), "txn_1").Return(nil, nil),
+		mockStorage.EXPECT().DeleteStaleIdempotencyRecord(gomock.Any(), "acc_12345", "key-1", claimedAt).Return(true, nil),
+		mockStorage.EXPECT().ClaimIdempotencyKey(gomock.Any(), gomock.Any()).Return(nil, nil),
+	)
+
+	record, err := service.BeginRequest(context.Background(), "acc_12345", "key-1", req)
+	assert.NoError(t, err)
+	assert.Nil(t, record)
+	assert.NotEmpty(t, req.TransactionID)
+}
+
+func TestIdempotencyService_BeginRequest_StaleClaimAlreadyApplied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockIdempotencyStorage(ctrl)
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewIdempotencyService(mockStorage, mockAccountStorage, mockTransactionStorage)
+
+	req := &models.TransactionRequest{Type: "withdraw", Amount: models.MustParseMoney("10.00")}
+	stale := &models.IdempotencyRecord{
+		Fingerprint:   transactionFingerprint("acc_12345", req),
+		TransactionID: "txn_1",
+		CreatedAt:     time.Now().Add(-time.Hour),
+	}
+
+	// The balance changed but the transaction log was never written
+	mockStorage.EXPECT().ClaimIdempotencyKey(gomock.Any(), gomock.Any()).Return(stale, nil).Times(2)
+	mockTransactionStorage.EXPECT().GetTransactionByID(gomock.Any(), "txn_1").Return(nil, models.ErrTransactionNotFound)
+	mockAccountStorage.EXPECT().GetAppliedTransaction(gomock.Any(), "txn_1").
+		Return(&models.AppliedTransaction{TransactionID: "txn_1", AccountID: "acc_12345"}, nil)
+
+	_, err := service.BeginRequest(context.Background(), "acc_12345", "key-1", req)
+	assert.ErrorIs(t, err, models.ErrIdempotencyIncomplete)
+
+	// The transaction log has it
+	mockTransactionStorage.EXPECT().GetTransactionByID(gomock.Any(), "txn_1").
+		Return(&models.Transaction{ID: "txn_1", Status: "completed"}, nil)
+
+	_, err = service.BeginRequest(context.Background(), "acc_12345", "key-1", req)
+	assert.ErrorIs(t, err, models.ErrIdempotencyIncomplete)
+}
+
+func TestIdempotencyService_AppliedTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockIdempotencyStorage(ctrl)
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewIdempotencyService(mockStorage, mockAccountStorage, mockTransactionStorage)
+	ctx := context.Background()
+
+	// No reserved ID, nothing to look up
+	transaction, applied, err := service.AppliedTransaction(ctx, "")
+	assert.NoError(t, err)
+	assert.False(t, applied)
+	assert.Nil(t, transaction)
+
+	// In the transaction log
+	mockTransactionStorage.EXPECT().GetTransactionByID(ctx, "txn_1").
+		Return(&models.Transaction{ID: "txn_1", TransactionID: "txn_1", Status: "completed"}, nil)
+	transaction, applied, err = service.AppliedTransaction(ctx, "txn_1")
+	assert.NoError(t, err)
+	assert.True(t, applied)
+	assert.Equal(t, "txn_1", transaction.TransactionID)
+
+	// Neither in the log nor applied to a balance
+	mockTransactionStorage.EXPECT().GetTransactionByID(ctx, "txn_2").Return(nil, models.ErrTransactionNotFound)
+	mockAccountStorage.EXPECT().GetAppliedTransaction(ctx, "txn_2").Return(nil, nil)
+	transaction, applied, err = service.AppliedTransaction(ctx, "txn_2")
+	assert.NoError(t, err)
+	assert.False(t, applied)
+	assert.Nil(t, transaction)
+}
+
+func TestIdempotencyService_BeginRequest_StaleClaimTakenByAnotherRetry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockIdempotencyStorage(ctrl)
+	service := NewIdempotencyService(mockStorage, nil, nil)
+
+	req := &models.TransactionRequest{Type: "withdraw", Amount: models.MustParseMoney("10.00")}
+	claimedAt := time.Now().Add(-time.Hour)
+
+	// Claims made before transaction IDs were reserved skip the ledger check
+	mockStorage.EXPECT().
+		ClaimIdempotencyKey(gomock.Any(), gomock.Any()).
+		Return(&models.IdempotencyRecord{Fingerprint: transactionFingerprint("acc_12345", req), CreatedAt: claimedAt}, nil)
+	mockStorage.EXPECT().DeleteStaleIdempotencyRecord(gomock.Any(), "acc_12345", "key-1", claimedAt).Return(false, nil)
+
+	_, err := service.BeginRequest(context.Background(), "acc_12345", "key-1", req)
+	assert.ErrorIs(t, err, models.ErrIdempotencyInProgress)
+}
+
+func TestTransactionFingerprint_NormalizesRequest(t *testing.T) {
+	a := &models.TransactionRequest{Type: "Deposit", Amount: models.MustParseMoney("10.5"), Currency: "usd", Description: " rent "}
+	b := &models.TransactionRequest{Type: "deposit", Amount: models.MustParseMoney("10.50"), Currency: "USD", Description: "rent"}
+
+	assert.Equal(t, transactionFingerprint("acc_1", a), transactionFingerprint("acc_1", b))
+	assert.NotEqual(t, transactionFingerprint("acc_1", a), transactionFingerprint("acc_2", a))
+}
@@ -2,7 +2,9 @@ package services
 
 import (
 	"context"
+	"time"
 
+	"github.com/appy29/banking-ledger-service/auth"
 	"github.com/appy29/banking-ledger-service/models"
 )
 
@@ -10,25 +12,155 @@ import (
 type AccountStorage interface {
 	CreateAccount(ctx context.Context, account *models.Account) error
 	GetAccountByID(ctx context.Context, accountID string) (*models.Account, error)
-	UpdateBalance(ctx context.Context, accountID string, newBalance float64) error
-	AtomicBalanceUpdate(ctx context.Context, accountID, transactionType string, amount float64) (previousBalance, newBalance float64, err error)
+	ListAccounts(ctx context.Context, afterID string, limit int) ([]models.Account, error)
+
+	// Account lifecycle: each state change is recorded with its reason
+	UpdateAccountStatus(ctx context.Context, accountID, status, reason string) (*models.AccountStatusChange, error)
+	GetAccountStatusHistory(ctx context.Context, accountID string) ([]models.AccountStatusChange, error)
+	SetOverdraftLimit(ctx context.Context, accountID string, limit models.Money) (*models.Account, error)
+
+	// Transaction limits: per-account overrides of the defaults, and the
+	// transactions rejected for exceeding them
+	SetLimitOverrides(ctx context.Context, accountID string, overrides *models.LimitOverrides) error
+	RecordLimitBreach(ctx context.Context, breach *models.LimitBreach) error
+	GetLimitBreaches(ctx context.Context, accountID string, limit int) ([]models.LimitBreach, error)
+
+	// Holds reserve funds without posting; a capture posts the withdrawal
+	CreateHold(ctx context.Context, hold *models.Hold) error
+	GetHold(ctx context.Context, holdID string) (*models.Hold, error)
+	GetAccountHolds(ctx context.Context, accountID string) ([]models.Hold, error)
+	CaptureHold(ctx context.Context, holdID string, entry *models.JournalEntry) (*models.Hold, []models.BalanceChange, error)
+	ReleaseHold(ctx context.Context, holdID string) (*models.Hold, error)
+	ReopenHold(ctx context.Context, holdID, transactionID string) error
+	ExpireHolds(ctx context.Context, now time.Time) (int64, error)
+
+	// Double-entry journal: balances only change by posting a balanced entry
+	PostJournalEntry(ctx context.Context, entry *models.JournalEntry) ([]models.BalanceChange, error)
+	GetLedgerBalance(ctx context.Context, accountID string) (*models.LedgerBalance, error)
+	GetSystemAccounts(ctx context.Context) ([]models.SystemAccount, error)
+
+	// Queued transactions record which balance changes were applied
+	ApplyTransaction(ctx context.Context, transactionID, accountID, transactionType string, amount models.Money, limits *models.TransactionLimits) (previousBalance, newBalance models.Money, err error)
+	ApplyTransfer(ctx context.Context, debitTransactionID, creditTransactionID, fromAccountID, toAccountID string, amount models.Money, limits *models.TransactionLimits) (fromPreviousBalance, fromNewBalance, toPreviousBalance, toNewBalance models.Money, err error)
+	GetAppliedTransaction(ctx context.Context, transactionID string) (*models.AppliedTransaction, error)
+	RevertAppliedTransactions(ctx context.Context, transactionIDs ...string) error
 }
 
 // TransactionStorage defines the interface for transaction storage operations
 type TransactionStorage interface {
 	CreateTransaction(ctx context.Context, transaction *models.Transaction) error
+	CreateTransactions(ctx context.Context, transactions []*models.Transaction) error
 	UpdateTransaction(ctx context.Context, transaction *models.Transaction) error
 	GetTransactionsByAccountID(ctx context.Context, accountID string, page, limit int) ([]models.Transaction, int64, error)
+	GetTransactionsPage(ctx context.Context, accountID string, filter *models.TransactionFilter, after *models.TransactionCursor, limit int) ([]models.Transaction, error)
 	GetTransactionByID(ctx context.Context, transactionID string) (*models.Transaction, error)
+	GetTransactionsByTransferID(ctx context.Context, transferID string) ([]models.Transaction, error)
 	UpdateTransactionStatus(ctx context.Context, transactionID, status string) error
 	UpdateTransactionStatusWithError(ctx context.Context, transactionID, status, errorMessage string) error
+
+	// Point-in-time balances and statements, over settled records only
+	GetLatestTransactionBefore(ctx context.Context, accountID string, t time.Time) (*models.Transaction, error)
+	GetEarliestTransactionFrom(ctx context.Context, accountID string, t time.Time) (*models.Transaction, error)
+	GetTransactionsInRange(ctx context.Context, accountID string, from, to time.Time) ([]models.Transaction, error)
+
+	// Reconciliation replays an account's whole log
+	GetAccountTransactions(ctx context.Context, accountID string) ([]models.Transaction, error)
+
+	// Tamper evidence: each account's sealed records in hash chain order
+	GetSealedTransactions(ctx context.Context, accountID string) ([]models.Transaction, error)
+
+	// Reversal operations
+	RecordRefund(ctx context.Context, transactionID, reversalID string, amount models.Money, refundedAt time.Time) (bool, error)
+	RestoreRefund(ctx context.Context, transactionID, reversalID string, amount models.Money) error
+
+	// Outbox operations
+	ClaimOutboxEntry(ctx context.Context, now time.Time, lease time.Duration) (*models.Transaction, error)
+	MarkOutboxDispatched(ctx context.Context, transactionID string, dispatchedAt time.Time) error
+	RescheduleOutboxEntry(ctx context.Context, transactionID string, nextAttemptAt time.Time, lastError string) error
+	RequeueOutboxEntry(ctx context.Context, transactionID string, entry *models.OutboxEntry, reason string) error
+
+	// Stuck-pending reaper operations
+	GetStalePendingTransactions(ctx context.Context, olderThan time.Time, limit int) ([]models.Transaction, error)
+	CountStalePendingTransactions(ctx context.Context, olderThan time.Time) (int64, error)
+}
+
+// IdempotencyStorage defines the interface for idempotency key storage operations
+type IdempotencyStorage interface {
+	ClaimIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord) (*models.IdempotencyRecord, error)
+	CompleteIdempotencyRecord(ctx context.Context, accountID, key string, statusCode int, responseBody []byte, transactionID string) error
+	DeleteIdempotencyRecord(ctx context.Context, accountID, key string) error
+	DeleteStaleIdempotencyRecord(ctx context.Context, accountID, key string, claimedAt time.Time) (bool, error)
+}
+
+// DeadLetterStorage defines the interface for dead letter storage operations
+type DeadLetterStorage interface {
+	SaveDeadLetter(ctx context.Context, deadLetter *models.DeadLetter) error
+	GetDeadLetters(ctx context.Context, status string, page, limit int) ([]models.DeadLetter, int64, error)
+	GetDeadLetterByID(ctx context.Context, id string) (*models.DeadLetter, error)
+	TransitionDeadLetter(ctx context.Context, id, fromStatus, toStatus string, resolvedAt *time.Time) (bool, error)
+}
+
+// ScheduleStorage defines the interface for scheduled transaction storage operations
+type ScheduleStorage interface {
+	CreateSchedule(ctx context.Context, schedule *models.Schedule) error
+	GetSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error)
+	GetAccountSchedules(ctx context.Context, accountID string) ([]models.Schedule, error)
+	ChangeSchedule(ctx context.Context, scheduleID string, change func(*models.Schedule) error) (*models.Schedule, error)
+	ClaimDueSchedules(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.Schedule, error)
+	AdvanceSchedule(ctx context.Context, scheduleID string, from time.Time, step *models.ScheduleStep, transactionID string) (bool, error)
+}
+
+// AuditStorage defines the interface for the append-only audit trail
+type AuditStorage interface {
+	InsertAuditRecord(ctx context.Context, record *models.AuditRecord) error
+	GetAuditRecordsPage(ctx context.Context, filter *models.AuditFilter, after *models.AuditCursor, limit int) ([]models.AuditRecord, error)
+}
+
+// WebhookStorage defines the interface for webhook subscription and delivery storage operations
+type WebhookStorage interface {
+	CreateWebhookSubscription(ctx context.Context, subscription *models.WebhookSubscription) error
+	GetWebhookSubscription(ctx context.Context, subscriptionID string) (*models.WebhookSubscription, error)
+	GetWebhookSubscriptions(ctx context.Context, accountID string) ([]models.WebhookSubscription, error)
+	GetSubscribedWebhooks(ctx context.Context, accountID, eventType string) ([]models.WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, subscriptionID string) error
+
+	// Deliveries are claimed by the dispatcher and every attempt is logged
+	CreateWebhookDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error
+	GetWebhookDelivery(ctx context.Context, deliveryID string) (*models.WebhookDelivery, error)
+	GetWebhookDeliveries(ctx context.Context, subscriptionID, status string, page, limit int) ([]models.WebhookDelivery, int64, error)
+	ClaimDueWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error)
+	RecordWebhookAttempt(ctx context.Context, delivery *models.WebhookDelivery, attempt *models.WebhookAttempt) error
+}
+
+// EventNotifier tells webhook subscribers about ledger events. A notification
+// that cannot be queued is logged; it never fails the operation that raised it.
+type EventNotifier interface {
+	AccountCreated(ctx context.Context, account *models.Account)
+	TransactionCompleted(ctx context.Context, transaction *models.Transaction)
+	TransactionFailed(ctx context.Context, transaction *models.Transaction)
+}
+
+// TransactionProcessor applies a logged pending transaction the way a worker
+// applies a queued one
+type TransactionProcessor interface {
+	ProcessTransactionAsync(ctx context.Context, transactionID string, req *models.TransactionRequest) (*models.Transaction, error)
+	FailUnprocessedTransaction(ctx context.Context, transactionID, errorMessage string) error
+}
+
+// MessagePublisher publishes a raw message body to the transaction queue
+type MessagePublisher interface {
+	RepublishMessage(ctx context.Context, messageID string, body []byte) error
 }
 
 // AccountServiceInterface defines the contract for account operations
 type AccountServiceInterface interface {
 	CreateAccount(ctx context.Context, req *models.CreateAccountRequest) (*models.Account, error)
 	GetAccountByID(ctx context.Context, accountID string) (*models.Account, error)
-	GetAccountBalance(ctx context.Context, accountID string) (float64, error)
+	GetAccountBalance(ctx context.Context, accountID string) (models.Money, error)
+	GetLedgerBalance(ctx context.Context, accountID string) (*models.LedgerBalance, error)
+	ChangeAccountStatus(ctx context.Context, accountID, status string, req *models.AccountStatusRequest) (*models.AccountStatusChange, error)
+	GetAccountStatusHistory(ctx context.Context, accountID string) ([]models.AccountStatusChange, error)
+	SetOverdraftLimit(ctx context.Context, accountID string, req *models.OverdraftLimitRequest) (*models.Account, error)
 }
 
 // TransactionServiceInterface defines the contract for transaction operations
@@ -36,15 +168,97 @@ type TransactionServiceInterface interface {
 	// Synchronous operations
 	ProcessTransaction(ctx context.Context, accountID string, req *models.TransactionRequest) (*models.Transaction, error)
 	GetTransactionHistory(ctx context.Context, accountID string, page, limit int) ([]models.Transaction, int64, error)
+	ListTransactions(ctx context.Context, accountID string, filter *models.TransactionFilter, cursor string, limit int) (*models.TransactionPage, error)
 	GetTransactionByID(ctx context.Context, transactionID string) (*models.Transaction, error)
+	ReverseTransaction(ctx context.Context, transactionID string, req *models.ReverseTransactionRequest) (*models.Transaction, error)
+	GetBalanceAsOf(ctx context.Context, accountID string, asOf time.Time) (*models.HistoricalBalance, error)
+	GetStatement(ctx context.Context, accountID string, from, to time.Time) (*models.Statement, error)
+	VerifyTransactionChain(ctx context.Context, accountID string) (*models.ChainVerification, error)
+	ProcessTransfer(ctx context.Context, req *models.TransferRequest) (*models.Transfer, error)
+	GetTransfer(ctx context.Context, transferID string) (*models.Transfer, error)
+	PlaceHold(ctx context.Context, accountID string, req *models.CreateHoldRequest) (*models.Hold, error)
+	GetHold(ctx context.Context, holdID string) (*models.Hold, error)
+	GetAccountHolds(ctx context.Context, accountID string) ([]models.Hold, error)
+	CaptureHold(ctx context.Context, holdID string, req *models.CaptureHoldRequest) (*models.HoldCapture, error)
+	ReleaseHold(ctx context.Context, holdID string) (*models.Hold, error)
+	GetAccountLimits(ctx context.Context, accountID string) (*models.AccountLimits, error)
+	SetAccountLimits(ctx context.Context, accountID string, overrides *models.LimitOverrides) (*models.AccountLimits, error)
+	GetLimitBreaches(ctx context.Context, accountID string) ([]models.LimitBreach, error)
 
 	// Asynchronous operations
 	CreatePendingTransaction(ctx context.Context, transaction *models.Transaction) error
 	ProcessTransactionAsync(ctx context.Context, transactionID string, req *models.TransactionRequest) (*models.Transaction, error)
+	CreatePendingTransfer(ctx context.Context, transfer *models.Transfer) error
+	ProcessTransferAsync(ctx context.Context, transferID string, req *models.TransferRequest) (*models.Transfer, error)
 	UpdateTransactionStatus(ctx context.Context, transactionID, status string) error
 	UpdateTransactionStatusWithError(ctx context.Context, transactionID, status, errorMessage string) error
 
 	// Utility methods
 	GetAccountByID(ctx context.Context, accountID string) (*models.Account, error)
-	CreateInitialTransaction(ctx context.Context, accountID string, initialBalance float64) error
+}
+
+// IdempotencyServiceInterface defines the contract for Idempotency-Key handling
+type IdempotencyServiceInterface interface {
+	BeginRequest(ctx context.Context, accountID, key string, req *models.TransactionRequest) (*models.IdempotencyRecord, error)
+	CompleteRequest(ctx context.Context, accountID, key string, statusCode int, responseBody []byte, transactionID string) error
+	ReleaseRequest(ctx context.Context, accountID, key string) error
+	AppliedTransaction(ctx context.Context, transactionID string) (*models.Transaction, bool, error)
+}
+
+// ReaperServiceInterface defines the contract for the stuck-pending reaper
+type ReaperServiceInterface interface {
+	Stats(ctx context.Context) (*models.ReaperStats, error)
+}
+
+// ReconciliationServiceInterface defines the contract for ledger reconciliation
+type ReconciliationServiceInterface interface {
+	Reconcile(ctx context.Context, opts models.ReconcileOptions) (*models.ReconciliationReport, error)
+	LastReport(ctx context.Context) (*models.ReconciliationReport, error)
+}
+
+// DeadLetterServiceInterface defines the contract for dead letter inspection and replay
+type DeadLetterServiceInterface interface {
+	ListDeadLetters(ctx context.Context, status string, page, limit int) ([]models.DeadLetter, int64, error)
+	GetDeadLetter(ctx context.Context, id string) (*models.DeadLetter, error)
+	ReplayDeadLetter(ctx context.Context, id string) (*models.DeadLetter, error)
+	DiscardDeadLetter(ctx context.Context, id string) (*models.DeadLetter, error)
+}
+
+// ScheduleServiceInterface defines the contract for scheduled transactions
+type ScheduleServiceInterface interface {
+	CreateSchedule(ctx context.Context, accountID string, req *models.CreateScheduleRequest) (*models.Schedule, error)
+	GetSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error)
+	GetAccountSchedules(ctx context.Context, accountID string) ([]models.Schedule, error)
+	UpdateSchedule(ctx context.Context, scheduleID string, req *models.UpdateScheduleRequest) (*models.Schedule, error)
+	CancelSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error)
+	PauseSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error)
+	ResumeSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error)
+	SkipSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error)
+}
+
+// WebhookServiceInterface defines the contract for webhook subscriptions and their deliveries
+type WebhookServiceInterface interface {
+	CreateWebhook(ctx context.Context, accountID string, req *models.CreateWebhookRequest) (*models.WebhookSubscription, error)
+	GetWebhook(ctx context.Context, webhookID string) (*models.WebhookSubscription, error)
+	GetWebhooks(ctx context.Context, accountID string) ([]models.WebhookSubscription, error)
+	DeleteWebhook(ctx context.Context, webhookID string) error
+	ListDeliveries(ctx context.Context, webhookID, status string, page, limit int) ([]models.WebhookDelivery, int64, error)
+	GetDelivery(ctx context.Context, webhookID, deliveryID string) (*models.WebhookDelivery, error)
+	Redeliver(ctx context.Context, webhookID, deliveryID string) (*models.WebhookDelivery, error)
+}
+
+// AccessServiceInterface defines the contract for per-account authorization
+type AccessServiceInterface interface {
+	CheckAccount(ctx context.Context, accountID string, access auth.Access) error
+	CheckTransaction(ctx context.Context, transactionID string, access auth.Access) error
+	CheckTransfer(ctx context.Context, transferID string, access auth.Access) error
+	CheckHold(ctx context.Context, holdID string, access auth.Access) error
+	CheckSchedule(ctx context.Context, scheduleID string, access auth.Access) error
+	CheckWebhook(ctx context.Context, webhookID string, access auth.Access) error
+}
+
+// AuditServiceInterface defines the contract for recording and querying the audit trail
+type AuditServiceInterface interface {
+	Record(ctx context.Context, record *models.AuditRecord) error
+	Query(ctx context.Context, filter *models.AuditFilter, cursor string, limit int) (*models.AuditPage, error)
 }
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/utils"
+)
+
+// balanceChange picks one account's change out of a posted entry's changes
+func balanceChange(changes []models.BalanceChange, accountID string) models.BalanceChange {
+	for _, change := range changes {
+		if change.AccountID == accountID {
+			return change
+		}
+	}
+	return models.BalanceChange{AccountID: accountID}
+}
+
+// reverseEntry posts the reversal of an entry whose transaction log record
+// could not be written, so the balance change does not outlive it
+func (s *TransactionService) reverseEntry(ctx context.Context, entry *models.JournalEntry) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "transaction"),
+		slog.String("operation", "reverse_entry"),
+		slog.String("journal_entry_id", entry.ID))
+
+	reversal := entry.Reversal("rollback of " + entry.ID)
+	if _, err := s.accountStorage.PostJournalEntry(ctx, reversal); err != nil {
+		logger.Error("Failed to reverse journal entry", slog.String("error", err.Error()))
+		return
+	}
+
+	logger.Info("Journal entry reversed", slog.String("reversal_id", reversal.ID))
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/utils"
+)
+
+// maxLimitBreaches caps how many breaches are returned for an account
+const maxLimitBreaches = 100
+
+// SetDefaultLimits sets the limits for accounts without overrides
+func (s *TransactionService) SetDefaultLimits(limits models.TransactionLimits) {
+	s.limits = limits
+}
+
+// checkLimits rejects a deposit, withdrawal or outgoing transfer larger than
+// the account's per-transaction limits, and records the breach. For a
+// withdrawal it returns the velocity limits, if any, that the journal enforces
+// when it posts, under the account's row lock. transactionID names the pending
+// record in async mode and is empty for synchronous requests.
+func (s *TransactionService) checkLimits(ctx context.Context, account *models.Account, transactionType, transactionID string, amount models.Money) (*models.TransactionLimits, error) {
+	limits := account.LimitOverrides.Apply(s.limits)
+	withdrawal := transactionType != "deposit"
+
+	if err := limits.CheckAmount(account.ID, withdrawal, amount); err != nil {
+		return nil, s.limitBreached(ctx, account.ID, transactionType, transactionID, amount, err)
+	}
+
+	if !withdrawal || !limits.HasVelocityLimits() {
+		return nil, nil
+	}
+	return &limits, nil
+}
+
+// limitBreached records a transaction rejected for breaking a limit, whether
+// by checkLimits or by the journal. err is returned as it is, and errors that
+// are not limit breaches are not recorded.
+func (s *TransactionService) limitBreached(ctx context.Context, accountID, transactionType, transactionID string, amount models.Money, err error) error {
+	var exceeded *models.LimitExceededError
+	if !errors.As(err, &exceeded) {
+		return err
+	}
+
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "transaction"),
+		slog.String("operation", "check_limits"),
+		slog.String("account_id", accountID))
+	logger.Warn("Transaction limit exceeded",
+		slog.String("type", transactionType),
+		slog.String("limit", exceeded.Limit),
+		slog.String("max", exceeded.Max),
+		slog.String("attempted", exceeded.Attempted))
+
+	breach := &models.LimitBreach{
+		AccountID:     accountID,
+		TransactionID: transactionID,
+		Type:          transactionType,
+		Amount:        amount,
+		Limit:         exceeded.Limit,
+		Max:           exceeded.Max,
+		Attempted:     exceeded.Attempted,
+		RecordedAt:    time.Now(),
+	}
+	// The transaction is rejected whether or not the breach is recorded
+	if recordErr := s.accountStorage.RecordLimitBreach(ctx, breach); recordErr != nil {
+		logger.Error("Failed to record limit breach", slog.String("error", recordErr.Error()))
+	}
+
+	return err
+}
+
+// GetAccountLimits returns the limits in force for an account, with its
+// overrides and the defaults they replace
+func (s *TransactionService) GetAccountLimits(ctx context.Context, accountID string) (*models.AccountLimits, error) {
+	account, err := s.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account limits: %w", err)
+	}
+
+	limits := &models.AccountLimits{
+		AccountID: accountID,
+		Defaults:  s.limits,
+		Effective: account.LimitOverrides.Apply(s.limits),
+	}
+	if account.LimitOverrides != nil {
+		limits.Overrides = *account.LimitOverrides
+	}
+	return limits, nil
+}
+
+// SetAccountLimits replaces an account's limit overrides. Limits left out keep
+// their defaults.
+func (s *TransactionService) SetAccountLimits(ctx context.Context, accountID string, overrides *models.LimitOverrides) (*models.AccountLimits, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "transaction"),
+		slog.String("operation", "set_account_limits"),
+		slog.String("account_id", accountID))
+
+	if err := overrides.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.accountStorage.SetLimitOverrides(ctx, accountID, overrides); err != nil {
+		logger.Error("Failed to set limit overrides", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to set account limits: %w", err)
+	}
+
+	logger.Info("Account limits updated")
+	return s.GetAccountLimits(ctx, accountID)
+}
+
+// GetLimitBreaches returns the account's transactions rejected for exceeding
+// a limit, newest first
+func (s *TransactionService) GetLimitBreaches(ctx context.Context, accountID string) ([]models.LimitBreach, error) {
+	if _, err := s.GetAccountByID(ctx, accountID); err != nil {
+		return nil, fmt.Errorf("failed to get limit breaches: %w", err)
+	}
+
+	breaches, err := s.accountStorage.GetLimitBreaches(ctx, accountID, maxLimitBreaches)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get limit breaches: %w", err)
+	}
+	return breaches, nil
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTransactionService_ProcessTransaction_OverSingleLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	service.SetDefaultLimits(models.TransactionLimits{MaxDeposit: models.MustParseMoney("1000.00")})
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
+
+	// The breach is recorded and nothing is posted
+	mockAccountStorage.EXPECT().
+		RecordLimitBreach(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, breach *models.LimitBreach) error {
+			assert.Equal(t, "acc_12345", breach.AccountID)
+			assert.Equal(t, "deposit", breach.Type)
+			assert.Equal(t, models.LimitMaxDeposit, breach.Limit)
+			assert.Equal(t, "1000.00", breach.Max)
+			assert.Equal(t, "1500.00", breach.Attempted)
+			assert.Empty(t, breach.TransactionID)
+			return nil
+		})
+
+	_, err := service.ProcessTransaction(context.Background(), "acc_12345", &models.TransactionRequest{
+		Type:   "deposit",
+		Amount: models.MustParseMoney("1500.00"),
+	})
+
+	var exceeded *models.LimitExceededError
+	require.True(t, errors.As(err, &exceeded))
+	assert.Equal(t, models.LimitMaxDeposit, exceeded.Limit)
+	assert.True(t, errors.Is(err, models.ErrLimitExceeded))
+}
+
+func TestTransactionService_ProcessTransaction_AccountOverrideLiftsDefault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	service.SetDefaultLimits(models.TransactionLimits{MaxWithdrawal: models.MustParseMoney("500.00")})
+
+	noLimit := models.Money(0)
+	mockAccountStorage.EXPECT().
+		GetAccountByID(gomock.Any(), "acc_12345").
+		Return(&models.Account{ID: "acc_12345", Currency: "USD", LimitOverrides: &models.LimitOverrides{MaxWithdrawal: &noLimit}}, nil)
+	mockAccountStorage.EXPECT().
+		PostJournalEntry(gomock.Any(), gomock.Any()).
+		Return([]models.BalanceChange{{AccountID: "acc_12345", PreviousBalance: models.MustParseMoney("2000.00"), NewBalance: models.MustParseMoney("1200.00")}}, nil)
+	mockTransactionStorage.EXPECT().CreateTransaction(gomock.Any(), gomock.Any()).Return(nil)
+
+	transaction, err := service.ProcessTransaction(context.Background(), "acc_12345", &models.TransactionRequest{
+		Type:   "withdraw",
+		Amount: models.MustParseMoney("800.00"),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.MustParseMoney("1200.00"), transaction.NewBalance)
+}
+
+func TestTransactionService_ProcessTransactionAsync_DailyLimitFailsRecord(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	service.SetDefaultLimits(models.TransactionLimits{MaxDailyWithdrawn: models.MustParseMoney("1000.00")})
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
+
+	mockTransactionStorage.EXPECT().
+		GetTransactionByID(gomock.Any(), "txn_12345").
+		Return(&models.Transaction{
+			ID:            "txn_12345",
+			TransactionID: "txn_12345",
+			AccountID:     "acc_12345",
+			Type:          "withdraw",
+			Amount:        models.MustParseMoney("300.00"),
+			Status:        "pending",
+			Timestamp:     time.Now(),
+		}, nil)
+	// The journal checks the limits against the usage it reads under the row lock
+	usage := &models.LimitUsage{DailyWithdrawals: 2, DailyWithdrawn: models.MustParseMoney("800.00"), MonthlyWithdrawn: models.MustParseMoney("800.00")}
+	mockAccountStorage.EXPECT().
+		ApplyTransaction(gomock.Any(), "txn_12345", "acc_12345", "withdraw", models.MustParseMoney("300.00"), gomock.Not(gomock.Nil())).
+		DoAndReturn(func(ctx context.Context, transactionID, accountID, transactionType string, amount models.Money, limits *models.TransactionLimits) (models.Money, models.Money, error) {
+			return 0, 0, limits.CheckUsage(accountID, amount, usage)
+		})
+	mockAccountStorage.EXPECT().
+		RecordLimitBreach(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, breach *models.LimitBreach) error {
+			assert.Equal(t, "txn_12345", breach.TransactionID)
+			assert.Equal(t, models.LimitMaxDailyWithdrawn, breach.Limit)
+			assert.Equal(t, "1100.00", breach.Attempted)
+			return nil
+		})
+
+	// The worker fails the pending record; the balance change is rolled back
+	mockTransactionStorage.EXPECT().
+		UpdateTransactionStatusWithError(gomock.Any(), "txn_12345", "failed", gomock.Any()).
+		Return(nil)
+
+	_, err := service.ProcessTransactionAsync(context.Background(), "txn_12345", &models.TransactionRequest{
+		Type:   "withdraw",
+		Amount: models.MustParseMoney("300.00"),
+	})
+
+	assert.True(t, errors.Is(err, models.ErrLimitExceeded))
+	assert.True(t, IsBusinessError(err))
+}
+
+func TestTransactionService_ProcessTransfer_CountsAsWithdrawal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	service.SetDefaultLimits(models.TransactionLimits{MaxDailyWithdrawals: 3})
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
+	expectAccountCurrency(mockAccountStorage, "acc_67890", "USD")
+
+	mockAccountStorage.EXPECT().
+		PostJournalEntry(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, entry *models.JournalEntry) ([]models.BalanceChange, error) {
+			require.NotNil(t, entry.Limits)
+			return nil, entry.Limits.CheckUsage("acc_12345", entry.Postings[0].Amount, &models.LimitUsage{DailyWithdrawals: 3})
+		})
+	mockAccountStorage.EXPECT().RecordLimitBreach(gomock.Any(), gomock.Any()).Return(errors.New("postgres unavailable"))
+
+	// Failing to record the breach does not let the transfer through
+	_, err := service.ProcessTransfer(context.Background(), &models.TransferRequest{
+		FromAccountID: "acc_12345",
+		ToAccountID:   "acc_67890",
+		Amount:        models.MustParseMoney("10.00"),
+	})
+
+	var exceeded *models.LimitExceededError
+	require.True(t, errors.As(err, &exceeded))
+	assert.Equal(t, models.LimitMaxDailyWithdrawals, exceeded.Limit)
+	assert.Equal(t, "4", exceeded.Attempted)
+}
+
+func TestTransactionService_SetAccountLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	service.SetDefaultLimits(models.TransactionLimits{MaxWithdrawal: models.MustParseMoney("500.00"), MaxDailyWithdrawals: 10})
+
+	negative := models.MustParseMoney("-1.00")
+	_, err := service.SetAccountLimits(context.Background(), "acc_12345", &models.LimitOverrides{MaxDeposit: &negative})
+	assert.True(t, errors.Is(err, models.ErrInvalidAmount))
+
+	raised := models.MustParseMoney("5000.00")
+	overrides := &models.LimitOverrides{MaxWithdrawal: &raised}
+	mockAccountStorage.EXPECT().SetLimitOverrides(gomock.Any(), "acc_12345", overrides).Return(nil)
+	mockAccountStorage.EXPECT().
+		GetAccountByID(gomock.Any(), "acc_12345").
+		Return(&models.Account{ID: "acc_12345", Currency: "USD", LimitOverrides: overrides}, nil)
+
+	limits, err := service.SetAccountLimits(context.Background(), "acc_12345", overrides)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.MustParseMoney("500.00"), limits.Defaults.MaxWithdrawal)
+	assert.Equal(t, raised, limits.Effective.MaxWithdrawal)
+	assert.Equal(t, int64(10), limits.Effective.MaxDailyWithdrawals)
+}
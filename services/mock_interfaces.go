@@ -12,7 +12,9 @@ package services
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
+	auth "github.com/appy29/banking-ledger-service/auth"
 	models "github.com/appy29/banking-ledger-service/models"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -41,20 +43,54 @@ func (m *MockAccountStorage) EXPECT() *MockAccountStorageMockRecorder {
 	return m.recorder
 }
 
-// AtomicBalanceUpdate mocks base method.
-func (m *MockAccountStorage) AtomicBalanceUpdate(ctx context.Context, accountID, transactionType string, amount float64) (float64, float64, error) {
+// ApplyTransaction mocks base method.
+func (m *MockAccountStorage) ApplyTransaction(ctx context.Context, transactionID, accountID, transactionType string, amount models.Money, limits *models.TransactionLimits) (models.Money, models.Money, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AtomicBalanceUpdate", ctx, accountID, transactionType, amount)
-	ret0, _ := ret[0].(float64)
-	ret1, _ := ret[1].(float64)
+	ret := m.ctrl.Call(m, "ApplyTransaction", ctx, transactionID, accountID, transactionType, amount, limits)
+	ret0, _ := ret[0].(models.Money)
+	ret1, _ := ret[1].(models.Money)
 	ret2, _ := ret[2].(error)
 	return ret0, ret1, ret2
 }
 
-// AtomicBalanceUpdate indicates an expected call of AtomicBalanceUpdate.
-func (mr *MockAccountStorageMockRecorder) AtomicBalanceUpdate(ctx, accountID, transactionType, amount any) *gomock.Call {
+// ApplyTransaction indicates an expected call of ApplyTransaction.
+func (mr *MockAccountStorageMockRecorder) ApplyTransaction(ctx, transactionID, accountID, transactionType, amount, limits any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AtomicBalanceUpdate", reflect.TypeOf((*MockAccountStorage)(nil).AtomicBalanceUpdate), ctx, accountID, transactionType, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyTransaction", reflect.TypeOf((*MockAccountStorage)(nil).ApplyTransaction), ctx, transactionID, accountID, transactionType, amount, limits)
+}
+
+// ApplyTransfer mocks base method.
+func (m *MockAccountStorage) ApplyTransfer(ctx context.Context, debitTransactionID, creditTransactionID, fromAccountID, toAccountID string, amount models.Money, limits *models.TransactionLimits) (models.Money, models.Money, models.Money, models.Money, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyTransfer", ctx, debitTransactionID, creditTransactionID, fromAccountID, toAccountID, amount, limits)
+	ret0, _ := ret[0].(models.Money)
+	ret1, _ := ret[1].(models.Money)
+	ret2, _ := ret[2].(models.Money)
+	ret3, _ := ret[3].(models.Money)
+	ret4, _ := ret[4].(error)
+	return ret0, ret1, ret2, ret3, ret4
+}
+
+// ApplyTransfer indicates an expected call of ApplyTransfer.
+func (mr *MockAccountStorageMockRecorder) ApplyTransfer(ctx, debitTransactionID, creditTransactionID, fromAccountID, toAccountID, amount, limits any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyTransfer", reflect.TypeOf((*MockAccountStorage)(nil).ApplyTransfer), ctx, debitTransactionID, creditTransactionID, fromAccountID, toAccountID, amount, limits)
+}
+
+// CaptureHold mocks base method.
+func (m *MockAccountStorage) CaptureHold(ctx context.Context, holdID string, entry *models.JournalEntry) (*models.Hold, []models.BalanceChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureHold", ctx, holdID, entry)
+	ret0, _ := ret[0].(*models.Hold)
+	ret1, _ := ret[1].([]models.BalanceChange)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CaptureHold indicates an expected call of CaptureHold.
+func (mr *MockAccountStorageMockRecorder) CaptureHold(ctx, holdID, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHold", reflect.TypeOf((*MockAccountStorage)(nil).CaptureHold), ctx, holdID, entry)
 }
 
 // CreateAccount mocks base method.
@@ -71,6 +107,35 @@ func (mr *MockAccountStorageMockRecorder) CreateAccount(ctx, account any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockAccountStorage)(nil).CreateAccount), ctx, account)
 }
 
+// CreateHold mocks base method.
+func (m *MockAccountStorage) CreateHold(ctx context.Context, hold *models.Hold) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHold", ctx, hold)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateHold indicates an expected call of CreateHold.
+func (mr *MockAccountStorageMockRecorder) CreateHold(ctx, hold any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHold", reflect.TypeOf((*MockAccountStorage)(nil).CreateHold), ctx, hold)
+}
+
+// ExpireHolds mocks base method.
+func (m *MockAccountStorage) ExpireHolds(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireHolds", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireHolds indicates an expected call of ExpireHolds.
+func (mr *MockAccountStorageMockRecorder) ExpireHolds(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireHolds", reflect.TypeOf((*MockAccountStorage)(nil).ExpireHolds), ctx, now)
+}
+
 // GetAccountByID mocks base method.
 func (m *MockAccountStorage) GetAccountByID(ctx context.Context, accountID string) (*models.Account, error) {
 	m.ctrl.T.Helper()
@@ -80,358 +145,2469 @@ func (m *MockAccountStorage) GetAccountByID(ctx context.Context, accountID strin
 	return ret0, ret1
 }
 
-// GetAccountByID indicates an expected call of GetAccountByID.
-func (mr *MockAccountStorageMockRecorder) GetAccountByID(ctx, accountID any) *gomock.Call {
+// GetAccountByID indicates an expected call of GetAccountByID.
+func (mr *MockAccountStorageMockRecorder) GetAccountByID(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByID", reflect.TypeOf((*MockAccountStorage)(nil).GetAccountByID), ctx, accountID)
+}
+
+// GetAccountHolds mocks base method.
+func (m *MockAccountStorage) GetAccountHolds(ctx context.Context, accountID string) ([]models.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountHolds", ctx, accountID)
+	ret0, _ := ret[0].([]models.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountHolds indicates an expected call of GetAccountHolds.
+func (mr *MockAccountStorageMockRecorder) GetAccountHolds(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountHolds", reflect.TypeOf((*MockAccountStorage)(nil).GetAccountHolds), ctx, accountID)
+}
+
+// GetAccountStatusHistory mocks base method.
+func (m *MockAccountStorage) GetAccountStatusHistory(ctx context.Context, accountID string) ([]models.AccountStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountStatusHistory", ctx, accountID)
+	ret0, _ := ret[0].([]models.AccountStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountStatusHistory indicates an expected call of GetAccountStatusHistory.
+func (mr *MockAccountStorageMockRecorder) GetAccountStatusHistory(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountStatusHistory", reflect.TypeOf((*MockAccountStorage)(nil).GetAccountStatusHistory), ctx, accountID)
+}
+
+// GetAppliedTransaction mocks base method.
+func (m *MockAccountStorage) GetAppliedTransaction(ctx context.Context, transactionID string) (*models.AppliedTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppliedTransaction", ctx, transactionID)
+	ret0, _ := ret[0].(*models.AppliedTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppliedTransaction indicates an expected call of GetAppliedTransaction.
+func (mr *MockAccountStorageMockRecorder) GetAppliedTransaction(ctx, transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppliedTransaction", reflect.TypeOf((*MockAccountStorage)(nil).GetAppliedTransaction), ctx, transactionID)
+}
+
+// GetHold mocks base method.
+func (m *MockAccountStorage) GetHold(ctx context.Context, holdID string) (*models.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHold", ctx, holdID)
+	ret0, _ := ret[0].(*models.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHold indicates an expected call of GetHold.
+func (mr *MockAccountStorageMockRecorder) GetHold(ctx, holdID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHold", reflect.TypeOf((*MockAccountStorage)(nil).GetHold), ctx, holdID)
+}
+
+// GetLedgerBalance mocks base method.
+func (m *MockAccountStorage) GetLedgerBalance(ctx context.Context, accountID string) (*models.LedgerBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLedgerBalance", ctx, accountID)
+	ret0, _ := ret[0].(*models.LedgerBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLedgerBalance indicates an expected call of GetLedgerBalance.
+func (mr *MockAccountStorageMockRecorder) GetLedgerBalance(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLedgerBalance", reflect.TypeOf((*MockAccountStorage)(nil).GetLedgerBalance), ctx, accountID)
+}
+
+// GetLimitBreaches mocks base method.
+func (m *MockAccountStorage) GetLimitBreaches(ctx context.Context, accountID string, limit int) ([]models.LimitBreach, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLimitBreaches", ctx, accountID, limit)
+	ret0, _ := ret[0].([]models.LimitBreach)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLimitBreaches indicates an expected call of GetLimitBreaches.
+func (mr *MockAccountStorageMockRecorder) GetLimitBreaches(ctx, accountID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLimitBreaches", reflect.TypeOf((*MockAccountStorage)(nil).GetLimitBreaches), ctx, accountID, limit)
+}
+
+// GetSystemAccounts mocks base method.
+func (m *MockAccountStorage) GetSystemAccounts(ctx context.Context) ([]models.SystemAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSystemAccounts", ctx)
+	ret0, _ := ret[0].([]models.SystemAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSystemAccounts indicates an expected call of GetSystemAccounts.
+func (mr *MockAccountStorageMockRecorder) GetSystemAccounts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSystemAccounts", reflect.TypeOf((*MockAccountStorage)(nil).GetSystemAccounts), ctx)
+}
+
+// ListAccounts mocks base method.
+func (m *MockAccountStorage) ListAccounts(ctx context.Context, afterID string, limit int) ([]models.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccounts", ctx, afterID, limit)
+	ret0, _ := ret[0].([]models.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccounts indicates an expected call of ListAccounts.
+func (mr *MockAccountStorageMockRecorder) ListAccounts(ctx, afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockAccountStorage)(nil).ListAccounts), ctx, afterID, limit)
+}
+
+// PostJournalEntry mocks base method.
+func (m *MockAccountStorage) PostJournalEntry(ctx context.Context, entry *models.JournalEntry) ([]models.BalanceChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostJournalEntry", ctx, entry)
+	ret0, _ := ret[0].([]models.BalanceChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostJournalEntry indicates an expected call of PostJournalEntry.
+func (mr *MockAccountStorageMockRecorder) PostJournalEntry(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostJournalEntry", reflect.TypeOf((*MockAccountStorage)(nil).PostJournalEntry), ctx, entry)
+}
+
+// RecordLimitBreach mocks base method.
+func (m *MockAccountStorage) RecordLimitBreach(ctx context.Context, breach *models.LimitBreach) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordLimitBreach", ctx, breach)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordLimitBreach indicates an expected call of RecordLimitBreach.
+func (mr *MockAccountStorageMockRecorder) RecordLimitBreach(ctx, breach any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLimitBreach", reflect.TypeOf((*MockAccountStorage)(nil).RecordLimitBreach), ctx, breach)
+}
+
+// ReleaseHold mocks base method.
+func (m *MockAccountStorage) ReleaseHold(ctx context.Context, holdID string) (*models.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseHold", ctx, holdID)
+	ret0, _ := ret[0].(*models.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseHold indicates an expected call of ReleaseHold.
+func (mr *MockAccountStorageMockRecorder) ReleaseHold(ctx, holdID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseHold", reflect.TypeOf((*MockAccountStorage)(nil).ReleaseHold), ctx, holdID)
+}
+
+// ReopenHold mocks base method.
+func (m *MockAccountStorage) ReopenHold(ctx context.Context, holdID, transactionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReopenHold", ctx, holdID, transactionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReopenHold indicates an expected call of ReopenHold.
+func (mr *MockAccountStorageMockRecorder) ReopenHold(ctx, holdID, transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReopenHold", reflect.TypeOf((*MockAccountStorage)(nil).ReopenHold), ctx, holdID, transactionID)
+}
+
+// RevertAppliedTransactions mocks base method.
+func (m *MockAccountStorage) RevertAppliedTransactions(ctx context.Context, transactionIDs ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range transactionIDs {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RevertAppliedTransactions", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevertAppliedTransactions indicates an expected call of RevertAppliedTransactions.
+func (mr *MockAccountStorageMockRecorder) RevertAppliedTransactions(ctx any, transactionIDs ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, transactionIDs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertAppliedTransactions", reflect.TypeOf((*MockAccountStorage)(nil).RevertAppliedTransactions), varargs...)
+}
+
+// SetLimitOverrides mocks base method.
+func (m *MockAccountStorage) SetLimitOverrides(ctx context.Context, accountID string, overrides *models.LimitOverrides) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLimitOverrides", ctx, accountID, overrides)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLimitOverrides indicates an expected call of SetLimitOverrides.
+func (mr *MockAccountStorageMockRecorder) SetLimitOverrides(ctx, accountID, overrides any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLimitOverrides", reflect.TypeOf((*MockAccountStorage)(nil).SetLimitOverrides), ctx, accountID, overrides)
+}
+
+// SetOverdraftLimit mocks base method.
+func (m *MockAccountStorage) SetOverdraftLimit(ctx context.Context, accountID string, limit models.Money) (*models.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOverdraftLimit", ctx, accountID, limit)
+	ret0, _ := ret[0].(*models.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetOverdraftLimit indicates an expected call of SetOverdraftLimit.
+func (mr *MockAccountStorageMockRecorder) SetOverdraftLimit(ctx, accountID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOverdraftLimit", reflect.TypeOf((*MockAccountStorage)(nil).SetOverdraftLimit), ctx, accountID, limit)
+}
+
+// UpdateAccountStatus mocks base method.
+func (m *MockAccountStorage) UpdateAccountStatus(ctx context.Context, accountID, status, reason string) (*models.AccountStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountStatus", ctx, accountID, status, reason)
+	ret0, _ := ret[0].(*models.AccountStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountStatus indicates an expected call of UpdateAccountStatus.
+func (mr *MockAccountStorageMockRecorder) UpdateAccountStatus(ctx, accountID, status, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountStatus", reflect.TypeOf((*MockAccountStorage)(nil).UpdateAccountStatus), ctx, accountID, status, reason)
+}
+
+// MockTransactionStorage is a mock of TransactionStorage interface.
+type MockTransactionStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockTransactionStorageMockRecorder is the mock recorder for MockTransactionStorage.
+type MockTransactionStorageMockRecorder struct {
+	mock *MockTransactionStorage
+}
+
+// NewMockTransactionStorage creates a new mock instance.
+func NewMockTransactionStorage(ctrl *gomock.Controller) *MockTransactionStorage {
+	mock := &MockTransactionStorage{ctrl: ctrl}
+	mock.recorder = &MockTransactionStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionStorage) EXPECT() *MockTransactionStorageMockRecorder {
+	return m.recorder
+}
+
+// ClaimOutboxEntry mocks base method.
+func (m *MockTransactionStorage) ClaimOutboxEntry(ctx context.Context, now time.Time, lease time.Duration) (*models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimOutboxEntry", ctx, now, lease)
+	ret0, _ := ret[0].(*models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimOutboxEntry indicates an expected call of ClaimOutboxEntry.
+func (mr *MockTransactionStorageMockRecorder) ClaimOutboxEntry(ctx, now, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimOutboxEntry", reflect.TypeOf((*MockTransactionStorage)(nil).ClaimOutboxEntry), ctx, now, lease)
+}
+
+// CountStalePendingTransactions mocks base method.
+func (m *MockTransactionStorage) CountStalePendingTransactions(ctx context.Context, olderThan time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountStalePendingTransactions", ctx, olderThan)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountStalePendingTransactions indicates an expected call of CountStalePendingTransactions.
+func (mr *MockTransactionStorageMockRecorder) CountStalePendingTransactions(ctx, olderThan any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountStalePendingTransactions", reflect.TypeOf((*MockTransactionStorage)(nil).CountStalePendingTransactions), ctx, olderThan)
+}
+
+// CreateTransaction mocks base method.
+func (m *MockTransactionStorage) CreateTransaction(ctx context.Context, transaction *models.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransaction", ctx, transaction)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTransaction indicates an expected call of CreateTransaction.
+func (mr *MockTransactionStorageMockRecorder) CreateTransaction(ctx, transaction any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockTransactionStorage)(nil).CreateTransaction), ctx, transaction)
+}
+
+// CreateTransactions mocks base method.
+func (m *MockTransactionStorage) CreateTransactions(ctx context.Context, transactions []*models.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransactions", ctx, transactions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTransactions indicates an expected call of CreateTransactions.
+func (mr *MockTransactionStorageMockRecorder) CreateTransactions(ctx, transactions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransactions", reflect.TypeOf((*MockTransactionStorage)(nil).CreateTransactions), ctx, transactions)
+}
+
+// GetAccountTransactions mocks base method.
+func (m *MockTransactionStorage) GetAccountTransactions(ctx context.Context, accountID string) ([]models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountTransactions", ctx, accountID)
+	ret0, _ := ret[0].([]models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountTransactions indicates an expected call of GetAccountTransactions.
+func (mr *MockTransactionStorageMockRecorder) GetAccountTransactions(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountTransactions", reflect.TypeOf((*MockTransactionStorage)(nil).GetAccountTransactions), ctx, accountID)
+}
+
+// GetEarliestTransactionFrom mocks base method.
+func (m *MockTransactionStorage) GetEarliestTransactionFrom(ctx context.Context, accountID string, t time.Time) (*models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEarliestTransactionFrom", ctx, accountID, t)
+	ret0, _ := ret[0].(*models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEarliestTransactionFrom indicates an expected call of GetEarliestTransactionFrom.
+func (mr *MockTransactionStorageMockRecorder) GetEarliestTransactionFrom(ctx, accountID, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEarliestTransactionFrom", reflect.TypeOf((*MockTransactionStorage)(nil).GetEarliestTransactionFrom), ctx, accountID, t)
+}
+
+// GetLatestTransactionBefore mocks base method.
+func (m *MockTransactionStorage) GetLatestTransactionBefore(ctx context.Context, accountID string, t time.Time) (*models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestTransactionBefore", ctx, accountID, t)
+	ret0, _ := ret[0].(*models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestTransactionBefore indicates an expected call of GetLatestTransactionBefore.
+func (mr *MockTransactionStorageMockRecorder) GetLatestTransactionBefore(ctx, accountID, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestTransactionBefore", reflect.TypeOf((*MockTransactionStorage)(nil).GetLatestTransactionBefore), ctx, accountID, t)
+}
+
+// GetSealedTransactions mocks base method.
+func (m *MockTransactionStorage) GetSealedTransactions(ctx context.Context, accountID string) ([]models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSealedTransactions", ctx, accountID)
+	ret0, _ := ret[0].([]models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSealedTransactions indicates an expected call of GetSealedTransactions.
+func (mr *MockTransactionStorageMockRecorder) GetSealedTransactions(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSealedTransactions", reflect.TypeOf((*MockTransactionStorage)(nil).GetSealedTransactions), ctx, accountID)
+}
+
+// GetStalePendingTransactions mocks base method.
+func (m *MockTransactionStorage) GetStalePendingTransactions(ctx context.Context, olderThan time.Time, limit int) ([]models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStalePendingTransactions", ctx, olderThan, limit)
+	ret0, _ := ret[0].([]models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStalePendingTransactions indicates an expected call of GetStalePendingTransactions.
+func (mr *MockTransactionStorageMockRecorder) GetStalePendingTransactions(ctx, olderThan, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStalePendingTransactions", reflect.TypeOf((*MockTransactionStorage)(nil).GetStalePendingTransactions), ctx, olderThan, limit)
+}
+
+// GetTransactionByID mocks base method.
+func (m *MockTransactionStorage) GetTransactionByID(ctx context.Context, transactionID string) (*models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionByID", ctx, transactionID)
+	ret0, _ := ret[0].(*models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionByID indicates an expected call of GetTransactionByID.
+func (mr *MockTransactionStorageMockRecorder) GetTransactionByID(ctx, transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByID", reflect.TypeOf((*MockTransactionStorage)(nil).GetTransactionByID), ctx, transactionID)
+}
+
+// GetTransactionsByAccountID mocks base method.
+func (m *MockTransactionStorage) GetTransactionsByAccountID(ctx context.Context, accountID string, page, limit int) ([]models.Transaction, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionsByAccountID", ctx, accountID, page, limit)
+	ret0, _ := ret[0].([]models.Transaction)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTransactionsByAccountID indicates an expected call of GetTransactionsByAccountID.
+func (mr *MockTransactionStorageMockRecorder) GetTransactionsByAccountID(ctx, accountID, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByAccountID", reflect.TypeOf((*MockTransactionStorage)(nil).GetTransactionsByAccountID), ctx, accountID, page, limit)
+}
+
+// GetTransactionsByTransferID mocks base method.
+func (m *MockTransactionStorage) GetTransactionsByTransferID(ctx context.Context, transferID string) ([]models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionsByTransferID", ctx, transferID)
+	ret0, _ := ret[0].([]models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionsByTransferID indicates an expected call of GetTransactionsByTransferID.
+func (mr *MockTransactionStorageMockRecorder) GetTransactionsByTransferID(ctx, transferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByTransferID", reflect.TypeOf((*MockTransactionStorage)(nil).GetTransactionsByTransferID), ctx, transferID)
+}
+
+// GetTransactionsInRange mocks base method.
+func (m *MockTransactionStorage) GetTransactionsInRange(ctx context.Context, accountID string, from, to time.Time) ([]models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionsInRange", ctx, accountID, from, to)
+	ret0, _ := ret[0].([]models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionsInRange indicates an expected call of GetTransactionsInRange.
+func (mr *MockTransactionStorageMockRecorder) GetTransactionsInRange(ctx, accountID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsInRange", reflect.TypeOf((*MockTransactionStorage)(nil).GetTransactionsInRange), ctx, accountID, from, to)
+}
+
+// GetTransactionsPage mocks base method.
+func (m *MockTransactionStorage) GetTransactionsPage(ctx context.Context, accountID string, filter *models.TransactionFilter, after *models.TransactionCursor, limit int) ([]models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionsPage", ctx, accountID, filter, after, limit)
+	ret0, _ := ret[0].([]models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionsPage indicates an expected call of GetTransactionsPage.
+func (mr *MockTransactionStorageMockRecorder) GetTransactionsPage(ctx, accountID, filter, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsPage", reflect.TypeOf((*MockTransactionStorage)(nil).GetTransactionsPage), ctx, accountID, filter, after, limit)
+}
+
+// MarkOutboxDispatched mocks base method.
+func (m *MockTransactionStorage) MarkOutboxDispatched(ctx context.Context, transactionID string, dispatchedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxDispatched", ctx, transactionID, dispatchedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOutboxDispatched indicates an expected call of MarkOutboxDispatched.
+func (mr *MockTransactionStorageMockRecorder) MarkOutboxDispatched(ctx, transactionID, dispatchedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxDispatched", reflect.TypeOf((*MockTransactionStorage)(nil).MarkOutboxDispatched), ctx, transactionID, dispatchedAt)
+}
+
+// RecordRefund mocks base method.
+func (m *MockTransactionStorage) RecordRefund(ctx context.Context, transactionID, reversalID string, amount models.Money, refundedAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordRefund", ctx, transactionID, reversalID, amount, refundedAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordRefund indicates an expected call of RecordRefund.
+func (mr *MockTransactionStorageMockRecorder) RecordRefund(ctx, transactionID, reversalID, amount, refundedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordRefund", reflect.TypeOf((*MockTransactionStorage)(nil).RecordRefund), ctx, transactionID, reversalID, amount, refundedAt)
+}
+
+// RequeueOutboxEntry mocks base method.
+func (m *MockTransactionStorage) RequeueOutboxEntry(ctx context.Context, transactionID string, entry *models.OutboxEntry, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueOutboxEntry", ctx, transactionID, entry, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequeueOutboxEntry indicates an expected call of RequeueOutboxEntry.
+func (mr *MockTransactionStorageMockRecorder) RequeueOutboxEntry(ctx, transactionID, entry, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueOutboxEntry", reflect.TypeOf((*MockTransactionStorage)(nil).RequeueOutboxEntry), ctx, transactionID, entry, reason)
+}
+
+// RescheduleOutboxEntry mocks base method.
+func (m *MockTransactionStorage) RescheduleOutboxEntry(ctx context.Context, transactionID string, nextAttemptAt time.Time, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RescheduleOutboxEntry", ctx, transactionID, nextAttemptAt, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RescheduleOutboxEntry indicates an expected call of RescheduleOutboxEntry.
+func (mr *MockTransactionStorageMockRecorder) RescheduleOutboxEntry(ctx, transactionID, nextAttemptAt, lastError any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RescheduleOutboxEntry", reflect.TypeOf((*MockTransactionStorage)(nil).RescheduleOutboxEntry), ctx, transactionID, nextAttemptAt, lastError)
+}
+
+// RestoreRefund mocks base method.
+func (m *MockTransactionStorage) RestoreRefund(ctx context.Context, transactionID, reversalID string, amount models.Money) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreRefund", ctx, transactionID, reversalID, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreRefund indicates an expected call of RestoreRefund.
+func (mr *MockTransactionStorageMockRecorder) RestoreRefund(ctx, transactionID, reversalID, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRefund", reflect.TypeOf((*MockTransactionStorage)(nil).RestoreRefund), ctx, transactionID, reversalID, amount)
+}
+
+// UpdateTransaction mocks base method.
+func (m *MockTransactionStorage) UpdateTransaction(ctx context.Context, transaction *models.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTransaction", ctx, transaction)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTransaction indicates an expected call of UpdateTransaction.
+func (mr *MockTransactionStorageMockRecorder) UpdateTransaction(ctx, transaction any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransaction", reflect.TypeOf((*MockTransactionStorage)(nil).UpdateTransaction), ctx, transaction)
+}
+
+// UpdateTransactionStatus mocks base method.
+func (m *MockTransactionStorage) UpdateTransactionStatus(ctx context.Context, transactionID, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTransactionStatus", ctx, transactionID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTransactionStatus indicates an expected call of UpdateTransactionStatus.
+func (mr *MockTransactionStorageMockRecorder) UpdateTransactionStatus(ctx, transactionID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransactionStatus", reflect.TypeOf((*MockTransactionStorage)(nil).UpdateTransactionStatus), ctx, transactionID, status)
+}
+
+// UpdateTransactionStatusWithError mocks base method.
+func (m *MockTransactionStorage) UpdateTransactionStatusWithError(ctx context.Context, transactionID, status, errorMessage string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTransactionStatusWithError", ctx, transactionID, status, errorMessage)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTransactionStatusWithError indicates an expected call of UpdateTransactionStatusWithError.
+func (mr *MockTransactionStorageMockRecorder) UpdateTransactionStatusWithError(ctx, transactionID, status, errorMessage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransactionStatusWithError", reflect.TypeOf((*MockTransactionStorage)(nil).UpdateTransactionStatusWithError), ctx, transactionID, status, errorMessage)
+}
+
+// MockIdempotencyStorage is a mock of IdempotencyStorage interface.
+type MockIdempotencyStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockIdempotencyStorageMockRecorder is the mock recorder for MockIdempotencyStorage.
+type MockIdempotencyStorageMockRecorder struct {
+	mock *MockIdempotencyStorage
+}
+
+// NewMockIdempotencyStorage creates a new mock instance.
+func NewMockIdempotencyStorage(ctrl *gomock.Controller) *MockIdempotencyStorage {
+	mock := &MockIdempotencyStorage{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyStorage) EXPECT() *MockIdempotencyStorageMockRecorder {
+	return m.recorder
+}
+
+// ClaimIdempotencyKey mocks base method.
+func (m *MockIdempotencyStorage) ClaimIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimIdempotencyKey", ctx, record)
+	ret0, _ := ret[0].(*models.IdempotencyRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimIdempotencyKey indicates an expected call of ClaimIdempotencyKey.
+func (mr *MockIdempotencyStorageMockRecorder) ClaimIdempotencyKey(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimIdempotencyKey", reflect.TypeOf((*MockIdempotencyStorage)(nil).ClaimIdempotencyKey), ctx, record)
+}
+
+// CompleteIdempotencyRecord mocks base method.
+func (m *MockIdempotencyStorage) CompleteIdempotencyRecord(ctx context.Context, accountID, key string, statusCode int, responseBody []byte, transactionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteIdempotencyRecord", ctx, accountID, key, statusCode, responseBody, transactionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteIdempotencyRecord indicates an expected call of CompleteIdempotencyRecord.
+func (mr *MockIdempotencyStorageMockRecorder) CompleteIdempotencyRecord(ctx, accountID, key, statusCode, responseBody, transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteIdempotencyRecord", reflect.TypeOf((*MockIdempotencyStorage)(nil).CompleteIdempotencyRecord), ctx, accountID, key, statusCode, responseBody, transactionID)
+}
+
+// DeleteIdempotencyRecord mocks base method.
+func (m *MockIdempotencyStorage) DeleteIdempotencyRecord(ctx context.Context, accountID, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdempotencyRecord", ctx, accountID, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIdempotencyRecord indicates an expected call of DeleteIdempotencyRecord.
+func (mr *MockIdempotencyStorageMockRecorder) DeleteIdempotencyRecord(ctx, accountID, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdempotencyRecord", reflect.TypeOf((*MockIdempotencyStorage)(nil).DeleteIdempotencyRecord), ctx, accountID, key)
+}
+
+// DeleteStaleIdempotencyRecord mocks base method.
+func (m *MockIdempotencyStorage) DeleteStaleIdempotencyRecord(ctx context.Context, accountID, key string, claimedAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStaleIdempotencyRecord", ctx, accountID, key, claimedAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteStaleIdempotencyRecord indicates an expected call of DeleteStaleIdempotencyRecord.
+func (mr *MockIdempotencyStorageMockRecorder) DeleteStaleIdempotencyRecord(ctx, accountID, key, claimedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStaleIdempotencyRecord", reflect.TypeOf((*MockIdempotencyStorage)(nil).DeleteStaleIdempotencyRecord), ctx, accountID, key, claimedAt)
+}
+
+// MockDeadLetterStorage is a mock of DeadLetterStorage interface.
+type MockDeadLetterStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLetterStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockDeadLetterStorageMockRecorder is the mock recorder for MockDeadLetterStorage.
+type MockDeadLetterStorageMockRecorder struct {
+	mock *MockDeadLetterStorage
+}
+
+// NewMockDeadLetterStorage creates a new mock instance.
+func NewMockDeadLetterStorage(ctrl *gomock.Controller) *MockDeadLetterStorage {
+	mock := &MockDeadLetterStorage{ctrl: ctrl}
+	mock.recorder = &MockDeadLetterStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetterStorage) EXPECT() *MockDeadLetterStorageMockRecorder {
+	return m.recorder
+}
+
+// GetDeadLetterByID mocks base method.
+func (m *MockDeadLetterStorage) GetDeadLetterByID(ctx context.Context, id string) (*models.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadLetterByID", ctx, id)
+	ret0, _ := ret[0].(*models.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeadLetterByID indicates an expected call of GetDeadLetterByID.
+func (mr *MockDeadLetterStorageMockRecorder) GetDeadLetterByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLetterByID", reflect.TypeOf((*MockDeadLetterStorage)(nil).GetDeadLetterByID), ctx, id)
+}
+
+// GetDeadLetters mocks base method.
+func (m *MockDeadLetterStorage) GetDeadLetters(ctx context.Context, status string, page, limit int) ([]models.DeadLetter, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadLetters", ctx, status, page, limit)
+	ret0, _ := ret[0].([]models.DeadLetter)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetDeadLetters indicates an expected call of GetDeadLetters.
+func (mr *MockDeadLetterStorageMockRecorder) GetDeadLetters(ctx, status, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLetters", reflect.TypeOf((*MockDeadLetterStorage)(nil).GetDeadLetters), ctx, status, page, limit)
+}
+
+// SaveDeadLetter mocks base method.
+func (m *MockDeadLetterStorage) SaveDeadLetter(ctx context.Context, deadLetter *models.DeadLetter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDeadLetter", ctx, deadLetter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveDeadLetter indicates an expected call of SaveDeadLetter.
+func (mr *MockDeadLetterStorageMockRecorder) SaveDeadLetter(ctx, deadLetter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDeadLetter", reflect.TypeOf((*MockDeadLetterStorage)(nil).SaveDeadLetter), ctx, deadLetter)
+}
+
+// TransitionDeadLetter mocks base method.
+func (m *MockDeadLetterStorage) TransitionDeadLetter(ctx context.Context, id, fromStatus, toStatus string, resolvedAt *time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransitionDeadLetter", ctx, id, fromStatus, toStatus, resolvedAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransitionDeadLetter indicates an expected call of TransitionDeadLetter.
+func (mr *MockDeadLetterStorageMockRecorder) TransitionDeadLetter(ctx, id, fromStatus, toStatus, resolvedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionDeadLetter", reflect.TypeOf((*MockDeadLetterStorage)(nil).TransitionDeadLetter), ctx, id, fromStatus, toStatus, resolvedAt)
+}
+
+// MockScheduleStorage is a mock of ScheduleStorage interface.
+type MockScheduleStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockScheduleStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockScheduleStorageMockRecorder is the mock recorder for MockScheduleStorage.
+type MockScheduleStorageMockRecorder struct {
+	mock *MockScheduleStorage
+}
+
+// NewMockScheduleStorage creates a new mock instance.
+func NewMockScheduleStorage(ctrl *gomock.Controller) *MockScheduleStorage {
+	mock := &MockScheduleStorage{ctrl: ctrl}
+	mock.recorder = &MockScheduleStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScheduleStorage) EXPECT() *MockScheduleStorageMockRecorder {
+	return m.recorder
+}
+
+// AdvanceSchedule mocks base method.
+func (m *MockScheduleStorage) AdvanceSchedule(ctx context.Context, scheduleID string, from time.Time, step *models.ScheduleStep, transactionID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvanceSchedule", ctx, scheduleID, from, step, transactionID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdvanceSchedule indicates an expected call of AdvanceSchedule.
+func (mr *MockScheduleStorageMockRecorder) AdvanceSchedule(ctx, scheduleID, from, step, transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceSchedule", reflect.TypeOf((*MockScheduleStorage)(nil).AdvanceSchedule), ctx, scheduleID, from, step, transactionID)
+}
+
+// ChangeSchedule mocks base method.
+func (m *MockScheduleStorage) ChangeSchedule(ctx context.Context, scheduleID string, change func(*models.Schedule) error) (*models.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeSchedule", ctx, scheduleID, change)
+	ret0, _ := ret[0].(*models.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeSchedule indicates an expected call of ChangeSchedule.
+func (mr *MockScheduleStorageMockRecorder) ChangeSchedule(ctx, scheduleID, change any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeSchedule", reflect.TypeOf((*MockScheduleStorage)(nil).ChangeSchedule), ctx, scheduleID, change)
+}
+
+// ClaimDueSchedules mocks base method.
+func (m *MockScheduleStorage) ClaimDueSchedules(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueSchedules", ctx, now, leaseUntil, limit)
+	ret0, _ := ret[0].([]models.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueSchedules indicates an expected call of ClaimDueSchedules.
+func (mr *MockScheduleStorageMockRecorder) ClaimDueSchedules(ctx, now, leaseUntil, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueSchedules", reflect.TypeOf((*MockScheduleStorage)(nil).ClaimDueSchedules), ctx, now, leaseUntil, limit)
+}
+
+// CreateSchedule mocks base method.
+func (m *MockScheduleStorage) CreateSchedule(ctx context.Context, schedule *models.Schedule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSchedule", ctx, schedule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSchedule indicates an expected call of CreateSchedule.
+func (mr *MockScheduleStorageMockRecorder) CreateSchedule(ctx, schedule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSchedule", reflect.TypeOf((*MockScheduleStorage)(nil).CreateSchedule), ctx, schedule)
+}
+
+// GetAccountSchedules mocks base method.
+func (m *MockScheduleStorage) GetAccountSchedules(ctx context.Context, accountID string) ([]models.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountSchedules", ctx, accountID)
+	ret0, _ := ret[0].([]models.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountSchedules indicates an expected call of GetAccountSchedules.
+func (mr *MockScheduleStorageMockRecorder) GetAccountSchedules(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountSchedules", reflect.TypeOf((*MockScheduleStorage)(nil).GetAccountSchedules), ctx, accountID)
+}
+
+// GetSchedule mocks base method.
+func (m *MockScheduleStorage) GetSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchedule", ctx, scheduleID)
+	ret0, _ := ret[0].(*models.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSchedule indicates an expected call of GetSchedule.
+func (mr *MockScheduleStorageMockRecorder) GetSchedule(ctx, scheduleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchedule", reflect.TypeOf((*MockScheduleStorage)(nil).GetSchedule), ctx, scheduleID)
+}
+
+// MockAuditStorage is a mock of AuditStorage interface.
+type MockAuditStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditStorageMockRecorder is the mock recorder for MockAuditStorage.
+type MockAuditStorageMockRecorder struct {
+	mock *MockAuditStorage
+}
+
+// NewMockAuditStorage creates a new mock instance.
+func NewMockAuditStorage(ctrl *gomock.Controller) *MockAuditStorage {
+	mock := &MockAuditStorage{ctrl: ctrl}
+	mock.recorder = &MockAuditStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditStorage) EXPECT() *MockAuditStorageMockRecorder {
+	return m.recorder
+}
+
+// GetAuditRecordsPage mocks base method.
+func (m *MockAuditStorage) GetAuditRecordsPage(ctx context.Context, filter *models.AuditFilter, after *models.AuditCursor, limit int) ([]models.AuditRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditRecordsPage", ctx, filter, after, limit)
+	ret0, _ := ret[0].([]models.AuditRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditRecordsPage indicates an expected call of GetAuditRecordsPage.
+func (mr *MockAuditStorageMockRecorder) GetAuditRecordsPage(ctx, filter, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditRecordsPage", reflect.TypeOf((*MockAuditStorage)(nil).GetAuditRecordsPage), ctx, filter, after, limit)
+}
+
+// InsertAuditRecord mocks base method.
+func (m *MockAuditStorage) InsertAuditRecord(ctx context.Context, record *models.AuditRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAuditRecord", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertAuditRecord indicates an expected call of InsertAuditRecord.
+func (mr *MockAuditStorageMockRecorder) InsertAuditRecord(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAuditRecord", reflect.TypeOf((*MockAuditStorage)(nil).InsertAuditRecord), ctx, record)
+}
+
+// MockWebhookStorage is a mock of WebhookStorage interface.
+type MockWebhookStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockWebhookStorageMockRecorder is the mock recorder for MockWebhookStorage.
+type MockWebhookStorageMockRecorder struct {
+	mock *MockWebhookStorage
+}
+
+// NewMockWebhookStorage creates a new mock instance.
+func NewMockWebhookStorage(ctrl *gomock.Controller) *MockWebhookStorage {
+	mock := &MockWebhookStorage{ctrl: ctrl}
+	mock.recorder = &MockWebhookStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookStorage) EXPECT() *MockWebhookStorageMockRecorder {
+	return m.recorder
+}
+
+// ClaimDueWebhookDeliveries mocks base method.
+func (m *MockWebhookStorage) ClaimDueWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueWebhookDeliveries", ctx, now, leaseUntil, limit)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueWebhookDeliveries indicates an expected call of ClaimDueWebhookDeliveries.
+func (mr *MockWebhookStorageMockRecorder) ClaimDueWebhookDeliveries(ctx, now, leaseUntil, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueWebhookDeliveries", reflect.TypeOf((*MockWebhookStorage)(nil).ClaimDueWebhookDeliveries), ctx, now, leaseUntil, limit)
+}
+
+// CreateWebhookDeliveries mocks base method.
+func (m *MockWebhookStorage) CreateWebhookDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDeliveries", ctx, deliveries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebhookDeliveries indicates an expected call of CreateWebhookDeliveries.
+func (mr *MockWebhookStorageMockRecorder) CreateWebhookDeliveries(ctx, deliveries any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDeliveries", reflect.TypeOf((*MockWebhookStorage)(nil).CreateWebhookDeliveries), ctx, deliveries)
+}
+
+// CreateWebhookSubscription mocks base method.
+func (m *MockWebhookStorage) CreateWebhookSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookSubscription", ctx, subscription)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebhookSubscription indicates an expected call of CreateWebhookSubscription.
+func (mr *MockWebhookStorageMockRecorder) CreateWebhookSubscription(ctx, subscription any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookSubscription", reflect.TypeOf((*MockWebhookStorage)(nil).CreateWebhookSubscription), ctx, subscription)
+}
+
+// DeleteWebhookSubscription mocks base method.
+func (m *MockWebhookStorage) DeleteWebhookSubscription(ctx context.Context, subscriptionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookSubscription", ctx, subscriptionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookSubscription indicates an expected call of DeleteWebhookSubscription.
+func (mr *MockWebhookStorageMockRecorder) DeleteWebhookSubscription(ctx, subscriptionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookSubscription", reflect.TypeOf((*MockWebhookStorage)(nil).DeleteWebhookSubscription), ctx, subscriptionID)
+}
+
+// GetSubscribedWebhooks mocks base method.
+func (m *MockWebhookStorage) GetSubscribedWebhooks(ctx context.Context, accountID, eventType string) ([]models.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscribedWebhooks", ctx, accountID, eventType)
+	ret0, _ := ret[0].([]models.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscribedWebhooks indicates an expected call of GetSubscribedWebhooks.
+func (mr *MockWebhookStorageMockRecorder) GetSubscribedWebhooks(ctx, accountID, eventType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscribedWebhooks", reflect.TypeOf((*MockWebhookStorage)(nil).GetSubscribedWebhooks), ctx, accountID, eventType)
+}
+
+// GetWebhookDeliveries mocks base method.
+func (m *MockWebhookStorage) GetWebhookDeliveries(ctx context.Context, subscriptionID, status string, page, limit int) ([]models.WebhookDelivery, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveries", ctx, subscriptionID, status, page, limit)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetWebhookDeliveries indicates an expected call of GetWebhookDeliveries.
+func (mr *MockWebhookStorageMockRecorder) GetWebhookDeliveries(ctx, subscriptionID, status, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveries", reflect.TypeOf((*MockWebhookStorage)(nil).GetWebhookDeliveries), ctx, subscriptionID, status, page, limit)
+}
+
+// GetWebhookDelivery mocks base method.
+func (m *MockWebhookStorage) GetWebhookDelivery(ctx context.Context, deliveryID string) (*models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDelivery", ctx, deliveryID)
+	ret0, _ := ret[0].(*models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDelivery indicates an expected call of GetWebhookDelivery.
+func (mr *MockWebhookStorageMockRecorder) GetWebhookDelivery(ctx, deliveryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDelivery", reflect.TypeOf((*MockWebhookStorage)(nil).GetWebhookDelivery), ctx, deliveryID)
+}
+
+// GetWebhookSubscription mocks base method.
+func (m *MockWebhookStorage) GetWebhookSubscription(ctx context.Context, subscriptionID string) (*models.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookSubscription", ctx, subscriptionID)
+	ret0, _ := ret[0].(*models.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookSubscription indicates an expected call of GetWebhookSubscription.
+func (mr *MockWebhookStorageMockRecorder) GetWebhookSubscription(ctx, subscriptionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookSubscription", reflect.TypeOf((*MockWebhookStorage)(nil).GetWebhookSubscription), ctx, subscriptionID)
+}
+
+// GetWebhookSubscriptions mocks base method.
+func (m *MockWebhookStorage) GetWebhookSubscriptions(ctx context.Context, accountID string) ([]models.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookSubscriptions", ctx, accountID)
+	ret0, _ := ret[0].([]models.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookSubscriptions indicates an expected call of GetWebhookSubscriptions.
+func (mr *MockWebhookStorageMockRecorder) GetWebhookSubscriptions(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookSubscriptions", reflect.TypeOf((*MockWebhookStorage)(nil).GetWebhookSubscriptions), ctx, accountID)
+}
+
+// RecordWebhookAttempt mocks base method.
+func (m *MockWebhookStorage) RecordWebhookAttempt(ctx context.Context, delivery *models.WebhookDelivery, attempt *models.WebhookAttempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordWebhookAttempt", ctx, delivery, attempt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordWebhookAttempt indicates an expected call of RecordWebhookAttempt.
+func (mr *MockWebhookStorageMockRecorder) RecordWebhookAttempt(ctx, delivery, attempt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWebhookAttempt", reflect.TypeOf((*MockWebhookStorage)(nil).RecordWebhookAttempt), ctx, delivery, attempt)
+}
+
+// MockEventNotifier is a mock of EventNotifier interface.
+type MockEventNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventNotifierMockRecorder
+	isgomock struct{}
+}
+
+// MockEventNotifierMockRecorder is the mock recorder for MockEventNotifier.
+type MockEventNotifierMockRecorder struct {
+	mock *MockEventNotifier
+}
+
+// NewMockEventNotifier creates a new mock instance.
+func NewMockEventNotifier(ctrl *gomock.Controller) *MockEventNotifier {
+	mock := &MockEventNotifier{ctrl: ctrl}
+	mock.recorder = &MockEventNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventNotifier) EXPECT() *MockEventNotifierMockRecorder {
+	return m.recorder
+}
+
+// AccountCreated mocks base method.
+func (m *MockEventNotifier) AccountCreated(ctx context.Context, account *models.Account) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AccountCreated", ctx, account)
+}
+
+// AccountCreated indicates an expected call of AccountCreated.
+func (mr *MockEventNotifierMockRecorder) AccountCreated(ctx, account any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountCreated", reflect.TypeOf((*MockEventNotifier)(nil).AccountCreated), ctx, account)
+}
+
+// TransactionCompleted mocks base method.
+func (m *MockEventNotifier) TransactionCompleted(ctx context.Context, transaction *models.Transaction) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "TransactionCompleted", ctx, transaction)
+}
+
+// TransactionCompleted indicates an expected call of TransactionCompleted.
+func (mr *MockEventNotifierMockRecorder) TransactionCompleted(ctx, transaction any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionCompleted", reflect.TypeOf((*MockEventNotifier)(nil).TransactionCompleted), ctx, transaction)
+}
+
+// TransactionFailed mocks base method.
+func (m *MockEventNotifier) TransactionFailed(ctx context.Context, transaction *models.Transaction) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "TransactionFailed", ctx, transaction)
+}
+
+// TransactionFailed indicates an expected call of TransactionFailed.
+func (mr *MockEventNotifierMockRecorder) TransactionFailed(ctx, transaction any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionFailed", reflect.TypeOf((*MockEventNotifier)(nil).TransactionFailed), ctx, transaction)
+}
+
+// MockTransactionProcessor is a mock of TransactionProcessor interface.
+type MockTransactionProcessor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionProcessorMockRecorder
+	isgomock struct{}
+}
+
+// MockTransactionProcessorMockRecorder is the mock recorder for MockTransactionProcessor.
+type MockTransactionProcessorMockRecorder struct {
+	mock *MockTransactionProcessor
+}
+
+// NewMockTransactionProcessor creates a new mock instance.
+func NewMockTransactionProcessor(ctrl *gomock.Controller) *MockTransactionProcessor {
+	mock := &MockTransactionProcessor{ctrl: ctrl}
+	mock.recorder = &MockTransactionProcessorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionProcessor) EXPECT() *MockTransactionProcessorMockRecorder {
+	return m.recorder
+}
+
+// FailUnprocessedTransaction mocks base method.
+func (m *MockTransactionProcessor) FailUnprocessedTransaction(ctx context.Context, transactionID, errorMessage string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailUnprocessedTransaction", ctx, transactionID, errorMessage)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailUnprocessedTransaction indicates an expected call of FailUnprocessedTransaction.
+func (mr *MockTransactionProcessorMockRecorder) FailUnprocessedTransaction(ctx, transactionID, errorMessage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailUnprocessedTransaction", reflect.TypeOf((*MockTransactionProcessor)(nil).FailUnprocessedTransaction), ctx, transactionID, errorMessage)
+}
+
+// ProcessTransactionAsync mocks base method.
+func (m *MockTransactionProcessor) ProcessTransactionAsync(ctx context.Context, transactionID string, req *models.TransactionRequest) (*models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessTransactionAsync", ctx, transactionID, req)
+	ret0, _ := ret[0].(*models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessTransactionAsync indicates an expected call of ProcessTransactionAsync.
+func (mr *MockTransactionProcessorMockRecorder) ProcessTransactionAsync(ctx, transactionID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessTransactionAsync", reflect.TypeOf((*MockTransactionProcessor)(nil).ProcessTransactionAsync), ctx, transactionID, req)
+}
+
+// MockMessagePublisher is a mock of MessagePublisher interface.
+type MockMessagePublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessagePublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockMessagePublisherMockRecorder is the mock recorder for MockMessagePublisher.
+type MockMessagePublisherMockRecorder struct {
+	mock *MockMessagePublisher
+}
+
+// NewMockMessagePublisher creates a new mock instance.
+func NewMockMessagePublisher(ctrl *gomock.Controller) *MockMessagePublisher {
+	mock := &MockMessagePublisher{ctrl: ctrl}
+	mock.recorder = &MockMessagePublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessagePublisher) EXPECT() *MockMessagePublisherMockRecorder {
+	return m.recorder
+}
+
+// RepublishMessage mocks base method.
+func (m *MockMessagePublisher) RepublishMessage(ctx context.Context, messageID string, body []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepublishMessage", ctx, messageID, body)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RepublishMessage indicates an expected call of RepublishMessage.
+func (mr *MockMessagePublisherMockRecorder) RepublishMessage(ctx, messageID, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepublishMessage", reflect.TypeOf((*MockMessagePublisher)(nil).RepublishMessage), ctx, messageID, body)
+}
+
+// MockAccountServiceInterface is a mock of AccountServiceInterface interface.
+type MockAccountServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockAccountServiceInterfaceMockRecorder is the mock recorder for MockAccountServiceInterface.
+type MockAccountServiceInterfaceMockRecorder struct {
+	mock *MockAccountServiceInterface
+}
+
+// NewMockAccountServiceInterface creates a new mock instance.
+func NewMockAccountServiceInterface(ctrl *gomock.Controller) *MockAccountServiceInterface {
+	mock := &MockAccountServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockAccountServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountServiceInterface) EXPECT() *MockAccountServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ChangeAccountStatus mocks base method.
+func (m *MockAccountServiceInterface) ChangeAccountStatus(ctx context.Context, accountID, status string, req *models.AccountStatusRequest) (*models.AccountStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeAccountStatus", ctx, accountID, status, req)
+	ret0, _ := ret[0].(*models.AccountStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeAccountStatus indicates an expected call of ChangeAccountStatus.
+func (mr *MockAccountServiceInterfaceMockRecorder) ChangeAccountStatus(ctx, accountID, status, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeAccountStatus", reflect.TypeOf((*MockAccountServiceInterface)(nil).ChangeAccountStatus), ctx, accountID, status, req)
+}
+
+// CreateAccount mocks base method.
+func (m *MockAccountServiceInterface) CreateAccount(ctx context.Context, req *models.CreateAccountRequest) (*models.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccount", ctx, req)
+	ret0, _ := ret[0].(*models.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccount indicates an expected call of CreateAccount.
+func (mr *MockAccountServiceInterfaceMockRecorder) CreateAccount(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockAccountServiceInterface)(nil).CreateAccount), ctx, req)
+}
+
+// GetAccountBalance mocks base method.
+func (m *MockAccountServiceInterface) GetAccountBalance(ctx context.Context, accountID string) (models.Money, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountBalance", ctx, accountID)
+	ret0, _ := ret[0].(models.Money)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountBalance indicates an expected call of GetAccountBalance.
+func (mr *MockAccountServiceInterfaceMockRecorder) GetAccountBalance(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountBalance", reflect.TypeOf((*MockAccountServiceInterface)(nil).GetAccountBalance), ctx, accountID)
+}
+
+// GetAccountByID mocks base method.
+func (m *MockAccountServiceInterface) GetAccountByID(ctx context.Context, accountID string) (*models.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountByID", ctx, accountID)
+	ret0, _ := ret[0].(*models.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountByID indicates an expected call of GetAccountByID.
+func (mr *MockAccountServiceInterfaceMockRecorder) GetAccountByID(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByID", reflect.TypeOf((*MockAccountServiceInterface)(nil).GetAccountByID), ctx, accountID)
+}
+
+// GetAccountStatusHistory mocks base method.
+func (m *MockAccountServiceInterface) GetAccountStatusHistory(ctx context.Context, accountID string) ([]models.AccountStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountStatusHistory", ctx, accountID)
+	ret0, _ := ret[0].([]models.AccountStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountStatusHistory indicates an expected call of GetAccountStatusHistory.
+func (mr *MockAccountServiceInterfaceMockRecorder) GetAccountStatusHistory(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountStatusHistory", reflect.TypeOf((*MockAccountServiceInterface)(nil).GetAccountStatusHistory), ctx, accountID)
+}
+
+// GetLedgerBalance mocks base method.
+func (m *MockAccountServiceInterface) GetLedgerBalance(ctx context.Context, accountID string) (*models.LedgerBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLedgerBalance", ctx, accountID)
+	ret0, _ := ret[0].(*models.LedgerBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLedgerBalance indicates an expected call of GetLedgerBalance.
+func (mr *MockAccountServiceInterfaceMockRecorder) GetLedgerBalance(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLedgerBalance", reflect.TypeOf((*MockAccountServiceInterface)(nil).GetLedgerBalance), ctx, accountID)
+}
+
+// SetOverdraftLimit mocks base method.
+func (m *MockAccountServiceInterface) SetOverdraftLimit(ctx context.Context, accountID string, req *models.OverdraftLimitRequest) (*models.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOverdraftLimit", ctx, accountID, req)
+	ret0, _ := ret[0].(*models.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetOverdraftLimit indicates an expected call of SetOverdraftLimit.
+func (mr *MockAccountServiceInterfaceMockRecorder) SetOverdraftLimit(ctx, accountID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOverdraftLimit", reflect.TypeOf((*MockAccountServiceInterface)(nil).SetOverdraftLimit), ctx, accountID, req)
+}
+
+// MockTransactionServiceInterface is a mock of TransactionServiceInterface interface.
+type MockTransactionServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockTransactionServiceInterfaceMockRecorder is the mock recorder for MockTransactionServiceInterface.
+type MockTransactionServiceInterfaceMockRecorder struct {
+	mock *MockTransactionServiceInterface
+}
+
+// NewMockTransactionServiceInterface creates a new mock instance.
+func NewMockTransactionServiceInterface(ctrl *gomock.Controller) *MockTransactionServiceInterface {
+	mock := &MockTransactionServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockTransactionServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionServiceInterface) EXPECT() *MockTransactionServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CaptureHold mocks base method.
+func (m *MockTransactionServiceInterface) CaptureHold(ctx context.Context, holdID string, req *models.CaptureHoldRequest) (*models.HoldCapture, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureHold", ctx, holdID, req)
+	ret0, _ := ret[0].(*models.HoldCapture)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CaptureHold indicates an expected call of CaptureHold.
+func (mr *MockTransactionServiceInterfaceMockRecorder) CaptureHold(ctx, holdID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHold", reflect.TypeOf((*MockTransactionServiceInterface)(nil).CaptureHold), ctx, holdID, req)
+}
+
+// CreatePendingTransaction mocks base method.
+func (m *MockTransactionServiceInterface) CreatePendingTransaction(ctx context.Context, transaction *models.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePendingTransaction", ctx, transaction)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePendingTransaction indicates an expected call of CreatePendingTransaction.
+func (mr *MockTransactionServiceInterfaceMockRecorder) CreatePendingTransaction(ctx, transaction any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePendingTransaction", reflect.TypeOf((*MockTransactionServiceInterface)(nil).CreatePendingTransaction), ctx, transaction)
+}
+
+// CreatePendingTransfer mocks base method.
+func (m *MockTransactionServiceInterface) CreatePendingTransfer(ctx context.Context, transfer *models.Transfer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePendingTransfer", ctx, transfer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePendingTransfer indicates an expected call of CreatePendingTransfer.
+func (mr *MockTransactionServiceInterfaceMockRecorder) CreatePendingTransfer(ctx, transfer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePendingTransfer", reflect.TypeOf((*MockTransactionServiceInterface)(nil).CreatePendingTransfer), ctx, transfer)
+}
+
+// GetAccountByID mocks base method.
+func (m *MockTransactionServiceInterface) GetAccountByID(ctx context.Context, accountID string) (*models.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountByID", ctx, accountID)
+	ret0, _ := ret[0].(*models.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountByID indicates an expected call of GetAccountByID.
+func (mr *MockTransactionServiceInterfaceMockRecorder) GetAccountByID(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByID", reflect.TypeOf((*MockTransactionServiceInterface)(nil).GetAccountByID), ctx, accountID)
+}
+
+// GetAccountHolds mocks base method.
+func (m *MockTransactionServiceInterface) GetAccountHolds(ctx context.Context, accountID string) ([]models.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountHolds", ctx, accountID)
+	ret0, _ := ret[0].([]models.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountHolds indicates an expected call of GetAccountHolds.
+func (mr *MockTransactionServiceInterfaceMockRecorder) GetAccountHolds(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountHolds", reflect.TypeOf((*MockTransactionServiceInterface)(nil).GetAccountHolds), ctx, accountID)
+}
+
+// GetAccountLimits mocks base method.
+func (m *MockTransactionServiceInterface) GetAccountLimits(ctx context.Context, accountID string) (*models.AccountLimits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountLimits", ctx, accountID)
+	ret0, _ := ret[0].(*models.AccountLimits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountLimits indicates an expected call of GetAccountLimits.
+func (mr *MockTransactionServiceInterfaceMockRecorder) GetAccountLimits(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountLimits", reflect.TypeOf((*MockTransactionServiceInterface)(nil).GetAccountLimits), ctx, accountID)
+}
+
+// GetBalanceAsOf mocks base method.
+func (m *MockTransactionServiceInterface) GetBalanceAsOf(ctx context.Context, accountID string, asOf time.Time) (*models.HistoricalBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceAsOf", ctx, accountID, asOf)
+	ret0, _ := ret[0].(*models.HistoricalBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceAsOf indicates an expected call of GetBalanceAsOf.
+func (mr *MockTransactionServiceInterfaceMockRecorder) GetBalanceAsOf(ctx, accountID, asOf any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceAsOf", reflect.TypeOf((*MockTransactionServiceInterface)(nil).GetBalanceAsOf), ctx, accountID, asOf)
+}
+
+// GetHold mocks base method.
+func (m *MockTransactionServiceInterface) GetHold(ctx context.Context, holdID string) (*models.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHold", ctx, holdID)
+	ret0, _ := ret[0].(*models.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHold indicates an expected call of GetHold.
+func (mr *MockTransactionServiceInterfaceMockRecorder) GetHold(ctx, holdID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHold", reflect.TypeOf((*MockTransactionServiceInterface)(nil).GetHold), ctx, holdID)
+}
+
+// GetLimitBreaches mocks base method.
+func (m *MockTransactionServiceInterface) GetLimitBreaches(ctx context.Context, accountID string) ([]models.LimitBreach, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLimitBreaches", ctx, accountID)
+	ret0, _ := ret[0].([]models.LimitBreach)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLimitBreaches indicates an expected call of GetLimitBreaches.
+func (mr *MockTransactionServiceInterfaceMockRecorder) GetLimitBreaches(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLimitBreaches", reflect.TypeOf((*MockTransactionServiceInterface)(nil).GetLimitBreaches), ctx, accountID)
+}
+
+// GetStatement mocks base method.
+func (m *MockTransactionServiceInterface) GetStatement(ctx context.Context, accountID string, from, to time.Time) (*models.Statement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatement", ctx, accountID, from, to)
+	ret0, _ := ret[0].(*models.Statement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatement indicates an expected call of GetStatement.
+func (mr *MockTransactionServiceInterfaceMockRecorder) GetStatement(ctx, accountID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatement", reflect.TypeOf((*MockTransactionServiceInterface)(nil).GetStatement), ctx, accountID, from, to)
+}
+
+// GetTransactionByID mocks base method.
+func (m *MockTransactionServiceInterface) GetTransactionByID(ctx context.Context, transactionID string) (*models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionByID", ctx, transactionID)
+	ret0, _ := ret[0].(*models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionByID indicates an expected call of GetTransactionByID.
+func (mr *MockTransactionServiceInterfaceMockRecorder) GetTransactionByID(ctx, transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByID", reflect.TypeOf((*MockTransactionServiceInterface)(nil).GetTransactionByID), ctx, transactionID)
+}
+
+// GetTransactionHistory mocks base method.
+func (m *MockTransactionServiceInterface) GetTransactionHistory(ctx context.Context, accountID string, page, limit int) ([]models.Transaction, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionHistory", ctx, accountID, page, limit)
+	ret0, _ := ret[0].([]models.Transaction)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTransactionHistory indicates an expected call of GetTransactionHistory.
+func (mr *MockTransactionServiceInterfaceMockRecorder) GetTransactionHistory(ctx, accountID, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionHistory", reflect.TypeOf((*MockTransactionServiceInterface)(nil).GetTransactionHistory), ctx, accountID, page, limit)
+}
+
+// GetTransfer mocks base method.
+func (m *MockTransactionServiceInterface) GetTransfer(ctx context.Context, transferID string) (*models.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransfer", ctx, transferID)
+	ret0, _ := ret[0].(*models.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransfer indicates an expected call of GetTransfer.
+func (mr *MockTransactionServiceInterfaceMockRecorder) GetTransfer(ctx, transferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockTransactionServiceInterface)(nil).GetTransfer), ctx, transferID)
+}
+
+// ListTransactions mocks base method.
+func (m *MockTransactionServiceInterface) ListTransactions(ctx context.Context, accountID string, filter *models.TransactionFilter, cursor string, limit int) (*models.TransactionPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactions", ctx, accountID, filter, cursor, limit)
+	ret0, _ := ret[0].(*models.TransactionPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransactions indicates an expected call of ListTransactions.
+func (mr *MockTransactionServiceInterfaceMockRecorder) ListTransactions(ctx, accountID, filter, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactions", reflect.TypeOf((*MockTransactionServiceInterface)(nil).ListTransactions), ctx, accountID, filter, cursor, limit)
+}
+
+// PlaceHold mocks base method.
+func (m *MockTransactionServiceInterface) PlaceHold(ctx context.Context, accountID string, req *models.CreateHoldRequest) (*models.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlaceHold", ctx, accountID, req)
+	ret0, _ := ret[0].(*models.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceHold indicates an expected call of PlaceHold.
+func (mr *MockTransactionServiceInterfaceMockRecorder) PlaceHold(ctx, accountID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceHold", reflect.TypeOf((*MockTransactionServiceInterface)(nil).PlaceHold), ctx, accountID, req)
+}
+
+// ProcessTransaction mocks base method.
+func (m *MockTransactionServiceInterface) ProcessTransaction(ctx context.Context, accountID string, req *models.TransactionRequest) (*models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessTransaction", ctx, accountID, req)
+	ret0, _ := ret[0].(*models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessTransaction indicates an expected call of ProcessTransaction.
+func (mr *MockTransactionServiceInterfaceMockRecorder) ProcessTransaction(ctx, accountID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessTransaction", reflect.TypeOf((*MockTransactionServiceInterface)(nil).ProcessTransaction), ctx, accountID, req)
+}
+
+// ProcessTransactionAsync mocks base method.
+func (m *MockTransactionServiceInterface) ProcessTransactionAsync(ctx context.Context, transactionID string, req *models.TransactionRequest) (*models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessTransactionAsync", ctx, transactionID, req)
+	ret0, _ := ret[0].(*models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessTransactionAsync indicates an expected call of ProcessTransactionAsync.
+func (mr *MockTransactionServiceInterfaceMockRecorder) ProcessTransactionAsync(ctx, transactionID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessTransactionAsync", reflect.TypeOf((*MockTransactionServiceInterface)(nil).ProcessTransactionAsync), ctx, transactionID, req)
+}
+
+// ProcessTransfer mocks base method.
+func (m *MockTransactionServiceInterface) ProcessTransfer(ctx context.Context, req *models.TransferRequest) (*models.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessTransfer", ctx, req)
+	ret0, _ := ret[0].(*models.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessTransfer indicates an expected call of ProcessTransfer.
+func (mr *MockTransactionServiceInterfaceMockRecorder) ProcessTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessTransfer", reflect.TypeOf((*MockTransactionServiceInterface)(nil).ProcessTransfer), ctx, req)
+}
+
+// ProcessTransferAsync mocks base method.
+func (m *MockTransactionServiceInterface) ProcessTransferAsync(ctx context.Context, transferID string, req *models.TransferRequest) (*models.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessTransferAsync", ctx, transferID, req)
+	ret0, _ := ret[0].(*models.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessTransferAsync indicates an expected call of ProcessTransferAsync.
+func (mr *MockTransactionServiceInterfaceMockRecorder) ProcessTransferAsync(ctx, transferID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessTransferAsync", reflect.TypeOf((*MockTransactionServiceInterface)(nil).ProcessTransferAsync), ctx, transferID, req)
+}
+
+// ReleaseHold mocks base method.
+func (m *MockTransactionServiceInterface) ReleaseHold(ctx context.Context, holdID string) (*models.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseHold", ctx, holdID)
+	ret0, _ := ret[0].(*models.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseHold indicates an expected call of ReleaseHold.
+func (mr *MockTransactionServiceInterfaceMockRecorder) ReleaseHold(ctx, holdID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseHold", reflect.TypeOf((*MockTransactionServiceInterface)(nil).ReleaseHold), ctx, holdID)
+}
+
+// ReverseTransaction mocks base method.
+func (m *MockTransactionServiceInterface) ReverseTransaction(ctx context.Context, transactionID string, req *models.ReverseTransactionRequest) (*models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReverseTransaction", ctx, transactionID, req)
+	ret0, _ := ret[0].(*models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReverseTransaction indicates an expected call of ReverseTransaction.
+func (mr *MockTransactionServiceInterfaceMockRecorder) ReverseTransaction(ctx, transactionID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverseTransaction", reflect.TypeOf((*MockTransactionServiceInterface)(nil).ReverseTransaction), ctx, transactionID, req)
+}
+
+// SetAccountLimits mocks base method.
+func (m *MockTransactionServiceInterface) SetAccountLimits(ctx context.Context, accountID string, overrides *models.LimitOverrides) (*models.AccountLimits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAccountLimits", ctx, accountID, overrides)
+	ret0, _ := ret[0].(*models.AccountLimits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetAccountLimits indicates an expected call of SetAccountLimits.
+func (mr *MockTransactionServiceInterfaceMockRecorder) SetAccountLimits(ctx, accountID, overrides any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccountLimits", reflect.TypeOf((*MockTransactionServiceInterface)(nil).SetAccountLimits), ctx, accountID, overrides)
+}
+
+// UpdateTransactionStatus mocks base method.
+func (m *MockTransactionServiceInterface) UpdateTransactionStatus(ctx context.Context, transactionID, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTransactionStatus", ctx, transactionID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTransactionStatus indicates an expected call of UpdateTransactionStatus.
+func (mr *MockTransactionServiceInterfaceMockRecorder) UpdateTransactionStatus(ctx, transactionID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransactionStatus", reflect.TypeOf((*MockTransactionServiceInterface)(nil).UpdateTransactionStatus), ctx, transactionID, status)
+}
+
+// UpdateTransactionStatusWithError mocks base method.
+func (m *MockTransactionServiceInterface) UpdateTransactionStatusWithError(ctx context.Context, transactionID, status, errorMessage string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTransactionStatusWithError", ctx, transactionID, status, errorMessage)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTransactionStatusWithError indicates an expected call of UpdateTransactionStatusWithError.
+func (mr *MockTransactionServiceInterfaceMockRecorder) UpdateTransactionStatusWithError(ctx, transactionID, status, errorMessage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransactionStatusWithError", reflect.TypeOf((*MockTransactionServiceInterface)(nil).UpdateTransactionStatusWithError), ctx, transactionID, status, errorMessage)
+}
+
+// VerifyTransactionChain mocks base method.
+func (m *MockTransactionServiceInterface) VerifyTransactionChain(ctx context.Context, accountID string) (*models.ChainVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyTransactionChain", ctx, accountID)
+	ret0, _ := ret[0].(*models.ChainVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyTransactionChain indicates an expected call of VerifyTransactionChain.
+func (mr *MockTransactionServiceInterfaceMockRecorder) VerifyTransactionChain(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyTransactionChain", reflect.TypeOf((*MockTransactionServiceInterface)(nil).VerifyTransactionChain), ctx, accountID)
+}
+
+// MockIdempotencyServiceInterface is a mock of IdempotencyServiceInterface interface.
+type MockIdempotencyServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockIdempotencyServiceInterfaceMockRecorder is the mock recorder for MockIdempotencyServiceInterface.
+type MockIdempotencyServiceInterfaceMockRecorder struct {
+	mock *MockIdempotencyServiceInterface
+}
+
+// NewMockIdempotencyServiceInterface creates a new mock instance.
+func NewMockIdempotencyServiceInterface(ctrl *gomock.Controller) *MockIdempotencyServiceInterface {
+	mock := &MockIdempotencyServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyServiceInterface) EXPECT() *MockIdempotencyServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// AppliedTransaction mocks base method.
+func (m *MockIdempotencyServiceInterface) AppliedTransaction(ctx context.Context, transactionID string) (*models.Transaction, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppliedTransaction", ctx, transactionID)
+	ret0, _ := ret[0].(*models.Transaction)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AppliedTransaction indicates an expected call of AppliedTransaction.
+func (mr *MockIdempotencyServiceInterfaceMockRecorder) AppliedTransaction(ctx, transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppliedTransaction", reflect.TypeOf((*MockIdempotencyServiceInterface)(nil).AppliedTransaction), ctx, transactionID)
+}
+
+// BeginRequest mocks base method.
+func (m *MockIdempotencyServiceInterface) BeginRequest(ctx context.Context, accountID, key string, req *models.TransactionRequest) (*models.IdempotencyRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginRequest", ctx, accountID, key, req)
+	ret0, _ := ret[0].(*models.IdempotencyRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginRequest indicates an expected call of BeginRequest.
+func (mr *MockIdempotencyServiceInterfaceMockRecorder) BeginRequest(ctx, accountID, key, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginRequest", reflect.TypeOf((*MockIdempotencyServiceInterface)(nil).BeginRequest), ctx, accountID, key, req)
+}
+
+// CompleteRequest mocks base method.
+func (m *MockIdempotencyServiceInterface) CompleteRequest(ctx context.Context, accountID, key string, statusCode int, responseBody []byte, transactionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteRequest", ctx, accountID, key, statusCode, responseBody, transactionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteRequest indicates an expected call of CompleteRequest.
+func (mr *MockIdempotencyServiceInterfaceMockRecorder) CompleteRequest(ctx, accountID, key, statusCode, responseBody, transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteRequest", reflect.TypeOf((*MockIdempotencyServiceInterface)(nil).CompleteRequest), ctx, accountID, key, statusCode, responseBody, transactionID)
+}
+
+// ReleaseRequest mocks base method.
+func (m *MockIdempotencyServiceInterface) ReleaseRequest(ctx context.Context, accountID, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseRequest", ctx, accountID, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseRequest indicates an expected call of ReleaseRequest.
+func (mr *MockIdempotencyServiceInterfaceMockRecorder) ReleaseRequest(ctx, accountID, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseRequest", reflect.TypeOf((*MockIdempotencyServiceInterface)(nil).ReleaseRequest), ctx, accountID, key)
+}
+
+// MockReaperServiceInterface is a mock of ReaperServiceInterface interface.
+type MockReaperServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockReaperServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockReaperServiceInterfaceMockRecorder is the mock recorder for MockReaperServiceInterface.
+type MockReaperServiceInterfaceMockRecorder struct {
+	mock *MockReaperServiceInterface
+}
+
+// NewMockReaperServiceInterface creates a new mock instance.
+func NewMockReaperServiceInterface(ctrl *gomock.Controller) *MockReaperServiceInterface {
+	mock := &MockReaperServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockReaperServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReaperServiceInterface) EXPECT() *MockReaperServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Stats mocks base method.
+func (m *MockReaperServiceInterface) Stats(ctx context.Context) (*models.ReaperStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats", ctx)
+	ret0, _ := ret[0].(*models.ReaperStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockReaperServiceInterfaceMockRecorder) Stats(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockReaperServiceInterface)(nil).Stats), ctx)
+}
+
+// MockReconciliationServiceInterface is a mock of ReconciliationServiceInterface interface.
+type MockReconciliationServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockReconciliationServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockReconciliationServiceInterfaceMockRecorder is the mock recorder for MockReconciliationServiceInterface.
+type MockReconciliationServiceInterfaceMockRecorder struct {
+	mock *MockReconciliationServiceInterface
+}
+
+// NewMockReconciliationServiceInterface creates a new mock instance.
+func NewMockReconciliationServiceInterface(ctrl *gomock.Controller) *MockReconciliationServiceInterface {
+	mock := &MockReconciliationServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockReconciliationServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReconciliationServiceInterface) EXPECT() *MockReconciliationServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// LastReport mocks base method.
+func (m *MockReconciliationServiceInterface) LastReport(ctx context.Context) (*models.ReconciliationReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastReport", ctx)
+	ret0, _ := ret[0].(*models.ReconciliationReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastReport indicates an expected call of LastReport.
+func (mr *MockReconciliationServiceInterfaceMockRecorder) LastReport(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastReport", reflect.TypeOf((*MockReconciliationServiceInterface)(nil).LastReport), ctx)
+}
+
+// Reconcile mocks base method.
+func (m *MockReconciliationServiceInterface) Reconcile(ctx context.Context, opts models.ReconcileOptions) (*models.ReconciliationReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reconcile", ctx, opts)
+	ret0, _ := ret[0].(*models.ReconciliationReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reconcile indicates an expected call of Reconcile.
+func (mr *MockReconciliationServiceInterfaceMockRecorder) Reconcile(ctx, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*MockReconciliationServiceInterface)(nil).Reconcile), ctx, opts)
+}
+
+// MockDeadLetterServiceInterface is a mock of DeadLetterServiceInterface interface.
+type MockDeadLetterServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLetterServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockDeadLetterServiceInterfaceMockRecorder is the mock recorder for MockDeadLetterServiceInterface.
+type MockDeadLetterServiceInterfaceMockRecorder struct {
+	mock *MockDeadLetterServiceInterface
+}
+
+// NewMockDeadLetterServiceInterface creates a new mock instance.
+func NewMockDeadLetterServiceInterface(ctrl *gomock.Controller) *MockDeadLetterServiceInterface {
+	mock := &MockDeadLetterServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockDeadLetterServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetterServiceInterface) EXPECT() *MockDeadLetterServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DiscardDeadLetter mocks base method.
+func (m *MockDeadLetterServiceInterface) DiscardDeadLetter(ctx context.Context, id string) (*models.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiscardDeadLetter", ctx, id)
+	ret0, _ := ret[0].(*models.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiscardDeadLetter indicates an expected call of DiscardDeadLetter.
+func (mr *MockDeadLetterServiceInterfaceMockRecorder) DiscardDeadLetter(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscardDeadLetter", reflect.TypeOf((*MockDeadLetterServiceInterface)(nil).DiscardDeadLetter), ctx, id)
+}
+
+// GetDeadLetter mocks base method.
+func (m *MockDeadLetterServiceInterface) GetDeadLetter(ctx context.Context, id string) (*models.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadLetter", ctx, id)
+	ret0, _ := ret[0].(*models.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeadLetter indicates an expected call of GetDeadLetter.
+func (mr *MockDeadLetterServiceInterfaceMockRecorder) GetDeadLetter(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLetter", reflect.TypeOf((*MockDeadLetterServiceInterface)(nil).GetDeadLetter), ctx, id)
+}
+
+// ListDeadLetters mocks base method.
+func (m *MockDeadLetterServiceInterface) ListDeadLetters(ctx context.Context, status string, page, limit int) ([]models.DeadLetter, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeadLetters", ctx, status, page, limit)
+	ret0, _ := ret[0].([]models.DeadLetter)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDeadLetters indicates an expected call of ListDeadLetters.
+func (mr *MockDeadLetterServiceInterfaceMockRecorder) ListDeadLetters(ctx, status, page, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByID", reflect.TypeOf((*MockAccountStorage)(nil).GetAccountByID), ctx, accountID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeadLetters", reflect.TypeOf((*MockDeadLetterServiceInterface)(nil).ListDeadLetters), ctx, status, page, limit)
 }
 
-// UpdateBalance mocks base method.
-func (m *MockAccountStorage) UpdateBalance(ctx context.Context, accountID string, newBalance float64) error {
+// ReplayDeadLetter mocks base method.
+func (m *MockDeadLetterServiceInterface) ReplayDeadLetter(ctx context.Context, id string) (*models.DeadLetter, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateBalance", ctx, accountID, newBalance)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "ReplayDeadLetter", ctx, id)
+	ret0, _ := ret[0].(*models.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// UpdateBalance indicates an expected call of UpdateBalance.
-func (mr *MockAccountStorageMockRecorder) UpdateBalance(ctx, accountID, newBalance any) *gomock.Call {
+// ReplayDeadLetter indicates an expected call of ReplayDeadLetter.
+func (mr *MockDeadLetterServiceInterfaceMockRecorder) ReplayDeadLetter(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBalance", reflect.TypeOf((*MockAccountStorage)(nil).UpdateBalance), ctx, accountID, newBalance)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayDeadLetter", reflect.TypeOf((*MockDeadLetterServiceInterface)(nil).ReplayDeadLetter), ctx, id)
 }
 
-// MockTransactionStorage is a mock of TransactionStorage interface.
-type MockTransactionStorage struct {
+// MockScheduleServiceInterface is a mock of ScheduleServiceInterface interface.
+type MockScheduleServiceInterface struct {
 	ctrl     *gomock.Controller
-	recorder *MockTransactionStorageMockRecorder
+	recorder *MockScheduleServiceInterfaceMockRecorder
 	isgomock struct{}
 }
 
-// MockTransactionStorageMockRecorder is the mock recorder for MockTransactionStorage.
-type MockTransactionStorageMockRecorder struct {
-	mock *MockTransactionStorage
+// MockScheduleServiceInterfaceMockRecorder is the mock recorder for MockScheduleServiceInterface.
+type MockScheduleServiceInterfaceMockRecorder struct {
+	mock *MockScheduleServiceInterface
 }
 
-// NewMockTransactionStorage creates a new mock instance.
-func NewMockTransactionStorage(ctrl *gomock.Controller) *MockTransactionStorage {
-	mock := &MockTransactionStorage{ctrl: ctrl}
-	mock.recorder = &MockTransactionStorageMockRecorder{mock}
+// NewMockScheduleServiceInterface creates a new mock instance.
+func NewMockScheduleServiceInterface(ctrl *gomock.Controller) *MockScheduleServiceInterface {
+	mock := &MockScheduleServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockScheduleServiceInterfaceMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockTransactionStorage) EXPECT() *MockTransactionStorageMockRecorder {
+func (m *MockScheduleServiceInterface) EXPECT() *MockScheduleServiceInterfaceMockRecorder {
 	return m.recorder
 }
 
-// CreateTransaction mocks base method.
-func (m *MockTransactionStorage) CreateTransaction(ctx context.Context, transaction *models.Transaction) error {
+// CancelSchedule mocks base method.
+func (m *MockScheduleServiceInterface) CancelSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTransaction", ctx, transaction)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "CancelSchedule", ctx, scheduleID)
+	ret0, _ := ret[0].(*models.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// CreateTransaction indicates an expected call of CreateTransaction.
-func (mr *MockTransactionStorageMockRecorder) CreateTransaction(ctx, transaction any) *gomock.Call {
+// CancelSchedule indicates an expected call of CancelSchedule.
+func (mr *MockScheduleServiceInterfaceMockRecorder) CancelSchedule(ctx, scheduleID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockTransactionStorage)(nil).CreateTransaction), ctx, transaction)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSchedule", reflect.TypeOf((*MockScheduleServiceInterface)(nil).CancelSchedule), ctx, scheduleID)
 }
 
-// GetTransactionByID mocks base method.
-func (m *MockTransactionStorage) GetTransactionByID(ctx context.Context, transactionID string) (*models.Transaction, error) {
+// CreateSchedule mocks base method.
+func (m *MockScheduleServiceInterface) CreateSchedule(ctx context.Context, accountID string, req *models.CreateScheduleRequest) (*models.Schedule, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactionByID", ctx, transactionID)
-	ret0, _ := ret[0].(*models.Transaction)
+	ret := m.ctrl.Call(m, "CreateSchedule", ctx, accountID, req)
+	ret0, _ := ret[0].(*models.Schedule)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTransactionByID indicates an expected call of GetTransactionByID.
-func (mr *MockTransactionStorageMockRecorder) GetTransactionByID(ctx, transactionID any) *gomock.Call {
+// CreateSchedule indicates an expected call of CreateSchedule.
+func (mr *MockScheduleServiceInterfaceMockRecorder) CreateSchedule(ctx, accountID, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByID", reflect.TypeOf((*MockTransactionStorage)(nil).GetTransactionByID), ctx, transactionID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSchedule", reflect.TypeOf((*MockScheduleServiceInterface)(nil).CreateSchedule), ctx, accountID, req)
 }
 
-// GetTransactionsByAccountID mocks base method.
-func (m *MockTransactionStorage) GetTransactionsByAccountID(ctx context.Context, accountID string, page, limit int) ([]models.Transaction, int64, error) {
+// GetAccountSchedules mocks base method.
+func (m *MockScheduleServiceInterface) GetAccountSchedules(ctx context.Context, accountID string) ([]models.Schedule, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactionsByAccountID", ctx, accountID, page, limit)
-	ret0, _ := ret[0].([]models.Transaction)
-	ret1, _ := ret[1].(int64)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret := m.ctrl.Call(m, "GetAccountSchedules", ctx, accountID)
+	ret0, _ := ret[0].([]models.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// GetTransactionsByAccountID indicates an expected call of GetTransactionsByAccountID.
-func (mr *MockTransactionStorageMockRecorder) GetTransactionsByAccountID(ctx, accountID, page, limit any) *gomock.Call {
+// GetAccountSchedules indicates an expected call of GetAccountSchedules.
+func (mr *MockScheduleServiceInterfaceMockRecorder) GetAccountSchedules(ctx, accountID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByAccountID", reflect.TypeOf((*MockTransactionStorage)(nil).GetTransactionsByAccountID), ctx, accountID, page, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountSchedules", reflect.TypeOf((*MockScheduleServiceInterface)(nil).GetAccountSchedules), ctx, accountID)
 }
 
-// UpdateTransaction mocks base method.
-func (m *MockTransactionStorage) UpdateTransaction(ctx context.Context, transaction *models.Transaction) error {
+// GetSchedule mocks base method.
+func (m *MockScheduleServiceInterface) GetSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTransaction", ctx, transaction)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetSchedule", ctx, scheduleID)
+	ret0, _ := ret[0].(*models.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// UpdateTransaction indicates an expected call of UpdateTransaction.
-func (mr *MockTransactionStorageMockRecorder) UpdateTransaction(ctx, transaction any) *gomock.Call {
+// GetSchedule indicates an expected call of GetSchedule.
+func (mr *MockScheduleServiceInterfaceMockRecorder) GetSchedule(ctx, scheduleID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransaction", reflect.TypeOf((*MockTransactionStorage)(nil).UpdateTransaction), ctx, transaction)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchedule", reflect.TypeOf((*MockScheduleServiceInterface)(nil).GetSchedule), ctx, scheduleID)
 }
 
-// UpdateTransactionStatus mocks base method.
-func (m *MockTransactionStorage) UpdateTransactionStatus(ctx context.Context, transactionID, status string) error {
+// PauseSchedule mocks base method.
+func (m *MockScheduleServiceInterface) PauseSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTransactionStatus", ctx, transactionID, status)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "PauseSchedule", ctx, scheduleID)
+	ret0, _ := ret[0].(*models.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// UpdateTransactionStatus indicates an expected call of UpdateTransactionStatus.
-func (mr *MockTransactionStorageMockRecorder) UpdateTransactionStatus(ctx, transactionID, status any) *gomock.Call {
+// PauseSchedule indicates an expected call of PauseSchedule.
+func (mr *MockScheduleServiceInterfaceMockRecorder) PauseSchedule(ctx, scheduleID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransactionStatus", reflect.TypeOf((*MockTransactionStorage)(nil).UpdateTransactionStatus), ctx, transactionID, status)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseSchedule", reflect.TypeOf((*MockScheduleServiceInterface)(nil).PauseSchedule), ctx, scheduleID)
 }
 
-// UpdateTransactionStatusWithError mocks base method.
-func (m *MockTransactionStorage) UpdateTransactionStatusWithError(ctx context.Context, transactionID, status, errorMessage string) error {
+// ResumeSchedule mocks base method.
+func (m *MockScheduleServiceInterface) ResumeSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTransactionStatusWithError", ctx, transactionID, status, errorMessage)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "ResumeSchedule", ctx, scheduleID)
+	ret0, _ := ret[0].(*models.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// UpdateTransactionStatusWithError indicates an expected call of UpdateTransactionStatusWithError.
-func (mr *MockTransactionStorageMockRecorder) UpdateTransactionStatusWithError(ctx, transactionID, status, errorMessage any) *gomock.Call {
+// ResumeSchedule indicates an expected call of ResumeSchedule.
+func (mr *MockScheduleServiceInterfaceMockRecorder) ResumeSchedule(ctx, scheduleID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransactionStatusWithError", reflect.TypeOf((*MockTransactionStorage)(nil).UpdateTransactionStatusWithError), ctx, transactionID, status, errorMessage)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeSchedule", reflect.TypeOf((*MockScheduleServiceInterface)(nil).ResumeSchedule), ctx, scheduleID)
 }
 
-// MockAccountServiceInterface is a mock of AccountServiceInterface interface.
-type MockAccountServiceInterface struct {
+// SkipSchedule mocks base method.
+func (m *MockScheduleServiceInterface) SkipSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SkipSchedule", ctx, scheduleID)
+	ret0, _ := ret[0].(*models.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SkipSchedule indicates an expected call of SkipSchedule.
+func (mr *MockScheduleServiceInterfaceMockRecorder) SkipSchedule(ctx, scheduleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SkipSchedule", reflect.TypeOf((*MockScheduleServiceInterface)(nil).SkipSchedule), ctx, scheduleID)
+}
+
+// UpdateSchedule mocks base method.
+func (m *MockScheduleServiceInterface) UpdateSchedule(ctx context.Context, scheduleID string, req *models.UpdateScheduleRequest) (*models.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSchedule", ctx, scheduleID, req)
+	ret0, _ := ret[0].(*models.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSchedule indicates an expected call of UpdateSchedule.
+func (mr *MockScheduleServiceInterfaceMockRecorder) UpdateSchedule(ctx, scheduleID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSchedule", reflect.TypeOf((*MockScheduleServiceInterface)(nil).UpdateSchedule), ctx, scheduleID, req)
+}
+
+// MockWebhookServiceInterface is a mock of WebhookServiceInterface interface.
+type MockWebhookServiceInterface struct {
 	ctrl     *gomock.Controller
-	recorder *MockAccountServiceInterfaceMockRecorder
+	recorder *MockWebhookServiceInterfaceMockRecorder
 	isgomock struct{}
 }
 
-// MockAccountServiceInterfaceMockRecorder is the mock recorder for MockAccountServiceInterface.
-type MockAccountServiceInterfaceMockRecorder struct {
-	mock *MockAccountServiceInterface
+// MockWebhookServiceInterfaceMockRecorder is the mock recorder for MockWebhookServiceInterface.
+type MockWebhookServiceInterfaceMockRecorder struct {
+	mock *MockWebhookServiceInterface
 }
 
-// NewMockAccountServiceInterface creates a new mock instance.
-func NewMockAccountServiceInterface(ctrl *gomock.Controller) *MockAccountServiceInterface {
-	mock := &MockAccountServiceInterface{ctrl: ctrl}
-	mock.recorder = &MockAccountServiceInterfaceMockRecorder{mock}
+// NewMockWebhookServiceInterface creates a new mock instance.
+func NewMockWebhookServiceInterface(ctrl *gomock.Controller) *MockWebhookServiceInterface {
+	mock := &MockWebhookServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockWebhookServiceInterfaceMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockAccountServiceInterface) EXPECT() *MockAccountServiceInterfaceMockRecorder {
+func (m *MockWebhookServiceInterface) EXPECT() *MockWebhookServiceInterfaceMockRecorder {
 	return m.recorder
 }
 
-// CreateAccount mocks base method.
-func (m *MockAccountServiceInterface) CreateAccount(ctx context.Context, req *models.CreateAccountRequest) (*models.Account, error) {
+// CreateWebhook mocks base method.
+func (m *MockWebhookServiceInterface) CreateWebhook(ctx context.Context, accountID string, req *models.CreateWebhookRequest) (*models.WebhookSubscription, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAccount", ctx, req)
-	ret0, _ := ret[0].(*models.Account)
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, accountID, req)
+	ret0, _ := ret[0].(*models.WebhookSubscription)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateAccount indicates an expected call of CreateAccount.
-func (mr *MockAccountServiceInterfaceMockRecorder) CreateAccount(ctx, req any) *gomock.Call {
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockWebhookServiceInterfaceMockRecorder) CreateWebhook(ctx, accountID, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockAccountServiceInterface)(nil).CreateAccount), ctx, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockWebhookServiceInterface)(nil).CreateWebhook), ctx, accountID, req)
 }
 
-// GetAccountBalance mocks base method.
-func (m *MockAccountServiceInterface) GetAccountBalance(ctx context.Context, accountID string) (float64, error) {
+// DeleteWebhook mocks base method.
+func (m *MockWebhookServiceInterface) DeleteWebhook(ctx context.Context, webhookID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountBalance", ctx, accountID)
-	ret0, _ := ret[0].(float64)
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, webhookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWebhookServiceInterfaceMockRecorder) DeleteWebhook(ctx, webhookID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhookServiceInterface)(nil).DeleteWebhook), ctx, webhookID)
+}
+
+// GetDelivery mocks base method.
+func (m *MockWebhookServiceInterface) GetDelivery(ctx context.Context, webhookID, deliveryID string) (*models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDelivery", ctx, webhookID, deliveryID)
+	ret0, _ := ret[0].(*models.WebhookDelivery)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccountBalance indicates an expected call of GetAccountBalance.
-func (mr *MockAccountServiceInterfaceMockRecorder) GetAccountBalance(ctx, accountID any) *gomock.Call {
+// GetDelivery indicates an expected call of GetDelivery.
+func (mr *MockWebhookServiceInterfaceMockRecorder) GetDelivery(ctx, webhookID, deliveryID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountBalance", reflect.TypeOf((*MockAccountServiceInterface)(nil).GetAccountBalance), ctx, accountID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDelivery", reflect.TypeOf((*MockWebhookServiceInterface)(nil).GetDelivery), ctx, webhookID, deliveryID)
 }
 
-// GetAccountByID mocks base method.
-func (m *MockAccountServiceInterface) GetAccountByID(ctx context.Context, accountID string) (*models.Account, error) {
+// GetWebhook mocks base method.
+func (m *MockWebhookServiceInterface) GetWebhook(ctx context.Context, webhookID string) (*models.WebhookSubscription, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountByID", ctx, accountID)
-	ret0, _ := ret[0].(*models.Account)
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, webhookID)
+	ret0, _ := ret[0].(*models.WebhookSubscription)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccountByID indicates an expected call of GetAccountByID.
-func (mr *MockAccountServiceInterfaceMockRecorder) GetAccountByID(ctx, accountID any) *gomock.Call {
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockWebhookServiceInterfaceMockRecorder) GetWebhook(ctx, webhookID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByID", reflect.TypeOf((*MockAccountServiceInterface)(nil).GetAccountByID), ctx, accountID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockWebhookServiceInterface)(nil).GetWebhook), ctx, webhookID)
 }
 
-// MockTransactionServiceInterface is a mock of TransactionServiceInterface interface.
-type MockTransactionServiceInterface struct {
+// GetWebhooks mocks base method.
+func (m *MockWebhookServiceInterface) GetWebhooks(ctx context.Context, accountID string) ([]models.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks", ctx, accountID)
+	ret0, _ := ret[0].([]models.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockWebhookServiceInterfaceMockRecorder) GetWebhooks(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockWebhookServiceInterface)(nil).GetWebhooks), ctx, accountID)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockWebhookServiceInterface) ListDeliveries(ctx context.Context, webhookID, status string, page, limit int) ([]models.WebhookDelivery, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", ctx, webhookID, status, page, limit)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockWebhookServiceInterfaceMockRecorder) ListDeliveries(ctx, webhookID, status, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockWebhookServiceInterface)(nil).ListDeliveries), ctx, webhookID, status, page, limit)
+}
+
+// Redeliver mocks base method.
+func (m *MockWebhookServiceInterface) Redeliver(ctx context.Context, webhookID, deliveryID string) (*models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeliver", ctx, webhookID, deliveryID)
+	ret0, _ := ret[0].(*models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Redeliver indicates an expected call of Redeliver.
+func (mr *MockWebhookServiceInterfaceMockRecorder) Redeliver(ctx, webhookID, deliveryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeliver", reflect.TypeOf((*MockWebhookServiceInterface)(nil).Redeliver), ctx, webhookID, deliveryID)
+}
+
+// MockAccessServiceInterface is a mock of AccessServiceInterface interface.
+type MockAccessServiceInterface struct {
 	ctrl     *gomock.Controller
-	recorder *MockTransactionServiceInterfaceMockRecorder
+	recorder *MockAccessServiceInterfaceMockRecorder
 	isgomock struct{}
 }
 
-// MockTransactionServiceInterfaceMockRecorder is the mock recorder for MockTransactionServiceInterface.
-type MockTransactionServiceInterfaceMockRecorder struct {
-	mock *MockTransactionServiceInterface
+// MockAccessServiceInterfaceMockRecorder is the mock recorder for MockAccessServiceInterface.
+type MockAccessServiceInterfaceMockRecorder struct {
+	mock *MockAccessServiceInterface
 }
 
-// NewMockTransactionServiceInterface creates a new mock instance.
-func NewMockTransactionServiceInterface(ctrl *gomock.Controller) *MockTransactionServiceInterface {
-	mock := &MockTransactionServiceInterface{ctrl: ctrl}
-	mock.recorder = &MockTransactionServiceInterfaceMockRecorder{mock}
+// NewMockAccessServiceInterface creates a new mock instance.
+func NewMockAccessServiceInterface(ctrl *gomock.Controller) *MockAccessServiceInterface {
+	mock := &MockAccessServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockAccessServiceInterfaceMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockTransactionServiceInterface) EXPECT() *MockTransactionServiceInterfaceMockRecorder {
+func (m *MockAccessServiceInterface) EXPECT() *MockAccessServiceInterfaceMockRecorder {
 	return m.recorder
 }
 
-// CreateInitialTransaction mocks base method.
-func (m *MockTransactionServiceInterface) CreateInitialTransaction(ctx context.Context, accountID string, initialBalance float64) error {
+// CheckAccount mocks base method.
+func (m *MockAccessServiceInterface) CheckAccount(ctx context.Context, accountID string, access auth.Access) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateInitialTransaction", ctx, accountID, initialBalance)
+	ret := m.ctrl.Call(m, "CheckAccount", ctx, accountID, access)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreateInitialTransaction indicates an expected call of CreateInitialTransaction.
-func (mr *MockTransactionServiceInterfaceMockRecorder) CreateInitialTransaction(ctx, accountID, initialBalance any) *gomock.Call {
+// CheckAccount indicates an expected call of CheckAccount.
+func (mr *MockAccessServiceInterfaceMockRecorder) CheckAccount(ctx, accountID, access any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInitialTransaction", reflect.TypeOf((*MockTransactionServiceInterface)(nil).CreateInitialTransaction), ctx, accountID, initialBalance)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckAccount", reflect.TypeOf((*MockAccessServiceInterface)(nil).CheckAccount), ctx, accountID, access)
 }
 
-// CreatePendingTransaction mocks base method.
-func (m *MockTransactionServiceInterface) CreatePendingTransaction(ctx context.Context, transaction *models.Transaction) error {
+// CheckHold mocks base method.
+func (m *MockAccessServiceInterface) CheckHold(ctx context.Context, holdID string, access auth.Access) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreatePendingTransaction", ctx, transaction)
+	ret := m.ctrl.Call(m, "CheckHold", ctx, holdID, access)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreatePendingTransaction indicates an expected call of CreatePendingTransaction.
-func (mr *MockTransactionServiceInterfaceMockRecorder) CreatePendingTransaction(ctx, transaction any) *gomock.Call {
+// CheckHold indicates an expected call of CheckHold.
+func (mr *MockAccessServiceInterfaceMockRecorder) CheckHold(ctx, holdID, access any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePendingTransaction", reflect.TypeOf((*MockTransactionServiceInterface)(nil).CreatePendingTransaction), ctx, transaction)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckHold", reflect.TypeOf((*MockAccessServiceInterface)(nil).CheckHold), ctx, holdID, access)
 }
 
-// GetAccountByID mocks base method.
-func (m *MockTransactionServiceInterface) GetAccountByID(ctx context.Context, accountID string) (*models.Account, error) {
+// CheckSchedule mocks base method.
+func (m *MockAccessServiceInterface) CheckSchedule(ctx context.Context, scheduleID string, access auth.Access) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountByID", ctx, accountID)
-	ret0, _ := ret[0].(*models.Account)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "CheckSchedule", ctx, scheduleID, access)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetAccountByID indicates an expected call of GetAccountByID.
-func (mr *MockTransactionServiceInterfaceMockRecorder) GetAccountByID(ctx, accountID any) *gomock.Call {
+// CheckSchedule indicates an expected call of CheckSchedule.
+func (mr *MockAccessServiceInterfaceMockRecorder) CheckSchedule(ctx, scheduleID, access any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByID", reflect.TypeOf((*MockTransactionServiceInterface)(nil).GetAccountByID), ctx, accountID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckSchedule", reflect.TypeOf((*MockAccessServiceInterface)(nil).CheckSchedule), ctx, scheduleID, access)
 }
 
-// GetTransactionByID mocks base method.
-func (m *MockTransactionServiceInterface) GetTransactionByID(ctx context.Context, transactionID string) (*models.Transaction, error) {
+// CheckTransaction mocks base method.
+func (m *MockAccessServiceInterface) CheckTransaction(ctx context.Context, transactionID string, access auth.Access) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactionByID", ctx, transactionID)
-	ret0, _ := ret[0].(*models.Transaction)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "CheckTransaction", ctx, transactionID, access)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetTransactionByID indicates an expected call of GetTransactionByID.
-func (mr *MockTransactionServiceInterfaceMockRecorder) GetTransactionByID(ctx, transactionID any) *gomock.Call {
+// CheckTransaction indicates an expected call of CheckTransaction.
+func (mr *MockAccessServiceInterfaceMockRecorder) CheckTransaction(ctx, transactionID, access any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByID", reflect.TypeOf((*MockTransactionServiceInterface)(nil).GetTransactionByID), ctx, transactionID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckTransaction", reflect.TypeOf((*MockAccessServiceInterface)(nil).CheckTransaction), ctx, transactionID, access)
 }
 
-// GetTransactionHistory mocks base method.
-func (m *MockTransactionServiceInterface) GetTransactionHistory(ctx context.Context, accountID string, page, limit int) ([]models.Transaction, int64, error) {
+// CheckTransfer mocks base method.
+func (m *MockAccessServiceInterface) CheckTransfer(ctx context.Context, transferID string, access auth.Access) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactionHistory", ctx, accountID, page, limit)
-	ret0, _ := ret[0].([]models.Transaction)
-	ret1, _ := ret[1].(int64)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret := m.ctrl.Call(m, "CheckTransfer", ctx, transferID, access)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetTransactionHistory indicates an expected call of GetTransactionHistory.
-func (mr *MockTransactionServiceInterfaceMockRecorder) GetTransactionHistory(ctx, accountID, page, limit any) *gomock.Call {
+// CheckTransfer indicates an expected call of CheckTransfer.
+func (mr *MockAccessServiceInterfaceMockRecorder) CheckTransfer(ctx, transferID, access any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionHistory", reflect.TypeOf((*MockTransactionServiceInterface)(nil).GetTransactionHistory), ctx, accountID, page, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckTransfer", reflect.TypeOf((*MockAccessServiceInterface)(nil).CheckTransfer), ctx, transferID, access)
 }
 
-// ProcessTransaction mocks base method.
-func (m *MockTransactionServiceInterface) ProcessTransaction(ctx context.Context, accountID string, req *models.TransactionRequest) (*models.Transaction, error) {
+// CheckWebhook mocks base method.
+func (m *MockAccessServiceInterface) CheckWebhook(ctx context.Context, webhookID string, access auth.Access) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ProcessTransaction", ctx, accountID, req)
-	ret0, _ := ret[0].(*models.Transaction)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "CheckWebhook", ctx, webhookID, access)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// ProcessTransaction indicates an expected call of ProcessTransaction.
-func (mr *MockTransactionServiceInterfaceMockRecorder) ProcessTransaction(ctx, accountID, req any) *gomock.Call {
+// CheckWebhook indicates an expected call of CheckWebhook.
+func (mr *MockAccessServiceInterfaceMockRecorder) CheckWebhook(ctx, webhookID, access any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessTransaction", reflect.TypeOf((*MockTransactionServiceInterface)(nil).ProcessTransaction), ctx, accountID, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckWebhook", reflect.TypeOf((*MockAccessServiceInterface)(nil).CheckWebhook), ctx, webhookID, access)
 }
 
-// ProcessTransactionAsync mocks base method.
-func (m *MockTransactionServiceInterface) ProcessTransactionAsync(ctx context.Context, transactionID string, req *models.TransactionRequest) (*models.Transaction, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ProcessTransactionAsync", ctx, transactionID, req)
-	ret0, _ := ret[0].(*models.Transaction)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+// MockAuditServiceInterface is a mock of AuditServiceInterface interface.
+type MockAuditServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditServiceInterfaceMockRecorder
+	isgomock struct{}
 }
 
-// ProcessTransactionAsync indicates an expected call of ProcessTransactionAsync.
-func (mr *MockTransactionServiceInterfaceMockRecorder) ProcessTransactionAsync(ctx, transactionID, req any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessTransactionAsync", reflect.TypeOf((*MockTransactionServiceInterface)(nil).ProcessTransactionAsync), ctx, transactionID, req)
+// MockAuditServiceInterfaceMockRecorder is the mock recorder for MockAuditServiceInterface.
+type MockAuditServiceInterfaceMockRecorder struct {
+	mock *MockAuditServiceInterface
 }
 
-// UpdateTransactionStatus mocks base method.
-func (m *MockTransactionServiceInterface) UpdateTransactionStatus(ctx context.Context, transactionID, status string) error {
+// NewMockAuditServiceInterface creates a new mock instance.
+func NewMockAuditServiceInterface(ctrl *gomock.Controller) *MockAuditServiceInterface {
+	mock := &MockAuditServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockAuditServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditServiceInterface) EXPECT() *MockAuditServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Query mocks base method.
+func (m *MockAuditServiceInterface) Query(ctx context.Context, filter *models.AuditFilter, cursor string, limit int) (*models.AuditPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTransactionStatus", ctx, transactionID, status)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "Query", ctx, filter, cursor, limit)
+	ret0, _ := ret[0].(*models.AuditPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// UpdateTransactionStatus indicates an expected call of UpdateTransactionStatus.
-func (mr *MockTransactionServiceInterfaceMockRecorder) UpdateTransactionStatus(ctx, transactionID, status any) *gomock.Call {
+// Query indicates an expected call of Query.
+func (mr *MockAuditServiceInterfaceMockRecorder) Query(ctx, filter, cursor, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransactionStatus", reflect.TypeOf((*MockTransactionServiceInterface)(nil).UpdateTransactionStatus), ctx, transactionID, status)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockAuditServiceInterface)(nil).Query), ctx, filter, cursor, limit)
 }
 
-// UpdateTransactionStatusWithError mocks base method.
-func (m *MockTransactionServiceInterface) UpdateTransactionStatusWithError(ctx context.Context, transactionID, status, errorMessage string) error {
+// Record mocks base method.
+func (m *MockAuditServiceInterface) Record(ctx context.Context, record *models.AuditRecord) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTransactionStatusWithError", ctx, transactionID, status, errorMessage)
+	ret := m.ctrl.Call(m, "Record", ctx, record)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateTransactionStatusWithError indicates an expected call of UpdateTransactionStatusWithError.
-func (mr *MockTransactionServiceInterfaceMockRecorder) UpdateTransactionStatusWithError(ctx, transactionID, status, errorMessage any) *gomock.Call {
+// Record indicates an expected call of Record.
+func (mr *MockAuditServiceInterfaceMockRecorder) Record(ctx, record any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransactionStatusWithError", reflect.TypeOf((*MockTransactionServiceInterface)(nil).UpdateTransactionStatusWithError), ctx, transactionID, status, errorMessage)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockAuditServiceInterface)(nil).Record), ctx, record)
 }
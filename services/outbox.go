@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/utils"
+)
+
+const (
+	// outboxLease is how long a claimed entry is hidden from other relays while
+	// it is being published; a relay that dies mid-publish is retried after it
+	outboxLease = 30 * time.Second
+
+	outboxBaseBackoff = time.Second
+	outboxMaxBackoff  = 5 * time.Minute
+)
+
+// OutboxService hands outbox entries to the relay and records publish outcomes
+type OutboxService struct {
+	transactionStorage TransactionStorage
+}
+
+func NewOutboxService(transactionStorage TransactionStorage) *OutboxService {
+	return &OutboxService{
+		transactionStorage: transactionStorage,
+	}
+}
+
+// ClaimDue claims up to limit outbox entries whose next attempt is due
+func (s *OutboxService) ClaimDue(ctx context.Context, limit int) ([]*models.Transaction, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "outbox"),
+		slog.String("operation", "claim_due"))
+
+	var claimed []*models.Transaction
+	for len(claimed) < limit {
+		transaction, err := s.transactionStorage.ClaimOutboxEntry(ctx, time.Now(), outboxLease)
+		if err != nil {
+			logger.Error("Failed to claim outbox entry", slog.String("error", err.Error()))
+			return claimed, err
+		}
+		if transaction == nil {
+			break
+		}
+		claimed = append(claimed, transaction)
+	}
+
+	return claimed, nil
+}
+
+// MarkDispatched records a successful publish
+func (s *OutboxService) MarkDispatched(ctx context.Context, transactionID string) error {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "outbox"),
+		slog.String("operation", "mark_dispatched"),
+		slog.String("transaction_id", transactionID))
+
+	if err := s.transactionStorage.MarkOutboxDispatched(ctx, transactionID, time.Now()); err != nil {
+		logger.Error("Failed to mark outbox entry dispatched", slog.String("error", err.Error()))
+		return err
+	}
+
+	logger.Info("Outbox entry dispatched")
+	return nil
+}
+
+// MarkFailed schedules another publish attempt with exponential backoff
+func (s *OutboxService) MarkFailed(ctx context.Context, transactionID string, attempts int, publishErr error) error {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "outbox"),
+		slog.String("operation", "mark_failed"),
+		slog.String("transaction_id", transactionID))
+
+	delay := OutboxBackoff(attempts)
+	logger.Warn("Outbox publish failed, retrying later",
+		slog.Int("attempts", attempts),
+		slog.Duration("retry_in", delay),
+		slog.String("error", publishErr.Error()))
+
+	if err := s.transactionStorage.RescheduleOutboxEntry(ctx, transactionID, time.Now().Add(delay), publishErr.Error()); err != nil {
+		logger.Error("Failed to reschedule outbox entry", slog.String("error", err.Error()))
+		return fmt.Errorf("failed to reschedule outbox entry: %w", err)
+	}
+
+	return nil
+}
+
+// OutboxBackoff returns the delay before the next publish attempt: one second
+// doubled per failed attempt, capped at five minutes
+func OutboxBackoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	delay := outboxBaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+
+	return delay
+}
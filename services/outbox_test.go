@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOutboxService_ClaimDue_StopsWhenNothingIsDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewOutboxService(mockTransactionStorage)
+
+	gomock.InOrder(
+		mockTransactionStorage.EXPECT().
+			ClaimOutboxEntry(gomock.Any(), gomock.Any(), outboxLease).
+			Return(&models.Transaction{TransactionID: "txn_1", Outbox: &models.OutboxEntry{Attempts: 1}}, nil),
+		mockTransactionStorage.EXPECT().
+			ClaimOutboxEntry(gomock.Any(), gomock.Any(), outboxLease).
+			Return(nil, nil),
+	)
+
+	claimed, err := service.ClaimDue(context.Background(), 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, claimed, 1)
+	assert.Equal(t, "txn_1", claimed[0].TransactionID)
+}
+
+func TestOutboxService_ClaimDue_RespectsLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewOutboxService(mockTransactionStorage)
+
+	mockTransactionStorage.EXPECT().
+		ClaimOutboxEntry(gomock.Any(), gomock.Any(), outboxLease).
+		Return(&models.Transaction{TransactionID: "txn_1", Outbox: &models.OutboxEntry{}}, nil).
+		Times(2)
+
+	claimed, err := service.ClaimDue(context.Background(), 2)
+
+	assert.NoError(t, err)
+	assert.Len(t, claimed, 2)
+}
+
+func TestOutboxService_MarkFailed_ReschedulesWithBackoff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewOutboxService(mockTransactionStorage)
+
+	before := time.Now()
+	mockTransactionStorage.EXPECT().
+		RescheduleOutboxEntry(gomock.Any(), "txn_1", gomock.Any(), "broker down").
+		DoAndReturn(func(ctx context.Context, transactionID string, nextAttemptAt time.Time, lastError string) error {
+			// Third attempt waits four seconds
+			assert.WithinDuration(t, before.Add(4*time.Second), nextAttemptAt, time.Second)
+			return nil
+		}).
+		Times(1)
+
+	err := service.MarkFailed(context.Background(), "txn_1", 3, errors.New("broker down"))
+
+	assert.NoError(t, err)
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, OutboxBackoff(0))
+	assert.Equal(t, time.Second, OutboxBackoff(1))
+	assert.Equal(t, 2*time.Second, OutboxBackoff(2))
+	assert.Equal(t, 64*time.Second, OutboxBackoff(7))
+	assert.Equal(t, 256*time.Second, OutboxBackoff(9))
+	assert.Equal(t, 5*time.Minute, OutboxBackoff(10))
+	assert.Equal(t, 5*time.Minute, OutboxBackoff(100))
+}
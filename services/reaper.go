@@ -0,0 +1,324 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/queue"
+	"github.com/appy29/banking-ledger-service/utils"
+)
+
+// reaperBatchSize caps how many stale records one sweep looks at
+const reaperBatchSize = 100
+
+// ReaperService settles transactions that have been pending for too long. The
+// applied_transactions table in Postgres says whether the balance change
+// happened: if it did the record is completed, otherwise the message is handed
+// back to the outbox relay, and after MaxRequeues attempts the record is failed.
+type ReaperService struct {
+	accountStorage     AccountStorage
+	transactionStorage TransactionStorage
+	maxAge             time.Duration
+	maxRequeues        int
+	notifier           EventNotifier // Told about the records it completes or fails, if set
+
+	mu    sync.Mutex
+	stats models.ReaperStats
+}
+
+func NewReaperService(accountStorage AccountStorage, transactionStorage TransactionStorage, maxAge time.Duration, maxRequeues int) *ReaperService {
+	return &ReaperService{
+		accountStorage:     accountStorage,
+		transactionStorage: transactionStorage,
+		maxAge:             maxAge,
+		maxRequeues:        maxRequeues,
+	}
+}
+
+// SetNotifier sets where webhook events for the records the reaper completes
+// or fails are sent
+func (s *ReaperService) SetNotifier(notifier EventNotifier) {
+	s.notifier = notifier
+}
+
+// reaperOutcome is what the reaper did with one stale record or transfer
+type reaperOutcome int
+
+const (
+	reaperCompleted reaperOutcome = iota
+	reaperRequeued
+	reaperFailed
+	reaperAwaitingDispatch
+)
+
+// Sweep settles one batch of pending records older than the maximum age
+func (s *ReaperService) Sweep(ctx context.Context) error {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "reaper"),
+		slog.String("operation", "sweep"))
+
+	now := time.Now()
+	cutoff := now.Add(-s.maxAge)
+
+	stale, err := s.transactionStorage.GetStalePendingTransactions(ctx, cutoff, reaperBatchSize)
+	if err != nil {
+		logger.Error("Failed to get stale pending transactions", slog.String("error", err.Error()))
+		s.record(now, 0, nil, 1)
+		return err
+	}
+
+	outcomes := make(map[reaperOutcome]int64)
+	var errorCount int64
+	seenTransfers := make(map[string]bool)
+
+	for i := range stale {
+		transaction := &stale[i]
+
+		var outcome reaperOutcome
+		var err error
+		if transaction.TransferID != "" {
+			// Both legs are settled together, once per transfer
+			if seenTransfers[transaction.TransferID] {
+				continue
+			}
+			seenTransfers[transaction.TransferID] = true
+			outcome, err = s.reapTransfer(ctx, transaction.TransferID, cutoff)
+		} else {
+			outcome, err = s.reapTransaction(ctx, transaction, cutoff)
+		}
+
+		if err != nil {
+			logger.Error("Failed to settle stale pending transaction",
+				slog.String("transaction_id", transaction.TransactionID),
+				slog.String("error", err.Error()))
+			errorCount++
+			continue
+		}
+		outcomes[outcome]++
+	}
+
+	s.record(now, int64(len(stale)), outcomes, errorCount)
+
+	if len(stale) > 0 {
+		logger.Info("Reaper sweep finished",
+			slog.Int("scanned", len(stale)),
+			slog.Int64("completed", outcomes[reaperCompleted]),
+			slog.Int64("requeued", outcomes[reaperRequeued]),
+			slog.Int64("failed", outcomes[reaperFailed]),
+			slog.Int64("awaiting_dispatch", outcomes[reaperAwaitingDispatch]),
+			slog.Int64("errors", errorCount))
+	}
+
+	return nil
+}
+
+// Stats returns the reaper's counters along with the current number of stale
+// pending records
+func (s *ReaperService) Stats(ctx context.Context) (*models.ReaperStats, error) {
+	stalePending, err := s.transactionStorage.CountStalePendingTransactions(ctx, time.Now().Add(-s.maxAge))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count stale pending transactions: %w", err)
+	}
+
+	s.mu.Lock()
+	stats := s.stats
+	s.mu.Unlock()
+
+	stats.StalePending = stalePending
+	stats.MaxAgeSeconds = int64(s.maxAge / time.Second)
+	return &stats, nil
+}
+
+// OldestPendingAge returns how long the oldest pending record has been
+// waiting, or zero when nothing is pending
+func (s *ReaperService) OldestPendingAge(ctx context.Context) (time.Duration, error) {
+	now := time.Now()
+	oldest, err := s.transactionStorage.GetStalePendingTransactions(ctx, now, 1)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get oldest pending transaction: %w", err)
+	}
+	if len(oldest) == 0 {
+		return 0, nil
+	}
+	return now.Sub(oldest[0].Timestamp), nil
+}
+
+func (s *ReaperService) reapTransaction(ctx context.Context, transaction *models.Transaction, cutoff time.Time) (reaperOutcome, error) {
+	applied, err := s.accountStorage.GetAppliedTransaction(ctx, transaction.TransactionID)
+	if err != nil {
+		return 0, err
+	}
+	if applied != nil {
+		return reaperCompleted, s.complete(ctx, transaction, applied)
+	}
+
+	if transaction.Outbox != nil && transaction.Outbox.IsInFlight(cutoff) {
+		return reaperAwaitingDispatch, nil
+	}
+
+	return s.requeueOrFail(ctx, transaction, queue.TransactionMessage{
+		ID:        transaction.TransactionID,
+		AccountID: transaction.AccountID,
+		Type:      transaction.Type,
+		Amount:    transaction.Amount,
+		Currency:  transaction.Currency,
+		Reference: transaction.Description,
+		CreatedAt: time.Now(),
+	}, []*models.Transaction{transaction})
+}
+
+func (s *ReaperService) reapTransfer(ctx context.Context, transferID string, cutoff time.Time) (reaperOutcome, error) {
+	legs, err := s.transactionStorage.GetTransactionsByTransferID(ctx, transferID)
+	if err != nil {
+		return 0, err
+	}
+
+	var debit, credit *models.Transaction
+	var pending []*models.Transaction
+	for i := range legs {
+		switch legs[i].Type {
+		case "transfer_out":
+			debit = &legs[i]
+		case "transfer_in":
+			credit = &legs[i]
+		}
+		if legs[i].Status == "pending" {
+			pending = append(pending, &legs[i])
+		}
+	}
+
+	// A transfer whose legs were not both written never had a message to
+	// publish; its balances cannot have moved
+	if debit == nil || credit == nil {
+		return reaperFailed, s.fail(ctx, pending, "reaper: transfer is missing a leg; balance unchanged")
+	}
+
+	// Both legs are recorded by the same Postgres transaction
+	applied, err := s.accountStorage.GetAppliedTransaction(ctx, debit.TransactionID)
+	if err != nil {
+		return 0, err
+	}
+	if applied != nil {
+		creditApplied, err := s.accountStorage.GetAppliedTransaction(ctx, credit.TransactionID)
+		if err != nil {
+			return 0, err
+		}
+		if creditApplied == nil {
+			return 0, fmt.Errorf("transfer %s has only its debit leg applied", transferID)
+		}
+		if err := s.complete(ctx, debit, applied); err != nil {
+			return 0, err
+		}
+		return reaperCompleted, s.complete(ctx, credit, creditApplied)
+	}
+
+	// The message rides on the credit leg
+	if credit.Outbox != nil && credit.Outbox.IsInFlight(cutoff) {
+		return reaperAwaitingDispatch, nil
+	}
+
+	return s.requeueOrFail(ctx, credit, queue.TransactionMessage{
+		ID:          transferID,
+		AccountID:   debit.AccountID,
+		ToAccountID: credit.AccountID,
+		Type:        "transfer",
+		Amount:      debit.Amount,
+		Currency:    debit.Currency,
+		Reference:   debit.Description,
+		CreatedAt:   time.Now(),
+	}, pending)
+}
+
+// complete marks a record whose balance change was applied but never written
+// back to the transaction log
+func (s *ReaperService) complete(ctx context.Context, transaction *models.Transaction, applied *models.AppliedTransaction) error {
+	if transaction.Status != "pending" {
+		return nil
+	}
+
+	completed := *transaction
+	completed.PreviousBalance = applied.PreviousBalance
+	completed.NewBalance = applied.NewBalance
+	completed.Status = "completed"
+	completed.ErrorMessage = "reaper: balance change was applied but the record was left pending"
+
+	if err := s.transactionStorage.UpdateTransaction(ctx, &completed); err != nil {
+		return err
+	}
+
+	// Transfer legs raise no transaction events, whoever settles them
+	if s.notifier != nil && completed.TransferID == "" {
+		s.notifier.TransactionCompleted(ctx, &completed)
+	}
+	return nil
+}
+
+// requeueOrFail hands the message back to the outbox relay, or fails the
+// pending records once the re-enqueue budget is spent. carrier is the record
+// that holds the outbox entry.
+func (s *ReaperService) requeueOrFail(ctx context.Context, carrier *models.Transaction, msg queue.TransactionMessage, pending []*models.Transaction) (reaperOutcome, error) {
+	requeues := 0
+	if carrier.Outbox != nil {
+		requeues = carrier.Outbox.Requeues
+	}
+
+	if requeues >= s.maxRequeues {
+		return reaperFailed, s.fail(ctx, pending,
+			fmt.Sprintf("reaper: not processed after %d re-enqueues; balance unchanged", requeues))
+	}
+
+	// Records created before the outbox existed are rebuilt from their fields
+	var entry *models.OutboxEntry
+	if carrier.Outbox != nil && len(carrier.Outbox.Payload) > 0 {
+		entry = models.NewOutboxEntry(carrier.Outbox.Payload)
+		entry.TraceParent = carrier.Outbox.TraceParent
+	} else {
+		var err error
+		entry, err = queue.NewOutboxEntry(ctx, msg)
+		if err != nil {
+			return 0, err
+		}
+	}
+	entry.Requeues = requeues + 1
+
+	reason := fmt.Sprintf("reaper: pending for over %v; re-enqueued (%d of %d)", s.maxAge, entry.Requeues, s.maxRequeues)
+	if err := s.transactionStorage.RequeueOutboxEntry(ctx, carrier.TransactionID, entry, reason); err != nil {
+		return 0, err
+	}
+
+	return reaperRequeued, nil
+}
+
+func (s *ReaperService) fail(ctx context.Context, transactions []*models.Transaction, reason string) error {
+	for _, transaction := range transactions {
+		if err := s.transactionStorage.UpdateTransactionStatusWithError(ctx, transaction.TransactionID, "failed", reason); err != nil {
+			return err
+		}
+
+		if s.notifier != nil && transaction.TransferID == "" {
+			failed := *transaction
+			failed.Status = "failed"
+			failed.ErrorMessage = reason
+			s.notifier.TransactionFailed(ctx, &failed)
+		}
+	}
+	return nil
+}
+
+func (s *ReaperService) record(runAt time.Time, scanned int64, outcomes map[reaperOutcome]int64, errorCount int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stats.Runs++
+	s.stats.LastRunAt = &runAt
+	s.stats.Scanned += scanned
+	s.stats.Completed += outcomes[reaperCompleted]
+	s.stats.Requeued += outcomes[reaperRequeued]
+	s.stats.Failed += outcomes[reaperFailed]
+	s.stats.AwaitingDispatch += outcomes[reaperAwaitingDispatch]
+	s.stats.Errors += errorCount
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func newStalePending(transactionID string, outbox *models.OutboxEntry) models.Transaction {
+	return models.Transaction{
+		ID:            transactionID,
+		TransactionID: transactionID,
+		AccountID:     "acc_12345",
+		Type:          "deposit",
+		Amount:        models.MustParseMoney("100.00"),
+		Currency:      "USD",
+		Status:        "pending",
+		Timestamp:     time.Now().Add(-time.Hour),
+		Outbox:        outbox,
+	}
+}
+
+func dispatchedOutbox(at time.Time, requeues int) *models.OutboxEntry {
+	return &models.OutboxEntry{
+		Payload:      []byte(`{"id":"txn_1"}`),
+		Dispatched:   true,
+		DispatchedAt: &at,
+		Requeues:     requeues,
+	}
+}
+
+func TestReaperService_Sweep_CompletesAppliedTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	mockNotifier := NewMockEventNotifier(ctrl)
+	service := NewReaperService(mockAccountStorage, mockTransactionStorage, 10*time.Minute, 3)
+	service.SetNotifier(mockNotifier)
+
+	stale := newStalePending("txn_1", dispatchedOutbox(time.Now().Add(-time.Hour), 0))
+
+	mockTransactionStorage.EXPECT().
+		GetStalePendingTransactions(gomock.Any(), gomock.Any(), reaperBatchSize).
+		Return([]models.Transaction{stale}, nil)
+	mockAccountStorage.EXPECT().
+		GetAppliedTransaction(gomock.Any(), "txn_1").
+		Return(&models.AppliedTransaction{
+			TransactionID:   "txn_1",
+			PreviousBalance: models.MustParseMoney("50.00"),
+			NewBalance:      models.MustParseMoney("150.00"),
+		}, nil)
+	mockTransactionStorage.EXPECT().
+		UpdateTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, transaction *models.Transaction) error {
+			assert.Equal(t, "completed", transaction.Status)
+			assert.Equal(t, models.MustParseMoney("50.00"), transaction.PreviousBalance)
+			assert.Equal(t, models.MustParseMoney("150.00"), transaction.NewBalance)
+			assert.Contains(t, transaction.ErrorMessage, "reaper")
+			return nil
+		})
+
+	// Subscribers hear about it just as if the worker had completed it
+	mockNotifier.EXPECT().
+		TransactionCompleted(gomock.Any(), gomock.Any()).
+		Do(func(ctx context.Context, transaction *models.Transaction) {
+			assert.Equal(t, "txn_1", transaction.TransactionID)
+			assert.Equal(t, "completed", transaction.Status)
+			assert.Equal(t, models.MustParseMoney("150.00"), transaction.NewBalance)
+		})
+
+	err := service.Sweep(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), service.stats.Completed)
+	assert.Equal(t, int64(1), service.stats.Scanned)
+}
+
+func TestReaperService_Sweep_RequeuesUnappliedTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewReaperService(mockAccountStorage, mockTransactionStorage, 10*time.Minute, 3)
+
+	stale := newStalePending("txn_1", dispatchedOutbox(time.Now().Add(-time.Hour), 1))
+
+	mockTransactionStorage.EXPECT().
+		GetStalePendingTransactions(gomock.Any(), gomock.Any(), reaperBatchSize).
+		Return([]models.Transaction{stale}, nil)
+	mockAccountStorage.EXPECT().GetAppliedTransaction(gomock.Any(), "txn_1").Return(nil, nil)
+	mockTransactionStorage.EXPECT().
+		RequeueOutboxEntry(gomock.Any(), "txn_1", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, transactionID string, entry *models.OutboxEntry, reason string) error {
+			assert.False(t, entry.Dispatched)
+			assert.Equal(t, 2, entry.Requeues)
+			assert.Equal(t, stale.Outbox.Payload, entry.Payload)
+			assert.Contains(t, reason, "re-enqueued (2 of 3)")
+			return nil
+		})
+
+	err := service.Sweep(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), service.stats.Requeued)
+}
+
+func TestReaperService_Sweep_LeavesRecentlyDispatchedAlone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewReaperService(mockAccountStorage, mockTransactionStorage, 10*time.Minute, 3)
+
+	stale := newStalePending("txn_1", dispatchedOutbox(time.Now().Add(-time.Minute), 0))
+
+	mockTransactionStorage.EXPECT().
+		GetStalePendingTransactions(gomock.Any(), gomock.Any(), reaperBatchSize).
+		Return([]models.Transaction{stale}, nil)
+	mockAccountStorage.EXPECT().GetAppliedTransaction(gomock.Any(), "txn_1").Return(nil, nil)
+
+	err := service.Sweep(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), service.stats.AwaitingDispatch)
+}
+
+func TestReaperService_Sweep_FailsAfterMaxRequeues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	mockNotifier := NewMockEventNotifier(ctrl)
+	service := NewReaperService(mockAccountStorage, mockTransactionStorage, 10*time.Minute, 3)
+	service.SetNotifier(mockNotifier)
+
+	stale := newStalePending("txn_1", dispatchedOutbox(time.Now().Add(-time.Hour), 3))
+
+	mockTransactionStorage.EXPECT().
+		GetStalePendingTransactions(gomock.Any(), gomock.Any(), reaperBatchSize).
+		Return([]models.Transaction{stale}, nil)
+	mockAccountStorage.EXPECT().GetAppliedTransaction(gomock.Any(), "txn_1").Return(nil, nil)
+	mockTransactionStorage.EXPECT().
+		UpdateTransactionStatusWithError(gomock.Any(), "txn_1", "failed", "reaper: not processed after 3 re-enqueues; balance unchanged").
+		Return(nil)
+	mockNotifier.EXPECT().
+		TransactionFailed(gomock.Any(), gomock.Any()).
+		Do(func(ctx context.Context, transaction *models.Transaction) {
+			assert.Equal(t, "failed", transaction.Status)
+			assert.Contains(t, transaction.ErrorMessage, "re-enqueues")
+		})
+
+	err := service.Sweep(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), service.stats.Failed)
+}
+
+func TestReaperService_Sweep_CompletesBothTransferLegsOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewReaperService(mockAccountStorage, mockTransactionStorage, 10*time.Minute, 3)
+
+	pending := NewPendingTransfer(&models.TransferRequest{
+		FromAccountID: "acc_from",
+		ToAccountID:   "acc_to",
+		Amount:        models.MustParseMoney("40.00"),
+	})
+	legs := []models.Transaction{*pending.Debit, *pending.Credit}
+
+	mockTransactionStorage.EXPECT().
+		GetStalePendingTransactions(gomock.Any(), gomock.Any(), reaperBatchSize).
+		Return(legs, nil)
+	mockTransactionStorage.EXPECT().
+		GetTransactionsByTransferID(gomock.Any(), pending.TransferID).
+		Return(legs, nil).
+		Times(1)
+	mockAccountStorage.EXPECT().
+		GetAppliedTransaction(gomock.Any(), pending.Debit.TransactionID).
+		Return(&models.AppliedTransaction{PreviousBalance: models.MustParseMoney("100.00"), NewBalance: models.MustParseMoney("60.00")}, nil)
+	mockAccountStorage.EXPECT().
+		GetAppliedTransaction(gomock.Any(), pending.Credit.TransactionID).
+		Return(&models.AppliedTransaction{PreviousBalance: models.MustParseMoney("10.00"), NewBalance: models.MustParseMoney("50.00")}, nil)
+	mockTransactionStorage.EXPECT().
+		UpdateTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, transaction *models.Transaction) error {
+			assert.Equal(t, "completed", transaction.Status)
+			return nil
+		}).
+		Times(2)
+
+	err := service.Sweep(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), service.stats.Scanned)
+	assert.Equal(t, int64(1), service.stats.Completed)
+}
+
+func TestReaperService_Stats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewReaperService(NewMockAccountStorage(ctrl), mockTransactionStorage, 10*time.Minute, 3)
+	service.stats.Requeued = 4
+
+	mockTransactionStorage.EXPECT().CountStalePendingTransactions(gomock.Any(), gomock.Any()).Return(int64(7), nil)
+
+	stats, err := service.Stats(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), stats.Requeued)
+	assert.Equal(t, int64(7), stats.StalePending)
+	assert.Equal(t, int64(600), stats.MaxAgeSeconds)
+}
+
+func TestReaperService_OldestPendingAge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewReaperService(NewMockAccountStorage(ctrl), mockTransactionStorage, 10*time.Minute, 3)
+
+	mockTransactionStorage.EXPECT().
+		GetStalePendingTransactions(gomock.Any(), gomock.Any(), 1).
+		Return([]models.Transaction{{TransactionID: "txn_old", Status: "pending", Timestamp: time.Now().Add(-90 * time.Second)}}, nil)
+
+	age, err := service.OldestPendingAge(context.Background())
+	assert.NoError(t, err)
+	assert.InDelta(t, 90, age.Seconds(), 1)
+
+	mockTransactionStorage.EXPECT().
+		GetStalePendingTransactions(gomock.Any(), gomock.Any(), 1).
+		Return(nil, nil)
+
+	age, err = service.OldestPendingAge(context.Background())
+	assert.NoError(t, err)
+	assert.Zero(t, age)
+}
@@ -0,0 +1,279 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/utils"
+)
+
+// reconcileBatchSize is how many accounts are loaded from Postgres at a time
+const reconcileBatchSize = 100
+
+// ReconciliationService proves that Postgres balances and the MongoDB
+// transaction log agree. For each account it replays the settled records of
+// the log, checks that each record's balances follow on from the one before,
+// and compares the result with the stored balance and the account's postings.
+//
+// Opening balances are not logged, so the replay starts from the previous
+// balance of the account's first settled record.
+type ReconciliationService struct {
+	accountStorage     AccountStorage
+	transactionStorage TransactionStorage
+	pendingMaxAge      time.Duration
+
+	mu   sync.Mutex
+	last *models.ReconciliationReport
+}
+
+func NewReconciliationService(accountStorage AccountStorage, transactionStorage TransactionStorage, pendingMaxAge time.Duration) *ReconciliationService {
+	return &ReconciliationService{
+		accountStorage:     accountStorage,
+		transactionStorage: transactionStorage,
+		pendingMaxAge:      pendingMaxAge,
+	}
+}
+
+// Reconcile checks one account, or every account, and with opts.Repair posts
+// adjustment entries for balances that disagree with the log
+func (s *ReconciliationService) Reconcile(ctx context.Context, opts models.ReconcileOptions) (*models.ReconciliationReport, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "reconciliation"),
+		slog.String("operation", "reconcile"),
+		slog.Bool("repair", opts.Repair))
+
+	report := &models.ReconciliationReport{
+		StartedAt: time.Now(),
+		AccountID: opts.AccountID,
+		Repair:    opts.Repair,
+		Issues:    []models.ReconciliationIssue{},
+		Repairs:   []models.ReconciliationRepair{},
+	}
+
+	if opts.AccountID != "" {
+		account, err := s.accountStorage.GetAccountByID(ctx, opts.AccountID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get account: %w", err)
+		}
+		if err := s.reconcileAccount(ctx, account, opts.Repair, report); err != nil {
+			return nil, err
+		}
+	} else {
+		afterID := ""
+		for {
+			accounts, err := s.accountStorage.ListAccounts(ctx, afterID, reconcileBatchSize)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list accounts: %w", err)
+			}
+			for i := range accounts {
+				if err := s.reconcileAccount(ctx, &accounts[i], opts.Repair, report); err != nil {
+					return nil, err
+				}
+			}
+			if len(accounts) < reconcileBatchSize {
+				break
+			}
+			afterID = accounts[len(accounts)-1].ID
+		}
+	}
+
+	report.FinishedAt = time.Now()
+
+	s.mu.Lock()
+	s.last = report
+	s.mu.Unlock()
+
+	logger.Info("Reconciliation finished",
+		slog.Int("accounts_checked", report.AccountsChecked),
+		slog.Int("accounts_skipped", report.AccountsSkipped),
+		slog.Int("issues", len(report.Issues)),
+		slog.Int("unresolved", report.Unresolved()),
+		slog.Int("repairs", len(report.Repairs)))
+
+	return report, nil
+}
+
+// LastReport returns the report of the most recent run, or nil if there has
+// been none since the service started
+func (s *ReconciliationService) LastReport(ctx context.Context) (*models.ReconciliationReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last, nil
+}
+
+// reconcileAccount adds one account's issues and repairs to report
+func (s *ReconciliationService) reconcileAccount(ctx context.Context, account *models.Account, repair bool, report *models.ReconciliationReport) error {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "reconciliation"),
+		slog.String("operation", "reconcile_account"),
+		slog.String("account_id", account.ID))
+
+	transactions, err := s.transactionStorage.GetAccountTransactions(ctx, account.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get transactions for %s: %w", account.ID, err)
+	}
+
+	// Read the balance again after the log: if it moved, a transaction landed
+	// mid-check and the two reads cannot be compared
+	ledger, err := s.accountStorage.GetLedgerBalance(ctx, account.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get ledger balance for %s: %w", account.ID, err)
+	}
+	if ledger.Balance != account.Balance {
+		logger.Info("Balance changed during check, skipping account")
+		report.AccountsSkipped++
+		return nil
+	}
+	report.AccountsChecked++
+
+	if !ledger.InBalance {
+		report.Issues = append(report.Issues, models.ReconciliationIssue{
+			AccountID: account.ID,
+			Kind:      models.ReconcileLedgerMismatch,
+			Expected:  ledger.PostedBalance,
+			Actual:    ledger.Balance,
+			Detail:    "stored balance differs from the sum of the account's postings",
+		})
+	}
+
+	orphanCutoff := time.Now().Add(-s.pendingMaxAge)
+	pending := 0
+	var replayed models.Money
+	var previous *models.Transaction
+
+	for i := range transactions {
+		transaction := &transactions[i]
+
+		switch transaction.Status {
+		case "pending":
+			pending++
+			if transaction.Timestamp.Before(orphanCutoff) {
+				report.Issues = append(report.Issues, models.ReconciliationIssue{
+					AccountID:     account.ID,
+					Kind:          models.ReconcileOrphanedPending,
+					TransactionID: transaction.TransactionID,
+					Detail:        fmt.Sprintf("pending since %s", transaction.Timestamp.UTC().Format(time.RFC3339)),
+				})
+			}
+			continue
+		case "completed", "partially_refunded", "reversed":
+		default:
+			continue
+		}
+
+		if previous == nil {
+			replayed = transaction.PreviousBalance
+		} else if transaction.PreviousBalance != previous.NewBalance {
+			report.Issues = append(report.Issues, models.ReconciliationIssue{
+				AccountID:     account.ID,
+				Kind:          models.ReconcileBalanceGap,
+				TransactionID: transaction.TransactionID,
+				Expected:      previous.NewBalance,
+				Actual:        transaction.PreviousBalance,
+				Detail:        fmt.Sprintf("previous balance does not follow on from %s", previous.TransactionID),
+			})
+		}
+
+		if expected := transaction.PreviousBalance.Add(transaction.SignedAmount()); transaction.NewBalance != expected {
+			report.Issues = append(report.Issues, models.ReconciliationIssue{
+				AccountID:     account.ID,
+				Kind:          models.ReconcileRecordMismatch,
+				TransactionID: transaction.TransactionID,
+				Expected:      expected,
+				Actual:        transaction.NewBalance,
+				Detail:        fmt.Sprintf("new balance is not previous balance plus %s %s", transaction.Type, transaction.Amount),
+			})
+		}
+
+		replayed = replayed.Add(transaction.SignedAmount())
+		previous = transaction
+	}
+
+	// With nothing settled in the log there is no history to replay
+	if previous == nil || replayed == ledger.Balance {
+		return nil
+	}
+
+	issue := models.ReconciliationIssue{
+		AccountID: account.ID,
+		Kind:      models.ReconcileBalanceMismatch,
+		Expected:  replayed,
+		Actual:    ledger.Balance,
+		Detail:    "replayed transaction log differs from the stored balance",
+	}
+	logger.Warn("Balance does not match transaction log",
+		slog.String("replayed", replayed.String()),
+		slog.String("balance", ledger.Balance.String()))
+
+	if repair {
+		result := s.repair(ctx, account, ledger.Balance, replayed, pending)
+		report.Repairs = append(report.Repairs, result)
+		issue.Repaired = result.Error == ""
+	}
+
+	report.Issues = append(report.Issues, issue)
+	return nil
+}
+
+// repair posts an adjustment entry that moves the account from balance to
+// replayed, with the difference held in the suspense account until it is
+// explained. Accounts with pending records are left alone, because a queued
+// change may already be in Postgres before its record is completed.
+func (s *ReconciliationService) repair(ctx context.Context, account *models.Account, balance, replayed models.Money, pending int) models.ReconciliationRepair {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "reconciliation"),
+		slog.String("operation", "repair"),
+		slog.String("account_id", account.ID))
+
+	delta := replayed.Sub(balance)
+	result := models.ReconciliationRepair{AccountID: account.ID, Amount: delta}
+
+	if pending > 0 {
+		result.Error = fmt.Sprintf("account has %d pending transactions", pending)
+		return result
+	}
+
+	entry := adjustmentEntry(account, delta)
+	changes, err := s.accountStorage.PostJournalEntry(ctx, entry)
+	if err != nil {
+		logger.Error("Failed to post adjustment entry", slog.String("error", err.Error()))
+		result.Error = err.Error()
+		return result
+	}
+
+	// A transaction that landed after the check makes the adjustment wrong, so
+	// it is taken back out
+	if change := balanceChange(changes, account.ID); change.PreviousBalance != balance {
+		if _, err := s.accountStorage.PostJournalEntry(ctx, entry.Reversal("rollback of "+entry.ID)); err != nil {
+			logger.Error("Failed to roll back adjustment entry",
+				slog.String("journal_entry_id", entry.ID),
+				slog.String("error", err.Error()))
+		}
+		result.Error = "balance changed before the adjustment was posted"
+		return result
+	}
+
+	logger.Info("Adjustment entry posted",
+		slog.String("journal_entry_id", entry.ID),
+		slog.String("amount", delta.String()))
+
+	result.JournalEntryID = entry.ID
+	return result
+}
+
+// adjustmentEntry changes an account's balance by delta against the suspense
+// account for its currency
+func adjustmentEntry(account *models.Account, delta models.Money) *models.JournalEntry {
+	currency := accountCurrency(account)
+	customer := models.Posting{AccountID: account.ID, Direction: models.Credit, Amount: delta, Currency: currency}
+	suspense := models.Posting{AccountID: models.SystemAccountID(models.SystemSuspense, currency), Direction: models.Debit, Amount: delta, Currency: currency}
+	if delta.IsNegative() {
+		customer.Direction, suspense.Direction = models.Debit, models.Credit
+		customer.Amount, suspense.Amount = delta.Neg(), delta.Neg()
+	}
+	return models.NewJournalEntry("Reconciliation adjustment", suspense, customer)
+}
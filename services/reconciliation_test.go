@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func loggedTransaction(transactionID, transactionType, amount, previous, updated string) models.Transaction {
+	return models.Transaction{
+		ID:              transactionID,
+		TransactionID:   transactionID,
+		AccountID:       "acc_12345",
+		Type:            transactionType,
+		Amount:          models.MustParseMoney(amount),
+		Currency:        "USD",
+		PreviousBalance: models.MustParseMoney(previous),
+		NewBalance:      models.MustParseMoney(updated),
+		Status:          "completed",
+		Timestamp:       time.Now().Add(-time.Hour),
+	}
+}
+
+func reconcileAccount(balance string) *models.Account {
+	return &models.Account{ID: "acc_12345", OwnerName: "John Doe", Balance: models.MustParseMoney(balance), Currency: "USD"}
+}
+
+func expectLedger(mock *MockAccountStorage, balance, posted string) {
+	mock.EXPECT().GetLedgerBalance(gomock.Any(), "acc_12345").Return(&models.LedgerBalance{
+		AccountID:     "acc_12345",
+		Currency:      "USD",
+		Balance:       models.MustParseMoney(balance),
+		PostedBalance: models.MustParseMoney(posted),
+		InBalance:     balance == posted,
+	}, nil)
+}
+
+func TestReconciliationService_Reconcile_Clean(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewReconciliationService(mockAccountStorage, mockTransactionStorage, 10*time.Minute)
+
+	fresh := loggedTransaction("txn_4", "deposit", "5.00", "0", "0")
+	fresh.Status = "pending"
+	fresh.Timestamp = time.Now()
+
+	mockAccountStorage.EXPECT().
+		ListAccounts(gomock.Any(), "", reconcileBatchSize).
+		Return([]models.Account{*reconcileAccount("120.00")}, nil)
+	mockTransactionStorage.EXPECT().
+		GetAccountTransactions(gomock.Any(), "acc_12345").
+		Return([]models.Transaction{
+			loggedTransaction("txn_1", "deposit", "100.00", "50.00", "150.00"),
+			loggedTransaction("txn_2", "transfer_out", "40.00", "150.00", "110.00"),
+			{TransactionID: "txn_x", Type: "withdraw", Amount: models.MustParseMoney("999.00"), Status: "failed"},
+			loggedTransaction("txn_3", "deposit", "10.00", "110.00", "120.00"),
+			fresh,
+		}, nil)
+	expectLedger(mockAccountStorage, "120.00", "120.00")
+
+	report, err := service.Reconcile(context.Background(), models.ReconcileOptions{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.AccountsChecked)
+	assert.Empty(t, report.Issues)
+	assert.Equal(t, 0, report.ExitCode())
+
+	last, _ := service.LastReport(context.Background())
+	assert.Same(t, report, last)
+}
+
+func TestReconciliationService_Reconcile_FindsDiscrepancies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewReconciliationService(mockAccountStorage, mockTransactionStorage, 10*time.Minute)
+
+	orphan := loggedTransaction("txn_4", "deposit", "5.00", "0", "0")
+	orphan.Status = "pending"
+
+	mockAccountStorage.EXPECT().GetAccountByID(gomock.Any(), "acc_12345").Return(reconcileAccount("200.00"), nil)
+	mockTransactionStorage.EXPECT().
+		GetAccountTransactions(gomock.Any(), "acc_12345").
+		Return([]models.Transaction{
+			loggedTransaction("txn_1", "deposit", "100.00", "50.00", "150.00"),
+			loggedTransaction("txn_2", "withdraw", "20.00", "160.00", "140.00"),
+			loggedTransaction("txn_3", "deposit", "10.00", "140.00", "155.00"),
+			orphan,
+		}, nil)
+	expectLedger(mockAccountStorage, "200.00", "190.00")
+
+	// Repair is not requested, so nothing is posted
+	report, err := service.Reconcile(context.Background(), models.ReconcileOptions{AccountID: "acc_12345"})
+
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 5)
+
+	kinds := make(map[string]models.ReconciliationIssue)
+	for _, issue := range report.Issues {
+		kinds[issue.Kind] = issue
+	}
+	assert.Equal(t, models.MustParseMoney("190.00"), kinds[models.ReconcileLedgerMismatch].Expected)
+	assert.Equal(t, "txn_2", kinds[models.ReconcileBalanceGap].TransactionID)
+	assert.Equal(t, models.MustParseMoney("150.00"), kinds[models.ReconcileBalanceGap].Expected)
+	assert.Equal(t, "txn_3", kinds[models.ReconcileRecordMismatch].TransactionID)
+	assert.Equal(t, "txn_4", kinds[models.ReconcileOrphanedPending].TransactionID)
+
+	// 50 + 100 - 20 + 10
+	assert.Equal(t, models.MustParseMoney("140.00"), kinds[models.ReconcileBalanceMismatch].Expected)
+	assert.Equal(t, models.MustParseMoney("200.00"), kinds[models.ReconcileBalanceMismatch].Actual)
+	assert.Empty(t, report.Repairs)
+	assert.Equal(t, 1, report.ExitCode())
+}
+
+func TestReconciliationService_Reconcile_RepairsMismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewReconciliationService(mockAccountStorage, mockTransactionStorage, 10*time.Minute)
+
+	mockAccountStorage.EXPECT().GetAccountByID(gomock.Any(), "acc_12345").Return(reconcileAccount("90.00"), nil)
+	mockTransactionStorage.EXPECT().
+		GetAccountTransactions(gomock.Any(), "acc_12345").
+		Return([]models.Transaction{loggedTransaction("txn_1", "deposit", "100.00", "0", "100.00")}, nil)
+	expectLedger(mockAccountStorage, "90.00", "90.00")
+	mockAccountStorage.EXPECT().
+		PostJournalEntry(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, entry *models.JournalEntry) ([]models.BalanceChange, error) {
+			assert.Equal(t, "Reconciliation adjustment", entry.Description)
+			assert.Equal(t, []models.Posting{
+				{AccountID: "sys_suspense_usd", Direction: models.Debit, Amount: models.MustParseMoney("10.00"), Currency: "USD", CreatedAt: entry.CreatedAt},
+				{AccountID: "acc_12345", Direction: models.Credit, Amount: models.MustParseMoney("10.00"), Currency: "USD", CreatedAt: entry.CreatedAt},
+			}, entry.Postings)
+			return []models.BalanceChange{{
+				AccountID:       "acc_12345",
+				PreviousBalance: models.MustParseMoney("90.00"),
+				NewBalance:      models.MustParseMoney("100.00"),
+			}}, nil
+		})
+
+	report, err := service.Reconcile(context.Background(), models.ReconcileOptions{AccountID: "acc_12345", Repair: true})
+
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 1)
+	assert.True(t, report.Issues[0].Repaired)
+	require.Len(t, report.Repairs, 1)
+	assert.Equal(t, models.MustParseMoney("10.00"), report.Repairs[0].Amount)
+	assert.NotEmpty(t, report.Repairs[0].JournalEntryID)
+	assert.Equal(t, 0, report.ExitCode())
+}
+
+func TestReconciliationService_Reconcile_NoRepairWithPendingRecords(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewReconciliationService(mockAccountStorage, mockTransactionStorage, 10*time.Minute)
+
+	inFlight := loggedTransaction("txn_2", "deposit", "10.00", "0", "0")
+	inFlight.Status = "pending"
+	inFlight.Timestamp = time.Now()
+
+	mockAccountStorage.EXPECT().GetAccountByID(gomock.Any(), "acc_12345").Return(reconcileAccount("110.00"), nil)
+	mockTransactionStorage.EXPECT().
+		GetAccountTransactions(gomock.Any(), "acc_12345").
+		Return([]models.Transaction{loggedTransaction("txn_1", "deposit", "100.00", "0", "100.00"), inFlight}, nil)
+	expectLedger(mockAccountStorage, "110.00", "110.00")
+
+	report, err := service.Reconcile(context.Background(), models.ReconcileOptions{AccountID: "acc_12345", Repair: true})
+
+	require.NoError(t, err)
+	require.Len(t, report.Repairs, 1)
+	assert.Contains(t, report.Repairs[0].Error, "pending")
+	assert.False(t, report.Issues[0].Repaired)
+	assert.Equal(t, 1, report.ExitCode())
+}
+
+func TestReconciliationService_Reconcile_SkipsAccountThatMoved(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewReconciliationService(mockAccountStorage, mockTransactionStorage, 10*time.Minute)
+
+	mockAccountStorage.EXPECT().GetAccountByID(gomock.Any(), "acc_12345").Return(reconcileAccount("100.00"), nil)
+	mockTransactionStorage.EXPECT().
+		GetAccountTransactions(gomock.Any(), "acc_12345").
+		Return([]models.Transaction{loggedTransaction("txn_1", "deposit", "100.00", "0", "100.00")}, nil)
+	expectLedger(mockAccountStorage, "130.00", "130.00")
+
+	report, err := service.Reconcile(context.Background(), models.ReconcileOptions{AccountID: "acc_12345"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.AccountsChecked)
+	assert.Equal(t, 1, report.AccountsSkipped)
+	assert.Empty(t, report.Issues)
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/audit"
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/utils"
+)
+
+// ReverseTransaction undoes a completed deposit or withdrawal, in full or in
+// part, by posting a compensating transaction in the opposite direction. Partial
+// refunds leave the original "partially_refunded" and may be repeated until
+// they add up to its amount; the original then moves to "reversed" and can
+// never be refunded again.
+func (s *TransactionService) ReverseTransaction(ctx context.Context, transactionID string, req *models.ReverseTransactionRequest) (*models.Transaction, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "transaction"),
+		slog.String("operation", "reverse_transaction"),
+		slog.String("transaction_id", transactionID))
+
+	logger.Info("Starting transaction reversal", slog.String("amount", req.Amount.String()))
+
+	original, err := s.GetTransactionByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	auditEntry := audit.FromContext(ctx)
+	auditEntry.Account(original.AccountID)
+
+	reversalType, err := checkReversible(original)
+	if err != nil {
+		logger.Error("Transaction cannot be reversed", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	// A zero amount refunds whatever is left; anything less is a partial refund
+	remaining := original.Amount.Sub(original.RefundedAmount)
+	amount := req.Amount
+	if amount == 0 {
+		amount = remaining
+	}
+	if amount.IsNegative() {
+		return nil, models.Errorf(models.ErrInvalidAmount, "reversal amount must be greater than 0")
+	}
+	if amount > remaining {
+		return nil, models.Errorf(models.ErrInvalidAmount, "reversal amount %s exceeds the %s left to refund of %s", amount, remaining, original.Amount)
+	}
+
+	currency, err := s.resolveCurrency(ctx, original.AccountID, original.Currency, amount)
+	if err != nil {
+		logger.Error("Currency check failed", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to reverse transaction: %w", err)
+	}
+
+	// Claim the refund on the original before moving any money; the update is
+	// conditional on the refunds staying within the original amount, so
+	// concurrent refunds of the same transaction cannot overshoot it
+	reversalID := models.NewTransactionID()
+	now := time.Now()
+	logger = logger.With(slog.String("reversal_id", reversalID))
+
+	recorded, err := s.transactionStorage.RecordRefund(ctx, transactionID, reversalID, amount, now)
+	if err != nil {
+		logger.Error("Failed to record refund", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to reverse transaction: %w", err)
+	}
+	if !recorded {
+		logger.Warn("Transaction was refunded by another request")
+		return nil, models.Errorf(models.ErrAlreadyReversed, "transaction %s was refunded by another request and has less than %s left to refund", transactionID, amount)
+	}
+
+	description := "Reversal of " + transactionID
+	if req.Reason != "" {
+		description += ": " + req.Reason
+	}
+
+	entry, err := models.NewTransactionEntry(reversalID, original.AccountID, reversalType, amount, currency, description)
+	if err != nil {
+		s.restoreRefund(ctx, transactionID, reversalID, amount)
+		return nil, err
+	}
+
+	changes, err := s.accountStorage.PostJournalEntry(ctx, entry)
+	if err != nil {
+		logger.Error("Failed to post reversal entry", slog.String("error", err.Error()))
+		s.restoreRefund(ctx, transactionID, reversalID, amount)
+		return nil, fmt.Errorf("failed to reverse transaction: %w", err)
+	}
+	change := balanceChange(changes, original.AccountID)
+
+	reversal := &models.Transaction{
+		ID:              reversalID,
+		TransactionID:   reversalID,
+		AccountID:       original.AccountID,
+		Type:            reversalType,
+		Amount:          amount,
+		PreviousBalance: change.PreviousBalance,
+		NewBalance:      change.NewBalance,
+		Currency:        currency,
+		Description:     description,
+		Timestamp:       now,
+		Status:          "completed",
+		ReversalOf:      transactionID,
+	}
+
+	if err := s.transactionStorage.CreateTransaction(ctx, reversal); err != nil {
+		logger.Error("Failed to save reversal, reversing journal entry", slog.String("error", err.Error()))
+		s.reverseEntry(ctx, entry)
+		s.restoreRefund(ctx, transactionID, reversalID, amount)
+		return nil, fmt.Errorf("failed to save reversal: %w", err)
+	}
+
+	auditEntry.Target(audit.TargetReversal, reversalID)
+	auditEntry.Balances(changes)
+
+	logger.Info("Transaction reversed successfully",
+		slog.String("reversal_amount", amount.String()),
+		slog.String("new_balance", change.NewBalance.String()))
+	return reversal, nil
+}
+
+// checkReversible returns the transaction type that compensates original, or
+// why original cannot be reversed
+func checkReversible(original *models.Transaction) (string, error) {
+	if original.ReversalOf != "" {
+		return "", models.Errorf(models.ErrNotReversible, "transaction %s is itself a reversal", original.TransactionID)
+	}
+
+	switch original.Status {
+	case "completed", "partially_refunded":
+	case "reversed":
+		return "", models.Errorf(models.ErrAlreadyReversed, "transaction already reversed by %s", original.ReversedBy)
+	default:
+		return "", models.Errorf(models.ErrNotReversible, "only completed transactions can be reversed, transaction is %s", original.Status)
+	}
+
+	switch original.Type {
+	case "deposit":
+		return "withdraw", nil
+	case "withdraw":
+		return "deposit", nil
+	}
+	return "", models.Errorf(models.ErrNotReversible, "only deposits and withdrawals can be reversed, not %s", original.Type)
+}
+
+// restoreRefund takes a refund back off the original after the reversal
+// failed part way
+func (s *TransactionService) restoreRefund(ctx context.Context, transactionID, reversalID string, amount models.Money) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "transaction"),
+		slog.String("operation", "restore_refund"),
+		slog.String("transaction_id", transactionID),
+		slog.String("reversal_id", reversalID))
+
+	if err := s.transactionStorage.RestoreRefund(ctx, transactionID, reversalID, amount); err != nil {
+		logger.Error("Failed to restore refunded transaction", slog.String("error", err.Error()))
+		return
+	}
+	logger.Info("Refund taken back off the original transaction")
+}
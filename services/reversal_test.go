@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/utils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func completedDeposit() *models.Transaction {
+	return &models.Transaction{
+		ID:            "txn_original",
+		TransactionID: "txn_original",
+		AccountID:     "acc_12345",
+		Type:          "deposit",
+		Amount:        models.MustParseMoney("100.00"),
+		Currency:      "USD",
+		Status:        "completed",
+	}
+}
+
+func TestTransactionService_ReverseTransaction_Full(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	ctx := utils.WithLogger(context.Background(), logger)
+
+	mockTransactionStorage.EXPECT().
+		GetTransactionByID(ctx, "txn_original").
+		Return(completedDeposit(), nil)
+
+	var reversalID string
+	mockTransactionStorage.EXPECT().
+		RecordRefund(ctx, "txn_original", gomock.Any(), models.MustParseMoney("100.00"), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, transactionID, id string, _ models.Money, _ interface{}) (bool, error) {
+			reversalID = id
+			return true, nil
+		})
+
+	// Reversing a deposit takes the money back out
+	expectPostedEntry(t, mockAccountStorage, "acc_12345", "withdraw", models.MustParseMoney("100.00"),
+		models.MustParseMoney("150.00"), models.MustParseMoney("50.00"))
+
+	mockTransactionStorage.EXPECT().
+		CreateTransaction(ctx, gomock.Any()).
+		Return(nil)
+
+	reversal, err := service.ReverseTransaction(ctx, "txn_original", &models.ReverseTransactionRequest{Reason: "posted twice"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, reversalID, reversal.TransactionID)
+	assert.Equal(t, "txn_original", reversal.ReversalOf)
+	assert.Equal(t, "withdraw", reversal.Type)
+	assert.Equal(t, models.MustParseMoney("100.00"), reversal.Amount)
+	assert.Equal(t, models.MustParseMoney("50.00"), reversal.NewBalance)
+	assert.Equal(t, "Reversal of txn_original: posted twice", reversal.Description)
+}
+
+func TestTransactionService_ReverseTransaction_PartialRefund(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
+
+	ctx := context.Background()
+
+	withdrawal := completedDeposit()
+	withdrawal.Type = "withdraw"
+	mockTransactionStorage.EXPECT().GetTransactionByID(ctx, "txn_original").Return(withdrawal, nil)
+	mockTransactionStorage.EXPECT().RecordRefund(ctx, "txn_original", gomock.Any(), models.MustParseMoney("40.00"), gomock.Any()).Return(true, nil)
+	expectPostedEntry(t, mockAccountStorage, "acc_12345", "deposit", models.MustParseMoney("40.00"),
+		models.MustParseMoney("10.00"), models.MustParseMoney("50.00"))
+	mockTransactionStorage.EXPECT().CreateTransaction(ctx, gomock.Any()).Return(nil)
+
+	reversal, err := service.ReverseTransaction(ctx, "txn_original", &models.ReverseTransactionRequest{Amount: models.MustParseMoney("40.00")})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "deposit", reversal.Type)
+	assert.Equal(t, models.MustParseMoney("40.00"), reversal.Amount)
+}
+
+func TestTransactionService_ReverseTransaction_RefundsWhatIsLeft(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
+
+	ctx := context.Background()
+
+	// An earlier partial refund took back 40.00 of the 100.00 deposit
+	original := completedDeposit()
+	original.Status = "partially_refunded"
+	original.RefundedAmount = models.MustParseMoney("40.00")
+	original.Refunds = []string{"txn_refund_1"}
+
+	mockTransactionStorage.EXPECT().GetTransactionByID(ctx, "txn_original").Return(original, nil)
+	mockTransactionStorage.EXPECT().RecordRefund(ctx, "txn_original", gomock.Any(), models.MustParseMoney("60.00"), gomock.Any()).Return(true, nil)
+	expectPostedEntry(t, mockAccountStorage, "acc_12345", "withdraw", models.MustParseMoney("60.00"),
+		models.MustParseMoney("110.00"), models.MustParseMoney("50.00"))
+	mockTransactionStorage.EXPECT().CreateTransaction(ctx, gomock.Any()).Return(nil)
+
+	reversal, err := service.ReverseTransaction(ctx, "txn_original", &models.ReverseTransactionRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.MustParseMoney("60.00"), reversal.Amount)
+}
+
+func TestTransactionService_ReverseTransaction_Rejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*models.Transaction)
+		amount  models.Money
+		wantErr error
+	}{
+		{"already reversed", func(tx *models.Transaction) { tx.Status = "reversed"; tx.ReversedBy = "txn_other" }, 0, models.ErrAlreadyReversed},
+		{"pending", func(tx *models.Transaction) { tx.Status = "pending" }, 0, models.ErrNotReversible},
+		{"transfer leg", func(tx *models.Transaction) { tx.Type = "transfer_out" }, 0, models.ErrNotReversible},
+		{"reversal of a reversal", func(tx *models.Transaction) { tx.ReversalOf = "txn_first" }, 0, models.ErrNotReversible},
+		{"amount above original", func(tx *models.Transaction) {}, models.MustParseMoney("100.01"), models.ErrInvalidAmount},
+		{"amount above what is left", func(tx *models.Transaction) {
+			tx.Status = "partially_refunded"
+			tx.RefundedAmount = models.MustParseMoney("70.00")
+		}, models.MustParseMoney("30.01"), models.ErrInvalidAmount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAccountStorage := NewMockAccountStorage(ctrl)
+			mockTransactionStorage := NewMockTransactionStorage(ctrl)
+			service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+
+			original := completedDeposit()
+			tt.mutate(original)
+			mockTransactionStorage.EXPECT().GetTransactionByID(gomock.Any(), "txn_original").Return(original, nil)
+
+			_, err := service.ReverseTransaction(context.Background(), "txn_original", &models.ReverseTransactionRequest{Amount: tt.amount})
+
+			assert.True(t, errors.Is(err, tt.wantErr), "got %v", err)
+		})
+	}
+}
+
+func TestTransactionService_ReverseTransaction_LostRace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
+
+	ctx := context.Background()
+
+	mockTransactionStorage.EXPECT().GetTransactionByID(ctx, "txn_original").Return(completedDeposit(), nil)
+	mockTransactionStorage.EXPECT().RecordRefund(ctx, "txn_original", gomock.Any(), models.MustParseMoney("100.00"), gomock.Any()).Return(false, nil)
+
+	_, err := service.ReverseTransaction(ctx, "txn_original", &models.ReverseTransactionRequest{})
+
+	assert.True(t, errors.Is(err, models.ErrAlreadyReversed))
+}
+
+func TestTransactionService_ReverseTransaction_InsufficientFundsRestoresOriginal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
+
+	ctx := context.Background()
+
+	var reversalID string
+	mockTransactionStorage.EXPECT().GetTransactionByID(ctx, "txn_original").Return(completedDeposit(), nil)
+	mockTransactionStorage.EXPECT().
+		RecordRefund(ctx, "txn_original", gomock.Any(), models.MustParseMoney("100.00"), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, transactionID, id string, _ models.Money, _ interface{}) (bool, error) {
+			reversalID = id
+			return true, nil
+		})
+	mockAccountStorage.EXPECT().
+		PostJournalEntry(ctx, gomock.Any()).
+		Return(nil, &models.InsufficientFundsError{AccountID: "acc_12345", Balance: models.MustParseMoney("20.00"), Requested: models.MustParseMoney("100.00")})
+	mockTransactionStorage.EXPECT().
+		RestoreRefund(ctx, "txn_original", gomock.Any(), models.MustParseMoney("100.00")).
+		DoAndReturn(func(ctx context.Context, transactionID, id string, _ models.Money) error {
+			assert.Equal(t, reversalID, id)
+			return nil
+		})
+
+	_, err := service.ReverseTransaction(ctx, "txn_original", &models.ReverseTransactionRequest{})
+
+	assert.True(t, errors.Is(err, models.ErrInsufficientFunds))
+}
@@ -0,0 +1,500 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/audit"
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/queue"
+	"github.com/appy29/banking-ledger-service/utils"
+)
+
+const (
+	// scheduleLease is how long a claimed schedule is hidden from other
+	// scheduler instances; one that dies mid-run is picked up after it
+	scheduleLease = time.Minute
+
+	// scheduleBatchSize caps how many due schedules one pass claims
+	scheduleBatchSize = 50
+
+	// scheduleMaxRunsPerPass caps how many occurrences of one schedule a pass
+	// runs, so a long MissedRunsAll backlog is worked off over several passes
+	scheduleMaxRunsPerPass = 10
+)
+
+// ScheduleService manages standing orders and runs their occurrences as they
+// fall due. Each occurrence is logged as a pending transaction with an outbox
+// message, so it reaches the workers through the same queue as API requests.
+// Without a broker, a processor applies each occurrence as soon as it is logged.
+type ScheduleService struct {
+	accountStorage     AccountStorage
+	scheduleStorage    ScheduleStorage
+	transactionStorage TransactionStorage
+	missedGrace        time.Duration
+	processor          TransactionProcessor // Applies occurrences in sync mode; nil queues them
+}
+
+func NewScheduleService(accountStorage AccountStorage, scheduleStorage ScheduleStorage, transactionStorage TransactionStorage, missedGrace time.Duration) *ScheduleService {
+	return &ScheduleService{
+		accountStorage:     accountStorage,
+		scheduleStorage:    scheduleStorage,
+		transactionStorage: transactionStorage,
+		missedGrace:        missedGrace,
+	}
+}
+
+// SetProcessor makes the scheduler apply each occurrence itself instead of
+// queuing it, for when there is no broker, and so no outbox relay or workers
+func (s *ScheduleService) SetProcessor(processor TransactionProcessor) {
+	s.processor = processor
+}
+
+// CreateSchedule sets up a one-off or recurring deposit or withdrawal
+func (s *ScheduleService) CreateSchedule(ctx context.Context, accountID string, req *models.CreateScheduleRequest) (*models.Schedule, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "schedule"),
+		slog.String("operation", "create_schedule"),
+		slog.String("account_id", accountID))
+
+	if req.Type != "deposit" && req.Type != "withdraw" {
+		return nil, models.Errorf(models.ErrInvalidTransactionType, "transaction type must be either 'deposit' or 'withdraw'")
+	}
+	if req.Amount <= 0 {
+		return nil, models.Errorf(models.ErrInvalidAmount, "amount must be greater than 0")
+	}
+
+	missedRuns := req.MissedRuns
+	if missedRuns == "" {
+		missedRuns = models.MissedRunsLatest
+	}
+	if err := validateMissedRuns(missedRuns); err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountStorage.GetAccountByID(ctx, accountID)
+	if err != nil {
+		logger.Error("Failed to get account", slog.String("error", err.Error()))
+		return nil, err
+	}
+	if account.Status == models.AccountClosed {
+		return nil, models.Errorf(models.ErrAccountClosed, "account %s is closed", accountID)
+	}
+
+	currency := accountCurrency(account)
+	if req.Currency != "" && models.NormalizeCurrency(req.Currency) != currency {
+		return nil, models.Errorf(models.ErrCurrencyMismatch, "currency mismatch: account %s is denominated in %s, not %s",
+			accountID, currency, models.NormalizeCurrency(req.Currency))
+	}
+	if err := models.ValidateAmountPrecision(req.Amount, currency); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	schedule := &models.Schedule{
+		ID:          models.NewScheduleID(),
+		AccountID:   accountID,
+		Type:        req.Type,
+		Amount:      req.Amount,
+		Currency:    currency,
+		Description: strings.TrimSpace(req.Description),
+		Cron:        strings.TrimSpace(req.Cron),
+		MissedRuns:  missedRuns,
+		Status:      models.ScheduleActive,
+		EndAt:       req.EndAt,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := planFirstRun(schedule, req.RunAt, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduleStorage.CreateSchedule(ctx, schedule); err != nil {
+		logger.Error("Failed to create schedule", slog.String("error", err.Error()))
+		return nil, err
+	}
+	audit.FromContext(ctx).Target(audit.TargetSchedule, schedule.ID)
+
+	logger.Info("Schedule created",
+		slog.String("schedule_id", schedule.ID),
+		slog.String("cron", schedule.Cron),
+		slog.Time("next_run_at", *schedule.NextRunAt))
+	return schedule, nil
+}
+
+// planFirstRun sets the schedule's next run. A one-off schedule runs at
+// runAt, which must not be in the past; a recurring one at its first
+// occurrence at or after runAt, or after now if runAt is not given.
+func planFirstRun(schedule *models.Schedule, runAt *time.Time, now time.Time) error {
+	if !schedule.IsRecurring() {
+		if schedule.EndAt != nil {
+			return models.Errorf(models.ErrInvalidSchedule, "end_at only applies to recurring schedules")
+		}
+		if runAt == nil {
+			return models.Errorf(models.ErrInvalidSchedule, "run_at is required for a one-off schedule")
+		}
+		first := runAt.UTC().Truncate(time.Second)
+		if first.Before(now) {
+			return models.Errorf(models.ErrInvalidSchedule, "run_at %s is in the past", first.Format(time.RFC3339))
+		}
+		schedule.NextRunAt = &first
+		return nil
+	}
+
+	start := now
+	if runAt != nil && runAt.After(now) {
+		start = runAt.UTC().Truncate(time.Second)
+	}
+	first, err := schedule.NextAfter(start.Add(-time.Second))
+	if err != nil {
+		return err
+	}
+	if first == nil {
+		return models.Errorf(models.ErrInvalidSchedule, "cron expression %q has no occurrence before end_at", schedule.Cron)
+	}
+	schedule.NextRunAt = first
+	return nil
+}
+
+func validateMissedRuns(missedRuns string) error {
+	switch missedRuns {
+	case models.MissedRunsLatest, models.MissedRunsAll, models.MissedRunsSkip:
+		return nil
+	}
+	return models.Errorf(models.ErrInvalidSchedule, "missed_runs must be one of 'latest', 'all' or 'skip'")
+}
+
+// GetSchedule returns a schedule by ID
+func (s *ScheduleService) GetSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error) {
+	return s.scheduleStorage.GetSchedule(ctx, scheduleID)
+}
+
+// GetAccountSchedules returns an account's schedules, newest first
+func (s *ScheduleService) GetAccountSchedules(ctx context.Context, accountID string) ([]models.Schedule, error) {
+	if _, err := s.accountStorage.GetAccountByID(ctx, accountID); err != nil {
+		return nil, err
+	}
+	return s.scheduleStorage.GetAccountSchedules(ctx, accountID)
+}
+
+// UpdateSchedule changes an active or paused schedule
+func (s *ScheduleService) UpdateSchedule(ctx context.Context, scheduleID string, req *models.UpdateScheduleRequest) (*models.Schedule, error) {
+	return s.changeSchedule(ctx, scheduleID, "update_schedule", func(schedule *models.Schedule) error {
+		if err := checkNotFinished(schedule); err != nil {
+			return err
+		}
+
+		if req.Amount != nil {
+			if *req.Amount <= 0 {
+				return models.Errorf(models.ErrInvalidAmount, "amount must be greater than 0")
+			}
+			if err := models.ValidateAmountPrecision(*req.Amount, schedule.Currency); err != nil {
+				return err
+			}
+			schedule.Amount = *req.Amount
+		}
+		if req.Description != nil {
+			schedule.Description = strings.TrimSpace(*req.Description)
+		}
+		if req.MissedRuns != nil {
+			if err := validateMissedRuns(*req.MissedRuns); err != nil {
+				return err
+			}
+			schedule.MissedRuns = *req.MissedRuns
+		}
+
+		if req.Cron == nil && req.RunAt == nil && req.EndAt == nil {
+			return nil
+		}
+		if (req.Cron != nil || req.EndAt != nil) && !schedule.IsRecurring() {
+			return models.Errorf(models.ErrInvalidSchedule, "cron and end_at only apply to recurring schedules")
+		}
+		if req.Cron != nil {
+			if strings.TrimSpace(*req.Cron) == "" {
+				return models.Errorf(models.ErrInvalidSchedule, "a recurring schedule needs a cron expression")
+			}
+			schedule.Cron = strings.TrimSpace(*req.Cron)
+		}
+		if req.EndAt != nil {
+			schedule.EndAt = req.EndAt
+		}
+
+		return planFirstRun(schedule, req.RunAt, time.Now().UTC().Truncate(time.Second))
+	})
+}
+
+// CancelSchedule stops a schedule for good. Transactions it already logged
+// are not affected.
+func (s *ScheduleService) CancelSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error) {
+	return s.changeSchedule(ctx, scheduleID, "cancel_schedule", func(schedule *models.Schedule) error {
+		if err := checkNotFinished(schedule); err != nil {
+			return err
+		}
+		schedule.Status = models.ScheduleCancelled
+		schedule.NextRunAt = nil
+		return nil
+	})
+}
+
+// PauseSchedule stops an active schedule from running until it is resumed
+func (s *ScheduleService) PauseSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error) {
+	return s.changeSchedule(ctx, scheduleID, "pause_schedule", func(schedule *models.Schedule) error {
+		if schedule.Status != models.ScheduleActive {
+			return models.Errorf(models.ErrInvalidScheduleChange, "schedule %s is %s, not active", schedule.ID, schedule.Status)
+		}
+		schedule.Status = models.SchedulePaused
+		return nil
+	})
+}
+
+// ResumeSchedule reactivates a paused schedule. A recurring schedule carries
+// on from its next occurrence after now; occurrences that fell due while it
+// was paused are not run. A one-off schedule whose time has passed runs as a
+// missed occurrence.
+func (s *ScheduleService) ResumeSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error) {
+	return s.changeSchedule(ctx, scheduleID, "resume_schedule", func(schedule *models.Schedule) error {
+		if schedule.Status != models.SchedulePaused {
+			return models.Errorf(models.ErrInvalidScheduleChange, "schedule %s is %s, not paused", schedule.ID, schedule.Status)
+		}
+		schedule.Status = models.ScheduleActive
+
+		now := time.Now()
+		if !schedule.IsRecurring() || !schedule.NextRunAt.Before(now) {
+			return nil
+		}
+		next, err := schedule.NextAfter(now)
+		if err != nil {
+			return err
+		}
+		schedule.NextRunAt = next
+		if next == nil {
+			schedule.Status = models.ScheduleCompleted
+		}
+		return nil
+	})
+}
+
+// SkipSchedule passes over the schedule's next occurrence without running
+// it. Skipping a one-off schedule's only occurrence completes it.
+func (s *ScheduleService) SkipSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error) {
+	return s.changeSchedule(ctx, scheduleID, "skip_schedule", func(schedule *models.Schedule) error {
+		if err := checkNotFinished(schedule); err != nil {
+			return err
+		}
+		next, err := schedule.NextAfter(*schedule.NextRunAt)
+		if err != nil {
+			return err
+		}
+		schedule.NextRunAt = next
+		schedule.SkippedCount++
+		if next == nil {
+			schedule.Status = models.ScheduleCompleted
+		}
+		return nil
+	})
+}
+
+func checkNotFinished(schedule *models.Schedule) error {
+	if schedule.IsFinished() {
+		return models.Errorf(models.ErrInvalidScheduleChange, "schedule %s is %s", schedule.ID, schedule.Status)
+	}
+	return nil
+}
+
+// changeSchedule applies change to the locked schedule and logs the outcome
+func (s *ScheduleService) changeSchedule(ctx context.Context, scheduleID, operation string, change func(*models.Schedule) error) (*models.Schedule, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "schedule"),
+		slog.String("operation", operation),
+		slog.String("schedule_id", scheduleID))
+
+	schedule, err := s.scheduleStorage.ChangeSchedule(ctx, scheduleID, change)
+	if err != nil {
+		logger.Error("Failed to change schedule", slog.String("error", err.Error()))
+		return nil, err
+	}
+	audit.FromContext(ctx).Account(schedule.AccountID)
+
+	logger.Info("Schedule changed", slog.String("status", schedule.Status))
+	return schedule, nil
+}
+
+// RunDue claims the schedules that have fallen due and runs their
+// occurrences, returning how many transactions were logged. Every instance
+// can call it: a claim keeps other instances off a schedule while it runs,
+// and each occurrence's transaction has a fixed ID, so an occurrence that is
+// run twice (say, after a claim lapses) still logs only one transaction.
+func (s *ScheduleService) RunDue(ctx context.Context) (int, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "schedule"),
+		slog.String("operation", "run_due"))
+
+	now := time.Now()
+	schedules, err := s.scheduleStorage.ClaimDueSchedules(ctx, now, now.Add(scheduleLease), scheduleBatchSize)
+	if err != nil {
+		logger.Error("Failed to claim due schedules", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	var ran int
+	for i := range schedules {
+		n, err := s.runSchedule(ctx, &schedules[i], now)
+		ran += n
+		if err != nil {
+			// The claim lapses and the occurrence is retried on a later pass
+			logger.Error("Failed to run schedule",
+				slog.String("schedule_id", schedules[i].ID),
+				slog.String("error", err.Error()))
+		}
+	}
+
+	if len(schedules) > 0 {
+		logger.Info("Due schedules run",
+			slog.Int("schedules", len(schedules)),
+			slog.Int("transactions", ran))
+	}
+	return ran, nil
+}
+
+// runSchedule works through a claimed schedule's due occurrences
+func (s *ScheduleService) runSchedule(ctx context.Context, schedule *models.Schedule, now time.Time) (int, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "schedule"),
+		slog.String("schedule_id", schedule.ID),
+		slog.String("account_id", schedule.AccountID))
+
+	var ran int
+	for i := 0; i < scheduleMaxRunsPerPass; i++ {
+		if schedule.NextRunAt == nil || schedule.NextRunAt.After(now) {
+			return ran, nil
+		}
+
+		step, err := schedule.Due(now, s.missedGrace)
+		if err != nil {
+			return ran, err
+		}
+
+		var transactionID string
+		if step.Run != nil {
+			transactionID, err = s.createScheduledTransaction(ctx, schedule, *step.Run)
+			if err != nil {
+				return ran, err
+			}
+			if s.processor != nil {
+				s.applyScheduledTransaction(ctx, schedule, transactionID)
+			}
+		}
+
+		advanced, err := s.scheduleStorage.AdvanceSchedule(ctx, schedule.ID, *schedule.NextRunAt, step, transactionID)
+		if err != nil {
+			return ran, err
+		}
+		if !advanced {
+			logger.Info("Schedule changed while running, leaving it for the next pass")
+			return ran, nil
+		}
+
+		if step.Skipped > 0 {
+			logger.Warn("Missed occurrences skipped",
+				slog.Int64("skipped", step.Skipped),
+				slog.String("missed_runs", schedule.MissedRuns))
+		}
+		if step.Run != nil {
+			ran++
+		}
+		schedule.NextRunAt = step.Next
+	}
+
+	return ran, nil
+}
+
+// createScheduledTransaction logs the pending transaction for one occurrence
+// together with its outbox message. The record's ID is derived from the
+// schedule and occurrence, so if it already exists the occurrence has run.
+func (s *ScheduleService) createScheduledTransaction(ctx context.Context, schedule *models.Schedule, occurrence time.Time) (string, error) {
+	transactionID := models.ScheduledTransactionID(schedule.ID, occurrence)
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "schedule"),
+		slog.String("schedule_id", schedule.ID),
+		slog.String("transaction_id", transactionID),
+		slog.Time("occurrence", occurrence))
+
+	// Occurrences the scheduler applies itself are not queued as well
+	now := time.Now()
+	var outbox *models.OutboxEntry
+	if s.processor == nil {
+		entry, err := queue.NewOutboxEntry(ctx, queue.TransactionMessage{
+			ID:        transactionID,
+			AccountID: schedule.AccountID,
+			Type:      schedule.Type,
+			Amount:    schedule.Amount,
+			Currency:  schedule.Currency,
+			Reference: schedule.Description,
+			CreatedAt: now,
+		})
+		if err != nil {
+			return "", err
+		}
+		outbox = entry
+	}
+
+	err := s.transactionStorage.CreateTransaction(ctx, &models.Transaction{
+		ID:            transactionID,
+		TransactionID: transactionID,
+		AccountID:     schedule.AccountID,
+		Type:          schedule.Type,
+		Amount:        schedule.Amount,
+		Currency:      schedule.Currency,
+		Description:   schedule.Description,
+		Timestamp:     now,
+		Status:        "pending",
+		ScheduleID:    schedule.ID,
+		Outbox:        outbox,
+	})
+	if errors.Is(err, models.ErrTransactionExists) {
+		logger.Info("Scheduled transaction already logged")
+		return transactionID, nil
+	}
+	if err != nil {
+		logger.Error("Failed to log scheduled transaction", slog.String("error", err.Error()))
+		return "", err
+	}
+
+	logger.Info("Scheduled transaction queued",
+		slog.String("type", schedule.Type),
+		slog.String("amount", schedule.Amount.String()))
+	return transactionID, nil
+}
+
+// applyScheduledTransaction applies a logged occurrence the way a worker
+// would. An occurrence already settled is left alone, and one that hits a
+// system error is failed, since in sync mode nothing would retry it; a record
+// whose balance change was applied is kept for the reaper.
+func (s *ScheduleService) applyScheduledTransaction(ctx context.Context, schedule *models.Schedule, transactionID string) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "schedule"),
+		slog.String("schedule_id", schedule.ID),
+		slog.String("transaction_id", transactionID))
+
+	_, err := s.processor.ProcessTransactionAsync(ctx, transactionID, &models.TransactionRequest{
+		Type:        schedule.Type,
+		Amount:      schedule.Amount,
+		Currency:    schedule.Currency,
+		Description: schedule.Description,
+	})
+	switch {
+	case err == nil:
+		logger.Info("Scheduled transaction applied")
+	case IsBusinessError(err):
+		// The service has already failed the record, or it was settled before
+		logger.Warn("Scheduled transaction not applied", slog.String("error", err.Error()))
+	default:
+		logger.Error("Failed to apply scheduled transaction", slog.String("error", err.Error()))
+		if failErr := s.processor.FailUnprocessedTransaction(ctx, transactionID, err.Error()); failErr != nil {
+			logger.Error("Failed to fail scheduled transaction", slog.String("error", failErr.Error()))
+		}
+	}
+}
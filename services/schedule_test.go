@@ -0,0 +1,354 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/queue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newScheduleTestService(ctrl *gomock.Controller) (*ScheduleService, *MockAccountStorage, *MockScheduleStorage, *MockTransactionStorage) {
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockScheduleStorage := NewMockScheduleStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewScheduleService(mockAccountStorage, mockScheduleStorage, mockTransactionStorage, time.Hour)
+	return service, mockAccountStorage, mockScheduleStorage, mockTransactionStorage
+}
+
+// expectChangeSchedule runs the service's change against schedule, as the
+// storage does under the row lock
+func expectChangeSchedule(mock *MockScheduleStorage, schedule *models.Schedule) {
+	mock.EXPECT().
+		ChangeSchedule(gomock.Any(), schedule.ID, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, scheduleID string, change func(*models.Schedule) error) (*models.Schedule, error) {
+			if err := change(schedule); err != nil {
+				return nil, err
+			}
+			return schedule, nil
+		})
+}
+
+func TestScheduleService_CreateSchedule_Recurring(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockAccountStorage, mockScheduleStorage, _ := newScheduleTestService(ctrl)
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
+
+	start := time.Date(2030, 3, 15, 12, 0, 0, 0, time.UTC)
+	mockScheduleStorage.EXPECT().
+		CreateSchedule(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, schedule *models.Schedule) error {
+			assert.Equal(t, models.ScheduleActive, schedule.Status)
+			assert.Equal(t, models.MissedRunsLatest, schedule.MissedRuns)
+			assert.Equal(t, "USD", schedule.Currency)
+			assert.Equal(t, time.Date(2030, 4, 1, 0, 0, 0, 0, time.UTC), *schedule.NextRunAt)
+			return nil
+		})
+
+	schedule, err := service.CreateSchedule(context.Background(), "acc_12345", &models.CreateScheduleRequest{
+		Type:   "deposit",
+		Amount: models.MustParseMoney("500.00"),
+		Cron:   "0 0 1 * *",
+		RunAt:  &start,
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, schedule.ID, "sch_")
+}
+
+func TestScheduleService_CreateSchedule_Invalid(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name string
+		req  models.CreateScheduleRequest
+		want error
+	}{
+		{name: "one-off in the past", req: models.CreateScheduleRequest{Type: "withdraw", Amount: models.MustParseMoney("10.00"), RunAt: &past}, want: models.ErrInvalidSchedule},
+		{name: "one-off without run_at", req: models.CreateScheduleRequest{Type: "withdraw", Amount: models.MustParseMoney("10.00")}, want: models.ErrInvalidSchedule},
+		{name: "bad cron", req: models.CreateScheduleRequest{Type: "deposit", Amount: models.MustParseMoney("10.00"), Cron: "every day"}, want: models.ErrInvalidSchedule},
+		{name: "bad missed runs policy", req: models.CreateScheduleRequest{Type: "deposit", Amount: models.MustParseMoney("10.00"), Cron: "@daily", MissedRuns: "some"}, want: models.ErrInvalidSchedule},
+		{name: "currency mismatch", req: models.CreateScheduleRequest{Type: "deposit", Amount: models.MustParseMoney("10.00"), Currency: "EUR", Cron: "@daily"}, want: models.ErrCurrencyMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, mockAccountStorage, _, _ := newScheduleTestService(ctrl)
+			expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
+
+			_, err := service.CreateSchedule(context.Background(), "acc_12345", &tt.req)
+			assert.True(t, errors.Is(err, tt.want), "got %v", err)
+		})
+	}
+}
+
+func TestScheduleService_RunDue_QueuesOccurrence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, mockScheduleStorage, mockTransactionStorage := newScheduleTestService(ctrl)
+
+	due := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	mockScheduleStorage.EXPECT().
+		ClaimDueSchedules(gomock.Any(), gomock.Any(), gomock.Any(), scheduleBatchSize).
+		Return([]models.Schedule{{
+			ID:          "sch_12345",
+			AccountID:   "acc_12345",
+			Type:        "deposit",
+			Amount:      models.MustParseMoney("500.00"),
+			Currency:    "USD",
+			Description: "Salary",
+			Cron:        "@monthly",
+			MissedRuns:  models.MissedRunsLatest,
+			Status:      models.ScheduleActive,
+			NextRunAt:   &due,
+		}}, nil)
+
+	transactionID := models.ScheduledTransactionID("sch_12345", due)
+	mockTransactionStorage.EXPECT().
+		CreateTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, transaction *models.Transaction) error {
+			assert.Equal(t, transactionID, transaction.ID)
+			assert.Equal(t, "pending", transaction.Status)
+			assert.Equal(t, "sch_12345", transaction.ScheduleID)
+
+			// The worker gets it through the outbox like any queued request
+			msg, err := queue.DecodeOutboxEntry(transaction.Outbox)
+			require.NoError(t, err)
+			assert.Equal(t, transactionID, msg.ID)
+			assert.Equal(t, "deposit", msg.Type)
+			assert.Equal(t, models.MustParseMoney("500.00"), msg.Amount)
+			return nil
+		})
+	mockScheduleStorage.EXPECT().
+		AdvanceSchedule(gomock.Any(), "sch_12345", due, gomock.Any(), transactionID).
+		DoAndReturn(func(ctx context.Context, scheduleID string, from time.Time, step *models.ScheduleStep, transactionID string) (bool, error) {
+			assert.Equal(t, due, *step.Run)
+			assert.True(t, step.Next.After(time.Now()))
+			return true, nil
+		})
+
+	ran, err := service.RunDue(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, ran)
+}
+
+func TestScheduleService_RunDue_OccurrenceAlreadyLogged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, mockScheduleStorage, mockTransactionStorage := newScheduleTestService(ctrl)
+
+	// Another instance logged the occurrence but died before advancing
+	due := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	mockScheduleStorage.EXPECT().
+		ClaimDueSchedules(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]models.Schedule{{ID: "sch_12345", AccountID: "acc_12345", Type: "withdraw", Amount: models.MustParseMoney("20.00"),
+			MissedRuns: models.MissedRunsLatest, Status: models.ScheduleActive, NextRunAt: &due}}, nil)
+	mockTransactionStorage.EXPECT().
+		CreateTransaction(gomock.Any(), gomock.Any()).
+		Return(models.Errorf(models.ErrTransactionExists, "transaction already exists"))
+	mockScheduleStorage.EXPECT().
+		AdvanceSchedule(gomock.Any(), "sch_12345", due, gomock.Any(), models.ScheduledTransactionID("sch_12345", due)).
+		DoAndReturn(func(ctx context.Context, scheduleID string, from time.Time, step *models.ScheduleStep, transactionID string) (bool, error) {
+			assert.Nil(t, step.Next) // The one-off is completed
+			return true, nil
+		})
+
+	ran, err := service.RunDue(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, ran)
+}
+
+func TestScheduleService_RunDue_AppliesOccurrenceInSyncMode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, mockScheduleStorage, mockTransactionStorage := newScheduleTestService(ctrl)
+	mockProcessor := NewMockTransactionProcessor(ctrl)
+	service.SetProcessor(mockProcessor)
+
+	due := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	schedule := models.Schedule{ID: "sch_12345", AccountID: "acc_12345", Type: "deposit", Amount: models.MustParseMoney("500.00"),
+		Currency: "USD", Description: "Salary", MissedRuns: models.MissedRunsLatest, Status: models.ScheduleActive, NextRunAt: &due}
+	mockScheduleStorage.EXPECT().
+		ClaimDueSchedules(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]models.Schedule{schedule}, nil)
+
+	// With no relay to publish it, the record is not queued
+	transactionID := models.ScheduledTransactionID("sch_12345", due)
+	mockTransactionStorage.EXPECT().
+		CreateTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, transaction *models.Transaction) error {
+			assert.Equal(t, "pending", transaction.Status)
+			assert.Nil(t, transaction.Outbox)
+			return nil
+		})
+	mockProcessor.EXPECT().
+		ProcessTransactionAsync(gomock.Any(), transactionID, &models.TransactionRequest{
+			Type: "deposit", Amount: models.MustParseMoney("500.00"), Currency: "USD", Description: "Salary"}).
+		Return(&models.Transaction{TransactionID: transactionID, Status: "completed"}, nil)
+	mockScheduleStorage.EXPECT().
+		AdvanceSchedule(gomock.Any(), "sch_12345", due, gomock.Any(), transactionID).
+		Return(true, nil)
+
+	ran, err := service.RunDue(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, ran)
+}
+
+func TestScheduleService_RunDue_FailsOccurrenceOnSystemErrorInSyncMode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, mockScheduleStorage, mockTransactionStorage := newScheduleTestService(ctrl)
+	mockProcessor := NewMockTransactionProcessor(ctrl)
+	service.SetProcessor(mockProcessor)
+
+	due := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	mockScheduleStorage.EXPECT().
+		ClaimDueSchedules(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]models.Schedule{{ID: "sch_12345", AccountID: "acc_12345", Type: "withdraw", Amount: models.MustParseMoney("20.00"),
+			MissedRuns: models.MissedRunsLatest, Status: models.ScheduleActive, NextRunAt: &due}}, nil)
+
+	transactionID := models.ScheduledTransactionID("sch_12345", due)
+	mockTransactionStorage.EXPECT().CreateTransaction(gomock.Any(), gomock.Any()).Return(nil)
+	mockProcessor.EXPECT().
+		ProcessTransactionAsync(gomock.Any(), transactionID, gomock.Any()).
+		Return(nil, errors.New("connection refused"))
+
+	// Nothing would retry it in sync mode, so it is failed rather than left pending
+	mockProcessor.EXPECT().
+		FailUnprocessedTransaction(gomock.Any(), transactionID, "connection refused").
+		Return(nil)
+	mockScheduleStorage.EXPECT().
+		AdvanceSchedule(gomock.Any(), "sch_12345", due, gomock.Any(), transactionID).
+		Return(true, nil)
+
+	ran, err := service.RunDue(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, ran)
+}
+
+func TestScheduleService_RunDue_CatchesUpMissedRuns(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, mockScheduleStorage, mockTransactionStorage := newScheduleTestService(ctrl)
+
+	// Down for over two hours with an hourly schedule that runs every missed occurrence
+	due := time.Now().UTC().Truncate(time.Hour).Add(-2 * time.Hour)
+	mockScheduleStorage.EXPECT().
+		ClaimDueSchedules(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]models.Schedule{{ID: "sch_12345", AccountID: "acc_12345", Type: "deposit", Amount: models.MustParseMoney("1.00"),
+			Cron: "@hourly", MissedRuns: models.MissedRunsAll, Status: models.ScheduleActive, NextRunAt: &due}}, nil)
+
+	var logged []string
+	mockTransactionStorage.EXPECT().
+		CreateTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, transaction *models.Transaction) error {
+			logged = append(logged, transaction.ID)
+			return nil
+		}).Times(3)
+	mockScheduleStorage.EXPECT().AdvanceSchedule(gomock.Any(), "sch_12345", gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).Times(3)
+
+	ran, err := service.RunDue(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, ran)
+	assert.Equal(t, []string{
+		models.ScheduledTransactionID("sch_12345", due),
+		models.ScheduledTransactionID("sch_12345", due.Add(time.Hour)),
+		models.ScheduledTransactionID("sch_12345", due.Add(2*time.Hour)),
+	}, logged)
+}
+
+func TestScheduleService_RunDue_ChangedWhileRunning(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, mockScheduleStorage, mockTransactionStorage := newScheduleTestService(ctrl)
+
+	due := time.Now().Add(-3 * time.Hour).UTC().Truncate(time.Hour)
+	mockScheduleStorage.EXPECT().
+		ClaimDueSchedules(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]models.Schedule{{ID: "sch_12345", AccountID: "acc_12345", Type: "deposit", Amount: models.MustParseMoney("1.00"),
+			Cron: "@hourly", MissedRuns: models.MissedRunsAll, Status: models.ScheduleActive, NextRunAt: &due}}, nil)
+	mockTransactionStorage.EXPECT().CreateTransaction(gomock.Any(), gomock.Any()).Return(nil)
+
+	// Rescheduled by an operator meanwhile: the pass stops there
+	mockScheduleStorage.EXPECT().AdvanceSchedule(gomock.Any(), "sch_12345", due, gomock.Any(), gomock.Any()).Return(false, nil)
+
+	ran, err := service.RunDue(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, ran)
+}
+
+func TestScheduleService_PauseResume(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, mockScheduleStorage, _ := newScheduleTestService(ctrl)
+
+	next := time.Now().Add(-48 * time.Hour).UTC().Truncate(time.Hour)
+	schedule := &models.Schedule{ID: "sch_12345", Cron: "@daily", MissedRuns: models.MissedRunsLatest, Status: models.ScheduleActive, NextRunAt: &next}
+
+	expectChangeSchedule(mockScheduleStorage, schedule)
+	paused, err := service.PauseSchedule(context.Background(), "sch_12345")
+	require.NoError(t, err)
+	assert.Equal(t, models.SchedulePaused, paused.Status)
+
+	expectChangeSchedule(mockScheduleStorage, schedule)
+	_, err = service.PauseSchedule(context.Background(), "sch_12345")
+	assert.True(t, errors.Is(err, models.ErrInvalidScheduleChange))
+
+	// Occurrences that fell due while paused are not run
+	expectChangeSchedule(mockScheduleStorage, schedule)
+	resumed, err := service.ResumeSchedule(context.Background(), "sch_12345")
+	require.NoError(t, err)
+	assert.Equal(t, models.ScheduleActive, resumed.Status)
+	assert.True(t, resumed.NextRunAt.After(time.Now()))
+}
+
+func TestScheduleService_SkipSchedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, mockScheduleStorage, _ := newScheduleTestService(ctrl)
+
+	next := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	recurring := &models.Schedule{ID: "sch_12345", Cron: "@monthly", Status: models.ScheduleActive, NextRunAt: &next}
+	expectChangeSchedule(mockScheduleStorage, recurring)
+
+	skipped, err := service.SkipSchedule(context.Background(), "sch_12345")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2030, 2, 1, 0, 0, 0, 0, time.UTC), *skipped.NextRunAt)
+	assert.Equal(t, int64(1), skipped.SkippedCount)
+
+	// Skipping a one-off schedule's only occurrence completes it
+	oneOff := &models.Schedule{ID: "sch_67890", Status: models.ScheduleActive, NextRunAt: &next}
+	expectChangeSchedule(mockScheduleStorage, oneOff)
+
+	skipped, err = service.SkipSchedule(context.Background(), "sch_67890")
+	require.NoError(t, err)
+	assert.Equal(t, models.ScheduleCompleted, skipped.Status)
+	assert.Nil(t, skipped.NextRunAt)
+
+	expectChangeSchedule(mockScheduleStorage, oneOff)
+	_, err = service.CancelSchedule(context.Background(), "sch_67890")
+	assert.True(t, errors.Is(err, models.ErrInvalidScheduleChange))
+}
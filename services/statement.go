@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/utils"
+)
+
+// MaxStatementPeriod bounds the date range of a single statement
+const MaxStatementPeriod = 366 * 24 * time.Hour
+
+// GetBalanceAsOf returns an account's balance after every settled transaction
+// logged before asOf
+func (s *TransactionService) GetBalanceAsOf(ctx context.Context, accountID string, asOf time.Time) (*models.HistoricalBalance, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "transaction"),
+		slog.String("operation", "get_balance_as_of"),
+		slog.String("account_id", accountID),
+		slog.Time("as_of", asOf))
+
+	logger.Info("Getting balance as of a point in time")
+
+	account, err := s.accountStorage.GetAccountByID(ctx, accountID)
+	if err != nil {
+		logger.Error("Account not found for balance as of", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	balance, err := s.balanceBefore(ctx, account, asOf)
+	if err != nil {
+		logger.Error("Failed to work out historical balance", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to get balance as of %s: %w", asOf.Format(time.RFC3339), err)
+	}
+
+	logger.Info("Historical balance retrieved", slog.String("balance", balance.String()))
+
+	return &models.HistoricalBalance{
+		AccountID: accountID,
+		Currency:  accountCurrency(account),
+		AsOf:      asOf,
+		Balance:   balance,
+	}, nil
+}
+
+// GetStatement builds a statement of the settled transactions logged in
+// [from, to), with opening and closing balances and totals by type
+func (s *TransactionService) GetStatement(ctx context.Context, accountID string, from, to time.Time) (*models.Statement, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "transaction"),
+		slog.String("operation", "get_statement"),
+		slog.String("account_id", accountID),
+		slog.Time("from", from),
+		slog.Time("to", to))
+
+	logger.Info("Generating account statement")
+
+	if !from.Before(to) {
+		return nil, models.Errorf(models.ErrInvalidRequest, "statement start must be before its end")
+	}
+	if to.Sub(from) > MaxStatementPeriod {
+		return nil, models.Errorf(models.ErrInvalidRequest, "statement period cannot be longer than %d days", int(MaxStatementPeriod.Hours()/24))
+	}
+
+	account, err := s.accountStorage.GetAccountByID(ctx, accountID)
+	if err != nil {
+		logger.Error("Account not found for statement", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	opening, err := s.balanceBefore(ctx, account, from)
+	if err != nil {
+		logger.Error("Failed to work out opening balance", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to get opening balance: %w", err)
+	}
+
+	transactions, err := s.transactionStorage.GetTransactionsInRange(ctx, accountID, from, to)
+	if err != nil {
+		logger.Error("Failed to get statement transactions", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to get statement transactions: %w", err)
+	}
+
+	statement := &models.Statement{
+		AccountID:      accountID,
+		OwnerName:      account.OwnerName,
+		Currency:       accountCurrency(account),
+		From:           from,
+		To:             to,
+		OpeningBalance: opening,
+		ClosingBalance: opening,
+		Totals:         []models.StatementTotal{},
+		Lines:          make([]models.StatementLine, 0, len(transactions)),
+		GeneratedAt:    time.Now(),
+	}
+
+	totals := make(map[string]*models.StatementTotal)
+	for i := range transactions {
+		transaction := &transactions[i]
+		statement.Lines = append(statement.Lines, models.StatementLine{
+			Timestamp:     transaction.Timestamp,
+			TransactionID: transaction.TransactionID,
+			Type:          transaction.Type,
+			Description:   transaction.Description,
+			Amount:        transaction.SignedAmount(),
+			Balance:       transaction.NewBalance,
+			Status:        transaction.Status,
+		})
+		statement.ClosingBalance = transaction.NewBalance
+
+		total, ok := totals[transaction.Type]
+		if !ok {
+			total = &models.StatementTotal{Type: transaction.Type}
+			totals[transaction.Type] = total
+		}
+		total.Count++
+		total.Amount = total.Amount.Add(transaction.Amount)
+	}
+
+	for _, total := range totals {
+		statement.Totals = append(statement.Totals, *total)
+	}
+	sort.Slice(statement.Totals, func(i, j int) bool {
+		return statement.Totals[i].Type < statement.Totals[j].Type
+	})
+
+	logger.Info("Account statement generated",
+		slog.Int("line_count", len(statement.Lines)),
+		slog.String("opening_balance", statement.OpeningBalance.String()),
+		slog.String("closing_balance", statement.ClosingBalance.String()))
+
+	return statement, nil
+}
+
+// balanceBefore reads an account's balance just before t from the log: the
+// new balance of the last transaction before t, or else the previous balance
+// of the first one after it. An account with no transactions still holds its
+// opening balance, which was never logged.
+func (s *TransactionService) balanceBefore(ctx context.Context, account *models.Account, t time.Time) (models.Money, error) {
+	if !account.CreatedAt.IsZero() && !account.CreatedAt.Before(t) {
+		return 0, nil
+	}
+
+	last, err := s.transactionStorage.GetLatestTransactionBefore(ctx, account.ID, t)
+	if err != nil {
+		return 0, err
+	}
+	if last != nil {
+		return last.NewBalance, nil
+	}
+
+	next, err := s.transactionStorage.GetEarliestTransactionFrom(ctx, account.ID, t)
+	if err != nil {
+		return 0, err
+	}
+	if next != nil {
+		return next.PreviousBalance, nil
+	}
+
+	return account.Balance, nil
+}
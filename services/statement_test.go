@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var statementAccount = &models.Account{
+	ID:        "acc_12345",
+	OwnerName: "John Doe",
+	Balance:   models.MustParseMoney("500.00"),
+	Currency:  "EUR",
+	CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+}
+
+func TestTransactionService_GetBalanceAsOf(t *testing.T) {
+	asOf := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		last    *models.Transaction
+		next    *models.Transaction
+		asOf    time.Time
+		balance models.Money
+	}{
+		{
+			name:    "last transaction before",
+			last:    &models.Transaction{NewBalance: models.MustParseMoney("320.00")},
+			asOf:    asOf,
+			balance: models.MustParseMoney("320.00"),
+		},
+		{
+			name:    "only transactions after",
+			next:    &models.Transaction{PreviousBalance: models.MustParseMoney("250.00")},
+			asOf:    asOf,
+			balance: models.MustParseMoney("250.00"),
+		},
+		{
+			name:    "no transactions holds the opening balance",
+			asOf:    asOf,
+			balance: models.MustParseMoney("500.00"),
+		},
+		{
+			name:    "before the account was opened",
+			asOf:    time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+			balance: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAccountStorage := NewMockAccountStorage(ctrl)
+			mockTransactionStorage := NewMockTransactionStorage(ctrl)
+			service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+			ctx := context.Background()
+
+			mockAccountStorage.EXPECT().GetAccountByID(ctx, "acc_12345").Return(statementAccount, nil)
+			mockTransactionStorage.EXPECT().GetLatestTransactionBefore(ctx, "acc_12345", tt.asOf).Return(tt.last, nil).MaxTimes(1)
+			mockTransactionStorage.EXPECT().GetEarliestTransactionFrom(ctx, "acc_12345", tt.asOf).Return(tt.next, nil).MaxTimes(1)
+
+			balance, err := service.GetBalanceAsOf(ctx, "acc_12345", tt.asOf)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.balance, balance.Balance)
+			assert.Equal(t, "EUR", balance.Currency)
+		})
+	}
+}
+
+func TestTransactionService_GetStatement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	ctx := context.Background()
+
+	from := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	mockAccountStorage.EXPECT().GetAccountByID(ctx, "acc_12345").Return(statementAccount, nil)
+	mockTransactionStorage.EXPECT().
+		GetLatestTransactionBefore(ctx, "acc_12345", from).
+		Return(&models.Transaction{NewBalance: models.MustParseMoney("100.00")}, nil)
+	mockTransactionStorage.EXPECT().
+		GetTransactionsInRange(ctx, "acc_12345", from, to).
+		Return([]models.Transaction{
+			{TransactionID: "txn_1", Type: "deposit", Amount: models.MustParseMoney("50.00"), NewBalance: models.MustParseMoney("150.00"), Status: "reversed"},
+			{TransactionID: "txn_2", Type: "transfer_out", Amount: models.MustParseMoney("30.00"), NewBalance: models.MustParseMoney("120.00"), Status: "completed"},
+			{TransactionID: "txn_3", Type: "withdraw", Amount: models.MustParseMoney("50.00"), NewBalance: models.MustParseMoney("70.00"), Status: "completed", ReversalOf: "txn_1"},
+			{TransactionID: "txn_4", Type: "deposit", Amount: models.MustParseMoney("10.00"), NewBalance: models.MustParseMoney("80.00"), Status: "completed"},
+		}, nil)
+
+	statement, err := service.GetStatement(ctx, "acc_12345", from, to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.MustParseMoney("100.00"), statement.OpeningBalance)
+	assert.Equal(t, models.MustParseMoney("80.00"), statement.ClosingBalance)
+	assert.Equal(t, "John Doe", statement.OwnerName)
+	assert.Equal(t, []models.StatementTotal{
+		{Type: "deposit", Count: 2, Amount: models.MustParseMoney("60.00")},
+		{Type: "transfer_out", Count: 1, Amount: models.MustParseMoney("30.00")},
+		{Type: "withdraw", Count: 1, Amount: models.MustParseMoney("50.00")},
+	}, statement.Totals)
+	assert.Len(t, statement.Lines, 4)
+	assert.Equal(t, models.MustParseMoney("-30.00"), statement.Lines[1].Amount)
+	assert.Equal(t, "reversed", statement.Lines[0].Status)
+}
+
+func TestTransactionService_GetStatement_InvalidPeriod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewTransactionService(NewMockAccountStorage(ctrl), NewMockTransactionStorage(ctrl))
+	from := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := service.GetStatement(context.Background(), "acc_12345", from, from)
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+
+	_, err = service.GetStatement(context.Background(), "acc_12345", from, from.AddDate(2, 0, 0))
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+}
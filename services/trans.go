@@ -2,17 +2,25 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
 
+	"github.com/appy29/banking-ledger-service/audit"
+	"github.com/appy29/banking-ledger-service/metrics"
 	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/tracing"
 	"github.com/appy29/banking-ledger-service/utils"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type TransactionService struct {
 	accountStorage     AccountStorage
 	transactionStorage TransactionStorage
+	limits             models.TransactionLimits // Defaults for accounts without overrides
+	notifier           EventNotifier            // Told about completed and failed transactions, if set
 }
 
 func NewTransactionService(accountStorage AccountStorage, transactionStorage TransactionStorage) *TransactionService {
@@ -22,6 +30,12 @@ func NewTransactionService(accountStorage AccountStorage, transactionStorage Tra
 	}
 }
 
+// SetNotifier sets where webhook events for completed and failed
+// transactions are sent
+func (s *TransactionService) SetNotifier(notifier EventNotifier) {
+	s.notifier = notifier
+}
+
 // GetAccountByID provides access to account information for validation
 func (s *TransactionService) GetAccountByID(ctx context.Context, accountID string) (*models.Account, error) {
 	logger := utils.LoggerFromContext(ctx).With(
@@ -37,12 +51,17 @@ func (s *TransactionService) GetAccountByID(ctx context.Context, accountID strin
 		return nil, err
 	}
 
-	logger.Info("Account retrieved successfully", slog.Float64("balance", account.Balance))
+	logger.Info("Account retrieved successfully", slog.String("balance", account.Balance.String()))
 	return account, nil
 }
 
 // CreatePendingTransaction saves a transaction with "pending" status
 func (s *TransactionService) CreatePendingTransaction(ctx context.Context, transaction *models.Transaction) error {
+	ctx, span := tracing.Start(ctx, "TransactionService.CreatePendingTransaction", trace.SpanKindInternal,
+		attribute.String("ledger.transaction.id", transaction.TransactionID),
+		attribute.String("ledger.transaction.type", transaction.Type))
+	defer span.End()
+
 	logger := utils.LoggerFromContext(ctx).With(
 		slog.String("service", "transaction"),
 		slog.String("operation", "create_pending_transaction"),
@@ -50,13 +69,15 @@ func (s *TransactionService) CreatePendingTransaction(ctx context.Context, trans
 
 	logger.Info("Creating pending transaction",
 		slog.String("type", transaction.Type),
-		slog.Float64("amount", transaction.Amount))
+		slog.String("amount", transaction.Amount.String()))
 
 	err := s.transactionStorage.CreateTransaction(ctx, transaction)
 	if err != nil {
 		logger.Error("Failed to create pending transaction", slog.String("error", err.Error()))
+		tracing.RecordError(span, err)
 		return err
 	}
+	audit.FromContext(ctx).Target(audit.TargetTransaction, transaction.TransactionID)
 
 	logger.Info("Pending transaction created successfully")
 	return nil
@@ -102,8 +123,74 @@ func (s *TransactionService) UpdateTransactionStatusWithError(ctx context.Contex
 	return nil
 }
 
-// ProcessTransaction - Updated to use atomic balance operations
+// FailUnprocessedTransaction marks a queued transaction failed once the worker
+// has given up on it. A record whose balance change was applied is left pending
+// for the reaper to complete.
+func (s *TransactionService) FailUnprocessedTransaction(ctx context.Context, transactionID, errorMessage string) error {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "transaction"),
+		slog.String("operation", "fail_unprocessed_transaction"),
+		slog.String("transaction_id", transactionID))
+
+	transaction, err := s.GetTransactionByID(ctx, transactionID)
+	if err != nil {
+		return err
+	}
+	if transaction.Status != "pending" {
+		return nil
+	}
+
+	applied, err := s.accountStorage.GetAppliedTransaction(ctx, transactionID)
+	if err != nil {
+		return fmt.Errorf("failed to check applied transaction: %w", err)
+	}
+	if applied != nil {
+		logger.Warn("Transaction was applied; leaving it for the pending reaper")
+		return nil
+	}
+
+	return s.failTransaction(ctx, transaction, errorMessage)
+}
+
+// failIfPermanent marks a queued transaction failed when retrying cannot help;
+// after a system error it stays pending for the worker's next attempt
+func (s *TransactionService) failIfPermanent(ctx context.Context, transaction *models.Transaction, err error) {
+	if IsBusinessError(err) {
+		s.failTransaction(ctx, transaction, err.Error())
+	}
+}
+
+// failTransaction marks a queued transaction failed and tells webhook
+// subscribers
+func (s *TransactionService) failTransaction(ctx context.Context, transaction *models.Transaction, errorMessage string) error {
+	if err := s.UpdateTransactionStatusWithError(ctx, transaction.TransactionID, "failed", errorMessage); err != nil {
+		return err
+	}
+
+	if s.notifier != nil {
+		failed := *transaction
+		failed.Status = "failed"
+		failed.ErrorMessage = errorMessage
+		s.notifier.TransactionFailed(ctx, &failed)
+	}
+	return nil
+}
+
+// ProcessTransaction posts a deposit or withdrawal as a two-leg journal entry
+// against the cash account and writes it to the transaction log
 func (s *TransactionService) ProcessTransaction(ctx context.Context, accountID string, req *models.TransactionRequest) (*models.Transaction, error) {
+	ctx, span := tracing.Start(ctx, "TransactionService.ProcessTransaction", trace.SpanKindInternal,
+		attribute.String("ledger.account.id", accountID),
+		attribute.String("ledger.transaction.type", req.Type))
+	defer span.End()
+
+	transaction, err := s.processTransaction(ctx, accountID, req)
+	observeTransaction(req.Type, metrics.ModeSync, err)
+	tracing.RecordError(span, err)
+	return transaction, err
+}
+
+func (s *TransactionService) processTransaction(ctx context.Context, accountID string, req *models.TransactionRequest) (*models.Transaction, error) {
 	logger := utils.LoggerFromContext(ctx).With(
 		slog.String("service", "transaction"),
 		slog.String("operation", "process_transaction_sync"),
@@ -111,63 +198,102 @@ func (s *TransactionService) ProcessTransaction(ctx context.Context, accountID s
 
 	logger.Info("Starting synchronous transaction processing",
 		slog.String("type", req.Type),
-		slog.Float64("amount", req.Amount))
+		slog.String("amount", req.Amount.String()))
 
 	// Validate request
 	if err := s.validateTransactionRequest(ctx, req); err != nil {
 		return nil, err
 	}
 
-	// Use atomic balance update instead of read-calculate-write pattern
-	previousBalance, newBalance, err := s.accountStorage.AtomicBalanceUpdate(ctx, accountID, req.Type, req.Amount)
+	account, currency, err := s.resolveAccount(ctx, accountID, req.Currency, req.Amount)
 	if err != nil {
-		logger.Error("Atomic balance update failed", slog.String("error", err.Error()))
+		logger.Error("Currency check failed", slog.String("error", err.Error()))
 		return nil, fmt.Errorf("failed to process transaction: %w", err)
 	}
 
-	logger.Info("Balance updated atomically",
-		slog.Float64("previous_balance", previousBalance),
-		slog.Float64("new_balance", newBalance))
+	limits, err := s.checkLimits(ctx, account, req.Type, "", req.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process transaction: %w", err)
+	}
+
+	// A claimed Idempotency-Key reserves the ID up front
+	transactionID := req.TransactionID
+	if transactionID == "" {
+		transactionID = models.NewTransactionID()
+	}
+	logger = logger.With(slog.String("transaction_id", transactionID))
+
+	entry, err := models.NewTransactionEntry(transactionID, accountID, req.Type, req.Amount, currency, req.Description)
+	if err != nil {
+		return nil, err
+	}
+	entry.Limits = limits
+
+	changes, err := s.accountStorage.PostJournalEntry(ctx, entry)
+	if err != nil {
+		logger.Error("Failed to post journal entry", slog.String("error", err.Error()))
+		err = s.limitBreached(ctx, accountID, req.Type, "", req.Amount, err)
+		return nil, fmt.Errorf("failed to process transaction: %w", err)
+	}
+	change := balanceChange(changes, accountID)
+
+	logger.Info("Journal entry posted",
+		slog.String("journal_entry_id", entry.ID),
+		slog.String("previous_balance", change.PreviousBalance.String()),
+		slog.String("new_balance", change.NewBalance.String()))
 
-	// Create transaction record
 	transaction := &models.Transaction{
-		ID:              models.NewTransactionID(),
-		TransactionID:   models.NewTransactionID(),
+		ID:              transactionID,
+		TransactionID:   transactionID,
 		AccountID:       accountID,
 		Type:            req.Type,
 		Amount:          req.Amount,
-		PreviousBalance: previousBalance,
-		NewBalance:      newBalance,
+		PreviousBalance: change.PreviousBalance,
+		NewBalance:      change.NewBalance,
+		Currency:        currency,
 		Description:     req.Description,
 		Timestamp:       time.Now(),
 		Status:          "completed",
+		IdempotencyKey:  req.IdempotencyKey,
 	}
 
-	logger = logger.With(slog.String("transaction_id", transaction.TransactionID))
-	logger.Info("Creating transaction record")
-
 	// Save transaction to MongoDB
 	if err := s.transactionStorage.CreateTransaction(ctx, transaction); err != nil {
-		logger.Error("Failed to save transaction, rolling back balance",
-			slog.String("error", err.Error()),
-			slog.Float64("rollback_balance", previousBalance))
-
-		// Rollback balance update by reversing the transaction
-		rollbackType := "withdraw"
-		if req.Type == "withdraw" {
-			rollbackType = "deposit"
-		}
-		s.accountStorage.AtomicBalanceUpdate(ctx, accountID, rollbackType, req.Amount)
+		logger.Error("Failed to save transaction, reversing journal entry", slog.String("error", err.Error()))
+		s.reverseEntry(ctx, entry)
 		return nil, fmt.Errorf("failed to save transaction: %w", err)
 	}
 
+	auditEntry := audit.FromContext(ctx)
+	auditEntry.Target(audit.TargetTransaction, transactionID)
+	auditEntry.Balances(changes)
+
+	if s.notifier != nil {
+		s.notifier.TransactionCompleted(ctx, transaction)
+	}
+
 	logger.Info("Synchronous transaction completed successfully",
-		slog.Float64("final_balance", newBalance))
+		slog.String("final_balance", change.NewBalance.String()))
 	return transaction, nil
 }
 
 // ProcessTransactionAsync - Updated to use atomic operations for pending transactions
 func (s *TransactionService) ProcessTransactionAsync(ctx context.Context, transactionID string, req *models.TransactionRequest) (*models.Transaction, error) {
+	ctx, span := tracing.Start(ctx, "TransactionService.ProcessTransactionAsync", trace.SpanKindInternal,
+		attribute.String("ledger.transaction.id", transactionID),
+		attribute.String("ledger.transaction.type", req.Type))
+	defer span.End()
+
+	transaction, err := s.processTransactionAsync(ctx, transactionID, req)
+	observeTransaction(req.Type, metrics.ModeAsync, err)
+	tracing.RecordError(span, err)
+	if err == nil {
+		metrics.ObserveSince(metrics.PendingTransactionAge.WithLabelValues(req.Type), transaction.Timestamp)
+	}
+	return transaction, err
+}
+
+func (s *TransactionService) processTransactionAsync(ctx context.Context, transactionID string, req *models.TransactionRequest) (*models.Transaction, error) {
 	logger := utils.LoggerFromContext(ctx).With(
 		slog.String("service", "transaction"),
 		slog.String("operation", "process_transaction_async"),
@@ -175,7 +301,7 @@ func (s *TransactionService) ProcessTransactionAsync(ctx context.Context, transa
 
 	logger.Info("Starting async transaction processing",
 		slog.String("type", req.Type),
-		slog.Float64("amount", req.Amount))
+		slog.String("amount", req.Amount.String()))
 
 	// Get the pending transaction
 	transaction, err := s.GetTransactionByID(ctx, transactionID)
@@ -186,28 +312,49 @@ func (s *TransactionService) ProcessTransactionAsync(ctx context.Context, transa
 
 	if transaction.Status != "pending" {
 		logger.Error("Transaction not in pending state", slog.String("current_status", transaction.Status))
-		return nil, fmt.Errorf("transaction is not in pending state: %s", transaction.Status)
+		return nil, models.Errorf(models.ErrNotPending, "transaction is not in pending state: %s", transaction.Status)
 	}
 
 	logger.Info("Retrieved pending transaction", slog.String("account_id", transaction.AccountID))
 
 	// Validate transaction request
 	if err := s.validateTransactionRequest(ctx, req); err != nil {
-		s.UpdateTransactionStatusWithError(ctx, transactionID, "failed", err.Error())
+		s.failTransaction(ctx, transaction, err.Error())
 		return nil, err
 	}
 
-	// Use atomic balance update for async processing
-	previousBalance, newBalance, err := s.accountStorage.AtomicBalanceUpdate(ctx, transaction.AccountID, req.Type, req.Amount)
+	account, currency, err := s.resolveAccount(ctx, transaction.AccountID, req.Currency, req.Amount)
+	if err != nil {
+		logger.Error("Currency check failed", slog.String("error", err.Error()))
+		s.failIfPermanent(ctx, transaction, err)
+		return nil, fmt.Errorf("failed to process transaction: %w", err)
+	}
+
+	limits, err := s.checkLimits(ctx, account, req.Type, transactionID, req.Amount)
+	if err != nil {
+		s.failIfPermanent(ctx, transaction, err)
+		return nil, fmt.Errorf("failed to process transaction: %w", err)
+	}
+
+	// Apply the balance change and record it under the transaction ID, so the
+	// pending reaper can tell whether it happened
+	previousBalance, newBalance, err := s.accountStorage.ApplyTransaction(ctx, transactionID, transaction.AccountID, req.Type, req.Amount, limits)
 	if err != nil {
+		err = s.limitBreached(ctx, transaction.AccountID, req.Type, transactionID, req.Amount, err)
+		if errors.Is(err, models.ErrAlreadyApplied) {
+			// A redelivered message; the balance moved on the first delivery,
+			// so leave the record for the pending reaper to complete
+			logger.Warn("Transaction was already applied", slog.String("error", err.Error()))
+			return nil, fmt.Errorf("failed to update balance: %w", err)
+		}
 		logger.Error("Async atomic balance update failed", slog.String("error", err.Error()))
-		s.UpdateTransactionStatusWithError(ctx, transactionID, "failed", err.Error())
+		s.failIfPermanent(ctx, transaction, err)
 		return nil, fmt.Errorf("failed to update balance: %w", err)
 	}
 
 	logger.Info("Balance calculated for async transaction",
-		slog.Float64("previous_balance", previousBalance),
-		slog.Float64("new_balance", newBalance))
+		slog.String("previous_balance", previousBalance.String()),
+		slog.String("new_balance", newBalance.String()))
 
 	// Update transaction record with final values
 	updatedTransaction := &models.Transaction{
@@ -218,33 +365,70 @@ func (s *TransactionService) ProcessTransactionAsync(ctx context.Context, transa
 		Amount:          transaction.Amount,
 		PreviousBalance: previousBalance,
 		NewBalance:      newBalance,
+		Currency:        currency,
 		Description:     transaction.Description,
 		Timestamp:       transaction.Timestamp,
 		Status:          "completed",
 		ErrorMessage:    "",
+		IdempotencyKey:  transaction.IdempotencyKey,
 	}
 
 	logger.Info("Updating transaction to completed status")
 	if err := s.transactionStorage.UpdateTransaction(ctx, updatedTransaction); err != nil {
+		if errors.Is(err, models.ErrTransactionSealed) {
+			// The pending reaper completed the record from applied_transactions
+			// first; the balance change stands and there is nothing to retry
+			logger.Warn("Transaction was already completed by the reaper")
+			if completed, getErr := s.transactionStorage.GetTransactionByID(ctx, transactionID); getErr == nil {
+				return completed, nil
+			}
+			return updatedTransaction, nil
+		}
+
+		// Only a record that is still pending may have its balance change
+		// rolled back; anything else was settled behind our back
+		current, getErr := s.transactionStorage.GetTransactionByID(ctx, transactionID)
+		if getErr != nil || current.Status != "pending" {
+			logger.Error("Failed to update transaction record, leaving it for the reaper",
+				slog.String("error", err.Error()))
+			return nil, fmt.Errorf("failed to update transaction: %w", err)
+		}
+
 		logger.Error("Failed to update transaction record, rolling back",
 			slog.String("error", err.Error()),
-			slog.Float64("rollback_balance", previousBalance))
-
-		// Rollback balance update
-		rollbackType := "withdraw"
-		if req.Type == "withdraw" {
-			rollbackType = "deposit"
+			slog.String("rollback_balance", previousBalance.String()))
+
+		// Rollback balance update; the record stays pending so the worker can retry
+		if revertErr := s.accountStorage.RevertAppliedTransactions(ctx, transactionID); revertErr != nil {
+			// The balance stays applied; the reaper completes the record from
+			// applied_transactions
+			logger.Error("Failed to roll back balance update", slog.String("error", revertErr.Error()))
+			return nil, fmt.Errorf("failed to update transaction: %w (rollback failed: %v)", err, revertErr)
 		}
-		s.accountStorage.AtomicBalanceUpdate(ctx, transaction.AccountID, rollbackType, req.Amount)
-		s.UpdateTransactionStatusWithError(ctx, transactionID, "failed", "Failed to update transaction record")
 		return nil, fmt.Errorf("failed to update transaction: %w", err)
 	}
 
+	if s.notifier != nil {
+		s.notifier.TransactionCompleted(ctx, updatedTransaction)
+	}
+
 	logger.Info("Async transaction completed successfully",
-		slog.Float64("final_balance", newBalance))
+		slog.String("final_balance", newBalance.String()))
 	return updatedTransaction, nil
 }
 
+// observeTransaction counts a processed transaction by how it ended
+func observeTransaction(transactionType, mode string, err error) {
+	outcome := metrics.OutcomeCompleted
+	if err != nil {
+		outcome = metrics.OutcomeFailed
+		if IsBusinessError(err) {
+			outcome = metrics.OutcomeRejected
+		}
+	}
+	metrics.Transactions.WithLabelValues(transactionType, mode, outcome).Inc()
+}
+
 func (s *TransactionService) GetTransactionHistory(ctx context.Context, accountID string, page, limit int) ([]models.Transaction, int64, error) {
 	logger := utils.LoggerFromContext(ctx).With(
 		slog.String("service", "transaction"),
@@ -257,14 +441,14 @@ func (s *TransactionService) GetTransactionHistory(ctx context.Context, accountI
 
 	if accountID == "" {
 		logger.Error("Account ID is required for transaction history")
-		return nil, 0, fmt.Errorf("account ID is required")
+		return nil, 0, models.Errorf(models.ErrInvalidRequest, "account ID is required")
 	}
 
 	// Verify account exists
 	_, err := s.accountStorage.GetAccountByID(ctx, accountID)
 	if err != nil {
 		logger.Error("Account not found for transaction history", slog.String("error", err.Error()))
-		return nil, 0, fmt.Errorf("account not found: %w", err)
+		return nil, 0, fmt.Errorf("failed to get account: %w", err)
 	}
 
 	// Set default values if not provided
@@ -291,6 +475,76 @@ func (s *TransactionService) GetTransactionHistory(ctx context.Context, accountI
 	return transactions, total, nil
 }
 
+// ListTransactions returns one page of an account's history, newest first,
+// continuing after cursor (the first page when it is empty) and narrowed by
+// filter
+func (s *TransactionService) ListTransactions(ctx context.Context, accountID string, filter *models.TransactionFilter, cursor string, limit int) (*models.TransactionPage, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "transaction"),
+		slog.String("operation", "list_transactions"),
+		slog.String("account_id", accountID))
+
+	logger.Info("Listing transactions", slog.Int("limit", limit), slog.Bool("has_cursor", cursor != ""))
+
+	if accountID == "" {
+		logger.Error("Account ID is required for transaction history")
+		return nil, models.Errorf(models.ErrInvalidRequest, "account ID is required")
+	}
+
+	if filter == nil {
+		filter = &models.TransactionFilter{}
+	}
+	if err := filter.Validate(); err != nil {
+		logger.Error("Invalid transaction filter", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var after *models.TransactionCursor
+	if cursor != "" {
+		var err error
+		if after, err = models.DecodeTransactionCursor(cursor); err != nil {
+			logger.Error("Invalid cursor", slog.String("error", err.Error()))
+			return nil, err
+		}
+	}
+
+	// Verify account exists
+	if _, err := s.accountStorage.GetAccountByID(ctx, accountID); err != nil {
+		logger.Error("Account not found for transaction history", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	// Fetch one extra record to learn whether another page follows
+	transactions, err := s.transactionStorage.GetTransactionsPage(ctx, accountID, filter, after, limit+1)
+	if err != nil {
+		logger.Error("Failed to get transaction page from storage", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to get transaction history: %w", err)
+	}
+
+	page := &models.TransactionPage{Transactions: transactions}
+	if len(transactions) > limit {
+		page.Transactions = transactions[:limit]
+		page.HasMore = true
+		page.NextCursor = models.CursorAfter(&page.Transactions[limit-1]).Encode()
+	}
+	if page.Transactions == nil {
+		page.Transactions = []models.Transaction{}
+	}
+
+	logger.Info("Transaction page retrieved successfully",
+		slog.Int("returned_count", len(page.Transactions)),
+		slog.Bool("has_more", page.HasMore))
+
+	return page, nil
+}
+
 func (s *TransactionService) GetTransactionByID(ctx context.Context, transactionID string) (*models.Transaction, error) {
 	logger := utils.LoggerFromContext(ctx).With(
 		slog.String("service", "transaction"),
@@ -301,7 +555,7 @@ func (s *TransactionService) GetTransactionByID(ctx context.Context, transaction
 
 	if transactionID == "" {
 		logger.Error("Transaction ID is required")
-		return nil, fmt.Errorf("transaction ID is required")
+		return nil, models.Errorf(models.ErrInvalidRequest, "transaction ID is required")
 	}
 
 	transaction, err := s.transactionStorage.GetTransactionByID(ctx, transactionID)
@@ -317,40 +571,42 @@ func (s *TransactionService) GetTransactionByID(ctx context.Context, transaction
 	return transaction, nil
 }
 
-func (s *TransactionService) CreateInitialTransaction(ctx context.Context, accountID string, initialBalance float64) error {
-	logger := utils.LoggerFromContext(ctx).With(
-		slog.String("service", "transaction"),
-		slog.String("operation", "create_initial_transaction"),
-		slog.String("account_id", accountID))
+// resolveCurrency returns the account's currency after checking that the request
+// uses the same currency (when it names one) and that the amount fits the
+// currency's precision
+func (s *TransactionService) resolveCurrency(ctx context.Context, accountID, requested string, amount models.Money) (string, error) {
+	_, currency, err := s.resolveAccount(ctx, accountID, requested, amount)
+	return currency, err
+}
 
-	if initialBalance <= 0 {
-		logger.Info("No initial transaction needed", slog.Float64("balance", initialBalance))
-		return nil
+// resolveAccount is resolveCurrency for callers that also need the account
+func (s *TransactionService) resolveAccount(ctx context.Context, accountID, requested string, amount models.Money) (*models.Account, string, error) {
+	account, err := s.accountStorage.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return nil, "", err
 	}
 
-	logger.Info("Creating initial transaction", slog.Float64("initial_balance", initialBalance))
+	currency := accountCurrency(account)
 
-	initialTransaction := &models.Transaction{
-		ID:              models.NewTransactionID(),
-		TransactionID:   models.NewTransactionID(),
-		AccountID:       accountID,
-		Type:            "deposit",
-		Amount:          initialBalance,
-		PreviousBalance: 0,
-		NewBalance:      initialBalance,
-		Description:     "Initial deposit",
-		Timestamp:       time.Now(),
-		Status:          "completed",
+	if requested != "" && models.NormalizeCurrency(requested) != currency {
+		return nil, "", models.Errorf(models.ErrCurrencyMismatch, "currency mismatch: account %s is denominated in %s, not %s",
+			accountID, currency, models.NormalizeCurrency(requested))
 	}
 
-	err := s.transactionStorage.CreateTransaction(ctx, initialTransaction)
-	if err != nil {
-		logger.Error("Failed to create initial transaction", slog.String("error", err.Error()))
-		return err
+	if err := models.ValidateAmountPrecision(amount, currency); err != nil {
+		return nil, "", err
 	}
 
-	logger.Info("Initial transaction created successfully")
-	return nil
+	return account, currency, nil
+}
+
+// accountCurrency returns the account's currency, defaulting for accounts
+// that predate currencies
+func accountCurrency(account *models.Account) string {
+	if account.Currency == "" {
+		return models.DefaultCurrency
+	}
+	return account.Currency
 }
 
 func (s *TransactionService) validateTransactionRequest(ctx context.Context, req *models.TransactionRequest) error {
@@ -358,12 +614,12 @@ func (s *TransactionService) validateTransactionRequest(ctx context.Context, req
 
 	if req.Type != "deposit" && req.Type != "withdraw" {
 		logger.Error("Invalid transaction type", slog.String("type", req.Type))
-		return fmt.Errorf("transaction type must be either 'deposit' or 'withdraw'")
+		return models.Errorf(models.ErrInvalidTransactionType, "transaction type must be either 'deposit' or 'withdraw'")
 	}
 
 	if req.Amount <= 0 {
-		logger.Error("Invalid amount", slog.Float64("amount", req.Amount))
-		return fmt.Errorf("amount must be greater than 0")
+		logger.Error("Invalid amount", slog.String("amount", req.Amount.String()))
+		return models.Errorf(models.ErrInvalidAmount, "amount must be greater than 0")
 	}
 
 	logger.Info("Transaction request validated successfully")
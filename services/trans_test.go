@@ -21,21 +21,19 @@ func TestTransactionService_ProcessTransaction_DepositSuccess(t *testing.T) {
 	mockAccountStorage := NewMockAccountStorage(ctrl)
 	mockTransactionStorage := NewMockTransactionStorage(ctrl)
 	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
 
 	accountID := "acc_12345"
 	req := &models.TransactionRequest{
 		Type:        "deposit",
-		Amount:      250.00,
+		Amount:      models.MustParseMoney("250.00"),
 		Description: "Test deposit",
 	}
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
 	ctx := utils.WithLogger(context.Background(), logger)
 
-	// Mock expectations - Updated to use AtomicBalanceUpdate
-	mockAccountStorage.EXPECT().
-		AtomicBalanceUpdate(ctx, accountID, "deposit", 250.00).
-		Return(500.00, 750.00, nil). // previousBalance, newBalance, error
+	expectPostedEntry(t, mockAccountStorage, accountID, "deposit", models.MustParseMoney("250.00"), models.MustParseMoney("500.00"), models.MustParseMoney("750.00")).
 		Times(1)
 
 	mockTransactionStorage.EXPECT().
@@ -43,9 +41,9 @@ func TestTransactionService_ProcessTransaction_DepositSuccess(t *testing.T) {
 		DoAndReturn(func(ctx context.Context, transaction *models.Transaction) error {
 			assert.Equal(t, accountID, transaction.AccountID)
 			assert.Equal(t, "deposit", transaction.Type)
-			assert.Equal(t, 250.00, transaction.Amount)
-			assert.Equal(t, 500.00, transaction.PreviousBalance)
-			assert.Equal(t, 750.00, transaction.NewBalance)
+			assert.Equal(t, models.MustParseMoney("250.00"), transaction.Amount)
+			assert.Equal(t, models.MustParseMoney("500.00"), transaction.PreviousBalance)
+			assert.Equal(t, models.MustParseMoney("750.00"), transaction.NewBalance)
 			assert.Equal(t, "completed", transaction.Status)
 			return nil
 		}).
@@ -58,9 +56,9 @@ func TestTransactionService_ProcessTransaction_DepositSuccess(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, transaction)
 	assert.Equal(t, "deposit", transaction.Type)
-	assert.Equal(t, 250.00, transaction.Amount)
-	assert.Equal(t, 500.00, transaction.PreviousBalance)
-	assert.Equal(t, 750.00, transaction.NewBalance)
+	assert.Equal(t, models.MustParseMoney("250.00"), transaction.Amount)
+	assert.Equal(t, models.MustParseMoney("500.00"), transaction.PreviousBalance)
+	assert.Equal(t, models.MustParseMoney("750.00"), transaction.NewBalance)
 	assert.Equal(t, "completed", transaction.Status)
 }
 
@@ -71,30 +69,28 @@ func TestTransactionService_ProcessTransaction_WithdrawSuccess(t *testing.T) {
 	mockAccountStorage := NewMockAccountStorage(ctrl)
 	mockTransactionStorage := NewMockTransactionStorage(ctrl)
 	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
 
 	accountID := "acc_12345"
 	req := &models.TransactionRequest{
 		Type:        "withdraw",
-		Amount:      200.00,
+		Amount:      models.MustParseMoney("200.00"),
 		Description: "Test withdrawal",
 	}
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
 	ctx := utils.WithLogger(context.Background(), logger)
 
-	// Mock expectations - Updated to use AtomicBalanceUpdate
-	mockAccountStorage.EXPECT().
-		AtomicBalanceUpdate(ctx, accountID, "withdraw", 200.00).
-		Return(500.00, 300.00, nil). // previousBalance, newBalance, error
+	expectPostedEntry(t, mockAccountStorage, accountID, "withdraw", models.MustParseMoney("200.00"), models.MustParseMoney("500.00"), models.MustParseMoney("300.00")).
 		Times(1)
 
 	mockTransactionStorage.EXPECT().
 		CreateTransaction(ctx, gomock.Any()).
 		DoAndReturn(func(ctx context.Context, transaction *models.Transaction) error {
 			assert.Equal(t, "withdraw", transaction.Type)
-			assert.Equal(t, 200.00, transaction.Amount)
-			assert.Equal(t, 500.00, transaction.PreviousBalance)
-			assert.Equal(t, 300.00, transaction.NewBalance)
+			assert.Equal(t, models.MustParseMoney("200.00"), transaction.Amount)
+			assert.Equal(t, models.MustParseMoney("500.00"), transaction.PreviousBalance)
+			assert.Equal(t, models.MustParseMoney("300.00"), transaction.NewBalance)
 			return nil
 		}).
 		Times(1)
@@ -105,9 +101,9 @@ func TestTransactionService_ProcessTransaction_WithdrawSuccess(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	assert.Equal(t, "withdraw", transaction.Type)
-	assert.Equal(t, 200.00, transaction.Amount)
-	assert.Equal(t, 500.00, transaction.PreviousBalance)
-	assert.Equal(t, 300.00, transaction.NewBalance)
+	assert.Equal(t, models.MustParseMoney("200.00"), transaction.Amount)
+	assert.Equal(t, models.MustParseMoney("500.00"), transaction.PreviousBalance)
+	assert.Equal(t, models.MustParseMoney("300.00"), transaction.NewBalance)
 }
 
 func TestTransactionService_ProcessTransaction_InsufficientFunds(t *testing.T) {
@@ -117,21 +113,22 @@ func TestTransactionService_ProcessTransaction_InsufficientFunds(t *testing.T) {
 	mockAccountStorage := NewMockAccountStorage(ctrl)
 	mockTransactionStorage := NewMockTransactionStorage(ctrl)
 	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
 
 	accountID := "acc_12345"
 	req := &models.TransactionRequest{
 		Type:        "withdraw",
-		Amount:      600.00, // More than available balance
+		Amount:      models.MustParseMoney("600.00"), // More than available balance
 		Description: "Test insufficient funds",
 	}
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
 	ctx := utils.WithLogger(context.Background(), logger)
 
-	// Mock expectations - AtomicBalanceUpdate returns insufficient funds error
+	// Mock expectations - posting the entry fails with insufficient funds
 	mockAccountStorage.EXPECT().
-		AtomicBalanceUpdate(ctx, accountID, "withdraw", 600.00).
-		Return(0.0, 0.0, errors.New("insufficient funds: current balance 500.00, requested 600.00")).
+		PostJournalEntry(ctx, gomock.Any()).
+		Return(nil, &models.InsufficientFundsError{AccountID: "acc_12345", Balance: models.MustParseMoney("500.00"), Requested: models.MustParseMoney("600.00")}).
 		Times(1)
 
 	// No transaction creation should occur since balance update failed
@@ -143,6 +140,7 @@ func TestTransactionService_ProcessTransaction_InsufficientFunds(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, transaction)
 	assert.Contains(t, err.Error(), "insufficient funds")
+	assert.ErrorIs(t, err, models.ErrInsufficientFunds)
 }
 
 func TestTransactionService_ProcessTransaction_InvalidType(t *testing.T) {
@@ -155,7 +153,7 @@ func TestTransactionService_ProcessTransaction_InvalidType(t *testing.T) {
 
 	req := &models.TransactionRequest{
 		Type:        "invalid",
-		Amount:      100.00,
+		Amount:      models.MustParseMoney("100.00"),
 		Description: "Invalid transaction type",
 	}
 
@@ -181,7 +179,7 @@ func TestTransactionService_ProcessTransaction_NegativeAmount(t *testing.T) {
 
 	req := &models.TransactionRequest{
 		Type:        "deposit",
-		Amount:      -100.00, // Negative amount
+		Amount:      models.MustParseMoney("-100.00"), // Negative amount
 		Description: "Negative amount test",
 	}
 
@@ -204,11 +202,12 @@ func TestTransactionService_ProcessTransaction_TransactionStorageFailure(t *test
 	mockAccountStorage := NewMockAccountStorage(ctrl)
 	mockTransactionStorage := NewMockTransactionStorage(ctrl)
 	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
 
 	accountID := "acc_12345"
 	req := &models.TransactionRequest{
 		Type:        "deposit",
-		Amount:      100.00,
+		Amount:      models.MustParseMoney("100.00"),
 		Description: "Storage failure test",
 	}
 
@@ -216,9 +215,7 @@ func TestTransactionService_ProcessTransaction_TransactionStorageFailure(t *test
 	ctx := utils.WithLogger(context.Background(), logger)
 
 	// Mock expectations - Balance update succeeds but transaction save fails
-	mockAccountStorage.EXPECT().
-		AtomicBalanceUpdate(ctx, accountID, "deposit", 100.00).
-		Return(500.00, 600.00, nil).
+	expectPostedEntry(t, mockAccountStorage, accountID, "deposit", models.MustParseMoney("100.00"), models.MustParseMoney("500.00"), models.MustParseMoney("600.00")).
 		Times(1)
 
 	mockTransactionStorage.EXPECT().
@@ -226,10 +223,8 @@ func TestTransactionService_ProcessTransaction_TransactionStorageFailure(t *test
 		Return(errors.New("database error")).
 		Times(1)
 
-	// Expect rollback - reverse the deposit with a withdraw
-	mockAccountStorage.EXPECT().
-		AtomicBalanceUpdate(ctx, accountID, "withdraw", 100.00).
-		Return(600.00, 500.00, nil).
+	// Expect rollback - the deposit entry is reversed
+	expectPostedEntry(t, mockAccountStorage, accountID, "withdraw", models.MustParseMoney("100.00"), models.MustParseMoney("600.00"), models.MustParseMoney("500.00")).
 		Times(1)
 
 	// Execute
@@ -248,22 +243,24 @@ func TestTransactionService_ProcessTransactionAsync_Success(t *testing.T) {
 	mockAccountStorage := NewMockAccountStorage(ctrl)
 	mockTransactionStorage := NewMockTransactionStorage(ctrl)
 	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
 
 	transactionID := "txn_12345"
 	req := &models.TransactionRequest{
 		Type:        "deposit",
-		Amount:      150.00,
+		Amount:      models.MustParseMoney("150.00"),
 		Description: "Async deposit test",
 	}
 
 	pendingTransaction := &models.Transaction{
-		ID:            transactionID,
-		TransactionID: transactionID,
-		AccountID:     "acc_12345",
-		Type:          "deposit",
-		Amount:        150.00,
-		Status:        "pending",
-		Timestamp:     time.Now(),
+		ID:             transactionID,
+		TransactionID:  transactionID,
+		AccountID:      "acc_12345",
+		Type:           "deposit",
+		Amount:         models.MustParseMoney("150.00"),
+		Status:         "pending",
+		Timestamp:      time.Now(),
+		IdempotencyKey: "key-1",
 	}
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
@@ -276,16 +273,17 @@ func TestTransactionService_ProcessTransactionAsync_Success(t *testing.T) {
 		Times(1)
 
 	mockAccountStorage.EXPECT().
-		AtomicBalanceUpdate(ctx, "acc_12345", "deposit", 150.00).
-		Return(400.00, 550.00, nil). // previousBalance, newBalance, error
+		ApplyTransaction(ctx, transactionID, "acc_12345", "deposit", models.MustParseMoney("150.00"), nil).
+		Return(models.MustParseMoney("400.00"), models.MustParseMoney("550.00"), nil). // previousBalance, newBalance, error
 		Times(1)
 
 	mockTransactionStorage.EXPECT().
 		UpdateTransaction(ctx, gomock.Any()).
 		DoAndReturn(func(ctx context.Context, transaction *models.Transaction) error {
 			assert.Equal(t, "completed", transaction.Status)
-			assert.Equal(t, 400.00, transaction.PreviousBalance)
-			assert.Equal(t, 550.00, transaction.NewBalance)
+			assert.Equal(t, models.MustParseMoney("400.00"), transaction.PreviousBalance)
+			assert.Equal(t, models.MustParseMoney("550.00"), transaction.NewBalance)
+			assert.Equal(t, "key-1", transaction.IdempotencyKey)
 			return nil
 		}).
 		Times(1)
@@ -297,7 +295,7 @@ func TestTransactionService_ProcessTransactionAsync_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, transaction)
 	assert.Equal(t, "completed", transaction.Status)
-	assert.Equal(t, 550.00, transaction.NewBalance)
+	assert.Equal(t, models.MustParseMoney("550.00"), transaction.NewBalance)
 }
 
 func TestTransactionService_ProcessTransactionAsync_NotPending(t *testing.T) {
@@ -311,7 +309,7 @@ func TestTransactionService_ProcessTransactionAsync_NotPending(t *testing.T) {
 	transactionID := "txn_12345"
 	req := &models.TransactionRequest{
 		Type:        "deposit",
-		Amount:      150.00,
+		Amount:      models.MustParseMoney("150.00"),
 		Description: "Already processed transaction",
 	}
 
@@ -345,11 +343,12 @@ func TestTransactionService_ProcessTransactionAsync_InsufficientFunds(t *testing
 	mockAccountStorage := NewMockAccountStorage(ctrl)
 	mockTransactionStorage := NewMockTransactionStorage(ctrl)
 	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
 
 	transactionID := "txn_12345"
 	req := &models.TransactionRequest{
 		Type:        "withdraw",
-		Amount:      600.00,
+		Amount:      models.MustParseMoney("600.00"),
 		Description: "Async insufficient funds test",
 	}
 
@@ -358,7 +357,7 @@ func TestTransactionService_ProcessTransactionAsync_InsufficientFunds(t *testing
 		TransactionID: transactionID,
 		AccountID:     "acc_12345",
 		Type:          "withdraw",
-		Amount:        600.00,
+		Amount:        models.MustParseMoney("600.00"),
 		Status:        "pending",
 		Timestamp:     time.Now(),
 	}
@@ -372,10 +371,10 @@ func TestTransactionService_ProcessTransactionAsync_InsufficientFunds(t *testing
 		Return(pendingTransaction, nil).
 		Times(1)
 
-	// AtomicBalanceUpdate fails with insufficient funds
+	// ApplyTransaction fails with insufficient funds
 	mockAccountStorage.EXPECT().
-		AtomicBalanceUpdate(ctx, "acc_12345", "withdraw", 600.00).
-		Return(0.0, 0.0, errors.New("insufficient funds: current balance 200.00, requested 600.00")).
+		ApplyTransaction(ctx, transactionID, "acc_12345", "withdraw", models.MustParseMoney("600.00"), nil).
+		Return(models.Money(0), models.Money(0), &models.InsufficientFundsError{AccountID: "acc_12345", Balance: models.MustParseMoney("200.00"), Requested: models.MustParseMoney("600.00")}).
 		Times(1)
 
 	// Expect transaction status update with error
@@ -391,6 +390,102 @@ func TestTransactionService_ProcessTransactionAsync_InsufficientFunds(t *testing
 	assert.Error(t, err)
 	assert.Nil(t, transaction)
 	assert.Contains(t, err.Error(), "insufficient funds")
+	assert.ErrorIs(t, err, models.ErrInsufficientFunds)
+}
+
+func TestTransactionService_ProcessTransactionAsync_FrozenAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
+
+	transactionID := "txn_12345"
+	req := &models.TransactionRequest{
+		Type:   "withdraw",
+		Amount: models.MustParseMoney("50.00"),
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	ctx := utils.WithLogger(context.Background(), logger)
+
+	mockTransactionStorage.EXPECT().
+		GetTransactionByID(ctx, transactionID).
+		Return(&models.Transaction{
+			ID:            transactionID,
+			TransactionID: transactionID,
+			AccountID:     "acc_12345",
+			Type:          "withdraw",
+			Amount:        models.MustParseMoney("50.00"),
+			Status:        "pending",
+			Timestamp:     time.Now(),
+		}, nil).
+		Times(1)
+
+	// The account was frozen after the request was queued; the storage layer
+	// rejects the debit under the row lock
+	mockAccountStorage.EXPECT().
+		ApplyTransaction(ctx, transactionID, "acc_12345", "withdraw", models.MustParseMoney("50.00"), nil).
+		Return(models.Money(0), models.Money(0), models.Errorf(models.ErrAccountFrozen, "account acc_12345 is frozen")).
+		Times(1)
+
+	// Retrying cannot help, so the record is failed rather than left pending
+	mockTransactionStorage.EXPECT().
+		UpdateTransactionStatusWithError(ctx, transactionID, "failed", "account acc_12345 is frozen").
+		Return(nil).
+		Times(1)
+
+	transaction, err := service.ProcessTransactionAsync(ctx, transactionID, req)
+
+	assert.Nil(t, transaction)
+	assert.ErrorIs(t, err, models.ErrAccountFrozen)
+	assert.True(t, IsBusinessError(err))
+}
+
+func TestTransactionService_ProcessTransactionAsync_AlreadyApplied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
+
+	transactionID := "txn_12345"
+	req := &models.TransactionRequest{Type: "deposit", Amount: models.MustParseMoney("100.00")}
+
+	pendingTransaction := &models.Transaction{
+		ID:            transactionID,
+		TransactionID: transactionID,
+		AccountID:     "acc_12345",
+		Type:          "deposit",
+		Amount:        models.MustParseMoney("100.00"),
+		Status:        "pending",
+		Timestamp:     time.Now(),
+	}
+
+	ctx := context.Background()
+
+	mockTransactionStorage.EXPECT().
+		GetTransactionByID(ctx, transactionID).
+		Return(pendingTransaction, nil).
+		Times(1)
+
+	// A redelivered message finds the balance change already recorded
+	mockAccountStorage.EXPECT().
+		ApplyTransaction(ctx, transactionID, "acc_12345", "deposit", models.MustParseMoney("100.00"), nil).
+		Return(models.Money(0), models.Money(0), models.Errorf(models.ErrAlreadyApplied, "transaction already applied: txn_12345")).
+		Times(1)
+
+	// Execute
+	transaction, err := service.ProcessTransactionAsync(ctx, transactionID, req)
+
+	// The record is left pending rather than marked failed
+	assert.Error(t, err)
+	assert.Nil(t, transaction)
+	assert.Contains(t, err.Error(), "already applied")
 }
 
 func TestTransactionService_ProcessTransactionAsync_StorageRollback(t *testing.T) {
@@ -400,11 +495,12 @@ func TestTransactionService_ProcessTransactionAsync_StorageRollback(t *testing.T
 	mockAccountStorage := NewMockAccountStorage(ctrl)
 	mockTransactionStorage := NewMockTransactionStorage(ctrl)
 	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
 
 	transactionID := "txn_12345"
 	req := &models.TransactionRequest{
 		Type:        "deposit",
-		Amount:      100.00,
+		Amount:      models.MustParseMoney("100.00"),
 		Description: "Rollback test",
 	}
 
@@ -413,7 +509,7 @@ func TestTransactionService_ProcessTransactionAsync_StorageRollback(t *testing.T
 		TransactionID: transactionID,
 		AccountID:     "acc_12345",
 		Type:          "deposit",
-		Amount:        100.00,
+		Amount:        models.MustParseMoney("100.00"),
 		Status:        "pending",
 		Timestamp:     time.Now(),
 	}
@@ -421,16 +517,16 @@ func TestTransactionService_ProcessTransactionAsync_StorageRollback(t *testing.T
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
 	ctx := utils.WithLogger(context.Background(), logger)
 
-	// Mock expectations
+	// Mock expectations; the record is read again after the update fails
 	mockTransactionStorage.EXPECT().
 		GetTransactionByID(ctx, transactionID).
 		Return(pendingTransaction, nil).
-		Times(1)
+		Times(2)
 
 	// Balance update succeeds
 	mockAccountStorage.EXPECT().
-		AtomicBalanceUpdate(ctx, "acc_12345", "deposit", 100.00).
-		Return(500.00, 600.00, nil).
+		ApplyTransaction(ctx, transactionID, "acc_12345", "deposit", models.MustParseMoney("100.00"), nil).
+		Return(models.MustParseMoney("500.00"), models.MustParseMoney("600.00"), nil).
 		Times(1)
 
 	// Transaction update fails
@@ -441,16 +537,12 @@ func TestTransactionService_ProcessTransactionAsync_StorageRollback(t *testing.T
 
 	// Expect rollback - reverse the deposit
 	mockAccountStorage.EXPECT().
-		AtomicBalanceUpdate(ctx, "acc_12345", "withdraw", 100.00).
-		Return(600.00, 500.00, nil).
-		Times(1)
-
-	// Expect error status update
-	mockTransactionStorage.EXPECT().
-		UpdateTransactionStatusWithError(ctx, transactionID, "failed", "Failed to update transaction record").
+		RevertAppliedTransactions(ctx, transactionID).
 		Return(nil).
 		Times(1)
 
+	// The record stays pending so the worker can retry; no status update is expected
+
 	// Execute
 	transaction, err := service.ProcessTransactionAsync(ctx, transactionID, req)
 
@@ -458,6 +550,201 @@ func TestTransactionService_ProcessTransactionAsync_StorageRollback(t *testing.T
 	assert.Error(t, err)
 	assert.Nil(t, transaction)
 	assert.Contains(t, err.Error(), "failed to update transaction")
+	assert.False(t, IsBusinessError(err))
+}
+
+func TestTransactionService_ProcessTransactionAsync_CompletedByReaper(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
+
+	transactionID := "txn_12345"
+	req := &models.TransactionRequest{Type: "deposit", Amount: models.MustParseMoney("100.00")}
+	ctx := context.Background()
+
+	pending := &models.Transaction{
+		ID:            transactionID,
+		TransactionID: transactionID,
+		AccountID:     "acc_12345",
+		Type:          "deposit",
+		Amount:        models.MustParseMoney("100.00"),
+		Status:        "pending",
+	}
+	completed := *pending
+	completed.Status = "completed"
+	completed.Hash = "sealed"
+
+	gomock.InOrder(
+		mockTransactionStorage.EXPECT().GetTransactionByID(ctx, transactionID).Return(pending, nil),
+		mockTransactionStorage.EXPECT().GetTransactionByID(ctx, transactionID).Return(&completed, nil),
+	)
+	mockAccountStorage.EXPECT().
+		ApplyTransaction(ctx, transactionID, "acc_12345", "deposit", models.MustParseMoney("100.00"), nil).
+		Return(models.MustParseMoney("500.00"), models.MustParseMoney("600.00"), nil)
+
+	// The reaper sealed the record between the balance update and ours
+	mockTransactionStorage.EXPECT().
+		UpdateTransaction(ctx, gomock.Any()).
+		Return(models.Errorf(models.ErrTransactionSealed, "transaction %s is sealed and cannot be changed", transactionID))
+	mockAccountStorage.EXPECT().RevertAppliedTransactions(gomock.Any(), gomock.Any()).Times(0)
+
+	transaction, err := service.ProcessTransactionAsync(ctx, transactionID, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "completed", transaction.Status)
+}
+
+func TestTransactionService_ProcessTransactionAsync_RollbackFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
+
+	transactionID := "txn_12345"
+	req := &models.TransactionRequest{Type: "deposit", Amount: models.MustParseMoney("100.00")}
+	ctx := context.Background()
+
+	mockTransactionStorage.EXPECT().
+		GetTransactionByID(ctx, transactionID).
+		Return(&models.Transaction{ID: transactionID, TransactionID: transactionID, AccountID: "acc_12345", Type: "deposit", Amount: models.MustParseMoney("100.00"), Status: "pending"}, nil).
+		Times(2)
+	mockAccountStorage.EXPECT().
+		ApplyTransaction(ctx, transactionID, "acc_12345", "deposit", models.MustParseMoney("100.00"), nil).
+		Return(models.MustParseMoney("500.00"), models.MustParseMoney("600.00"), nil)
+	mockTransactionStorage.EXPECT().
+		UpdateTransaction(ctx, gomock.Any()).
+		Return(errors.New("database connection lost"))
+	mockAccountStorage.EXPECT().
+		RevertAppliedTransactions(ctx, transactionID).
+		Return(errors.New("postgres unavailable"))
+
+	transaction, err := service.ProcessTransactionAsync(ctx, transactionID, req)
+
+	assert.Error(t, err)
+	assert.Nil(t, transaction)
+	assert.Contains(t, err.Error(), "rollback failed")
+	assert.False(t, IsBusinessError(err))
+}
+
+func TestTransactionService_ProcessTransactionAsync_SystemErrorLeavesPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
+
+	transactionID := "txn_12345"
+	req := &models.TransactionRequest{Type: "deposit", Amount: models.MustParseMoney("100.00")}
+	ctx := context.Background()
+
+	mockTransactionStorage.EXPECT().
+		GetTransactionByID(ctx, transactionID).
+		Return(&models.Transaction{TransactionID: transactionID, AccountID: "acc_12345", Status: "pending"}, nil).
+		Times(1)
+
+	mockAccountStorage.EXPECT().
+		ApplyTransaction(ctx, transactionID, "acc_12345", "deposit", models.MustParseMoney("100.00"), nil).
+		Return(models.Money(0), models.Money(0), errors.New("failed to begin transaction: connection refused")).
+		Times(1)
+
+	// No UpdateTransactionStatusWithError: the worker retries and fails the
+	// record itself once it gives up
+
+	transaction, err := service.ProcessTransactionAsync(ctx, transactionID, req)
+
+	assert.Error(t, err)
+	assert.Nil(t, transaction)
+	assert.False(t, IsBusinessError(err))
+}
+
+func TestTransactionService_FailUnprocessedTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	ctx := context.Background()
+
+	pending := &models.Transaction{TransactionID: "txn_1", Status: "pending"}
+
+	// Not applied: the record is failed with the worker's reason
+	mockTransactionStorage.EXPECT().GetTransactionByID(ctx, "txn_1").Return(pending, nil)
+	mockAccountStorage.EXPECT().GetAppliedTransaction(ctx, "txn_1").Return(nil, nil)
+	mockTransactionStorage.EXPECT().
+		UpdateTransactionStatusWithError(ctx, "txn_1", "failed", "processing failed after 6 attempts: connection refused").
+		Return(nil)
+
+	err := service.FailUnprocessedTransaction(ctx, "txn_1", "processing failed after 6 attempts: connection refused")
+	assert.NoError(t, err)
+
+	// Applied: the record is left for the reaper to complete
+	mockTransactionStorage.EXPECT().GetTransactionByID(ctx, "txn_2").Return(&models.Transaction{TransactionID: "txn_2", Status: "pending"}, nil)
+	mockAccountStorage.EXPECT().GetAppliedTransaction(ctx, "txn_2").Return(&models.AppliedTransaction{TransactionID: "txn_2"}, nil)
+
+	err = service.FailUnprocessedTransaction(ctx, "txn_2", "processing failed after 6 attempts: connection refused")
+	assert.NoError(t, err)
+}
+
+func TestTransactionService_FailUnprocessedTransaction_NotifiesFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	mockNotifier := NewMockEventNotifier(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	service.SetNotifier(mockNotifier)
+	ctx := context.Background()
+
+	mockTransactionStorage.EXPECT().GetTransactionByID(ctx, "txn_1").Return(&models.Transaction{TransactionID: "txn_1", AccountID: "acc_1", Status: "pending"}, nil)
+	mockAccountStorage.EXPECT().GetAppliedTransaction(ctx, "txn_1").Return(nil, nil)
+	mockTransactionStorage.EXPECT().UpdateTransactionStatusWithError(ctx, "txn_1", "failed", "connection refused").Return(nil)
+	mockNotifier.EXPECT().
+		TransactionFailed(ctx, gomock.Any()).
+		Do(func(ctx context.Context, transaction *models.Transaction) {
+			assert.Equal(t, "txn_1", transaction.TransactionID)
+			assert.Equal(t, "failed", transaction.Status)
+			assert.Equal(t, "connection refused", transaction.ErrorMessage)
+		})
+
+	err := service.FailUnprocessedTransaction(ctx, "txn_1", "connection refused")
+	assert.NoError(t, err)
+}
+
+func TestTransactionService_ProcessTransaction_NotifiesCompleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	mockNotifier := NewMockEventNotifier(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	service.SetNotifier(mockNotifier)
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
+	ctx := context.Background()
+
+	expectPostedEntry(t, mockAccountStorage, "acc_12345", "withdraw", models.MustParseMoney("450.00"), models.MustParseMoney("500.00"), models.MustParseMoney("50.00"))
+	mockTransactionStorage.EXPECT().CreateTransaction(ctx, gomock.Any()).Return(nil)
+	mockNotifier.EXPECT().
+		TransactionCompleted(ctx, gomock.Any()).
+		Do(func(ctx context.Context, transaction *models.Transaction) {
+			assert.Equal(t, "completed", transaction.Status)
+			assert.Equal(t, models.MustParseMoney("500.00"), transaction.PreviousBalance)
+			assert.Equal(t, models.MustParseMoney("50.00"), transaction.NewBalance)
+		})
+
+	_, err := service.ProcessTransaction(ctx, "acc_12345", &models.TransactionRequest{Type: "withdraw", Amount: models.MustParseMoney("450.00")})
+	assert.NoError(t, err)
 }
 
 func TestTransactionService_GetTransactionByID_Success(t *testing.T) {
@@ -474,7 +761,7 @@ func TestTransactionService_GetTransactionByID_Success(t *testing.T) {
 		TransactionID: transactionID,
 		AccountID:     "acc_12345",
 		Type:          "deposit",
-		Amount:        200.00,
+		Amount:        models.MustParseMoney("200.00"),
 		Status:        "completed",
 	}
 
@@ -528,12 +815,12 @@ func TestTransactionService_GetTransactionHistory_Success(t *testing.T) {
 
 	existingAccount := &models.Account{
 		ID:      accountID,
-		Balance: 500.00,
+		Balance: models.MustParseMoney("500.00"),
 	}
 
 	expectedTransactions := []models.Transaction{
-		{ID: "txn_1", AccountID: accountID, Type: "deposit", Amount: 100.00},
-		{ID: "txn_2", AccountID: accountID, Type: "withdraw", Amount: 50.00},
+		{ID: "txn_1", AccountID: accountID, Type: "deposit", Amount: models.MustParseMoney("100.00")},
+		{ID: "txn_2", AccountID: accountID, Type: "withdraw", Amount: models.MustParseMoney("50.00")},
 	}
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
@@ -573,7 +860,7 @@ func TestTransactionService_GetTransactionHistory_AccountNotFound(t *testing.T)
 
 	mockAccountStorage.EXPECT().
 		GetAccountByID(ctx, accountID).
-		Return(nil, errors.New("account not found")).
+		Return(nil, models.ErrAccountNotFound).
 		Times(1)
 
 	// Execute
@@ -583,7 +870,7 @@ func TestTransactionService_GetTransactionHistory_AccountNotFound(t *testing.T)
 	assert.Error(t, err)
 	assert.Nil(t, transactions)
 	assert.Equal(t, int64(0), total)
-	assert.Contains(t, err.Error(), "account not found")
+	assert.ErrorIs(t, err, models.ErrAccountNotFound)
 }
 
 func TestTransactionService_GetTransactionHistory_PaginationDefaults(t *testing.T) {
@@ -595,7 +882,7 @@ func TestTransactionService_GetTransactionHistory_PaginationDefaults(t *testing.
 	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
 
 	accountID := "acc_12345"
-	existingAccount := &models.Account{ID: accountID, Balance: 500.00}
+	existingAccount := &models.Account{ID: accountID, Balance: models.MustParseMoney("500.00")}
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
 	ctx := utils.WithLogger(context.Background(), logger)
@@ -620,7 +907,7 @@ func TestTransactionService_GetTransactionHistory_PaginationDefaults(t *testing.
 	assert.Equal(t, int64(0), total)
 }
 
-func TestTransactionService_CreatePendingTransaction_Success(t *testing.T) {
+func TestTransactionService_ListTransactions_NextCursor(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -628,31 +915,42 @@ func TestTransactionService_CreatePendingTransaction_Success(t *testing.T) {
 	mockTransactionStorage := NewMockTransactionStorage(ctrl)
 	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
 
-	pendingTransaction := &models.Transaction{
-		ID:            "txn_12345",
-		TransactionID: "txn_12345",
-		AccountID:     "acc_12345",
-		Type:          "deposit",
-		Amount:        300.00,
-		Status:        "pending",
-	}
+	accountID := "acc_12345"
+	ctx := context.Background()
+	now := time.Now()
+	minAmount := models.MustParseMoney("10.00")
+	filter := &models.TransactionFilter{Types: []string{"deposit"}, MinAmount: &minAmount}
+	after := &models.TransactionCursor{Timestamp: now, ID: "txn_a"}
 
-	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
-	ctx := utils.WithLogger(context.Background(), logger)
+	mockAccountStorage.EXPECT().
+		GetAccountByID(ctx, accountID).
+		Return(&models.Account{ID: accountID}, nil)
 
+	// One record more than the limit is requested to detect a following page
 	mockTransactionStorage.EXPECT().
-		CreateTransaction(ctx, pendingTransaction).
-		Return(nil).
-		Times(1)
+		GetTransactionsPage(ctx, accountID, filter, gomock.Any(), 3).
+		DoAndReturn(func(ctx context.Context, accountID string, filter *models.TransactionFilter, cursor *models.TransactionCursor, limit int) ([]models.Transaction, error) {
+			assert.Equal(t, "txn_a", cursor.ID)
+			assert.True(t, now.Equal(cursor.Timestamp))
+			return []models.Transaction{
+				{ID: "txn_b", Timestamp: now.Add(-time.Minute)},
+				{ID: "txn_c", Timestamp: now.Add(-2 * time.Minute)},
+				{ID: "txn_d", Timestamp: now.Add(-3 * time.Minute)},
+			}, nil
+		})
 
-	// Execute
-	err := service.CreatePendingTransaction(ctx, pendingTransaction)
+	page, err := service.ListTransactions(ctx, accountID, filter, after.Encode(), 2)
 
-	// Assert
 	assert.NoError(t, err)
+	assert.Len(t, page.Transactions, 2)
+	assert.True(t, page.HasMore)
+
+	next, err := models.DecodeTransactionCursor(page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, "txn_c", next.ID)
 }
 
-func TestTransactionService_UpdateTransactionStatus_Success(t *testing.T) {
+func TestTransactionService_ListTransactions_LastPage(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -660,25 +958,37 @@ func TestTransactionService_UpdateTransactionStatus_Success(t *testing.T) {
 	mockTransactionStorage := NewMockTransactionStorage(ctrl)
 	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
 
-	transactionID := "txn_12345"
-	status := "completed"
-
-	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
-	ctx := utils.WithLogger(context.Background(), logger)
+	ctx := context.Background()
 
+	mockAccountStorage.EXPECT().GetAccountByID(ctx, "acc_12345").Return(&models.Account{ID: "acc_12345"}, nil)
 	mockTransactionStorage.EXPECT().
-		UpdateTransactionStatus(ctx, transactionID, status).
-		Return(nil).
-		Times(1)
+		GetTransactionsPage(ctx, "acc_12345", gomock.Any(), nil, 11).
+		Return(nil, nil)
 
-	// Execute
-	err := service.UpdateTransactionStatus(ctx, transactionID, status)
+	page, err := service.ListTransactions(ctx, "acc_12345", nil, "", 0)
 
-	// Assert
 	assert.NoError(t, err)
+	assert.Equal(t, []models.Transaction{}, page.Transactions)
+	assert.False(t, page.HasMore)
+	assert.Empty(t, page.NextCursor)
 }
 
-func TestTransactionService_UpdateTransactionStatusWithError_Success(t *testing.T) {
+func TestTransactionService_ListTransactions_InvalidInput(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewTransactionService(NewMockAccountStorage(ctrl), NewMockTransactionStorage(ctrl))
+	ctx := context.Background()
+
+	_, err := service.ListTransactions(ctx, "acc_12345", nil, "garbage!", 10)
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+
+	low, high := models.MustParseMoney("5.00"), models.MustParseMoney("1.00")
+	_, err = service.ListTransactions(ctx, "acc_12345", &models.TransactionFilter{MinAmount: &low, MaxAmount: &high}, "", 10)
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+}
+
+func TestTransactionService_CreatePendingTransaction_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -686,26 +996,31 @@ func TestTransactionService_UpdateTransactionStatusWithError_Success(t *testing.
 	mockTransactionStorage := NewMockTransactionStorage(ctrl)
 	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
 
-	transactionID := "txn_12345"
-	status := "failed"
-	errorMessage := "Insufficient funds"
+	pendingTransaction := &models.Transaction{
+		ID:            "txn_12345",
+		TransactionID: "txn_12345",
+		AccountID:     "acc_12345",
+		Type:          "deposit",
+		Amount:        models.MustParseMoney("300.00"),
+		Status:        "pending",
+	}
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
 	ctx := utils.WithLogger(context.Background(), logger)
 
 	mockTransactionStorage.EXPECT().
-		UpdateTransactionStatusWithError(ctx, transactionID, status, errorMessage).
+		CreateTransaction(ctx, pendingTransaction).
 		Return(nil).
 		Times(1)
 
 	// Execute
-	err := service.UpdateTransactionStatusWithError(ctx, transactionID, status, errorMessage)
+	err := service.CreatePendingTransaction(ctx, pendingTransaction)
 
 	// Assert
 	assert.NoError(t, err)
 }
 
-func TestTransactionService_CreateInitialTransaction_Success(t *testing.T) {
+func TestTransactionService_UpdateTransactionStatus_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -713,34 +1028,25 @@ func TestTransactionService_CreateInitialTransaction_Success(t *testing.T) {
 	mockTransactionStorage := NewMockTransactionStorage(ctrl)
 	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
 
-	accountID := "acc_12345"
-	initialBalance := 1000.00
+	transactionID := "txn_12345"
+	status := "completed"
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
 	ctx := utils.WithLogger(context.Background(), logger)
 
 	mockTransactionStorage.EXPECT().
-		CreateTransaction(ctx, gomock.Any()).
-		DoAndReturn(func(ctx context.Context, transaction *models.Transaction) error {
-			assert.Equal(t, accountID, transaction.AccountID)
-			assert.Equal(t, "deposit", transaction.Type)
-			assert.Equal(t, 1000.00, transaction.Amount)
-			assert.Equal(t, 0.0, transaction.PreviousBalance)
-			assert.Equal(t, 1000.00, transaction.NewBalance)
-			assert.Equal(t, "Initial deposit", transaction.Description)
-			assert.Equal(t, "completed", transaction.Status)
-			return nil
-		}).
+		UpdateTransactionStatus(ctx, transactionID, status).
+		Return(nil).
 		Times(1)
 
 	// Execute
-	err := service.CreateInitialTransaction(ctx, accountID, initialBalance)
+	err := service.UpdateTransactionStatus(ctx, transactionID, status)
 
 	// Assert
 	assert.NoError(t, err)
 }
 
-func TestTransactionService_CreateInitialTransaction_ZeroBalance(t *testing.T) {
+func TestTransactionService_UpdateTransactionStatusWithError_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -748,15 +1054,20 @@ func TestTransactionService_CreateInitialTransaction_ZeroBalance(t *testing.T) {
 	mockTransactionStorage := NewMockTransactionStorage(ctrl)
 	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
 
-	accountID := "acc_12345"
-	initialBalance := 0.0
+	transactionID := "txn_12345"
+	status := "failed"
+	errorMessage := "Insufficient funds"
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
 	ctx := utils.WithLogger(context.Background(), logger)
 
-	// No storage call expected for zero balance
+	mockTransactionStorage.EXPECT().
+		UpdateTransactionStatusWithError(ctx, transactionID, status, errorMessage).
+		Return(nil).
+		Times(1)
+
 	// Execute
-	err := service.CreateInitialTransaction(ctx, accountID, initialBalance)
+	err := service.UpdateTransactionStatusWithError(ctx, transactionID, status, errorMessage)
 
 	// Assert
 	assert.NoError(t, err)
@@ -774,7 +1085,7 @@ func TestTransactionService_GetAccountByID_Success(t *testing.T) {
 	expectedAccount := &models.Account{
 		ID:        accountID,
 		OwnerName: "Test User",
-		Balance:   750.00,
+		Balance:   models.MustParseMoney("750.00"),
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
@@ -806,7 +1117,7 @@ func TestTransactionService_ProcessTransaction_ZeroAmount(t *testing.T) {
 
 	req := &models.TransactionRequest{
 		Type:        "deposit",
-		Amount:      0.0, // Zero amount should fail validation
+		Amount:      models.Money(0), // Zero amount should fail validation
 		Description: "Zero amount test",
 	}
 
@@ -832,7 +1143,7 @@ func TestTransactionService_ProcessTransaction_EmptyAccountID(t *testing.T) {
 
 	req := &models.TransactionRequest{
 		Type:        "deposit",
-		Amount:      100.00,
+		Amount:      models.MustParseMoney("100.00"),
 		Description: "Empty account ID test",
 	}
 
@@ -841,8 +1152,8 @@ func TestTransactionService_ProcessTransaction_EmptyAccountID(t *testing.T) {
 
 	// Mock expectation for empty account ID
 	mockAccountStorage.EXPECT().
-		AtomicBalanceUpdate(ctx, "", "deposit", 100.00).
-		Return(0.0, 0.0, errors.New("account not found")).
+		GetAccountByID(ctx, "").
+		Return(nil, errors.New("account not found")).
 		Times(1)
 
 	// Execute
@@ -861,9 +1172,10 @@ func TestTransactionService_ProcessTransaction_LargeAmount(t *testing.T) {
 	mockAccountStorage := NewMockAccountStorage(ctrl)
 	mockTransactionStorage := NewMockTransactionStorage(ctrl)
 	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_12345", "USD")
 
 	accountID := "acc_12345"
-	largeAmount := 999999999.99
+	largeAmount := models.MustParseMoney("999999999.99")
 
 	req := &models.TransactionRequest{
 		Type:        "deposit",
@@ -875,17 +1187,15 @@ func TestTransactionService_ProcessTransaction_LargeAmount(t *testing.T) {
 	ctx := utils.WithLogger(context.Background(), logger)
 
 	// Mock expectations for large amount
-	mockAccountStorage.EXPECT().
-		AtomicBalanceUpdate(ctx, accountID, "deposit", largeAmount).
-		Return(1000.00, 1000000000.99, nil).
+	expectPostedEntry(t, mockAccountStorage, accountID, "deposit", largeAmount, models.MustParseMoney("1000.00"), models.MustParseMoney("1000000000.99")).
 		Times(1)
 
 	mockTransactionStorage.EXPECT().
 		CreateTransaction(ctx, gomock.Any()).
 		DoAndReturn(func(ctx context.Context, transaction *models.Transaction) error {
 			assert.Equal(t, largeAmount, transaction.Amount)
-			assert.Equal(t, 1000.00, transaction.PreviousBalance)
-			assert.Equal(t, 1000000000.99, transaction.NewBalance)
+			assert.Equal(t, models.MustParseMoney("1000.00"), transaction.PreviousBalance)
+			assert.Equal(t, models.MustParseMoney("1000000000.99"), transaction.NewBalance)
 			return nil
 		}).
 		Times(1)
@@ -906,6 +1216,7 @@ func TestTransactionService_ValidateTransactionRequest_AllScenarios(t *testing.T
 	mockAccountStorage := NewMockAccountStorage(ctrl)
 	mockTransactionStorage := NewMockTransactionStorage(ctrl)
 	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_123", "USD")
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
 	ctx := utils.WithLogger(context.Background(), logger)
@@ -919,7 +1230,7 @@ func TestTransactionService_ValidateTransactionRequest_AllScenarios(t *testing.T
 			name: "Valid deposit",
 			request: &models.TransactionRequest{
 				Type:   "deposit",
-				Amount: 100.00,
+				Amount: models.MustParseMoney("100.00"),
 			},
 			expectedError: "",
 		},
@@ -927,7 +1238,7 @@ func TestTransactionService_ValidateTransactionRequest_AllScenarios(t *testing.T
 			name: "Valid withdraw",
 			request: &models.TransactionRequest{
 				Type:   "withdraw",
-				Amount: 50.00,
+				Amount: models.MustParseMoney("50.00"),
 			},
 			expectedError: "",
 		},
@@ -935,7 +1246,7 @@ func TestTransactionService_ValidateTransactionRequest_AllScenarios(t *testing.T
 			name: "Invalid type",
 			request: &models.TransactionRequest{
 				Type:   "transfer",
-				Amount: 100.00,
+				Amount: models.MustParseMoney("100.00"),
 			},
 			expectedError: "transaction type must be either 'deposit' or 'withdraw'",
 		},
@@ -943,7 +1254,7 @@ func TestTransactionService_ValidateTransactionRequest_AllScenarios(t *testing.T
 			name: "Negative amount",
 			request: &models.TransactionRequest{
 				Type:   "deposit",
-				Amount: -50.00,
+				Amount: models.MustParseMoney("-50.00"),
 			},
 			expectedError: "amount must be greater than 0",
 		},
@@ -951,7 +1262,7 @@ func TestTransactionService_ValidateTransactionRequest_AllScenarios(t *testing.T
 			name: "Zero amount",
 			request: &models.TransactionRequest{
 				Type:   "deposit",
-				Amount: 0.0,
+				Amount: models.Money(0),
 			},
 			expectedError: "amount must be greater than 0",
 		},
@@ -970,9 +1281,7 @@ func TestTransactionService_ValidateTransactionRequest_AllScenarios(t *testing.T
 				assert.Contains(t, err.Error(), tc.expectedError)
 			} else {
 				// For valid requests, we need to mock storage calls
-				mockAccountStorage.EXPECT().
-					AtomicBalanceUpdate(ctx, "acc_123", tc.request.Type, tc.request.Amount).
-					Return(500.00, 500.00+tc.request.Amount, nil).
+				expectPostedEntry(t, mockAccountStorage, "acc_123", tc.request.Type, tc.request.Amount, models.MustParseMoney("500.00"), models.MustParseMoney("500.00")+tc.request.Amount).
 					Times(1)
 
 				mockTransactionStorage.EXPECT().
@@ -992,3 +1301,97 @@ func TestTransactionService_ValidateTransactionRequest_AllScenarios(t *testing.T
 		})
 	}
 }
+
+// expectPostedEntry expects one balanced journal entry for a deposit or
+// withdrawal of amount on accountID, and reports the balance moving from
+// previous to updated
+func expectPostedEntry(t *testing.T, mockAccountStorage *MockAccountStorage, accountID, transactionType string, amount, previous, updated models.Money) *gomock.Call {
+	direction := models.Credit
+	if transactionType == "withdraw" {
+		direction = models.Debit
+	}
+
+	return mockAccountStorage.EXPECT().
+		PostJournalEntry(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, entry *models.JournalEntry) ([]models.BalanceChange, error) {
+			assert.NoError(t, entry.Validate())
+			assert.Len(t, entry.Postings, 2)
+			for _, posting := range entry.Postings {
+				if posting.AccountID == accountID {
+					assert.Equal(t, direction, posting.Direction)
+					assert.Equal(t, amount, posting.Amount)
+				} else {
+					assert.True(t, models.IsSystemAccount(posting.AccountID))
+				}
+			}
+			return []models.BalanceChange{{AccountID: accountID, PreviousBalance: previous, NewBalance: updated}}, nil
+		})
+}
+
+// expectAccountCurrency stubs the account lookup used to resolve a transaction's currency
+func expectAccountCurrency(mockAccountStorage *MockAccountStorage, accountID, currency string) {
+	mockAccountStorage.EXPECT().
+		GetAccountByID(gomock.Any(), accountID).
+		Return(&models.Account{ID: accountID, Currency: currency}, nil).
+		AnyTimes()
+}
+
+func TestTransactionService_ProcessTransaction_CurrencyMismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_eur", "EUR")
+
+	req := &models.TransactionRequest{
+		Type:     "deposit",
+		Amount:   models.MustParseMoney("100.00"),
+		Currency: "USD",
+	}
+
+	// No balance update may happen when the currency does not match
+	transaction, err := service.ProcessTransaction(context.Background(), "acc_eur", req)
+
+	assert.Error(t, err)
+	assert.Nil(t, transaction)
+	assert.Contains(t, err.Error(), "currency mismatch")
+}
+
+func TestTransactionService_ProcessTransaction_CurrencyPrecision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_jpy", "JPY")
+	expectAccountCurrency(mockAccountStorage, "acc_bhd", "BHD")
+
+	ctx := context.Background()
+
+	// JPY has no minor unit
+	transaction, err := service.ProcessTransaction(ctx, "acc_jpy", &models.TransactionRequest{
+		Type:   "deposit",
+		Amount: models.MustParseMoney("100.50"),
+	})
+	assert.Error(t, err)
+	assert.Nil(t, transaction)
+	assert.Contains(t, err.Error(), "more than 0 decimal places for JPY")
+
+	// BHD allows three decimals
+	expectPostedEntry(t, mockAccountStorage, "acc_bhd", "deposit", models.MustParseMoney("1.125"), models.Money(0), models.MustParseMoney("1.125")).
+		Times(1)
+	mockTransactionStorage.EXPECT().
+		CreateTransaction(ctx, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	transaction, err = service.ProcessTransaction(ctx, "acc_bhd", &models.TransactionRequest{
+		Type:   "deposit",
+		Amount: models.MustParseMoney("1.125"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "BHD", transaction.Currency)
+}
@@ -0,0 +1,443 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/audit"
+	"github.com/appy29/banking-ledger-service/metrics"
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/tracing"
+	"github.com/appy29/banking-ledger-service/utils"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ProcessTransfer moves funds between two accounts synchronously. Both balances
+// change in one journal entry and both legs are written to the log together.
+func (s *TransactionService) ProcessTransfer(ctx context.Context, req *models.TransferRequest) (*models.Transfer, error) {
+	ctx, span := tracing.Start(ctx, "TransactionService.ProcessTransfer", trace.SpanKindInternal,
+		attribute.String("ledger.from_account.id", req.FromAccountID),
+		attribute.String("ledger.to_account.id", req.ToAccountID))
+	defer span.End()
+
+	transfer, err := s.processTransfer(ctx, req)
+	observeTransaction("transfer", metrics.ModeSync, err)
+	tracing.RecordError(span, err)
+	return transfer, err
+}
+
+func (s *TransactionService) processTransfer(ctx context.Context, req *models.TransferRequest) (*models.Transfer, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "transaction"),
+		slog.String("operation", "process_transfer_sync"),
+		slog.String("from_account_id", req.FromAccountID),
+		slog.String("to_account_id", req.ToAccountID))
+
+	logger.Info("Starting synchronous transfer processing", slog.String("amount", req.Amount.String()))
+
+	if err := s.validateTransferRequest(ctx, req); err != nil {
+		return nil, err
+	}
+
+	source, currency, err := s.resolveTransferCurrency(ctx, req)
+	if err != nil {
+		logger.Error("Currency check failed", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to process transfer: %w", err)
+	}
+
+	limits, err := s.checkLimits(ctx, source, "transfer_out", "", req.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process transfer: %w", err)
+	}
+
+	transferID := models.NewTransferID()
+	now := time.Now()
+	debit := newTransferLeg(transferID, "transfer_out", req.FromAccountID, req.ToAccountID, req, now)
+	credit := newTransferLeg(transferID, "transfer_in", req.ToAccountID, req.FromAccountID, req, now)
+	logger = logger.With(slog.String("transfer_id", transferID))
+
+	entry := models.NewTransferEntry(debit.TransactionID, credit.TransactionID, req.FromAccountID, req.ToAccountID, req.Amount, currency, req.Description)
+	entry.Limits = limits
+	changes, err := s.accountStorage.PostJournalEntry(ctx, entry)
+	if err != nil {
+		logger.Error("Failed to post journal entry", slog.String("error", err.Error()))
+		err = s.limitBreached(ctx, req.FromAccountID, "transfer_out", "", req.Amount, err)
+		return nil, fmt.Errorf("failed to process transfer: %w", err)
+	}
+	from := balanceChange(changes, req.FromAccountID)
+	to := balanceChange(changes, req.ToAccountID)
+
+	logger.Info("Journal entry posted",
+		slog.String("journal_entry_id", entry.ID),
+		slog.String("from_new_balance", from.NewBalance.String()),
+		slog.String("to_new_balance", to.NewBalance.String()))
+
+	debit.PreviousBalance = from.PreviousBalance
+	debit.NewBalance = from.NewBalance
+	debit.Currency = currency
+	debit.Status = "completed"
+
+	credit.PreviousBalance = to.PreviousBalance
+	credit.NewBalance = to.NewBalance
+	credit.Currency = currency
+	credit.Status = "completed"
+
+	logger.Info("Creating transfer records")
+
+	if err := s.transactionStorage.CreateTransactions(ctx, []*models.Transaction{debit, credit}); err != nil {
+		logger.Error("Failed to save transfer, reversing journal entry", slog.String("error", err.Error()))
+		s.reverseEntry(ctx, entry)
+		return nil, fmt.Errorf("failed to save transfer: %w", err)
+	}
+
+	auditEntry := audit.FromContext(ctx)
+	auditEntry.Target(audit.TargetTransfer, transferID)
+	auditEntry.Balances(changes)
+
+	logger.Info("Synchronous transfer completed successfully")
+	return newTransfer(debit, credit), nil
+}
+
+// CreatePendingTransfer saves both legs of a transfer with "pending" status
+func (s *TransactionService) CreatePendingTransfer(ctx context.Context, transfer *models.Transfer) error {
+	ctx, span := tracing.Start(ctx, "TransactionService.CreatePendingTransfer", trace.SpanKindInternal,
+		attribute.String("ledger.transfer.id", transfer.TransferID))
+	defer span.End()
+
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "transaction"),
+		slog.String("operation", "create_pending_transfer"),
+		slog.String("transfer_id", transfer.TransferID))
+
+	logger.Info("Creating pending transfer", slog.String("amount", transfer.Amount.String()))
+
+	if err := s.transactionStorage.CreateTransactions(ctx, []*models.Transaction{transfer.Debit, transfer.Credit}); err != nil {
+		logger.Error("Failed to create pending transfer", slog.String("error", err.Error()))
+		tracing.RecordError(span, err)
+		return err
+	}
+	audit.FromContext(ctx).Target(audit.TargetTransfer, transfer.TransferID)
+
+	logger.Info("Pending transfer created successfully")
+	return nil
+}
+
+// ProcessTransferAsync applies a queued transfer and completes both pending legs
+func (s *TransactionService) ProcessTransferAsync(ctx context.Context, transferID string, req *models.TransferRequest) (*models.Transfer, error) {
+	ctx, span := tracing.Start(ctx, "TransactionService.ProcessTransferAsync", trace.SpanKindInternal,
+		attribute.String("ledger.transfer.id", transferID))
+	defer span.End()
+
+	transfer, err := s.processTransferAsync(ctx, transferID, req)
+	observeTransaction("transfer", metrics.ModeAsync, err)
+	tracing.RecordError(span, err)
+	if err == nil {
+		metrics.ObserveSince(metrics.PendingTransactionAge.WithLabelValues("transfer"), transfer.Debit.Timestamp)
+	}
+	return transfer, err
+}
+
+func (s *TransactionService) processTransferAsync(ctx context.Context, transferID string, req *models.TransferRequest) (*models.Transfer, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "transaction"),
+		slog.String("operation", "process_transfer_async"),
+		slog.String("transfer_id", transferID))
+
+	logger.Info("Starting async transfer processing", slog.String("amount", req.Amount.String()))
+
+	transfer, err := s.GetTransfer(ctx, transferID)
+	if err != nil {
+		logger.Error("Pending transfer not found", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("pending transfer not found: %w", err)
+	}
+
+	if transfer.Debit.Status != "pending" || transfer.Credit.Status != "pending" {
+		logger.Error("Transfer not in pending state",
+			slog.String("debit_status", transfer.Debit.Status),
+			slog.String("credit_status", transfer.Credit.Status))
+		return nil, models.Errorf(models.ErrNotPending, "transfer is not in pending state: %s", transfer.Status)
+	}
+
+	if err := s.validateTransferRequest(ctx, req); err != nil {
+		s.failTransfer(ctx, transfer, err.Error())
+		return nil, err
+	}
+
+	source, currency, err := s.resolveTransferCurrency(ctx, req)
+	if err != nil {
+		logger.Error("Currency check failed", slog.String("error", err.Error()))
+		if IsBusinessError(err) {
+			s.failTransfer(ctx, transfer, err.Error())
+		}
+		return nil, fmt.Errorf("failed to process transfer: %w", err)
+	}
+
+	limits, err := s.checkLimits(ctx, source, "transfer_out", transfer.Debit.TransactionID, req.Amount)
+	if err != nil {
+		if IsBusinessError(err) {
+			s.failTransfer(ctx, transfer, err.Error())
+		}
+		return nil, fmt.Errorf("failed to process transfer: %w", err)
+	}
+
+	fromPrevious, fromNew, toPrevious, toNew, err := s.accountStorage.ApplyTransfer(ctx,
+		transfer.Debit.TransactionID, transfer.Credit.TransactionID, req.FromAccountID, req.ToAccountID, req.Amount, limits)
+	if err != nil {
+		err = s.limitBreached(ctx, req.FromAccountID, "transfer_out", transfer.Debit.TransactionID, req.Amount, err)
+		if errors.Is(err, models.ErrAlreadyApplied) {
+			// A redelivered message; the balances moved on the first delivery,
+			// so leave the legs for the pending reaper to complete
+			logger.Warn("Transfer was already applied", slog.String("error", err.Error()))
+			return nil, fmt.Errorf("failed to process transfer: %w", err)
+		}
+		logger.Error("Async atomic transfer failed", slog.String("error", err.Error()))
+		// System errors leave the legs pending so the worker can retry
+		if IsBusinessError(err) {
+			s.failTransfer(ctx, transfer, err.Error())
+		}
+		return nil, fmt.Errorf("failed to process transfer: %w", err)
+	}
+
+	debit := *transfer.Debit
+	debit.PreviousBalance = fromPrevious
+	debit.NewBalance = fromNew
+	debit.Currency = currency
+	debit.Status = "completed"
+	debit.ErrorMessage = ""
+
+	credit := *transfer.Credit
+	credit.PreviousBalance = toPrevious
+	credit.NewBalance = toNew
+	credit.Currency = currency
+	credit.Status = "completed"
+	credit.ErrorMessage = ""
+
+	logger.Info("Updating transfer legs to completed status")
+	legs := []*models.Transaction{&debit, &credit}
+	for i, leg := range legs {
+		if err := s.transactionStorage.UpdateTransaction(ctx, leg); err != nil {
+			if i > 0 {
+				// The debit leg is completed and sealed, so it cannot go back
+				// to pending; the balances stay applied and the pending reaper
+				// completes this leg from applied_transactions
+				logger.Error("Failed to update transfer record, leaving it for the reaper",
+					slog.String("transaction_id", leg.TransactionID),
+					slog.String("error", err.Error()))
+				return nil, fmt.Errorf("failed to update transfer: %w", err)
+			}
+
+			if errors.Is(err, models.ErrTransactionSealed) {
+				// The pending reaper completed the debit leg from
+				// applied_transactions first; the balance change stands and
+				// the reaper completes the credit leg too
+				logger.Warn("Transfer was already completed by the reaper",
+					slog.String("transaction_id", leg.TransactionID))
+				return nil, fmt.Errorf("failed to update transfer: %w", err)
+			}
+
+			// Only legs that are both still pending may have their balance
+			// changes rolled back; anything else was settled behind our back
+			if !s.transferPending(ctx, transferID) {
+				logger.Error("Failed to update transfer record, leaving it for the reaper",
+					slog.String("transaction_id", leg.TransactionID),
+					slog.String("error", err.Error()))
+				return nil, fmt.Errorf("failed to update transfer: %w", err)
+			}
+
+			logger.Error("Failed to update transfer record, rolling back",
+				slog.String("transaction_id", leg.TransactionID),
+				slog.String("error", err.Error()))
+
+			// Both legs are still pending, so the worker can retry
+			if revertErr := s.accountStorage.RevertAppliedTransactions(ctx, debit.TransactionID, credit.TransactionID); revertErr != nil {
+				logger.Error("Failed to roll back transfer balances", slog.String("error", revertErr.Error()))
+				return nil, fmt.Errorf("failed to update transfer: %w (rollback failed: %v)", err, revertErr)
+			}
+			return nil, fmt.Errorf("failed to update transfer: %w", err)
+		}
+	}
+
+	logger.Info("Async transfer completed successfully")
+	return newTransfer(&debit, &credit), nil
+}
+
+// transferPending re-reads a transfer's legs and reports whether both are
+// still pending. A failed read counts as not pending.
+func (s *TransactionService) transferPending(ctx context.Context, transferID string) bool {
+	legs, err := s.transactionStorage.GetTransactionsByTransferID(ctx, transferID)
+	if err != nil || len(legs) != 2 {
+		return false
+	}
+	for _, leg := range legs {
+		if leg.Status != "pending" {
+			return false
+		}
+	}
+	return true
+}
+
+// GetTransfer reassembles a transfer from its debit and credit legs
+func (s *TransactionService) GetTransfer(ctx context.Context, transferID string) (*models.Transfer, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "transaction"),
+		slog.String("operation", "get_transfer"),
+		slog.String("transfer_id", transferID))
+
+	logger.Info("Getting transfer by ID")
+
+	if transferID == "" {
+		logger.Error("Transfer ID is required")
+		return nil, models.Errorf(models.ErrInvalidRequest, "transfer ID is required")
+	}
+
+	legs, err := s.transactionStorage.GetTransactionsByTransferID(ctx, transferID)
+	if err != nil {
+		logger.Error("Failed to get transfer from storage", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to get transfer: %w", err)
+	}
+
+	var debit, credit *models.Transaction
+	for i := range legs {
+		switch legs[i].Type {
+		case "transfer_out":
+			debit = &legs[i]
+		case "transfer_in":
+			credit = &legs[i]
+		}
+	}
+
+	if debit == nil || credit == nil {
+		logger.Error("Transfer is missing a leg", slog.Int("legs_found", len(legs)))
+		return nil, fmt.Errorf("failed to get transfer: %w", models.ErrTransferNotFound)
+	}
+
+	return newTransfer(debit, credit), nil
+}
+
+// FailUnprocessedTransfer marks a queued transfer failed once the worker has
+// given up on it. Legs whose balance change was applied are left pending for
+// the reaper to complete.
+func (s *TransactionService) FailUnprocessedTransfer(ctx context.Context, transferID, errorMessage string) error {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "transaction"),
+		slog.String("operation", "fail_unprocessed_transfer"),
+		slog.String("transfer_id", transferID))
+
+	transfer, err := s.GetTransfer(ctx, transferID)
+	if err != nil {
+		return err
+	}
+
+	applied, err := s.accountStorage.GetAppliedTransaction(ctx, transfer.Debit.TransactionID)
+	if err != nil {
+		return fmt.Errorf("failed to check applied transfer: %w", err)
+	}
+	if applied != nil {
+		logger.Warn("Transfer was applied; leaving it for the pending reaper")
+		return nil
+	}
+
+	for _, leg := range []*models.Transaction{transfer.Debit, transfer.Credit} {
+		if leg.Status != "pending" {
+			continue
+		}
+		if err := s.UpdateTransactionStatusWithError(ctx, leg.TransactionID, "failed", errorMessage); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// failTransfer marks both legs of a transfer as failed
+func (s *TransactionService) failTransfer(ctx context.Context, transfer *models.Transfer, errorMessage string) {
+	s.UpdateTransactionStatusWithError(ctx, transfer.Debit.TransactionID, "failed", errorMessage)
+	s.UpdateTransactionStatusWithError(ctx, transfer.Credit.TransactionID, "failed", errorMessage)
+}
+
+// resolveTransferCurrency checks that both accounts share a currency that also
+// matches the request, and returns it with the source account
+func (s *TransactionService) resolveTransferCurrency(ctx context.Context, req *models.TransferRequest) (*models.Account, string, error) {
+	from, fromCurrency, err := s.resolveAccount(ctx, req.FromAccountID, req.Currency, req.Amount)
+	if err != nil {
+		return nil, "", err
+	}
+
+	toCurrency, err := s.resolveCurrency(ctx, req.ToAccountID, fromCurrency, req.Amount)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return from, toCurrency, nil
+}
+
+func (s *TransactionService) validateTransferRequest(ctx context.Context, req *models.TransferRequest) error {
+	logger := utils.LoggerFromContext(ctx).With(slog.String("service", "transaction"))
+
+	if req.FromAccountID == "" || req.ToAccountID == "" {
+		logger.Error("Transfer is missing an account ID")
+		return models.Errorf(models.ErrInvalidRequest, "both source and destination account IDs are required")
+	}
+
+	if req.FromAccountID == req.ToAccountID {
+		logger.Error("Transfer source and destination are the same", slog.String("account_id", req.FromAccountID))
+		return models.ErrSameAccount
+	}
+
+	if req.Amount <= 0 {
+		logger.Error("Invalid amount", slog.String("amount", req.Amount.String()))
+		return models.Errorf(models.ErrInvalidAmount, "amount must be greater than 0")
+	}
+
+	logger.Info("Transfer request validated successfully")
+	return nil
+}
+
+// newTransferLeg builds one side of a transfer; callers fill in balances and status
+func newTransferLeg(transferID, legType, accountID, counterpartyID string, req *models.TransferRequest, timestamp time.Time) *models.Transaction {
+	transactionID := models.NewTransactionID()
+	return &models.Transaction{
+		ID:                    transactionID,
+		TransactionID:         transactionID,
+		AccountID:             accountID,
+		Type:                  legType,
+		Amount:                req.Amount,
+		Currency:              models.NormalizeCurrency(req.Currency),
+		Description:           req.Description,
+		Timestamp:             timestamp,
+		TransferID:            transferID,
+		CounterpartyAccountID: counterpartyID,
+	}
+}
+
+// NewPendingTransfer builds a transfer whose legs are waiting for a worker
+func NewPendingTransfer(req *models.TransferRequest) *models.Transfer {
+	transferID := models.NewTransferID()
+	now := time.Now()
+
+	debit := newTransferLeg(transferID, "transfer_out", req.FromAccountID, req.ToAccountID, req, now)
+	debit.Status = "pending"
+
+	credit := newTransferLeg(transferID, "transfer_in", req.ToAccountID, req.FromAccountID, req, now)
+	credit.Status = "pending"
+
+	return newTransfer(debit, credit)
+}
+
+func newTransfer(debit, credit *models.Transaction) *models.Transfer {
+	return &models.Transfer{
+		TransferID:    debit.TransferID,
+		FromAccountID: debit.AccountID,
+		ToAccountID:   credit.AccountID,
+		Amount:        debit.Amount,
+		Currency:      debit.Currency,
+		Description:   debit.Description,
+		Status:        debit.Status,
+		Debit:         debit,
+		Credit:        credit,
+	}
+}
@@ -0,0 +1,408 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/utils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTransactionService_ProcessTransfer_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_from", "USD")
+	expectAccountCurrency(mockAccountStorage, "acc_to", "USD")
+
+	req := &models.TransferRequest{
+		FromAccountID: "acc_from",
+		ToAccountID:   "acc_to",
+		Amount:        models.MustParseMoney("200.00"),
+		Description:   "Rent share",
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	ctx := utils.WithLogger(context.Background(), logger)
+
+	expectPostedTransfer(t, mockAccountStorage, "acc_from", "acc_to", models.MustParseMoney("200.00"),
+		models.MustParseMoney("500.00"), models.MustParseMoney("300.00"), models.MustParseMoney("100.00"), models.MustParseMoney("300.00"))
+
+	mockTransactionStorage.EXPECT().
+		CreateTransactions(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, transactions []*models.Transaction) error {
+			assert.Len(t, transactions, 2)
+			debit, credit := transactions[0], transactions[1]
+
+			assert.Equal(t, "transfer_out", debit.Type)
+			assert.Equal(t, "acc_from", debit.AccountID)
+			assert.Equal(t, "acc_to", debit.CounterpartyAccountID)
+			assert.Equal(t, models.MustParseMoney("500.00"), debit.PreviousBalance)
+			assert.Equal(t, models.MustParseMoney("300.00"), debit.NewBalance)
+
+			assert.Equal(t, "transfer_in", credit.Type)
+			assert.Equal(t, "acc_to", credit.AccountID)
+			assert.Equal(t, models.MustParseMoney("100.00"), credit.PreviousBalance)
+			assert.Equal(t, models.MustParseMoney("300.00"), credit.NewBalance)
+
+			assert.NotEmpty(t, debit.TransferID)
+			assert.Equal(t, debit.TransferID, credit.TransferID)
+			return nil
+		}).
+		Times(1)
+
+	transfer, err := service.ProcessTransfer(ctx, req)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, transfer)
+	assert.Equal(t, "completed", transfer.Status)
+	assert.Equal(t, "acc_from", transfer.FromAccountID)
+	assert.Equal(t, "acc_to", transfer.ToAccountID)
+	assert.Equal(t, transfer.TransferID, transfer.Debit.TransferID)
+}
+
+func TestTransactionService_ProcessTransfer_SameAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+
+	req := &models.TransferRequest{
+		FromAccountID: "acc_same",
+		ToAccountID:   "acc_same",
+		Amount:        models.MustParseMoney("50.00"),
+	}
+
+	transfer, err := service.ProcessTransfer(context.Background(), req)
+
+	assert.Error(t, err)
+	assert.Nil(t, transfer)
+	assert.Contains(t, err.Error(), "cannot transfer to the same account")
+}
+
+func TestTransactionService_ProcessTransfer_StorageFailureRollsBack(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_from", "USD")
+	expectAccountCurrency(mockAccountStorage, "acc_to", "USD")
+
+	req := &models.TransferRequest{
+		FromAccountID: "acc_from",
+		ToAccountID:   "acc_to",
+		Amount:        models.MustParseMoney("75.00"),
+	}
+
+	ctx := context.Background()
+
+	expectPostedTransfer(t, mockAccountStorage, "acc_from", "acc_to", models.MustParseMoney("75.00"),
+		models.MustParseMoney("100.00"), models.MustParseMoney("25.00"), models.MustParseMoney("0.00"), models.MustParseMoney("75.00"))
+
+	mockTransactionStorage.EXPECT().
+		CreateTransactions(ctx, gomock.Any()).
+		Return(errors.New("database error")).
+		Times(1)
+
+	// Expect the transfer entry to be reversed
+	expectPostedTransfer(t, mockAccountStorage, "acc_to", "acc_from", models.MustParseMoney("75.00"),
+		models.MustParseMoney("75.00"), models.MustParseMoney("0.00"), models.MustParseMoney("25.00"), models.MustParseMoney("100.00"))
+
+	transfer, err := service.ProcessTransfer(ctx, req)
+
+	assert.Error(t, err)
+	assert.Nil(t, transfer)
+	assert.Contains(t, err.Error(), "failed to save transfer")
+}
+
+func TestTransactionService_ProcessTransferAsync_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_from", "USD")
+	expectAccountCurrency(mockAccountStorage, "acc_to", "USD")
+
+	req := &models.TransferRequest{
+		FromAccountID: "acc_from",
+		ToAccountID:   "acc_to",
+		Amount:        models.MustParseMoney("40.00"),
+	}
+	pending := NewPendingTransfer(req)
+	ctx := context.Background()
+
+	mockTransactionStorage.EXPECT().
+		GetTransactionsByTransferID(ctx, pending.TransferID).
+		Return([]models.Transaction{*pending.Debit, *pending.Credit}, nil).
+		Times(1)
+
+	mockAccountStorage.EXPECT().
+		ApplyTransfer(ctx, pending.Debit.TransactionID, pending.Credit.TransactionID, "acc_from", "acc_to", models.MustParseMoney("40.00"), nil).
+		Return(models.MustParseMoney("100.00"), models.MustParseMoney("60.00"), models.MustParseMoney("10.00"), models.MustParseMoney("50.00"), nil).
+		Times(1)
+
+	mockTransactionStorage.EXPECT().
+		UpdateTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, transaction *models.Transaction) error {
+			assert.Equal(t, "completed", transaction.Status)
+			assert.Equal(t, pending.TransferID, transaction.TransferID)
+			return nil
+		}).
+		Times(2)
+
+	transfer, err := service.ProcessTransferAsync(ctx, pending.TransferID, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "completed", transfer.Status)
+	assert.Equal(t, models.MustParseMoney("60.00"), transfer.Debit.NewBalance)
+	assert.Equal(t, models.MustParseMoney("50.00"), transfer.Credit.NewBalance)
+}
+
+func TestTransactionService_ProcessTransferAsync_CreditLegUpdateFailsLeavesItForReaper(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_from", "USD")
+	expectAccountCurrency(mockAccountStorage, "acc_to", "USD")
+
+	req := &models.TransferRequest{
+		FromAccountID: "acc_from",
+		ToAccountID:   "acc_to",
+		Amount:        models.MustParseMoney("40.00"),
+	}
+	pending := NewPendingTransfer(req)
+	ctx := context.Background()
+
+	mockTransactionStorage.EXPECT().
+		GetTransactionsByTransferID(ctx, pending.TransferID).
+		Return([]models.Transaction{*pending.Debit, *pending.Credit}, nil)
+	mockAccountStorage.EXPECT().
+		ApplyTransfer(ctx, pending.Debit.TransactionID, pending.Credit.TransactionID, "acc_from", "acc_to", models.MustParseMoney("40.00"), nil).
+		Return(models.MustParseMoney("100.00"), models.MustParseMoney("60.00"), models.MustParseMoney("10.00"), models.MustParseMoney("50.00"), nil)
+	gomock.InOrder(
+		mockTransactionStorage.EXPECT().UpdateTransaction(ctx, gomock.Any()).Return(nil),
+		mockTransactionStorage.EXPECT().UpdateTransaction(ctx, gomock.Any()).Return(errors.New("connection reset")),
+	)
+
+	// The sealed debit leg is neither reverted nor set back to pending
+	mockAccountStorage.EXPECT().RevertAppliedTransactions(gomock.Any(), gomock.Any()).Times(0)
+	mockTransactionStorage.EXPECT().UpdateTransactionStatus(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := service.ProcessTransferAsync(ctx, pending.TransferID, req)
+
+	assert.Error(t, err)
+	assert.False(t, IsBusinessError(err))
+}
+
+// setupDebitLegUpdateFailure applies a pending transfer whose debit leg
+// update then fails with updateErr
+func setupDebitLegUpdateFailure(t *testing.T, updateErr error) (*TransactionService, *MockAccountStorage, *MockTransactionStorage, *models.Transfer, *models.TransferRequest) {
+	ctrl := gomock.NewController(t)
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_from", "USD")
+	expectAccountCurrency(mockAccountStorage, "acc_to", "USD")
+
+	req := &models.TransferRequest{
+		FromAccountID: "acc_from",
+		ToAccountID:   "acc_to",
+		Amount:        models.MustParseMoney("40.00"),
+	}
+	pending := NewPendingTransfer(req)
+
+	mockTransactionStorage.EXPECT().
+		GetTransactionsByTransferID(gomock.Any(), pending.TransferID).
+		Return([]models.Transaction{*pending.Debit, *pending.Credit}, nil)
+	mockAccountStorage.EXPECT().
+		ApplyTransfer(gomock.Any(), pending.Debit.TransactionID, pending.Credit.TransactionID, "acc_from", "acc_to", models.MustParseMoney("40.00"), nil).
+		Return(models.MustParseMoney("100.00"), models.MustParseMoney("60.00"), models.MustParseMoney("10.00"), models.MustParseMoney("50.00"), nil)
+	mockTransactionStorage.EXPECT().UpdateTransaction(gomock.Any(), gomock.Any()).Return(updateErr)
+
+	return service, mockAccountStorage, mockTransactionStorage, pending, req
+}
+
+func TestTransactionService_ProcessTransferAsync_DebitLegUpdateFailsRollsBack(t *testing.T) {
+	service, mockAccountStorage, mockTransactionStorage, pending, req := setupDebitLegUpdateFailure(t, errors.New("connection reset"))
+
+	// Both legs are still pending, so the balances go back for a retry
+	mockTransactionStorage.EXPECT().
+		GetTransactionsByTransferID(gomock.Any(), pending.TransferID).
+		Return([]models.Transaction{*pending.Debit, *pending.Credit}, nil)
+	mockAccountStorage.EXPECT().
+		RevertAppliedTransactions(gomock.Any(), pending.Debit.TransactionID, pending.Credit.TransactionID).
+		Return(nil)
+
+	_, err := service.ProcessTransferAsync(context.Background(), pending.TransferID, req)
+
+	assert.Error(t, err)
+	assert.False(t, IsBusinessError(err))
+}
+
+func TestTransactionService_ProcessTransferAsync_DebitLegSettledLeavesItForReaper(t *testing.T) {
+	service, mockAccountStorage, mockTransactionStorage, pending, req := setupDebitLegUpdateFailure(t, errors.New("connection reset"))
+
+	// The write landed despite the error, so the debit leg is completed
+	completed := *pending.Debit
+	completed.Status = "completed"
+	mockTransactionStorage.EXPECT().
+		GetTransactionsByTransferID(gomock.Any(), pending.TransferID).
+		Return([]models.Transaction{completed, *pending.Credit}, nil)
+	mockAccountStorage.EXPECT().RevertAppliedTransactions(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := service.ProcessTransferAsync(context.Background(), pending.TransferID, req)
+
+	assert.Error(t, err)
+	assert.False(t, IsBusinessError(err))
+}
+
+func TestTransactionService_ProcessTransferAsync_CompletedByReaper(t *testing.T) {
+	service, mockAccountStorage, _, pending, req := setupDebitLegUpdateFailure(t,
+		models.Errorf(models.ErrTransactionSealed, "transaction %s is sealed and cannot be changed", "txn_1"))
+
+	mockAccountStorage.EXPECT().RevertAppliedTransactions(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := service.ProcessTransferAsync(context.Background(), pending.TransferID, req)
+
+	assert.ErrorIs(t, err, models.ErrTransactionSealed)
+}
+
+func TestTransactionService_ProcessTransferAsync_InsufficientFundsFailsBothLegs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_from", "USD")
+	expectAccountCurrency(mockAccountStorage, "acc_to", "USD")
+
+	req := &models.TransferRequest{
+		FromAccountID: "acc_from",
+		ToAccountID:   "acc_to",
+		Amount:        models.MustParseMoney("500.00"),
+	}
+	pending := NewPendingTransfer(req)
+	ctx := context.Background()
+
+	mockTransactionStorage.EXPECT().
+		GetTransactionsByTransferID(ctx, pending.TransferID).
+		Return([]models.Transaction{*pending.Debit, *pending.Credit}, nil).
+		Times(1)
+
+	mockAccountStorage.EXPECT().
+		ApplyTransfer(ctx, pending.Debit.TransactionID, pending.Credit.TransactionID, "acc_from", "acc_to", models.MustParseMoney("500.00"), nil).
+		Return(models.Money(0), models.Money(0), models.Money(0), models.Money(0), &models.InsufficientFundsError{AccountID: "acc_from", Balance: models.MustParseMoney("100.00"), Requested: models.MustParseMoney("500.00")}).
+		Times(1)
+
+	mockTransactionStorage.EXPECT().
+		UpdateTransactionStatusWithError(ctx, pending.Debit.TransactionID, "failed", gomock.Any()).
+		Return(nil).
+		Times(1)
+	mockTransactionStorage.EXPECT().
+		UpdateTransactionStatusWithError(ctx, pending.Credit.TransactionID, "failed", gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	transfer, err := service.ProcessTransferAsync(ctx, pending.TransferID, req)
+
+	assert.Error(t, err)
+	assert.Nil(t, transfer)
+	assert.Contains(t, err.Error(), "insufficient funds")
+}
+
+func TestTransactionService_ProcessTransferAsync_NotPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+
+	req := &models.TransferRequest{
+		FromAccountID: "acc_from",
+		ToAccountID:   "acc_to",
+		Amount:        models.MustParseMoney("40.00"),
+	}
+	pending := NewPendingTransfer(req)
+	pending.Debit.Status = "completed"
+	pending.Credit.Status = "completed"
+	ctx := context.Background()
+
+	mockTransactionStorage.EXPECT().
+		GetTransactionsByTransferID(ctx, pending.TransferID).
+		Return([]models.Transaction{*pending.Debit, *pending.Credit}, nil).
+		Times(1)
+
+	transfer, err := service.ProcessTransferAsync(ctx, pending.TransferID, req)
+
+	assert.Error(t, err)
+	assert.Nil(t, transfer)
+	assert.Contains(t, err.Error(), "transfer is not in pending state")
+}
+
+func TestTransactionService_ProcessTransfer_CurrencyMismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockTransactionStorage := NewMockTransactionStorage(ctrl)
+	service := NewTransactionService(mockAccountStorage, mockTransactionStorage)
+	expectAccountCurrency(mockAccountStorage, "acc_from", "USD")
+	expectAccountCurrency(mockAccountStorage, "acc_to", "EUR")
+
+	req := &models.TransferRequest{
+		FromAccountID: "acc_from",
+		ToAccountID:   "acc_to",
+		Amount:        models.MustParseMoney("50.00"),
+	}
+
+	transfer, err := service.ProcessTransfer(context.Background(), req)
+
+	assert.Error(t, err)
+	assert.Nil(t, transfer)
+	assert.Contains(t, err.Error(), "currency mismatch: account acc_to is denominated in EUR, not USD")
+}
+
+// expectPostedTransfer expects one balanced journal entry debiting fromID and
+// crediting toID with amount, and reports both accounts' balance changes
+func expectPostedTransfer(t *testing.T, mockAccountStorage *MockAccountStorage, fromID, toID string, amount, fromPrevious, fromUpdated, toPrevious, toUpdated models.Money) *gomock.Call {
+	return mockAccountStorage.EXPECT().
+		PostJournalEntry(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, entry *models.JournalEntry) ([]models.BalanceChange, error) {
+			assert.NoError(t, entry.Validate())
+			for _, posting := range entry.Postings {
+				assert.Equal(t, amount, posting.Amount)
+				switch posting.AccountID {
+				case fromID:
+					assert.Equal(t, models.Debit, posting.Direction)
+				case toID:
+					assert.Equal(t, models.Credit, posting.Direction)
+				default:
+					t.Errorf("unexpected posting to %s", posting.AccountID)
+				}
+			}
+			return []models.BalanceChange{
+				{AccountID: fromID, PreviousBalance: fromPrevious, NewBalance: fromUpdated},
+				{AccountID: toID, PreviousBalance: toPrevious, NewBalance: toUpdated},
+			}, nil
+		})
+}
@@ -0,0 +1,504 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/audit"
+	"github.com/appy29/banking-ledger-service/metrics"
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/queue"
+	"github.com/appy29/banking-ledger-service/utils"
+)
+
+const (
+	// webhookLease is how long a claimed delivery is hidden from other
+	// dispatchers; it must outlast the request timeout
+	webhookLease = 2 * time.Minute
+
+	// webhookBatchSize caps how many deliveries one pass claims; they are sent
+	// concurrently, so one slow subscriber does not hold up the rest
+	webhookBatchSize = 20
+
+	// maxWebhookResponseBody caps how much of a subscriber's answer is logged
+	maxWebhookResponseBody = 1024
+)
+
+// DefaultWebhookRetryPolicy retries a delivery eight times over about four
+// hours: 30s, 1m, 2m, 4m, 8m, 16m, 32m and an hour apart
+var DefaultWebhookRetryPolicy = queue.RetryPolicy{
+	MaxRetries: 8,
+	BaseDelay:  30 * time.Second,
+	MaxDelay:   time.Hour,
+}
+
+// WebhookService manages webhook subscriptions, queues a delivery for every
+// subscriber of a ledger event and sends them. Deliveries are signed with the
+// subscription's secret and retried with exponential backoff; every attempt
+// is logged.
+type WebhookService struct {
+	accountStorage AccountStorage
+	webhookStorage WebhookStorage
+	retryPolicy    queue.RetryPolicy
+	client         *http.Client
+}
+
+func NewWebhookService(accountStorage AccountStorage, webhookStorage WebhookStorage, retryPolicy queue.RetryPolicy, timeout time.Duration) *WebhookService {
+	return &WebhookService{
+		accountStorage: accountStorage,
+		webhookStorage: webhookStorage,
+		retryPolicy:    retryPolicy,
+		client: &http.Client{
+			Timeout: timeout,
+			// A redirect is answered as a failure rather than followed, which
+			// would turn the POST into a GET
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// CreateWebhook subscribes a URL to events on an account, or on every account
+// when accountID is empty. The returned subscription carries the secret its
+// deliveries are signed with; it is not shown again.
+func (s *WebhookService) CreateWebhook(ctx context.Context, accountID string, req *models.CreateWebhookRequest) (*models.WebhookSubscription, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "webhook"),
+		slog.String("operation", "create_webhook"),
+		slog.String("account_id", accountID))
+
+	now := time.Now().UTC()
+	subscription := &models.WebhookSubscription{
+		ID:                  models.NewWebhookID(),
+		AccountID:           accountID,
+		URL:                 strings.TrimSpace(req.URL),
+		Events:              uniqueEvents(req.Events),
+		LowBalanceThreshold: req.LowBalanceThreshold,
+		Description:         strings.TrimSpace(req.Description),
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+	if err := subscription.Validate(); err != nil {
+		logger.Error("Validation failed", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if !subscription.IsGlobal() {
+		account, err := s.accountStorage.GetAccountByID(ctx, accountID)
+		if err != nil {
+			logger.Error("Failed to get account", slog.String("error", err.Error()))
+			return nil, err
+		}
+		if subscription.LowBalanceThreshold != nil {
+			if err := models.ValidateAmountPrecision(*subscription.LowBalanceThreshold, account.Currency); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	secret, err := models.NewWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	subscription.Secret = secret
+
+	if err := s.webhookStorage.CreateWebhookSubscription(ctx, subscription); err != nil {
+		logger.Error("Failed to create webhook", slog.String("error", err.Error()))
+		return nil, err
+	}
+	audit.FromContext(ctx).Target(audit.TargetWebhook, subscription.ID)
+
+	logger.Info("Webhook created",
+		slog.String("webhook_id", subscription.ID),
+		slog.Any("events", subscription.Events))
+	return subscription, nil
+}
+
+// uniqueEvents trims the requested event types and drops repeats
+func uniqueEvents(events []string) []string {
+	unique := make([]string, 0, len(events))
+	seen := make(map[string]bool, len(events))
+	for _, event := range events {
+		event = strings.TrimSpace(event)
+		if !seen[event] {
+			seen[event] = true
+			unique = append(unique, event)
+		}
+	}
+	return unique
+}
+
+// GetWebhook returns a subscription by ID
+func (s *WebhookService) GetWebhook(ctx context.Context, webhookID string) (*models.WebhookSubscription, error) {
+	return s.webhookStorage.GetWebhookSubscription(ctx, webhookID)
+}
+
+// GetWebhooks returns an account's subscriptions, or the global ones when
+// accountID is empty, newest first
+func (s *WebhookService) GetWebhooks(ctx context.Context, accountID string) ([]models.WebhookSubscription, error) {
+	if accountID != "" {
+		if _, err := s.accountStorage.GetAccountByID(ctx, accountID); err != nil {
+			return nil, err
+		}
+	}
+	return s.webhookStorage.GetWebhookSubscriptions(ctx, accountID)
+}
+
+// DeleteWebhook removes a subscription along with its deliveries; deliveries
+// still pending are not sent
+func (s *WebhookService) DeleteWebhook(ctx context.Context, webhookID string) error {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "webhook"),
+		slog.String("operation", "delete_webhook"),
+		slog.String("webhook_id", webhookID))
+
+	if err := s.webhookStorage.DeleteWebhookSubscription(ctx, webhookID); err != nil {
+		logger.Error("Failed to delete webhook", slog.String("error", err.Error()))
+		return err
+	}
+	audit.FromContext(ctx).Target(audit.TargetWebhook, webhookID)
+
+	logger.Info("Webhook deleted")
+	return nil
+}
+
+// ListDeliveries returns a page of a subscription's deliveries, newest first,
+// optionally filtered by status
+func (s *WebhookService) ListDeliveries(ctx context.Context, webhookID, status string, page, limit int) ([]models.WebhookDelivery, int64, error) {
+	switch status {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliveryDelivered, models.WebhookDeliveryFailed:
+	default:
+		return nil, 0, models.Errorf(models.ErrInvalidRequest, "invalid delivery status: %s", status)
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	if _, err := s.webhookStorage.GetWebhookSubscription(ctx, webhookID); err != nil {
+		return nil, 0, err
+	}
+	return s.webhookStorage.GetWebhookDeliveries(ctx, webhookID, status, page, limit)
+}
+
+// GetDelivery returns one of a subscription's deliveries with its attempt log
+func (s *WebhookService) GetDelivery(ctx context.Context, webhookID, deliveryID string) (*models.WebhookDelivery, error) {
+	delivery, err := s.webhookStorage.GetWebhookDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	// Reached through the subscription, so a caller allowed to read one
+	// subscription cannot read another's deliveries
+	if delivery.SubscriptionID != webhookID {
+		return nil, models.Errorf(models.ErrWebhookDeliveryNotFound, "webhook delivery not found: %s", deliveryID)
+	}
+	return delivery, nil
+}
+
+// Redeliver sends a delivered or failed delivery's event to its subscriber
+// again, as a new delivery with a fresh retry budget. The event keeps its ID,
+// so receivers can tell it is one they may have seen.
+func (s *WebhookService) Redeliver(ctx context.Context, webhookID, deliveryID string) (*models.WebhookDelivery, error) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "webhook"),
+		slog.String("operation", "redeliver"),
+		slog.String("webhook_id", webhookID),
+		slog.String("delivery_id", deliveryID))
+
+	original, err := s.GetDelivery(ctx, webhookID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if !original.IsFinished() {
+		return nil, models.Errorf(models.ErrWebhookDeliveryPending, "webhook delivery %s is still pending", deliveryID)
+	}
+
+	now := time.Now().UTC()
+	redelivery := &models.WebhookDelivery{
+		ID:             models.NewWebhookDeliveryID(),
+		SubscriptionID: original.SubscriptionID,
+		EventID:        original.EventID,
+		EventType:      original.EventType,
+		AccountID:      original.AccountID,
+		Payload:        original.Payload,
+		Status:         models.WebhookDeliveryPending,
+		NextAttemptAt:  &now,
+		RedeliveryOf:   original.ID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.webhookStorage.CreateWebhookDeliveries(ctx, []*models.WebhookDelivery{redelivery}); err != nil {
+		logger.Error("Failed to queue redelivery", slog.String("error", err.Error()))
+		return nil, err
+	}
+	audit.FromContext(ctx).Target(audit.TargetWebhookDelivery, redelivery.ID)
+
+	logger.Info("Webhook delivery queued again", slog.String("redelivery_id", redelivery.ID))
+	return redelivery, nil
+}
+
+// AccountCreated notifies subscribers of a new account
+func (s *WebhookService) AccountCreated(ctx context.Context, account *models.Account) {
+	s.publish(ctx, models.EventAccountCreated, account.ID, account)
+}
+
+// TransactionCompleted notifies subscribers of an applied deposit or
+// withdrawal, and those whose low balance threshold it crossed
+func (s *WebhookService) TransactionCompleted(ctx context.Context, transaction *models.Transaction) {
+	s.publish(ctx, models.EventTransactionCompleted, transaction.AccountID, transaction)
+	s.publishBalanceLow(ctx, transaction)
+}
+
+// TransactionFailed notifies subscribers of a queued transaction marked failed
+func (s *WebhookService) TransactionFailed(ctx context.Context, transaction *models.Transaction) {
+	s.publish(ctx, models.EventTransactionFailed, transaction.AccountID, transaction)
+}
+
+// publish queues one event carrying data for every subscriber of eventType on
+// the account
+func (s *WebhookService) publish(ctx context.Context, eventType, accountID string, data interface{}) {
+	// The event is raised once the change it reports is saved, so it is
+	// queued even if the caller has gone away
+	ctx = context.WithoutCancel(ctx)
+	logger := s.eventLogger(ctx, eventType, accountID)
+
+	subscriptions, err := s.webhookStorage.GetSubscribedWebhooks(ctx, accountID, eventType)
+	if err != nil {
+		logger.Error("Failed to find webhook subscribers", slog.String("error", err.Error()))
+		return
+	}
+	if len(subscriptions) == 0 {
+		return
+	}
+
+	event, err := models.NewWebhookEvent(eventType, accountID, data)
+	if err != nil {
+		logger.Error("Failed to create webhook event", slog.String("error", err.Error()))
+		return
+	}
+
+	deliveries := make([]*models.WebhookDelivery, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		delivery, err := models.NewWebhookDelivery(subscription.ID, event)
+		if err != nil {
+			logger.Error("Failed to create webhook delivery", slog.String("error", err.Error()))
+			return
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	s.queueDeliveries(ctx, logger, deliveries)
+}
+
+// publishBalanceLow queues a balance.low event for each subscriber whose
+// threshold the transaction took the balance below. Each event carries the
+// subscriber's own threshold.
+func (s *WebhookService) publishBalanceLow(ctx context.Context, transaction *models.Transaction) {
+	if transaction.NewBalance >= transaction.PreviousBalance {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	logger := s.eventLogger(ctx, models.EventBalanceLow, transaction.AccountID)
+
+	subscriptions, err := s.webhookStorage.GetSubscribedWebhooks(ctx, transaction.AccountID, models.EventBalanceLow)
+	if err != nil {
+		logger.Error("Failed to find webhook subscribers", slog.String("error", err.Error()))
+		return
+	}
+
+	var deliveries []*models.WebhookDelivery
+	for _, subscription := range subscriptions {
+		if !subscription.BalanceFellBelow(transaction.PreviousBalance, transaction.NewBalance) {
+			continue
+		}
+
+		event, err := models.NewWebhookEvent(models.EventBalanceLow, transaction.AccountID, &models.BalanceLowData{
+			AccountID:       transaction.AccountID,
+			TransactionID:   transaction.TransactionID,
+			PreviousBalance: transaction.PreviousBalance,
+			Balance:         transaction.NewBalance,
+			Threshold:       *subscription.LowBalanceThreshold,
+			Currency:        transaction.Currency,
+		})
+		if err == nil {
+			var delivery *models.WebhookDelivery
+			if delivery, err = models.NewWebhookDelivery(subscription.ID, event); err == nil {
+				deliveries = append(deliveries, delivery)
+			}
+		}
+		if err != nil {
+			logger.Error("Failed to create webhook delivery", slog.String("error", err.Error()))
+			return
+		}
+	}
+	if len(deliveries) > 0 {
+		s.queueDeliveries(ctx, logger, deliveries)
+	}
+}
+
+func (s *WebhookService) queueDeliveries(ctx context.Context, logger *slog.Logger, deliveries []*models.WebhookDelivery) {
+	if err := s.webhookStorage.CreateWebhookDeliveries(ctx, deliveries); err != nil {
+		logger.Error("Failed to queue webhook deliveries", slog.String("error", err.Error()))
+		return
+	}
+	logger.Info("Webhook deliveries queued",
+		slog.String("event_id", deliveries[0].EventID),
+		slog.Int("deliveries", len(deliveries)))
+}
+
+func (s *WebhookService) eventLogger(ctx context.Context, eventType, accountID string) *slog.Logger {
+	return utils.LoggerFromContext(ctx).With(
+		slog.String("service", "webhook"),
+		slog.String("operation", "publish"),
+		slog.String("event_type", eventType),
+		slog.String("account_id", accountID))
+}
+
+// DeliverDue sends the deliveries that are due, batch by batch, and returns
+// how many it attempted
+func (s *WebhookService) DeliverDue(ctx context.Context) (int, error) {
+	attempted := 0
+	for {
+		now := time.Now()
+		deliveries, err := s.webhookStorage.ClaimDueWebhookDeliveries(ctx, now, now.Add(webhookLease), webhookBatchSize)
+		if err != nil {
+			return attempted, err
+		}
+
+		var wg sync.WaitGroup
+		for i := range deliveries {
+			wg.Add(1)
+			go func(delivery *models.WebhookDelivery) {
+				defer wg.Done()
+				s.deliver(ctx, delivery)
+			}(&deliveries[i])
+		}
+		wg.Wait()
+
+		attempted += len(deliveries)
+		if len(deliveries) < webhookBatchSize || ctx.Err() != nil {
+			return attempted, ctx.Err()
+		}
+	}
+}
+
+// deliver makes one attempt at a claimed delivery and records how it went. If
+// the outcome cannot be saved the claim lapses and the delivery is attempted
+// again.
+func (s *WebhookService) deliver(ctx context.Context, delivery *models.WebhookDelivery) {
+	logger := utils.LoggerFromContext(ctx).With(
+		slog.String("service", "webhook"),
+		slog.String("operation", "deliver"),
+		slog.String("delivery_id", delivery.ID),
+		slog.String("webhook_id", delivery.SubscriptionID),
+		slog.String("event_type", delivery.EventType))
+
+	subscription, err := s.webhookStorage.GetWebhookSubscription(ctx, delivery.SubscriptionID)
+	if err != nil {
+		logger.Error("Failed to get webhook subscription", slog.String("error", err.Error()))
+		return
+	}
+
+	attempt := s.post(ctx, subscription, delivery)
+	metrics.WebhookAttemptDuration.WithLabelValues(delivery.EventType).Observe(float64(attempt.DurationMs) / 1000)
+
+	now := time.Now().UTC()
+	delivery.Attempts = attempt.Attempt
+	delivery.LastStatusCode = attempt.StatusCode
+	delivery.LastError = attempt.Error
+	delivery.UpdatedAt = now
+
+	outcome := metrics.WebhookDelivered
+	switch {
+	case attempt.Succeeded():
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+	case delivery.Attempts > s.retryPolicy.MaxRetries:
+		outcome = metrics.WebhookGaveUp
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+	default:
+		outcome = metrics.WebhookRetry
+		next := now.Add(s.retryPolicy.Delay(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+	}
+	metrics.WebhookAttempts.WithLabelValues(delivery.EventType, outcome).Inc()
+
+	if err := s.webhookStorage.RecordWebhookAttempt(ctx, delivery, attempt); err != nil {
+		logger.Error("Failed to record webhook delivery attempt", slog.String("error", err.Error()))
+		return
+	}
+
+	switch outcome {
+	case metrics.WebhookDelivered:
+		logger.Info("Webhook delivered", slog.Int("attempt", attempt.Attempt), slog.Int("status_code", attempt.StatusCode))
+	case metrics.WebhookRetry:
+		logger.Warn("Webhook delivery failed, retrying later",
+			slog.Int("attempt", attempt.Attempt),
+			slog.Time("next_attempt_at", *delivery.NextAttemptAt),
+			slog.String("error", attempt.Error))
+	default:
+		logger.Error("Webhook delivery failed, giving up",
+			slog.Int("attempt", attempt.Attempt),
+			slog.String("error", attempt.Error))
+	}
+}
+
+// post sends the delivery's payload, signed with the subscription's secret
+func (s *WebhookService) post(ctx context.Context, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery) *models.WebhookAttempt {
+	attempt := &models.WebhookAttempt{
+		DeliveryID:  delivery.ID,
+		Attempt:     delivery.Attempts + 1,
+		AttemptedAt: time.Now().UTC(),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		attempt.Error = fmt.Sprintf("failed to build request: %v", err)
+		return attempt
+	}
+
+	timestamp := attempt.AttemptedAt.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "banking-ledger-service-webhooks")
+	req.Header.Set(models.WebhookIDHeader, delivery.ID)
+	req.Header.Set(models.WebhookEventHeader, delivery.EventType)
+	req.Header.Set(models.WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(models.WebhookSignatureHeader, models.SignWebhook(subscription.Secret, timestamp, delivery.Payload))
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	attempt.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+		var urlErr interface{ Timeout() bool }
+		if errors.As(err, &urlErr) && urlErr.Timeout() {
+			attempt.Error = fmt.Sprintf("subscriber did not answer within %v", s.client.Timeout)
+		}
+		return attempt
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponseBody))
+	// Postgres text cannot hold NUL bytes or invalid UTF-8
+	attempt.ResponseBody = strings.ReplaceAll(strings.ToValidUTF8(string(body), "\uFFFD"), "\x00", "")
+	attempt.StatusCode = resp.StatusCode
+	if !attempt.Succeeded() {
+		attempt.Error = "subscriber answered " + resp.Status
+	}
+	return attempt
+}
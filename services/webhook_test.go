@@ -0,0 +1,337 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/queue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var testWebhookRetryPolicy = queue.RetryPolicy{MaxRetries: 2, BaseDelay: 30 * time.Second, MaxDelay: time.Hour}
+
+func newTestWebhookService(ctrl *gomock.Controller) (*WebhookService, *MockAccountStorage, *MockWebhookStorage) {
+	mockAccountStorage := NewMockAccountStorage(ctrl)
+	mockWebhookStorage := NewMockWebhookStorage(ctrl)
+	service := NewWebhookService(mockAccountStorage, mockWebhookStorage, testWebhookRetryPolicy, 5*time.Second)
+	return service, mockAccountStorage, mockWebhookStorage
+}
+
+func TestWebhookService_CreateWebhook_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockAccountStorage, mockWebhookStorage := newTestWebhookService(ctrl)
+	ctx := context.Background()
+
+	mockAccountStorage.EXPECT().
+		GetAccountByID(ctx, "acc_12345").
+		Return(&models.Account{ID: "acc_12345", Currency: "USD"}, nil)
+
+	mockWebhookStorage.EXPECT().
+		CreateWebhookSubscription(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, subscription *models.WebhookSubscription) error {
+			assert.Equal(t, "acc_12345", subscription.AccountID)
+			assert.Equal(t, []string{models.EventTransactionCompleted, models.EventTransactionFailed}, subscription.Events)
+			assert.Contains(t, subscription.Secret, "whsec_")
+			return nil
+		})
+
+	subscription, err := service.CreateWebhook(ctx, "acc_12345", &models.CreateWebhookRequest{
+		URL:    " https://example.com/hooks ",
+		Events: []string{models.EventTransactionCompleted, " transaction.failed", models.EventTransactionCompleted},
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, subscription.ID, "whk_")
+	assert.Equal(t, "https://example.com/hooks", subscription.URL)
+	assert.NotEmpty(t, subscription.Secret)
+}
+
+func TestWebhookService_CreateWebhook_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, _ := newTestWebhookService(ctrl)
+
+	// Validation fails before any storage calls
+	subscription, err := service.CreateWebhook(context.Background(), "", &models.CreateWebhookRequest{
+		URL:    "https://example.com/hooks",
+		Events: []string{models.EventBalanceLow},
+	})
+
+	assert.Nil(t, subscription)
+	assert.ErrorIs(t, err, models.ErrInvalidWebhook)
+}
+
+func TestWebhookService_CreateWebhook_ThresholdPrecision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockAccountStorage, _ := newTestWebhookService(ctrl)
+	ctx := context.Background()
+
+	mockAccountStorage.EXPECT().
+		GetAccountByID(ctx, "acc_12345").
+		Return(&models.Account{ID: "acc_12345", Currency: "JPY"}, nil)
+
+	threshold := models.MustParseMoney("100.50")
+	_, err := service.CreateWebhook(ctx, "acc_12345", &models.CreateWebhookRequest{
+		URL:                 "https://example.com/hooks",
+		Events:              []string{models.EventBalanceLow},
+		LowBalanceThreshold: &threshold,
+	})
+
+	assert.ErrorIs(t, err, models.ErrInvalidPrecision)
+}
+
+func TestWebhookService_TransactionCompleted_BalanceLow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, mockWebhookStorage := newTestWebhookService(ctrl)
+
+	low := models.MustParseMoney("100.00")
+	high := models.MustParseMoney("1000.00")
+	transaction := &models.Transaction{
+		TransactionID:   "txn_12345",
+		AccountID:       "acc_12345",
+		Type:            "withdraw",
+		Amount:          models.MustParseMoney("450.00"),
+		Currency:        "USD",
+		PreviousBalance: models.MustParseMoney("500.00"),
+		NewBalance:      models.MustParseMoney("50.00"),
+		Status:          "completed",
+	}
+
+	mockWebhookStorage.EXPECT().
+		GetSubscribedWebhooks(gomock.Any(), "acc_12345", models.EventTransactionCompleted).
+		Return([]models.WebhookSubscription{{ID: "whk_global", Events: []string{models.EventTransactionCompleted}}}, nil)
+
+	mockWebhookStorage.EXPECT().
+		GetSubscribedWebhooks(gomock.Any(), "acc_12345", models.EventBalanceLow).
+		Return([]models.WebhookSubscription{
+			{ID: "whk_low", AccountID: "acc_12345", Events: []string{models.EventBalanceLow}, LowBalanceThreshold: &low},
+			// Already below this threshold before the withdrawal
+			{ID: "whk_high", AccountID: "acc_12345", Events: []string{models.EventBalanceLow}, LowBalanceThreshold: &high},
+		}, nil)
+
+	var queued []*models.WebhookDelivery
+	mockWebhookStorage.EXPECT().
+		CreateWebhookDeliveries(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+			queued = append(queued, deliveries...)
+			return nil
+		}).
+		Times(2)
+
+	service.TransactionCompleted(context.Background(), transaction)
+
+	require.Len(t, queued, 2)
+	assert.Equal(t, "whk_global", queued[0].SubscriptionID)
+	assert.Equal(t, models.EventTransactionCompleted, queued[0].EventType)
+
+	assert.Equal(t, "whk_low", queued[1].SubscriptionID)
+	assert.Equal(t, models.EventBalanceLow, queued[1].EventType)
+
+	var event models.WebhookEvent
+	require.NoError(t, json.Unmarshal(queued[1].Payload, &event))
+	var data models.BalanceLowData
+	require.NoError(t, json.Unmarshal(event.Data, &data))
+	assert.Equal(t, low, data.Threshold)
+	assert.Equal(t, "txn_12345", data.TransactionID)
+	assert.Equal(t, models.MustParseMoney("50.00"), data.Balance)
+}
+
+func TestWebhookService_AccountCreated_NoSubscribers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, mockWebhookStorage := newTestWebhookService(ctrl)
+
+	mockWebhookStorage.EXPECT().
+		GetSubscribedWebhooks(gomock.Any(), "acc_12345", models.EventAccountCreated).
+		Return(nil, nil)
+
+	// No deliveries are queued
+	service.AccountCreated(context.Background(), &models.Account{ID: "acc_12345"})
+}
+
+// newTestDelivery returns a claimed delivery of an account.created event
+func newTestDelivery(t *testing.T, subscriptionID string, attempts int) models.WebhookDelivery {
+	event, err := models.NewWebhookEvent(models.EventAccountCreated, "acc_12345", map[string]string{"id": "acc_12345"})
+	require.NoError(t, err)
+	delivery, err := models.NewWebhookDelivery(subscriptionID, event)
+	require.NoError(t, err)
+	delivery.Attempts = attempts
+	return *delivery
+}
+
+func TestWebhookService_DeliverDue_Delivered(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, mockWebhookStorage := newTestWebhookService(ctrl)
+	ctx := context.Background()
+	delivery := newTestDelivery(t, "whk_12345", 0)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(models.WebhookTimestampHeader), 10, 64)
+
+		assert.Equal(t, delivery.ID, r.Header.Get(models.WebhookIDHeader))
+		assert.Equal(t, models.EventAccountCreated, r.Header.Get(models.WebhookEventHeader))
+		assert.True(t, models.VerifyWebhookSignature("whsec_test", timestamp, body, r.Header.Get(models.WebhookSignatureHeader)))
+		assert.JSONEq(t, string(delivery.Payload), string(body))
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	mockWebhookStorage.EXPECT().
+		ClaimDueWebhookDeliveries(ctx, gomock.Any(), gomock.Any(), webhookBatchSize).
+		Return([]models.WebhookDelivery{delivery}, nil)
+	mockWebhookStorage.EXPECT().
+		GetWebhookSubscription(ctx, "whk_12345").
+		Return(&models.WebhookSubscription{ID: "whk_12345", URL: server.URL, Secret: "whsec_test"}, nil)
+	mockWebhookStorage.EXPECT().
+		RecordWebhookAttempt(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, delivery *models.WebhookDelivery, attempt *models.WebhookAttempt) error {
+			assert.Equal(t, models.WebhookDeliveryDelivered, delivery.Status)
+			assert.Equal(t, 1, delivery.Attempts)
+			assert.NotNil(t, delivery.DeliveredAt)
+			assert.Nil(t, delivery.NextAttemptAt)
+			assert.Equal(t, 1, attempt.Attempt)
+			assert.Equal(t, http.StatusNoContent, attempt.StatusCode)
+			assert.Empty(t, attempt.Error)
+			return nil
+		})
+
+	attempted, err := service.DeliverDue(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, attempted)
+}
+
+func TestWebhookService_DeliverDue_RetryAndGiveUp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("down for maintenance"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		attempts       int
+		expectedStatus string
+		expectRetry    bool
+	}{
+		{name: "first failure is retried", attempts: 0, expectedStatus: models.WebhookDeliveryPending, expectRetry: true},
+		{name: "last retry gives up", attempts: testWebhookRetryPolicy.MaxRetries, expectedStatus: models.WebhookDeliveryFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, _, mockWebhookStorage := newTestWebhookService(ctrl)
+			ctx := context.Background()
+
+			mockWebhookStorage.EXPECT().
+				ClaimDueWebhookDeliveries(ctx, gomock.Any(), gomock.Any(), webhookBatchSize).
+				Return([]models.WebhookDelivery{newTestDelivery(t, "whk_12345", tt.attempts)}, nil)
+			mockWebhookStorage.EXPECT().
+				GetWebhookSubscription(ctx, "whk_12345").
+				Return(&models.WebhookSubscription{ID: "whk_12345", URL: server.URL, Secret: "whsec_test"}, nil)
+			mockWebhookStorage.EXPECT().
+				RecordWebhookAttempt(ctx, gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, delivery *models.WebhookDelivery, attempt *models.WebhookAttempt) error {
+					assert.Equal(t, tt.expectedStatus, delivery.Status)
+					assert.Equal(t, tt.attempts+1, delivery.Attempts)
+					assert.Equal(t, http.StatusServiceUnavailable, delivery.LastStatusCode)
+					assert.Equal(t, "subscriber answered 503 Service Unavailable", attempt.Error)
+					assert.Equal(t, "down for maintenance", attempt.ResponseBody)
+					if tt.expectRetry {
+						require.NotNil(t, delivery.NextAttemptAt)
+						assert.WithinDuration(t, time.Now().Add(testWebhookRetryPolicy.BaseDelay), *delivery.NextAttemptAt, 5*time.Second)
+					} else {
+						assert.Nil(t, delivery.NextAttemptAt)
+					}
+					return nil
+				})
+
+			_, err := service.DeliverDue(ctx)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestWebhookService_Redeliver(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, mockWebhookStorage := newTestWebhookService(ctrl)
+	ctx := context.Background()
+
+	failed := newTestDelivery(t, "whk_12345", 3)
+	failed.Status = models.WebhookDeliveryFailed
+	failed.NextAttemptAt = nil
+
+	mockWebhookStorage.EXPECT().GetWebhookDelivery(ctx, failed.ID).Return(&failed, nil)
+	mockWebhookStorage.EXPECT().
+		CreateWebhookDeliveries(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+			require.Len(t, deliveries, 1)
+			assert.Equal(t, models.WebhookDeliveryPending, deliveries[0].Status)
+			assert.Equal(t, 0, deliveries[0].Attempts)
+			return nil
+		})
+
+	redelivery, err := service.Redeliver(ctx, "whk_12345", failed.ID)
+
+	require.NoError(t, err)
+	assert.NotEqual(t, failed.ID, redelivery.ID)
+	assert.Equal(t, failed.ID, redelivery.RedeliveryOf)
+	assert.Equal(t, failed.EventID, redelivery.EventID)
+	assert.Equal(t, failed.Payload, redelivery.Payload)
+}
+
+func TestWebhookService_Redeliver_StillPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, mockWebhookStorage := newTestWebhookService(ctrl)
+	ctx := context.Background()
+
+	pending := newTestDelivery(t, "whk_12345", 1)
+	mockWebhookStorage.EXPECT().GetWebhookDelivery(ctx, pending.ID).Return(&pending, nil)
+
+	_, err := service.Redeliver(ctx, "whk_12345", pending.ID)
+
+	assert.ErrorIs(t, err, models.ErrWebhookDeliveryPending)
+}
+
+func TestWebhookService_GetDelivery_OtherSubscription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, mockWebhookStorage := newTestWebhookService(ctrl)
+	ctx := context.Background()
+
+	delivery := newTestDelivery(t, "whk_other", 1)
+	mockWebhookStorage.EXPECT().GetWebhookDelivery(ctx, delivery.ID).Return(&delivery, nil)
+
+	_, err := service.GetDelivery(ctx, "whk_12345", delivery.ID)
+
+	assert.ErrorIs(t, err, models.ErrWebhookDeliveryNotFound)
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+)
+
+// createAccountStatusChangesTable creates the history of account state
+// changes. A row is written in the same database transaction as each change,
+// so every freeze, unfreeze and close has its reason on record.
+func createAccountStatusChangesTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS account_status_changes (
+		id BIGSERIAL PRIMARY KEY,
+		account_id VARCHAR(255) NOT NULL REFERENCES accounts(id),
+		from_status VARCHAR(16) NOT NULL,
+		to_status VARCHAR(16) NOT NULL,
+		reason TEXT NOT NULL,
+		changed_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+	);
+	CREATE INDEX IF NOT EXISTS idx_account_status_changes_account ON account_status_changes(account_id, id);
+	`
+	_, err := db.Exec(query)
+	return err
+}
+
+// UpdateAccountStatus moves an account to a new state and records why. The
+// account row is locked first, so the change is serialized with postings; an
+// account can only be closed once its balance is zero.
+func (s *PostgresAccountStorage) UpdateAccountStatus(ctx context.Context, accountID, status, reason string) (*models.AccountStatusChange, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Will be ignored if tx.Commit() succeeds
+
+	accounts, err := lockAccounts(ctx, tx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	account := accounts[accountID]
+
+	if err := models.ValidateStatusChange(account.Status, status); err != nil {
+		return nil, err
+	}
+	if status == models.AccountClosed && !account.Balance.IsZero() {
+		return nil, models.Errorf(models.ErrInvalidStatusChange,
+			"account %s has a balance of %s; it must be zero to close", accountID, account.Balance)
+	}
+
+	change := &models.AccountStatusChange{
+		AccountID:  accountID,
+		FromStatus: account.Status,
+		ToStatus:   status,
+		Reason:     reason,
+		ChangedAt:  time.Now(),
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE accounts SET status = $1, updated_at = $2 WHERE id = $3",
+		status, change.ChangedAt, accountID); err != nil {
+		return nil, fmt.Errorf("failed to update account status: %w", err)
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO account_status_changes (account_id, from_status, to_status, reason, changed_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, accountID, change.FromStatus, change.ToStatus, change.Reason, change.ChangedAt).Scan(&change.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record account status change: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return change, nil
+}
+
+// GetAccountStatusHistory returns an account's state changes, oldest first
+func (s *PostgresAccountStorage) GetAccountStatusHistory(ctx context.Context, accountID string) ([]models.AccountStatusChange, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, account_id, from_status, to_status, reason, changed_at
+		FROM account_status_changes WHERE account_id = $1 ORDER BY id
+	`, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account status history: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []models.AccountStatusChange{}
+	for rows.Next() {
+		var change models.AccountStatusChange
+		if err := rows.Scan(&change.ID, &change.AccountID, &change.FromStatus, &change.ToStatus, &change.Reason, &change.ChangedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan account status change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
@@ -0,0 +1,170 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/lib/pq"
+)
+
+// createAuditLogTable creates the audit trail. Triggers reject every UPDATE,
+// DELETE and TRUNCATE, so once written a record can only be read; account_ids
+// has no foreign key because rejected calls may name accounts that do not
+// exist.
+func createAuditLogTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS audit_log (
+		id VARCHAR(255) PRIMARY KEY,
+		request_id VARCHAR(255) NOT NULL DEFAULT '',
+		actor VARCHAR(255) NOT NULL DEFAULT '',
+		auth_method VARCHAR(16) NOT NULL DEFAULT '',
+		api_key VARCHAR(255) NOT NULL DEFAULT '',
+		roles TEXT[] NOT NULL DEFAULT '{}',
+		action TEXT NOT NULL,
+		account_ids TEXT[] NOT NULL DEFAULT '{}',
+		targets JSONB NOT NULL DEFAULT '{}',
+		balances JSONB NOT NULL DEFAULT '[]',
+		outcome VARCHAR(16) NOT NULL,
+		status_code INTEGER NOT NULL,
+		error TEXT NOT NULL DEFAULT '',
+		client_ip VARCHAR(64) NOT NULL DEFAULT '',
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+	);
+	CREATE INDEX IF NOT EXISTS idx_audit_log_created ON audit_log(created_at, id);
+	CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(actor, created_at);
+	CREATE INDEX IF NOT EXISTS idx_audit_log_account_ids ON audit_log USING GIN (account_ids);
+
+	CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+	BEGIN
+		RAISE EXCEPTION 'audit_log is append-only';
+	END;
+	$$ LANGUAGE plpgsql;
+
+	DO $$
+	BEGIN
+		IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'audit_log_no_change') THEN
+			CREATE TRIGGER audit_log_no_change BEFORE UPDATE OR DELETE ON audit_log
+				FOR EACH ROW EXECUTE PROCEDURE audit_log_append_only();
+		END IF;
+		IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'audit_log_no_truncate') THEN
+			CREATE TRIGGER audit_log_no_truncate BEFORE TRUNCATE ON audit_log
+				FOR EACH STATEMENT EXECUTE PROCEDURE audit_log_append_only();
+		END IF;
+	END $$;
+	`
+	_, err := db.Exec(query)
+	return err
+}
+
+const auditColumns = `id, request_id, actor, auth_method, api_key, roles, action, account_ids, targets, balances,
+	outcome, status_code, error, client_ip, created_at`
+
+// scanAuditRecord reads a row selected with auditColumns
+func scanAuditRecord(row interface{ Scan(...interface{}) error }) (*models.AuditRecord, error) {
+	record := &models.AuditRecord{}
+	var roles, accountIDs pq.StringArray
+	var targets, balances []byte
+	err := row.Scan(&record.ID, &record.RequestID, &record.Actor, &record.AuthMethod, &record.APIKey, &roles,
+		&record.Action, &accountIDs, &targets, &balances, &record.Outcome, &record.StatusCode, &record.Error,
+		&record.ClientIP, &record.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	record.Roles = roles
+	record.AccountIDs = accountIDs
+	if err := json.Unmarshal(targets, &record.Targets); err != nil {
+		return nil, fmt.Errorf("failed to decode targets: %w", err)
+	}
+	if err := json.Unmarshal(balances, &record.Balances); err != nil {
+		return nil, fmt.Errorf("failed to decode balances: %w", err)
+	}
+	return record, nil
+}
+
+// InsertAuditRecord appends a record to the audit trail
+func (s *PostgresAccountStorage) InsertAuditRecord(ctx context.Context, record *models.AuditRecord) error {
+	targets, err := json.Marshal(record.Targets)
+	if err != nil {
+		return fmt.Errorf("failed to encode targets: %w", err)
+	}
+	if record.Targets == nil {
+		targets = []byte("{}")
+	}
+	balances, err := json.Marshal(record.Balances)
+	if err != nil {
+		return fmt.Errorf("failed to encode balances: %w", err)
+	}
+	if record.Balances == nil {
+		balances = []byte("[]")
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO audit_log (`+auditColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`, record.ID, record.RequestID, record.Actor, record.AuthMethod, record.APIKey, pq.Array(record.Roles),
+		record.Action, pq.Array(record.AccountIDs), targets, balances, record.Outcome, record.StatusCode,
+		record.Error, record.ClientIP, record.Timestamp)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit record: %w", err)
+	}
+	return nil
+}
+
+// GetAuditRecordsPage returns up to limit records matching filter, newest
+// first, starting after the cursor (or from the newest when after is nil)
+func (s *PostgresAccountStorage) GetAuditRecordsPage(ctx context.Context, filter *models.AuditFilter, after *models.AuditCursor, limit int) ([]models.AuditRecord, error) {
+	var conditions []string
+	var args []interface{}
+	where := func(condition string, values ...interface{}) {
+		for _, value := range values {
+			args = append(args, value)
+			condition = strings.Replace(condition, "?", fmt.Sprintf("$%d", len(args)), 1)
+		}
+		conditions = append(conditions, condition)
+	}
+
+	if filter.Actor != "" {
+		where("actor = ?", filter.Actor)
+	}
+	if filter.AccountID != "" {
+		where("account_ids @> ARRAY[?]::TEXT[]", filter.AccountID)
+	}
+	if !filter.From.IsZero() {
+		where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		where("created_at < ?", filter.To)
+	}
+	if after != nil {
+		where("(created_at, id) < (?, ?)", after.Timestamp, after.ID)
+	}
+
+	query := "SELECT " + auditColumns + " FROM audit_log"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, limit)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d", len(args))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit records: %w", err)
+	}
+	defer rows.Close()
+
+	records := []models.AuditRecord{}
+	for rows.Next() {
+		record, err := scanAuditRecord(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit record: %w", err)
+		}
+		records = append(records, *record)
+	}
+
+	return records, rows.Err()
+}
@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoDeadLetterStorage struct {
+	client     *mongo.Client
+	collection *mongo.Collection
+}
+
+func NewMongoDeadLetterStorage(uri, database, collection string) (*MongoDeadLetterStorage, error) {
+	clientOptions := options.Client().ApplyURI(uri).SetMonitor(commandMonitor(collection))
+
+	client, err := mongo.Connect(context.Background(), clientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx, nil); err != nil {
+		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
+	}
+
+	coll := client.Database(database).Collection(collection)
+
+	indexes := []mongo.IndexModel{
+		{
+			// A dead letter redelivered before it was acked is stored once
+			Keys:    bson.D{{Key: "dedupkey", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "receivedat", Value: -1}},
+		},
+	}
+
+	_, err = coll.Indexes().CreateMany(context.Background(), indexes)
+	if err != nil {
+		log.Printf("Warning: Failed to create dead letter indexes: %v", err)
+	}
+
+	return &MongoDeadLetterStorage{
+		client:     client,
+		collection: coll,
+	}, nil
+}
+
+// SaveDeadLetter stores a dead letter; one that was already stored is ignored
+func (s *MongoDeadLetterStorage) SaveDeadLetter(ctx context.Context, deadLetter *models.DeadLetter) error {
+	_, err := s.collection.InsertOne(ctx, deadLetter)
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("failed to save dead letter: %w", err)
+	}
+
+	return nil
+}
+
+// GetDeadLetters returns dead letters, newest first, optionally filtered by status
+func (s *MongoDeadLetterStorage) GetDeadLetters(ctx context.Context, status string, page, limit int) ([]models.DeadLetter, int64, error) {
+	skip := (page - 1) * limit
+
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	total, err := s.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count dead letters: %w", err)
+	}
+
+	findOptions := options.Find()
+	findOptions.SetSkip(int64(skip))
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetSort(bson.D{{Key: "receivedat", Value: -1}})
+
+	cursor, err := s.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find dead letters: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var deadLetters []models.DeadLetter
+	if err := cursor.All(ctx, &deadLetters); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode dead letters: %w", err)
+	}
+
+	return deadLetters, total, nil
+}
+
+func (s *MongoDeadLetterStorage) GetDeadLetterByID(ctx context.Context, id string) (*models.DeadLetter, error) {
+	var deadLetter models.DeadLetter
+	err := s.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&deadLetter)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrDeadLetterNotFound
+		}
+		return nil, fmt.Errorf("failed to get dead letter: %w", err)
+	}
+
+	return &deadLetter, nil
+}
+
+// TransitionDeadLetter moves a dead letter from one status to another. It
+// reports false when the dead letter was no longer in the expected status.
+func (s *MongoDeadLetterStorage) TransitionDeadLetter(ctx context.Context, id, fromStatus, toStatus string, resolvedAt *time.Time) (bool, error) {
+	filter := bson.M{"_id": id, "status": fromStatus}
+
+	set := bson.M{"status": toStatus}
+	update := bson.M{"$set": set}
+	if resolvedAt != nil {
+		set["resolvedat"] = resolvedAt
+	} else {
+		update["$unset"] = bson.M{"resolvedat": ""}
+	}
+
+	result, err := s.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("failed to update dead letter: %w", err)
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+func (s *MongoDeadLetterStorage) Close() error {
+	return s.client.Disconnect(context.Background())
+}
@@ -0,0 +1,284 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+)
+
+// createHoldsTable creates the table of funds reserved on accounts. Holds
+// never touch the ledger: they only lower the available balance that debits
+// are checked against, until a capture posts the withdrawal.
+func createHoldsTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS holds (
+		id VARCHAR(255) PRIMARY KEY,
+		account_id VARCHAR(255) NOT NULL REFERENCES accounts(id),
+		amount DECIMAL(19,4) NOT NULL CHECK (amount > 0),
+		currency CHAR(3) NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		status VARCHAR(16) NOT NULL DEFAULT 'active',
+		captured_amount DECIMAL(19,4) NOT NULL DEFAULT 0,
+		transaction_id VARCHAR(255),
+		expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+		updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+	);
+	CREATE INDEX IF NOT EXISTS idx_holds_account ON holds(account_id, status, expires_at);
+	CREATE INDEX IF NOT EXISTS idx_holds_expiry ON holds(expires_at) WHERE status = 'active';
+	`
+	_, err := db.Exec(query)
+	return err
+}
+
+// heldAmount returns the funds reserved on an account by holds that are still
+// active and have not expired. Callers hold the account's row lock, which is
+// also taken to place a hold, so the sum cannot change underneath them.
+func heldAmount(ctx context.Context, tx *sql.Tx, accountID string) (models.Money, error) {
+	var held models.Money
+	err := tx.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(amount), 0) FROM holds
+		WHERE account_id = $1 AND status = 'active' AND expires_at > $2
+	`, accountID, time.Now()).Scan(&held)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get held amount: %w", err)
+	}
+	return held, nil
+}
+
+// CreateHold reserves hold.Amount on the account if its available balance
+// covers it
+func (s *PostgresAccountStorage) CreateHold(ctx context.Context, hold *models.Hold) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Will be ignored if tx.Commit() succeeds
+
+	accounts, err := lockAccounts(ctx, tx, hold.AccountID)
+	if err != nil {
+		return err
+	}
+	account := accounts[hold.AccountID]
+
+	if account.Currency != models.NormalizeCurrency(hold.Currency) {
+		return models.Errorf(models.ErrCurrencyMismatch, "currency mismatch: account %s is denominated in %s, not %s",
+			account.ID, account.Currency, models.NormalizeCurrency(hold.Currency))
+	}
+	if err := account.CheckDebit(); err != nil {
+		return err
+	}
+
+	held, err := heldAmount(ctx, tx, hold.AccountID)
+	if err != nil {
+		return err
+	}
+	account.HeldBalance = held
+	if available := account.Available(); available < hold.Amount {
+		return &models.InsufficientFundsError{AccountID: hold.AccountID, Balance: available, Requested: hold.Amount}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO holds (id, account_id, amount, currency, description, status, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, hold.ID, hold.AccountID, hold.Amount, account.Currency, hold.Description, hold.Status,
+		hold.ExpiresAt, hold.CreatedAt, hold.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to insert hold: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+const holdColumns = `id, account_id, amount, currency, description, status, captured_amount,
+	COALESCE(transaction_id, ''), expires_at, created_at, updated_at`
+
+// scanHold reads a row selected with holdColumns. A hold past its expiry is
+// reported as expired even before the sweep marks it.
+func scanHold(row interface{ Scan(...interface{}) error }) (*models.Hold, error) {
+	hold := &models.Hold{}
+	err := row.Scan(&hold.ID, &hold.AccountID, &hold.Amount, &hold.Currency, &hold.Description, &hold.Status,
+		&hold.CapturedAmount, &hold.TransactionID, &hold.ExpiresAt, &hold.CreatedAt, &hold.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if hold.IsExpired(time.Now()) {
+		hold.Status = models.HoldExpired
+	}
+	return hold, nil
+}
+
+// GetHold returns a hold by ID
+func (s *PostgresAccountStorage) GetHold(ctx context.Context, holdID string) (*models.Hold, error) {
+	hold, err := scanHold(s.db.QueryRowContext(ctx, "SELECT "+holdColumns+" FROM holds WHERE id = $1", holdID))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrHoldNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hold: %w", err)
+	}
+	return hold, nil
+}
+
+// GetAccountHolds returns an account's holds, newest first
+func (s *PostgresAccountStorage) GetAccountHolds(ctx context.Context, accountID string) ([]models.Hold, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+holdColumns+" FROM holds WHERE account_id = $1 ORDER BY created_at DESC, id", accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holds: %w", err)
+	}
+	defer rows.Close()
+
+	holds := []models.Hold{}
+	for rows.Next() {
+		hold, err := scanHold(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan hold: %w", err)
+		}
+		holds = append(holds, *hold)
+	}
+
+	return holds, rows.Err()
+}
+
+// lockHold locks an active, unexpired hold together with its account. The
+// account row is locked first, in the same order as every posting, so a
+// capture cannot deadlock with a transaction on the same account.
+func lockHold(ctx context.Context, tx *sql.Tx, holdID string) (*models.Hold, error) {
+	var accountID string
+	err := tx.QueryRowContext(ctx, "SELECT account_id FROM holds WHERE id = $1", holdID).Scan(&accountID)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrHoldNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hold: %w", err)
+	}
+
+	if _, err := lockAccounts(ctx, tx, accountID); err != nil {
+		return nil, err
+	}
+
+	hold, err := scanHold(tx.QueryRowContext(ctx, "SELECT "+holdColumns+" FROM holds WHERE id = $1 FOR UPDATE", holdID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock hold: %w", err)
+	}
+	if hold.Status != models.HoldActive {
+		return nil, models.Errorf(models.ErrHoldNotActive, "hold %s is %s", holdID, hold.Status)
+	}
+	return hold, nil
+}
+
+// CaptureHold settles a hold by posting entry, the withdrawal of the captured
+// amount, in the same database transaction that marks the hold captured.
+// Whatever was not captured stops being held.
+func (s *PostgresAccountStorage) CaptureHold(ctx context.Context, holdID string, entry *models.JournalEntry) (*models.Hold, []models.BalanceChange, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Will be ignored if tx.Commit() succeeds
+
+	hold, err := lockHold(ctx, tx, holdID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var amount models.Money
+	var transactionID string
+	for _, posting := range entry.Postings {
+		if posting.AccountID == hold.AccountID {
+			amount, transactionID = posting.Amount, posting.TransactionID
+		}
+	}
+	if amount > hold.Amount {
+		return nil, nil, models.Errorf(models.ErrInvalidAmount, "capture amount %s exceeds the hold amount %s", amount, hold.Amount)
+	}
+
+	// Mark the hold captured first, so the withdrawal is checked against the
+	// available balance without this hold in it
+	hold.Status = models.HoldCaptured
+	hold.CapturedAmount = amount
+	hold.TransactionID = transactionID
+	hold.UpdatedAt = time.Now()
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE holds SET status = $1, captured_amount = $2, transaction_id = $3, updated_at = $4 WHERE id = $5
+	`, hold.Status, hold.CapturedAmount, hold.TransactionID, hold.UpdatedAt, holdID); err != nil {
+		return nil, nil, fmt.Errorf("failed to capture hold: %w", err)
+	}
+
+	changes, err := postEntry(ctx, tx, entry, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return hold, changes, nil
+}
+
+// ReleaseHold gives the funds reserved by an active hold back to the account
+func (s *PostgresAccountStorage) ReleaseHold(ctx context.Context, holdID string) (*models.Hold, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Will be ignored if tx.Commit() succeeds
+
+	hold, err := lockHold(ctx, tx, holdID)
+	if err != nil {
+		return nil, err
+	}
+
+	hold.Status = models.HoldReleased
+	hold.UpdatedAt = time.Now()
+	if _, err := tx.ExecContext(ctx, "UPDATE holds SET status = $1, updated_at = $2 WHERE id = $3",
+		hold.Status, hold.UpdatedAt, holdID); err != nil {
+		return nil, fmt.Errorf("failed to release hold: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return hold, nil
+}
+
+// ReopenHold puts a hold captured by transactionID back to active. Used to
+// roll back a capture whose transaction log record could not be written,
+// after its withdrawal has been reversed.
+func (s *PostgresAccountStorage) ReopenHold(ctx context.Context, holdID, transactionID string) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE holds SET status = 'active', captured_amount = 0, transaction_id = NULL, updated_at = $1
+		WHERE id = $2 AND status = 'captured' AND transaction_id = $3
+	`, time.Now(), holdID, transactionID)
+	if err != nil {
+		return fmt.Errorf("failed to reopen hold: %w", err)
+	}
+	return nil
+}
+
+// ExpireHolds marks active holds whose expiry has passed as expired and
+// returns how many there were. They stopped reserving funds at their expiry;
+// this only brings the stored status up to date.
+func (s *PostgresAccountStorage) ExpireHolds(ctx context.Context, now time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE holds SET status = 'expired', updated_at = $1
+		WHERE status = 'active' AND expires_at <= $1
+	`, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire holds: %w", err)
+	}
+
+	expired, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return expired, nil
+}
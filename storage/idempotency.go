@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// idempotencyKeyTTL is how long a stored response can be replayed
+const idempotencyKeyTTL = 24 * time.Hour
+
+type MongoIdempotencyStorage struct {
+	client     *mongo.Client
+	collection *mongo.Collection
+}
+
+func NewMongoIdempotencyStorage(uri, database, collection string) (*MongoIdempotencyStorage, error) {
+	clientOptions := options.Client().ApplyURI(uri).SetMonitor(commandMonitor(collection))
+
+	client, err := mongo.Connect(context.Background(), clientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx, nil); err != nil {
+		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
+	}
+
+	coll := client.Database(database).Collection(collection)
+
+	// Keys are scoped per account; the unique index is what makes claiming a key atomic
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "accountid", Value: 1}, {Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "createdat", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(idempotencyKeyTTL.Seconds())),
+		},
+	}
+
+	_, err = coll.Indexes().CreateMany(context.Background(), indexes)
+	if err != nil {
+		log.Printf("Warning: Failed to create idempotency indexes: %v", err)
+	}
+
+	return &MongoIdempotencyStorage{
+		client:     client,
+		collection: coll,
+	}, nil
+}
+
+// ClaimIdempotencyKey inserts a new in-flight record. If the key is already
+// taken for the account, the stored record is returned instead.
+func (s *MongoIdempotencyStorage) ClaimIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+	_, err := s.collection.InsertOne(ctx, record)
+	if err == nil {
+		return nil, nil
+	}
+
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+
+	var existing models.IdempotencyRecord
+	filter := bson.M{"accountid": record.AccountID, "key": record.Key}
+	if err := s.collection.FindOne(ctx, filter).Decode(&existing); err != nil {
+		return nil, fmt.Errorf("failed to load idempotency record: %w", err)
+	}
+
+	return &existing, nil
+}
+
+// CompleteIdempotencyRecord stores the final response for a claimed key
+func (s *MongoIdempotencyStorage) CompleteIdempotencyRecord(ctx context.Context, accountID, key string, statusCode int, responseBody []byte, transactionID string) error {
+	filter := bson.M{"accountid": accountID, "key": key}
+	update := bson.M{
+		"$set": bson.M{
+			"statuscode":    statusCode,
+			"responsebody":  responseBody,
+			"transactionid": transactionID,
+			"updatedat":     time.Now(),
+		},
+	}
+
+	result, err := s.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency record: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("idempotency record not found")
+	}
+
+	return nil
+}
+
+// DeleteIdempotencyRecord releases a key so the request can be retried
+func (s *MongoIdempotencyStorage) DeleteIdempotencyRecord(ctx context.Context, accountID, key string) error {
+	filter := bson.M{"accountid": accountID, "key": key}
+
+	if _, err := s.collection.DeleteOne(ctx, filter); err != nil {
+		return fmt.Errorf("failed to delete idempotency record: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteStaleIdempotencyRecord releases an abandoned claim, but only the one
+// made at claimedAt; it reports false when the key was meanwhile completed,
+// released or claimed again
+func (s *MongoIdempotencyStorage) DeleteStaleIdempotencyRecord(ctx context.Context, accountID, key string, claimedAt time.Time) (bool, error) {
+	filter := bson.M{
+		"accountid":  accountID,
+		"key":        key,
+		"createdat":  claimedAt,
+		"statuscode": 0,
+	}
+
+	result, err := s.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete stale idempotency record: %w", err)
+	}
+
+	return result.DeletedCount > 0, nil
+}
+
+func (s *MongoIdempotencyStorage) Close() error {
+	return s.client.Disconnect(context.Background())
+}
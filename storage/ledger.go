@@ -0,0 +1,346 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+)
+
+// createLedgerTables creates the double-entry journal. Every balance change is
+// a journal entry whose postings balance; customer balances on the accounts
+// table are kept in step with their postings in the same database transaction.
+func createLedgerTables(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS system_accounts (
+		id VARCHAR(255) PRIMARY KEY,
+		kind VARCHAR(32) NOT NULL,
+		currency CHAR(3) NOT NULL,
+		normal_balance VARCHAR(6) NOT NULL CHECK (normal_balance IN ('debit', 'credit')),
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+	);
+	CREATE TABLE IF NOT EXISTS journal_entries (
+		id VARCHAR(255) PRIMARY KEY,
+		description TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+	);
+	CREATE TABLE IF NOT EXISTS postings (
+		id BIGSERIAL PRIMARY KEY,
+		entry_id VARCHAR(255) NOT NULL REFERENCES journal_entries(id),
+		account_id VARCHAR(255) NOT NULL,
+		transaction_id VARCHAR(255),
+		direction VARCHAR(6) NOT NULL CHECK (direction IN ('debit', 'credit')),
+		amount DECIMAL(19,4) NOT NULL CHECK (amount > 0),
+		currency CHAR(3) NOT NULL,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+	);
+	CREATE INDEX IF NOT EXISTS idx_postings_account ON postings(account_id, created_at);
+	CREATE INDEX IF NOT EXISTS idx_postings_transaction ON postings(transaction_id);
+	`
+	_, err := db.Exec(query)
+	return err
+}
+
+// backfillOpeningEntries gives accounts created before the journal existed an
+// opening entry for their current balance, so their postings add up to it.
+// Only rows are written; the stored balances are already correct.
+func backfillOpeningEntries(db *sql.DB) error {
+	rows, err := db.Query(`
+		SELECT id, balance, currency FROM accounts a
+		WHERE balance <> 0 AND NOT EXISTS (SELECT 1 FROM postings p WHERE p.account_id = a.id)
+	`)
+	if err != nil {
+		return err
+	}
+
+	var accounts []models.Account
+	for rows.Next() {
+		var account models.Account
+		if err := rows.Scan(&account.ID, &account.Balance, &account.Currency); err != nil {
+			rows.Close()
+			return err
+		}
+		accounts = append(accounts, account)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, account := range accounts {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if err := insertEntry(context.Background(), tx, openingEntry(&account, account.Balance)); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// openingEntry moves an opening balance from cash into a customer account
+func openingEntry(account *models.Account, amount models.Money) *models.JournalEntry {
+	customer := models.Posting{AccountID: account.ID, Direction: models.Credit, Amount: amount, Currency: account.Currency}
+	cash := models.Posting{AccountID: models.SystemAccountID(models.SystemCash, account.Currency), Direction: models.Debit, Amount: amount, Currency: account.Currency}
+	if amount.IsNegative() {
+		customer.Direction, cash.Direction = models.Debit, models.Credit
+		customer.Amount, cash.Amount = amount.Neg(), amount.Neg()
+	}
+	return models.NewJournalEntry("Opening balance", cash, customer)
+}
+
+// PostJournalEntry posts a balanced entry and applies it to the customer
+// balances it touches, all in one database transaction
+func (s *PostgresAccountStorage) PostJournalEntry(ctx context.Context, entry *models.JournalEntry) ([]models.BalanceChange, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Will be ignored if tx.Commit() succeeds
+
+	changes, err := postEntry(ctx, tx, entry, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return changes, nil
+}
+
+// postEntry validates entry, locks and updates the customer accounts it posts
+// to, and writes it. With checkFunds set, a debit larger than the customer's
+// available balance (the balance less active holds, plus its overdraft limit)
+// is rejected, as is one that debits a frozen account or posts to a closed
+// one or breaks the entry's velocity limits; compensating entries skip these
+// checks.
+func postEntry(ctx context.Context, tx *sql.Tx, entry *models.JournalEntry, checkFunds bool) ([]models.BalanceChange, error) {
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+
+	deltas := make(map[string]models.Money)
+	var accountIDs []string
+	for _, posting := range entry.Postings {
+		if models.IsSystemAccount(posting.AccountID) {
+			continue
+		}
+		if _, seen := deltas[posting.AccountID]; !seen {
+			accountIDs = append(accountIDs, posting.AccountID)
+		}
+		if posting.Direction == models.Credit {
+			deltas[posting.AccountID] = deltas[posting.AccountID].Add(posting.Amount)
+		} else {
+			deltas[posting.AccountID] = deltas[posting.AccountID].Sub(posting.Amount)
+		}
+	}
+
+	accounts, err := lockAccounts(ctx, tx, accountIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, posting := range entry.Postings {
+		account, ok := accounts[posting.AccountID]
+		if ok && account.Currency != models.NormalizeCurrency(posting.Currency) {
+			return nil, models.Errorf(models.ErrCurrencyMismatch, "currency mismatch: account %s is denominated in %s, not %s",
+				account.ID, account.Currency, models.NormalizeCurrency(posting.Currency))
+		}
+	}
+
+	sort.Strings(accountIDs)
+	changes := make([]models.BalanceChange, 0, len(accountIDs))
+	now := time.Now()
+	for _, accountID := range accountIDs {
+		account := accounts[accountID]
+		previous := account.Balance
+		updated := previous.Add(deltas[accountID])
+		if checkFunds {
+			// The state and overdraft limit are read under the row lock, so a
+			// freeze, close or limit change that commits first is always seen
+			check := account.CheckCredit
+			if deltas[accountID].IsNegative() {
+				check = account.CheckDebit
+			}
+			if err := check(); err != nil {
+				return nil, err
+			}
+			if deltas[accountID].IsNegative() {
+				held, err := heldAmount(ctx, tx, accountID)
+				if err != nil {
+					return nil, err
+				}
+				account.HeldBalance = held
+				if available := account.Available(); available.Add(deltas[accountID]).IsNegative() {
+					return nil, &models.InsufficientFundsError{AccountID: accountID, Balance: available, Requested: deltas[accountID].Neg()}
+				}
+
+				// Usage is read under the row lock too, so a concurrent
+				// withdrawal is either counted here or sees this one
+				if entry.Limits != nil {
+					usage, err := withdrawalUsage(ctx, tx, accountID, now)
+					if err != nil {
+						return nil, err
+					}
+					if err := entry.Limits.CheckUsage(accountID, deltas[accountID].Neg(), usage); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, "UPDATE accounts SET balance = $1, updated_at = $2 WHERE id = $3",
+			updated, now, accountID); err != nil {
+			return nil, fmt.Errorf("failed to update balance: %w", err)
+		}
+		changes = append(changes, models.BalanceChange{AccountID: accountID, PreviousBalance: previous, NewBalance: updated})
+	}
+
+	if err := insertEntry(ctx, tx, entry); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// insertEntry writes an entry and its postings, creating any system accounts
+// they post to
+func insertEntry(ctx context.Context, tx *sql.Tx, entry *models.JournalEntry) error {
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO journal_entries (id, description, created_at) VALUES ($1, $2, $3)",
+		entry.ID, entry.Description, entry.CreatedAt); err != nil {
+		return fmt.Errorf("failed to insert journal entry: %w", err)
+	}
+
+	for _, posting := range entry.Postings {
+		if kind, currency, ok := models.ParseSystemAccountID(posting.AccountID); ok {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO system_accounts (id, kind, currency, normal_balance)
+				VALUES ($1, $2, $3, $4)
+				ON CONFLICT (id) DO NOTHING
+			`, posting.AccountID, kind, currency, models.SystemAccountNormalBalance(kind)); err != nil {
+				return fmt.Errorf("failed to create system account: %w", err)
+			}
+		}
+
+		var transactionID sql.NullString
+		if posting.TransactionID != "" {
+			transactionID = sql.NullString{String: posting.TransactionID, Valid: true}
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO postings (entry_id, account_id, transaction_id, direction, amount, currency, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, entry.ID, posting.AccountID, transactionID, posting.Direction, posting.Amount,
+			models.NormalizeCurrency(posting.Currency), posting.CreatedAt); err != nil {
+			return fmt.Errorf("failed to insert posting: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// reversalEntry builds an entry that undoes the net effect of everything
+// posted under the given transaction IDs. It returns nil if that is nothing.
+func reversalEntry(ctx context.Context, tx *sql.Tx, description string, transactionIDs ...string) (*models.JournalEntry, error) {
+	var postings []models.Posting
+	for _, transactionID := range transactionIDs {
+		rows, err := tx.QueryContext(ctx, `
+			SELECT account_id, currency,
+				SUM(CASE WHEN direction = 'credit' THEN amount ELSE -amount END)
+			FROM postings
+			WHERE transaction_id = $1
+			GROUP BY account_id, currency
+			ORDER BY account_id
+		`, transactionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get postings: %w", err)
+		}
+
+		for rows.Next() {
+			posting := models.Posting{TransactionID: transactionID}
+			var net models.Money
+			if err := rows.Scan(&posting.AccountID, &posting.Currency, &net); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan postings: %w", err)
+			}
+			if net == 0 {
+				continue
+			}
+
+			// Post the net amount back to the other side
+			posting.Direction, posting.Amount = models.Debit, net
+			if net.IsNegative() {
+				posting.Direction, posting.Amount = models.Credit, net.Neg()
+			}
+			postings = append(postings, posting)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read postings: %w", err)
+		}
+	}
+
+	if len(postings) == 0 {
+		return nil, nil
+	}
+	return models.NewJournalEntry(description, postings...), nil
+}
+
+// GetLedgerBalance compares an account's stored balance with its postings
+func (s *PostgresAccountStorage) GetLedgerBalance(ctx context.Context, accountID string) (*models.LedgerBalance, error) {
+	balance := &models.LedgerBalance{AccountID: accountID}
+	err := s.db.QueryRowContext(ctx, `
+		SELECT a.balance, a.currency,
+			COALESCE((SELECT SUM(CASE WHEN p.direction = 'credit' THEN p.amount ELSE -p.amount END)
+				FROM postings p WHERE p.account_id = a.id), 0)
+		FROM accounts a WHERE a.id = $1
+	`, accountID).Scan(&balance.Balance, &balance.Currency, &balance.PostedBalance)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrAccountNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger balance: %w", err)
+	}
+
+	balance.InBalance = balance.Balance == balance.PostedBalance
+	return balance, nil
+}
+
+// GetSystemAccounts returns every system account with the balance derived
+// from its postings, measured in its normal direction
+func (s *PostgresAccountStorage) GetSystemAccounts(ctx context.Context) ([]models.SystemAccount, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT s.id, s.kind, s.currency, s.normal_balance,
+			COALESCE(SUM(CASE WHEN p.direction = s.normal_balance THEN p.amount ELSE -p.amount END), 0)
+		FROM system_accounts s
+		LEFT JOIN postings p ON p.account_id = s.id
+		GROUP BY s.id, s.kind, s.currency, s.normal_balance
+		ORDER BY s.currency, s.kind
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get system accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []models.SystemAccount
+	for rows.Next() {
+		var account models.SystemAccount
+		if err := rows.Scan(&account.ID, &account.Kind, &account.Currency, &account.NormalBalance, &account.Balance); err != nil {
+			return nil, fmt.Errorf("failed to scan system account: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+
+	return accounts, rows.Err()
+}
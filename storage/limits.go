@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+)
+
+// createLimitsTables creates the per-account limit overrides and the record of
+// transactions rejected for exceeding a limit. A NULL override keeps the
+// configured default.
+func createLimitsTables(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS account_limits (
+		account_id VARCHAR(255) PRIMARY KEY REFERENCES accounts(id),
+		max_deposit DECIMAL(19,4) CHECK (max_deposit >= 0),
+		max_withdrawal DECIMAL(19,4) CHECK (max_withdrawal >= 0),
+		max_daily_withdrawals BIGINT CHECK (max_daily_withdrawals >= 0),
+		max_daily_withdrawn DECIMAL(19,4) CHECK (max_daily_withdrawn >= 0),
+		max_monthly_withdrawn DECIMAL(19,4) CHECK (max_monthly_withdrawn >= 0),
+		updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+	);
+	CREATE TABLE IF NOT EXISTS limit_breaches (
+		id BIGSERIAL PRIMARY KEY,
+		account_id VARCHAR(255) NOT NULL REFERENCES accounts(id),
+		transaction_id VARCHAR(255),
+		type VARCHAR(16) NOT NULL,
+		amount DECIMAL(19,4) NOT NULL,
+		limit_name VARCHAR(32) NOT NULL,
+		max_value VARCHAR(32) NOT NULL,
+		attempted VARCHAR(32) NOT NULL,
+		recorded_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+	);
+	CREATE INDEX IF NOT EXISTS idx_limit_breaches_account ON limit_breaches(account_id, id);
+	`
+	_, err := db.Exec(query)
+	return err
+}
+
+// SetLimitOverrides replaces an account's limit overrides. Overrides that
+// leave every limit at its default remove the account's row.
+func (s *PostgresAccountStorage) SetLimitOverrides(ctx context.Context, accountID string, overrides *models.LimitOverrides) error {
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM accounts WHERE id = $1)", accountID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check account: %w", err)
+	}
+	if !exists {
+		return models.Errorf(models.ErrAccountNotFound, "account not found: %s", accountID)
+	}
+
+	if overrides.IsEmpty() {
+		if _, err := s.db.ExecContext(ctx, "DELETE FROM account_limits WHERE account_id = $1", accountID); err != nil {
+			return fmt.Errorf("failed to clear limit overrides: %w", err)
+		}
+		return nil
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO account_limits (account_id, max_deposit, max_withdrawal, max_daily_withdrawals, max_daily_withdrawn, max_monthly_withdrawn, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (account_id) DO UPDATE SET
+			max_deposit = EXCLUDED.max_deposit,
+			max_withdrawal = EXCLUDED.max_withdrawal,
+			max_daily_withdrawals = EXCLUDED.max_daily_withdrawals,
+			max_daily_withdrawn = EXCLUDED.max_daily_withdrawn,
+			max_monthly_withdrawn = EXCLUDED.max_monthly_withdrawn,
+			updated_at = EXCLUDED.updated_at
+	`, accountID, overrides.MaxDeposit, overrides.MaxWithdrawal, overrides.MaxDailyWithdrawals,
+		overrides.MaxDailyWithdrawn, overrides.MaxMonthlyWithdrawn, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to set limit overrides: %w", err)
+	}
+	return nil
+}
+
+// withdrawalUsage totals what an account has withdrawn within the limit
+// windows ending at now, from its postings. Every transaction that left a net
+// debit on the account counts once; one that was rolled back nets to zero.
+func withdrawalUsage(ctx context.Context, tx *sql.Tx, accountID string, now time.Time) (*models.LimitUsage, error) {
+	usage := &models.LimitUsage{}
+	err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FILTER (WHERE debited_at >= $2),
+			COALESCE(SUM(net) FILTER (WHERE debited_at >= $2), 0),
+			COALESCE(SUM(net), 0)
+		FROM (
+			SELECT MIN(created_at) AS debited_at,
+				SUM(CASE WHEN direction = 'debit' THEN amount ELSE -amount END) AS net
+			FROM postings
+			WHERE account_id = $1 AND transaction_id IS NOT NULL AND created_at >= $3
+			GROUP BY transaction_id
+		) withdrawals
+		WHERE net > 0
+	`, accountID, now.Add(-models.LimitDailyWindow), now.Add(-models.LimitMonthlyWindow)).
+		Scan(&usage.DailyWithdrawals, &usage.DailyWithdrawn, &usage.MonthlyWithdrawn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get withdrawal usage: %w", err)
+	}
+	return usage, nil
+}
+
+// RecordLimitBreach stores a rejected transaction with the limit it broke
+func (s *PostgresAccountStorage) RecordLimitBreach(ctx context.Context, breach *models.LimitBreach) error {
+	var transactionID sql.NullString
+	if breach.TransactionID != "" {
+		transactionID = sql.NullString{String: breach.TransactionID, Valid: true}
+	}
+
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO limit_breaches (account_id, transaction_id, type, amount, limit_name, max_value, attempted, recorded_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`, breach.AccountID, transactionID, breach.Type, breach.Amount, breach.Limit, breach.Max, breach.Attempted, breach.RecordedAt).Scan(&breach.ID)
+	if err != nil {
+		return fmt.Errorf("failed to record limit breach: %w", err)
+	}
+	return nil
+}
+
+// GetLimitBreaches returns an account's rejected transactions, newest first
+func (s *PostgresAccountStorage) GetLimitBreaches(ctx context.Context, accountID string, limit int) ([]models.LimitBreach, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, account_id, COALESCE(transaction_id, ''), type, amount, limit_name, max_value, attempted, recorded_at
+		FROM limit_breaches WHERE account_id = $1 ORDER BY id DESC LIMIT $2
+	`, accountID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get limit breaches: %w", err)
+	}
+	defer rows.Close()
+
+	breaches := []models.LimitBreach{}
+	for rows.Next() {
+		var breach models.LimitBreach
+		if err := rows.Scan(&breach.ID, &breach.AccountID, &breach.TransactionID, &breach.Type, &breach.Amount,
+			&breach.Limit, &breach.Max, &breach.Attempted, &breach.RecordedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan limit breach: %w", err)
+		}
+		breaches = append(breaches, breach)
+	}
+
+	return breaches, rows.Err()
+}
@@ -2,12 +2,16 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/appy29/banking-ledger-service/models"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -20,7 +24,7 @@ type MongoTransactionStorage struct {
 
 func NewMongoTransactionStorage(uri, database, collection string) (*MongoTransactionStorage, error) {
 	// Set client options
-	clientOptions := options.Client().ApplyURI(uri)
+	clientOptions := options.Client().ApplyURI(uri).SetMonitor(commandMonitor(collection))
 
 	// Connect to MongoDB
 	client, err := mongo.Connect(context.Background(), clientOptions)
@@ -50,6 +54,34 @@ func NewMongoTransactionStorage(uri, database, collection string) (*MongoTransac
 		{
 			Keys: bson.D{{Key: "timestamp", Value: -1}},
 		},
+		{
+			// Cursor pagination, balance-as-of lookups and statements walk one
+			// account's log in time order, with the record ID as tie-breaker
+			Keys: bson.D{{Key: "accountid", Value: 1}, {Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}},
+		},
+		{
+			// Each sealed record takes the next link in its account's hash
+			// chain; the unique index stops two writers taking the same one
+			Keys: bson.D{{Key: "accountid", Value: 1}, {Key: "chainseq", Value: 1}},
+			Options: options.Index().
+				SetName(chainIndexName).
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"chainseq": bson.M{"$exists": true}}),
+		},
+		{
+			Keys:    bson.D{{Key: "transferid", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "timestamp", Value: 1}},
+		},
+		{
+			// Only undispatched outbox entries are indexed, keeping the relay's poll cheap
+			Keys: bson.D{{Key: "outbox.nextattemptat", Value: 1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{
+				"outbox.dispatched": false,
+			}),
+		},
 	}
 
 	_, err = coll.Indexes().CreateMany(context.Background(), indexes)
@@ -64,36 +96,225 @@ func NewMongoTransactionStorage(uri, database, collection string) (*MongoTransac
 	}, nil
 }
 
+// chainIndexName names the unique index on each account's hash chain links
+const chainIndexName = "accountid_chainseq_unique"
+
+// maxSealAttempts bounds how often sealing retries when another writer takes
+// the link it was about to use
+const maxSealAttempts = 10
+
+// notSealed matches records that can still be changed
+var notSealed = bson.M{"$exists": false}
+
+// CreateTransaction inserts a record; a completed one is flagged if it
+// overdrew the account and is sealed into its account's hash chain as it is
+// written. A record whose ID is taken fails with ErrTransactionExists.
 func (s *MongoTransactionStorage) CreateTransaction(ctx context.Context, transaction *models.Transaction) error {
-	_, err := s.collection.InsertOne(ctx, transaction)
+	transaction.MarkOverdrawn()
+	insert := func(transaction *models.Transaction) error {
+		_, err := s.collection.InsertOne(ctx, transaction)
+		return err
+	}
+
+	var err error
+	if transaction.Status == "completed" {
+		err = s.seal(ctx, transaction, insert)
+	} else {
+		err = insert(transaction)
+	}
+	if mongo.IsDuplicateKeyError(err) && !strings.Contains(err.Error(), chainIndexName) {
+		return models.Errorf(models.ErrTransactionExists, "transaction %s already exists", transaction.ID)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to insert transaction: %w", err)
 	}
 	return nil
 }
 
-// UpdateTransaction updates a transaction record
+// CreateTransactions inserts several related records (e.g. both legs of a
+// transfer) in one call. Completed records are sealed, and so written one at
+// a time.
+func (s *MongoTransactionStorage) CreateTransactions(ctx context.Context, transactions []*models.Transaction) error {
+	for _, transaction := range transactions {
+		if transaction.Status == "completed" {
+			return s.createSealed(ctx, transactions)
+		}
+	}
+
+	documents := make([]interface{}, 0, len(transactions))
+	for _, transaction := range transactions {
+		documents = append(documents, transaction)
+	}
+
+	// Ordered, so a failure leaves only the records before it in place; those
+	// are removed again so the reaper never finds one leg of a pair on its own
+	_, err := s.collection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(true))
+	if err != nil {
+		s.removePartialInsert(ctx, transactions, err)
+		return fmt.Errorf("failed to insert transactions: %w", err)
+	}
+	return nil
+}
+
+// removePartialInsert deletes the records an ordered InsertMany wrote before
+// it failed with err. When the failure does not say where the insert stopped,
+// every record of the batch still unsealed is removed.
+func (s *MongoTransactionStorage) removePartialInsert(ctx context.Context, transactions []*models.Transaction, err error) {
+	inserted := transactions
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) && len(bulkErr.WriteErrors) > 0 {
+		inserted = transactions[:bulkErr.WriteErrors[0].Index]
+	}
+	if len(inserted) == 0 {
+		return
+	}
+
+	ids := make([]string, 0, len(inserted))
+	for _, transaction := range inserted {
+		ids = append(ids, transaction.ID)
+	}
+
+	// The caller may have given up; the orphans must go either way
+	ctx = context.WithoutCancel(ctx)
+	if _, delErr := s.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}, "hash": notSealed}); delErr != nil {
+		log.Printf("Warning: Failed to remove partially inserted transactions %v: %v", ids, delErr)
+	}
+}
+
+// createSealed writes records one by one so each can be sealed
+func (s *MongoTransactionStorage) createSealed(ctx context.Context, transactions []*models.Transaction) error {
+	for _, transaction := range transactions {
+		if err := s.CreateTransaction(ctx, transaction); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateTransaction updates a record's balances, status and error message.
+// Completing the record flags an overdraft and seals it; a sealed record
+// cannot be updated.
 func (s *MongoTransactionStorage) UpdateTransaction(ctx context.Context, transaction *models.Transaction) error {
-	filter := bson.M{"transactionid": transaction.TransactionID}
+	filter := bson.M{"transactionid": transaction.TransactionID, "hash": notSealed}
+
+	set := bson.M{
+		"previousbalance": transaction.PreviousBalance,
+		"newbalance":      transaction.NewBalance,
+		"currency":        transaction.Currency,
+		"status":          transaction.Status,
+		"errormessage":    transaction.ErrorMessage,
+	}
 
-	update := bson.M{
-		"$set": bson.M{
-			"previousbalance": transaction.PreviousBalance,
-			"newbalance":      transaction.NewBalance,
-			"status":          transaction.Status,
-			"errormessage":    transaction.ErrorMessage,
-		},
+	update := func(*models.Transaction) error {
+		result, err := s.collection.UpdateOne(ctx, filter, bson.M{"$set": set})
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return s.unchangeable(ctx, transaction.TransactionID, "transaction not found for update")
+		}
+		return nil
 	}
 
-	result, err := s.collection.UpdateOne(ctx, filter, update)
+	if transaction.Status != "completed" {
+		if err := update(transaction); err != nil {
+			return fmt.Errorf("failed to update transaction: %w", err)
+		}
+		return nil
+	}
+
+	// The hash covers the record as it will be stored, so start from the
+	// stored document and apply the update to it
+	var stored models.Transaction
+	err := s.collection.FindOne(ctx, filter).Decode(&stored)
+	if err == mongo.ErrNoDocuments {
+		return s.unchangeable(ctx, transaction.TransactionID, "transaction not found for update")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %w", err)
+	}
+	stored.PreviousBalance = transaction.PreviousBalance
+	stored.NewBalance = transaction.NewBalance
+	stored.Currency = transaction.Currency
+	stored.Status = transaction.Status
+	stored.ErrorMessage = transaction.ErrorMessage
+	stored.MarkOverdrawn()
+	set["overdrawn"] = stored.Overdrawn
+
+	err = s.seal(ctx, &stored, func(sealed *models.Transaction) error {
+		set["chainseq"] = sealed.ChainSeq
+		set["prevhash"] = sealed.PrevHash
+		set["hash"] = sealed.Hash
+		return update(sealed)
+	})
 	if err != nil {
 		return fmt.Errorf("failed to update transaction: %w", err)
 	}
 
-	if result.MatchedCount == 0 {
-		return fmt.Errorf("transaction not found for update")
+	transaction.ChainSeq, transaction.PrevHash, transaction.Hash = stored.ChainSeq, stored.PrevHash, stored.Hash
+	transaction.Overdrawn = stored.Overdrawn
+	return nil
+}
+
+// seal links transaction after the head of its account's chain and writes it
+// with write. If another writer takes the same link first, the unique chain
+// index rejects the write and sealing starts again from the new head.
+func (s *MongoTransactionStorage) seal(ctx context.Context, transaction *models.Transaction, write func(*models.Transaction) error) error {
+	for attempt := 0; attempt < maxSealAttempts; attempt++ {
+		head, err := s.chainHead(ctx, transaction.AccountID)
+		if err != nil {
+			return err
+		}
+
+		transaction.Seal(head)
+		err = write(transaction)
+		if mongo.IsDuplicateKeyError(err) && strings.Contains(err.Error(), chainIndexName) {
+			continue
+		}
+		return err
+	}
+
+	return fmt.Errorf("failed to seal transaction %s: chain head kept moving", transaction.TransactionID)
+}
+
+// chainHead returns the last sealed record of an account, or nil if the
+// account's chain is empty
+func (s *MongoTransactionStorage) chainHead(ctx context.Context, accountID string) (*models.Transaction, error) {
+	filter := bson.M{"accountid": accountID, "chainseq": bson.M{"$exists": true}}
+	findOptions := options.FindOne().SetSort(bson.D{{Key: "chainseq", Value: -1}})
+
+	var head models.Transaction
+	err := s.collection.FindOne(ctx, filter, findOptions).Decode(&head)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain head: %w", err)
 	}
 
+	return &head, nil
+}
+
+// unchangeable explains why an update matched nothing: the record is either
+// sealed or missing
+func (s *MongoTransactionStorage) unchangeable(ctx context.Context, transactionID, notFound string) error {
+	count, err := s.collection.CountDocuments(ctx, bson.M{"transactionid": transactionID})
+	if err != nil {
+		return fmt.Errorf("failed to find transaction: %w", err)
+	}
+	if count > 0 {
+		return models.Errorf(models.ErrTransactionSealed, "transaction %s is sealed and cannot be changed", transactionID)
+	}
+	return models.Errorf(models.ErrTransactionNotFound, notFound)
+}
+
+// checkStatusUpdate rejects settled statuses in the status updaters: records
+// are completed by UpdateTransaction, which seals them, and refunded or
+// reversed by RecordRefund
+func checkStatusUpdate(status string) error {
+	if status == "completed" || status == "partially_refunded" || status == "reversed" {
+		return models.Errorf(models.ErrInvalidRequest, "status %q cannot be set directly", status)
+	}
 	return nil
 }
 
@@ -130,6 +351,81 @@ func (s *MongoTransactionStorage) GetTransactionsByAccountID(ctx context.Context
 	return transactions, total, nil
 }
 
+// GetTransactionsPage returns up to limit of the account's transactions that
+// match filter, newest first, starting after the cursor (or from the newest
+// when after is nil). It seeks on (timestamp, _id) instead of skipping and
+// does not count the matches.
+func (s *MongoTransactionStorage) GetTransactionsPage(ctx context.Context, accountID string, filter *models.TransactionFilter, after *models.TransactionCursor, limit int) ([]models.Transaction, error) {
+	query := transactionFilterQuery(accountID, filter)
+	if after != nil {
+		query["$or"] = bson.A{
+			bson.M{"timestamp": bson.M{"$lt": after.Timestamp}},
+			bson.M{"timestamp": after.Timestamp, "_id": bson.M{"$lt": after.ID}},
+		}
+	}
+
+	findOptions := options.Find()
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}})
+
+	cursor, err := s.collection.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find transactions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var transactions []models.Transaction
+	if err := cursor.All(ctx, &transactions); err != nil {
+		return nil, fmt.Errorf("failed to decode transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// transactionFilterQuery builds the query for one account's transactions
+// matching filter
+func transactionFilterQuery(accountID string, filter *models.TransactionFilter) bson.M {
+	query := bson.M{"accountid": accountID}
+	if filter == nil {
+		return query
+	}
+
+	if len(filter.Types) > 0 {
+		query["type"] = bson.M{"$in": filter.Types}
+	}
+	if len(filter.Statuses) > 0 {
+		query["status"] = bson.M{"$in": filter.Statuses}
+	}
+
+	amount := bson.M{}
+	if filter.MinAmount != nil {
+		amount["$gte"] = *filter.MinAmount
+	}
+	if filter.MaxAmount != nil {
+		amount["$lte"] = *filter.MaxAmount
+	}
+	if len(amount) > 0 {
+		query["amount"] = amount
+	}
+
+	timestamp := bson.M{}
+	if !filter.From.IsZero() {
+		timestamp["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		timestamp["$lt"] = filter.To
+	}
+	if len(timestamp) > 0 {
+		query["timestamp"] = timestamp
+	}
+
+	if filter.Description != "" {
+		query["description"] = primitive.Regex{Pattern: regexp.QuoteMeta(filter.Description), Options: "i"}
+	}
+
+	return query
+}
+
 func (s *MongoTransactionStorage) GetTransactionByID(ctx context.Context, transactionID string) (*models.Transaction, error) {
 	// Search by transaction_id field - this is the key fix
 	filter := bson.M{"transactionid": transactionID}
@@ -138,7 +434,7 @@ func (s *MongoTransactionStorage) GetTransactionByID(ctx context.Context, transa
 	err := s.collection.FindOne(ctx, filter).Decode(&transaction)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("transaction not found")
+			return nil, models.ErrTransactionNotFound
 		}
 		return nil, fmt.Errorf("failed to find transaction: %w", err)
 	}
@@ -146,8 +442,144 @@ func (s *MongoTransactionStorage) GetTransactionByID(ctx context.Context, transa
 	return &transaction, nil
 }
 
+// settledStatuses are the statuses of records whose balance change happened;
+// a reversed transaction still moved money before its reversal did
+var settledStatuses = bson.M{"$in": []string{"completed", "partially_refunded", "reversed"}}
+
+// GetLatestTransactionBefore returns the account's last settled transaction
+// logged before t, or nil if there is none
+func (s *MongoTransactionStorage) GetLatestTransactionBefore(ctx context.Context, accountID string, t time.Time) (*models.Transaction, error) {
+	filter := bson.M{
+		"accountid": accountID,
+		"status":    settledStatuses,
+		"timestamp": bson.M{"$lt": t},
+	}
+	return s.findFirst(ctx, filter, -1)
+}
+
+// GetEarliestTransactionFrom returns the account's first settled transaction
+// logged at or after t, or nil if there is none
+func (s *MongoTransactionStorage) GetEarliestTransactionFrom(ctx context.Context, accountID string, t time.Time) (*models.Transaction, error) {
+	filter := bson.M{
+		"accountid": accountID,
+		"status":    settledStatuses,
+		"timestamp": bson.M{"$gte": t},
+	}
+	return s.findFirst(ctx, filter, 1)
+}
+
+// findFirst returns the first record matching filter in timestamp order
+// (1 ascending, -1 descending), or nil if none match
+func (s *MongoTransactionStorage) findFirst(ctx context.Context, filter bson.M, order int) (*models.Transaction, error) {
+	findOptions := options.FindOne().SetSort(bson.D{{Key: "timestamp", Value: order}})
+
+	var transaction models.Transaction
+	err := s.collection.FindOne(ctx, filter, findOptions).Decode(&transaction)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find transaction: %w", err)
+	}
+
+	return &transaction, nil
+}
+
+// GetTransactionsInRange returns the account's settled transactions logged in
+// [from, to), oldest first
+func (s *MongoTransactionStorage) GetTransactionsInRange(ctx context.Context, accountID string, from, to time.Time) ([]models.Transaction, error) {
+	filter := bson.M{
+		"accountid": accountID,
+		"status":    settledStatuses,
+		"timestamp": bson.M{"$gte": from, "$lt": to},
+	}
+
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "timestamp", Value: 1}})
+
+	cursor, err := s.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find transactions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var transactions []models.Transaction
+	if err := cursor.All(ctx, &transactions); err != nil {
+		return nil, fmt.Errorf("failed to decode transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// GetAccountTransactions returns every record logged for the account, in any
+// status, oldest first
+func (s *MongoTransactionStorage) GetAccountTransactions(ctx context.Context, accountID string) ([]models.Transaction, error) {
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := s.collection.Find(ctx, bson.M{"accountid": accountID}, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find transactions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var transactions []models.Transaction
+	if err := cursor.All(ctx, &transactions); err != nil {
+		return nil, fmt.Errorf("failed to decode transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// GetSealedTransactions returns an account's sealed records in chain order
+func (s *MongoTransactionStorage) GetSealedTransactions(ctx context.Context, accountID string) ([]models.Transaction, error) {
+	filter := bson.M{"accountid": accountID, "chainseq": bson.M{"$exists": true}}
+
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "chainseq", Value: 1}})
+
+	cursor, err := s.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sealed transactions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var transactions []models.Transaction
+	if err := cursor.All(ctx, &transactions); err != nil {
+		return nil, fmt.Errorf("failed to decode sealed transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// GetTransactionsByTransferID returns the debit and credit legs recorded for a transfer
+func (s *MongoTransactionStorage) GetTransactionsByTransferID(ctx context.Context, transferID string) ([]models.Transaction, error) {
+	filter := bson.M{"transferid": transferID}
+
+	cursor, err := s.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find transfer transactions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var transactions []models.Transaction
+	if err := cursor.All(ctx, &transactions); err != nil {
+		return nil, fmt.Errorf("failed to decode transfer transactions: %w", err)
+	}
+
+	if len(transactions) == 0 {
+		return nil, models.ErrTransferNotFound
+	}
+
+	return transactions, nil
+}
+
 func (s *MongoTransactionStorage) UpdateTransactionStatus(ctx context.Context, transactionID, status string) error {
-	filter := bson.M{"transactionid": transactionID}
+	if err := checkStatusUpdate(status); err != nil {
+		return err
+	}
+
+	filter := bson.M{"transactionid": transactionID, "hash": notSealed}
 	update := bson.M{"$set": bson.M{"status": status}}
 
 	result, err := s.collection.UpdateOne(ctx, filter, update)
@@ -156,7 +588,7 @@ func (s *MongoTransactionStorage) UpdateTransactionStatus(ctx context.Context, t
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("transaction not found for status update")
+		return s.unchangeable(ctx, transactionID, "transaction not found for status update")
 	}
 
 	return nil
@@ -164,7 +596,11 @@ func (s *MongoTransactionStorage) UpdateTransactionStatus(ctx context.Context, t
 
 // UpdateTransactionStatusWithError updates transaction status with error message
 func (s *MongoTransactionStorage) UpdateTransactionStatusWithError(ctx context.Context, transactionID, status, errorMessage string) error {
-	filter := bson.M{"transactionid": transactionID}
+	if err := checkStatusUpdate(status); err != nil {
+		return err
+	}
+
+	filter := bson.M{"transactionid": transactionID, "hash": notSealed}
 	update := bson.M{
 		"$set": bson.M{
 			"status":       status,
@@ -178,7 +614,210 @@ func (s *MongoTransactionStorage) UpdateTransactionStatusWithError(ctx context.C
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("transaction not found for status update with error")
+		return s.unchangeable(ctx, transactionID, "transaction not found for status update with error")
+	}
+
+	return nil
+}
+
+// refundable are the statuses of records that can still be refunded
+var refundable = bson.M{"$in": []string{"completed", "partially_refunded"}}
+
+// RecordRefund adds amount to a transaction's refunded total and links the
+// refund to it. The original moves to "partially_refunded", or to "reversed"
+// once the refunds add up to its amount. It reports false when the
+// transaction cannot be refunded or the refund would exceed what is left, so
+// concurrent refunds can never together take back more than the original.
+func (s *MongoTransactionStorage) RecordRefund(ctx context.Context, transactionID, reversalID string, amount models.Money, refundedAt time.Time) (bool, error) {
+	refunded := bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$refundedamount", models.Money(0)}}, amount}}
+
+	filter := bson.M{
+		"transactionid": transactionID,
+		"status":        refundable,
+		"$expr":         bson.M{"$lte": bson.A{refunded, "$amount"}},
+	}
+
+	full := bson.M{"$gte": bson.A{"$refundedamount", "$amount"}}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"refundedamount": refunded,
+			"refunds":        bson.M{"$concatArrays": bson.A{bson.M{"$ifNull": bson.A{"$refunds", bson.A{}}}, bson.A{reversalID}}},
+		}}},
+		{{Key: "$set", Value: bson.M{
+			"status":     bson.M{"$cond": bson.A{full, "reversed", "partially_refunded"}},
+			"reversedby": bson.M{"$cond": bson.A{full, reversalID, "$$REMOVE"}},
+			"reversedat": bson.M{"$cond": bson.A{full, refundedAt, "$$REMOVE"}},
+		}}},
+	}
+
+	result, err := s.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("failed to record refund: %w", err)
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
+// RestoreRefund undoes RecordRefund when the refund could not be completed
+func (s *MongoTransactionStorage) RestoreRefund(ctx context.Context, transactionID, reversalID string, amount models.Money) error {
+	filter := bson.M{
+		"transactionid": transactionID,
+		"refunds":       reversalID,
+	}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"refundedamount": bson.M{"$subtract": bson.A{"$refundedamount", amount}},
+			"refunds":        bson.M{"$setDifference": bson.A{"$refunds", bson.A{reversalID}}},
+		}}},
+		{{Key: "$set", Value: bson.M{
+			"status": bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$refundedamount", models.Money(0)}}, "partially_refunded", "completed"}},
+		}}},
+		{{Key: "$unset", Value: bson.A{"reversedby", "reversedat"}}},
+	}
+
+	result, err := s.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to restore refunded transaction: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return models.Errorf(models.ErrTransactionNotFound, "refunded transaction not found for restore")
+	}
+
+	return nil
+}
+
+// ClaimOutboxEntry atomically picks the oldest due outbox entry and pushes its
+// next attempt out by lease, so concurrent relays never publish the same entry.
+// Returns nil when nothing is due.
+func (s *MongoTransactionStorage) ClaimOutboxEntry(ctx context.Context, now time.Time, lease time.Duration) (*models.Transaction, error) {
+	filter := bson.M{
+		"outbox.dispatched":    false,
+		"outbox.nextattemptat": bson.M{"$lte": now},
+	}
+	update := bson.M{
+		"$set": bson.M{"outbox.nextattemptat": now.Add(lease)},
+		"$inc": bson.M{"outbox.attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "outbox.nextattemptat", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var transaction models.Transaction
+	err := s.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&transaction)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox entry: %w", err)
+	}
+
+	return &transaction, nil
+}
+
+// MarkOutboxDispatched records that the entry's message reached the broker
+func (s *MongoTransactionStorage) MarkOutboxDispatched(ctx context.Context, transactionID string, dispatchedAt time.Time) error {
+	filter := bson.M{"transactionid": transactionID}
+	update := bson.M{
+		"$set": bson.M{
+			"outbox.dispatched":   true,
+			"outbox.dispatchedat": dispatchedAt,
+			"outbox.lasterror":    "",
+		},
+	}
+
+	result, err := s.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox entry dispatched: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return models.Errorf(models.ErrTransactionNotFound, "transaction not found for outbox update")
+	}
+
+	return nil
+}
+
+// RescheduleOutboxEntry sets when a failed publish should be retried
+func (s *MongoTransactionStorage) RescheduleOutboxEntry(ctx context.Context, transactionID string, nextAttemptAt time.Time, lastError string) error {
+	filter := bson.M{"transactionid": transactionID}
+	update := bson.M{
+		"$set": bson.M{
+			"outbox.nextattemptat": nextAttemptAt,
+			"outbox.lasterror":     lastError,
+		},
+	}
+
+	result, err := s.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to reschedule outbox entry: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return models.Errorf(models.ErrTransactionNotFound, "transaction not found for outbox update")
+	}
+
+	return nil
+}
+
+// GetStalePendingTransactions returns pending records created before olderThan, oldest first
+func (s *MongoTransactionStorage) GetStalePendingTransactions(ctx context.Context, olderThan time.Time, limit int) ([]models.Transaction, error) {
+	filter := bson.M{
+		"status":    "pending",
+		"timestamp": bson.M{"$lt": olderThan},
+	}
+
+	findOptions := options.Find()
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetSort(bson.D{{Key: "timestamp", Value: 1}})
+
+	cursor, err := s.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find stale pending transactions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var transactions []models.Transaction
+	if err := cursor.All(ctx, &transactions); err != nil {
+		return nil, fmt.Errorf("failed to decode stale pending transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// CountStalePendingTransactions counts pending records created before olderThan
+func (s *MongoTransactionStorage) CountStalePendingTransactions(ctx context.Context, olderThan time.Time) (int64, error) {
+	filter := bson.M{
+		"status":    "pending",
+		"timestamp": bson.M{"$lt": olderThan},
+	}
+
+	count, err := s.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count stale pending transactions: %w", err)
+	}
+
+	return count, nil
+}
+
+// RequeueOutboxEntry replaces a record's outbox entry so the relay publishes it
+// again, and notes why on the record
+func (s *MongoTransactionStorage) RequeueOutboxEntry(ctx context.Context, transactionID string, entry *models.OutboxEntry, reason string) error {
+	filter := bson.M{"transactionid": transactionID, "status": "pending"}
+	update := bson.M{
+		"$set": bson.M{
+			"outbox":       entry,
+			"errormessage": reason,
+		},
+	}
+
+	result, err := s.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to requeue outbox entry: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return models.Errorf(models.ErrTransactionNotFound, "pending transaction not found for requeue")
 	}
 
 	return nil
@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/appy29/banking-ledger-service/metrics"
+	"github.com/appy29/banking-ledger-service/tracing"
+	"github.com/lib/pq"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// commandMonitor times every command a Mongo client sends and, when the
+// operation's context is traced, records a client span for it. Each storage
+// has its own client, so the collection label is fixed per client.
+func commandMonitor(collection string) *event.CommandMonitor {
+	// Started and finished events are matched by request ID
+	var spans sync.Map
+
+	finish := func(requestID int64, err string) {
+		if value, ok := spans.LoadAndDelete(requestID); ok {
+			span := value.(trace.Span)
+			if err != "" {
+				tracing.RecordError(span, errors.New(err))
+			}
+			span.End()
+		}
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			_, span := tracing.StartChild(ctx, "mongodb "+e.CommandName, trace.SpanKindClient,
+				attribute.String("db.system", "mongodb"),
+				attribute.String("db.name", e.DatabaseName),
+				attribute.String("db.operation", e.CommandName),
+				attribute.String("db.mongodb.collection", collection))
+			if span.IsRecording() {
+				spans.Store(e.RequestID, span)
+			}
+		},
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			metrics.MongoOperationDuration.WithLabelValues(collection, e.CommandName, "success").Observe(e.Duration.Seconds())
+			finish(e.RequestID, "")
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			metrics.MongoOperationDuration.WithLabelValues(collection, e.CommandName, "error").Observe(e.Duration.Seconds())
+			finish(e.RequestID, e.Failure)
+		},
+	}
+}
+
+// openPostgres opens the database through a connector that records a client
+// span for each statement run under a traced context
+func openPostgres(dsn string) (*sql.DB, error) {
+	connector, err := pq.NewConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(tracedConnector{connector}), nil
+}
+
+type tracedConnector struct {
+	driver.Connector
+}
+
+func (c tracedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if pgConn, ok := conn.(postgresConn); ok {
+		return tracedConn{pgConn}, nil
+	}
+	return conn, nil
+}
+
+// postgresConn is what lib/pq's connections implement. Embedding it keeps
+// every optional driver interface that database/sql looks for.
+type postgresConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+	driver.SessionResetter
+	driver.Validator
+}
+
+type tracedConn struct {
+	postgresConn
+}
+
+func (c tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	span := startStatementSpan(ctx, query)
+	result, err := c.postgresConn.ExecContext(ctx, query, args)
+	endStatementSpan(span, err)
+	return result, err
+}
+
+// QueryContext's span ends once the first results are back, which includes any
+// time spent waiting for row locks
+func (c tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	span := startStatementSpan(ctx, query)
+	rows, err := c.postgresConn.QueryContext(ctx, query, args)
+	endStatementSpan(span, err)
+	return rows, err
+}
+
+func startStatementSpan(ctx context.Context, query string) trace.Span {
+	operation := "QUERY"
+	if fields := strings.Fields(query); len(fields) > 0 {
+		operation = strings.ToUpper(fields[0])
+	}
+	_, span := tracing.StartChild(ctx, "postgres "+operation, trace.SpanKindClient,
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.operation", operation),
+		attribute.String("db.statement", query))
+	return span
+}
+
+func endStatementSpan(span trace.Span, err error) {
+	// ErrSkip only tells database/sql to take another path
+	if !errors.Is(err, driver.ErrSkip) {
+		tracing.RecordError(span, err)
+	}
+	span.End()
+}
@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+)
+
+// SetOverdraftLimit changes how far debits may take an account below zero.
+// The account row is locked first, so the change is serialized with postings.
+// Lowering the limit below what is already overdrawn is allowed; the account
+// then takes no debits until it is back within the new limit.
+func (s *PostgresAccountStorage) SetOverdraftLimit(ctx context.Context, accountID string, limit models.Money) (*models.Account, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Will be ignored if tx.Commit() succeeds
+
+	accounts, err := lockAccounts(ctx, tx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if accounts[accountID].Status == models.AccountClosed {
+		return nil, models.Errorf(models.ErrAccountClosed, "account %s is closed", accountID)
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE accounts SET overdraft_limit = $1, updated_at = $2 WHERE id = $3",
+		limit, time.Now(), accountID); err != nil {
+		return nil, fmt.Errorf("failed to update overdraft limit: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.GetAccountByID(ctx, accountID)
+}
@@ -4,10 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sort"
 	"time"
 
+	"github.com/appy29/banking-ledger-service/metrics"
 	"github.com/appy29/banking-ledger-service/models"
-	_ "github.com/lib/pq"
 )
 
 type PostgresAccountStorage struct {
@@ -15,7 +16,7 @@ type PostgresAccountStorage struct {
 }
 
 func NewPostgresAccountStorage(dsn string) (*PostgresAccountStorage, error) {
-	db, err := sql.Open("postgres", dsn)
+	db, err := openPostgres(dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -29,6 +30,46 @@ func NewPostgresAccountStorage(dsn string) (*PostgresAccountStorage, error) {
 		return nil, fmt.Errorf("failed to create accounts table: %w", err)
 	}
 
+	if err := migrateAccountsTable(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate accounts table: %w", err)
+	}
+
+	if err := createAppliedTransactionsTable(db); err != nil {
+		return nil, fmt.Errorf("failed to create applied_transactions table: %w", err)
+	}
+
+	if err := createAccountStatusChangesTable(db); err != nil {
+		return nil, fmt.Errorf("failed to create account_status_changes table: %w", err)
+	}
+
+	if err := createLimitsTables(db); err != nil {
+		return nil, fmt.Errorf("failed to create limits tables: %w", err)
+	}
+
+	if err := createHoldsTable(db); err != nil {
+		return nil, fmt.Errorf("failed to create holds table: %w", err)
+	}
+
+	if err := createSchedulesTable(db); err != nil {
+		return nil, fmt.Errorf("failed to create schedules table: %w", err)
+	}
+
+	if err := createWebhookTables(db); err != nil {
+		return nil, fmt.Errorf("failed to create webhook tables: %w", err)
+	}
+
+	if err := createAuditLogTable(db); err != nil {
+		return nil, fmt.Errorf("failed to create audit_log table: %w", err)
+	}
+
+	if err := createLedgerTables(db); err != nil {
+		return nil, fmt.Errorf("failed to create ledger tables: %w", err)
+	}
+
+	if err := backfillOpeningEntries(db); err != nil {
+		return nil, fmt.Errorf("failed to backfill opening entries: %w", err)
+	}
+
 	return &PostgresAccountStorage{db: db}, nil
 }
 
@@ -37,7 +78,11 @@ func createAccountsTable(db *sql.DB) error {
 	CREATE TABLE IF NOT EXISTS accounts (
 		id VARCHAR(255) PRIMARY KEY,
 		owner_name VARCHAR(255) NOT NULL,
-		balance DECIMAL(15,2) NOT NULL DEFAULT 0.00,
+		owner_id VARCHAR(255) NOT NULL DEFAULT '',
+		balance DECIMAL(19,4) NOT NULL DEFAULT 0,
+		currency CHAR(3) NOT NULL DEFAULT 'USD',
+		status VARCHAR(16) NOT NULL DEFAULT 'active',
+		overdraft_limit DECIMAL(19,4) NOT NULL DEFAULT 0 CHECK (overdraft_limit >= 0),
 		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
 		updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
 	);
@@ -47,55 +92,293 @@ func createAccountsTable(db *sql.DB) error {
 	return err
 }
 
+// createAppliedTransactionsTable creates the table that records which queued
+// transactions have changed a balance. Rows are written in the same database
+// transaction as the balance update, so they are the source of truth for
+// whether a pending transaction was applied.
+func createAppliedTransactionsTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS applied_transactions (
+		transaction_id VARCHAR(255) PRIMARY KEY,
+		account_id VARCHAR(255) NOT NULL REFERENCES accounts(id),
+		previous_balance DECIMAL(19,4) NOT NULL,
+		new_balance DECIMAL(19,4) NOT NULL,
+		applied_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+	);
+	`
+	_, err := db.Exec(query)
+	return err
+}
+
+// accountMigrations bring tables created by older versions up to date.
+// Every statement must be idempotent because they run on each startup.
+var accountMigrations = []string{
+	// Widen balances created as DECIMAL(15,2) so they can hold every value models.Money can represent
+	`DO $$
+	BEGIN
+		IF EXISTS (
+			SELECT 1 FROM information_schema.columns
+			WHERE table_name = 'accounts' AND column_name = 'balance' AND numeric_scale <> 4
+		) THEN
+			ALTER TABLE accounts ALTER COLUMN balance TYPE DECIMAL(19,4);
+		END IF;
+	END $$;`,
+	// Accounts created before multi-currency support are USD accounts
+	`ALTER TABLE accounts ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD'`,
+	// Accounts created before account states are active
+	`ALTER TABLE accounts ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'active'`,
+	// Accounts created before overdrafts have none
+	`ALTER TABLE accounts ADD COLUMN IF NOT EXISTS overdraft_limit DECIMAL(19,4) NOT NULL DEFAULT 0 CHECK (overdraft_limit >= 0)`,
+	// Accounts created before authentication have no owner; only admins and operators can reach them
+	`ALTER TABLE accounts ADD COLUMN IF NOT EXISTS owner_id VARCHAR(255) NOT NULL DEFAULT ''`,
+	`CREATE INDEX IF NOT EXISTS idx_accounts_owner_id ON accounts(owner_id)`,
+}
+
+func migrateAccountsTable(db *sql.DB) error {
+	for _, statement := range accountMigrations {
+		if _, err := db.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateAccount inserts the account and posts its initial balance as an
+// opening entry, so the account's postings always add up to its balance
 func (s *PostgresAccountStorage) CreateAccount(ctx context.Context, account *models.Account) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Will be ignored if tx.Commit() succeeds
+
 	query := `
-		INSERT INTO accounts (id, owner_name, balance, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO accounts (id, owner_name, owner_id, balance, currency, status, created_at, updated_at)
+		VALUES ($1, $2, $3, 0, $4, $5, $6, $7)
 	`
-	_, err := s.db.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, query,
 		account.ID,
 		account.OwnerName,
-		account.Balance,
+		account.OwnerID,
+		account.Currency,
+		account.Status,
 		account.CreatedAt,
 		account.UpdatedAt,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if !account.Balance.IsZero() {
+		if _, err := postEntry(ctx, tx, openingEntry(account, account.Balance), true); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
+// GetAccountByID returns an account with its ledger balance, the balance
+// available once active holds are taken out and its overdraft added, and its
+// limit overrides
 func (s *PostgresAccountStorage) GetAccountByID(ctx context.Context, accountID string) (*models.Account, error) {
 	query := `
-		SELECT id, owner_name, balance, created_at, updated_at
-		FROM accounts WHERE id = $1
+		SELECT a.id, a.owner_name, a.owner_id, a.balance, a.currency, a.status, a.overdraft_limit, a.created_at, a.updated_at,
+			COALESCE((SELECT SUM(h.amount) FROM holds h
+				WHERE h.account_id = a.id AND h.status = 'active' AND h.expires_at > $2), 0),
+			l.max_deposit, l.max_withdrawal, l.max_daily_withdrawals, l.max_daily_withdrawn, l.max_monthly_withdrawn
+		FROM accounts a LEFT JOIN account_limits l ON l.account_id = a.id
+		WHERE a.id = $1
 	`
 
 	account := &models.Account{}
-	err := s.db.QueryRowContext(ctx, query, accountID).Scan(
+	overrides := &models.LimitOverrides{}
+	err := s.db.QueryRowContext(ctx, query, accountID, time.Now()).Scan(
 		&account.ID,
 		&account.OwnerName,
+		&account.OwnerID,
 		&account.Balance,
+		&account.Currency,
+		&account.Status,
+		&account.OverdraftLimit,
 		&account.CreatedAt,
 		&account.UpdatedAt,
+		&account.HeldBalance,
+		&overrides.MaxDeposit,
+		&overrides.MaxWithdrawal,
+		&overrides.MaxDailyWithdrawals,
+		&overrides.MaxDailyWithdrawn,
+		&overrides.MaxMonthlyWithdrawn,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("account not found")
+			return nil, models.ErrAccountNotFound
 		}
 		return nil, fmt.Errorf("failed to get account: %w", err)
 	}
+	account.Overdrawn = account.Balance.IsNegative()
+	account.AvailableBalance = account.Available()
+	if !overrides.IsEmpty() {
+		account.LimitOverrides = overrides
+	}
 
 	return account, nil
 }
 
-func (s *PostgresAccountStorage) UpdateBalance(ctx context.Context, accountID string, newBalance float64) error {
-	query := `
-		UPDATE accounts 
-		SET balance = $1, updated_at = $2
-		WHERE id = $3
-	`
-	result, err := s.db.ExecContext(ctx, query, newBalance, time.Now(), accountID)
+// ListAccounts returns up to limit accounts with IDs after afterID, in ID order
+func (s *PostgresAccountStorage) ListAccounts(ctx context.Context, afterID string, limit int) ([]models.Account, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, owner_name, owner_id, balance, currency, status, overdraft_limit, created_at, updated_at
+		FROM accounts WHERE id > $1 ORDER BY id LIMIT $2
+	`, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []models.Account
+	for rows.Next() {
+		var account models.Account
+		if err := rows.Scan(&account.ID, &account.OwnerName, &account.OwnerID, &account.Balance, &account.Currency, &account.Status, &account.OverdraftLimit, &account.CreatedAt, &account.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan account: %w", err)
+		}
+		account.Overdrawn = account.Balance.IsNegative()
+		accounts = append(accounts, account)
+	}
+
+	return accounts, rows.Err()
+}
+
+// ApplyTransaction posts a queued deposit or withdrawal and records the balance
+// change under transactionID in the same database transaction; a transaction
+// that was already applied is rejected. A withdrawal is checked against limits
+// when they are set.
+func (s *PostgresAccountStorage) ApplyTransaction(ctx context.Context, transactionID, accountID, transactionType string, amount models.Money, limits *models.TransactionLimits) (models.Money, models.Money, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Will be ignored if tx.Commit() succeeds
+
+	previousBalance, newBalance, err := updateBalance(ctx, tx, transactionID, accountID, transactionType, amount, limits)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err := recordApplied(ctx, tx, transactionID, accountID, previousBalance, newBalance); err != nil {
+		return 0, 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return previousBalance, newBalance, nil
+}
+
+// updateBalance posts a deposit or withdrawal against the cash account
+func updateBalance(ctx context.Context, tx *sql.Tx, transactionID, accountID, transactionType string, amount models.Money, limits *models.TransactionLimits) (models.Money, models.Money, error) {
+	currency, err := accountCurrency(ctx, tx, accountID)
 	if err != nil {
-		return fmt.Errorf("failed to update balance: %w", err)
+		return 0, 0, err
+	}
+
+	entry, err := models.NewTransactionEntry(transactionID, accountID, transactionType, amount, currency, transactionType)
+	if err != nil {
+		return 0, 0, err
+	}
+	entry.Limits = limits
+
+	changes, err := postEntry(ctx, tx, entry, true)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return changes[0].PreviousBalance, changes[0].NewBalance, nil
+}
+
+// accountCurrency returns the currency an account is denominated in
+func accountCurrency(ctx context.Context, tx *sql.Tx, accountID string) (string, error) {
+	var currency string
+	err := tx.QueryRowContext(ctx, "SELECT currency FROM accounts WHERE id = $1", accountID).Scan(&currency)
+	if err == sql.ErrNoRows {
+		return "", models.ErrAccountNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get account currency: %w", err)
+	}
+	return currency, nil
+}
+
+// ApplyTransfer posts a queued transfer; both legs are recorded as applied in
+// the same database transaction as the balance changes. The source account is
+// checked against limits when they are set.
+func (s *PostgresAccountStorage) ApplyTransfer(ctx context.Context, debitTransactionID, creditTransactionID, fromAccountID, toAccountID string, amount models.Money, limits *models.TransactionLimits) (models.Money, models.Money, models.Money, models.Money, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Will be ignored if tx.Commit() succeeds
+
+	fromPrevious, fromNew, toPrevious, toNew, err := transferBalance(ctx, tx, debitTransactionID, creditTransactionID, fromAccountID, toAccountID, amount, limits)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+
+	if err := recordApplied(ctx, tx, debitTransactionID, fromAccountID, fromPrevious, fromNew); err != nil {
+		return 0, 0, 0, 0, err
+	}
+	if err := recordApplied(ctx, tx, creditTransactionID, toAccountID, toPrevious, toNew); err != nil {
+		return 0, 0, 0, 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return fromPrevious, fromNew, toPrevious, toNew, nil
+}
+
+// transferBalance posts a debit to one account and a credit to the other
+func transferBalance(ctx context.Context, tx *sql.Tx, debitTransactionID, creditTransactionID, fromAccountID, toAccountID string, amount models.Money, limits *models.TransactionLimits) (models.Money, models.Money, models.Money, models.Money, error) {
+	if fromAccountID == toAccountID {
+		return 0, 0, 0, 0, models.ErrSameAccount
+	}
+
+	currency, err := accountCurrency(ctx, tx, fromAccountID)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+
+	entry := models.NewTransferEntry(debitTransactionID, creditTransactionID, fromAccountID, toAccountID, amount, currency, "transfer")
+	entry.Limits = limits
+	changes, err := postEntry(ctx, tx, entry, true)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+
+	var from, to models.BalanceChange
+	for _, change := range changes {
+		if change.AccountID == fromAccountID {
+			from = change
+		} else {
+			to = change
+		}
+	}
+
+	return from.PreviousBalance, from.NewBalance, to.PreviousBalance, to.NewBalance, nil
+}
+
+// recordApplied inserts the applied_transactions row for a balance change
+func recordApplied(ctx context.Context, tx *sql.Tx, transactionID, accountID string, previousBalance, newBalance models.Money) error {
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO applied_transactions (transaction_id, account_id, previous_balance, new_balance, applied_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (transaction_id) DO NOTHING
+	`, transactionID, accountID, previousBalance, newBalance, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to record applied transaction: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -104,57 +387,111 @@ func (s *PostgresAccountStorage) UpdateBalance(ctx context.Context, accountID st
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("account not found")
+		return models.Errorf(models.ErrAlreadyApplied, "transaction already applied: %s", transactionID)
 	}
 
 	return nil
 }
 
-// AtomicBalanceUpdate performs atomic balance updates with proper locking
-func (s *PostgresAccountStorage) AtomicBalanceUpdate(ctx context.Context, accountID, transactionType string, amount float64) (float64, float64, error) {
-	tx, err := s.db.BeginTx(ctx, nil)
+// GetAppliedTransaction returns the recorded balance change for a transaction,
+// or nil if the transaction never changed a balance
+func (s *PostgresAccountStorage) GetAppliedTransaction(ctx context.Context, transactionID string) (*models.AppliedTransaction, error) {
+	query := `
+		SELECT transaction_id, account_id, previous_balance, new_balance, applied_at
+		FROM applied_transactions
+		WHERE transaction_id = $1
+	`
+
+	applied := &models.AppliedTransaction{}
+	err := s.db.QueryRowContext(ctx, query, transactionID).Scan(
+		&applied.TransactionID,
+		&applied.AccountID,
+		&applied.PreviousBalance,
+		&applied.NewBalance,
+		&applied.AppliedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, fmt.Errorf("failed to get applied transaction: %w", err)
 	}
-	defer tx.Rollback() // Will be ignored if tx.Commit() succeeds
 
-	// Lock the account row and get current balance
-	var previousBalance float64
-	err = tx.QueryRowContext(ctx, "SELECT balance FROM accounts WHERE id = $1 FOR UPDATE", accountID).Scan(&previousBalance)
+	return applied, nil
+}
+
+// RevertAppliedTransactions undoes the balance changes recorded for the given
+// transactions by posting a compensating entry, and removes their applied
+// records, all in one database transaction. Used to roll back when the
+// transaction log could not be updated.
+func (s *PostgresAccountStorage) RevertAppliedTransactions(ctx context.Context, transactionIDs ...string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return 0, 0, fmt.Errorf("account not found")
-		}
-		return 0, 0, fmt.Errorf("failed to lock account: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback() // Will be ignored if tx.Commit() succeeds
 
-	// Calculate new balance based on transaction type
-	var newBalance float64
-	switch transactionType {
-	case "deposit":
-		newBalance = previousBalance + amount
-	case "withdraw":
-		if previousBalance < amount {
-			return 0, 0, fmt.Errorf("insufficient funds: current balance %.2f, requested %.2f", previousBalance, amount)
+	var applied []string
+	for _, transactionID := range transactionIDs {
+		result, err := tx.ExecContext(ctx, "DELETE FROM applied_transactions WHERE transaction_id = $1", transactionID)
+		if err != nil {
+			return fmt.Errorf("failed to remove applied transaction: %w", err)
+		}
+		if rowsAffected, err := result.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		} else if rowsAffected > 0 {
+			applied = append(applied, transactionID)
 		}
-		newBalance = previousBalance - amount
-	default:
-		return 0, 0, fmt.Errorf("invalid transaction type: %s", transactionType)
 	}
 
-	// Update balance atomically
-	_, err = tx.ExecContext(ctx, "UPDATE accounts SET balance = $1, updated_at = $2 WHERE id = $3",
-		newBalance, time.Now(), accountID)
+	entry, err := reversalEntry(ctx, tx, "rollback", applied...)
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to update balance: %w", err)
+		return err
+	}
+	if entry != nil {
+		if _, err := postEntry(ctx, tx, entry, false); err != nil {
+			return fmt.Errorf("failed to revert balance: %w", err)
+		}
 	}
 
-	// Commit the transaction
 	if err = tx.Commit(); err != nil {
-		return 0, 0, fmt.Errorf("failed to commit transaction: %w", err)
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	return previousBalance, newBalance, nil
+	return nil
+}
+
+// lockAccounts locks the given account rows and returns their current balances,
+// currencies and states.
+// Rows are always locked in ascending ID order so that two transactions touching
+// the same accounts in opposite directions cannot deadlock. The time spent
+// waiting for the locks is recorded in ledger_postgres_row_lock_wait_seconds.
+func lockAccounts(ctx context.Context, tx *sql.Tx, accountIDs ...string) (map[string]*models.Account, error) {
+	ordered := append([]string(nil), accountIDs...)
+	sort.Strings(ordered)
+
+	start := time.Now()
+	defer metrics.ObserveSince(metrics.PostgresLockWait, start)
+
+	accounts := make(map[string]*models.Account, len(ordered))
+	for _, accountID := range ordered {
+		if _, locked := accounts[accountID]; locked {
+			continue
+		}
+
+		account := &models.Account{ID: accountID}
+		err := tx.QueryRowContext(ctx, "SELECT balance, currency, status, overdraft_limit FROM accounts WHERE id = $1 FOR UPDATE", accountID).
+			Scan(&account.Balance, &account.Currency, &account.Status, &account.OverdraftLimit)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, models.Errorf(models.ErrAccountNotFound, "account not found: %s", accountID)
+			}
+			return nil, fmt.Errorf("failed to lock account: %w", err)
+		}
+		accounts[accountID] = account
+	}
+
+	return accounts, nil
 }
 
 func (s *PostgresAccountStorage) Close() error {
@@ -0,0 +1,211 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+)
+
+// createSchedulesTable creates the table of standing orders. claimed_until
+// hides a due schedule from other scheduler instances while one of them is
+// running it.
+func createSchedulesTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS schedules (
+		id VARCHAR(255) PRIMARY KEY,
+		account_id VARCHAR(255) NOT NULL REFERENCES accounts(id),
+		type VARCHAR(16) NOT NULL,
+		amount DECIMAL(19,4) NOT NULL CHECK (amount > 0),
+		currency CHAR(3) NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		cron VARCHAR(255) NOT NULL DEFAULT '',
+		missed_runs VARCHAR(16) NOT NULL DEFAULT 'latest',
+		status VARCHAR(16) NOT NULL DEFAULT 'active',
+		next_run_at TIMESTAMP WITH TIME ZONE,
+		end_at TIMESTAMP WITH TIME ZONE,
+		last_run_at TIMESTAMP WITH TIME ZONE,
+		last_transaction_id VARCHAR(255),
+		run_count BIGINT NOT NULL DEFAULT 0,
+		skipped_count BIGINT NOT NULL DEFAULT 0,
+		claimed_until TIMESTAMP WITH TIME ZONE,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+		updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+	);
+	CREATE INDEX IF NOT EXISTS idx_schedules_account ON schedules(account_id, created_at);
+	CREATE INDEX IF NOT EXISTS idx_schedules_due ON schedules(next_run_at) WHERE status = 'active';
+	`
+	_, err := db.Exec(query)
+	return err
+}
+
+const scheduleColumns = `id, account_id, type, amount, currency, description, cron, missed_runs, status,
+	next_run_at, end_at, last_run_at, COALESCE(last_transaction_id, ''), run_count, skipped_count, created_at, updated_at`
+
+// scanSchedule reads a row selected with scheduleColumns
+func scanSchedule(row interface{ Scan(...interface{}) error }) (*models.Schedule, error) {
+	schedule := &models.Schedule{}
+	err := row.Scan(&schedule.ID, &schedule.AccountID, &schedule.Type, &schedule.Amount, &schedule.Currency,
+		&schedule.Description, &schedule.Cron, &schedule.MissedRuns, &schedule.Status, &schedule.NextRunAt,
+		&schedule.EndAt, &schedule.LastRunAt, &schedule.LastTransactionID, &schedule.RunCount,
+		&schedule.SkippedCount, &schedule.CreatedAt, &schedule.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// CreateSchedule stores a new schedule
+func (s *PostgresAccountStorage) CreateSchedule(ctx context.Context, schedule *models.Schedule) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO schedules (id, account_id, type, amount, currency, description, cron, missed_runs, status,
+			next_run_at, end_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`, schedule.ID, schedule.AccountID, schedule.Type, schedule.Amount, schedule.Currency, schedule.Description,
+		schedule.Cron, schedule.MissedRuns, schedule.Status, schedule.NextRunAt, schedule.EndAt,
+		schedule.CreatedAt, schedule.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert schedule: %w", err)
+	}
+	return nil
+}
+
+// GetSchedule returns a schedule by ID
+func (s *PostgresAccountStorage) GetSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error) {
+	schedule, err := scanSchedule(s.db.QueryRowContext(ctx, "SELECT "+scheduleColumns+" FROM schedules WHERE id = $1", scheduleID))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule: %w", err)
+	}
+	return schedule, nil
+}
+
+// GetAccountSchedules returns an account's schedules, newest first
+func (s *PostgresAccountStorage) GetAccountSchedules(ctx context.Context, accountID string) ([]models.Schedule, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+scheduleColumns+" FROM schedules WHERE account_id = $1 ORDER BY created_at DESC, id", accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedules: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := []models.Schedule{}
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan schedule: %w", err)
+		}
+		schedules = append(schedules, *schedule)
+	}
+
+	return schedules, rows.Err()
+}
+
+// ChangeSchedule locks a schedule, lets change edit it and saves the result.
+// The row lock serializes the change with the scheduler advancing the same
+// schedule, so neither overwrites the other.
+func (s *PostgresAccountStorage) ChangeSchedule(ctx context.Context, scheduleID string, change func(*models.Schedule) error) (*models.Schedule, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Will be ignored if tx.Commit() succeeds
+
+	schedule, err := scanSchedule(tx.QueryRowContext(ctx, "SELECT "+scheduleColumns+" FROM schedules WHERE id = $1 FOR UPDATE", scheduleID))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock schedule: %w", err)
+	}
+
+	if err := change(schedule); err != nil {
+		return nil, err
+	}
+	schedule.UpdatedAt = time.Now()
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE schedules SET amount = $1, description = $2, cron = $3, missed_runs = $4, status = $5,
+			next_run_at = $6, end_at = $7, skipped_count = $8, updated_at = $9
+		WHERE id = $10
+	`, schedule.Amount, schedule.Description, schedule.Cron, schedule.MissedRuns, schedule.Status,
+		schedule.NextRunAt, schedule.EndAt, schedule.SkippedCount, schedule.UpdatedAt, scheduleID); err != nil {
+		return nil, fmt.Errorf("failed to update schedule: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// ClaimDueSchedules claims up to limit active schedules due at now that no
+// other scheduler holds, hiding them from other instances until leaseUntil.
+// SKIP LOCKED lets instances claiming at the same moment take different rows.
+func (s *PostgresAccountStorage) ClaimDueSchedules(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.Schedule, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		UPDATE schedules SET claimed_until = $2
+		WHERE id IN (
+			SELECT id FROM schedules
+			WHERE status = 'active' AND next_run_at <= $1 AND (claimed_until IS NULL OR claimed_until <= $1)
+			ORDER BY next_run_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+scheduleColumns, now, leaseUntil, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim schedules: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := []models.Schedule{}
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan schedule: %w", err)
+		}
+		schedules = append(schedules, *schedule)
+	}
+
+	return schedules, rows.Err()
+}
+
+// AdvanceSchedule records step on a schedule whose next run is still from:
+// the occurrence run and the transaction logged for it, the occurrences
+// skipped, and the next run, whose absence completes the schedule. It also
+// gives up the scheduler's claim. It reports false, changing nothing, if the
+// schedule was advanced or rescheduled since it was read.
+func (s *PostgresAccountStorage) AdvanceSchedule(ctx context.Context, scheduleID string, from time.Time, step *models.ScheduleStep, transactionID string) (bool, error) {
+	var runs int64
+	var lastTransactionID sql.NullString
+	if step.Run != nil {
+		runs = 1
+		lastTransactionID = sql.NullString{String: transactionID, Valid: true}
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE schedules SET
+			next_run_at = $3,
+			last_run_at = COALESCE($4, last_run_at),
+			last_transaction_id = COALESCE($5, last_transaction_id),
+			run_count = run_count + $6,
+			skipped_count = skipped_count + $7,
+			status = CASE WHEN $3::timestamptz IS NULL AND status IN ('active', 'paused') THEN 'completed' ELSE status END,
+			claimed_until = NULL,
+			updated_at = $8
+		WHERE id = $1 AND next_run_at = $2
+	`, scheduleID, from, step.Next, step.Run, lastTransactionID, runs, step.Skipped, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("failed to advance schedule: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check schedule update: %w", err)
+	}
+	return rows == 1, nil
+}
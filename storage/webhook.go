@@ -0,0 +1,351 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/lib/pq"
+)
+
+// createWebhookTables creates webhook subscriptions, their deliveries and the
+// log of every delivery attempt. A subscription's deliveries and attempts are
+// deleted with it. claimed_until hides a due delivery from other dispatchers
+// while one of them is sending it.
+func createWebhookTables(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+		id VARCHAR(255) PRIMARY KEY,
+		account_id VARCHAR(255) REFERENCES accounts(id),
+		url TEXT NOT NULL,
+		events TEXT[] NOT NULL,
+		secret VARCHAR(255) NOT NULL,
+		low_balance_threshold DECIMAL(19,4),
+		description TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+		updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+	);
+	CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_account ON webhook_subscriptions(account_id);
+
+	CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		id VARCHAR(255) PRIMARY KEY,
+		subscription_id VARCHAR(255) NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+		event_id VARCHAR(255) NOT NULL,
+		event_type VARCHAR(64) NOT NULL,
+		account_id VARCHAR(255) NOT NULL DEFAULT '',
+		payload JSONB NOT NULL,
+		status VARCHAR(16) NOT NULL DEFAULT 'pending',
+		attempts INTEGER NOT NULL DEFAULT 0,
+		next_attempt_at TIMESTAMP WITH TIME ZONE,
+		claimed_until TIMESTAMP WITH TIME ZONE,
+		last_status_code INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		redelivery_of VARCHAR(255),
+		delivered_at TIMESTAMP WITH TIME ZONE,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+		updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+	);
+	CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries(subscription_id, created_at);
+	CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+
+	CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+		delivery_id VARCHAR(255) NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+		attempt INTEGER NOT NULL,
+		status_code INTEGER NOT NULL DEFAULT 0,
+		error TEXT NOT NULL DEFAULT '',
+		response_body TEXT NOT NULL DEFAULT '',
+		duration_ms BIGINT NOT NULL DEFAULT 0,
+		attempted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (delivery_id, attempt)
+	);
+	`
+	_, err := db.Exec(query)
+	return err
+}
+
+const webhookColumns = `id, COALESCE(account_id, ''), url, events, secret, low_balance_threshold, description, created_at, updated_at`
+
+// scanWebhook reads a row selected with webhookColumns
+func scanWebhook(row interface{ Scan(...interface{}) error }) (*models.WebhookSubscription, error) {
+	subscription := &models.WebhookSubscription{}
+	var events pq.StringArray
+	err := row.Scan(&subscription.ID, &subscription.AccountID, &subscription.URL, &events, &subscription.Secret,
+		&subscription.LowBalanceThreshold, &subscription.Description, &subscription.CreatedAt, &subscription.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	subscription.Events = events
+	return subscription, nil
+}
+
+const webhookDeliveryColumns = `id, subscription_id, event_id, event_type, account_id, payload, status, attempts,
+	next_attempt_at, last_status_code, last_error, COALESCE(redelivery_of, ''), delivered_at, created_at, updated_at`
+
+// scanWebhookDelivery reads a row selected with webhookDeliveryColumns
+func scanWebhookDelivery(row interface{ Scan(...interface{}) error }) (*models.WebhookDelivery, error) {
+	delivery := &models.WebhookDelivery{}
+	var payload []byte
+	err := row.Scan(&delivery.ID, &delivery.SubscriptionID, &delivery.EventID, &delivery.EventType,
+		&delivery.AccountID, &payload, &delivery.Status, &delivery.Attempts, &delivery.NextAttemptAt,
+		&delivery.LastStatusCode, &delivery.LastError, &delivery.RedeliveryOf, &delivery.DeliveredAt,
+		&delivery.CreatedAt, &delivery.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	delivery.Payload = payload
+	return delivery, nil
+}
+
+// CreateWebhookSubscription stores a new subscription
+func (s *PostgresAccountStorage) CreateWebhookSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	var accountID sql.NullString
+	if !subscription.IsGlobal() {
+		accountID = sql.NullString{String: subscription.AccountID, Valid: true}
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO webhook_subscriptions (id, account_id, url, events, secret, low_balance_threshold, description,
+			created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, subscription.ID, accountID, subscription.URL, pq.Array(subscription.Events), subscription.Secret,
+		subscription.LowBalanceThreshold, subscription.Description, subscription.CreatedAt, subscription.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert webhook subscription: %w", err)
+	}
+	return nil
+}
+
+// GetWebhookSubscription returns a subscription by ID
+func (s *PostgresAccountStorage) GetWebhookSubscription(ctx context.Context, subscriptionID string) (*models.WebhookSubscription, error) {
+	subscription, err := scanWebhook(s.db.QueryRowContext(ctx, "SELECT "+webhookColumns+" FROM webhook_subscriptions WHERE id = $1", subscriptionID))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	return subscription, nil
+}
+
+// GetWebhookSubscriptions returns an account's subscriptions, or the global
+// ones when accountID is empty, newest first
+func (s *PostgresAccountStorage) GetWebhookSubscriptions(ctx context.Context, accountID string) ([]models.WebhookSubscription, error) {
+	query := "SELECT " + webhookColumns + " FROM webhook_subscriptions WHERE account_id = $1 ORDER BY created_at DESC, id"
+	args := []interface{}{accountID}
+	if accountID == "" {
+		query = "SELECT " + webhookColumns + " FROM webhook_subscriptions WHERE account_id IS NULL ORDER BY created_at DESC, id"
+		args = nil
+	}
+	return s.queryWebhooks(ctx, query, args...)
+}
+
+// GetSubscribedWebhooks returns the subscriptions that asked for eventType on
+// the account: the account's own and the global ones
+func (s *PostgresAccountStorage) GetSubscribedWebhooks(ctx context.Context, accountID, eventType string) ([]models.WebhookSubscription, error) {
+	return s.queryWebhooks(ctx, "SELECT "+webhookColumns+` FROM webhook_subscriptions
+		WHERE (account_id = $1 OR account_id IS NULL) AND $2 = ANY(events)
+		ORDER BY created_at, id`, accountID, eventType)
+}
+
+func (s *PostgresAccountStorage) queryWebhooks(ctx context.Context, query string, args ...interface{}) ([]models.WebhookSubscription, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := []models.WebhookSubscription{}
+	for rows.Next() {
+		subscription, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, *subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+// DeleteWebhookSubscription deletes a subscription with its deliveries and
+// their attempt logs
+func (s *PostgresAccountStorage) DeleteWebhookSubscription(ctx context.Context, subscriptionID string) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM webhook_subscriptions WHERE id = $1", subscriptionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check webhook subscription delete: %w", err)
+	}
+	if rows == 0 {
+		return models.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// CreateWebhookDeliveries stores new deliveries together, so an event reaches
+// all of its subscribers or none
+func (s *PostgresAccountStorage) CreateWebhookDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Will be ignored if tx.Commit() succeeds
+
+	for _, delivery := range deliveries {
+		var redeliveryOf sql.NullString
+		if delivery.RedeliveryOf != "" {
+			redeliveryOf = sql.NullString{String: delivery.RedeliveryOf, Valid: true}
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO webhook_deliveries (id, subscription_id, event_id, event_type, account_id, payload, status,
+				next_attempt_at, redelivery_of, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		`, delivery.ID, delivery.SubscriptionID, delivery.EventID, delivery.EventType, delivery.AccountID,
+			[]byte(delivery.Payload), delivery.Status, delivery.NextAttemptAt, redeliveryOf,
+			delivery.CreatedAt, delivery.UpdatedAt); err != nil {
+			return fmt.Errorf("failed to insert webhook delivery: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetWebhookDelivery returns a delivery by ID with its attempt log, oldest
+// attempt first
+func (s *PostgresAccountStorage) GetWebhookDelivery(ctx context.Context, deliveryID string) (*models.WebhookDelivery, error) {
+	delivery, err := scanWebhookDelivery(s.db.QueryRowContext(ctx, "SELECT "+webhookDeliveryColumns+" FROM webhook_deliveries WHERE id = $1", deliveryID))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrWebhookDeliveryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT delivery_id, attempt, status_code, error, response_body, duration_ms, attempted_at
+		FROM webhook_delivery_attempts WHERE delivery_id = $1 ORDER BY attempt
+	`, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery attempts: %w", err)
+	}
+	defer rows.Close()
+
+	delivery.AttemptLog = []models.WebhookAttempt{}
+	for rows.Next() {
+		var attempt models.WebhookAttempt
+		if err := rows.Scan(&attempt.DeliveryID, &attempt.Attempt, &attempt.StatusCode, &attempt.Error,
+			&attempt.ResponseBody, &attempt.DurationMs, &attempt.AttemptedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery attempt: %w", err)
+		}
+		delivery.AttemptLog = append(delivery.AttemptLog, attempt)
+	}
+
+	return delivery, rows.Err()
+}
+
+// GetWebhookDeliveries returns a page of a subscription's deliveries, newest
+// first, optionally filtered by status, and how many there are in all
+func (s *PostgresAccountStorage) GetWebhookDeliveries(ctx context.Context, subscriptionID, status string, page, limit int) ([]models.WebhookDelivery, int64, error) {
+	where := "WHERE subscription_id = $1"
+	args := []interface{}{subscriptionID}
+	if status != "" {
+		where += " AND status = $2"
+		args = append(args, status)
+	}
+
+	var total int64
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM webhook_deliveries "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	args = append(args, limit, (page-1)*limit)
+	rows, err := s.db.QueryContext(ctx, "SELECT "+webhookDeliveryColumns+" FROM webhook_deliveries "+where+
+		fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	return deliveries, total, rows.Err()
+}
+
+// ClaimDueWebhookDeliveries claims up to limit pending deliveries due at now
+// that no other dispatcher holds, hiding them from other instances until
+// leaseUntil. SKIP LOCKED lets instances claiming at the same moment take
+// different rows.
+func (s *PostgresAccountStorage) ClaimDueWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		UPDATE webhook_deliveries SET claimed_until = $2
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1 AND (claimed_until IS NULL OR claimed_until <= $1)
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+webhookDeliveryColumns, now, leaseUntil, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// RecordWebhookAttempt logs an attempt and saves the delivery's state after
+// it, giving up the dispatcher's claim, in one database transaction
+func (s *PostgresAccountStorage) RecordWebhookAttempt(ctx context.Context, delivery *models.WebhookDelivery, attempt *models.WebhookAttempt) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Will be ignored if tx.Commit() succeeds
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO webhook_delivery_attempts (delivery_id, attempt, status_code, error, response_body, duration_ms, attempted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, attempt.DeliveryID, attempt.Attempt, attempt.StatusCode, attempt.Error, attempt.ResponseBody,
+		attempt.DurationMs, attempt.AttemptedAt); err != nil {
+		return fmt.Errorf("failed to insert webhook delivery attempt: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE webhook_deliveries SET status = $2, attempts = $3, next_attempt_at = $4, last_status_code = $5,
+			last_error = $6, delivered_at = $7, claimed_until = NULL, updated_at = $8
+		WHERE id = $1
+	`, delivery.ID, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastStatusCode,
+		delivery.LastError, delivery.DeliveredAt, delivery.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -3,12 +3,16 @@
 # Test script for the async transaction flow
 BASE_URL="http://localhost:8080/api/v1"
 
+# Requests authenticate with a token minted from the development secret in
+# docker-compose.yml, unless TOKEN is already set
+TOKEN=${TOKEN:-$(AUTH_JWT_SECRET=${AUTH_JWT_SECRET:-local-development-secret} go run . mint-token -sub test_user 2>/dev/null)}
+
 echo "🧪 Testing Banking Ledger Async Flow"
 echo "===================================="
 
 # 1. Create an account
 echo "1️⃣  Creating account..."
-ACCOUNT_RESPONSE=$(curl -s -X POST $BASE_URL/accounts \
+ACCOUNT_RESPONSE=$(curl -s -H "Authorization: Bearer $TOKEN" -X POST $BASE_URL/accounts \
   -H "Content-Type: application/json" \
   -d '{
     "owner_name": "John Doe",
@@ -22,7 +26,7 @@ echo
 
 # 2. Submit async transaction
 echo "2️⃣  Submitting async transaction..."
-TRANSACTION_RESPONSE=$(curl -s -X POST $BASE_URL/accounts/$ACCOUNT_ID/transactions \
+TRANSACTION_RESPONSE=$(curl -s -H "Authorization: Bearer $TOKEN" -X POST $BASE_URL/accounts/$ACCOUNT_ID/transactions \
   -H "Content-Type: application/json" \
   -d '{
     "type": "deposit",
@@ -39,7 +43,7 @@ echo
 # 3. Check transaction immediately (should show pending)
 echo "3️⃣  Checking transaction status (immediately)..."
 sleep 0.5
-IMMEDIATE_CHECK=$(curl -s $BASE_URL/transactions/$TRANSACTION_ID)
+IMMEDIATE_CHECK=$(curl -s -H "Authorization: Bearer $TOKEN" $BASE_URL/transactions/$TRANSACTION_ID)
 echo "📋 Immediate status: $(echo $IMMEDIATE_CHECK | jq -r '.status')"
 echo "   Response: $IMMEDIATE_CHECK"
 echo
@@ -48,7 +52,7 @@ echo
 echo "4️⃣  Waiting for background processing..."
 for i in {1..10}; do
   sleep 1
-  STATUS_CHECK=$(curl -s $BASE_URL/transactions/$TRANSACTION_ID)
+  STATUS_CHECK=$(curl -s -H "Authorization: Bearer $TOKEN" $BASE_URL/transactions/$TRANSACTION_ID)
   STATUS=$(echo $STATUS_CHECK | jq -r '.status')
   echo "   Check $i: Status = $STATUS"
   
@@ -63,14 +67,14 @@ echo
 
 # 5. Check final account balance
 echo "5️⃣  Checking final account balance..."
-FINAL_ACCOUNT=$(curl -s $BASE_URL/accounts/$ACCOUNT_ID)
+FINAL_ACCOUNT=$(curl -s -H "Authorization: Bearer $TOKEN" $BASE_URL/accounts/$ACCOUNT_ID)
 FINAL_BALANCE=$(echo $FINAL_ACCOUNT | jq -r '.account.balance')
 echo "✅ Final balance: $FINAL_BALANCE"
 echo
 
 # 6. Test insufficient funds (async failure)
 echo "6️⃣  Testing insufficient funds (should fail)..."
-FAIL_RESPONSE=$(curl -s -X POST $BASE_URL/accounts/$ACCOUNT_ID/transactions \
+FAIL_RESPONSE=$(curl -s -H "Authorization: Bearer $TOKEN" -X POST $BASE_URL/accounts/$ACCOUNT_ID/transactions \
   -H "Content-Type: application/json" \
   -d '{
     "type": "withdraw",
@@ -83,7 +87,7 @@ echo "📤 Insufficient funds transaction: $FAIL_TRANSACTION_ID"
 
 # Wait for failure processing
 sleep 3
-FAIL_CHECK=$(curl -s $BASE_URL/transactions/$FAIL_TRANSACTION_ID)
+FAIL_CHECK=$(curl -s -H "Authorization: Bearer $TOKEN" $BASE_URL/transactions/$FAIL_TRANSACTION_ID)
 FAIL_STATUS=$(echo $FAIL_CHECK | jq -r '.status')
 echo "❌ Expected failure status: $FAIL_STATUS"
 if [ "$FAIL_STATUS" = "failed" ]; then
@@ -94,7 +98,7 @@ echo
 
 # 7. Check processing mode
 echo "7️⃣  Checking processing mode..."
-PROCESSING_MODE=$(curl -s $BASE_URL/processing-mode)
+PROCESSING_MODE=$(curl -s -H "Authorization: Bearer $TOKEN" $BASE_URL/processing-mode)
 echo "⚙️  Processing info: $PROCESSING_MODE"
 echo
 
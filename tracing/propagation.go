@@ -0,0 +1,42 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// TraceParentHeader carries the W3C trace context, in HTTP requests and in
+// RabbitMQ message headers alike
+const TraceParentHeader = "traceparent"
+
+// propagator reads and writes W3C traceparent and tracestate
+var propagator = propagation.TraceContext{}
+
+// Inject writes the context's span into the carrier so the next process can
+// continue the trace. Nothing is written when there is no span.
+func Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	propagator.Inject(ctx, carrier)
+}
+
+// Extract returns a context continuing the trace named in the carrier, or ctx
+// unchanged when it names none or the header is malformed
+func Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	return propagator.Extract(ctx, carrier)
+}
+
+// TraceParent returns the traceparent value for the context's span, or ""
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	Inject(ctx, carrier)
+	return carrier.Get(TraceParentHeader)
+}
+
+// ContextWithTraceParent returns a context whose next span continues the trace
+// named by a stored traceparent value, or ctx unchanged when it names none
+func ContextWithTraceParent(ctx context.Context, traceParent string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+	return Extract(ctx, propagation.MapCarrier{TraceParentHeader: traceParent})
+}
@@ -0,0 +1,174 @@
+// Package tracing sets up OpenTelemetry tracing for the service and follows
+// traces across HTTP, RabbitMQ and the workers using W3C trace context
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Exporter names accepted in Config.Exporter
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+	ExporterOTLP   = "otlp"
+)
+
+// instrumentationScope names this service's own spans
+const instrumentationScope = "github.com/appy29/banking-ledger-service"
+
+// Config selects where spans are sent
+type Config struct {
+	Exporter     string            // none, stdout, file or otlp
+	FilePath     string            // Output file for the file exporter
+	OTLPEndpoint string            // Collector base URL; spans are posted to /v1/traces
+	OTLPHeaders  map[string]string // Extra request headers, such as an API key
+	ServiceName  string            // Reported as the service.name resource attribute
+	SampleRatio  float64           // Share of new traces recorded; continued traces follow their parent
+}
+
+// New creates a tracer provider that batches spans to the configured
+// exporter. It returns nil when tracing is disabled. Call Shutdown on the
+// provider to flush the last spans.
+func New(ctx context.Context, config Config) (*sdktrace.TracerProvider, error) {
+	var exporter sdktrace.SpanExporter
+	switch strings.ToLower(config.Exporter) {
+	case "", ExporterNone:
+		return nil, nil
+	case ExporterStdout:
+		stdoutExporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout trace exporter: %w", err)
+		}
+		exporter = stdoutExporter
+	case ExporterFile:
+		if config.FilePath == "" {
+			return nil, fmt.Errorf("the file trace exporter needs a file path")
+		}
+		fileExporter, err := newFileExporter(config.FilePath)
+		if err != nil {
+			return nil, err
+		}
+		exporter = fileExporter
+	case ExporterOTLP:
+		if config.OTLPEndpoint == "" {
+			return nil, fmt.Errorf("the otlp trace exporter needs an endpoint")
+		}
+		otlpExporter, err := otlptracehttp.New(ctx,
+			otlptracehttp.WithEndpointURL(strings.TrimSuffix(config.OTLPEndpoint, "/")+"/v1/traces"),
+			otlptracehttp.WithHeaders(config.OTLPHeaders))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create otlp trace exporter: %w", err)
+		}
+		exporter = otlpExporter
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", config.Exporter)
+	}
+
+	serviceResource, err := resource.Merge(resource.Default(),
+		resource.NewSchemaless(attribute.String("service.name", config.ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(serviceResource),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(min(max(config.SampleRatio, 0), 1)))),
+	), nil
+}
+
+// enabled is set once a provider is installed. Until then Start leaves the
+// context as it is.
+var enabled atomic.Bool
+
+// SetDefault makes provider the one spans are started from and W3C trace
+// context the propagator for instrumentation such as otelgin. A nil provider
+// leaves tracing disabled.
+func SetDefault(provider *sdktrace.TracerProvider) {
+	otel.SetTextMapPropagator(propagator)
+	if provider != nil {
+		otel.SetTracerProvider(provider)
+	}
+	enabled.Store(provider != nil)
+}
+
+// Start begins a span as a child of the context's span or remote parent, or
+// as the root of a new trace. With tracing disabled it returns ctx unchanged
+// and a span that records nothing.
+func Start(ctx context.Context, name string, kind trace.SpanKind, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	if !enabled.Load() {
+		return ctx, trace.SpanFromContext(context.Background())
+	}
+	return otel.Tracer(instrumentationScope).Start(ctx, name,
+		trace.WithSpanKind(kind),
+		trace.WithAttributes(attributes...))
+}
+
+// StartChild is Start, except no span is recorded unless the context already
+// belongs to a trace. Storage calls use it so background polling does not
+// start traces of its own.
+func StartChild(ctx context.Context, name string, kind trace.SpanKind, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	return Start(ctx, name, kind, attributes...)
+}
+
+// RecordError marks the span as failed with the error's message. A nil error
+// is ignored.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// TraceIDFromContext returns the hex trace ID of the context's span, or ""
+func TraceIDFromContext(ctx context.Context) string {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		return sc.TraceID().String()
+	}
+	return ""
+}
+
+// fileExporter writes spans to a file it owns and closes on Shutdown
+type fileExporter struct {
+	sdktrace.SpanExporter
+	file *os.File
+}
+
+func newFileExporter(path string) (*fileExporter, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trace file: %w", err)
+	}
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to create file trace exporter: %w", err)
+	}
+	return &fileExporter{SpanExporter: exporter, file: file}, nil
+}
+
+func (e *fileExporter) Shutdown(ctx context.Context) error {
+	if err := e.SpanExporter.Shutdown(ctx); err != nil {
+		e.file.Close()
+		return err
+	}
+	return e.file.Close()
+}
@@ -0,0 +1,176 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const upstreamTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+// recordSpans makes a provider keeping every span in memory the default for
+// the rest of the test
+func recordSpans(t *testing.T, sampler sdktrace.Sampler) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter), sdktrace.WithSampler(sampler))
+
+	previous := otel.GetTracerProvider()
+	SetDefault(provider)
+	t.Cleanup(func() {
+		provider.Shutdown(context.Background())
+		SetDefault(nil)
+		otel.SetTracerProvider(previous)
+	})
+	return exporter
+}
+
+func TestStart_ContinuesRemoteTrace(t *testing.T) {
+	exporter := recordSpans(t, sdktrace.AlwaysSample())
+
+	header := http.Header{}
+	header.Set(TraceParentHeader, upstreamTraceParent)
+	ctx := Extract(context.Background(), propagation.HeaderCarrier(header))
+
+	ctx, server := Start(ctx, "POST /accounts", trace.SpanKindServer)
+	_, child := Start(ctx, "postgres INSERT", trace.SpanKindClient, attribute.String("db.system", "postgresql"))
+	RecordError(child, errors.New("connection refused"))
+	child.End()
+	server.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	dbSpan, serverSpan := spans[0], spans[1]
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", serverSpan.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", serverSpan.Parent.SpanID().String())
+	assert.Equal(t, serverSpan.SpanContext.SpanID(), dbSpan.Parent.SpanID())
+	assert.Equal(t, codes.Error, dbSpan.Status.Code)
+	assert.Equal(t, "connection refused", dbSpan.Status.Description)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", TraceIDFromContext(ctx))
+
+	// The next hop continues from the current span
+	out := http.Header{}
+	Inject(ctx, propagation.HeaderCarrier(out))
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-"+serverSpan.SpanContext.SpanID().String()+"-01", out.Get(TraceParentHeader))
+	assert.Equal(t, out.Get(TraceParentHeader), TraceParent(ctx))
+}
+
+func TestStart_Sampling(t *testing.T) {
+	exporter := recordSpans(t, sdktrace.ParentBased(sdktrace.TraceIDRatioBased(0)))
+
+	ctx, root := Start(context.Background(), "unsampled", trace.SpanKindServer)
+	_, child := Start(ctx, "child", trace.SpanKindInternal)
+	child.End()
+	root.End()
+
+	// A sampled parent from upstream is followed whatever the local ratio
+	_, continued := Start(ContextWithTraceParent(context.Background(), upstreamTraceParent), "continued", trace.SpanKindConsumer)
+	continued.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "continued", spans[0].Name)
+	assert.True(t, root.SpanContext().IsValid(), "unsampled spans still carry IDs downstream")
+}
+
+func TestStartChild_NeedsParent(t *testing.T) {
+	exporter := recordSpans(t, sdktrace.AlwaysSample())
+
+	_, span := StartChild(context.Background(), "postgres SELECT", trace.SpanKindClient)
+	assert.False(t, span.IsRecording())
+	span.End()
+
+	ctx, parent := Start(context.Background(), "worker", trace.SpanKindConsumer)
+	_, span = StartChild(ctx, "postgres SELECT", trace.SpanKindClient)
+	assert.True(t, span.IsRecording())
+	assert.Equal(t, parent.SpanContext().TraceID(), span.SpanContext().TraceID())
+	span.End()
+	parent.End()
+
+	assert.Len(t, exporter.GetSpans(), 2)
+}
+
+func TestContextWithTraceParent(t *testing.T) {
+	assert.Equal(t, context.Background(), ContextWithTraceParent(context.Background(), ""))
+
+	ctx := ContextWithTraceParent(context.Background(), "not-a-traceparent")
+	assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+
+	ctx = ContextWithTraceParent(context.Background(), upstreamTraceParent)
+	assert.Equal(t, upstreamTraceParent, TraceParent(ctx))
+}
+
+func TestStart_Disabled(t *testing.T) {
+	ctx := context.WithValue(context.Background(), struct{}{}, "value")
+
+	started, span := Start(ctx, "ignored", trace.SpanKindServer)
+	assert.Equal(t, ctx, started)
+	assert.False(t, span.IsRecording())
+	RecordError(span, errors.New("ignored"))
+	span.End()
+	assert.Empty(t, TraceParent(started))
+}
+
+func TestNew(t *testing.T) {
+	provider, err := New(context.Background(), Config{Exporter: ExporterNone})
+	require.NoError(t, err)
+	assert.Nil(t, provider)
+
+	_, err = New(context.Background(), Config{Exporter: "zipkin"})
+	assert.Error(t, err)
+	_, err = New(context.Background(), Config{Exporter: ExporterFile})
+	assert.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	provider, err = New(context.Background(), Config{Exporter: ExporterFile, FilePath: path, ServiceName: "test", SampleRatio: 1})
+	require.NoError(t, err)
+	_, span := provider.Tracer("test").Start(context.Background(), "written")
+	span.End()
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(written), `"Name":"written"`)
+}
+
+func TestNew_OTLP(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r
+	}))
+	defer server.Close()
+
+	provider, err := New(context.Background(), Config{
+		Exporter:     ExporterOTLP,
+		OTLPEndpoint: server.URL + "/",
+		OTLPHeaders:  map[string]string{"X-Collector-Key": "secret"},
+		ServiceName:  "banking-ledger-service",
+		SampleRatio:  1,
+	})
+	require.NoError(t, err)
+
+	_, span := provider.Tracer("test").Start(context.Background(), "transaction_queue publish")
+	span.End()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, provider.Shutdown(ctx))
+
+	r := <-received
+	assert.Equal(t, "/v1/traces", r.URL.Path)
+	assert.Equal(t, "secret", r.Header.Get("X-Collector-Key"))
+}
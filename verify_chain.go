@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/services"
+)
+
+// verifyChainBatchSize is how many accounts are loaded at a time
+const verifyChainBatchSize = 100
+
+// chainReport is the output of verify-chain
+type chainReport struct {
+	AccountsChecked int                         `json:"accounts_checked"`
+	AccountsBroken  int                         `json:"accounts_broken"`
+	Verifications   []*models.ChainVerification `json:"verifications"` // Broken chains only, unless one account was asked for
+}
+
+// runVerifyChain implements `banking-ledger-service verify-chain`: it walks
+// the hash chain of every account (or one, with -account), writes the result
+// to stdout and exits 1 if any chain is broken
+func runVerifyChain(args []string) int {
+	flags := flag.NewFlagSet("verify-chain", flag.ContinueOnError)
+	accountID := flags.String("account", "", "verify only this account")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	env, ok := openCommandEnv()
+	if !ok {
+		return exitError
+	}
+	defer env.Close()
+
+	ctx, stop := env.context()
+	defer stop()
+
+	transactionService := services.NewTransactionService(env.accountStorage, env.transactionStorage)
+	report := &chainReport{Verifications: []*models.ChainVerification{}}
+
+	verify := func(id string) bool {
+		verification, err := transactionService.VerifyTransactionChain(ctx, id)
+		if err != nil {
+			env.logger.Error("Chain verification failed", slog.String("account_id", id), slog.String("error", err.Error()))
+			return false
+		}
+		report.AccountsChecked++
+		if !verification.Valid {
+			report.AccountsBroken++
+		}
+		if !verification.Valid || *accountID != "" {
+			report.Verifications = append(report.Verifications, verification)
+		}
+		return true
+	}
+
+	if *accountID != "" {
+		if !verify(*accountID) {
+			return exitError
+		}
+	} else {
+		afterID := ""
+		for {
+			accounts, err := env.accountStorage.ListAccounts(ctx, afterID, verifyChainBatchSize)
+			if err != nil {
+				env.logger.Error("Failed to list accounts", slog.String("error", err.Error()))
+				return exitError
+			}
+			for _, account := range accounts {
+				if !verify(account.ID) {
+					return exitError
+				}
+			}
+			if len(accounts) < verifyChainBatchSize {
+				break
+			}
+			afterID = accounts[len(accounts)-1].ID
+		}
+	}
+
+	if err := writeJSON(report); err != nil {
+		env.logger.Error("Failed to write report", slog.String("error", err.Error()))
+		return exitError
+	}
+
+	// The service has already logged where each broken chain breaks
+	if report.AccountsBroken > 0 {
+		return 1
+	}
+	return 0
+}
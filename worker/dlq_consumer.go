@@ -0,0 +1,67 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/queue"
+	"github.com/appy29/banking-ledger-service/services"
+	"github.com/streadway/amqp"
+)
+
+// DeadLetterConsumer drains the dead letter queue into storage, where operators
+// can inspect, replay or discard the messages
+type DeadLetterConsumer struct {
+	rabbitmq      *queue.RabbitMQ
+	deadLetterSvc *services.DeadLetterService
+}
+
+// NewDeadLetterConsumer creates a consumer for the dead letter queue
+func NewDeadLetterConsumer(rabbitmq *queue.RabbitMQ, deadLetterSvc *services.DeadLetterService) *DeadLetterConsumer {
+	return &DeadLetterConsumer{
+		rabbitmq:      rabbitmq,
+		deadLetterSvc: deadLetterSvc,
+	}
+}
+
+// Start runs the consumer until the context is cancelled
+func (c *DeadLetterConsumer) Start(ctx context.Context) error {
+	deliveries, err := c.rabbitmq.ConsumeDeadLetters()
+	if err != nil {
+		return err
+	}
+
+	log.Println("Dead letter consumer started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Dead letter consumer shutting down")
+			return ctx.Err()
+		case delivery, ok := <-deliveries:
+			if !ok {
+				return fmt.Errorf("dead letter channel closed")
+			}
+			c.record(ctx, delivery)
+		}
+	}
+}
+
+// record stores one dead letter and acks it; the message stays on the queue
+// until it is stored
+func (c *DeadLetterConsumer) record(ctx context.Context, delivery amqp.Delivery) {
+	deadLetter := queue.NewDeadLetter(delivery)
+
+	if err := c.deadLetterSvc.RecordDeadLetter(ctx, deadLetter); err != nil {
+		log.Printf("Dead letter consumer: failed to record %s: %v", deadLetter.MessageID, err)
+		// Back off so a storage outage does not spin on the same message
+		time.Sleep(time.Second)
+		delivery.Nack(false, true)
+		return
+	}
+
+	log.Printf("Dead letter consumer: recorded %s (reason: %s)", deadLetter.MessageID, deadLetter.Reason)
+	delivery.Ack(false)
+}
@@ -0,0 +1,45 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/services"
+)
+
+// HoldExpirer periodically marks holds past their expiry as expired. Expired
+// holds stop reserving funds at their expiry either way; this keeps their
+// stored status in step.
+type HoldExpirer struct {
+	transactionSvc *services.TransactionService
+	interval       time.Duration
+}
+
+// NewHoldExpirer creates an expirer that runs every interval
+func NewHoldExpirer(transactionSvc *services.TransactionService, interval time.Duration) *HoldExpirer {
+	return &HoldExpirer{
+		transactionSvc: transactionSvc,
+		interval:       interval,
+	}
+}
+
+// Start runs the expirer until the context is cancelled
+func (e *HoldExpirer) Start(ctx context.Context) error {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	log.Printf("Hold expirer started (interval %v)", e.interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Hold expirer shutting down")
+			return ctx.Err()
+		case <-ticker.C:
+			if _, err := e.transactionSvc.ExpireHolds(ctx); err != nil {
+				log.Printf("Hold expirer: expiry failed: %v", err)
+			}
+		}
+	}
+}
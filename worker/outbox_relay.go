@@ -0,0 +1,98 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/metrics"
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/queue"
+	"github.com/appy29/banking-ledger-service/services"
+	"github.com/appy29/banking-ledger-service/tracing"
+)
+
+// outboxBatchSize caps how many entries one poll publishes
+const outboxBatchSize = 50
+
+// OutboxRelay publishes messages stored alongside pending transactions
+type OutboxRelay struct {
+	rabbitmq     *queue.RabbitMQ
+	outboxSvc    *services.OutboxService
+	pollInterval time.Duration
+}
+
+// NewOutboxRelay creates a relay that polls for due outbox entries every pollInterval
+func NewOutboxRelay(rabbitmq *queue.RabbitMQ, outboxSvc *services.OutboxService, pollInterval time.Duration) *OutboxRelay {
+	return &OutboxRelay{
+		rabbitmq:     rabbitmq,
+		outboxSvc:    outboxSvc,
+		pollInterval: pollInterval,
+	}
+}
+
+// Start runs the relay until the context is cancelled
+func (r *OutboxRelay) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	log.Printf("Outbox relay started (poll interval %v)", r.pollInterval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Outbox relay shutting down")
+			return ctx.Err()
+		case <-ticker.C:
+			r.dispatchDue(ctx)
+		}
+	}
+}
+
+// dispatchDue publishes every due entry, draining the backlog batch by batch
+func (r *OutboxRelay) dispatchDue(ctx context.Context) {
+	for {
+		entries, err := r.outboxSvc.ClaimDue(ctx, outboxBatchSize)
+		if err != nil {
+			log.Printf("Outbox relay: failed to claim entries: %v", err)
+		}
+
+		for _, transaction := range entries {
+			r.dispatch(ctx, transaction)
+		}
+
+		if err != nil || len(entries) < outboxBatchSize {
+			return
+		}
+	}
+}
+
+// dispatch publishes one entry and records the outcome
+func (r *OutboxRelay) dispatch(ctx context.Context, transaction *models.Transaction) {
+	if err := r.publish(ctx, transaction.Outbox); err != nil {
+		log.Printf("Outbox relay: failed to publish %s (attempt %d): %v",
+			transaction.TransactionID, transaction.Outbox.Attempts, err)
+		r.outboxSvc.MarkFailed(ctx, transaction.TransactionID, transaction.Outbox.Attempts, err)
+		return
+	}
+
+	// If this update is lost the entry is published again after the lease; the
+	// worker ignores messages for records that are no longer pending
+	r.outboxSvc.MarkDispatched(ctx, transaction.TransactionID)
+}
+
+func (r *OutboxRelay) publish(ctx context.Context, entry *models.OutboxEntry) error {
+	if !r.rabbitmq.IsConnected() {
+		metrics.QueuePublishFailures.WithLabelValues("transaction").Inc()
+		return queue.ErrNotConnected
+	}
+
+	msg, err := queue.DecodeOutboxEntry(entry)
+	if err != nil {
+		return err
+	}
+
+	// Publish as part of the trace of the request that queued the message
+	ctx = tracing.ContextWithTraceParent(ctx, entry.TraceParent)
+	return r.rabbitmq.PublishTransaction(ctx, msg)
+}
@@ -0,0 +1,43 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/services"
+)
+
+// PendingSweeper periodically settles transactions stuck in pending
+type PendingSweeper struct {
+	reaperSvc *services.ReaperService
+	interval  time.Duration
+}
+
+// NewPendingSweeper creates a sweeper that runs the reaper every interval
+func NewPendingSweeper(reaperSvc *services.ReaperService, interval time.Duration) *PendingSweeper {
+	return &PendingSweeper{
+		reaperSvc: reaperSvc,
+		interval:  interval,
+	}
+}
+
+// Start runs the sweeper until the context is cancelled
+func (s *PendingSweeper) Start(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	log.Printf("Pending sweeper started (interval %v)", s.interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Pending sweeper shutting down")
+			return ctx.Err()
+		case <-ticker.C:
+			if err := s.reaperSvc.Sweep(ctx); err != nil {
+				log.Printf("Pending sweeper: sweep failed: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/models"
+	"github.com/appy29/banking-ledger-service/services"
+)
+
+// Reconciler periodically checks Postgres balances against the transaction log
+type Reconciler struct {
+	reconciliationSvc *services.ReconciliationService
+	interval          time.Duration
+	repair            bool
+}
+
+// NewReconciler creates a job that reconciles every account every interval,
+// posting adjustment entries when repair is set
+func NewReconciler(reconciliationSvc *services.ReconciliationService, interval time.Duration, repair bool) *Reconciler {
+	return &Reconciler{
+		reconciliationSvc: reconciliationSvc,
+		interval:          interval,
+		repair:            repair,
+	}
+}
+
+// Start runs the reconciler until the context is cancelled
+func (r *Reconciler) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	log.Printf("Reconciler started (interval %v, repair %v)", r.interval, r.repair)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Reconciler shutting down")
+			return ctx.Err()
+		case <-ticker.C:
+			report, err := r.reconciliationSvc.Reconcile(ctx, models.ReconcileOptions{Repair: r.repair})
+			if err != nil {
+				log.Printf("Reconciler: run failed: %v", err)
+				continue
+			}
+			if unresolved := report.Unresolved(); unresolved > 0 {
+				log.Printf("Reconciler: %d of %d issues unresolved across %d accounts", unresolved, len(report.Issues), report.AccountsChecked)
+			}
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/services"
+)
+
+// Scheduler periodically runs the occurrences of schedules that have fallen
+// due. Any number of instances can run one; the schedule service keeps them
+// from running the same occurrence twice.
+type Scheduler struct {
+	scheduleSvc *services.ScheduleService
+	interval    time.Duration
+}
+
+// NewScheduler creates a scheduler that runs every interval
+func NewScheduler(scheduleSvc *services.ScheduleService, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		scheduleSvc: scheduleSvc,
+		interval:    interval,
+	}
+}
+
+// Start runs the scheduler until the context is cancelled. The first pass
+// runs straight away so occurrences missed while the service was down are
+// dealt with on startup.
+func (s *Scheduler) Start(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	log.Printf("Scheduler started (interval %v)", s.interval)
+
+	for {
+		if _, err := s.scheduleSvc.RunDue(ctx); err != nil {
+			log.Printf("Scheduler: run failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("Scheduler shutting down")
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -3,14 +3,20 @@ package worker
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
-	"strings"
+	"log/slog"
 	"time"
 
+	"github.com/appy29/banking-ledger-service/metrics"
 	"github.com/appy29/banking-ledger-service/models"
 	"github.com/appy29/banking-ledger-service/queue"
 	"github.com/appy29/banking-ledger-service/services"
+	"github.com/appy29/banking-ledger-service/tracing"
+	"github.com/appy29/banking-ledger-service/utils"
 	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // TransactionWorker processes transaction messages from the queue
@@ -63,10 +69,35 @@ func (w *TransactionWorker) processMessage(delivery amqp.Delivery) {
 	start := time.Now()
 	var msg queue.TransactionMessage
 
+	metrics.WorkerInFlight.Inc()
+	defer metrics.WorkerInFlight.Dec()
+
+	// Continue the trace of the request that queued the message, so the
+	// worker's database writes show up under the original API call
+	ctx := tracing.Extract(context.Background(), queue.HeaderCarrier(delivery.Headers))
+	ctx, span := tracing.Start(ctx, queue.TransactionQueue+" process", trace.SpanKindConsumer,
+		append(queue.MessagingAttributes("process", delivery.MessageId),
+			attribute.Int("messaging.rabbitmq.retry_count", queue.RetryCount(delivery)),
+			attribute.Int("worker.id", w.id))...)
+	defer span.End()
+	if traceID := tracing.TraceIDFromContext(ctx); traceID != "" {
+		ctx = utils.WithLogger(ctx, utils.LoggerFromContext(ctx).With(slog.String("trace_id", traceID)))
+	}
+
 	// Parse the message
 	if err := json.Unmarshal(delivery.Body, &msg); err != nil {
 		log.Printf("Worker %d: Failed to unmarshal message: %v", w.id, err)
-		delivery.Nack(false, false) // Don't requeue malformed messages
+		tracing.RecordError(span, err)
+		settle(delivery, metrics.DeliveryNack) // Don't requeue malformed messages
+		metrics.ObserveSince(metrics.WorkerProcessingDuration.WithLabelValues("malformed"), start)
+		return
+	}
+	defer metrics.ObserveSince(metrics.WorkerProcessingDuration.WithLabelValues(msg.Type), start)
+
+	span.SetAttributes(attribute.String("ledger.transaction.type", msg.Type))
+
+	if msg.Type == "transfer" {
+		w.processTransferMessage(ctx, delivery, msg, start)
 		return
 	}
 
@@ -76,27 +107,29 @@ func (w *TransactionWorker) processMessage(delivery amqp.Delivery) {
 	req := &models.TransactionRequest{
 		Type:        msg.Type,
 		Amount:      msg.Amount,
+		Currency:    msg.Currency,
 		Description: msg.Reference,
 	}
 
 	// Process the pending transaction using new method
 	processedTransaction, processErr := w.transactionSvc.ProcessTransactionAsync(
-		context.Background(),
+		ctx,
 		msg.ID, // This is the transaction ID we created earlier
 		req,
 	)
 
 	if processErr != nil {
 		log.Printf("Worker %d: Failed to process transaction %s: %v", w.id, msg.ID, processErr)
+		tracing.RecordError(span, processErr)
 
 		// Transaction status is already updated to "failed" by the service
 		// For business logic errors (insufficient funds, etc.), don't requeue
-		if isBusinessError(processErr) {
-			delivery.Ack(false)
+		if services.IsBusinessError(processErr) {
+			settle(delivery, metrics.DeliveryAck)
 			w.handleFailedTransaction(msg, processErr)
 		} else {
-			// For system errors (DB connection, etc.), requeue
-			delivery.Nack(false, true)
+			// For system errors (DB connection, etc.), retry after a delay
+			w.retryOrGiveUp(ctx, delivery, msg, processErr)
 		}
 		return
 	}
@@ -105,32 +138,97 @@ func (w *TransactionWorker) processMessage(delivery amqp.Delivery) {
 	log.Printf("Worker %d: Successfully processed transaction %s in %v",
 		w.id, processedTransaction.ID, time.Since(start))
 
-	delivery.Ack(false)
+	settle(delivery, metrics.DeliveryAck)
 }
 
-// isBusinessError checks if error is due to business logic (don't requeue)
-func isBusinessError(err error) bool {
-	errorMsg := strings.ToLower(err.Error())
-	businessErrors := []string{
-		"insufficient funds",
-		"invalid transaction type",
-		"account not found",
-		"amount must be greater than 0",
-		"transaction is not in pending state",
+// processTransferMessage applies a queued account-to-account transfer
+func (w *TransactionWorker) processTransferMessage(ctx context.Context, delivery amqp.Delivery, msg queue.TransactionMessage, start time.Time) {
+	log.Printf("Worker %d: Processing transfer %s from %s to %s", w.id, msg.ID, msg.AccountID, msg.ToAccountID)
+
+	req := &models.TransferRequest{
+		FromAccountID: msg.AccountID,
+		ToAccountID:   msg.ToAccountID,
+		Amount:        msg.Amount,
+		Currency:      msg.Currency,
+		Description:   msg.Reference,
 	}
 
-	for _, busErr := range businessErrors {
-		if strings.Contains(errorMsg, busErr) {
-			return true
+	transfer, processErr := w.transactionSvc.ProcessTransferAsync(ctx, msg.ID, req)
+	if processErr != nil {
+		log.Printf("Worker %d: Failed to process transfer %s: %v", w.id, msg.ID, processErr)
+		tracing.RecordError(trace.SpanFromContext(ctx), processErr)
+
+		if services.IsBusinessError(processErr) {
+			settle(delivery, metrics.DeliveryAck)
+			w.handleFailedTransaction(msg, processErr)
+		} else {
+			w.retryOrGiveUp(ctx, delivery, msg, processErr)
 		}
+		return
 	}
-	return false
+
+	log.Printf("Worker %d: Successfully processed transfer %s in %v",
+		w.id, transfer.TransferID, time.Since(start))
+
+	settle(delivery, metrics.DeliveryAck)
 }
 
-// handleFailedTransaction logs failed transactions (could save to DB for audit)
+// retryOrGiveUp schedules another attempt through the delay queues, or, once
+// the retry budget is spent, dead-letters the message and fails the record
+func (w *TransactionWorker) retryOrGiveUp(ctx context.Context, delivery amqp.Delivery, msg queue.TransactionMessage, processErr error) {
+	policy := w.rabbitmq.RetryPolicy()
+	retries := queue.RetryCount(delivery)
+
+	if retries < policy.MaxRetries {
+		if err := w.rabbitmq.PublishRetry(delivery, retries+1, processErr); err != nil {
+			log.Printf("Worker %d: Failed to schedule retry of %s: %v", w.id, msg.ID, err)
+			settle(delivery, metrics.DeliveryRequeue)
+			return
+		}
+		settle(delivery, metrics.DeliveryRetry)
+		return
+	}
+
+	attempts := retries + 1
+	log.Printf("Worker %d: Giving up on %s after %d attempts: %v", w.id, msg.ID, attempts, processErr)
+
+	if err := w.rabbitmq.PublishDeadLetter(delivery, attempts, processErr); err != nil {
+		// Rejecting without requeue dead-letters it through the queue's DLX instead
+		log.Printf("Worker %d: Failed to dead-letter %s: %v", w.id, msg.ID, err)
+		settle(delivery, metrics.DeliveryNack)
+	} else {
+		settle(delivery, metrics.DeliveryDeadLetter)
+	}
+
+	errorMessage := fmt.Sprintf("processing failed after %d attempts: %v", attempts, processErr)
+	var failErr error
+	if msg.Type == "transfer" {
+		failErr = w.transactionSvc.FailUnprocessedTransfer(ctx, msg.ID, errorMessage)
+	} else {
+		failErr = w.transactionSvc.FailUnprocessedTransaction(ctx, msg.ID, errorMessage)
+	}
+	if failErr != nil {
+		// The pending reaper settles the record later
+		log.Printf("Worker %d: Failed to mark %s failed: %v", w.id, msg.ID, failErr)
+	}
+}
+
+// handleFailedTransaction logs transactions rejected for good. They are
+// counted in ledger_transactions_total by the transaction service.
 func (w *TransactionWorker) handleFailedTransaction(msg queue.TransactionMessage, err error) {
 	log.Printf("Worker %d: Transaction %s failed permanently: %v", w.id, msg.ID, err)
+}
 
-	// TODO: Additional logging or metrics could be added here
-	// For example, increment a failed transaction counter
+// settle acknowledges a delivery and counts what was done with it. Retried and
+// dead-lettered deliveries were republished, so the original is acked.
+func settle(delivery amqp.Delivery, outcome string) {
+	switch outcome {
+	case metrics.DeliveryNack:
+		delivery.Nack(false, false)
+	case metrics.DeliveryRequeue:
+		delivery.Nack(false, true)
+	default:
+		delivery.Ack(false)
+	}
+	metrics.WorkerDeliveries.WithLabelValues(outcome).Inc()
 }
@@ -0,0 +1,45 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/appy29/banking-ledger-service/services"
+)
+
+// WebhookDispatcher periodically sends the webhook deliveries that are due:
+// new ones and failed ones whose retry delay has passed. Deliveries are
+// claimed before they are sent, so several instances can dispatch together.
+type WebhookDispatcher struct {
+	webhookSvc *services.WebhookService
+	interval   time.Duration
+}
+
+// NewWebhookDispatcher creates a dispatcher that runs every interval
+func NewWebhookDispatcher(webhookSvc *services.WebhookService, interval time.Duration) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		webhookSvc: webhookSvc,
+		interval:   interval,
+	}
+}
+
+// Start runs the dispatcher until the context is cancelled
+func (d *WebhookDispatcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	log.Printf("Webhook dispatcher started (interval %v)", d.interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Webhook dispatcher shutting down")
+			return ctx.Err()
+		case <-ticker.C:
+			if _, err := d.webhookSvc.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Webhook dispatcher: dispatch failed: %v", err)
+			}
+		}
+	}
+}